@@ -0,0 +1,277 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"asset-manager/core/config"
+	"asset-manager/core/database"
+	"asset-manager/core/logger"
+	"asset-manager/core/reconcile"
+	"asset-manager/core/storage"
+	"asset-manager/feature/catalog"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var (
+	// Flags for reconcile catalog command
+	purgeCatalog      bool
+	syncCatalog       bool
+	syncCatalogOffers bool
+	checkPrices       bool
+)
+
+// catalogReconcileCmd cross-checks the catalog against the furniture table.
+var catalogReconcileCmd = &cobra.Command{
+	Use:   "catalog",
+	Short: "Reconcile catalog offers against the furniture table (report + optionally purge/sync)",
+	Long: `Cross-checks catalog_items and catalog_pages against the emulator furniture table.
+
+Reports offers pointing at deleted furniture, offers on missing pages, furniture no
+page sells and offers whose catalog_name differs from the item_name.
+
+Examples:
+  # Report only
+  reconcile catalog
+
+  # Delete offers that only sell deleted furniture
+  reconcile catalog --purge
+
+  # Set catalog_name to the item_name, without a prompt
+  reconcile catalog --sync --yes
+
+  # Preview, then push gamedata offerid/buyout/rent data into catalog_items
+  reconcile catalog --sync-offers --dry-run
+  reconcile catalog --sync-offers
+
+  # Also report free, negative and absurdly high offer prices
+  reconcile catalog --prices
+
+  # Save the plan for review, then apply it later
+  reconcile catalog --purge --sync --plan-out plan.json
+  reconcile catalog apply --plan-in plan.json --yes`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runCatalogReconcile(cmd.Context())
+	},
+}
+
+// catalogApplyCmd applies a plan saved by reconcile catalog --plan-out.
+var catalogApplyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Apply a catalog plan saved with --plan-out",
+	Long: `Apply a catalog plan saved by "reconcile catalog --plan-out", e.g. after review or
+in a maintenance window.
+
+Like reconcile furniture apply, the signature is checked with SERVER_API_KEY and the
+plan is refused when the catalog changed since it was saved.
+
+Examples:
+  reconcile catalog --purge --sync --plan-out plan.json
+  reconcile catalog apply --plan-in plan.json --yes`,
+	Args: cobra.NoArgs,
+	RunE: runCatalogApply,
+}
+
+func init() {
+	reconcileCmd.AddCommand(catalogReconcileCmd)
+	catalogReconcileCmd.AddCommand(catalogApplyCmd)
+
+	catalogReconcileCmd.Flags().BoolVar(&purgeCatalog, "purge", false, "Delete offers whose every item is missing from the furniture table")
+	catalogReconcileCmd.Flags().BoolVar(&syncCatalog, "sync", false, "Set catalog_name of single-item offers to the item_name")
+	catalogReconcileCmd.Flags().BoolVar(&syncCatalogOffers, "sync-offers", false, "Set the offer ID, buyout and rent columns of single-item offers from gamedata")
+	catalogReconcileCmd.Flags().BoolVar(&checkPrices, "prices", false, "Also report offers that are free, negatively priced or above RECONCILE_CATALOG_PRICES bounds")
+	catalogReconcileCmd.Flags().BoolVar(&dryRunFlag, "dry-run", false, "Force dry-run (no mutations even with --yes)")
+	catalogReconcileCmd.Flags().BoolVar(&yesConfirm, "yes", false, "Auto-confirm destructive actions (non-interactive)")
+	catalogReconcileCmd.Flags().BoolVar(&ignoreOnlineGate, "ignore-online-gate", false, "Apply even while more users are online than RECONCILE_ONLINE_GATE_MAX_USERS")
+	catalogReconcileCmd.Flags().StringVar(&planOut, "plan-out", "", "Save the plan to this file for review instead of applying it (see reconcile catalog apply)")
+
+	catalogApplyCmd.Flags().StringVar(&planIn, "plan-in", "", "Plan file saved with reconcile catalog --plan-out")
+	catalogApplyCmd.Flags().BoolVar(&yesConfirm, "yes", false, "Auto-confirm destructive actions (non-interactive)")
+	catalogApplyCmd.Flags().BoolVar(&ignoreOnlineGate, "ignore-online-gate", false, "Apply even while more users are online than RECONCILE_ONLINE_GATE_MAX_USERS")
+	_ = catalogApplyCmd.MarkFlagRequired("plan-in")
+}
+
+func runCatalogReconcile(ctx context.Context) error {
+	if planOut != "" && (syncCatalogOffers || !(purgeCatalog || syncCatalog)) {
+		return fmt.Errorf("--plan-out saves the actions of --purge or --sync; it cannot be combined with --sync-offers")
+	}
+
+	cfg, err := config.LoadConfig(".")
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	// Saved plans are signed, so fail before planning rather than after
+	if planOut != "" && cfg.Server.ApiKey == "" {
+		return reconcile.ErrNoSigningKey
+	}
+
+	l, err := logger.New(&cfg.Log)
+	if err != nil {
+		return fmt.Errorf("failed to initialize logger: %w", err)
+	}
+	ctx, l = startRun(ctx, l)
+	ctx = withProgress(ctx, l)
+	ctx = withMutationLog(ctx, cfg, l)
+
+	db, err := database.Connect(cfg.Database)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	detectEmulator(cfg, db, l)
+	client, err := storage.NewClient(cfg.Storage)
+	if err != nil {
+		return fmt.Errorf("failed to connect to storage: %w", err)
+	}
+
+	openReplica(cfg, l)
+	applyReconcileConfig(cfg)
+	openState(cfg, l)
+
+	adapter := catalog.NewAdapter(db, cfg.Server.Emulator)
+	spec := catalog.NewSpec(adapter, cfg.Server.Emulator)
+	opts := reconcile.ReconcileOptions{DoPurge: purgeCatalog, DoSync: syncCatalog, DryRun: dryRunFlag}
+	report, err := catalog.Reconcile(ctx, spec, db, client, cfg.Storage.Bucket, opts)
+	if err != nil {
+		return fmt.Errorf("failed to reconcile catalog: %w", err)
+	}
+	printCatalogReport(l, report)
+
+	if checkPrices {
+		prices, err := catalog.CheckPrices(ctx, db, cfg.Reconcile.CatalogPrices)
+		if err != nil {
+			return fmt.Errorf("failed to check catalog prices: %w", err)
+		}
+		printPriceReport(l, prices)
+	}
+
+	if !purgeCatalog && !syncCatalog && !syncCatalogOffers {
+		l.Info("No actions requested. Use --purge to delete dangling offers, --sync to repair catalog names or --sync-offers to push gamedata offer data.")
+		return nil
+	}
+
+	plan := report.Plan
+	if syncCatalogOffers {
+		gamedata, err := catalog.LoadGamedata(ctx, client, cfg.Storage.Buckets().Gamedata)
+		if err != nil {
+			return err
+		}
+		offers, err := catalog.PlanOffers(ctx, db, cfg.Server.Emulator, gamedata)
+		if err != nil {
+			return fmt.Errorf("failed to plan offer sync: %w", err)
+		}
+		printOfferPreview(l, offers)
+		plan.Actions = append(plan.Actions, offers.Actions...)
+		plan.Summary.SyncActions += len(offers.Actions)
+	}
+
+	// Export the plan for review; it is applied later by reconcile catalog apply
+	if planOut != "" {
+		return exportPlan(l, cfg, adapter.Name(), opts, plan)
+	}
+
+	if dryRunFlag {
+		l.Info("Dry-run mode: No changes were made.")
+		return nil
+	}
+	_, err = applyReconcilePlan(ctx, l, cfg, db, client, spec, plan, opts)
+	return err
+}
+
+func runCatalogApply(cmd *cobra.Command, args []string) error {
+	return runSavedPlan(catalog.AdapterName, func(ctx context.Context, l *zap.Logger, cfg *config.Config, db *gorm.DB, client storage.Client, opts reconcile.ReconcileOptions) (*reconcile.Spec, error) {
+		return catalog.NewSpec(catalog.NewAdapter(db, cfg.Server.Emulator), cfg.Server.Emulator), nil
+	})
+}
+
+// printCatalogReport logs every catalog issue and the planned actions.
+func printCatalogReport(l *zap.Logger, report *catalog.Report) {
+	for _, offer := range report.Dangling {
+		l.Warn("Offer references deleted furniture",
+			zap.Int("offer", offer.ID),
+			zap.Int("page", offer.PageID),
+			zap.String("catalog_name", offer.CatalogName),
+			zap.Ints("missing", offer.Missing))
+	}
+	for _, offer := range report.Pageless {
+		l.Warn("Offer on missing page",
+			zap.Int("offer", offer.ID),
+			zap.Int("page", offer.PageID),
+			zap.String("catalog_name", offer.CatalogName))
+	}
+	for _, item := range report.Unpublished {
+		l.Info("Furniture not in catalog", zap.Int("id", item.ID), zap.String("item_name", item.ItemName))
+	}
+	for _, m := range report.Mismatches {
+		l.Info("Catalog name mismatch",
+			zap.Int("offer", m.OfferID),
+			zap.Int("item", m.ItemID),
+			zap.String("catalog_name", m.CatalogName),
+			zap.String("item_name", m.ItemName))
+	}
+	for _, action := range report.Actions {
+		l.Info("Planned action",
+			zap.String("action", string(action.Type)),
+			zap.String("offer", action.Key),
+			zap.String("reason", action.Reason))
+	}
+
+	l.Info("Catalog report",
+		zap.Int("offers", report.Offers),
+		zap.Int("pages", report.Pages),
+		zap.Int("furniture", report.Furniture),
+		zap.Int("dangling", len(report.Dangling)),
+		zap.Int("pageless", len(report.Pageless)),
+		zap.Int("unpublished", len(report.Unpublished)),
+		zap.Int("mismatches", len(report.Mismatches)),
+		zap.Int("actions", len(report.Actions)))
+}
+
+// printPriceReport logs every implausible offer price.
+func printPriceReport(l *zap.Logger, report *catalog.PriceReport) {
+	for _, issue := range report.Issues {
+		l.Warn("Implausible offer price",
+			zap.String("kind", issue.Kind),
+			zap.Int("offer", issue.OfferID),
+			zap.Int("page", issue.PageID),
+			zap.String("catalog_name", issue.CatalogName),
+			zap.String("column", issue.Column),
+			zap.Int64("price", issue.Price),
+			zap.Int("max", issue.Max))
+	}
+
+	l.Info("Price report",
+		zap.Int("checked", report.Checked),
+		zap.Strings("columns", report.Columns),
+		zap.Int("free", report.Free),
+		zap.Int("negative", report.Negative),
+		zap.Int("too_high", report.TooHigh))
+}
+
+// printOfferPreview logs every catalog row the offer sync would change.
+func printOfferPreview(l *zap.Logger, report *catalog.OfferReport) {
+	if len(report.Skipped) > 0 {
+		l.Warn("Commerce fields not synced: not mapped by the server profile or missing from catalog_items",
+			zap.Strings("fields", report.Skipped))
+	}
+	for _, offer := range report.Syncs {
+		for _, change := range offer.Changes {
+			l.Info("Offer sync",
+				zap.Int("offer", offer.OfferID),
+				zap.Int("page", offer.PageID),
+				zap.String("catalog_name", offer.CatalogName),
+				zap.Int("sprite", offer.SpriteID),
+				zap.String("column", change.Column),
+				zap.String("current", change.Current),
+				zap.String("gamedata", change.Target))
+		}
+	}
+
+	l.Info("Offer sync preview",
+		zap.Int("checked", report.Checked),
+		zap.Strings("columns", report.Columns),
+		zap.Int("offers", len(report.Syncs)))
+}
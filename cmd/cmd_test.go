@@ -1,9 +1,26 @@
 package cmd
 
 import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"asset-manager/core/json"
+	"asset-manager/core/reconcile"
+	"asset-manager/core/server"
+	"asset-manager/feature/furniture/models"
+
+	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 func TestRootCmdStructure(t *testing.T) {
@@ -20,6 +37,7 @@ func TestRootCmdStructure(t *testing.T) {
 
 	assert.True(t, cmdMap["start"], "start command should be registered")
 	assert.True(t, cmdMap["integrity"], "integrity command should be registered")
+	assert.True(t, cmdMap["import"], "import command should be registered")
 }
 
 func TestIntegrityCmdStructure(t *testing.T) {
@@ -37,6 +55,8 @@ func TestIntegrityCmdStructure(t *testing.T) {
 	assert.True(t, cmdMap["bundle"], "bundle command should be registered")
 	assert.True(t, cmdMap["gamedata"], "gamedata command should be registered")
 	assert.True(t, cmdMap["server"], "server command should be registered")
+	assert.True(t, cmdMap["health"], "health command should be registered")
+	assert.True(t, cmdMap["badges"], "badges command should be registered")
 }
 
 func TestFlags(t *testing.T) {
@@ -46,4 +66,291 @@ func TestFlags(t *testing.T) {
 
 	jsonFlag := furnitureCmd.Flags().Lookup("json")
 	assert.NotNil(t, jsonFlag)
+
+	policyFlag := furnitureReconcileCmd.Flags().Lookup("purge-policy")
+	assert.NotNil(t, policyFlag)
+
+	assert.NotNil(t, furnitureReconcileCmd.Flags().Lookup("ignore-online-gate"))
+	assert.NotNil(t, catalogReconcileCmd.Flags().Lookup("ignore-online-gate"))
+	assert.Equal(t, "strict", policyFlag.DefValue)
+
+	assert.NotNil(t, furnitureReconcileCmd.Flags().Lookup("interactive"))
+	if planFileFlag := furnitureReconcileCmd.Flags().Lookup("plan-file"); assert.NotNil(t, planFileFlag) {
+		assert.Equal(t, "reconcile-plan.json", planFileFlag.DefValue)
+	}
+	assert.NotNil(t, furnitureReconcileCmd.Flags().Lookup("plan-out"))
+	if planInFlag := furnitureApplyCmd.Flags().Lookup("plan-in"); assert.NotNil(t, planInFlag) {
+		assert.Equal(t, []string{"true"}, planInFlag.Annotations[cobra.BashCompOneRequiredFlag])
+	}
+	assert.NotNil(t, furnitureApplyCmd.Flags().Lookup("yes"))
+
+	concurrencyFlag := allReconcileCmd.Flags().Lookup("concurrency")
+	if assert.NotNil(t, concurrencyFlag) {
+		assert.Equal(t, "4", concurrencyFlag.DefValue)
+	}
+	assert.NotNil(t, allReconcileCmd.Flags().Lookup("purge"))
+
+	assert.NotNil(t, gamedataCmd.Flags().Lookup("deep"))
+	assert.NotNil(t, gamedataCmd.Flags().Lookup("file"))
+	assert.NotNil(t, gamedataCmd.Flags().Lookup("figure"))
+	assert.NotNil(t, gamedataCmd.Flags().Lookup("texts"))
+
+	for _, c := range []*cobra.Command{furnitureCmd, gamedataCmd, catalogCmd, badgesCmd} {
+		formatFlag := c.Flags().Lookup("format")
+		if assert.NotNil(t, formatFlag, c.Name()) {
+			assert.Equal(t, "text", formatFlag.DefValue)
+		}
+	}
+
+	snapshotFlag := furnitureReconcileCmd.Flags().Lookup("from-snapshot")
+	if assert.NotNil(t, snapshotFlag) {
+		assert.Empty(t, snapshotFlag.DefValue)
+	}
+
+	safeFixFlag := furnitureReconcileCmd.Flags().Lookup("safe-fix")
+	assert.NotNil(t, safeFixFlag)
+	assert.Equal(t, "false", safeFixFlag.DefValue)
+
+	fixStorageFlag := furnitureReconcileCmd.Flags().Lookup("fix-storage")
+	if assert.NotNil(t, fixStorageFlag) {
+		assert.Equal(t, "false", fixStorageFlag.DefValue)
+	}
+
+	insertGamedataFlag := furnitureReconcileCmd.Flags().Lookup("insert-gamedata")
+	if assert.NotNil(t, insertGamedataFlag) {
+		assert.Equal(t, "false", insertGamedataFlag.DefValue)
+	}
+
+	syncDirectionFlag := furnitureReconcileCmd.Flags().Lookup("sync-direction")
+	if assert.NotNil(t, syncDirectionFlag) {
+		assert.Equal(t, "gamedata-to-db", syncDirectionFlag.DefValue)
+	}
+
+	logMutationsFlag := furnitureReconcileCmd.Flags().Lookup("log-mutations")
+	if assert.NotNil(t, logMutationsFlag) {
+		assert.Equal(t, "false", logMutationsFlag.DefValue)
+	}
+}
+
+func TestReconcileCatalogCmdStructure(t *testing.T) {
+	found, _, err := RootCmd.Find([]string{"reconcile", "catalog"})
+	assert.NoError(t, err)
+	assert.Equal(t, catalogReconcileCmd, found)
+	for _, name := range []string{"purge", "sync", "sync-offers", "prices", "dry-run", "yes", "plan-out"} {
+		assert.NotNil(t, catalogReconcileCmd.Flags().Lookup(name), name)
+	}
+
+	found, _, err = RootCmd.Find([]string{"reconcile", "catalog", "apply"})
+	assert.NoError(t, err)
+	assert.Equal(t, catalogApplyCmd, found)
+	if planInFlag := catalogApplyCmd.Flags().Lookup("plan-in"); assert.NotNil(t, planInFlag) {
+		assert.Equal(t, []string{"true"}, planInFlag.Annotations[cobra.BashCompOneRequiredFlag])
+	}
+}
+
+func TestFurnitureRenameCmdStructure(t *testing.T) {
+	// The rename subcommand must win over the [identifier] argument
+	found, args, err := RootCmd.Find([]string{"furniture", "rename", "old_chair", "new_chair"})
+	assert.NoError(t, err)
+	assert.Equal(t, furnitureRenameCmd, found)
+	assert.Equal(t, []string{"old_chair", "new_chair"}, args)
+
+	found, _, err = RootCmd.Find([]string{"furniture", "f_couch"})
+	assert.NoError(t, err)
+	assert.Equal(t, furnitureDetailCmd, found)
+
+	assert.NotNil(t, furnitureRenameCmd.Flags().Lookup("dry-run"))
+	assert.NotNil(t, furnitureRenameCmd.Flags().Lookup("yes"))
+	assert.NotNil(t, furnitureRenameCmd.Flags().Lookup("prefix"))
+}
+
+func TestFurnitureNamesCmdStructure(t *testing.T) {
+	found, _, err := RootCmd.Find([]string{"furniture", "names"})
+	assert.NoError(t, err)
+	assert.Equal(t, furnitureNamesCmd, found)
+	for _, name := range []string{"repair", "dry-run", "yes"} {
+		assert.NotNil(t, furnitureNamesCmd.Flags().Lookup(name), name)
+	}
+}
+
+func TestPackCmdStructure(t *testing.T) {
+	found, _, err := RootCmd.Find([]string{"pack", "build"})
+	assert.NoError(t, err)
+	assert.Equal(t, packBuildCmd, found)
+	assert.NotNil(t, packBuildCmd.Flags().Lookup("ids"))
+	assert.NotNil(t, packBuildCmd.Flags().Lookup("out"))
+
+	found, args, err := RootCmd.Find([]string{"pack", "install", "pack.tar.gz"})
+	assert.NoError(t, err)
+	assert.Equal(t, packInstallCmd, found)
+	assert.Equal(t, []string{"pack.tar.gz"}, args)
+	assert.NotNil(t, packInstallCmd.Flags().Lookup("dry-run"))
+	assert.NotNil(t, packInstallCmd.Flags().Lookup("yes"))
+}
+
+func TestUndoCmdStructure(t *testing.T) {
+	found, args, err := RootCmd.Find([]string{"undo", "plan-1"})
+	assert.NoError(t, err)
+	assert.Equal(t, undoCmd, found)
+	assert.Equal(t, []string{"plan-1"}, args)
+	assert.NotNil(t, undoCmd.Flags().Lookup("dry-run"))
+	assert.NotNil(t, undoCmd.Flags().Lookup("yes"))
+}
+
+func TestRollbackCmdStructure(t *testing.T) {
+	found, args, err := RootCmd.Find([]string{"reconcile", "rollback", "plan-1"})
+	assert.NoError(t, err)
+	assert.Equal(t, rollbackCmd, found)
+	assert.Equal(t, []string{"plan-1"}, args)
+	assert.NotNil(t, rollbackCmd.Flags().Lookup("dry-run"))
+	assert.NotNil(t, rollbackCmd.Flags().Lookup("yes"))
+	assert.NotNil(t, rollbackCmd.Flags().Lookup("ignore-online-gate"))
+}
+
+func TestSnapshotCmdStructure(t *testing.T) {
+	found, _, err := RootCmd.Find([]string{"snapshot"})
+	assert.NoError(t, err)
+	assert.Equal(t, snapshotCmd, found)
+	outputFlag := snapshotCmd.Flags().Lookup("output")
+	if assert.NotNil(t, outputFlag) {
+		assert.Equal(t, "o", outputFlag.Shorthand)
+		assert.Empty(t, outputFlag.DefValue)
+	}
+}
+
+func TestPingCmdStructure(t *testing.T) {
+	assert.Equal(t, "ping", pingCmd.Use)
+	assert.NotNil(t, pingCmd.Flags().Lookup("url"))
+	assert.NotNil(t, pingCmd.Flags().Lookup("direct"))
+	timeoutFlag := pingCmd.Flags().Lookup("timeout")
+	if assert.NotNil(t, timeoutFlag) {
+		assert.Equal(t, "3s", timeoutFlag.DefValue)
+	}
+}
+
+func TestPingHTTP(t *testing.T) {
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, healthzPath, r.URL.Path)
+	}))
+	defer healthy.Close()
+	assert.NoError(t, pingHTTP(context.Background(), http.DefaultClient, healthy.URL+healthzPath))
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+	assert.ErrorContains(t, pingHTTP(context.Background(), http.DefaultClient, failing.URL+healthzPath), "answered 503")
+
+	url := healthy.URL
+	healthy.Close()
+	assert.ErrorContains(t, pingHTTP(context.Background(), http.DefaultClient, url+healthzPath), "health check failed")
+}
+
+func TestLocalHealthURL(t *testing.T) {
+	assert.Equal(t, "http://127.0.0.1:8080/healthz", localHealthURL(server.Config{Port: "8080"}))
+	assert.Equal(t, "http://127.0.0.1:8080/healthz", localHealthURL(server.Config{Host: "0.0.0.0", Port: "8080"}))
+	assert.Equal(t, "https://[::1]:8443/healthz", localHealthURL(server.Config{Host: "::1", Port: "8443", TLSCert: "c", TLSKey: "k"}))
+}
+
+func TestStartCmdFlags(t *testing.T) {
+	assert.Contains(t, startCmd.Aliases, "serve")
+	for _, name := range []string{"host", "port", "tls-cert", "tls-key", "http2"} {
+		assert.NotNil(t, startCmd.Flags().Lookup(name), name)
+	}
+
+	cmd := &cobra.Command{}
+	cmd.Flags().AddFlagSet(startCmd.Flags())
+	require.NoError(t, cmd.Flags().Parse([]string{"--host", "127.0.0.1", "--http2"}))
+	cfg := server.Config{Port: "8080"}
+	applyServeFlags(cmd, &cfg)
+	assert.Equal(t, server.Config{Host: "127.0.0.1", Port: "8080", HTTP2: true}, cfg)
+}
+
+// TestProgressReporter tests that progress is drawn as bars on a terminal and logged
+// at stage changes and completion otherwise.
+func TestProgressReporter(t *testing.T) {
+	events := []reconcile.ProgressEvent{
+		{Stage: reconcile.StageIndexes},
+		{Stage: reconcile.StageIndexed, Source: reconcile.SourceDB, Processed: 4},
+		{Stage: reconcile.StageResults, Processed: 2, Total: 4},
+		{Stage: reconcile.StageResults, Processed: 3, Total: 4},
+		{Stage: reconcile.StageResults, Processed: 4, Total: 4},
+		{Stage: reconcile.StageSummary, Summary: &reconcile.PlanSummary{TotalItems: 4}},
+	}
+
+	var out bytes.Buffer
+	tty := &progressReporter{out: &out, tty: true}
+	for _, event := range events {
+		tty.report(event)
+	}
+	assert.Contains(t, out.String(), "Indexed db: 4 items\n")
+	assert.Contains(t, out.String(), "\r\033[KComparing [###############---------------] 2/4 (50%)")
+	assert.Contains(t, out.String(), "Comparing [##############################] 4/4 (100%)\n")
+	assert.False(t, tty.open)
+
+	core, logs := observer.New(zap.InfoLevel)
+	logged := &progressReporter{l: zap.New(core), interval: time.Hour}
+	for _, event := range events {
+		logged.report(event)
+	}
+	stages := make([]string, 0)
+	for _, entry := range logs.All() {
+		stages = append(stages, entry.ContextMap()["stage"].(string))
+	}
+	// The intermediate result is within the interval of the first
+	assert.Equal(t, []string{"indexes", "indexed", "results", "results", "summary"}, stages)
+}
+
+// TestWriteIssueSummary tests that the summary is written next to its issue file.
+func TestWriteIssueSummary(t *testing.T) {
+	issueFile := filepath.Join(t.TempDir(), "integrity_furniture_1700000000.json")
+
+	filename, err := writeIssueSummary(models.IssueSummary{
+		IssueFile:       issueFile,
+		ItemsWithIssues: 2,
+		Summary:         reconcile.PlanSummary{TotalItems: 10, Mismatches: 2},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, strings.TrimSuffix(issueFile, ".json")+"_summary.json", filename)
+
+	data, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	var summary models.IssueSummary
+	require.NoError(t, json.Unmarshal(data, &summary))
+	assert.Equal(t, issueFile, summary.IssueFile)
+	assert.Equal(t, 2, summary.ItemsWithIssues)
+	assert.Equal(t, 10, summary.Summary.TotalItems)
+}
+
+// TestResolveItems tests the interactive prompt: per-item answers, decisions applied to
+// similar items and resuming after a quit.
+func TestResolveItems(t *testing.T) {
+	plan := &reconcile.ReconcilePlan{Actions: []reconcile.Action{
+		{Type: reconcile.ActionDeleteStorage, Key: "a", Reason: "missing in: [db gamedata]"},
+		{Type: reconcile.ActionSyncDB, Key: "b", Reason: "mismatch: [name: gd=x db=y]", Fields: []string{"name"}},
+		{Type: reconcile.ActionSyncDB, Key: "c", Reason: "mismatch: [name: gd=z db=w]", Fields: []string{"name"}},
+		{Type: reconcile.ActionDeleteStorage, Key: "d", Reason: "missing in: [db]"},
+	}}
+	items := reconcile.PendingItems(plan)
+	res := &reconcile.Resolution{}
+	saves := 0
+	save := func() error { saves++; return nil }
+
+	// An invalid answer is asked again; sync is no choice for a delete
+	var out bytes.Buffer
+	complete, err := resolveItems(strings.NewReader("s\nd\nq\n"), &out, items, res, save)
+	require.NoError(t, err)
+	assert.False(t, complete)
+	assert.Contains(t, out.String(), `Unknown answer "s"`)
+	assert.True(t, res.Decided("a"))
+	assert.Equal(t, 1, saves)
+
+	// Resuming skips decided items; S syncs every similar item
+	out.Reset()
+	complete, err = resolveItems(strings.NewReader("S\nn\n"), &out, items, res, save)
+	require.NoError(t, err)
+	assert.True(t, complete)
+	assert.Contains(t, out.String(), "c: sync (as similar items)")
+	assert.False(t, res.Decided("d"))
+	assert.Equal(t, []reconcile.Action{plan.Actions[0], plan.Actions[1], plan.Actions[2]}, res.Approved(plan))
 }
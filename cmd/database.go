@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"asset-manager/core/config"
+	"asset-manager/core/database"
+	"asset-manager/core/reconcile"
+	"asset-manager/core/server"
+	furnitureAdp "asset-manager/feature/furniture/reconcile"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// openReplica connects the read replica configured in DATABASE_READ_DSN and registers
+// it for reconcile index building. The replica is optional: failures are logged and
+// reads fall back to the primary.
+func openReplica(cfg *config.Config, l *zap.Logger) {
+	replica, err := database.ConnectReplica(cfg.Database)
+	if err != nil {
+		l.Warn("Read replica unavailable, reading from primary", zap.Error(err))
+		return
+	}
+	if replica == nil {
+		return
+	}
+
+	reconcile.SetReadReplica(replica)
+	l.Info("Reading reconcile indices from replica")
+}
+
+// detectEmulator resolves the emulator of the connected database into
+// cfg.Server.Emulator: "auto" becomes the detected emulator, and "arcturus" picks the
+// arcturus-ms profile on Morningstar 4.x databases. A profile configured under the
+// setting's name is left alone. A failed detection keeps the configured emulator, or
+// falls back to arcturus for "auto".
+func detectEmulator(cfg *config.Config, db *gorm.DB, l *zap.Logger) {
+	if _, custom := cfg.Profiles[cfg.Server.Emulator]; custom {
+		return
+	}
+	emulator, err := furnitureAdp.DetectEmulator(db, cfg.Server.Emulator)
+	if err != nil {
+		if cfg.Server.Emulator == server.EmulatorAuto {
+			l.Warn("Emulator detection failed, using arcturus", zap.Error(err))
+			cfg.Server.Emulator = server.EmulatorArcturus
+			return
+		}
+		l.Warn("Emulator schema detection failed, using configured emulator", zap.String("emulator", cfg.Server.Emulator), zap.Error(err))
+		return
+	}
+	if emulator != cfg.Server.Emulator {
+		l.Info("Detected emulator profile", zap.String("configured", cfg.Server.Emulator), zap.String("emulator", emulator))
+		cfg.Server.Emulator = emulator
+	}
+}
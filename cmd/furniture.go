@@ -4,18 +4,28 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"sort"
 
 	"asset-manager/core/config"
 	"asset-manager/core/database"
 	"asset-manager/core/logger"
 	"asset-manager/core/storage"
 	"asset-manager/feature/furniture"
+	"asset-manager/feature/furniture/models"
 
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
 
+var (
+	// renamePrefix makes furniture rename migrate a classname prefix.
+	renamePrefix bool
+
+	// repairNames makes furniture names write the repaired names.
+	repairNames bool
+)
+
 // furnitureDetailCmd represents the top-level furniture command
 var furnitureDetailCmd = &cobra.Command{
 	Use:   "furniture [identifier]",
@@ -27,8 +37,226 @@ var furnitureDetailCmd = &cobra.Command{
 	},
 }
 
+// furnitureRenameCmd renames a furniture classname across every source
+var furnitureRenameCmd = &cobra.Command{
+	Use:   "rename <old> <new>",
+	Short: "Rename a furniture classname in the database, gamedata, catalog and storage",
+	Long: `Renames a classname (and its color variants, e.g. old*2) in the furniture table,
+FurnitureData.json, catalog_items.catalog_name and the bundled .nitro file together.
+With --prefix, every classname starting with <old> is renamed to start with <new>.
+The database changes only commit once the new gamedata is written; a failure undoes
+the earlier steps.
+
+Examples:
+  # Preview the rename
+  furniture rename chair_old chair_new --dry-run
+
+  # Apply without a prompt
+  furniture rename chair_old chair_new --yes
+
+  # Move a whole content line to a new naming scheme
+  furniture rename xmas14_ xmas_2014_ --prefix --dry-run`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runFurnitureRename(cmd.Context(), args[0], args[1])
+	},
+}
+
+// furnitureNamesCmd finds and repairs garbled furniture names
+var furnitureNamesCmd = &cobra.Command{
+	Use:   "names",
+	Short: "Find public names garbled by HTML entities or double encoding, and optionally repair them",
+	Long: `Compares every public_name with the gamedata name and reports names that are the
+gamedata name garbled by HTML entities (Rock &amp; Roll) or by a latin1/UTF-8 double
+encoding (CafÃ© for Café). With --repair, the gamedata names are written back.
+Names that differ in any other way are left to reconcile furniture --sync.
+
+Examples:
+  # Report only
+  furniture names
+
+  # Repair without a prompt
+  furniture names --repair --yes`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runFurnitureNames(cmd.Context())
+	},
+}
+
 func init() {
 	RootCmd.AddCommand(furnitureDetailCmd)
+	furnitureDetailCmd.AddCommand(furnitureRenameCmd, furnitureNamesCmd)
+
+	furnitureRenameCmd.Flags().BoolVar(&dryRunFlag, "dry-run", false, "Show what would be renamed without changing anything")
+	furnitureRenameCmd.Flags().BoolVar(&yesConfirm, "yes", false, "Auto-confirm the rename (non-interactive)")
+	furnitureRenameCmd.Flags().BoolVar(&renamePrefix, "prefix", false, "Rename every classname starting with <old> to start with <new>")
+
+	furnitureNamesCmd.Flags().BoolVar(&repairNames, "repair", false, "Write the gamedata name over every garbled public_name")
+	furnitureNamesCmd.Flags().BoolVar(&dryRunFlag, "dry-run", false, "Force dry-run (no changes even with --repair)")
+	furnitureNamesCmd.Flags().BoolVar(&yesConfirm, "yes", false, "Auto-confirm the repair (non-interactive)")
+}
+
+func runFurnitureRename(ctx context.Context, oldName, newName string) error {
+	cfg, err := config.LoadConfig(".")
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	logg, err := logger.New(&cfg.Log)
+	if err != nil {
+		return fmt.Errorf("failed to create logger: %w", err)
+	}
+
+	store, err := storage.NewClient(cfg.Storage)
+	if err != nil {
+		return fmt.Errorf("failed to create storage client: %w", err)
+	}
+
+	db, err := database.Connect(cfg.Database)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	detectEmulator(cfg, db, logg)
+
+	svc := furniture.NewService(store, cfg.Storage.Buckets(), logg, db, cfg.Server.Emulator)
+	rename := svc.RenameClassname
+	if renamePrefix {
+		rename = svc.RenamePrefix
+	}
+
+	plan, err := rename(ctx, oldName, newName, true)
+	if err != nil {
+		return fmt.Errorf("failed to plan rename: %w", err)
+	}
+	printRenamePlan(logg, plan)
+
+	if dryRunFlag {
+		logg.Info("Dry-run mode: No changes were made.")
+		return nil
+	}
+	if !confirmDestructiveAction() {
+		logg.Warn("Operation cancelled by user. No changes were made.")
+		return nil
+	}
+
+	previewed := make(map[string]bool, len(plan.Warnings))
+	for _, warning := range plan.Warnings {
+		previewed[warning] = true
+	}
+
+	plan, err = rename(ctx, oldName, newName, false)
+	if plan != nil {
+		for _, warning := range plan.Warnings {
+			if !previewed[warning] {
+				logg.Warn("Rename warning", zap.String("warning", warning))
+			}
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to rename: %w", err)
+	}
+
+	logg.Info("Classnames renamed", zap.String("old", oldName), zap.String("new", newName), zap.Int("classnames", len(plan.Classnames)))
+	return nil
+}
+
+// printRenamePlan logs what a classname rename changes in each source.
+func printRenamePlan(l *zap.Logger, plan *models.RenamePlan) {
+	names := make([]string, 0, len(plan.Classnames))
+	for name := range plan.Classnames {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		l.Info("Rename classname", zap.String("from", name), zap.String("to", plan.Classnames[name]))
+	}
+
+	l.Info("Rename plan",
+		zap.String("old", plan.Old),
+		zap.String("new", plan.New),
+		zap.Bool("prefix", plan.Prefix),
+		zap.Int("classnames", len(plan.Classnames)),
+		zap.Int("gamedata_entries", plan.GamedataEntries),
+		zap.Int("db_rows", plan.DBRows),
+		zap.Int("catalog_rows", plan.CatalogRows),
+		zap.Int("storage_files", len(plan.Storage)),
+	)
+	for _, warning := range plan.Warnings {
+		l.Warn("Rename warning", zap.String("warning", warning))
+	}
+}
+
+func runFurnitureNames(ctx context.Context) error {
+	cfg, err := config.LoadConfig(".")
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	logg, err := logger.New(&cfg.Log)
+	if err != nil {
+		return fmt.Errorf("failed to create logger: %w", err)
+	}
+
+	store, err := storage.NewClient(cfg.Storage)
+	if err != nil {
+		return fmt.Errorf("failed to create storage client: %w", err)
+	}
+
+	db, err := database.Connect(cfg.Database)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	detectEmulator(cfg, db, logg)
+
+	svc := furniture.NewService(store, cfg.Storage.Buckets(), logg, db, cfg.Server.Emulator)
+	report, err := svc.RepairNames(ctx, true)
+	if err != nil {
+		return fmt.Errorf("failed to check names: %w", err)
+	}
+	printNameRepairs(logg, report)
+
+	if !repairNames {
+		if len(report.Repairs) > 0 {
+			logg.Info("No actions requested. Use --repair to restore the gamedata names.")
+		}
+		return nil
+	}
+	if dryRunFlag {
+		logg.Info("Dry-run mode: No changes were made.")
+		return nil
+	}
+	if len(report.Repairs) == 0 {
+		logg.Info("No names to repair.")
+		return nil
+	}
+	if !confirmDestructiveAction() {
+		logg.Warn("Operation cancelled by user. No changes were made.")
+		return nil
+	}
+
+	report, err = svc.RepairNames(ctx, false)
+	if err != nil {
+		return fmt.Errorf("failed to repair names: %w", err)
+	}
+	logg.Info("Names repaired", zap.Int("names", len(report.Repairs)))
+	return nil
+}
+
+// printNameRepairs logs every garbled name and the name it is restored to.
+func printNameRepairs(l *zap.Logger, report *models.NameEncodingReport) {
+	if report.Skipped != "" {
+		l.Warn("Name encoding check skipped", zap.String("reason", report.Skipped))
+		return
+	}
+	for _, repair := range report.Repairs {
+		l.Warn("Garbled name",
+			zap.String("key", repair.Key),
+			zap.String("classname", repair.ClassName),
+			zap.String("stored", repair.Stored),
+			zap.String("repaired", repair.Repaired),
+			zap.Strings("issues", repair.Issues))
+	}
+	l.Info("Name encoding report", zap.Int("checked", report.Checked), zap.Int("garbled", len(report.Repairs)))
 }
 
 func runFurnitureDetailCheck(ctx context.Context, identifier string) {
@@ -54,11 +282,14 @@ func runFurnitureDetailCheck(ctx context.Context, identifier string) {
 	if conn, err := database.Connect(cfg.Database); err != nil {
 		logg.Warn("Optional database connection failed", zap.Error(err))
 	} else {
+		detectEmulator(cfg, conn, logg)
 		db = conn
 		logg = logg.With(zap.String("server", cfg.Server.Emulator))
+		openReplica(cfg, logg)
+		applyReconcileConfig(cfg)
 	}
 
-	svc := furniture.NewService(store, cfg.Storage.Bucket, logg, db, cfg.Server.Emulator)
+	svc := furniture.NewService(store, cfg.Storage.Buckets(), logg, db, cfg.Server.Emulator)
 
 	logg.Info("Checking furniture item...", zap.String("identifier", identifier))
 	report, err := svc.GetFurnitureDetail(ctx, identifier)
@@ -94,5 +325,11 @@ func runFurnitureDetailCheck(ctx context.Context, identifier string) {
 			fmt.Printf("- %s\n", m)
 		}
 	}
+	if len(report.Warnings) > 0 {
+		fmt.Println("\nWarnings:")
+		for _, w := range report.Warnings {
+			fmt.Printf("- %s\n", w)
+		}
+	}
 	fmt.Println("-----------------------------")
 }
@@ -0,0 +1,274 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"asset-manager/core/config"
+	"asset-manager/core/json"
+	"asset-manager/core/logger"
+	"asset-manager/core/storage"
+	furnitureReconcile "asset-manager/feature/furniture/reconcile"
+	"asset-manager/feature/pack"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+var (
+	// importManifest is the manifest file of import furniture.
+	importManifest string
+	// importTemplate is the template file of import furniture.
+	importTemplate string
+)
+
+// importCmd groups the bulk import commands
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Bulk import assets from archives",
+}
+
+// importFurnitureCmd imports a ZIP archive of .nitro files
+var importFurnitureCmd = &cobra.Command{
+	Use:   "furniture <file.zip>",
+	Short: "Import a ZIP archive of .nitro files as new furniture",
+	Long: `Extracts a ZIP archive, uploads every .nitro file to storage and adds a
+FurnitureData.json entry per file. Entries start from the template (by default a
+1x1 room item named after its classname) with the manifest item of their classname
+on top. Without --manifest, manifest.json at the archive root is used if present.
+
+Files that fail the upload checks, and classnames or IDs already in gamedata or
+storage, are skipped. Database rows are left to "reconcile furniture --sync".
+With UPLOAD_CONVERT_COMMAND or UPLOAD_CONVERT_URL set, .swf files are converted
+and imported too.
+
+Manifest format:
+  {"template": {...}, "items": [{"classname": "chair", "id": 5001, "name": "Chair"}]}
+
+Examples:
+  # List what would be created and skipped
+  import furniture ./pack.zip --dry-run
+
+  # Import with a manifest, without a prompt
+  import furniture ./pack.zip --manifest items.json --yes`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runImportFurniture(cmd.Context(), args[0])
+	},
+}
+
+// importSWFCmd converts the legacy .swf files in storage
+var importSWFCmd = &cobra.Command{
+	Use:   "swf",
+	Short: "Convert legacy .swf furniture in storage to bundled .nitro files",
+	Long: `Converts every .swf file under UPLOAD_CONVERT_SOURCE_PREFIX (default swf/) whose
+classname has no bundled .nitro file yet, with the converter command or service of
+UPLOAD_CONVERT_COMMAND or UPLOAD_CONVERT_URL, and writes the bundles to
+bundled/furniture. A failed file does not stop the others. Every outcome is recorded
+in the conversion log (swf/conversions.json), which reconciles report as the
+swf_conversion metadata of each item. Gamedata and database rows are left alone.
+
+Examples:
+  # List the files that would be converted
+  import swf --dry-run
+
+  # Convert without a prompt
+  import swf --yes`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runImportSWF(cmd.Context())
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(importCmd)
+	importCmd.AddCommand(importFurnitureCmd, importSWFCmd)
+
+	importFurnitureCmd.Flags().StringVar(&importManifest, "manifest", "", "Manifest JSON file describing the archived items")
+	importFurnitureCmd.Flags().StringVar(&importTemplate, "template", "", "Template JSON file of the generated gamedata entries")
+	importFurnitureCmd.Flags().BoolVar(&dryRunFlag, "dry-run", false, "Show what would be imported without changing anything")
+	importFurnitureCmd.Flags().BoolVar(&yesConfirm, "yes", false, "Auto-confirm the import (non-interactive)")
+
+	importSWFCmd.Flags().BoolVar(&dryRunFlag, "dry-run", false, "List the files that would be converted without converting them")
+	importSWFCmd.Flags().BoolVar(&yesConfirm, "yes", false, "Auto-confirm the conversion (non-interactive)")
+}
+
+func runImportFurniture(ctx context.Context, path string) error {
+	archive, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read archive: %w", err)
+	}
+
+	opts := pack.ImportOptions{DryRun: true}
+	if importManifest != "" {
+		opts.Manifest = &pack.ImportManifest{}
+		if err := readJSONFile(importManifest, opts.Manifest); err != nil {
+			return err
+		}
+	}
+	if importTemplate != "" {
+		if err := readJSONFile(importTemplate, &opts.Template); err != nil {
+			return err
+		}
+	}
+
+	cfg, err := config.LoadConfig(".")
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	opts.Upload = cfg.Upload
+
+	logg, err := logger.New(&cfg.Log)
+	if err != nil {
+		return fmt.Errorf("failed to create logger: %w", err)
+	}
+
+	store, err := storage.NewClient(cfg.Storage)
+	if err != nil {
+		return fmt.Errorf("failed to create storage client: %w", err)
+	}
+
+	if err := openScanner(cfg, logg); err != nil {
+		return err
+	}
+	if err := openConverter(cfg, logg); err != nil {
+		return err
+	}
+
+	report, err := pack.ImportZip(ctx, store, cfg.Storage.Buckets(), archive, opts)
+	if err != nil {
+		return fmt.Errorf("failed to plan import: %w", err)
+	}
+	printImportReport(logg, report)
+
+	if len(report.Created) == 0 {
+		logg.Info("Nothing to import.")
+		return nil
+	}
+	if dryRunFlag {
+		logg.Info("Dry-run mode: No changes were made.")
+		return nil
+	}
+	if !confirmDestructiveAction() {
+		logg.Warn("Operation cancelled by user. No changes were made.")
+		return nil
+	}
+
+	opts.DryRun = false
+	report, err = pack.ImportZip(ctx, store, cfg.Storage.Buckets(), archive, opts)
+	if report != nil {
+		for _, warning := range report.Warnings {
+			logg.Warn("Import warning", zap.String("warning", warning))
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to import archive: %w", err)
+	}
+
+	logg.Info("Furniture imported", zap.String("file", path), zap.Int("items", len(report.Created)))
+	return nil
+}
+
+func runImportSWF(ctx context.Context) error {
+	cfg, err := config.LoadConfig(".")
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	logg, err := logger.New(&cfg.Log)
+	if err != nil {
+		return fmt.Errorf("failed to create logger: %w", err)
+	}
+
+	store, err := storage.NewClient(cfg.Storage)
+	if err != nil {
+		return fmt.Errorf("failed to create storage client: %w", err)
+	}
+
+	if err := openScanner(cfg, logg); err != nil {
+		return err
+	}
+	if err := openConverter(cfg, logg); err != nil {
+		return err
+	}
+
+	opts := pack.ConvertOptions{Upload: cfg.Upload, DryRun: true}
+	report, err := pack.ConvertSWF(ctx, store, cfg.Storage.Buckets(), opts)
+	if err != nil {
+		return fmt.Errorf("failed to list swf files: %w", err)
+	}
+	for _, file := range report.Files {
+		logg.Info("Swf file", zap.String("source", file.Source), zap.String("object", file.Object))
+	}
+	for _, skipped := range report.Skipped {
+		logg.Warn("Swf skipped", zap.String("file", skipped.File), zap.String("reason", skipped.Reason))
+	}
+	logg.Info("Swf conversion plan", zap.Int("files", len(report.Files)), zap.Int("skipped", len(report.Skipped)))
+
+	if len(report.Files) == 0 {
+		logg.Info("Nothing to convert.")
+		return nil
+	}
+	if dryRunFlag {
+		logg.Info("Dry-run mode: No changes were made.")
+		return nil
+	}
+	if !confirmDestructiveAction() {
+		logg.Warn("Operation cancelled by user. No changes were made.")
+		return nil
+	}
+
+	opts.DryRun = false
+	report, err = pack.ConvertSWF(ctx, store, cfg.Storage.Buckets(), opts)
+	if err != nil {
+		return fmt.Errorf("failed to convert swf files: %w", err)
+	}
+	failed := 0
+	for _, conversion := range report.Conversions {
+		if conversion.Status == furnitureReconcile.ConversionFailed {
+			failed++
+			logg.Warn("Swf conversion failed", zap.String("source", conversion.Source), zap.String("error", conversion.Error))
+		}
+	}
+	for _, warning := range report.Warnings {
+		logg.Warn("Conversion warning", zap.String("warning", warning))
+	}
+
+	logg.Info("Swf files converted",
+		zap.Int("converted", len(report.Conversions)-failed),
+		zap.Int("failed", failed))
+	return nil
+}
+
+// readJSONFile decodes the JSON file at path into v.
+func readJSONFile(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return nil
+}
+
+// printImportReport logs the items an import creates and the files it skips.
+func printImportReport(l *zap.Logger, report *pack.ImportReport) {
+	for _, item := range report.Created {
+		l.Info("Import item",
+			zap.Int("id", item.ID),
+			zap.String("classname", item.Classname),
+			zap.String("section", item.Section),
+			zap.String("source", item.Source))
+	}
+	for _, skipped := range report.Skipped {
+		l.Warn("Import skipped",
+			zap.String("file", skipped.File),
+			zap.String("classname", skipped.Classname),
+			zap.String("reason", skipped.Reason))
+	}
+	l.Info("Import plan",
+		zap.Int("created", len(report.Created)),
+		zap.Int("skipped", len(report.Skipped)))
+}
@@ -2,17 +2,26 @@ package cmd
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"asset-manager/core/config"
 	"asset-manager/core/database"
+	"asset-manager/core/json"
 	"asset-manager/core/logger"
+	"asset-manager/core/reconcile"
 	"asset-manager/core/storage"
+	"asset-manager/feature/badges"
+	"asset-manager/feature/catalog"
+	"asset-manager/feature/furniture/convert"
 	furnitureIntegrity "asset-manager/feature/furniture/integrity"
+	"asset-manager/feature/furniture/models"
+	furnitureReconcile "asset-manager/feature/furniture/reconcile"
 	"asset-manager/feature/integrity"
+	"asset-manager/feature/integrity/checks"
+	"asset-manager/feature/integrity/cireport"
 
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
@@ -20,6 +29,8 @@ import (
 )
 
 var fixFlag bool
+var dryRunFlag bool
+var keepFlag bool
 var dbFlag bool
 
 // integrityCmd represents the integrity command
@@ -58,8 +69,54 @@ var bundleCmd = &cobra.Command{
 var gamedataCmd = &cobra.Command{
 	Use:   "gamedata",
 	Short: "Check gamedata files",
-	Run: func(cmd *cobra.Command, args []string) {
-		runIntegrityChecks(cmd.Context(), false, false, true, false)
+	Long: `Checks that all required gamedata files are present in storage.
+
+With --deep, runs gamedata-internal validations on FurnitureData.json instead (duplicate IDs,
+duplicate classnames, invalid color variants, missing required fields). The deep mode never
+connects to the database, and with --file it validates a local file without storage access.
+
+With --figure, parses FigureData.json structurally instead: set types referencing undefined
+palettes, duplicate set IDs, and sets whose parts resolve through FigureMap.json to no clothing
+bundle under bundled/figure. With --file the local FigureData.json is checked without the
+asset check.
+
+With --texts, cross-checks FurnitureData.json against ProductData.json and ExternalTexts.json
+instead: items without a product data entry, or without furni_<classname>_name and _desc texts
+(wallitem_ for wall items), whose tooltips show blank to players. It reads storage only.
+
+With --deep, --figure or --texts, --format junit|sarif also writes the issues to stdout for CI systems.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		deep, _ := cmd.Flags().GetBool("deep")
+		figure, _ := cmd.Flags().GetBool("figure")
+		texts, _ := cmd.Flags().GetBool("texts")
+		file, _ := cmd.Flags().GetString("file")
+		format, err := formatFlag(cmd)
+		if err != nil {
+			return err
+		}
+		if (deep && figure) || (texts && (deep || figure)) {
+			return fmt.Errorf("--deep, --figure and --texts cannot be combined")
+		}
+		if texts {
+			if file != "" {
+				return fmt.Errorf("--file cannot be used with --texts")
+			}
+			return runTexts(cmd.Context(), format)
+		}
+		if figure {
+			return runFigureData(cmd.Context(), file, format)
+		}
+		if !deep {
+			if file != "" {
+				return fmt.Errorf("--file requires --deep or --figure")
+			}
+			if format != cireport.FormatText {
+				return fmt.Errorf("--format requires --deep, --figure or --texts")
+			}
+			runIntegrityChecks(cmd.Context(), false, false, true, false)
+			return nil
+		}
+		return runGamedataDeep(cmd.Context(), file, format)
 	},
 }
 
@@ -67,12 +124,16 @@ var gamedataCmd = &cobra.Command{
 var furnitureCmd = &cobra.Command{
 	Use:   "furniture",
 	Short: "Check integrity of bundled furniture",
-	Long:  `Validates furniture assets by comparing storage (S3/MinIO), gamedata (FurnitureData.json), and database. Outputs metrics by default or detailed JSON with --json flag.`,
+	Long:  `Validates furniture assets by comparing storage (S3/MinIO), gamedata (FurnitureData.json), and database. Outputs metrics by default or detailed JSON with --json flag, plus a small *_summary.json with the counts and run metadata. With --format junit|sarif the issues are also written to stdout for CI systems.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx := cmd.Context()
 		startTime := time.Now()
 
 		jsonOutput, _ := cmd.Flags().GetBool("json")
+		format, err := formatFlag(cmd)
+		if err != nil {
+			return err
+		}
 
 		cfg, err := config.LoadConfig(".")
 		if err != nil {
@@ -83,6 +144,7 @@ var furnitureCmd = &cobra.Command{
 		if err != nil {
 			return fmt.Errorf("failed to create logger: %w", err)
 		}
+		ctx, logg = startRun(ctx, logg)
 
 		// Create storage client
 		client, err := storage.NewClient(cfg.Storage)
@@ -96,53 +158,25 @@ var furnitureCmd = &cobra.Command{
 			return fmt.Errorf("database connection required: %w", err)
 		}
 
+		detectEmulator(cfg, db, logg)
+		openReplica(cfg, logg)
+		applyReconcileConfig(cfg)
+		openState(cfg, logg)
+
 		logg.Info("Checking furniture assets (this might take a while)...", zap.String("server", cfg.Server.Emulator))
+		ctx = withProgress(ctx, logg)
 
 		// Use ReconcileFurnitureWithPlan to get accurate summary (unified counting)
-		plan, err := furnitureIntegrity.ReconcileFurnitureWithPlan(ctx, client, cfg.Storage.Bucket, db, cfg.Server.Emulator)
+		plan, err := furnitureIntegrity.ReconcileFurnitureWithPlan(ctx, client, cfg.Storage.Buckets(), db, cfg.Server.Emulator)
 		if err != nil {
 			return fmt.Errorf("furniture integrity check failed: %w", err)
 		}
-		results := plan.Results
 		summary := plan.Summary
 
-		// Use summary counts from reconcile engine (unified counting with OR semantics)
-		gamedata_missing := summary.MissingGamedata
-		storage_missing := summary.MissingStorage
-		db_missing := summary.MissingDB
-		mismatch := summary.Mismatches
-
-		// Custom JSON output structure
-		type FurnitureIssue struct {
-			ID              string   `json:"id"`
-			Name            string   `json:"name"`
-			GamedataMissing bool     `json:"gamedata_missing"`
-			StorageMissing  bool     `json:"storage_missing"`
-			DBMissing       bool     `json:"db_missing"`
-			Mismatch        []string `json:"mismatch"`
-		}
-
-		// Filter results for JSON output - only include items with issues
-		var jsonIssues []FurnitureIssue
-		for _, r := range results {
-			hasIssue := !r.GamedataPresent || !r.DBPresent || !r.StoragePresent || len(r.Mismatch) > 0
-			if hasIssue {
-				// Initialize empty mismatch array to prevent null in JSON
-				mismatchList := r.Mismatch
-				if mismatchList == nil {
-					mismatchList = []string{}
-				}
+		// Only items with issues are exported; counts come from the same summary
+		jsonIssues := convert.ToIssues(plan.Results)
 
-				jsonIssues = append(jsonIssues, FurnitureIssue{
-					ID:              r.ID,
-					Name:            r.Name,
-					GamedataMissing: !r.GamedataPresent,
-					StorageMissing:  !r.StoragePresent,
-					DBMissing:       !r.DBPresent,
-					Mismatch:        mismatchList,
-				})
-			}
-		}
+		executionTime := time.Since(startTime)
 
 		if jsonOutput {
 			// Save detailed JSON to file (only items with issues)
@@ -155,18 +189,41 @@ var furnitureCmd = &cobra.Command{
 				return fmt.Errorf("failed to save JSON file: %w", err)
 			}
 			logg.Info("Detailed JSON report saved", zap.String("file", filename), zap.Int("items_with_issues", len(jsonIssues)))
-		}
 
-		executionTime := time.Since(startTime)
+			summaryFile, err := writeIssueSummary(models.IssueSummary{
+				IssueFile:       filename,
+				ItemsWithIssues: len(jsonIssues),
+				PlanID:          plan.ID,
+				RunID:           plan.RunID,
+				Emulator:        cfg.Server.Emulator,
+				GeneratedAt:     time.Now().Format(time.RFC3339),
+				ExecutionTime:   executionTime.String(),
+				Summary:         summary,
+			})
+			if err != nil {
+				return err
+			}
+			logg.Info("JSON summary saved", zap.String("file", summaryFile))
+		}
+		if err := writeCIReport(format, cireport.Furniture(jsonIssues)); err != nil {
+			return err
+		}
 
 		logg.Info("Furniture integrity check completed",
 			zap.Int("total", summary.TotalItems),
-			zap.Int("gamedata_missing", gamedata_missing),
-			zap.Int("storage_missing", storage_missing),
-			zap.Int("db_missing", db_missing),
-			zap.Int("mismatch", mismatch),
+			zap.Int("gamedata_missing", summary.MissingGamedata),
+			zap.Int("storage_missing", summary.MissingStorage),
+			zap.Int("db_missing", summary.MissingDB),
+			zap.Int("mismatch", summary.Mismatches),
+			zap.Int("flapping", summary.Flapping),
+			zap.Int("ignored", summary.Ignored),
+			zap.Int("key_conflicts", summary.KeyConflicts),
+			zap.Int("duplicate_names", summary.DuplicateNames),
 			zap.Duration("execution_time", executionTime),
 		)
+		printMemoryStats(logg, summary.Memory)
+		printDiagnostics(logg, plan.Diagnostics)
+		printFixRecipes(logg, furnitureReconcileCmd, reconcile.FixRecipes(plan.Results))
 
 		return nil
 	},
@@ -181,13 +238,431 @@ var serverCmd = &cobra.Command{
 	},
 }
 
+// catalogCmd represents the integrity catalog command
+var catalogCmd = &cobra.Command{
+	Use:   "catalog",
+	Short: "Check that catalog page icons and images exist",
+	Long:  `Reads the emulator catalog_pages table and reports shop pages whose icon or headline, teaser and special images are missing under STORAGE_LAYOUT_CATALOG_IMAGES_PREFIX. With --format junit|sarif the broken pages are also written to stdout for CI systems.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, err := formatFlag(cmd)
+		if err != nil {
+			return err
+		}
+
+		cfg, err := config.LoadConfig(".")
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		logg, err := logger.New(&cfg.Log)
+		if err != nil {
+			return fmt.Errorf("failed to create logger: %w", err)
+		}
+		ctx, logg := startRun(cmd.Context(), logg)
+
+		client, err := storage.NewClient(cfg.Storage)
+		if err != nil {
+			return fmt.Errorf("failed to create storage client: %w", err)
+		}
+
+		db, err := database.Connect(cfg.Database)
+		if err != nil {
+			return fmt.Errorf("catalog check requires a database connection: %w", err)
+		}
+		detectEmulator(cfg, db, logg)
+		openReplica(cfg, logg)
+		applyReconcileConfig(cfg)
+
+		svc := integrity.NewService(client, cfg.Storage.Buckets(), cfg.Storage.Layout, logg, db, cfg.Server.Emulator)
+		report, err := svc.CheckCatalog(ctx)
+		if err != nil {
+			return fmt.Errorf("catalog check failed: %w", err)
+		}
+
+		for _, page := range report.Broken {
+			logg.Warn("Broken catalog page",
+				zap.Int("id", page.ID),
+				zap.String("caption", page.Caption),
+				zap.Strings("missing", page.Missing),
+			)
+		}
+		logg.Info("Catalog check completed",
+			zap.Int("pages", report.Pages),
+			zap.Int("referenced", report.Referenced),
+			zap.Int("broken", len(report.Broken)),
+		)
+
+		return writeCIReport(format, cireport.Catalog(report))
+	},
+}
+
+// badgesCmd represents the integrity badges command
+var badgesCmd = &cobra.Command{
+	Use:   "badges",
+	Short: "Check badge images against defined badges",
+	Long: `Compares badge images under STORAGE_LAYOUT_BADGES_PREFIX with the badge codes defined in
+gamedata/ExternalTexts.json and, when a database is configured, the emulator's badge table.
+Reports defined badges without an image and images of badges defined nowhere.
+With --format junit|sarif the issues are also written to stdout for CI systems.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, err := formatFlag(cmd)
+		if err != nil {
+			return err
+		}
+
+		cfg, err := config.LoadConfig(".")
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		logg, err := logger.New(&cfg.Log)
+		if err != nil {
+			return fmt.Errorf("failed to create logger: %w", err)
+		}
+		ctx, logg := startRun(cmd.Context(), logg)
+
+		client, err := storage.NewClient(cfg.Storage)
+		if err != nil {
+			return fmt.Errorf("failed to create storage client: %w", err)
+		}
+
+		var db *gorm.DB
+		if conn, err := database.Connect(cfg.Database); err != nil {
+			logg.Warn("Optional database connection failed, checking against ExternalTexts.json only", zap.Error(err))
+		} else {
+			detectEmulator(cfg, conn, logg)
+			db = conn
+			openReplica(cfg, logg)
+		}
+
+		svc := badges.NewService(client, cfg.Storage.Buckets(), cfg.Storage.Layout, logg, db, cfg.Server.Emulator)
+		report, err := svc.Check(ctx)
+		if err != nil {
+			return fmt.Errorf("badge check failed: %w", err)
+		}
+
+		for _, warning := range report.Warnings {
+			logg.Warn("Badge check warning", zap.String("warning", warning))
+		}
+		for _, badge := range report.Missing {
+			logg.Warn("Missing badge image", zap.String("code", badge.Code), zap.Strings("defined_in", badge.Sources))
+		}
+		for _, badge := range report.Orphaned {
+			logg.Warn("Orphaned badge image", zap.String("code", badge.Code), zap.String("key", badge.Key))
+		}
+		logg.Info("Badge check completed",
+			zap.String("prefix", report.Prefix),
+			zap.Int("files", report.Files),
+			zap.Int("texts", report.Texts),
+			zap.Int("database", report.Database),
+			zap.Int("missing", len(report.Missing)),
+			zap.Int("orphaned", len(report.Orphaned)),
+		)
+
+		return writeCIReport(format, cireport.Badges(report))
+	},
+}
+
+// healthCmd represents the integrity health command
+var healthCmd = &cobra.Command{
+	Use:   "health",
+	Short: "Compute the combined health score across all domains",
+	Long:  `Reconciles every domain (currently furniture) and reports one hotel health score (0-100) with per-domain contributions. An entity is healthy when present in every source without mismatches.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.LoadConfig(".")
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		logg, err := logger.New(&cfg.Log)
+		if err != nil {
+			return fmt.Errorf("failed to create logger: %w", err)
+		}
+		ctx, logg := startRun(cmd.Context(), logg)
+
+		client, err := storage.NewClient(cfg.Storage)
+		if err != nil {
+			return fmt.Errorf("failed to create storage client: %w", err)
+		}
+
+		var db *gorm.DB
+		if conn, err := database.Connect(cfg.Database); err != nil {
+			logg.Warn("Optional database connection failed", zap.Error(err))
+		} else {
+			detectEmulator(cfg, conn, logg)
+			db = conn
+			openReplica(cfg, logg)
+			applyReconcileConfig(cfg)
+		}
+
+		furnitureReconcile.Register(cfg.Server.Emulator, cfg.Storage.Buckets().Gamedata, 0)
+		if db != nil {
+			catalog.Register(db, cfg.Server.Emulator)
+		}
+
+		logg.Info("Computing health score (this might take a while)...")
+		svc := integrity.NewService(client, cfg.Storage.Buckets(), cfg.Storage.Layout, logg, db, cfg.Server.Emulator)
+		report := svc.CheckHealth(ctx)
+
+		for _, domain := range report.Domains {
+			if domain.Error != "" {
+				logg.Warn("Domain health failed", zap.String("adapter", domain.Adapter), zap.String("error", domain.Error))
+				continue
+			}
+			logg.Info("Domain health",
+				zap.String("adapter", domain.Adapter),
+				zap.Float64("score", domain.Score),
+				zap.Float64("contribution", domain.Contribution),
+				zap.Int("healthy", domain.Healthy),
+				zap.Int("total", domain.Total),
+			)
+		}
+		logg.Info("Hotel health", zap.Float64("score", report.Score))
+
+		return nil
+	},
+}
+
 func init() {
 	RootCmd.AddCommand(integrityCmd)
-	integrityCmd.AddCommand(structureCmd, bundleCmd, gamedataCmd, furnitureCmd, serverCmd)
+	integrityCmd.AddCommand(structureCmd, bundleCmd, gamedataCmd, furnitureCmd, serverCmd, catalogCmd, badgesCmd, healthCmd)
 
 	structureCmd.Flags().BoolVar(&fixFlag, "fix", false, "Fix missing folders")
 	bundleCmd.Flags().BoolVar(&fixFlag, "fix", false, "Fix missing folders")
+	structureCmd.Flags().BoolVar(&dryRunFlag, "dry-run", false, "List the placeholder objects --fix would create without writing them")
+	bundleCmd.Flags().BoolVar(&dryRunFlag, "dry-run", false, "List the placeholder objects --fix would create without writing them")
+	structureCmd.Flags().BoolVar(&keepFlag, "keep", false, "Create folder/.keep objects instead of zero-byte folder objects (required in prefix folder mode)")
+	bundleCmd.Flags().BoolVar(&keepFlag, "keep", false, "Create folder/.keep objects instead of zero-byte folder objects (required in prefix folder mode)")
 	furnitureCmd.Flags().Bool("json", false, "Output detailed JSON format")
+	gamedataCmd.Flags().Bool("deep", false, "Validate FurnitureData.json contents without DB access")
+	gamedataCmd.Flags().Bool("figure", false, "Validate FigureData.json structure and clothing assets")
+	gamedataCmd.Flags().Bool("texts", false, "Cross-check FurnitureData.json against ProductData.json and ExternalTexts.json")
+	gamedataCmd.Flags().String("file", "", "Local FurnitureData.json (--deep) or FigureData.json (--figure) to validate (skips storage)")
+	for _, c := range []*cobra.Command{furnitureCmd, gamedataCmd, catalogCmd, badgesCmd} {
+		c.Flags().String("format", cireport.FormatText, "Also write the issues to stdout for CI: text (logs only), junit or sarif")
+	}
+}
+
+// formatFlag returns the validated --format of cmd.
+func formatFlag(cmd *cobra.Command) (string, error) {
+	format, _ := cmd.Flags().GetString("format")
+	return cireport.ParseFormat(format)
+}
+
+// writeCIReport writes r to stdout in format. Logs go to stderr, so the output can be
+// redirected to a file as is.
+func writeCIReport(format string, r cireport.Report) error {
+	if err := cireport.Write(os.Stdout, format, r); err != nil {
+		return fmt.Errorf("failed to write %s report: %w", format, err)
+	}
+	return nil
+}
+
+// logPlaceholders logs the placeholder objects a folder fix would create.
+func logPlaceholders(l *zap.Logger, placeholders []checks.Placeholder) {
+	for _, p := range placeholders {
+		l.Info("Would create placeholder", zap.String("folder", p.Folder), zap.String("bucket", p.Bucket), zap.String("key", p.Key))
+	}
+	l.Info("Dry-run mode: No changes were made.")
+}
+
+// writeIssueSummary writes summary next to its issue file, as <issue file>_summary.json,
+// and returns the file name.
+func writeIssueSummary(summary models.IssueSummary) (string, error) {
+	filename := strings.TrimSuffix(summary.IssueFile, ".json") + "_summary.json"
+	data, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal JSON summary: %w", err)
+	}
+	if err := os.WriteFile(filename, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to save JSON summary: %w", err)
+	}
+	return filename, nil
+}
+
+// runGamedataDeep validates FurnitureData.json from a local file or storage without a DB connection.
+func runGamedataDeep(ctx context.Context, file, format string) error {
+	cfg, err := config.LoadConfig(".")
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	logg, err := logger.New(&cfg.Log)
+	if err != nil {
+		return fmt.Errorf("failed to create logger: %w", err)
+	}
+	ctx, logg = startRun(ctx, logg)
+
+	var data []byte
+	source := file
+	if file != "" {
+		if data, err = os.ReadFile(file); err != nil {
+			return fmt.Errorf("failed to read gamedata file: %w", err)
+		}
+	} else {
+		client, err := storage.NewClient(cfg.Storage)
+		if err != nil {
+			return fmt.Errorf("failed to create storage client: %w", err)
+		}
+		if data, err = checks.LoadFurnitureData(ctx, client, cfg.Storage.Buckets().Gamedata); err != nil {
+			return err
+		}
+		source = checks.FurnitureDataObject
+	}
+
+	logg.Info("Validating furniture gamedata...", zap.String("source", source))
+
+	report, err := checks.ValidateFurnitureData(data)
+	if err != nil {
+		return err
+	}
+
+	for _, issue := range report.Issues {
+		logg.Warn("Gamedata issue",
+			zap.String("section", issue.Section),
+			zap.Int("id", issue.ID),
+			zap.String("classname", issue.ClassName),
+			zap.String("problem", issue.Problem),
+		)
+	}
+
+	logg.Info("Gamedata validation completed",
+		zap.Int("total", report.TotalItems),
+		zap.Int("duplicate_ids", report.DuplicateIDs),
+		zap.Int("duplicate_classnames", report.DuplicateClassNames),
+		zap.Int("invalid_colors", report.InvalidColors),
+		zap.Int("missing_fields", report.MissingFields),
+	)
+
+	return writeCIReport(format, cireport.Gamedata(report))
+}
+
+// runFigureData validates FigureData.json from a local file or storage without a DB
+// connection. Clothing assets are only checked against storage.
+func runFigureData(ctx context.Context, file, format string) error {
+	cfg, err := config.LoadConfig(".")
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	logg, err := logger.New(&cfg.Log)
+	if err != nil {
+		return fmt.Errorf("failed to create logger: %w", err)
+	}
+	ctx, logg = startRun(ctx, logg)
+
+	var data []byte
+	var assets *checks.FigureAssets
+	source := file
+	if file != "" {
+		if data, err = os.ReadFile(file); err != nil {
+			return fmt.Errorf("failed to read figure data file: %w", err)
+		}
+	} else {
+		client, err := storage.NewClient(cfg.Storage)
+		if err != nil {
+			return fmt.Errorf("failed to create storage client: %w", err)
+		}
+		buckets := cfg.Storage.Buckets()
+		if data, err = checks.LoadFigureData(ctx, client, buckets.Gamedata); err != nil {
+			return err
+		}
+		if assets, err = checks.LoadFigureAssets(ctx, client, buckets.Gamedata, buckets.Assets); err != nil {
+			return err
+		}
+		if assets == nil {
+			logg.Warn("No figure map found, skipping the clothing asset check", zap.String("object", checks.FigureMapObject))
+		}
+		source = checks.FigureDataObject
+	}
+
+	logg.Info("Validating figure gamedata...", zap.String("source", source))
+
+	report, err := checks.ValidateFigureData(data, assets)
+	if err != nil {
+		return err
+	}
+
+	for _, issue := range report.Issues {
+		logg.Warn("Figure data issue",
+			zap.String("kind", issue.Kind),
+			zap.String("settype", issue.SetType),
+			zap.Int("set_id", issue.SetID),
+			zap.String("problem", issue.Problem),
+		)
+	}
+
+	logg.Info("Figure data validation completed",
+		zap.Int("palettes", report.Palettes),
+		zap.Int("settypes", report.SetTypes),
+		zap.Int("sets", report.Sets),
+		zap.Int("broken_palettes", report.BrokenPalettes),
+		zap.Int("duplicate_sets", report.DuplicateSets),
+		zap.Int("missing_assets", report.MissingAssets),
+		zap.Bool("assets_checked", report.AssetsChecked),
+	)
+
+	return writeCIReport(format, cireport.Figure(report))
+}
+
+// runTexts cross-checks FurnitureData.json against ProductData.json and
+// ExternalTexts.json in storage without a DB connection.
+func runTexts(ctx context.Context, format string) error {
+	cfg, err := config.LoadConfig(".")
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	logg, err := logger.New(&cfg.Log)
+	if err != nil {
+		return fmt.Errorf("failed to create logger: %w", err)
+	}
+	ctx, logg = startRun(ctx, logg)
+
+	client, err := storage.NewClient(cfg.Storage)
+	if err != nil {
+		return fmt.Errorf("failed to create storage client: %w", err)
+	}
+	bucket := cfg.Storage.Buckets().Gamedata
+	furniture, err := checks.LoadFurnitureData(ctx, client, bucket)
+	if err != nil {
+		return err
+	}
+	products, err := checks.LoadProductData(ctx, client, bucket)
+	if err != nil {
+		return err
+	}
+	texts, err := checks.LoadExternalTexts(ctx, client, bucket)
+	if err != nil {
+		return err
+	}
+
+	logg.Info("Cross-checking furniture product data and texts...")
+
+	report, err := checks.CrossCheckTexts(furniture, products, texts)
+	if err != nil {
+		return err
+	}
+
+	for _, issue := range report.Issues {
+		logg.Warn("Furniture text issue",
+			zap.String("kind", issue.Kind),
+			zap.String("section", issue.Section),
+			zap.Int("id", issue.ID),
+			zap.String("classname", issue.ClassName),
+			zap.String("problem", issue.Problem),
+		)
+	}
+
+	logg.Info("Furniture text cross-check completed",
+		zap.Int("total", report.TotalItems),
+		zap.Int("missing_products", report.MissingProducts),
+		zap.Int("missing_names", report.MissingNames),
+		zap.Int("missing_descriptions", report.MissingDescriptions),
+		zap.Int("blank_tooltips", len(report.BlankTooltips)),
+	)
+
+	return writeCIReport(format, cireport.Texts(report))
 }
 
 func runIntegrityChecks(ctx context.Context, onlyStructure, onlyBundle, onlyGameData, onlyServer bool) {
@@ -202,6 +677,7 @@ func runIntegrityChecks(ctx context.Context, onlyStructure, onlyBundle, onlyGame
 		fmt.Printf("Failed to create logger: %v\n", err)
 		os.Exit(1)
 	}
+	ctx, logg = startRun(ctx, logg)
 
 	// Create Storage Client
 	store, err := storage.NewClient(cfg.Storage)
@@ -214,11 +690,14 @@ func runIntegrityChecks(ctx context.Context, onlyStructure, onlyBundle, onlyGame
 	if conn, err := database.Connect(cfg.Database); err != nil {
 		logg.Warn("Optional database connection failed", zap.Error(err))
 	} else {
+		detectEmulator(cfg, conn, logg)
 		db = conn
 		logg = logg.With(zap.String("server", cfg.Server.Emulator))
+		openReplica(cfg, logg)
+		applyReconcileConfig(cfg)
 	}
 
-	svc := integrity.NewService(store, cfg.Storage.Bucket, logg, db, cfg.Server.Emulator)
+	svc := integrity.NewService(store, cfg.Storage.Buckets(), cfg.Storage.Layout, logg, db, cfg.Server.Emulator)
 	runStructure := !onlyGameData && !onlyServer && !onlyBundle
 	runGameData := onlyGameData || (!onlyStructure && !onlyBundle && !onlyServer)
 	runBundle := onlyBundle || (!onlyStructure && !onlyGameData && !onlyServer)
@@ -240,9 +719,15 @@ func runIntegrityChecks(ctx context.Context, onlyStructure, onlyBundle, onlyGame
 		} else {
 			logg.Warn("Missing folders detected", zap.Strings("missing", missingStructure))
 
-			if onlyStructure && fixFlag {
+			if onlyStructure && dryRunFlag {
+				placeholders, err := svc.PlanStructureFix(missingStructure, keepFlag)
+				if err != nil {
+					logg.Fatal("Cannot plan structure fix", zap.Error(err))
+				}
+				logPlaceholders(logg, placeholders)
+			} else if onlyStructure && fixFlag {
 				logg.Info("Fixing missing folders...")
-				if err := svc.FixStructure(ctx, missingStructure); err != nil {
+				if err := svc.FixStructure(ctx, missingStructure, keepFlag); err != nil {
 					logg.Fatal("Failed to fix structure", zap.Error(err))
 				}
 				logg.Info("Structure fixed successfully.")
@@ -254,15 +739,25 @@ func runIntegrityChecks(ctx context.Context, onlyStructure, onlyBundle, onlyGame
 
 	if runGameData {
 		logg.Info("Checking gamedata files...")
-		missingGameData, err := svc.CheckGameData(ctx)
+		gamedata, err := svc.CheckGameData(ctx)
 		if err != nil {
 			logg.Fatal("GameData check failed", zap.Error(err))
 		}
 
-		if len(missingGameData) == 0 {
+		if len(gamedata.Missing) == 0 {
 			logg.Info("GameData files are present.")
 		} else {
-			logg.Warn("Missing gamedata files detected", zap.Strings("missing", missingGameData))
+			logg.Warn("Missing gamedata files detected", zap.Strings("missing", gamedata.Missing))
+		}
+		for _, file := range gamedata.Files {
+			for _, warning := range file.Warnings {
+				logg.Warn("GameData file looks stale or truncated",
+					zap.String("file", file.Name),
+					zap.Int64("size", file.Size),
+					zap.Timep("last_modified", file.LastModified),
+					zap.String("warning", warning),
+				)
+			}
 		}
 	}
 
@@ -278,9 +773,15 @@ func runIntegrityChecks(ctx context.Context, onlyStructure, onlyBundle, onlyGame
 		} else {
 			logg.Warn("Missing bundled folders detected", zap.Strings("missing", missingBundled))
 
-			if onlyBundle && fixFlag {
+			if onlyBundle && dryRunFlag {
+				placeholders, err := svc.PlanBundledFix(missingBundled, keepFlag)
+				if err != nil {
+					logg.Fatal("Cannot plan bundled fix", zap.Error(err))
+				}
+				logPlaceholders(logg, placeholders)
+			} else if onlyBundle && fixFlag {
 				logg.Info("Fixing missing bundled folders...")
-				if err := svc.FixBundled(ctx, missingBundled); err != nil {
+				if err := svc.FixBundled(ctx, missingBundled, keepFlag); err != nil {
 					logg.Fatal("Failed to fix bundled folders", zap.Error(err))
 				}
 				logg.Info("Bundled folders fixed successfully.")
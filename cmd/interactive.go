@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"asset-manager/core/reconcile"
+
+	"go.uber.org/zap"
+)
+
+var (
+	// interactiveFurniture resolves planned actions item by item
+	interactiveFurniture bool
+
+	// planFile is where --interactive saves its decisions
+	planFile string
+)
+
+// decisionKeys maps the answer letters of the interactive prompt to decisions.
+// Upper case letters apply the decision to all similar items.
+var decisionKeys = map[string]reconcile.Decision{
+	"k": reconcile.DecisionKeep,
+	"d": reconcile.DecisionDelete,
+	"s": reconcile.DecisionSync,
+	"n": reconcile.DecisionSkip,
+}
+
+// resolvePlan lets the operator resolve the plan item by item, saving every decision
+// to --plan-file, and narrows the plan to the approved actions. It returns false when
+// the session was interrupted before every item was seen; the plan is then untouched.
+func resolvePlan(l *zap.Logger, plan *reconcile.ReconcilePlan) (bool, error) {
+	res, err := reconcile.LoadResolution(planFile)
+	if err != nil {
+		return false, err
+	}
+	if len(res.Items) > 0 {
+		l.Info("Resuming interactive resolution", zap.String("plan_file", planFile), zap.Int("decided", len(res.Items)))
+	}
+
+	complete, err := resolveItems(os.Stdin, os.Stdout, reconcile.PendingItems(plan), res, func() error {
+		return res.Save(planFile)
+	})
+	if err != nil || !complete {
+		return complete, err
+	}
+
+	plan.Actions = res.Approved(plan)
+	l.Info("Interactive resolution complete",
+		zap.String("plan_file", planFile),
+		zap.Int("decided", len(res.Items)),
+		zap.Int("approved_actions", len(plan.Actions)))
+	return true, nil
+}
+
+// resolveItems prompts on out for a decision on every item res has not decided yet,
+// reading answers from in, and calls save after each one. Decisions given for all
+// similar items are applied to later items of the same kind without asking. It
+// returns false when in ends or the operator quits.
+func resolveItems(in io.Reader, out io.Writer, items []reconcile.PendingItem, res *reconcile.Resolution, save func() error) (bool, error) {
+	reader := bufio.NewReader(in)
+	similar := make(map[string]reconcile.Decision)
+
+	for i, item := range items {
+		if res.Decided(item.Key) {
+			continue
+		}
+
+		decision, ok := similar[item.Kind]
+		if ok {
+			fmt.Fprintf(out, "[%d/%d] %s: %s (as similar items)\n", i+1, len(items), item.Key, decision)
+		} else {
+			var all, quit bool
+			decision, all, quit = promptDecision(reader, out, item, i, len(items))
+			if quit {
+				return false, nil
+			}
+			if all {
+				similar[item.Kind] = decision
+			}
+		}
+
+		res.Decide(item, decision)
+		if decision != reconcile.DecisionSkip {
+			if err := save(); err != nil {
+				return false, err
+			}
+		}
+	}
+	return true, nil
+}
+
+// promptDecision asks for the decision on one item until a valid answer is given.
+// It reports whether the answer applies to all similar items, and quit when the
+// operator quits or in ends.
+func promptDecision(reader *bufio.Reader, out io.Writer, item reconcile.PendingItem, i, total int) (decision reconcile.Decision, all, quit bool) {
+	fmt.Fprintf(out, "\n[%d/%d] %s\n", i+1, total, item.Key)
+	for _, action := range item.Actions {
+		fmt.Fprintf(out, "  %s: %s\n", action.Type, action.Reason)
+	}
+
+	choices := item.Choices()
+	options := make([]string, 0, len(choices)+1)
+	for _, choice := range choices {
+		for key, d := range decisionKeys {
+			if d == choice {
+				options = append(options, fmt.Sprintf("[%s] %s", key, choice))
+			}
+		}
+	}
+	options = append(options, "[q] quit")
+
+	for {
+		fmt.Fprintf(out, "%s (upper case applies to all similar): ", strings.Join(options, ", "))
+		line, err := reader.ReadString('\n')
+		answer := strings.TrimSpace(line)
+		if answer == "q" || answer == "Q" || (err != nil && answer == "") {
+			return "", false, true
+		}
+
+		decision, ok := decisionKeys[strings.ToLower(answer)]
+		if ok && allowed(choices, decision) {
+			return decision, answer != strings.ToLower(answer), false
+		}
+		fmt.Fprintf(out, "Unknown answer %q\n", answer)
+		if err != nil {
+			return "", false, true
+		}
+	}
+}
+
+// allowed reports whether decision is one of choices.
+func allowed(choices []reconcile.Decision, decision reconcile.Decision) bool {
+	for _, choice := range choices {
+		if choice == decision {
+			return true
+		}
+	}
+	return false
+}
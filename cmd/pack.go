@@ -0,0 +1,230 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"asset-manager/core/config"
+	"asset-manager/core/database"
+	"asset-manager/core/logger"
+	"asset-manager/core/storage"
+	"asset-manager/feature/pack"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+var (
+	// packIDs selects the items exported by pack build.
+	packIDs string
+	// packOut is the archive written by pack build.
+	packOut string
+)
+
+// packCmd groups the content pack commands
+var packCmd = &cobra.Command{
+	Use:   "pack",
+	Short: "Export and import portable furniture content packs",
+	Long: `Content packs bundle furniture items (.nitro files, FurnitureData.json entries and
+furniture table rows) into one .tar.gz archive that can be installed into another hotel.`,
+}
+
+// packBuildCmd exports furniture items into a pack
+var packBuildCmd = &cobra.Command{
+	Use:   "build",
+	Short: "Export furniture items into a content pack",
+	Long: `Exports the items with the given IDs (gamedata IDs, matched against the sprite_id
+of the furniture table) into a content pack. Missing assets and unknown IDs are
+logged as warnings and left out.
+
+Examples:
+  pack build --ids 1000-1050 --out pack.tar.gz
+  pack build --ids 1000-1050,1200 --out summer.tar.gz`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runPackBuild(cmd.Context())
+	},
+}
+
+// packInstallCmd imports a pack into this hotel
+var packInstallCmd = &cobra.Command{
+	Use:   "install <file>",
+	Short: "Install a content pack into this hotel",
+	Long: `Installs a content pack built by "pack build". The install stops before writing
+anything when a packed ID or classname already exists in gamedata, the database or
+storage, and lists every conflict. Packs built for another emulator are refused.
+
+Examples:
+  # List what would be installed and any conflicts
+  pack install pack.tar.gz --dry-run
+
+  # Install without a prompt
+  pack install pack.tar.gz --yes`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runPackInstall(cmd.Context(), args[0])
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(packCmd)
+	packCmd.AddCommand(packBuildCmd, packInstallCmd)
+
+	packBuildCmd.Flags().StringVar(&packIDs, "ids", "", "IDs to export, e.g. 1000-1050,1200")
+	packBuildCmd.Flags().StringVar(&packOut, "out", "pack.tar.gz", "Path of the pack to write")
+	packBuildCmd.MarkFlagRequired("ids")
+
+	packInstallCmd.Flags().BoolVar(&dryRunFlag, "dry-run", false, "Show what would be installed without changing anything")
+	packInstallCmd.Flags().BoolVar(&yesConfirm, "yes", false, "Auto-confirm the install (non-interactive)")
+}
+
+func runPackBuild(ctx context.Context) error {
+	ids, err := pack.ParseIDs(packIDs)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.LoadConfig(".")
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	logg, err := logger.New(&cfg.Log)
+	if err != nil {
+		return fmt.Errorf("failed to create logger: %w", err)
+	}
+
+	store, err := storage.NewClient(cfg.Storage)
+	if err != nil {
+		return fmt.Errorf("failed to create storage client: %w", err)
+	}
+
+	db, err := database.Connect(cfg.Database)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	detectEmulator(cfg, db, logg)
+	openReplica(cfg, logg)
+
+	// Write next to the target and rename, so a failed build never leaves a partial pack
+	tmp := packOut + ".tmp"
+	file, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", tmp, err)
+	}
+	manifest, err := pack.Build(ctx, store, cfg.Storage.Buckets(), db, cfg.Server.Emulator, ids, file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp, packOut)
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to build pack: %w", err)
+	}
+
+	for _, warning := range manifest.Warnings {
+		logg.Warn("Pack warning", zap.String("warning", warning))
+	}
+	logg.Info("Pack built",
+		zap.String("file", packOut),
+		zap.Int("items", len(manifest.Items)),
+		zap.Int("assets", len(manifest.Assets)),
+		zap.String("emulator", manifest.Emulator))
+	return nil
+}
+
+func runPackInstall(ctx context.Context, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open pack: %w", err)
+	}
+	p, err := pack.Read(file)
+	file.Close()
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.LoadConfig(".")
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	logg, err := logger.New(&cfg.Log)
+	if err != nil {
+		return fmt.Errorf("failed to create logger: %w", err)
+	}
+
+	store, err := storage.NewClient(cfg.Storage)
+	if err != nil {
+		return fmt.Errorf("failed to create storage client: %w", err)
+	}
+
+	db, err := database.Connect(cfg.Database)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	detectEmulator(cfg, db, logg)
+
+	if err := openScanner(cfg, logg); err != nil {
+		return err
+	}
+
+	report, err := pack.Install(ctx, store, cfg.Storage.Buckets(), db, cfg.Server.Emulator, p, true)
+	if err != nil {
+		return fmt.Errorf("failed to plan install: %w", err)
+	}
+	printInstallReport(logg, p, report)
+
+	if len(report.Conflicts) > 0 {
+		return pack.ErrConflicts
+	}
+	if dryRunFlag {
+		logg.Info("Dry-run mode: No changes were made.")
+		return nil
+	}
+	if !confirmDestructiveAction() {
+		logg.Warn("Operation cancelled by user. No changes were made.")
+		return nil
+	}
+
+	report, err = pack.Install(ctx, store, cfg.Storage.Buckets(), db, cfg.Server.Emulator, p, false)
+	if errors.Is(err, pack.ErrConflicts) {
+		printInstallReport(logg, p, report)
+	}
+	if report != nil {
+		for _, warning := range report.Warnings[len(p.Manifest.Warnings):] {
+			logg.Warn("Install warning", zap.String("warning", warning))
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to install pack: %w", err)
+	}
+
+	logg.Info("Pack installed", zap.String("file", path), zap.Int("items", len(p.Manifest.Items)))
+	return nil
+}
+
+// printInstallReport logs what a pack install writes and every conflict found.
+func printInstallReport(l *zap.Logger, p *pack.Pack, report *pack.InstallReport) {
+	for _, warning := range p.Manifest.Warnings {
+		l.Warn("Pack warning", zap.String("warning", warning))
+	}
+	for _, conflict := range report.Conflicts {
+		l.Error("Pack conflict",
+			zap.String("source", conflict.Source),
+			zap.String("item", conflict.Item),
+			zap.String("detail", conflict.Detail))
+	}
+
+	l.Info("Install plan",
+		zap.String("emulator", p.Manifest.Emulator),
+		zap.Time("built_at", p.Manifest.CreatedAt),
+		zap.Int("gamedata_entries", report.GamedataEntries),
+		zap.Int("db_rows", report.Rows),
+		zap.Int("assets", report.Assets),
+		zap.Int("conflicts", len(report.Conflicts)))
+}
@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"asset-manager/core/config"
+	"asset-manager/core/database"
+	"asset-manager/core/server"
+	"asset-manager/core/storage"
+
+	"github.com/spf13/cobra"
+)
+
+// healthzPath is the unauthenticated liveness endpoint served by start.
+const healthzPath = "/healthz"
+
+var (
+	// pingURL overrides the health endpoint called by ping.
+	pingURL string
+	// pingTimeout bounds the whole ping.
+	pingTimeout time.Duration
+	// pingDirect checks storage and the database instead of the HTTP server.
+	pingDirect bool
+)
+
+// pingCmd checks that this deployment is healthy
+var pingCmd = &cobra.Command{
+	Use:   "ping",
+	Short: "Check that the server (or its sources) is healthy, for container healthchecks",
+	Long: `Calls the local health endpoint (http://127.0.0.1:<SERVER_PORT>/healthz) and exits 0 when it
+answers 200, 1 otherwise. Meant for Docker HEALTHCHECK, so images need no curl.
+The default endpoint follows SERVER_HOST and uses https when SERVER_TLS_CERT is set; the
+certificate is not verified for it, since it names the public host rather than the local one.
+
+CLI-only deployments run no server: use --direct to check that every storage bucket
+exists and the database answers instead.
+
+Examples:
+  HEALTHCHECK --interval=30s --timeout=5s CMD ["asset-manager", "ping"]
+  asset-manager ping --direct`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := context.WithTimeout(cmd.Context(), pingTimeout)
+		defer cancel()
+
+		cfg, err := config.LoadConfig(".")
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		if pingDirect {
+			err = pingSources(ctx, cfg)
+		} else {
+			url, client := pingURL, http.DefaultClient
+			if url == "" {
+				url, client = localHealthURL(cfg.Server), localHealthClient(cfg.Server)
+			}
+			err = pingHTTP(ctx, client, url)
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), "ok")
+		return nil
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(pingCmd)
+
+	pingCmd.Flags().StringVar(&pingURL, "url", "", "Health endpoint to call (default http://127.0.0.1:<SERVER_PORT>/healthz)")
+	pingCmd.Flags().DurationVar(&pingTimeout, "timeout", 3*time.Second, "Give up after this long")
+	pingCmd.Flags().BoolVar(&pingDirect, "direct", false, "Check storage and the database directly instead of the HTTP server")
+}
+
+// localHealthURL returns the health endpoint of the server started with cfg on this host.
+func localHealthURL(cfg server.Config) string {
+	host := cfg.Host
+	if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+		host = "127.0.0.1"
+	}
+	scheme := "http"
+	if cfg.TLSEnabled() {
+		scheme = "https"
+	}
+	return scheme + "://" + net.JoinHostPort(host, cfg.Port) + healthzPath
+}
+
+// localHealthClient returns the client calling localHealthURL. The local TLS endpoint
+// is reached by address, so its certificate cannot be verified.
+func localHealthClient(cfg server.Config) *http.Client {
+	if !cfg.TLSEnabled() {
+		return http.DefaultClient
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	return &http.Client{Transport: transport}
+}
+
+// pingHTTP fails unless url answers 200 before ctx expires.
+func pingHTTP(ctx context.Context, client *http.Client, url string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("invalid health url: %w", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("health check failed: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("health check failed: %s answered %d", url, resp.StatusCode)
+	}
+	return nil
+}
+
+// pingSources checks that every configured bucket exists and the database answers.
+func pingSources(ctx context.Context, cfg *config.Config) error {
+	client, err := storage.NewClient(cfg.Storage)
+	if err != nil {
+		return fmt.Errorf("failed to create storage client: %w", err)
+	}
+	buckets := cfg.Storage.Buckets()
+	names := []string{buckets.Assets}
+	if buckets.Split() {
+		names = append(names, buckets.Gamedata)
+	}
+	for _, name := range names {
+		exists, err := client.BucketExists(ctx, name)
+		if err != nil {
+			return fmt.Errorf("storage check failed: %w", err)
+		}
+		if !exists {
+			return fmt.Errorf("storage check failed: bucket %q does not exist", name)
+		}
+	}
+
+	// Bound the connect and ping by the remaining time instead of the database timeout
+	dbCfg := cfg.Database
+	if deadline, ok := ctx.Deadline(); ok {
+		dbCfg.TimeoutSeconds = max(1, int(time.Until(deadline).Seconds()))
+	}
+	db, err := database.Connect(dbCfg)
+	if err != nil {
+		return fmt.Errorf("database check failed: %w", err)
+	}
+	if sqlDB, err := db.DB(); err == nil {
+		sqlDB.Close()
+	}
+	return nil
+}
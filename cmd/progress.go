@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"asset-manager/core/reconcile"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// progressBarWidth is the number of cells in a progress bar.
+	progressBarWidth = 30
+
+	// progressLogInterval is the minimum time between two progress log lines when
+	// stderr is not a terminal.
+	progressLogInterval = 10 * time.Second
+)
+
+// progressReporter renders the progress of long reconciles and applies: bars on
+// stderr when it is a terminal, periodic log lines otherwise (CI, cron, pipes).
+type progressReporter struct {
+	out      io.Writer
+	tty      bool
+	l        *zap.Logger
+	interval time.Duration
+
+	// stage is the stage of the last event, lastLog the time of the last log line
+	stage   reconcile.ProgressStage
+	lastLog time.Time
+	// open is set while a bar line waits for its newline
+	open bool
+}
+
+// withProgress returns ctx reporting reconcile progress to stderr or, when stderr is
+// not a terminal, to l.
+func withProgress(ctx context.Context, l *zap.Logger) context.Context {
+	p := &progressReporter{out: os.Stderr, tty: isTerminal(os.Stderr), l: l, interval: progressLogInterval}
+	return reconcile.WithProgress(ctx, p.report)
+}
+
+// isTerminal reports whether f is attached to a terminal.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// report handles one progress event.
+func (p *progressReporter) report(event reconcile.ProgressEvent) {
+	if p.tty {
+		p.draw(event)
+	} else {
+		p.log(event)
+	}
+	p.stage = event.Stage
+}
+
+// draw renders event as a bar line, rewritten in place until its stage completes.
+func (p *progressReporter) draw(event reconcile.ProgressEvent) {
+	switch event.Stage {
+	case reconcile.StageIndexes:
+		p.line("Loading indexes...", true)
+	case reconcile.StageListing:
+		p.line(fmt.Sprintf("Listing %s objects: %d", event.Source, event.Processed), false)
+	case reconcile.StageIndexed:
+		p.line(fmt.Sprintf("Indexed %s: %d items", event.Source, event.Processed), true)
+	case reconcile.StageResults:
+		p.line(progressBar("Comparing", event.Processed, event.Total), event.Processed >= event.Total)
+	case reconcile.StageApplying:
+		p.line(progressBar("Applying", event.Processed, event.Total), event.Processed >= event.Total)
+	case reconcile.StageSummary:
+		p.finish()
+	}
+}
+
+// line rewrites the open line with text, ending it when done.
+func (p *progressReporter) line(text string, done bool) {
+	fmt.Fprintf(p.out, "\r\033[K%s", text)
+	p.open = !done
+	if done {
+		fmt.Fprintln(p.out)
+	}
+}
+
+// finish ends a bar line left open, e.g. by a run that failed midway.
+func (p *progressReporter) finish() {
+	if p.open {
+		fmt.Fprintln(p.out)
+		p.open = false
+	}
+}
+
+// log writes event as a log line on stage changes, on completion and otherwise at
+// most once per interval.
+func (p *progressReporter) log(event reconcile.ProgressEvent) {
+	now := time.Now()
+	complete := event.Total > 0 && event.Processed >= event.Total
+	if event.Stage == p.stage && event.Stage != reconcile.StageIndexed && !complete && now.Sub(p.lastLog) < p.interval {
+		return
+	}
+	p.lastLog = now
+
+	fields := []zap.Field{zap.String("stage", string(event.Stage))}
+	if event.Source != "" {
+		fields = append(fields, zap.String("source", event.Source))
+	}
+	if event.Processed > 0 || event.Total > 0 {
+		fields = append(fields, zap.Int("processed", event.Processed))
+	}
+	if event.Total > 0 {
+		fields = append(fields, zap.Int("total", event.Total))
+	}
+	if event.Summary != nil {
+		fields = append(fields, zap.Int("total_items", event.Summary.TotalItems))
+	}
+	p.l.Info("Progress", fields...)
+}
+
+// progressBar renders label with a bar of processed out of total.
+func progressBar(label string, processed, total int) string {
+	if total <= 0 {
+		return fmt.Sprintf("%s %d", label, processed)
+	}
+	filled := min(processed*progressBarWidth/total, progressBarWidth)
+	return fmt.Sprintf("%-9s [%s%s] %d/%d (%d%%)", label,
+		strings.Repeat("#", filled), strings.Repeat("-", progressBarWidth-filled),
+		processed, total, min(processed*100/total, 100))
+}
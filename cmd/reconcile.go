@@ -11,11 +11,15 @@ import (
 	"asset-manager/core/database"
 	"asset-manager/core/logger"
 	"asset-manager/core/reconcile"
+	"asset-manager/core/snapshot"
 	"asset-manager/core/storage"
+	"asset-manager/feature/catalog"
+	furnitureIntegrity "asset-manager/feature/furniture/integrity"
 	furnitureReconcile "asset-manager/feature/furniture/reconcile"
 
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
 var (
@@ -24,6 +28,22 @@ var (
 	syncFurniture   bool
 	dryRunFurniture bool
 	yesConfirm      bool
+	purgePolicy     string
+	safeFix         bool
+	fixStorage      bool
+	syncDirection   string
+
+	// insertGamedata adds gamedata entries for items in the database and storage only
+	insertGamedata bool
+
+	// ignoreOnlineGate skips the online users check of mutating runs
+	ignoreOnlineGate bool
+
+	// fromSnapshot reconciles an exported snapshot instead of the live systems
+	fromSnapshot string
+
+	// logMutations logs the SQL and storage writes of applied plans
+	logMutations bool
 )
 
 // reconcileCmd is the parent command for all reconcile operations.
@@ -53,11 +73,39 @@ Examples:
   # Purge with auto-confirm (non-interactive)
   reconcile furniture --purge --yes
 
+  # Only delete storage files unknown to gamedata and the database
+  reconcile furniture --purge --purge-policy storage-orphans-only
+
+  # Only delete those untouched for RECONCILE_STALE_ORPHAN_AGE (default 7 days)
+  reconcile furniture --purge --purge-policy stale-storage-orphans-only
+
   # Sync mismatches with auto-confirm
   reconcile furniture --sync --yes
 
+  # Rewrite gamedata entries from the database instead
+  reconcile furniture --sync --sync-direction db-to-gamedata --yes
+
   # Both purge and sync
-  reconcile furniture --purge --sync --yes`,
+  reconcile furniture --purge --sync --yes
+
+  # Download files missing in storage from the upstream (see RECONCILE_UPSTREAM_*)
+  reconcile furniture --fix-storage --yes
+
+  # Add gamedata entries for custom furniture found in the database and storage only
+  reconcile furniture --insert-gamedata --yes
+
+  # Decide item by item which purges and syncs to apply (resumable)
+  reconcile furniture --purge --sync --interactive
+
+  # Save the plan for review, then apply it later
+  reconcile furniture --purge --sync --plan-out plan.json
+  reconcile furniture apply --plan-in plan.json --yes
+
+  # Apply only whitelisted syncs, capped, without a prompt (see SCHEDULER_SAFEFIX_*)
+  reconcile furniture --safe-fix
+
+  # Report on an exported snapshot without touching live systems
+  reconcile furniture --from-snapshot dump.tar.gz`,
 	RunE: runFurnitureReconcile,
 }
 
@@ -67,9 +115,20 @@ func init() {
 
 	// Add flags
 	furnitureReconcileCmd.Flags().BoolVar(&purgeFurniture, "purge", false, "Enable purge (delete items missing in any store)")
-	furnitureReconcileCmd.Flags().BoolVar(&syncFurniture, "sync", false, "Enable sync (update DB fields from gamedata)")
+	furnitureReconcileCmd.Flags().BoolVar(&syncFurniture, "sync", false, "Enable sync (update DB fields from gamedata, or the reverse with --sync-direction)")
 	furnitureReconcileCmd.Flags().BoolVar(&dryRunFurniture, "dry-run", false, "Force dry-run (no mutations even with --yes)")
 	furnitureReconcileCmd.Flags().BoolVar(&yesConfirm, "yes", false, "Auto-confirm destructive actions (non-interactive)")
+	furnitureReconcileCmd.Flags().StringVar(&syncDirection, "sync-direction", string(reconcile.SyncGamedataToDB), "Sync source of truth: gamedata-to-db, db-to-gamedata")
+	furnitureReconcileCmd.Flags().StringVar(&purgePolicy, "purge-policy", string(reconcile.PurgeStrict), "Purge scope: strict, storage-orphans-only, stale-storage-orphans-only, db-orphans-only, gamedata-ghosts-only")
+	furnitureReconcileCmd.Flags().BoolVar(&fixStorage, "fix-storage", false, "Download files missing in storage from the configured upstream")
+	furnitureReconcileCmd.Flags().BoolVar(&insertGamedata, "insert-gamedata", false, "Add gamedata entries from the DB rows of items present in the database and storage only")
+	furnitureReconcileCmd.Flags().BoolVar(&safeFix, "safe-fix", false, "Apply only whitelisted syncs up to the configured cap, without confirmation")
+	furnitureReconcileCmd.Flags().BoolVar(&ignoreOnlineGate, "ignore-online-gate", false, "Apply even while more users are online than RECONCILE_ONLINE_GATE_MAX_USERS")
+	furnitureReconcileCmd.Flags().BoolVar(&logMutations, "log-mutations", false, "Log every executed SQL statement and storage key at info level (also RECONCILE_LOG_MUTATIONS)")
+	furnitureReconcileCmd.Flags().BoolVar(&interactiveFurniture, "interactive", false, "Choose keep/delete/sync/skip per planned item before applying")
+	furnitureReconcileCmd.Flags().StringVar(&planFile, "plan-file", "reconcile-plan.json", "File --interactive saves its decisions to, and resumes from")
+	furnitureReconcileCmd.Flags().StringVar(&planOut, "plan-out", "", "Save the plan to this file for review instead of applying it (see reconcile furniture apply)")
+	furnitureReconcileCmd.Flags().StringVar(&fromSnapshot, "from-snapshot", "", "Report on an exported snapshot (.tar.gz of DB CSV, gamedata and storage listing) instead of live systems")
 
 	// Add reconcile to root
 	RootCmd.AddCommand(reconcileCmd)
@@ -78,25 +137,55 @@ func init() {
 func runFurnitureReconcile(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 
+	policy, err := reconcile.ParsePurgePolicy(purgePolicy)
+	if err != nil {
+		return err
+	}
+	direction, err := reconcile.ParseSyncDirection(syncDirection)
+	if err != nil {
+		return err
+	}
+	if fromSnapshot != "" && (purgeFurniture || syncFurniture || fixStorage || insertGamedata || safeFix) {
+		return fmt.Errorf("--from-snapshot only reports; it cannot be combined with --purge, --sync, --fix-storage, --insert-gamedata or --safe-fix")
+	}
+	if planOut != "" && (safeFix || fromSnapshot != "" || !(purgeFurniture || syncFurniture || fixStorage || insertGamedata)) {
+		return fmt.Errorf("--plan-out saves the actions of --purge, --sync, --fix-storage or --insert-gamedata; it cannot be combined with --safe-fix or --from-snapshot")
+	}
+	if interactiveFurniture && (safeFix || !(purgeFurniture || syncFurniture || fixStorage || insertGamedata)) {
+		return fmt.Errorf("--interactive resolves the actions of --purge, --sync, --fix-storage or --insert-gamedata; it cannot be combined with --safe-fix")
+	}
+
 	//Load configuration
 	cfg, err := config.LoadConfig(".")
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
+	// Saved plans are signed, so fail before planning rather than after
+	if planOut != "" && cfg.Server.ApiKey == "" {
+		return reconcile.ErrNoSigningKey
+	}
 
 	// Initialize logger
 	l, err := logger.New(&cfg.Log)
 	if err != nil {
 		return fmt.Errorf("failed to initialize logger: %w", err)
 	}
+	ctx, l = startRun(ctx, l)
 
 	l.Info("Starting furniture reconciliation")
+	ctx = withProgress(ctx, l)
+	ctx = withMutationLog(ctx, cfg, l)
+
+	if fromSnapshot != "" {
+		return runSnapshotReconcile(ctx, cmd, cfg, l)
+	}
 
 	// Connect to database
 	db, err := database.Connect(cfg.Database)
 	if err != nil {
 		return fmt.Errorf("failed to connect to database: %w", err)
 	}
+	detectEmulator(cfg, db, l)
 
 	// Connect to storage
 	client, err := storage.NewClient(cfg.Storage)
@@ -104,38 +193,67 @@ func runFurnitureReconcile(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to connect to storage: %w", err)
 	}
 
+	openReplica(cfg, l)
+	applyReconcileConfig(cfg)
+	openState(cfg, l)
+	if fixStorage {
+		if err := openUpstream(cfg, l); err != nil {
+			return err
+		}
+	}
+
+	// Unattended mode: the whitelist and cap replace the confirmation prompt
+	if safeFix {
+		if err := waitForOnlineGate(ctx, cfg, db, l); err != nil {
+			return err
+		}
+		result, err := furnitureIntegrity.SafeFixFurniture(ctx, client, cfg.Storage.Buckets(), db, cfg.Server.Emulator, cfg.Scheduler.SafeFix.Policy())
+		if result != nil {
+			logSafeFix(l, result)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to apply safe fixes: %w", err)
+		}
+		return nil
+	}
+
 	// Create furniture adapter
 	adapter := furnitureReconcile.NewAdapter()
 
-	// Set mutation context for purge/sync
-	if purgeFurniture || syncFurniture {
+	// Set mutation context for purge/sync/fix-storage/insert-gamedata
+	if purgeFurniture || syncFurniture || fixStorage || insertGamedata {
 		adapter.SetMutationContext(
 			db,
 			client,
-			cfg.Storage.Bucket,
-			"bundled/furniture",
+			cfg.Storage.Buckets(),
+			furnitureReconcile.AssetPrefix(),
 			cfg.Server.Emulator,
-			"gamedata/FurnitureData.json",
+			furnitureReconcile.GamedataKey(),
 		)
 	}
 
-	// Build spec
-	spec := &reconcile.Spec{
-		Adapter:            adapter,
-		CacheTTL:           0, // No caching to prevent stale data after DB changes
-		StoragePrefix:      "bundled/furniture",
-		StorageExtension:   ".nitro",
-		GamedataPaths:      []string{}, // Not used, loads full JSON
-		GamedataObjectName: "gamedata/FurnitureData.json",
-		ServerProfile:      cfg.Server.Emulator,
-	}
+	// Build spec (no caching to prevent stale data after DB changes)
+	spec := furnitureReconcile.NewSpec(adapter, cfg.Server.Emulator, cfg.Storage.Buckets().Gamedata, 0)
 
 	// Build reconcile options
 	opts := reconcile.ReconcileOptions{
-		DoPurge:   purgeFurniture,
-		DoSync:    syncFurniture,
-		DryRun:    dryRunFurniture,
-		Confirmed: false, // Will be set after confirmation prompt
+		DoPurge:          purgeFurniture,
+		PurgePolicy:      policy,
+		DoSync:           syncFurniture,
+		SyncDirection:    direction,
+		DoFixStorage:     fixStorage,
+		DoInsertGamedata: insertGamedata,
+		DryRun:           dryRunFurniture,
+		Confirmed:        false, // Will be set after confirmation prompt
+	}
+
+	// Preflight: fail before Prepare or planning if the run could not complete
+	report, err := reconcile.CheckPermissions(ctx, spec, db, client, cfg.Storage.Bucket, opts)
+	if err != nil {
+		if report != nil {
+			printPermissionReport(l, report)
+		}
+		return fmt.Errorf("permissions preflight failed: %w", err)
 	}
 
 	// Step 0: Prepare Schema (Auto-fix limits)
@@ -155,43 +273,231 @@ func runFurnitureReconcile(cmd *cobra.Command, args []string) error {
 	printReconcileReport(l, plan)
 
 	// Step 3: Check if actions are requested
-	if !purgeFurniture && !syncFurniture {
-		l.Info("No actions requested. Use --purge to delete incomplete items or --sync to repair mismatches.")
+	if !purgeFurniture && !syncFurniture && !fixStorage && !insertGamedata {
+		printFixRecipes(l, cmd, reconcile.FixRecipes(plan.Results))
+		l.Info("No actions requested. Use --purge to delete incomplete items, --sync to repair mismatches, --fix-storage to download missing files or --insert-gamedata to add missing gamedata entries.")
 		return nil
 	}
 
-	// Step 4: Apply (if confirmed)
-	if !dryRunFurniture {
-		numberActions := len(plan.Actions)
-		if numberActions == 0 {
-			l.Info("No actions required based on current flags.")
+	// Let the operator resolve each item; an interrupted session is resumed next run
+	if interactiveFurniture && len(plan.Actions) > 0 {
+		complete, err := resolvePlan(l, plan)
+		if err != nil {
+			return err
+		}
+		if !complete {
+			l.Info("Interactive resolution interrupted. No changes were made; rerun with --interactive to resume.", zap.String("plan_file", planFile))
 			return nil
 		}
+	}
 
-		// Check confirmation
-		confirmed := confirmDestructiveAction()
-		if !confirmed {
-			l.Warn("Operation cancelled by user. No changes were made.")
-			return nil
+	// Export the plan for review; it is applied later by reconcile furniture apply
+	if planOut != "" {
+		return exportPlan(l, cfg, adapter.Name(), opts, plan)
+	}
+
+	// Step 4: Apply (if confirmed)
+	if dryRunFurniture {
+		l.Info("Dry-run mode: No changes were made.")
+		return nil
+	}
+	applied, err := applyReconcilePlan(ctx, l, cfg, db, client, spec, plan, opts)
+	if err != nil {
+		return err
+	}
+
+	// The session is applied; the next one starts over
+	if applied && interactiveFurniture {
+		if err := os.Remove(planFile); err != nil && !os.IsNotExist(err) {
+			l.Warn("Failed to remove plan file", zap.String("plan_file", planFile), zap.Error(err))
 		}
+	}
+	return nil
+}
 
-		opts.Confirmed = true
+// applyReconcilePlan confirms plan, waits for the online gate and applies the plan
+// under the run lock, then verifies it. It reports false when there was nothing to
+// apply or the operator cancelled.
+func applyReconcilePlan(ctx context.Context, l *zap.Logger, cfg *config.Config, db *gorm.DB, client storage.Client, spec *reconcile.Spec, plan *reconcile.ReconcilePlan, opts reconcile.ReconcileOptions) (bool, error) {
+	if len(plan.Actions) == 0 {
+		l.Info("No actions required based on current flags.")
+		return false, nil
+	}
 
-		// Execute actions
-		l.Info("Applying actions...")
-		executed, err := reconcile.ApplyPlan(ctx, spec, db, client, cfg.Storage.Bucket, plan, opts)
-		if err != nil {
-			return fmt.Errorf("failed to apply plan: %w", err)
+	// Check confirmation
+	if !confirmDestructiveAction() {
+		l.Warn("Operation cancelled by user. No changes were made.")
+		return false, nil
+	}
+	opts.Confirmed = true
+
+	if err := waitForOnlineGate(ctx, cfg, db, l); err != nil {
+		return false, err
+	}
+
+	// Hold the run lock so a server instance cannot mutate the hotel concurrently
+	lock, err := reconcile.AcquireRunLock(ctx, client, cfg.Storage.Bucket, reconcile.DefaultLockTTL)
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire run lock: %w", err)
+	}
+	defer func() {
+		if err := lock.Release(); err != nil {
+			l.Warn("Failed to release run lock", zap.Error(err))
 		}
+	}()
 
-		l.Info("Successfully executed actions", zap.Int("count", executed))
-	} else {
-		l.Info("Dry-run mode: No changes were made.")
+	// Execute actions
+	l.Info("Applying actions...")
+	executed, err := reconcile.ApplyPlan(ctx, spec, db, client, cfg.Storage.Bucket, plan, opts)
+	if err != nil {
+		printDeleteFailures(l, plan.Failures)
+		return false, fmt.Errorf("failed to apply plan %s after %d actions: %w", plan.ID, executed, err)
+	}
+	printSkippedDownloads(l, plan.SkippedDownloads)
+
+	l.Info("Successfully executed actions",
+		zap.Int("count", executed),
+		zap.String("plan_id", plan.ID),
+		zap.Int("versions", len(plan.Versions)),
+		zap.String("backup", plan.Backup))
+
+	// Step 5: Verify (second pass over affected keys)
+	l.Info("Verifying applied actions...")
+	verification, err := reconcile.VerifyPlan(ctx, spec, db, client, cfg.Storage.Bucket, plan)
+	if err != nil {
+		return true, fmt.Errorf("failed to verify plan: %w", err)
 	}
+	printVerification(l, verification)
+	return true, nil
+}
 
+// runSnapshotReconcile reports on the snapshot set by --from-snapshot. Nothing live is
+// opened: no database, storage, replica or state store, so the run is not recorded.
+func runSnapshotReconcile(ctx context.Context, cmd *cobra.Command, cfg *config.Config, l *zap.Logger) error {
+	snap, err := snapshot.Open(fromSnapshot)
+	if err != nil {
+		return err
+	}
+	defer snap.Close()
+
+	applyReconcileConfig(cfg)
+	// Snapshots describe another point in time, not the live hotel
+	reconcile.SetStatusObject("")
+	emulator := cfg.Server.Emulator
+	if snap.Manifest.Emulator != "" {
+		emulator = snap.Manifest.Emulator
+	}
+	l.Info("Reconciling snapshot",
+		zap.String("snapshot", fromSnapshot),
+		zap.String("emulator", emulator),
+		zap.Strings("tables", snap.Tables))
+
+	// Gamedata comes from the snapshot even when a gamedata URL is configured
+	spec := furnitureReconcile.NewSpec(furnitureReconcile.NewAdapter(), emulator, "", 0)
+	spec.GamedataObjectName = furnitureReconcile.GamedataObject
+
+	plan, err := reconcile.ReconcileWithPlan(ctx, spec, snap.DB, snap.Client, "", reconcile.ReconcileOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to reconcile snapshot: %w", err)
+	}
+	printReconcileReport(l, plan)
+	printFixRecipes(l, cmd, reconcile.FixRecipes(plan.Results))
 	return nil
 }
 
+// waitForOnlineGate holds a mutating run back while more users are online than
+// RECONCILE_ONLINE_GATE_MAX_USERS allows, refusing or deferring it as configured.
+// --ignore-online-gate skips the check.
+func waitForOnlineGate(ctx context.Context, cfg *config.Config, db *gorm.DB, l *zap.Logger) error {
+	if ignoreOnlineGate {
+		return nil
+	}
+	gate := cfg.Reconcile.OnlineGate
+	count := func(ctx context.Context) (int64, error) {
+		return furnitureReconcile.CountOnlineUsers(ctx, db, cfg.Server.Emulator)
+	}
+	err := gate.Wait(ctx, count, func(online int64) {
+		l.Warn("Waiting for online users to drop",
+			zap.Int64("online", online),
+			zap.Int("max", gate.MaxUsers),
+			zap.Duration("retry_in", gate.PollInterval))
+	})
+	if err != nil {
+		return fmt.Errorf("online gate: %w", err)
+	}
+	return nil
+}
+
+// printPermissionReport logs every denied right found by the preflight.
+func printPermissionReport(l *zap.Logger, report *reconcile.PermissionReport) {
+	for _, c := range report.Missing() {
+		l.Error("Missing permission",
+			zap.String("store", c.Store),
+			zap.String("target", c.Target),
+			zap.String("permission", c.Permission),
+			zap.String("detail", c.Detail),
+		)
+	}
+}
+
+// printDeleteFailures logs every key a batch deletion could not remove.
+func printDeleteFailures(l *zap.Logger, failures []reconcile.DeleteFailure) {
+	for _, f := range failures {
+		l.Error("Delete failed",
+			zap.String("key", f.Key),
+			zap.String("action", string(f.Action)),
+			zap.String("object", f.Object),
+			zap.String("code", f.Code),
+			zap.Bool("retriable", f.Retriable),
+			zap.Int("attempts", f.Attempts),
+			zap.String("error", f.Message),
+		)
+	}
+}
+
+// printSkippedDownloads warns about every missing file the upstream could not serve.
+func printSkippedDownloads(l *zap.Logger, skipped []reconcile.SkippedDownload) {
+	for _, s := range skipped {
+		l.Warn("Download skipped", zap.String("key", s.Key), zap.String("reason", s.Reason))
+	}
+}
+
+// printDiagnostics warns about every key several entities of one source map to, about
+// mapped columns a table lacks and about names shared by several keys. Only one of the
+// conflicting entities is reconciled, fields of missing columns are not compared and
+// names resolve to one key only, so the report may be incomplete.
+func printDiagnostics(l *zap.Logger, diagnostics *reconcile.Diagnostics) {
+	if diagnostics == nil {
+		return
+	}
+	for _, c := range diagnostics.KeyConflicts {
+		l.Warn("Key conflict", zap.String("source", c.Source), zap.String("key", c.Key), zap.Strings("entities", c.Entities))
+	}
+	for _, gap := range diagnostics.MissingColumns {
+		l.Warn("Missing columns, their fields are not compared",
+			zap.String("source", gap.Source), zap.String("table", gap.Table), zap.Strings("columns", gap.Columns))
+	}
+	for _, n := range diagnostics.DuplicateNames {
+		l.Warn("Duplicate name", zap.String("source", n.Source), zap.String("name", n.Name), zap.Strings("keys", n.Keys))
+	}
+}
+
+// printFixRecipes logs, for each category of issues, the command that would address it.
+func printFixRecipes(l *zap.Logger, cmd *cobra.Command, recipes []reconcile.FixRecipe) {
+	for _, recipe := range recipes {
+		fields := []zap.Field{
+			zap.String("category", recipe.Category),
+			zap.Int("count", recipe.Count),
+			zap.String("run", cmd.CommandPath()+" "+strings.Join(recipe.Args, " ")),
+			zap.String("effect", recipe.Description),
+		}
+		if len(recipe.Fields) > 0 {
+			fields = append(fields, zap.Strings("fields", recipe.Fields))
+		}
+		l.Info("Fix recipe", fields...)
+	}
+}
+
 // printReconcileReport prints a formatted reconciliation report using logger.
 func printReconcileReport(l *zap.Logger, plan *reconcile.ReconcilePlan) {
 	s := plan.Summary
@@ -202,12 +508,36 @@ func printReconcileReport(l *zap.Logger, plan *reconcile.ReconcilePlan) {
 		zap.Int("missing_storage", s.MissingStorage),
 		zap.Int("missing_db", s.MissingDB),
 		zap.Int("mismatches", s.Mismatches),
+		zap.Int("flapping", s.Flapping),
+		zap.Int("pending", s.Pending),
+		zap.Int("ignored", s.Ignored),
+		zap.Int("key_conflicts", s.KeyConflicts),
+		zap.Int("duplicate_names", s.DuplicateNames),
+		zap.Int("misplaced", s.Misplaced),
+		zap.Int("recent_storage_orphans", s.RecentOrphans),
+		zap.Int("stale_storage_orphans", s.StaleOrphans),
 	)
+	printMemoryStats(l, s.Memory)
+	printDiagnostics(l, plan.Diagnostics)
+
+	for _, r := range plan.Ignored {
+		l.Info("Ignored item", zap.String("key", r.ID), zap.String("name", r.Name), zap.String("reason", r.Ignore.Reason))
+	}
+
+	// Flapping items point to another tool rewriting one of the sources
+	for _, r := range plan.Results {
+		if r.Flapping {
+			l.Warn("Flapping item", zap.String("key", r.ID), zap.String("name", r.Name), zap.Int("transitions", r.Transitions))
+		}
+	}
 
 	if len(plan.Actions) > 0 {
 		l.Info("Planned actions",
 			zap.Int("purge_actions", s.PurgeActions),
 			zap.Int("sync_actions", s.SyncActions),
+			zap.Int("move_actions", s.MoveActions),
+			zap.Int("download_actions", s.DownloadActions),
+			zap.Int("insert_actions", s.InsertActions),
 			zap.Int("total_actions", len(plan.Actions)),
 		)
 
@@ -232,6 +562,91 @@ func printReconcileReport(l *zap.Logger, plan *reconcile.ReconcilePlan) {
 	}
 }
 
+// printMemoryStats logs peak heap and index sizes of a run, for sizing deployments.
+func printMemoryStats(l *zap.Logger, m *reconcile.MemoryStats) {
+	if m == nil {
+		return
+	}
+	l.Info("Memory usage",
+		zap.Uint64("peak_heap_bytes", m.PeakHeapBytes),
+		zap.Uint64("sys_bytes", m.SysBytes),
+		zap.Int("db_index_entries", m.DBIndex.Entries),
+		zap.Int("gamedata_index_entries", m.GamedataIndex.Entries),
+		zap.Int("storage_set_entries", m.StorageSet.Entries),
+		zap.Int64("index_bytes", m.IndexBytes),
+	)
+}
+
+// printVerification logs the post-apply verification section.
+func printVerification(l *zap.Logger, v *reconcile.PlanVerification) {
+	l.Info("Verification",
+		zap.Int("checked", v.Checked),
+		zap.Int("consistent", len(v.Consistent)),
+		zap.Int("still_broken", len(v.StillBroken)),
+	)
+	for _, failure := range v.StillBroken {
+		l.Warn("Action did not take effect",
+			zap.String("key", failure.Key),
+			zap.String("type", string(failure.Action)),
+			zap.String("reason", failure.Reason),
+		)
+	}
+}
+
+// applyReconcileConfig applies the process-wide reconcile settings, such as name
+// normalization, the warning fields syncs repair, per-adapter settings, where gamedata
+// is read from and the .swf conversion log, before any spec is built or comparison runs.
+func applyReconcileConfig(cfg *config.Config) {
+	reconcile.SetNameNormalization(cfg.Reconcile.Names)
+	reconcile.SetSyncedWarnings(cfg.Reconcile.SyncWarnings)
+	reconcile.SetFieldPolicy(cfg.Reconcile.SyncFields)
+	reconcile.SetStaleOrphanAge(cfg.Reconcile.StaleOrphanAge)
+	reconcile.SetGracePeriod(cfg.Reconcile.GracePeriod)
+	reconcile.SetAdapterSettings(cfg.Reconcile.Adapters)
+	reconcile.SetStatusObject(cfg.Reconcile.StatusObject)
+	reconcile.SetGamedataURLCache(cfg.Reconcile.Gamedata.URLCacheTTL, cfg.Reconcile.Gamedata.URLTimeout)
+	furnitureReconcile.SetGamedataURL(cfg.Reconcile.Gamedata.FurnitureURL)
+	catalog.SetPriceBounds(cfg.Reconcile.CatalogPrices)
+	if cfg.Upload.Convert.Enabled() {
+		furnitureReconcile.SetConversionLog(cfg.Upload.Convert.SourcePrefix)
+	} else {
+		furnitureReconcile.SetConversionLog("")
+	}
+}
+
+// startRun gives ctx the run ID of the command and returns l tagged with it, so the
+// log lines, stored run, audit entries and reports of one execution can be correlated.
+func startRun(ctx context.Context, l *zap.Logger) (context.Context, *zap.Logger) {
+	ctx, runID := reconcile.StartRun(ctx)
+	return ctx, l.With(zap.String("run_id", runID))
+}
+
+// withMutationLog makes applied plans log their SQL statements and storage writes to
+// l when RECONCILE_LOG_MUTATIONS or --log-mutations asks for it.
+func withMutationLog(ctx context.Context, cfg *config.Config, l *zap.Logger) context.Context {
+	if !cfg.Reconcile.LogMutations && !logMutations {
+		return ctx
+	}
+	return logger.WithMutationLog(ctx, l)
+}
+
+// openUpstream registers the upstream configured by RECONCILE_UPSTREAM_URL or
+// RECONCILE_UPSTREAM_BUCKET for --fix-storage, and the scanner downloads pass.
+func openUpstream(cfg *config.Config, l *zap.Logger) error {
+	upstream, err := reconcile.NewUpstream(cfg.Reconcile.Upstream)
+	if err != nil {
+		return fmt.Errorf("failed to configure upstream: %w", err)
+	}
+	if upstream == nil {
+		return fmt.Errorf("--fix-storage needs RECONCILE_UPSTREAM_URL or RECONCILE_UPSTREAM_BUCKET")
+	}
+	reconcile.SetUpstream(upstream)
+	l.Info("Upstream downloads enabled",
+		zap.String("url", cfg.Reconcile.Upstream.URL),
+		zap.String("bucket", cfg.Reconcile.Upstream.Bucket))
+	return openScanner(cfg, l)
+}
+
 // confirmDestructiveAction prompts the user for confirmation or uses --yes flag.
 func confirmDestructiveAction() bool {
 	if yesConfirm {
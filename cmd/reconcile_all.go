@@ -0,0 +1,234 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"asset-manager/core/config"
+	"asset-manager/core/database"
+	"asset-manager/core/logger"
+	"asset-manager/core/reconcile"
+	"asset-manager/core/storage"
+	"asset-manager/feature/catalog"
+	furnitureReconcile "asset-manager/feature/furniture/reconcile"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// adapterConcurrency bounds how many adapters reconcile all runs at once
+var adapterConcurrency int
+
+// allReconcileCmd reconciles every registered adapter in one run.
+var allReconcileCmd = &cobra.Command{
+	Use:   "all",
+	Short: "Reconcile every registered adapter concurrently (report + optionally purge/sync)",
+	Long: `Reconcile every registered adapter concurrently and print one combined report.
+
+The purge, sync and repair flags apply to each adapter that implements them; adapters
+that cannot mutate are reported and skipped. One confirmation covers every adapter.
+An adapter that fails does not stop the others, but makes the command fail.
+
+Examples:
+  # Report only
+  reconcile all
+
+  # Purge storage orphans of every adapter
+  reconcile all --purge --purge-policy storage-orphans-only --yes
+
+  # Sync mismatches, two adapters at a time
+  reconcile all --sync --concurrency 2 --yes`,
+	Args: cobra.NoArgs,
+	RunE: runReconcileAll,
+}
+
+func init() {
+	reconcileCmd.AddCommand(allReconcileCmd)
+
+	allReconcileCmd.Flags().BoolVar(&purgeFurniture, "purge", false, "Enable purge (delete items missing in any store)")
+	allReconcileCmd.Flags().StringVar(&purgePolicy, "purge-policy", string(reconcile.PurgeStrict), "Purge scope: strict, storage-orphans-only, stale-storage-orphans-only, db-orphans-only, gamedata-ghosts-only")
+	allReconcileCmd.Flags().BoolVar(&syncFurniture, "sync", false, "Enable sync (update DB fields from gamedata, or the reverse with --sync-direction)")
+	allReconcileCmd.Flags().StringVar(&syncDirection, "sync-direction", string(reconcile.SyncGamedataToDB), "Sync source of truth: gamedata-to-db, db-to-gamedata")
+	allReconcileCmd.Flags().BoolVar(&fixStorage, "fix-storage", false, "Download files missing in storage from the configured upstream")
+	allReconcileCmd.Flags().BoolVar(&insertGamedata, "insert-gamedata", false, "Add gamedata entries from the DB rows of items present in the database and storage only")
+	allReconcileCmd.Flags().BoolVar(&dryRunFurniture, "dry-run", false, "Force dry-run (no mutations even with --yes)")
+	allReconcileCmd.Flags().BoolVar(&yesConfirm, "yes", false, "Auto-confirm destructive actions (non-interactive)")
+	allReconcileCmd.Flags().BoolVar(&ignoreOnlineGate, "ignore-online-gate", false, "Apply even while more users are online than RECONCILE_ONLINE_GATE_MAX_USERS")
+	allReconcileCmd.Flags().BoolVar(&logMutations, "log-mutations", false, "Log every executed SQL statement and storage key at info level (also RECONCILE_LOG_MUTATIONS)")
+	allReconcileCmd.Flags().IntVar(&adapterConcurrency, "concurrency", reconcile.DefaultAdapterConcurrency, "Number of adapters reconciled at once")
+}
+
+func runReconcileAll(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	policy, err := reconcile.ParsePurgePolicy(purgePolicy)
+	if err != nil {
+		return err
+	}
+	direction, err := reconcile.ParseSyncDirection(syncDirection)
+	if err != nil {
+		return err
+	}
+	if adapterConcurrency < 1 {
+		return fmt.Errorf("--concurrency must be at least 1")
+	}
+
+	cfg, err := config.LoadConfig(".")
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	l, err := logger.New(&cfg.Log)
+	if err != nil {
+		return fmt.Errorf("failed to initialize logger: %w", err)
+	}
+	ctx, l = startRun(ctx, l)
+	ctx = withMutationLog(ctx, cfg, l)
+
+	db, err := database.Connect(cfg.Database)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	detectEmulator(cfg, db, l)
+	client, err := storage.NewClient(cfg.Storage)
+	if err != nil {
+		return fmt.Errorf("failed to connect to storage: %w", err)
+	}
+
+	openReplica(cfg, l)
+	applyReconcileConfig(cfg)
+	openState(cfg, l)
+	if fixStorage {
+		if err := openUpstream(cfg, l); err != nil {
+			return err
+		}
+	}
+
+	mutating := purgeFurniture || syncFurniture || fixStorage || insertGamedata
+	registerReconcileSpecs(cfg, db, client, mutating)
+	specs := reconcile.RegisteredSpecs()
+
+	opts := reconcile.ReconcileOptions{
+		DoPurge:          purgeFurniture,
+		PurgePolicy:      policy,
+		DoSync:           syncFurniture,
+		SyncDirection:    direction,
+		DoFixStorage:     fixStorage,
+		DoInsertGamedata: insertGamedata,
+		DryRun:           dryRunFurniture,
+	}
+
+	l.Info("Planning reconciliation of every adapter...", zap.Int("adapters", len(specs)), zap.Int("concurrency", adapterConcurrency))
+	report := reconcile.PlanAll(ctx, specs, db, client, cfg.Storage.Bucket, opts, adapterConcurrency)
+	printMultiReport(l, report)
+
+	switch {
+	case !mutating:
+		l.Info("No actions requested. Use --purge to delete incomplete items, --sync to repair mismatches, --fix-storage to download missing files or --insert-gamedata to add missing gamedata entries.")
+	case dryRunFurniture:
+		l.Info("Dry-run mode: No changes were made.")
+	case report.Actions() == 0:
+		l.Info("No actions required based on current flags.")
+	default:
+		if !confirmDestructiveAction() {
+			l.Warn("Operation cancelled by user. No changes were made.")
+			return nil
+		}
+		opts.Confirmed = true
+
+		if err := waitForOnlineGate(ctx, cfg, db, l); err != nil {
+			return err
+		}
+
+		// Hold the run lock so a server instance cannot mutate the hotel concurrently
+		lock, err := reconcile.AcquireRunLock(ctx, client, cfg.Storage.Bucket, reconcile.DefaultLockTTL)
+		if err != nil {
+			return fmt.Errorf("failed to acquire run lock: %w", err)
+		}
+		defer func() {
+			if err := lock.Release(); err != nil {
+				l.Warn("Failed to release run lock", zap.Error(err))
+			}
+		}()
+
+		l.Info("Applying actions...")
+		executed := reconcile.ApplyAll(ctx, report, db, client, cfg.Storage.Bucket, opts, adapterConcurrency)
+		printMultiApply(l, report)
+		l.Info("Successfully executed actions", zap.Int("count", executed))
+	}
+
+	if report.Failed > 0 {
+		return fmt.Errorf("%d of %d adapters failed", report.Failed, len(report.Adapters))
+	}
+	return nil
+}
+
+// registerReconcileSpecs registers the spec of every adapter the CLI reconciles, without
+// caching. Mutating runs get adapters able to write to db and client; the catalog
+// adapter always writes to db.
+func registerReconcileSpecs(cfg *config.Config, db *gorm.DB, client storage.Client, mutating bool) {
+	furniture := furnitureReconcile.NewAdapter()
+	if mutating {
+		furniture.SetMutationContext(db, client, cfg.Storage.Buckets(), furnitureReconcile.AssetPrefix(), cfg.Server.Emulator, furnitureReconcile.GamedataKey())
+	}
+	reconcile.RegisterSpec(furnitureReconcile.NewSpec(furniture, cfg.Server.Emulator, cfg.Storage.Buckets().Gamedata, 0))
+	catalog.Register(db, cfg.Server.Emulator)
+}
+
+// printMultiReport prints the report of every adapter, then the combined counts.
+func printMultiReport(l *zap.Logger, report *reconcile.MultiReport) {
+	for _, run := range report.Adapters {
+		al := l.With(zap.String("adapter", run.Adapter))
+		if run.Error != "" {
+			al.Error("Adapter failed", zap.String("error", run.Error))
+			continue
+		}
+		printReconcileReport(al, run.Plan)
+	}
+
+	s := report.Summary
+	l.Info("Combined report",
+		zap.Int("adapters", len(report.Adapters)),
+		zap.Int("failed", report.Failed),
+		zap.Int("total_items", s.TotalItems),
+		zap.Int("missing_gamedata", s.MissingGamedata),
+		zap.Int("missing_storage", s.MissingStorage),
+		zap.Int("missing_db", s.MissingDB),
+		zap.Int("mismatches", s.Mismatches),
+		zap.Int("purge_actions", s.PurgeActions),
+		zap.Int("sync_actions", s.SyncActions),
+		zap.Int("move_actions", s.MoveActions),
+		zap.Int("download_actions", s.DownloadActions),
+		zap.Int("insert_actions", s.InsertActions),
+		zap.Int("total_actions", report.Actions()),
+	)
+}
+
+// printMultiApply prints what ApplyAll did for every adapter with planned actions.
+func printMultiApply(l *zap.Logger, report *reconcile.MultiReport) {
+	for _, run := range report.Adapters {
+		if run.Plan == nil || len(run.Plan.Actions) == 0 {
+			continue
+		}
+		al := l.With(zap.String("adapter", run.Adapter))
+		switch {
+		case run.Skipped != "":
+			al.Warn("Adapter skipped", zap.String("reason", run.Skipped))
+			continue
+		case run.Error != "":
+			printDeleteFailures(al, run.Plan.Failures)
+			al.Error("Adapter failed", zap.String("error", run.Error), zap.Int("executed", run.Executed))
+			continue
+		}
+		printSkippedDownloads(al, run.Plan.SkippedDownloads)
+		al.Info("Applied actions",
+			zap.Int("count", run.Executed),
+			zap.String("plan_id", run.Plan.ID),
+			zap.Int("versions", len(run.Plan.Versions)),
+			zap.String("backup", run.Plan.Backup))
+		if run.Plan.Verification != nil {
+			printVerification(al, run.Plan.Verification)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"asset-manager/core/config"
+	"asset-manager/core/database"
+	"asset-manager/core/logger"
+	"asset-manager/core/reconcile"
+	"asset-manager/core/storage"
+	furnitureReconcile "asset-manager/feature/furniture/reconcile"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var (
+	// planOut is where reconcile furniture and catalog save their plan instead of applying it
+	planOut string
+
+	// planIn is the saved plan the apply subcommands of reconcile furniture and catalog apply
+	planIn string
+)
+
+// furnitureApplyCmd applies a plan saved by reconcile furniture --plan-out.
+var furnitureApplyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Apply a furniture plan saved with --plan-out",
+	Long: `Apply a furniture plan saved by "reconcile furniture --plan-out", e.g. after review
+or in a maintenance window.
+
+The file's signature is checked with SERVER_API_KEY, so a file whose options, data
+hash or actions were edited after it was saved is refused. The plan is then rebuilt from live data with the saved options, and
+refused when the data changed since it was saved: run reconcile furniture --plan-out
+again and review the new plan. Only the saved actions are applied, under the saved
+plan ID.
+
+Examples:
+  reconcile furniture --purge --sync --plan-out plan.json
+  reconcile furniture apply --plan-in plan.json --yes`,
+	Args: cobra.NoArgs,
+	RunE: runFurnitureApply,
+}
+
+func init() {
+	furnitureReconcileCmd.AddCommand(furnitureApplyCmd)
+
+	furnitureApplyCmd.Flags().StringVar(&planIn, "plan-in", "", "Plan file saved with reconcile furniture --plan-out")
+	furnitureApplyCmd.Flags().BoolVar(&yesConfirm, "yes", false, "Auto-confirm destructive actions (non-interactive)")
+	furnitureApplyCmd.Flags().BoolVar(&ignoreOnlineGate, "ignore-online-gate", false, "Apply even while more users are online than RECONCILE_ONLINE_GATE_MAX_USERS")
+	furnitureApplyCmd.Flags().BoolVar(&logMutations, "log-mutations", false, "Log every executed SQL statement and storage key at info level (also RECONCILE_LOG_MUTATIONS)")
+	_ = furnitureApplyCmd.MarkFlagRequired("plan-in")
+}
+
+func runFurnitureApply(cmd *cobra.Command, args []string) error {
+	return runSavedPlan(furnitureReconcile.AdapterName, func(ctx context.Context, l *zap.Logger, cfg *config.Config, db *gorm.DB, client storage.Client, opts reconcile.ReconcileOptions) (*reconcile.Spec, error) {
+		if opts.DoFixStorage {
+			if err := openUpstream(cfg, l); err != nil {
+				return nil, err
+			}
+		}
+
+		adapter := furnitureReconcile.NewAdapter()
+		adapter.SetMutationContext(
+			db,
+			client,
+			cfg.Storage.Buckets(),
+			furnitureReconcile.AssetPrefix(),
+			cfg.Server.Emulator,
+			furnitureReconcile.GamedataKey(),
+		)
+		spec := furnitureReconcile.NewSpec(adapter, cfg.Server.Emulator, cfg.Storage.Buckets().Gamedata, 0)
+
+		report, err := reconcile.CheckPermissions(ctx, spec, db, client, cfg.Storage.Bucket, opts)
+		if err != nil {
+			if report != nil {
+				printPermissionReport(l, report)
+			}
+			return nil, fmt.Errorf("permissions preflight failed: %w", err)
+		}
+		if err := adapter.Prepare(ctx, db); err != nil {
+			return nil, fmt.Errorf("failed to prepare schema: %w", err)
+		}
+		return spec, nil
+	})
+}
+
+// savedPlanSpec builds the spec a saved plan is applied with, able to write to db
+// and client, after any adapter preflight.
+type savedPlanSpec func(ctx context.Context, l *zap.Logger, cfg *config.Config, db *gorm.DB, client storage.Client, opts reconcile.ReconcileOptions) (*reconcile.Spec, error)
+
+// runSavedPlan applies the plan file of --plan-in saved for adapter: it checks the
+// signature, replans with the saved options and applies the saved actions when the
+// live data still matches them.
+func runSavedPlan(adapter string, newSpec savedPlanSpec) error {
+	ctx := context.Background()
+
+	saved, err := reconcile.LoadPlanFile(planIn)
+	if err != nil {
+		return err
+	}
+	if saved.Adapter != adapter {
+		return fmt.Errorf("plan file %s was saved for adapter %s, not %s", planIn, saved.Adapter, adapter)
+	}
+	opts := saved.Options.ReconcileOptions()
+
+	cfg, err := config.LoadConfig(".")
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	// Refuse a plan whose ID or actions were edited after it was saved, before
+	// anything is connected to or prepared
+	if err := saved.CheckSignature(cfg.Server.ApiKey); err != nil {
+		return fmt.Errorf("plan file %s: %w", planIn, err)
+	}
+
+	l, err := logger.New(&cfg.Log)
+	if err != nil {
+		return fmt.Errorf("failed to initialize logger: %w", err)
+	}
+	ctx, l = startRun(ctx, l)
+	l.Info("Applying saved plan",
+		zap.String("adapter", adapter),
+		zap.String("plan_file", planIn),
+		zap.String("plan_id", saved.Plan.ID),
+		zap.Time("created_at", saved.CreatedAt),
+		zap.Int("actions", len(saved.Plan.Actions)))
+	ctx = withProgress(ctx, l)
+	ctx = withMutationLog(ctx, cfg, l)
+
+	db, err := database.Connect(cfg.Database)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	detectEmulator(cfg, db, l)
+	client, err := storage.NewClient(cfg.Storage)
+	if err != nil {
+		return fmt.Errorf("failed to connect to storage: %w", err)
+	}
+
+	openReplica(cfg, l)
+	applyReconcileConfig(cfg)
+	openState(cfg, l)
+
+	spec, err := newSpec(ctx, l, cfg, db, client, opts)
+	if err != nil {
+		return err
+	}
+
+	// Replan so actions carry live item data, then hold it to the saved plan
+	l.Info("Replanning to check the saved plan against current data...")
+	fresh, err := reconcile.ReconcileWithPlan(ctx, spec, db, client, cfg.Storage.Bucket, opts)
+	if err != nil {
+		return fmt.Errorf("failed to plan reconciliation: %w", err)
+	}
+	plan, err := saved.Resolve(fresh)
+	if err != nil {
+		return fmt.Errorf("plan file %s: %w", planIn, err)
+	}
+	printReconcileReport(l, plan)
+
+	_, err = applyReconcilePlan(ctx, l, cfg, db, client, spec, plan, opts)
+	return err
+}
+
+// exportPlan saves plan to --plan-out for a later reconcile <adapter> apply.
+func exportPlan(l *zap.Logger, cfg *config.Config, adapter string, opts reconcile.ReconcileOptions, plan *reconcile.ReconcilePlan) error {
+	f, err := reconcile.NewPlanFile(adapter, opts, plan, cfg.Server.ApiKey)
+	if err != nil {
+		return err
+	}
+	if err := reconcile.SavePlanFile(planOut, f); err != nil {
+		return err
+	}
+	l.Info("Plan saved. No changes were made; apply it with the apply subcommand and --plan-in.",
+		zap.String("command", "reconcile "+adapter+" apply"),
+		zap.String("plan_file", planOut),
+		zap.String("plan_id", f.Plan.ID),
+		zap.Int("actions", len(f.Plan.Actions)))
+	return nil
+}
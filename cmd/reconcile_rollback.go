@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"asset-manager/core/config"
+	"asset-manager/core/database"
+	"asset-manager/core/logger"
+	"asset-manager/core/reconcile"
+	"asset-manager/core/storage"
+	furnitureReconcile "asset-manager/feature/furniture/reconcile"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+// rollbackCmd restores the DB rows, gamedata entries and storage objects of an applied plan
+var rollbackCmd = &cobra.Command{
+	Use:   "rollback <plan-id>",
+	Short: "Restore the DB rows, gamedata entries and files an applied plan changed",
+	Long: `Restores the keys an applied reconcile plan affected from the backup taken before it
+was applied, under backups/<plan-id>/ in storage. The plan ID is logged when a plan is
+applied.
+
+Rows and gamedata entries of the keys are put back as they were, including deleted
+ones; rows and entries the plan created are removed. Files the plan deleted, moved or
+overwrote are copied back, and files it downloaded are removed. Changes made to the
+same keys after the plan are lost.
+
+Examples:
+  # Show what the backup holds
+  reconcile rollback 6f1c2a4e-... --dry-run
+
+  # Restore without a prompt
+  reconcile rollback 6f1c2a4e-... --yes`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runRollback(cmd.Context(), args[0])
+	},
+}
+
+func init() {
+	reconcileCmd.AddCommand(rollbackCmd)
+
+	rollbackCmd.Flags().BoolVar(&dryRunFlag, "dry-run", false, "Show what would be restored without changing anything")
+	rollbackCmd.Flags().BoolVar(&yesConfirm, "yes", false, "Auto-confirm the restore (non-interactive)")
+	rollbackCmd.Flags().BoolVar(&ignoreOnlineGate, "ignore-online-gate", false, "Restore even while more users are online than RECONCILE_ONLINE_GATE_MAX_USERS")
+}
+
+func runRollback(ctx context.Context, planID string) error {
+	cfg, err := config.LoadConfig(".")
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	l, err := logger.New(&cfg.Log)
+	if err != nil {
+		return fmt.Errorf("failed to initialize logger: %w", err)
+	}
+	ctx, l = startRun(ctx, l)
+	ctx = withMutationLog(ctx, cfg, l)
+
+	client, err := storage.NewClient(cfg.Storage)
+	if err != nil {
+		return fmt.Errorf("failed to connect to storage: %w", err)
+	}
+	backup, err := reconcile.LoadBackup(ctx, client, cfg.Storage.Bucket, planID)
+	if err != nil {
+		return err
+	}
+
+	adapter := furnitureReconcile.NewAdapter()
+	if backup.Adapter != adapter.Name() {
+		return fmt.Errorf("plan %s was applied by adapter %s, which cannot be rolled back from the CLI", planID, backup.Adapter)
+	}
+	printBackup(l, backup)
+	if dryRunFlag {
+		l.Info("Dry-run mode: No changes were made.")
+		return nil
+	}
+	if !confirmDestructiveAction() {
+		l.Warn("Operation cancelled by user. No changes were made.")
+		return nil
+	}
+
+	db, err := database.Connect(cfg.Database)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	detectEmulator(cfg, db, l)
+	applyReconcileConfig(cfg)
+	openState(cfg, l)
+
+	if err := waitForOnlineGate(ctx, cfg, db, l); err != nil {
+		return err
+	}
+
+	// Hold the run lock so a reconcile cannot mutate the same keys concurrently
+	lock, err := reconcile.AcquireRunLock(ctx, client, cfg.Storage.Bucket, reconcile.DefaultLockTTL)
+	if err != nil {
+		return fmt.Errorf("failed to acquire run lock: %w", err)
+	}
+	defer func() {
+		if err := lock.Release(); err != nil {
+			l.Warn("Failed to release run lock", zap.Error(err))
+		}
+	}()
+
+	adapter.SetMutationContext(
+		db,
+		client,
+		cfg.Storage.Buckets(),
+		furnitureReconcile.AssetPrefix(),
+		cfg.Server.Emulator,
+		furnitureReconcile.GamedataKey(),
+	)
+	spec := furnitureReconcile.NewSpec(adapter, cfg.Server.Emulator, cfg.Storage.Buckets().Gamedata, 0)
+	if err := reconcile.RollbackPlan(ctx, spec, client, backup); err != nil {
+		return fmt.Errorf("failed to roll back plan %s: %w", planID, err)
+	}
+
+	l.Info("Plan rolled back", zap.String("plan_id", planID), zap.Int("keys", len(backup.Keys)))
+	return nil
+}
+
+// printBackup logs what a plan backup holds.
+func printBackup(l *zap.Logger, backup *reconcile.Backup) {
+	var rows, entries, saved int
+	for _, r := range backup.Rows {
+		rows += len(r)
+	}
+	for _, e := range backup.Gamedata {
+		entries += len(e)
+	}
+	for _, obj := range backup.Objects {
+		if obj.Saved != "" {
+			saved++
+		}
+	}
+	l.Info("Plan backup",
+		zap.String("plan_id", backup.PlanID),
+		zap.String("adapter", backup.Adapter),
+		zap.Time("created_at", backup.CreatedAt),
+		zap.Strings("sources", backup.Sources),
+		zap.Int("keys", len(backup.Keys)),
+		zap.Int("db_rows", rows),
+		zap.Int("gamedata_entries", entries),
+		zap.Int("files_to_restore", saved),
+		zap.Int("files_to_remove", len(backup.Objects)-saved))
+}
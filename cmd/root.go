@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"os"
 
+	"asset-manager/core/config"
 	"asset-manager/core/logger"
+	furnitureAdp "asset-manager/feature/furniture/reconcile"
 
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
@@ -18,6 +20,28 @@ var RootCmd = &cobra.Command{
 It supports S3 storage engines and high-performance file serving.`,
 	SilenceUsage:  true,
 	SilenceErrors: true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return registerProfiles()
+	},
+}
+
+// registerProfiles makes the server profiles defined in config.yaml available to
+// every command before it runs, and checks the other config.yaml-only settings.
+func registerProfiles() error {
+	cfg, err := config.LoadConfig(".")
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if err := furnitureAdp.RegisterConfigProfiles(cfg.Profiles); err != nil {
+		return fmt.Errorf("failed to register server profiles: %w", err)
+	}
+	if err := cfg.Reconcile.SyncFields.Validate(); err != nil {
+		return fmt.Errorf("invalid reconcile.sync_fields: %w", err)
+	}
+	if err := cfg.Reconcile.Adapters.Validate(); err != nil {
+		return fmt.Errorf("invalid reconcile.adapters: %w", err)
+	}
+	return nil
 }
 
 func Execute() {
@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"asset-manager/core/config"
+	"asset-manager/core/reconcile"
+	"asset-manager/core/scheduler"
+	"asset-manager/core/storage"
+	"asset-manager/core/webhook"
+	furnitureIntegrity "asset-manager/feature/furniture/integrity"
+	furnitureAdp "asset-manager/feature/furniture/reconcile"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// startScheduler launches the background jobs enabled in the configuration.
+// It returns nil when no job is enabled.
+func startScheduler(cfg *config.Config, l *zap.Logger, db *gorm.DB, client storage.Client) *scheduler.Scheduler {
+	s := scheduler.New(l)
+	added := 0
+	if job, ok := safeFixJob(cfg, l, db, client); ok {
+		s.Add(job)
+		added++
+	}
+	if job, ok := integrityJob(cfg, l, db, client); ok {
+		s.Add(job)
+		added++
+	}
+	if added == 0 {
+		return nil
+	}
+	s.Start(context.Background())
+
+	return s
+}
+
+// safeFixJob returns the scheduled safe-fix job, or false when it is disabled, the
+// database is unavailable or its window is invalid.
+func safeFixJob(cfg *config.Config, l *zap.Logger, db *gorm.DB, client storage.Client) (scheduler.Job, bool) {
+	safeFix := cfg.Scheduler.SafeFix
+	if !safeFix.Enabled {
+		return scheduler.Job{}, false
+	}
+	if db == nil {
+		l.Warn("Scheduled safe-fix disabled: database unavailable")
+		return scheduler.Job{}, false
+	}
+
+	window, err := cfg.Scheduler.SafeFixWindow()
+	if err != nil {
+		l.Error("Scheduled safe-fix disabled: invalid maintenance window", zap.Error(err))
+		return scheduler.Job{}, false
+	}
+
+	return scheduler.Job{
+		Name:     reconcile.SafeFixTrigger,
+		Interval: safeFix.Interval,
+		Window:   window,
+		Precheck: onlineUsersPrecheck(db, cfg.Server.Emulator, safeFix.MaxOnlineUsers),
+		Run: func(ctx context.Context) error {
+			ctx, l := startRun(ctx, l)
+			result, err := furnitureIntegrity.SafeFixFurniture(withMutationLog(ctx, cfg, l), client, cfg.Storage.Buckets(), db, cfg.Server.Emulator, safeFix.Policy())
+			if result != nil {
+				logSafeFix(l, result)
+			}
+
+			// A manual run holding the lock is not a failure; the next tick retries
+			var locked *reconcile.LockedError
+			if errors.As(err, &locked) {
+				l.Warn("Safe-fix skipped", zap.Error(err))
+				return nil
+			}
+			return err
+		},
+	}, true
+}
+
+// integrityJob returns the scheduled full integrity check, or false when it has no
+// schedule or its schedule is invalid. Each run stores its report and posts the new
+// issues to the configured webhook.
+func integrityJob(cfg *config.Config, l *zap.Logger, db *gorm.DB, client storage.Client) (scheduler.Job, bool) {
+	schedule, err := cfg.Scheduler.IntegritySchedule()
+	if err != nil {
+		l.Error("Scheduled integrity check disabled: invalid schedule", zap.Error(err))
+		return scheduler.Job{}, false
+	}
+	if schedule == nil {
+		return scheduler.Job{}, false
+	}
+
+	settings := cfg.Scheduler.Integrity
+	if cfg.State.Path == "" && settings.WebhookURL != "" {
+		l.Warn("Scheduled integrity webhook disabled: new issues are found by comparing with the stored report, which requires STATE_PATH")
+	}
+
+	return scheduler.Job{
+		Name:     "integrity",
+		Schedule: schedule,
+		Run: func(ctx context.Context) error {
+			ctx, l := startRun(ctx, l)
+			run, err := furnitureIntegrity.RunScheduledCheck(ctx, client, cfg.Storage.Buckets(), db, cfg.Server.Emulator)
+			if err != nil {
+				return err
+			}
+			s := run.Report.Summary
+			l.Info("Scheduled integrity check",
+				zap.Int("total_items", s.TotalItems),
+				zap.Int("missing_gamedata", s.MissingGamedata),
+				zap.Int("missing_storage", s.MissingStorage),
+				zap.Int("missing_db", s.MissingDB),
+				zap.Int("mismatches", s.Mismatches),
+				zap.Bool("stored", run.Stored),
+				zap.Bool("baseline", run.Baseline),
+				zap.Int("new_issues", len(run.NewIssues)))
+
+			if len(run.NewIssues) == 0 || settings.WebhookURL == "" {
+				return nil
+			}
+			if err := webhook.Post(ctx, settings.WebhookURL, settings.WebhookSecret, run.Event()); err != nil {
+				return fmt.Errorf("failed to send new issues webhook: %w", err)
+			}
+			return nil
+		},
+	}, true
+}
+
+// onlineUsersPrecheck returns a precheck refusing runs while more than max users are
+// online in the emulator, or nil when max is zero.
+func onlineUsersPrecheck(db *gorm.DB, emulator string, max int) func(ctx context.Context) error {
+	if max <= 0 {
+		return nil
+	}
+	return func(ctx context.Context) error {
+		online, err := furnitureAdp.CountOnlineUsers(ctx, db, emulator)
+		if err != nil {
+			return err
+		}
+		if online > int64(max) {
+			return &reconcile.BusyError{Online: online, Max: max}
+		}
+		return nil
+	}
+}
+
+// logSafeFix logs the outcome of a safe-fix run and one audit line per attempted action.
+func logSafeFix(l *zap.Logger, result *reconcile.SafeFixResult) {
+	l.Info("Safe-fix run",
+		zap.Int("planned", result.Planned),
+		zap.Int("applied", len(result.Audit)),
+		zap.Int("skipped", result.Skipped),
+		zap.Int("capped", result.Capped),
+		zap.String("plan_id", result.PlanID),
+	)
+
+	audit := l.Named("audit")
+	for _, e := range result.Audit {
+		audit.Info("Action applied",
+			zap.String("adapter", e.Adapter),
+			zap.String("trigger", e.Trigger),
+			zap.String("action", string(e.Action)),
+			zap.String("key", e.Key),
+			zap.Strings("fields", e.Fields),
+			zap.String("outcome", e.Outcome),
+			zap.String("error", e.Error),
+		)
+	}
+}
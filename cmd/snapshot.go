@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"asset-manager/core/config"
+	"asset-manager/core/database"
+	"asset-manager/core/logger"
+	"asset-manager/core/snapshot"
+	"asset-manager/core/storage"
+	furnitureReconcile "asset-manager/feature/furniture/reconcile"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// snapshotOutput is the archive path written by the snapshot command
+var snapshotOutput string
+
+// snapshotCmd exports the furniture sources into an archive for offline reconciles
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Export the furniture table, gamedata and storage listing into one archive",
+	Long: `Exports what a furniture reconcile reads into a .tar.gz archive for bug reports and
+offline analysis with "reconcile furniture --from-snapshot":
+
+  - db/<table>.csv: the furniture table of the configured emulator
+  - gamedata/FurnitureData.json: the furniture gamedata, also when served over HTTP
+  - storage.txt: the keys of every object under the furniture prefix
+  - manifest.json: the emulator and export time
+
+The archive holds no other table, no file contents and no connection details,
+credentials, hostnames or bucket names, so it can be attached to a public issue.
+
+Examples:
+  # Write snapshot_<unix time>.tar.gz
+  snapshot
+
+  # Write to a chosen path
+  snapshot --output support.tar.gz`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runSnapshot(context.Background())
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(snapshotCmd)
+
+	snapshotCmd.Flags().StringVarP(&snapshotOutput, "output", "o", "", "Archive path (default snapshot_<unix time>.tar.gz)")
+}
+
+func runSnapshot(ctx context.Context) error {
+	cfg, err := config.LoadConfig(".")
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	logg, err := logger.New(&cfg.Log)
+	if err != nil {
+		return fmt.Errorf("failed to create logger: %w", err)
+	}
+
+	db, err := database.Connect(cfg.Database)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	detectEmulator(cfg, db, logg)
+	client, err := storage.NewClient(cfg.Storage)
+	if err != nil {
+		return fmt.Errorf("failed to connect to storage: %w", err)
+	}
+	applyReconcileConfig(cfg)
+
+	now := time.Now()
+	path := snapshotOutput
+	if path == "" {
+		path = fmt.Sprintf("snapshot_%d.tar.gz", now.Unix())
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot: %w", err)
+	}
+	defer file.Close()
+
+	if err := writeFurnitureSnapshot(ctx, snapshot.NewWriter(file), cfg, db, client, now, logg); err != nil {
+		os.Remove(path)
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+
+	logg.Info("Snapshot written", zap.String("path", path))
+	return nil
+}
+
+// writeFurnitureSnapshot writes the manifest, furniture table, gamedata and storage
+// listing, and finishes the archive.
+func writeFurnitureSnapshot(ctx context.Context, w *snapshot.Writer, cfg *config.Config, db *gorm.DB, client storage.Client, now time.Time, logg *zap.Logger) error {
+	emulator := cfg.Server.Emulator
+	if err := w.WriteManifest(snapshot.Manifest{Emulator: emulator, ExportedAt: &now}); err != nil {
+		return err
+	}
+
+	table := furnitureReconcile.NewAdapter().TableName(emulator)
+	rows, err := w.WriteTable(ctx, db, table)
+	if err != nil {
+		return err
+	}
+
+	spec := furnitureReconcile.NewSpec(furnitureReconcile.NewAdapter(), emulator, cfg.Storage.Buckets().Gamedata, 0)
+	gamedata, err := spec.ReadGamedata(ctx, client, cfg.Storage.Bucket)
+	if err != nil {
+		return err
+	}
+	// Offline reconciles read gamedata from the archive, wherever it was served from
+	if err := w.WriteFile(furnitureReconcile.GamedataObject, gamedata); err != nil {
+		return err
+	}
+
+	var keys []string
+	for obj := range client.ListObjects(ctx, cfg.Storage.Bucket, minio.ListObjectsOptions{Prefix: furnitureReconcile.StoragePrefix + "/", Recursive: true}) {
+		if obj.Err != nil {
+			return fmt.Errorf("failed to list storage: %w", obj.Err)
+		}
+		keys = append(keys, obj.Key)
+	}
+	if err := w.WriteListing(keys); err != nil {
+		return err
+	}
+
+	logg.Info("Snapshot exported",
+		zap.String("emulator", emulator),
+		zap.String("table", table),
+		zap.Int("rows", rows),
+		zap.Int("gamedata_bytes", len(gamedata)),
+		zap.Int("storage_objects", len(keys)))
+	return w.Close()
+}
@@ -1,23 +1,38 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"log"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 
 	"asset-manager/core/config"
+	"asset-manager/core/confirm"
 	"asset-manager/core/database"
+	"asset-manager/core/jobs"
+	"asset-manager/core/json"
 	"asset-manager/core/loader"
 	"asset-manager/core/logger"
 	"asset-manager/core/middleware/auth"
 	"asset-manager/core/middleware/rayid"
+	"asset-manager/core/reconcile"
+	"asset-manager/core/server"
 	"asset-manager/core/storage"
 
+	"asset-manager/feature/assets"
+	"asset-manager/feature/badges"
+	"asset-manager/feature/catalog"
 	"asset-manager/feature/furniture"
+	"asset-manager/feature/gamedata"
 	"asset-manager/feature/integrity"
+	jobsFeature "asset-manager/feature/jobs"
+	"asset-manager/feature/pack"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
 	"github.com/gofiber/swagger"
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
@@ -34,15 +49,27 @@ import (
 
 // startCmd represents the start command
 var startCmd = &cobra.Command{
-	Use:   "start",
-	Short: "Start the asset manager server",
-	Long:  `Starts the HTTP server and initializes all enabled features.`,
+	Use:     "start",
+	Aliases: []string{"serve"},
+	Short:   "Start the asset manager server",
+	Long: `Starts the HTTP server and initializes all enabled features.
+
+The listen flags override SERVER_HOST, SERVER_PORT, SERVER_TLS_CERT, SERVER_TLS_KEY and
+SERVER_HTTP2. With a certificate and key the server terminates TLS itself; HTTP/2 requires TLS.
+
+Examples:
+  serve --host 127.0.0.1 --port 8080
+  serve --port 443 --tls-cert /etc/ssl/assets.pem --tls-key /etc/ssl/assets.key --http2`,
 	Run: func(cmd *cobra.Command, args []string) {
 		// 1. Load Configuration
 		cfg, err := config.LoadConfig(".")
 		if err != nil {
 			log.Fatalf("Failed to load configuration: %v", err)
 		}
+		applyServeFlags(cmd, &cfg.Server)
+		if err := cfg.Server.Validate(); err != nil {
+			log.Fatalf("Invalid server configuration: %v", err)
+		}
 
 		// 2. Initialize Logger
 		logg, err := logger.New(&cfg.Log)
@@ -58,15 +85,27 @@ var startCmd = &cobra.Command{
 		if conn, err := database.Connect(cfg.Database); err != nil {
 			logg.Warn("Optional database connection failed", zap.Error(err))
 		} else {
+			detectEmulator(cfg, conn, logg)
 			db = conn
 			// If succeeded, inject "server" field into logger
 			logg = logg.With(zap.String("server", cfg.Server.Emulator))
 			logg.Info("Connected to emulator database")
+			openReplica(cfg, logg)
 		}
 
+		// 3.5 Open local state store (Optional, enables flapping detection)
+		openState(cfg, logg)
+
+		// Identify this process in run locks so the CLI can tell who holds them
+		reconcile.SetLockOwner("server")
+		applyReconcileConfig(cfg)
+
 		// 3. Initialize Fiber App
 		app := fiber.New(fiber.Config{
 			DisableStartupMessage: true, // We will log our own startup message
+			BodyLimit:             int(cfg.Upload.MaxBodySize),
+			JSONEncoder:           json.Marshal,
+			JSONDecoder:           json.Unmarshal,
 		})
 
 		// 3. Initialize Storage
@@ -75,12 +114,35 @@ var startCmd = &cobra.Command{
 			logg.Fatal("Failed to create storage client", zap.Error(err))
 		}
 
+		// 3.6 Malware scanning and .swf conversion of uploads and imports (Optional)
+		if err := openScanner(cfg, logg); err != nil {
+			logg.Fatal("Invalid upload scanner", zap.Error(err))
+		}
+		if err := openConverter(cfg, logg); err != nil {
+			logg.Fatal("Invalid swf converter", zap.Error(err))
+		}
+
+		// 3.7 Confirmation tokens for HTTP-triggered mutations (Optional)
+		if cfg.Server.MutationsRequireConfirmation {
+			confirm.SetStore(confirm.NewStore(cfg.Server.ConfirmationTTL))
+		}
+
+		// 3.8 Background jobs for long HTTP-triggered runs
+		jobManager := jobs.NewManager(cfg.Jobs)
+		jobs.SetManager(jobManager)
+
 		// 4. Initialize Feature Loader
 		mgr := loader.NewManager()
 
 		// Register Features
-		mgr.Register(integrity.NewFeature(store, cfg.Storage.Bucket, logg, db, cfg.Server.Emulator))
-		mgr.Register(furniture.NewFeature(store, cfg.Storage.Bucket, logg, db, cfg.Server.Emulator))
+		mgr.Register(integrity.NewFeature(store, cfg.Storage.Buckets(), cfg.Storage.Layout, logg, db, cfg.Server.Emulator))
+		mgr.Register(furniture.NewFeature(store, cfg.Storage.Buckets(), logg, db, cfg.Server.Emulator))
+		mgr.Register(badges.NewFeature(store, cfg.Storage.Buckets(), cfg.Storage.Layout, logg, db, cfg.Server.Emulator))
+		mgr.Register(catalog.NewFeature(store, cfg.Storage.Buckets(), db, cfg.Server.Emulator, logg))
+		mgr.Register(jobsFeature.NewFeature(logg))
+		mgr.Register(pack.NewFeature(store, cfg.Storage.Buckets(), logg, cfg.Upload))
+		mgr.Register(gamedata.NewFeature(store, cfg.Storage.Buckets(), cfg.Storage.History, logg))
+		mgr.Register(assets.NewPresignFeature(store, cfg.Storage.Buckets(), logg, assets.Options{Presign: cfg.Storage.Presign, Upload: cfg.Upload}))
 
 		// Middleware Registration
 		// 1. RayID (Must be first to trace everything)
@@ -107,6 +169,19 @@ var startCmd = &cobra.Command{
 		// 2.5 Swagger Documentation (Public)
 		app.Get("/swagger/*", swagger.HandlerDefault)
 
+		// 2.6 Liveness probe (Public, used by "asset-manager ping")
+		app.Get(healthzPath, func(c *fiber.Ctx) error {
+			return c.JSON(fiber.Map{"status": "ok"})
+		})
+
+		// 2.7 Read-through asset proxy (Public, optional)
+		public := loader.NewManager()
+		assetOpts := assets.Options{MaxAge: cfg.Server.AssetsMaxAge, Presign: cfg.Storage.Presign, Upload: cfg.Upload}
+		public.Register(assets.NewFeature(store, cfg.Storage.Buckets(), logg, cfg.Server.ServeAssets, assetOpts))
+		if err := public.LoadAll(app); err != nil {
+			logg.Fatal("Failed to load public features", zap.Error(err))
+		}
+
 		// 3. Auth (Protect API)
 		// We protect everything for now as requested ("protect every request")
 		app.Use(auth.New(auth.Config{ApiKey: cfg.Server.ApiKey}))
@@ -116,10 +191,18 @@ var startCmd = &cobra.Command{
 			logg.Fatal("Failed to load features", zap.Error(err))
 		}
 
+		// 6. Start background jobs (Optional, e.g. scheduled safe-fix)
+		scheduled := startScheduler(cfg, logg, db, store)
+
 		// 7. Start Server
+		srv := newHTTPServer(app, cfg.Server)
 		go func() {
-			logg.Info("Starting server", zap.String("port", cfg.Server.Port))
-			if err := app.Listen(":" + cfg.Server.Port); err != nil {
+			logg.Info("Starting server",
+				zap.String("addr", cfg.Server.Addr()),
+				zap.Bool("tls", cfg.Server.TLSEnabled()),
+				zap.Bool("http2", cfg.Server.HTTP2),
+				zap.String("json", json.Library))
+			if err := srv.listen(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 				logg.Fatal("Server failed to start", zap.Error(err))
 			}
 		}()
@@ -129,10 +212,78 @@ var startCmd = &cobra.Command{
 		signal.Notify(c, os.Interrupt, syscall.SIGTERM)
 		<-c
 		logg.Info("Shutting down server...")
-		_ = app.Shutdown()
+		if scheduled != nil {
+			scheduled.Stop()
+		}
+		_ = srv.shutdown()
+		jobManager.Stop()
 	},
 }
 
 func init() {
 	RootCmd.AddCommand(startCmd)
+
+	startCmd.Flags().String("host", "", "Address to listen on (default every interface)")
+	startCmd.Flags().String("port", "", "Port to listen on (default SERVER_PORT)")
+	startCmd.Flags().String("tls-cert", "", "PEM certificate file to serve HTTPS")
+	startCmd.Flags().String("tls-key", "", "PEM private key file of --tls-cert")
+	startCmd.Flags().Bool("http2", false, "Serve HTTP/2 over TLS")
+}
+
+// applyServeFlags overrides the server config with the listen flags set on cmd.
+func applyServeFlags(cmd *cobra.Command, cfg *server.Config) {
+	flags := cmd.Flags()
+	for name, target := range map[string]*string{
+		"host":     &cfg.Host,
+		"port":     &cfg.Port,
+		"tls-cert": &cfg.TLSCert,
+		"tls-key":  &cfg.TLSKey,
+	} {
+		if flags.Changed(name) {
+			*target, _ = flags.GetString(name)
+		}
+	}
+	if flags.Changed("http2") {
+		cfg.HTTP2, _ = flags.GetBool("http2")
+	}
+}
+
+// httpServer serves the Fiber app over HTTP/1.1 (fasthttp), optionally with TLS,
+// or over HTTP/2 through net/http, which fasthttp does not support.
+type httpServer struct {
+	app *fiber.App
+	cfg server.Config
+	// std is the net/http server used for HTTP/2; nil otherwise.
+	std *http.Server
+}
+
+// newHTTPServer prepares app to be served with cfg.
+func newHTTPServer(app *fiber.App, cfg server.Config) *httpServer {
+	srv := &httpServer{app: app, cfg: cfg}
+	if cfg.HTTP2 {
+		// net/http negotiates HTTP/2 over TLS by itself
+		srv.std = &http.Server{Addr: cfg.Addr(), Handler: adaptor.FiberApp(app)}
+	}
+	return srv
+}
+
+// listen serves until shutdown. It returns http.ErrServerClosed after a shutdown
+// of the HTTP/2 server.
+func (s *httpServer) listen() error {
+	switch {
+	case s.std != nil:
+		return s.std.ListenAndServeTLS(s.cfg.TLSCert, s.cfg.TLSKey)
+	case s.cfg.TLSEnabled():
+		return s.app.ListenTLS(s.cfg.Addr(), s.cfg.TLSCert, s.cfg.TLSKey)
+	default:
+		return s.app.Listen(s.cfg.Addr())
+	}
+}
+
+// shutdown stops the server gracefully.
+func (s *httpServer) shutdown() error {
+	if s.std != nil {
+		return s.std.Shutdown(context.Background())
+	}
+	return s.app.Shutdown()
 }
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"asset-manager/core/config"
+	"asset-manager/core/reconcile"
+	"asset-manager/core/state"
+	furnitureIntegrity "asset-manager/feature/furniture/integrity"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// openState opens the local state store and registers its history store for
+// flapping detection, its audit store for unattended fixes, its ignore list, issue
+// triage, the reports of scheduled integrity checks, the history of reconcile runs and
+// the outcome of applied plans.
+// State is optional: failures are logged and nil is returned.
+func openState(cfg *config.Config, l *zap.Logger) *gorm.DB {
+	if cfg.State.Path == "" {
+		return nil
+	}
+
+	db, err := state.Open(cfg.State)
+	if err != nil {
+		l.Warn("Optional state store unavailable", zap.Error(err))
+		return nil
+	}
+
+	history, err := state.NewHistoryStore(db)
+	if err != nil {
+		l.Warn("History tracking disabled", zap.Error(err))
+		return db
+	}
+	reconcile.SetHistoryStore(history, reconcile.DefaultFlapPolicy)
+
+	audit, err := state.NewAuditStore(db)
+	if err != nil {
+		l.Warn("Audit log disabled", zap.Error(err))
+		return db
+	}
+	reconcile.SetAuditStore(audit)
+
+	ignores, err := state.NewIgnoreStore(db)
+	if err != nil {
+		l.Warn("Ignore list disabled", zap.Error(err))
+		return db
+	}
+	reconcile.SetIgnoreStore(ignores)
+
+	triage, err := state.NewTriageStore(db)
+	if err != nil {
+		l.Warn("Triage disabled", zap.Error(err))
+		return db
+	}
+	reconcile.SetTriageStore(triage)
+
+	reports, err := state.NewReportStore(db, cfg.Scheduler.Integrity.Keep)
+	if err != nil {
+		l.Warn("Stored integrity reports disabled", zap.Error(err))
+		return db
+	}
+	furnitureIntegrity.SetReportStore(reports)
+
+	runs, err := state.NewRunStore(db, cfg.State.RunsKeep)
+	if err != nil {
+		l.Warn("Run history disabled", zap.Error(err))
+		return db
+	}
+	reconcile.SetRunStore(runs)
+
+	plans, err := state.NewPlanStatusStore(db, cfg.State.RunsKeep)
+	if err != nil {
+		l.Warn("Plan statuses disabled", zap.Error(err))
+		return db
+	}
+	reconcile.SetPlanStatusStore(plans)
+
+	return db
+}
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"asset-manager/core/config"
+	"asset-manager/core/logger"
+	"asset-manager/core/reconcile"
+	"asset-manager/core/state"
+	"asset-manager/core/storage"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+// undoCmd restores the storage objects an applied plan deleted or overwrote
+var undoCmd = &cobra.Command{
+	Use:   "undo <plan-id>",
+	Short: "Restore the storage objects an applied plan deleted or overwrote",
+	Long: `Restores the object versions recorded in the audit log for an applied reconcile plan,
+making each one the current version of its object again. The plan ID is logged when
+a plan is applied.
+
+Only buckets with S3 versioning enabled keep the replaced versions, and the audit log
+requires the state store (STATE_PATH). Database changes of the plan are not undone.
+
+Examples:
+  # List the versions that would be restored
+  undo 6f1c2a4e-... --dry-run
+
+  # Restore without a prompt
+  undo 6f1c2a4e-... --yes`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runUndo(cmd.Context(), args[0])
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(undoCmd)
+
+	undoCmd.Flags().BoolVar(&dryRunFlag, "dry-run", false, "List the versions that would be restored without changing anything")
+	undoCmd.Flags().BoolVar(&yesConfirm, "yes", false, "Auto-confirm the restore (non-interactive)")
+}
+
+func runUndo(ctx context.Context, planID string) error {
+	cfg, err := config.LoadConfig(".")
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	logg, err := logger.New(&cfg.Log)
+	if err != nil {
+		return fmt.Errorf("failed to create logger: %w", err)
+	}
+
+	if cfg.State.Path == "" {
+		return fmt.Errorf("undo requires the state store (STATE_PATH)")
+	}
+	db, err := state.Open(cfg.State)
+	if err != nil {
+		return err
+	}
+	audit, err := state.NewAuditStore(db)
+	if err != nil {
+		return err
+	}
+	entries, err := audit.PlanAudit(ctx, planID)
+	if err != nil {
+		return err
+	}
+	versions := reconcile.PlanVersions(entries, planID)
+	if len(versions) == 0 {
+		return fmt.Errorf("%w %s", reconcile.ErrNothingToUndo, planID)
+	}
+
+	for _, v := range versions {
+		logg.Info("Version to restore",
+			zap.String("bucket", v.Bucket),
+			zap.String("key", v.Key),
+			zap.String("version_id", v.VersionID))
+	}
+	if dryRunFlag {
+		logg.Info("Dry-run mode: No changes were made.", zap.Int("versions", len(versions)))
+		return nil
+	}
+	if !confirmDestructiveAction() {
+		logg.Warn("Operation cancelled by user. No changes were made.")
+		return nil
+	}
+
+	client, err := storage.NewClient(cfg.Storage)
+	if err != nil {
+		return fmt.Errorf("failed to create storage client: %w", err)
+	}
+
+	// Hold the run lock so a reconcile cannot mutate the same objects concurrently
+	lock, err := reconcile.AcquireRunLock(ctx, client, cfg.Storage.Bucket, reconcile.DefaultLockTTL)
+	if err != nil {
+		return fmt.Errorf("failed to acquire run lock: %w", err)
+	}
+	defer func() {
+		if err := lock.Release(); err != nil {
+			logg.Warn("Failed to release run lock", zap.Error(err))
+		}
+	}()
+
+	result, err := reconcile.UndoPlan(ctx, client, planID, versions)
+	if result != nil {
+		for _, f := range result.Failures {
+			logg.Error("Restore failed",
+				zap.String("bucket", f.Bucket),
+				zap.String("key", f.Key),
+				zap.String("version_id", f.VersionID),
+				zap.String("error", f.Message))
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to undo plan %s: %w", planID, err)
+	}
+
+	logg.Info("Plan undone", zap.String("plan_id", planID), zap.Int("restored", len(result.Restored)))
+	return nil
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"fmt"
+
+	"asset-manager/core/config"
+	"asset-manager/core/upload"
+
+	"go.uber.org/zap"
+)
+
+// openScanner registers the malware scanner configured by UPLOAD_SCAN_URL, if any.
+func openScanner(cfg *config.Config, l *zap.Logger) error {
+	scanner, err := upload.NewScanner(cfg.Upload.Scan)
+	if err != nil {
+		return fmt.Errorf("failed to configure upload scanning: %w", err)
+	}
+	upload.SetScanner(scanner, cfg.Upload.Scan.QuarantinePrefix)
+	if scanner != nil {
+		l.Info("Upload scanning enabled", zap.String("scanner", cfg.Upload.Scan.URL))
+	}
+	return nil
+}
+
+// openConverter registers the .swf converter configured by UPLOAD_CONVERT_URL or
+// UPLOAD_CONVERT_COMMAND, if any.
+func openConverter(cfg *config.Config, l *zap.Logger) error {
+	converter, err := upload.NewConverter(cfg.Upload.Convert)
+	if err != nil {
+		return fmt.Errorf("failed to configure swf conversion: %w", err)
+	}
+	upload.SetConverter(converter)
+	if converter != nil {
+		l.Info("Swf conversion enabled", zap.String("source_prefix", cfg.Upload.Convert.SourcePrefix))
+	}
+	return nil
+}
@@ -1,13 +1,21 @@
 package config
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
 
 	"asset-manager/core/database"
+	"asset-manager/core/jobs"
 	"asset-manager/core/logger"
+	"asset-manager/core/reconcile"
+	"asset-manager/core/scheduler"
 	"asset-manager/core/server"
+	"asset-manager/core/state"
 	"asset-manager/core/storage"
+	"asset-manager/core/upload"
 
 	"github.com/joho/godotenv"
 	"github.com/spf13/viper"
@@ -24,9 +32,26 @@ type Config struct {
 	Log logger.Config `mapstructure:"log"`
 	// Database holds configuration for the database connection.
 	Database database.Config `mapstructure:"database"`
+	// State holds configuration for the local persistent state store.
+	State state.Config `mapstructure:"state"`
+	// Scheduler holds configuration for background jobs such as safe-fix.
+	Scheduler scheduler.Config `mapstructure:"scheduler"`
+	// Jobs holds the limits of background jobs started over HTTP.
+	Jobs jobs.Config `mapstructure:"jobs"`
+	// Reconcile holds settings shared by every reconcile adapter, such as name normalization.
+	Reconcile reconcile.Config `mapstructure:"reconcile"`
+	// Upload holds the size, extension and content limits of uploaded assets.
+	Upload upload.Config `mapstructure:"upload"`
+	// Profiles defines custom emulator server profiles. It is read from config.yaml only.
+	Profiles map[string]ProfileConfig `mapstructure:"profiles"`
 }
 
-// LoadConfig loads configuration from environment variables and .env file.
+// FileName is the optional YAML configuration file read from the config path.
+// Environment variables override the values it sets.
+const FileName = "config.yaml"
+
+// LoadConfig loads configuration from environment variables, the .env file and the
+// optional config.yaml file.
 func LoadConfig(path string) (*Config, error) {
 	// 1. Load .env file if it exists
 	// We construct the path to .env
@@ -47,14 +72,37 @@ func LoadConfig(path string) (*Config, error) {
 	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 	v.AutomaticEnv()
 
+	// Settings that do not fit environment variables, such as profiles, come from config.yaml
+	filePath := filepath.Join(path, FileName)
+	if _, err := os.Stat(filePath); err == nil {
+		v.SetConfigFile(filePath)
+		if err := v.ReadInConfig(); err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", filePath, err)
+		}
+	}
+
 	var config Config
 	if err := v.Unmarshal(&config); err != nil {
 		return nil, err
 	}
+	config.Storage.History.Object = historyObject(config)
 
 	return &config, nil
 }
 
+// historyObject returns the object the gamedata history snapshots: the configured one,
+// or else the furniture gamedata reconcile reads and writes, so the file syncs and
+// purges rewrite is the one snapshotted.
+func historyObject(config Config) string {
+	if object := config.Storage.History.Object; object != "" {
+		return object
+	}
+	if object := config.Reconcile.Adapters["furniture"].GamedataObject; object != "" {
+		return object
+	}
+	return storage.DefaultHistoryObject
+}
+
 // bindValues uses reflection to iterate over the struct and set default values in Viper
 // based on the 'default' and 'mapstructure' tags.
 func bindValues(v *viper.Viper, iface any, prefix string) {
@@ -86,6 +134,11 @@ func bindValues(v *viper.Viper, iface any, prefix string) {
 			continue
 		}
 
+		// Maps have no environment form and are only read from config.yaml
+		if field.Type.Kind() == reflect.Map {
+			continue
+		}
+
 		defaultValue := field.Tag.Get("default")
 		// Always set default (even if empty) to register the key for AutomaticEnv
 		v.SetDefault(key, defaultValue)
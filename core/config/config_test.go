@@ -2,8 +2,12 @@ package config
 
 import (
 	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
+	"time"
+
+	"asset-manager/core/reconcile"
 
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
@@ -62,10 +66,81 @@ func TestLoadConfig(t *testing.T) {
 	
 	// Check defaults
 	assert.Equal(t, "8080", config.Server.Port)
+	assert.Equal(t, "", config.Server.Host)
+	assert.False(t, config.Server.TLSEnabled())
+	assert.False(t, config.Server.HTTP2)
+	assert.False(t, config.Server.MutationsRequireConfirmation)
+	assert.Equal(t, 5*time.Minute, config.Server.ConfirmationTTL)
+	assert.False(t, config.Server.ServeAssets)
+	assert.Equal(t, 24*time.Hour, config.Server.AssetsMaxAge)
 	assert.Equal(t, "minioadmin", config.Storage.AccessKey)
 	assert.Equal(t, "", config.Storage.Region)
+	assert.Equal(t, "auto", config.Storage.Addressing)
+	assert.False(t, config.Storage.RequesterPays)
+	assert.Equal(t, []string{"bundled", "c_images", "dcr", "gamedata", "images", "logos", "sounds"}, config.Storage.Layout.Folders)
+	assert.Contains(t, config.Storage.Layout.BundledFolders, "bundled/furniture")
+	assert.Equal(t, 720*time.Hour, config.Storage.Layout.GamedataMaxAge)
+	assert.Equal(t, int64(64), config.Storage.Layout.GamedataMinSize)
+	assert.Equal(t, "c_images/catalogue", config.Storage.Layout.CatalogImagesPrefix)
+	assert.Equal(t, "c_images/album1584", config.Storage.Layout.BadgesPrefix)
+	assert.False(t, config.Storage.ChangeLog.Enabled)
+	assert.Equal(t, "_changes/", config.Storage.ChangeLog.Prefix)
+	assert.True(t, config.Storage.History.Enabled)
+	assert.Equal(t, "gamedata/FurnitureData.json", config.Storage.History.Object)
+	assert.Equal(t, "gamedata/history/", config.Storage.History.Prefix)
+	assert.Equal(t, 50, config.Storage.History.Keep)
+	assert.Equal(t, time.Duration(0), config.Storage.History.MaxAge)
+	assert.Equal(t, "", config.Storage.Presign.Endpoint)
+	assert.Equal(t, 15*time.Minute, config.Storage.Presign.Expiry)
+	assert.Equal(t, 24*time.Hour, config.Storage.Presign.MaxExpiry)
+	assert.False(t, config.Storage.Presign.AllowPut)
+	assert.Equal(t, []string{"bundled/", "c_images/", "dcr/", "images/", "logos/", "sounds/"}, config.Storage.Presign.Prefixes)
+	assert.Equal(t, "", config.Storage.Presign.StagingPrefix)
 	assert.Equal(t, "info", config.Log.Level)
 	assert.Equal(t, "json", config.Log.Format)
+	assert.False(t, config.Scheduler.SafeFix.Enabled)
+	assert.Equal(t, 24*time.Hour, config.Scheduler.SafeFix.Interval)
+	assert.Equal(t, []string{"width", "length"}, config.Scheduler.SafeFix.SyncFields)
+	assert.Equal(t, "Local", config.Scheduler.Timezone)
+	assert.Equal(t, "", config.Scheduler.SafeFix.Window)
+	assert.Equal(t, 0, config.Scheduler.SafeFix.MaxOnlineUsers)
+	assert.Equal(t, "", config.Scheduler.Integrity.Schedule)
+	assert.Equal(t, 30, config.Scheduler.Integrity.Keep)
+	assert.Equal(t, 90, config.State.RunsKeep)
+	assert.Equal(t, "", config.Scheduler.Integrity.WebhookURL)
+	assert.Equal(t, "", config.Scheduler.Integrity.WebhookSecret)
+	assert.Equal(t, time.Hour, config.Jobs.Retention)
+	assert.Equal(t, 2, config.Jobs.MaxRunning)
+	assert.False(t, config.Reconcile.Names.Trim)
+	assert.Equal(t, int64(64<<20), config.Upload.MaxBodySize)
+	assert.Equal(t, int64(16<<20), config.Upload.MaxFileSize)
+	assert.Equal(t, int64(32<<20), config.Upload.MaxImportSize)
+	assert.Equal(t, []string{".nitro"}, config.Upload.Extensions)
+	assert.Equal(t, "", config.Upload.Scan.URL)
+	assert.Equal(t, 30*time.Second, config.Upload.Scan.Timeout)
+	assert.Equal(t, "quarantine/", config.Upload.Scan.QuarantinePrefix)
+	assert.Equal(t, "", config.Upload.Convert.Command)
+	assert.Equal(t, "", config.Upload.Convert.URL)
+	assert.Equal(t, 2*time.Minute, config.Upload.Convert.Timeout)
+	assert.Equal(t, "swf/", config.Upload.Convert.SourcePrefix)
+	assert.False(t, config.Reconcile.Names.CaseInsensitive)
+	assert.Empty(t, config.Reconcile.SyncWarnings)
+	assert.Empty(t, config.Reconcile.SyncFields)
+	assert.Empty(t, config.Reconcile.Adapters)
+	assert.Equal(t, 7*24*time.Hour, config.Reconcile.StaleOrphanAge)
+	assert.Zero(t, config.Reconcile.GracePeriod)
+	assert.Equal(t, 0, config.Reconcile.OnlineGate.MaxUsers)
+	assert.Equal(t, "refuse", config.Reconcile.OnlineGate.Mode)
+	assert.Equal(t, time.Minute, config.Reconcile.OnlineGate.PollInterval)
+	assert.Equal(t, 30*time.Minute, config.Reconcile.OnlineGate.MaxWait)
+	assert.Empty(t, config.Reconcile.Gamedata.FurnitureURL)
+	assert.Equal(t, 5*time.Minute, config.Reconcile.Gamedata.URLCacheTTL)
+	assert.Equal(t, 30*time.Second, config.Reconcile.Gamedata.URLTimeout)
+	assert.Empty(t, config.Reconcile.Upstream.URL)
+	assert.Empty(t, config.Reconcile.Upstream.Bucket)
+	assert.Equal(t, time.Minute, config.Reconcile.Upstream.Timeout)
+	assert.False(t, config.Reconcile.LogMutations)
+	assert.Equal(t, "gamedata/.asset-manager-status.json", config.Reconcile.StatusObject)
 }
 
 func TestEnvOverridesDefaults(t *testing.T) {
@@ -83,3 +158,56 @@ func TestEnvOverridesDefaults(t *testing.T) {
 	// Expect storage.endpoint to be overridden
 	assert.Equal(t, val, config.Storage.Endpoint, "Environment variable should override default value")
 }
+
+// TestLoadConfigProfiles checks that the profiles, sync_fields and adapters sections are
+// read from config.yaml.
+func TestLoadConfigProfiles(t *testing.T) {
+	dir := t.TempDir()
+	yaml := `server:
+  port: "9090"
+profiles:
+  myfork:
+    extends: arcturus
+    table: furni
+    bools: enum
+    decimal_strings: true
+    columns:
+      sprite_id: spriteid
+reconcile:
+  sync_fields:
+    name: db
+    dimensions: gamedata
+  adapters:
+    furniture:
+      cache_ttl: 10m
+      concurrency: 8
+      storage_prefix: assets/furni
+      gamedata_object: gamedata/custom/FurnitureData.json
+      ignore_fields: [description]
+`
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, FileName), []byte(yaml), 0o644))
+
+	config, err := LoadConfig(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "9090", config.Server.Port)
+
+	profile, ok := config.Profiles["myfork"]
+	assert.True(t, ok)
+	assert.Equal(t, "arcturus", profile.Extends)
+	assert.Equal(t, "furni", profile.Table)
+	assert.Equal(t, "enum", profile.Bools)
+	assert.Equal(t, map[string]string{"sprite_id": "spriteid"}, profile.Columns)
+	assert.Equal(t, reconcile.FieldPolicy{"name": "db", "dimensions": "gamedata"}, config.Reconcile.SyncFields)
+	assert.Equal(t, reconcile.AdapterSettings{
+		CacheTTL:      10 * time.Minute,
+		Concurrency:   8,
+		StoragePrefix:  "assets/furni",
+		GamedataObject: "gamedata/custom/FurnitureData.json",
+		IgnoreFields:   []string{"description"},
+	}, config.Reconcile.Adapters["furniture"])
+	// The gamedata history follows the furniture gamedata_object
+	assert.Equal(t, "gamedata/custom/FurnitureData.json", config.Storage.History.Object)
+	if assert.NotNil(t, profile.DecimalStrings) {
+		assert.True(t, *profile.DecimalStrings)
+	}
+}
@@ -10,6 +10,8 @@
 //   - Database: MySQL connection details
 //   - Storage: S3/MinIO credentials and bucket settings
 //   - Log: Logging level and format
+//   - Jobs: retention and concurrency of background jobs
+//   - Profiles: custom emulator server profiles, read from config.yaml only
 //
 // # Usage
 //
@@ -0,0 +1,35 @@
+package config
+
+// ProfileConfig defines a custom emulator server profile in the profiles section of
+// config.yaml. The map key is the emulator name selected with SERVER_EMULATOR.
+//
+//	profiles:
+//	  myfork:
+//	    extends: arcturus
+//	    columns:
+//	      sprite_id: spriteid
+//	      can_sit: sittable
+type ProfileConfig struct {
+	// Extends names a built-in or configured profile this one starts from; the
+	// settings below override it.
+	Extends string `mapstructure:"extends"`
+	// Table is the furniture table of the emulator.
+	Table string `mapstructure:"table"`
+	// Columns maps logical fields (id, sprite_id, item_name, public_name, width, length,
+	// stack_height, can_stack, can_sit, can_walk, can_lay, type, interaction_type,
+	// is_rare, description) to the emulator's column names.
+	Columns map[string]string `mapstructure:"columns"`
+	// Bools is the encoding of boolean flag columns: "tinyint" or "enum".
+	Bools string `mapstructure:"bools"`
+	// DecimalStrings marks stack_height as a varchar column that may use comma separators.
+	DecimalStrings *bool `mapstructure:"decimal_strings"`
+	// UsersTable and OnlineColumn locate the per-user online flag used to count online users.
+	UsersTable   string `mapstructure:"users_table"`
+	OnlineColumn string `mapstructure:"online_column"`
+	// OfferColumns maps the gamedata commerce fields (offer_id, buyout, rent_offer_id,
+	// rent_buyout) to catalog_items columns for the catalog offer sync.
+	OfferColumns map[string]string `mapstructure:"offer_columns"`
+	// Reserved adds items the emulator reserves without a storage bundle, by sprite ID
+	// or classname pattern, to those of the extended profile.
+	Reserved []string `mapstructure:"reserved"`
+}
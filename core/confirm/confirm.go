@@ -0,0 +1,190 @@
+package confirm
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"maps"
+	"sync"
+	"time"
+
+	"asset-manager/core/json"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrUnknownToken is returned for a token that was never issued or was already used.
+	ErrUnknownToken = errors.New("unknown or already used confirmation token")
+
+	// ErrExpired is returned for a token redeemed after its TTL.
+	ErrExpired = errors.New("confirmation token expired")
+
+	// ErrPlanChanged is returned when the plan differs from the one the token was issued for.
+	ErrPlanChanged = errors.New("plan changed since it was reviewed")
+)
+
+// Pending is an issued confirmation token.
+type Pending struct {
+	// Token must be echoed back to apply the plan.
+	Token string `json:"confirmation_token"`
+	// ExpiresAt is when the token stops being accepted.
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// pending is the stored state of an issued token.
+type pending struct {
+	scope     string
+	digest    string
+	expiresAt time.Time
+}
+
+// Store issues and redeems confirmation tokens.
+type Store struct {
+	mu     sync.Mutex
+	ttl    time.Duration
+	tokens map[string]pending
+	now    func() time.Time
+}
+
+// NewStore creates a store whose tokens are valid for ttl.
+func NewStore(ttl time.Duration) *Store {
+	return &Store{ttl: ttl, tokens: make(map[string]pending), now: time.Now}
+}
+
+// Issue returns a token confirming plan within scope.
+func (s *Store) Issue(scope string, plan any) (Pending, error) {
+	digest, err := planDigest(plan)
+	if err != nil {
+		return Pending{}, err
+	}
+
+	raw := make([]byte, 16)
+	if _, err := rand.Read(raw); err != nil {
+		return Pending{}, fmt.Errorf("failed to generate confirmation token: %w", err)
+	}
+	token := hex.EncodeToString(raw)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	s.prune(now)
+	expiresAt := now.Add(s.ttl)
+	s.tokens[token] = pending{scope: scope, digest: digest, expiresAt: expiresAt}
+	return Pending{Token: token, ExpiresAt: expiresAt}, nil
+}
+
+// Redeem consumes token, checking that it was issued for scope and plan and has not
+// expired. A token is consumed even when the plan changed, so every retry reviews again.
+func (s *Store) Redeem(token, scope string, plan any) error {
+	digest, err := planDigest(plan)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.tokens[token]
+	if !ok || p.scope != scope {
+		return ErrUnknownToken
+	}
+	delete(s.tokens, token)
+
+	if !s.now().Before(p.expiresAt) {
+		return ErrExpired
+	}
+	if p.digest != digest {
+		return ErrPlanChanged
+	}
+	return nil
+}
+
+// prune drops expired tokens. Callers hold mu.
+func (s *Store) prune(now time.Time) {
+	for token, p := range s.tokens {
+		if !now.Before(p.expiresAt) {
+			delete(s.tokens, token)
+		}
+	}
+}
+
+// planDigest returns the SHA-256 of the JSON encoding of plan.
+func planDigest(plan any) (string, error) {
+	data, err := json.Marshal(plan)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode plan: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// StatusCode returns the HTTP status for an error returned by Redeem.
+func StatusCode(err error) int {
+	switch {
+	case errors.Is(err, ErrExpired):
+		return fiber.StatusGone
+	case errors.Is(err, ErrPlanChanged):
+		return fiber.StatusConflict
+	case errors.Is(err, ErrUnknownToken):
+		return fiber.StatusForbidden
+	}
+	return fiber.StatusInternalServerError
+}
+
+// registry holds the process-wide store.
+type registry struct {
+	mu    sync.RWMutex
+	store *Store
+}
+
+// global is the singleton confirmation registry.
+var global = &registry{}
+
+// SetStore registers the store HTTP mutations confirm through.
+// Passing nil applies mutations without confirmation.
+func SetStore(store *Store) {
+	global.mu.Lock()
+	defer global.mu.Unlock()
+	global.store = store
+}
+
+// Required returns the registered store, or nil when mutations need no confirmation.
+func Required() *Store {
+	global.mu.RLock()
+	defer global.mu.RUnlock()
+	return global.store
+}
+
+// Await gates a mutation behind a confirmation token of store. Without a confirm query
+// parameter it answers with report, a "confirmation_required" status and a new token
+// for plan; with one it redeems the token against plan, computed again by the caller,
+// and answers with report and the refusal when the token does not hold. It returns
+// true when the mutation may proceed, and false once it has written the response.
+func Await(c *fiber.Ctx, l *zap.Logger, store *Store, scope string, plan any, report fiber.Map) (bool, error) {
+	token := c.Query("confirm")
+	if token == "" {
+		pending, err := store.Issue(scope, plan)
+		if err != nil {
+			return false, c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+		l.Info("Mutation awaiting confirmation", zap.String("scope", scope), zap.Time("expires_at", pending.ExpiresAt))
+		body := fiber.Map{
+			"status":             "confirmation_required",
+			"confirmation_token": pending.Token,
+			"expires_at":         pending.ExpiresAt,
+		}
+		maps.Copy(body, report)
+		return false, c.JSON(body)
+	}
+
+	if err := store.Redeem(token, scope, plan); err != nil {
+		l.Warn("Mutation confirmation refused", zap.String("scope", scope), zap.Error(err))
+		body := fiber.Map{"error": err.Error()}
+		maps.Copy(body, report)
+		return false, c.Status(StatusCode(err)).JSON(body)
+	}
+	return true, nil
+}
@@ -0,0 +1,53 @@
+package confirm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore_IssueAndRedeem(t *testing.T) {
+	store := NewStore(time.Minute)
+	plan := []string{"bundled/", "gamedata/"}
+
+	pending, err := store.Issue("integrity/structure", plan)
+	require.NoError(t, err)
+	assert.Len(t, pending.Token, 32)
+
+	assert.ErrorIs(t, store.Redeem(pending.Token, "integrity/bundled", plan), ErrUnknownToken)
+	assert.NoError(t, store.Redeem(pending.Token, "integrity/structure", plan))
+	assert.ErrorIs(t, store.Redeem(pending.Token, "integrity/structure", plan), ErrUnknownToken)
+}
+
+func TestStore_PlanChanged(t *testing.T) {
+	store := NewStore(time.Minute)
+
+	pending, err := store.Issue("integrity/structure", []string{"bundled/"})
+	require.NoError(t, err)
+	assert.ErrorIs(t, store.Redeem(pending.Token, "integrity/structure", []string{"bundled/", "gamedata/"}), ErrPlanChanged)
+
+	// The token is consumed, so the caller has to review again
+	assert.ErrorIs(t, store.Redeem(pending.Token, "integrity/structure", []string{"bundled/"}), ErrUnknownToken)
+}
+
+func TestStore_Expired(t *testing.T) {
+	store := NewStore(time.Minute)
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+
+	pending, err := store.Issue("integrity/structure", nil)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(time.Minute), pending.ExpiresAt)
+
+	now = now.Add(2 * time.Minute)
+	assert.ErrorIs(t, store.Redeem(pending.Token, "integrity/structure", nil), ErrExpired)
+}
+
+func TestStatusCode(t *testing.T) {
+	assert.Equal(t, fiber.StatusForbidden, StatusCode(ErrUnknownToken))
+	assert.Equal(t, fiber.StatusGone, StatusCode(ErrExpired))
+	assert.Equal(t, fiber.StatusConflict, StatusCode(ErrPlanChanged))
+}
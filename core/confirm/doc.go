@@ -0,0 +1,14 @@
+// Package confirm holds the confirmation tokens of HTTP-triggered mutations.
+//
+// With server.mutations_require_confirmation enabled, a mutating request first answers
+// with the plan it would apply and a one-time token. Repeating the request with the
+// token applies the plan, mirroring the CLI prompt: the caller reviews, then confirms.
+//
+// A token is bound to one scope (e.g. "integrity/structure") and to a digest of the
+// plan it was issued for. Redeeming fails when the token is unknown or already used,
+// has expired (server.confirmation_ttl), or the plan computed again at confirmation
+// differs from the reviewed one, in which case the caller must review again.
+//
+// Tokens live in process memory: with several server instances behind a load
+// balancer, the confirming request must reach the instance that issued the token.
+package confirm
@@ -14,4 +14,7 @@ type Config struct {
 	Name string `mapstructure:"name" default:"emulator"`
 	// TimeoutSeconds is the connection timeout in seconds.
 	TimeoutSeconds int `mapstructure:"timeout_seconds" default:"30"`
+	// ReadDSN is an optional MySQL DSN of a read replica used to build reconcile
+	// indices (e.g. "reader:pass@tcp(replica:3306)/emulator"). Empty reads from the primary.
+	ReadDSN string `mapstructure:"read_dsn" default:""`
 }
@@ -6,6 +6,7 @@ import (
 	"net/url"
 	"time"
 
+	mysqldriver "github.com/go-sql-driver/mysql"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
@@ -40,9 +41,48 @@ func Connect(cfg Config) (*gorm.DB, error) {
 	dsn := fmt.Sprintf("%s@tcp(%s:%d)/%s?charset=utf8mb4&parseTime=True&loc=Local&timeout=%ds&readTimeout=%ds&writeTimeout=%ds",
 		userInfo, cfg.Host, cfg.Port, cfg.Name, timeout, timeout, timeout)
 
-	// Suppress GORM logging for cleaner optional warnings in main logger
+	return open(dsn, timeout)
+}
+
+// ConnectReplica connects to the read replica configured in ReadDSN.
+// It returns nil without error when no replica is configured.
+// Connection timeouts are applied unless the DSN sets them, and times are always parsed.
+func ConnectReplica(cfg Config) (*gorm.DB, error) {
+	if cfg.ReadDSN == "" {
+		return nil, nil
+	}
+
+	dsnCfg, err := mysqldriver.ParseDSN(cfg.ReadDSN)
+	if err != nil {
+		return nil, fmt.Errorf("invalid read DSN: %w", err)
+	}
+
+	timeout := cfg.TimeoutSeconds
+	if timeout <= 0 {
+		timeout = 30
+	}
+	limit := time.Duration(timeout) * time.Second
+	if dsnCfg.Timeout == 0 {
+		dsnCfg.Timeout = limit
+	}
+	if dsnCfg.ReadTimeout == 0 {
+		dsnCfg.ReadTimeout = limit
+	}
+	dsnCfg.ParseTime = true
+
+	db, err := open(dsnCfg.FormatDSN(), timeout)
+	if err != nil {
+		return nil, fmt.Errorf("read replica: %w", err)
+	}
+	return db, nil
+}
+
+// open opens a MySQL connection pool for dsn and verifies it with a ping.
+func open(dsn string, timeout int) (*gorm.DB, error) {
+	// Suppress GORM logging for cleaner optional warnings in main logger; statements
+	// are only logged for contexts asking for a mutation log
 	gormConfig := &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Silent),
+		Logger: mutationLogger{Interface: logger.Default.LogMode(logger.Silent)},
 	}
 
 	db, err := gorm.Open(mysql.Open(dsn), gormConfig)
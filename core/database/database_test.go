@@ -74,3 +74,63 @@ func TestGetTableColumns(t *testing.T) {
 		assert.Nil(t, columns)
 	})
 }
+
+func TestParseGrant(t *testing.T) {
+	grant, ok := ParseGrant("GRANT SELECT, UPDATE (`public_name`), DELETE ON `emulator`.`items_base` TO `am`@`%`")
+	assert.True(t, ok)
+	assert.Equal(t, []string{"SELECT", "DELETE"}, grant.Privileges)
+	assert.Equal(t, "emulator", grant.Database)
+	assert.Equal(t, "items_base", grant.Table)
+
+	grant, ok = ParseGrant("GRANT ALL PRIVILEGES ON *.* TO `root`@`localhost` WITH GRANT OPTION")
+	assert.True(t, ok)
+	assert.Equal(t, []string{"ALL PRIVILEGES"}, grant.Privileges)
+	assert.Equal(t, "*", grant.Database)
+
+	_, ok = ParseGrant("GRANT `app_role`@`%` TO `am`@`%`")
+	assert.False(t, ok)
+}
+
+func TestHasPrivilege(t *testing.T) {
+	grants := []Grant{
+		{Privileges: []string{"USAGE"}, Database: "*", Table: "*"},
+		{Privileges: []string{"SELECT", "UPDATE"}, Database: "hotel\\_%", Table: "*"},
+		{Privileges: []string{"DELETE"}, Database: "hotel_main", Table: "items_base"},
+	}
+
+	assert.True(t, HasPrivilege(grants, "update", "hotel_main", "items_base"))
+	assert.True(t, HasPrivilege(grants, "DELETE", "hotel_main", "items_base"))
+	assert.False(t, HasPrivilege(grants, "DELETE", "hotel_main", "catalog_items"))
+	assert.False(t, HasPrivilege(grants, "DELETE", "hotel_main", ""))
+	assert.False(t, HasPrivilege(grants, "UPDATE", "hotelmain", "items_base"))
+	assert.False(t, HasPrivilege(grants, "ALTER", "hotel_main", "items_base"))
+	assert.True(t, HasPrivilege([]Grant{{Privileges: []string{"ALL"}, Database: "*", Table: "*"}}, "ALTER", "x", "y"))
+}
+
+func TestGetCurrentGrants(t *testing.T) {
+	db, sqlMock := setupMockDB(t)
+
+	rows := sqlmock.NewRows([]string{"Grants for am@%"}).
+		AddRow("GRANT USAGE ON *.* TO `am`@`%`").
+		AddRow("GRANT SELECT ON `emulator`.* TO `am`@`%`")
+	sqlMock.ExpectQuery("SHOW GRANTS FOR CURRENT_USER\\(\\)").WillReturnRows(rows)
+
+	grants, err := GetCurrentGrants(db)
+	assert.NoError(t, err)
+	assert.Len(t, grants, 2)
+	assert.True(t, HasPrivilege(grants, "SELECT", "emulator", "items_base"))
+}
+
+func TestConnectReplica(t *testing.T) {
+	t.Run("Not Configured", func(t *testing.T) {
+		db, err := ConnectReplica(Config{})
+		assert.NoError(t, err)
+		assert.Nil(t, db)
+	})
+
+	t.Run("Invalid DSN", func(t *testing.T) {
+		db, err := ConnectReplica(Config{ReadDSN: "not a dsn"})
+		assert.ErrorContains(t, err, "invalid read DSN")
+		assert.Nil(t, db)
+	})
+}
@@ -0,0 +1,68 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+
+	"asset-manager/core/server"
+
+	"gorm.io/gorm"
+)
+
+// emulatorSchemas lists the furniture tables DetectEmulator looks for, in order, with
+// the sit flag column telling their emulators apart: an enum flag selects
+// enumEmulator, any other type intEmulator.
+var emulatorSchemas = []struct {
+	table        string
+	flag         string
+	enumEmulator string
+	intEmulator  string
+}{
+	{"items_base", "allow_sit", server.EmulatorArcturusMS, server.EmulatorArcturus},
+	{"furniture", "can_sit", server.EmulatorComet, server.EmulatorPlus},
+}
+
+// DetectEmulator infers the emulator of the connected database from its furniture
+// table: items_base with an allow_sit column is Arcturus, furniture with a can_sit
+// column is PlusEMU or Comet. Boolean flags stored as enum('0','1') select
+// Arcturus Morningstar 4.x (arcturus-ms) and Comet; tinyint(1) ones the legacy
+// Arcturus schema and PlusEMU.
+func DetectEmulator(db *gorm.DB) (string, error) {
+	rows, err := db.Raw("SHOW TABLES").Rows()
+	if err != nil {
+		return "", fmt.Errorf("failed to list tables: %w", err)
+	}
+	defer rows.Close()
+
+	tables := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return "", fmt.Errorf("failed to scan table name: %w", err)
+		}
+		tables[strings.ToLower(name)] = true
+	}
+	if err := rows.Err(); err != nil {
+		return "", fmt.Errorf("failed to list tables: %w", err)
+	}
+
+	for _, schema := range emulatorSchemas {
+		if !tables[schema.table] {
+			continue
+		}
+		columns, err := GetTableColumns(db, schema.table)
+		if err != nil {
+			return "", err
+		}
+		for _, col := range columns {
+			if col.Field != schema.flag {
+				continue
+			}
+			if strings.HasPrefix(col.Type, "enum") {
+				return schema.enumEmulator, nil
+			}
+			return schema.intEmulator, nil
+		}
+	}
+	return "", fmt.Errorf("no items_base.allow_sit or furniture.can_sit column found")
+}
@@ -0,0 +1,61 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDetectEmulator tests that the furniture table and its sit flag type select the emulator.
+func TestDetectEmulator(t *testing.T) {
+	tests := []struct {
+		name     string
+		table    string
+		flag     string
+		flagType string
+		want     string
+	}{
+		{"arcturus", "items_base", "allow_sit", "tinyint(1)", "arcturus"},
+		{"arcturus-ms", "items_base", "allow_sit", "enum('0','1')", "arcturus-ms"},
+		{"plusemu", "furniture", "can_sit", "tinyint(1)", "plusemu"},
+		{"comet", "furniture", "can_sit", "ENUM('0','1')", "comet"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := setupMockDB(t)
+			mock.ExpectQuery("SHOW TABLES").WillReturnRows(sqlmock.NewRows([]string{"Tables_in_emulator"}).
+				AddRow("users").AddRow(tt.table))
+			mock.ExpectQuery("SHOW COLUMNS FROM `" + tt.table + "`").WillReturnRows(
+				sqlmock.NewRows([]string{"Field", "Type", "Null", "Key", "Default", "Extra"}).
+					AddRow("id", "int(11)", "NO", "PRI", nil, "auto_increment").
+					AddRow(tt.flag, tt.flagType, "NO", "", "0", ""))
+
+			emulator, err := DetectEmulator(db)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, emulator)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// TestDetectEmulator_Unknown tests that a database without a known furniture table is
+// reported, as is a furniture table without the sit flag.
+func TestDetectEmulator_Unknown(t *testing.T) {
+	db, mock := setupMockDB(t)
+	mock.ExpectQuery("SHOW TABLES").WillReturnRows(sqlmock.NewRows([]string{"Tables_in_emulator"}).AddRow("users"))
+	_, err := DetectEmulator(db)
+	assert.ErrorContains(t, err, "no items_base.allow_sit or furniture.can_sit column found")
+
+	mock.ExpectQuery("SHOW TABLES").WillReturnRows(sqlmock.NewRows([]string{"Tables_in_emulator"}).AddRow("furniture"))
+	mock.ExpectQuery("SHOW COLUMNS FROM `furniture`").WillReturnRows(
+		sqlmock.NewRows([]string{"Field", "Type", "Null", "Key", "Default", "Extra"}).AddRow("id", "int(11)", "NO", "PRI", nil, ""))
+	_, err = DetectEmulator(db)
+	assert.Error(t, err)
+
+	mock.ExpectQuery("SHOW TABLES").WillReturnError(assert.AnError)
+	_, err = DetectEmulator(db)
+	assert.ErrorContains(t, err, "failed to list tables")
+}
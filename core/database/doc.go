@@ -9,12 +9,28 @@
 // to the specific emulator schema (Arcturus, Comet, Plus) regarding connection establishment,
 // but the Schema Inspector relies on knowing the expected schema.
 //
+// ConnectReplica opens the optional read replica configured in ReadDSN. Reconcile
+// builds its indices from it while writes stay on the primary.
+//
 // # Schema Inspection
 //
 // The package includes tools to inspect the database schema, which is crucial for
 // the Server Integrity Check. It allows retrieving table columns and verifying matches
 // against expected models defined in feature packages.
 //
+// # Emulator Detection
+//
+// DetectEmulator infers the emulator from SHOW TABLES and the sit flag column of the
+// furniture table: items_base.allow_sit for Arcturus, furniture.can_sit for PlusEMU
+// and Comet, each told apart by tinyint or enum flags. Commands use it when the
+// configured emulator is "auto", and to pick the Arcturus schema variant.
+//
+// # Grants
+//
+// GetCurrentGrants parses SHOW GRANTS for the connected user, and HasPrivilege answers
+// whether a privilege covers a given table. Reconcile uses them to fail mutating runs
+// before they start when the user lacks DELETE, UPDATE or ALTER.
+//
 // # Usage
 //
 //	db, err := database.Connect(cfg.Database)
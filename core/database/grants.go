@@ -0,0 +1,155 @@
+package database
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Grant is one privilege line from SHOW GRANTS.
+type Grant struct {
+	// Privileges are the upper-case privilege names (e.g. "SELECT", "ALL PRIVILEGES").
+	// Column-level privileges are omitted since they never cover a whole table.
+	Privileges []string
+	// Database is the schema name or pattern, "*" for every schema.
+	Database string
+	// Table is the table name, "*" for every table.
+	Table string
+}
+
+// grantPattern matches "GRANT <privileges> ON <db>.<table> TO ...".
+var grantPattern = regexp.MustCompile("(?i)^GRANT\\s+(.+?)\\s+ON\\s+(?:TABLE\\s+)?(\\S+)\\s+TO\\s")
+
+// GetCurrentGrants returns the privileges of the connected MySQL user.
+func GetCurrentGrants(db *gorm.DB) ([]Grant, error) {
+	rows, err := db.Raw("SHOW GRANTS FOR CURRENT_USER()").Rows()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read grants: %w", err)
+	}
+	defer rows.Close()
+
+	var grants []Grant
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			return nil, fmt.Errorf("failed to scan grant: %w", err)
+		}
+		if grant, ok := ParseGrant(line); ok {
+			grants = append(grants, grant)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read grants: %w", err)
+	}
+	return grants, nil
+}
+
+// ParseGrant parses one SHOW GRANTS line. Role grants (no ON clause) return false.
+func ParseGrant(line string) (Grant, bool) {
+	m := grantPattern.FindStringSubmatch(strings.TrimSpace(line))
+	if m == nil {
+		return Grant{}, false
+	}
+
+	database, table, ok := splitGrantTarget(m[2])
+	if !ok {
+		return Grant{}, false
+	}
+
+	return Grant{
+		Privileges: splitPrivileges(m[1]),
+		Database:   database,
+		Table:      table,
+	}, true
+}
+
+// HasPrivilege reports whether any grant gives privilege on database.table.
+// An empty table asks for a schema-wide grant.
+func HasPrivilege(grants []Grant, privilege, database, table string) bool {
+	privilege = strings.ToUpper(privilege)
+	for _, g := range grants {
+		if !matchDatabase(g.Database, database) {
+			continue
+		}
+		if g.Table != "*" && (table == "" || !strings.EqualFold(g.Table, table)) {
+			continue
+		}
+		for _, p := range g.Privileges {
+			if p == privilege || p == "ALL" || p == "ALL PRIVILEGES" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// splitPrivileges splits a privilege list, dropping column-level entries like "UPDATE (`name`)".
+func splitPrivileges(list string) []string {
+	var privileges []string
+	depth, start := 0, 0
+	flush := func(end int) {
+		p := strings.TrimSpace(list[start:end])
+		if p != "" && !strings.Contains(p, "(") {
+			privileges = append(privileges, strings.ToUpper(p))
+		}
+	}
+	for i, r := range list {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				flush(i)
+				start = i + 1
+			}
+		}
+	}
+	flush(len(list))
+	return privileges
+}
+
+// splitGrantTarget splits "`db`.`table`" into its unquoted parts.
+func splitGrantTarget(target string) (string, string, bool) {
+	inQuote := false
+	for i, r := range target {
+		switch {
+		case r == '`':
+			inQuote = !inQuote
+		case r == '.' && !inQuote:
+			return strings.Trim(target[:i], "`"), strings.Trim(target[i+1:], "`"), true
+		}
+	}
+	return "", "", false
+}
+
+// matchDatabase matches a schema name against a grant's schema, which may use the
+// LIKE wildcards % and _ (escaped as \_ for a literal underscore).
+func matchDatabase(pattern, database string) bool {
+	if pattern == "*" || strings.EqualFold(pattern, database) {
+		return true
+	}
+
+	var b strings.Builder
+	b.WriteString("(?i)^")
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; {
+		case c == '\\' && i+1 < len(pattern):
+			i++
+			b.WriteString(regexp.QuoteMeta(string(pattern[i])))
+		case c == '%':
+			b.WriteString(".*")
+		case c == '_':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+
+	re, err := regexp.Compile(b.String())
+	return err == nil && re.MatchString(database)
+}
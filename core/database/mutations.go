@@ -0,0 +1,57 @@
+package database
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	applogger "asset-manager/core/logger"
+
+	"go.uber.org/zap"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// mutationLogger is a GORM logger that logs every statement other than reads to the
+// mutation logger of its context (see logger.WithMutationLog), with bound values
+// inlined. Everything else goes to the wrapped logger.
+type mutationLogger struct {
+	gormlogger.Interface
+}
+
+// LogMode keeps mutation logging on whatever level the wrapped logger is set to.
+func (m mutationLogger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
+	return mutationLogger{Interface: m.Interface.LogMode(level)}
+}
+
+// Trace logs the executed statement when the context asks for it.
+func (m mutationLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	m.Interface.Trace(ctx, begin, fc, err)
+
+	l := applogger.MutationLog(ctx)
+	if l == nil {
+		return
+	}
+	sql, rows := fc()
+	if isRead(sql) {
+		return
+	}
+	fields := []zap.Field{
+		zap.String("sql", sql),
+		zap.Int64("rows", rows),
+		zap.Duration("elapsed", time.Since(begin)),
+	}
+	if err != nil {
+		fields = append(fields, zap.Error(err))
+	}
+	l.Info("Executed SQL", fields...)
+}
+
+// isRead reports whether a statement only reads.
+func isRead(sql string) bool {
+	verb, _, _ := strings.Cut(strings.TrimSpace(sql), " ")
+	switch strings.ToUpper(verb) {
+	case "SELECT", "SHOW", "DESCRIBE", "EXPLAIN":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,48 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"asset-manager/core/logger"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// TestMutationLogger tests that writes under a mutation log are logged with their
+// values inlined, and that reads and other contexts are not.
+func TestMutationLogger(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	db, err := gorm.Open(mysql.New(mysql.Config{Conn: sqlDB, SkipInitializeWithVersion: true}),
+		&gorm.Config{Logger: mutationLogger{Interface: gormlogger.Default.LogMode(gormlogger.Silent)}})
+	require.NoError(t, err)
+
+	core, logs := observer.New(zap.InfoLevel)
+	ctx := logger.WithMutationLog(context.Background(), zap.New(core).With(zap.String("plan_id", "plan-1")))
+
+	mock.ExpectExec("DELETE FROM items_base").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectExec("DELETE FROM items_base").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	require.NoError(t, db.WithContext(ctx).Exec("DELETE FROM items_base WHERE id = ?", 42).Error)
+	var id int
+	require.NoError(t, db.WithContext(ctx).Raw("SELECT id FROM items_base").Scan(&id).Error)
+	require.NoError(t, db.WithContext(context.Background()).Exec("DELETE FROM items_base WHERE id = ?", 43).Error)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	entries := logs.All()
+	require.Len(t, entries, 1)
+	assert.Equal(t, "Executed SQL", entries[0].Message)
+	fields := entries[0].ContextMap()
+	assert.Equal(t, "DELETE FROM items_base WHERE id = 42", fields["sql"])
+	assert.Equal(t, int64(1), fields["rows"])
+	assert.Equal(t, "plan-1", fields["plan_id"])
+}
@@ -0,0 +1,12 @@
+// Package jobs runs long operations in the background of the server, so an HTTP
+// request can return a job ID right away instead of timing out behind a proxy such as
+// Cloudflare (100 seconds) on large hotels.
+//
+// A Manager runs each submitted Func in its own goroutine, at most jobs.max_running
+// at a time; later jobs wait as queued. A job reports its stage and progress percent
+// while it runs, and keeps its result or error for jobs.retention after it finishes.
+// Jobs live in process memory: they are lost on restart, and with several server
+// instances the status must be asked of the instance that accepted the job.
+//
+// The server registers its Manager with SetManager; handlers find it with Current.
+package jobs
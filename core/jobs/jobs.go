@@ -0,0 +1,224 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrNoManager is returned when a job is submitted while no manager is registered.
+var ErrNoManager = errors.New("background jobs are not available")
+
+// Status is the state of a job.
+type Status string
+
+const (
+	// StatusQueued jobs wait for a free slot (jobs.max_running).
+	StatusQueued Status = "queued"
+	// StatusRunning jobs are in progress.
+	StatusRunning Status = "running"
+	// StatusSucceeded jobs finished and hold a result.
+	StatusSucceeded Status = "succeeded"
+	// StatusFailed jobs finished with an error.
+	StatusFailed Status = "failed"
+)
+
+// Config holds the settings of the job manager.
+type Config struct {
+	// Retention is how long finished jobs and their results are kept.
+	Retention time.Duration `mapstructure:"retention" default:"1h"`
+	// MaxRunning is the number of jobs run at the same time; later jobs are queued.
+	MaxRunning int `mapstructure:"max_running" default:"2"`
+}
+
+// Job is a snapshot of a background job.
+type Job struct {
+	ID   string `json:"id"`
+	Kind string `json:"kind"`
+
+	Status Status `json:"status"`
+	// Stage is the step the job last reported, e.g. "indexes".
+	Stage string `json:"stage,omitempty"`
+	// Progress is the percentage of the job done, from 0 to 100.
+	Progress float64 `json:"progress"`
+	// Error is the failure of a failed job.
+	Error string `json:"error,omitempty"`
+	// Result is where the result of a succeeded job can be fetched. Handlers set it.
+	Result string `json:"result,omitempty"`
+
+	CreatedAt  time.Time  `json:"created_at"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// ProgressFunc reports the stage a job reached and its progress percent.
+type ProgressFunc func(stage string, percent float64)
+
+// Func is the work of a job. It should return soon after ctx is cancelled.
+type Func func(ctx context.Context, progress ProgressFunc) (any, error)
+
+// entry is the stored state of a job.
+type entry struct {
+	job    Job
+	result any
+}
+
+// Manager runs and tracks background jobs.
+type Manager struct {
+	cfg    Config
+	slots  chan struct{}
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+	now    func() time.Time
+
+	mu   sync.Mutex
+	jobs map[string]*entry
+}
+
+// NewManager creates a manager with cfg. A MaxRunning below 1 runs one job at a time.
+func NewManager(cfg Config) *Manager {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Manager{
+		cfg:    cfg,
+		slots:  make(chan struct{}, max(cfg.MaxRunning, 1)),
+		ctx:    ctx,
+		cancel: cancel,
+		now:    time.Now,
+		jobs:   make(map[string]*entry),
+	}
+}
+
+// Submit starts fn as a job of the given kind and returns its snapshot at once.
+func (m *Manager) Submit(kind string, fn Func) Job {
+	m.mu.Lock()
+	now := m.now()
+	m.prune(now)
+	e := &entry{job: Job{ID: uuid.NewString(), Kind: kind, Status: StatusQueued, CreatedAt: now}}
+	m.jobs[e.job.ID] = e
+	job := e.job
+	m.mu.Unlock()
+
+	m.wg.Add(1)
+	go m.run(e, fn)
+	return job
+}
+
+// run waits for a slot, then runs fn and records its outcome.
+func (m *Manager) run(e *entry, fn Func) {
+	defer m.wg.Done()
+
+	select {
+	case m.slots <- struct{}{}:
+		defer func() { <-m.slots }()
+	case <-m.ctx.Done():
+	}
+	// A slot may free up while the manager stops
+	if err := m.ctx.Err(); err != nil {
+		m.finish(e, nil, err)
+		return
+	}
+
+	m.update(e, func(job *Job) {
+		started := m.now()
+		job.Status = StatusRunning
+		job.StartedAt = &started
+	})
+
+	result, err := fn(m.ctx, func(stage string, percent float64) {
+		m.update(e, func(job *Job) {
+			job.Stage = stage
+			job.Progress = min(max(percent, 0), 100)
+		})
+	})
+	m.finish(e, result, err)
+}
+
+// finish records the outcome of a job.
+func (m *Manager) finish(e *entry, result any, err error) {
+	m.update(e, func(job *Job) {
+		finished := m.now()
+		job.FinishedAt = &finished
+		if err != nil {
+			job.Status = StatusFailed
+			job.Error = err.Error()
+			return
+		}
+		job.Status = StatusSucceeded
+		job.Progress = 100
+		e.result = result
+	})
+}
+
+// update applies change to the job of e under the lock.
+func (m *Manager) update(e *entry, change func(*Job)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	change(&e.job)
+}
+
+// Get returns the snapshot of the job with id.
+func (m *Manager) Get(id string) (Job, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.prune(m.now())
+	e, ok := m.jobs[id]
+	if !ok {
+		return Job{}, false
+	}
+	return e.job, true
+}
+
+// Result returns the snapshot of the job with id and its result, which is nil until
+// the job succeeded.
+func (m *Manager) Result(id string) (Job, any, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.prune(m.now())
+	e, ok := m.jobs[id]
+	if !ok {
+		return Job{}, nil, false
+	}
+	return e.job, e.result, true
+}
+
+// Stop cancels the running and queued jobs and waits for them to return.
+func (m *Manager) Stop() {
+	m.cancel()
+	m.wg.Wait()
+}
+
+// prune drops jobs finished more than the retention ago. Callers hold mu.
+func (m *Manager) prune(now time.Time) {
+	for id, e := range m.jobs {
+		if e.job.FinishedAt != nil && now.Sub(*e.job.FinishedAt) > m.cfg.Retention {
+			delete(m.jobs, id)
+		}
+	}
+}
+
+// registry holds the process-wide manager.
+type registry struct {
+	mu      sync.RWMutex
+	manager *Manager
+}
+
+// global is the singleton job registry.
+var global = &registry{}
+
+// SetManager registers the manager background jobs run on. Passing nil disables them.
+func SetManager(manager *Manager) {
+	global.mu.Lock()
+	defer global.mu.Unlock()
+	global.manager = manager
+}
+
+// Current returns the registered manager, or nil when background jobs are disabled.
+func Current() *Manager {
+	global.mu.RLock()
+	defer global.mu.RUnlock()
+	return global.manager
+}
@@ -0,0 +1,124 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// waitFor polls the job with id until it reaches status.
+func waitFor(t *testing.T, m *Manager, id string, status Status) Job {
+	t.Helper()
+	var job Job
+	require.Eventually(t, func() bool {
+		job, _ = m.Get(id)
+		return job.Status == status
+	}, time.Second, time.Millisecond)
+	return job
+}
+
+func TestManager_Succeeded(t *testing.T) {
+	m := NewManager(Config{Retention: time.Hour, MaxRunning: 1})
+	defer m.Stop()
+
+	release := make(chan struct{})
+	job := m.Submit("test", func(ctx context.Context, progress ProgressFunc) (any, error) {
+		progress("working", 40)
+		<-release
+		return "done", nil
+	})
+	assert.Equal(t, "test", job.Kind)
+	assert.NotEmpty(t, job.ID)
+
+	require.Eventually(t, func() bool {
+		got, _ := m.Get(job.ID)
+		return got.Stage == "working"
+	}, time.Second, time.Millisecond)
+	running, _ := m.Get(job.ID)
+	assert.Equal(t, StatusRunning, running.Status)
+	assert.Equal(t, 40.0, running.Progress)
+	assert.NotNil(t, running.StartedAt)
+
+	close(release)
+	done := waitFor(t, m, job.ID, StatusSucceeded)
+	assert.Equal(t, 100.0, done.Progress)
+	assert.NotNil(t, done.FinishedAt)
+
+	_, result, ok := m.Result(job.ID)
+	require.True(t, ok)
+	assert.Equal(t, "done", result)
+}
+
+func TestManager_Failed(t *testing.T) {
+	m := NewManager(Config{Retention: time.Hour, MaxRunning: 1})
+	defer m.Stop()
+
+	job := m.Submit("test", func(ctx context.Context, progress ProgressFunc) (any, error) {
+		return nil, errors.New("storage unreachable")
+	})
+	failed := waitFor(t, m, job.ID, StatusFailed)
+	assert.Equal(t, "storage unreachable", failed.Error)
+
+	_, result, _ := m.Result(job.ID)
+	assert.Nil(t, result)
+}
+
+func TestManager_Queued(t *testing.T) {
+	m := NewManager(Config{Retention: time.Hour, MaxRunning: 1})
+	defer m.Stop()
+
+	release := make(chan struct{})
+	block := func(ctx context.Context, progress ProgressFunc) (any, error) {
+		<-release
+		return nil, nil
+	}
+	first := m.Submit("test", block)
+	waitFor(t, m, first.ID, StatusRunning)
+
+	second := m.Submit("test", block)
+	time.Sleep(10 * time.Millisecond)
+	queued, _ := m.Get(second.ID)
+	assert.Equal(t, StatusQueued, queued.Status)
+
+	close(release)
+	waitFor(t, m, second.ID, StatusSucceeded)
+}
+
+func TestManager_Retention(t *testing.T) {
+	m := NewManager(Config{Retention: time.Minute, MaxRunning: 1})
+	defer m.Stop()
+
+	job := m.Submit("test", func(ctx context.Context, progress ProgressFunc) (any, error) {
+		return nil, nil
+	})
+	waitFor(t, m, job.ID, StatusSucceeded)
+
+	m.mu.Lock()
+	m.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	m.mu.Unlock()
+	_, ok := m.Get(job.ID)
+	assert.False(t, ok)
+}
+
+func TestManager_Stop(t *testing.T) {
+	m := NewManager(Config{Retention: time.Hour, MaxRunning: 1})
+
+	job := m.Submit("test", func(ctx context.Context, progress ProgressFunc) (any, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	waitFor(t, m, job.ID, StatusRunning)
+	queued := m.Submit("test", func(ctx context.Context, progress ProgressFunc) (any, error) {
+		return nil, nil
+	})
+
+	m.Stop()
+	got, _ := m.Get(job.ID)
+	assert.Equal(t, StatusFailed, got.Status)
+	got, _ = m.Get(queued.ID)
+	assert.Equal(t, StatusFailed, got.Status)
+}
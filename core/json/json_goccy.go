@@ -0,0 +1,28 @@
+//go:build goccy_json
+
+package json
+
+import (
+	goccy "github.com/goccy/go-json"
+)
+
+// Library is the name of the active JSON backend.
+const Library = "goccy/go-json"
+
+// RawMessage is a raw encoded JSON value.
+type RawMessage = goccy.RawMessage
+
+// Marshal returns the JSON encoding of v.
+var Marshal = goccy.Marshal
+
+// MarshalIndent is like Marshal but applies indentation to format the output.
+var MarshalIndent = goccy.MarshalIndent
+
+// Unmarshal parses JSON-encoded data and stores the result in the value pointed to by v.
+var Unmarshal = goccy.Unmarshal
+
+// NewDecoder returns a new decoder that reads from r.
+var NewDecoder = goccy.NewDecoder
+
+// NewEncoder returns a new encoder that writes to w.
+var NewEncoder = goccy.NewEncoder
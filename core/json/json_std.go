@@ -0,0 +1,28 @@
+//go:build !goccy_json
+
+package json
+
+import (
+	stdjson "encoding/json"
+)
+
+// Library is the name of the active JSON backend.
+const Library = "encoding/json"
+
+// RawMessage is a raw encoded JSON value.
+type RawMessage = stdjson.RawMessage
+
+// Marshal returns the JSON encoding of v.
+var Marshal = stdjson.Marshal
+
+// MarshalIndent is like Marshal but applies indentation to format the output.
+var MarshalIndent = stdjson.MarshalIndent
+
+// Unmarshal parses JSON-encoded data and stores the result in the value pointed to by v.
+var Unmarshal = stdjson.Unmarshal
+
+// NewDecoder returns a new decoder that reads from r.
+var NewDecoder = stdjson.NewDecoder
+
+// NewEncoder returns a new encoder that writes to w.
+var NewEncoder = stdjson.NewEncoder
@@ -0,0 +1,102 @@
+package json
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type benchItem struct {
+	ID         int    `json:"id"`
+	ClassName  string `json:"classname"`
+	Revision   int    `json:"revision"`
+	Category   string `json:"category"`
+	Name       string `json:"name"`
+	XDim       int    `json:"xdim"`
+	YDim       int    `json:"ydim"`
+	CanStandOn bool   `json:"canstandon"`
+	CanSitOn   bool   `json:"cansiton"`
+	PartColors struct {
+		Color []string `json:"color"`
+	} `json:"partcolors"`
+}
+
+type benchData struct {
+	RoomItemTypes struct {
+		FurniType []benchItem `json:"furnitype"`
+	} `json:"roomitemtypes"`
+	WallItemTypes struct {
+		FurniType []benchItem `json:"furnitype"`
+	} `json:"wallitemtypes"`
+}
+
+// furnidata builds a synthetic FurnitureData.json with n room and n/10 wall items.
+func furnidata(tb testing.TB, n int) []byte {
+	tb.Helper()
+	var data benchData
+	for i := 1; i <= n; i++ {
+		item := benchItem{ID: i, ClassName: fmt.Sprintf("furni_%d", i), Revision: 60000 + i, Category: "other", Name: fmt.Sprintf("Furni %d", i), XDim: 1, YDim: 2}
+		item.PartColors.Color = []string{"#FFFFFF", "#000000"}
+		data.RoomItemTypes.FurniType = append(data.RoomItemTypes.FurniType, item)
+		if i%10 == 0 {
+			data.WallItemTypes.FurniType = append(data.WallItemTypes.FurniType, item)
+		}
+	}
+	raw, err := Marshal(data)
+	require.NoError(tb, err)
+	return raw
+}
+
+func TestRoundTrip(t *testing.T) {
+	raw := furnidata(t, 50)
+
+	var data benchData
+	require.NoError(t, Unmarshal(raw, &data))
+	assert.Len(t, data.RoomItemTypes.FurniType, 50)
+	assert.Len(t, data.WallItemTypes.FurniType, 5)
+	assert.Equal(t, "furni_10", data.WallItemTypes.FurniType[0].ClassName)
+
+	var decoded benchData
+	require.NoError(t, NewDecoder(bytes.NewReader(raw)).Decode(&decoded))
+	assert.Equal(t, data, decoded)
+
+	indented, err := MarshalIndent(decoded, "", "  ")
+	require.NoError(t, err)
+	var again benchData
+	require.NoError(t, Unmarshal(indented, &again))
+	assert.Equal(t, data, again)
+}
+
+func BenchmarkUnmarshalFurnidata(b *testing.B) {
+	for _, n := range []int{1000, 20000} {
+		raw := furnidata(b, n)
+		b.Run(fmt.Sprintf("%s/%d", Library, n), func(b *testing.B) {
+			b.SetBytes(int64(len(raw)))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				var data benchData
+				if err := Unmarshal(raw, &data); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkMarshalIndentFurnidata(b *testing.B) {
+	var data benchData
+	if err := Unmarshal(furnidata(b, 20000), &data); err != nil {
+		b.Fatal(err)
+	}
+	b.Run(Library, func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := MarshalIndent(data, "", "  "); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
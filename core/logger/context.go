@@ -0,0 +1,28 @@
+package logger
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+// mutationLogKey is the context key of the mutation logger.
+type mutationLogKey struct{}
+
+// WithMutationLog returns a context under which the database statements and storage
+// writes of the hotel are logged to l at info level, for hotels whose change
+// management requires a full command log. Fields of l, such as ray_id or plan_id,
+// are kept on every entry.
+func WithMutationLog(ctx context.Context, l *zap.Logger) context.Context {
+	return context.WithValue(ctx, mutationLogKey{}, l)
+}
+
+// MutationLog returns the logger set by WithMutationLog, or nil when mutations are
+// not logged.
+func MutationLog(ctx context.Context) *zap.Logger {
+	if ctx == nil {
+		return nil
+	}
+	l, _ := ctx.Value(mutationLogKey{}).(*zap.Logger)
+	return l
+}
@@ -9,6 +9,12 @@
 // The WithRayID helper extracts the RayID from a Fiber context and attaches it to the
 // log entry, ensuring that all logs related to a specific request can be correlated.
 //
+// # Mutation Log
+//
+// WithMutationLog attaches a logger to a context. Database statements (through the
+// GORM logger installed by database.Connect) and storage writes (through the storage
+// client) made with that context are logged to it at info level.
+//
 // # Configuration
 //
 // The package supports configuration for:
@@ -8,6 +8,9 @@
 //   - RayID: Generates a unique Request ID (RayID) for every incoming request,
 //     injecting it into the context and response headers for tracing.
 //
+// The furniture import route additionally uses upload.Limit (core/upload) for its own,
+// lower body limit.
+//
 // These middleware components are designed to be registered globally or per-route group
 // in the main application setup.
 package middleware
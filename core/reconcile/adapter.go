@@ -11,6 +11,9 @@ import (
 // Adapter defines the interface for model-specific reconciliation logic.
 // Each adapter implements how to load, index, and compare data for a specific model
 // (e.g., furniture, effects, clothing).
+//
+// ResolveName, CompareFields and GetMetadata are called from several goroutines at
+// once while results are built, so they must only read the items they are given.
 type Adapter interface {
 	// Name returns the unique name of this adapter (e.g., "furniture", "effects").
 	Name() string
@@ -79,6 +82,20 @@ type Adapter interface {
 	Prepare(ctx context.Context, db *gorm.DB) error
 }
 
+// Source is an additional source of truth reconciled alongside the default
+// DB, gamedata and storage sources (e.g. a catalog table, icon set or CDN manifest).
+// Additional sources are report-only: they extend presence checks, health and
+// summaries, while purge and sync still act on the default three.
+type Source interface {
+	// Name returns the unique source name used in results and summaries (e.g. "catalog").
+	// It must not collide with SourceDB, SourceGamedata or SourceStorage.
+	Name() string
+
+	// LoadIndex loads every entity in the source and returns it indexed by entity key.
+	// Keys must use the same format as the adapter's keys to join with the other sources.
+	LoadIndex(ctx context.Context, db *gorm.DB, client storage.Client, bucket string) (map[string]any, error)
+}
+
 // Mutator extends Adapter with mutation capabilities for purge and sync operations.
 // Adapters implementing this interface can handle destructive cleanup and data repair.
 type Mutator interface {
@@ -100,3 +117,69 @@ type Mutator interface {
 	// Returns an error if the sync fails.
 	SyncDBFromGamedata(ctx context.Context, key string, gdItem GDItem) error
 }
+
+// GamedataSyncer extends Mutator for adapters that can sync in the reverse direction,
+// rewriting gamedata entries from their DB rows. Syncs with SyncDBToGamedata are only
+// planned for adapters implementing it.
+type GamedataSyncer interface {
+	// SyncGamedataFromDB updates the gamedata entry of key to match the DB for the
+	// fields that differ from gdItem. The dbItem parameter provides the authoritative
+	// source data.
+	SyncGamedataFromDB(ctx context.Context, key string, dbItem DBItem, gdItem GDItem) error
+}
+
+// CacheUpdater lets an adapter translate executed actions into index updates, so
+// cached indices stay warm after ApplyPlan instead of being rebuilt from scratch.
+// The engine passes a copy of the cached indices; returning an error discards the
+// cache and the next call rebuilds it.
+//
+// Adapters that do not implement it get the default behavior: deletions are applied
+// with ApplyDeletions and any other executed action invalidates the cache.
+type CacheUpdater interface {
+	// UpdateCache applies the executed actions to cache in place.
+	UpdateCache(cache *ReconcileCache, actions []Action) error
+}
+
+// KeyNormalizer lets an adapter map near-miss keys of different sources to one entity,
+// e.g. "007" and "7" or "Chair" and "chair". The engine normalizes every key of every
+// index before building the union, so such keys no longer produce duplicate phantom
+// entries. Results, actions and targeted lookups then carry normalized keys, so the
+// adapter's CheckStorage and mutations must accept them.
+//
+// Adapters that do not implement it have their keys compared as they are.
+type KeyNormalizer interface {
+	// NormalizeKey returns the canonical form of key. It must be idempotent.
+	NormalizeKey(key string) string
+}
+
+// TableNamer lets the permissions preflight check grants on the adapter's table.
+// Adapters that do not implement it are checked against schema-wide grants.
+type TableNamer interface {
+	// TableName returns the DB table the adapter mutates for the given server profile.
+	TableName(serverProfile string) string
+}
+
+// Searchable lets Search match entities on the adapter's own fields, such as
+// classnames and display names. Adapters that do not implement it are searched on
+// their keys, the name from ResolveName and the values of GetMetadata.
+type Searchable interface {
+	// SearchTerms returns the texts an entity can be found by. Either item may be nil.
+	SearchTerms(dbItem DBItem, gdItem GDItem) []string
+}
+
+// PartialComparer lets an adapter name the fields CompareFields could not compare,
+// e.g. because the DB column they are read from is missing (see RecordMissingColumns).
+// Such fields are reported in ReconcileResult.NotComparable instead of as mismatches.
+type PartialComparer interface {
+	// NotComparable returns the fields left out of the comparison of the two items.
+	NotComparable(dbItem DBItem, gdItem GDItem) []string
+}
+
+// SeverityRater lets an adapter report some fields of CompareFields at warning
+// severity, e.g. cosmetic text the client can live with. Their mismatches are moved to
+// ReconcileResult.Warnings and are not counted as issues.
+type SeverityRater interface {
+	// FieldSeverity returns the severity of a mismatch in field, as labeled in the
+	// mismatch description ("description: ...").
+	FieldSeverity(field string) Severity
+}
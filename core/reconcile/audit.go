@@ -0,0 +1,104 @@
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"asset-manager/core/storage"
+)
+
+// Audit outcomes.
+const (
+	// AuditApplied marks an action that executed successfully.
+	AuditApplied = "applied"
+	// AuditFailed marks an action whose execution returned an error.
+	AuditFailed = "failed"
+)
+
+// ApplyTrigger is the audit trigger recorded by ApplyPlan.
+const ApplyTrigger = "apply"
+
+// ActionApplyPlan is the audit action of an applied plan as a whole. ApplyPlan records
+// it with the replaced object versions, so UndoPlan can restore them later.
+const ActionApplyPlan ActionType = "apply_plan"
+
+// AuditEntry records one action applied to a store, for after-the-fact review.
+type AuditEntry struct {
+	// Time is when the action was applied.
+	Time time.Time `json:"time"`
+
+	// Adapter is the adapter the action belongs to (e.g. "furniture").
+	Adapter string `json:"adapter"`
+
+	// Trigger identifies what applied the action (e.g. "safe-fix").
+	Trigger string `json:"trigger"`
+
+	// Action is the action type.
+	Action ActionType `json:"action"`
+
+	// Key is the entity identifier.
+	Key string `json:"key"`
+
+	// Fields lists the mismatched fields a sync repaired.
+	Fields []string `json:"fields,omitempty"`
+
+	// Reason explains why the action was planned.
+	Reason string `json:"reason"`
+
+	// Outcome is AuditApplied or AuditFailed.
+	Outcome string `json:"outcome"`
+
+	// Error holds the failure message when Outcome is AuditFailed.
+	Error string `json:"error,omitempty"`
+
+	// PlanID is the ID of the applied plan the action belonged to.
+	PlanID string `json:"plan_id,omitempty"`
+
+	// RunID is the run that applied the action (see WithRunID).
+	RunID string `json:"run_id,omitempty"`
+
+	// Versions lists the object versions the plan deleted or overwrote, in a bucket
+	// with versioning enabled. Only set on ActionApplyPlan entries.
+	Versions []storage.ObjectVersion `json:"versions,omitempty"`
+}
+
+// AuditStore persists audit entries.
+type AuditStore interface {
+	// RecordAudit appends entries to the audit log.
+	RecordAudit(ctx context.Context, entries []AuditEntry) error
+}
+
+// auditRegistry holds the process-wide audit store.
+type auditRegistry struct {
+	mu    sync.RWMutex
+	store AuditStore
+}
+
+// globalAudit is the singleton audit registry for all reconcile operations.
+var globalAudit = &auditRegistry{}
+
+// SetAuditStore registers the store that receives audit entries.
+// Passing nil disables persistence; entries are then only returned to callers.
+func SetAuditStore(store AuditStore) {
+	globalAudit.mu.Lock()
+	defer globalAudit.mu.Unlock()
+	globalAudit.store = store
+}
+
+// RecordAudit writes entries to the registered audit store.
+// It is a no-op when no store is registered.
+func RecordAudit(ctx context.Context, entries []AuditEntry) error {
+	globalAudit.mu.RLock()
+	store := globalAudit.store
+	globalAudit.mu.RUnlock()
+
+	if store == nil || len(entries) == 0 {
+		return nil
+	}
+	if err := store.RecordAudit(ctx, entries); err != nil {
+		return fmt.Errorf("failed to record audit entries: %w", err)
+	}
+	return nil
+}
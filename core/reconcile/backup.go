@@ -0,0 +1,258 @@
+package reconcile
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"asset-manager/core/json"
+	"asset-manager/core/storage"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// BackupPrefix is the storage folder ApplyPlan backs plans up to, one folder per plan.
+const BackupPrefix = "backups"
+
+// backupManifest is the name of the manifest in a plan's backup folder.
+const backupManifest = "backup.json"
+
+// ActionRollbackPlan is the audit action of a plan rolled back by RollbackPlan.
+const ActionRollbackPlan ActionType = "rollback_plan"
+
+// RollbackTrigger is the audit trigger recorded by RollbackPlan.
+const RollbackTrigger = "rollback"
+
+// ErrNoBackup is returned when no backup was written for a plan.
+var ErrNoBackup = errors.New("no backup for plan")
+
+// Backuper is implemented by adapters whose plans ApplyPlan backs up before applying
+// them, so RollbackPlan can restore the affected keys.
+type Backuper interface {
+	// BackupItems records the DB rows and gamedata entries of backup.Keys in backup,
+	// and lists in backup.Objects the storage objects actions may delete or overwrite,
+	// whether they exist or not. Only stores listed in backup.Sources are backed up.
+	BackupItems(ctx context.Context, actions []Action, backup *Backup) error
+
+	// RestoreItems writes the DB rows and gamedata entries of backup back. Rows and
+	// entries of backup.Keys the backup does not hold are removed: the plan created them.
+	RestoreItems(ctx context.Context, backup *Backup) error
+}
+
+// Backup is the state of the keys a plan affected from before it was applied.
+type Backup struct {
+	// PlanID is the backed up plan.
+	PlanID string `json:"plan_id"`
+
+	// RunID is the run that applied the plan (see WithRunID).
+	RunID string `json:"run_id,omitempty"`
+
+	// Adapter is the name of the adapter that applied the plan.
+	Adapter string `json:"adapter"`
+
+	// CreatedAt is when the backup was taken.
+	CreatedAt time.Time `json:"created_at"`
+
+	// Keys lists the keys the plan affected, in plan order.
+	Keys []string `json:"keys"`
+
+	// Sources lists the stores the plan writes (SourceDB, SourceGamedata,
+	// SourceStorage). Only these are backed up and restored.
+	Sources []string `json:"sources"`
+
+	// Rows maps keys to their DB rows, by column.
+	Rows map[string][]map[string]any `json:"rows,omitempty"`
+
+	// Gamedata maps keys to their gamedata entries.
+	Gamedata map[string][]BackupEntry `json:"gamedata,omitempty"`
+
+	// Objects lists the storage objects of the keys.
+	Objects []BackupObject `json:"objects,omitempty"`
+}
+
+// BackupEntry is a gamedata entry saved by a backup.
+type BackupEntry struct {
+	// Section is the part of the gamedata file holding the entry, e.g. "roomitemtypes".
+	Section string `json:"section,omitempty"`
+
+	// Entry holds the fields of the entry as stored.
+	Entry map[string]any `json:"entry"`
+}
+
+// BackupObject is a storage object of a backed up key.
+type BackupObject struct {
+	// Key is the entity identifier.
+	Key string `json:"key"`
+
+	// Bucket and Object locate the object.
+	Bucket string `json:"bucket"`
+	Object string `json:"object"`
+
+	// Saved is the copy in the backup folder, in Bucket. Empty when the object did not
+	// exist, so a rollback removes it.
+	Saved string `json:"saved,omitempty"`
+}
+
+// BackupFolder returns the storage folder of the backup of planID.
+func BackupFolder(planID string) string {
+	return BackupPrefix + "/" + planID + "/"
+}
+
+// actionSource returns the store an action of type t writes.
+func actionSource(t ActionType) string {
+	switch t {
+	case ActionDeleteDB, ActionSyncDB, ActionInsertDB:
+		return SourceDB
+	case ActionDeleteGamedata, ActionSyncGamedata, ActionInsertGamedata:
+		return SourceGamedata
+	}
+	return SourceStorage
+}
+
+// HasSource reports whether the backup holds the state of source.
+func (b *Backup) HasSource(source string) bool {
+	return slices.Contains(b.Sources, source)
+}
+
+// backupPlan saves the state of the keys plan affects to the backup folder of the plan
+// in bucket, when the adapter is a Backuper, and sets plan.Backup to its manifest.
+func backupPlan(ctx context.Context, spec *Spec, client storage.Client, bucket string, plan *ReconcilePlan) error {
+	backuper, ok := spec.Adapter.(Backuper)
+	if !ok || len(plan.Actions) == 0 {
+		return nil
+	}
+
+	backup := &Backup{
+		PlanID:    plan.ID,
+		RunID:     RunIDFrom(ctx),
+		Adapter:   spec.Adapter.Name(),
+		CreatedAt: time.Now().UTC(),
+	}
+	seen := make(map[string]bool, len(plan.Actions))
+	for _, action := range plan.Actions {
+		if !seen[action.Key] {
+			seen[action.Key] = true
+			backup.Keys = append(backup.Keys, action.Key)
+		}
+		if source := actionSource(action.Type); !slices.Contains(backup.Sources, source) {
+			backup.Sources = append(backup.Sources, source)
+		}
+	}
+	sort.Strings(backup.Sources)
+	if err := backuper.BackupItems(ctx, plan.Actions, backup); err != nil {
+		return fmt.Errorf("failed to back up plan %s: %w", plan.ID, err)
+	}
+
+	folder := BackupFolder(plan.ID)
+	for i, obj := range backup.Objects {
+		data, found, err := storage.ReadObject(ctx, client, obj.Bucket, obj.Object)
+		if err != nil {
+			return fmt.Errorf("failed to back up plan %s: %w", plan.ID, err)
+		}
+		if !found {
+			continue
+		}
+		saved := folder + obj.Object
+		if _, err := client.PutObject(ctx, obj.Bucket, saved, bytes.NewReader(data), int64(len(data)), minio.PutObjectOptions{}); err != nil {
+			return fmt.Errorf("failed to back up %s: %w", obj.Object, err)
+		}
+		backup.Objects[i].Saved = saved
+	}
+
+	data, err := json.MarshalIndent(backup, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode backup: %w", err)
+	}
+	manifest := folder + backupManifest
+	if _, err := client.PutObject(ctx, bucket, manifest, bytes.NewReader(data), int64(len(data)), minio.PutObjectOptions{ContentType: "application/json"}); err != nil {
+		return fmt.Errorf("failed to write backup manifest: %w", err)
+	}
+	plan.Backup = manifest
+	return nil
+}
+
+// LoadBackup reads the backup ApplyPlan saved for planID in bucket. It returns
+// ErrNoBackup when there is none.
+func LoadBackup(ctx context.Context, client storage.Client, bucket, planID string) (*Backup, error) {
+	data, found, err := storage.ReadObject(ctx, client, bucket, BackupFolder(planID)+backupManifest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup: %w", err)
+	}
+	if !found {
+		return nil, fmt.Errorf("%w %s", ErrNoBackup, planID)
+	}
+	var backup Backup
+	if err := json.Unmarshal(data, &backup); err != nil {
+		return nil, fmt.Errorf("failed to parse backup of plan %s: %w", planID, err)
+	}
+	return &backup, nil
+}
+
+// RollbackPlan restores the keys of backup to their state from before the plan: the
+// adapter writes the DB rows and gamedata entries back, then saved storage objects are
+// copied back and objects the plan created are removed. Cached indices of spec are
+// dropped.
+func RollbackPlan(ctx context.Context, spec *Spec, client storage.Client, backup *Backup) (err error) {
+	backuper, ok := spec.Adapter.(Backuper)
+	if !ok {
+		return fmt.Errorf("adapter %s does not support rollback", spec.Adapter.Name())
+	}
+	if backup.Adapter != spec.Adapter.Name() {
+		return fmt.Errorf("plan %s was applied by adapter %s, not %s", backup.PlanID, backup.Adapter, spec.Adapter.Name())
+	}
+
+	defer InvalidateCache(spec)
+	defer func() {
+		if auditErr := recordRollback(ctx, backup, err); auditErr != nil && err == nil {
+			err = auditErr
+		}
+	}()
+
+	if err := backuper.RestoreItems(ctx, backup); err != nil {
+		return fmt.Errorf("failed to restore items: %w", err)
+	}
+
+	for _, obj := range backup.Objects {
+		if obj.Saved == "" {
+			if err := client.RemoveObject(ctx, obj.Bucket, obj.Object, minio.RemoveObjectOptions{}); err != nil {
+				return fmt.Errorf("failed to remove %s: %w", obj.Object, err)
+			}
+			continue
+		}
+		data, found, err := storage.ReadObject(ctx, client, obj.Bucket, obj.Saved)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return fmt.Errorf("backup copy %s of %s is missing", obj.Saved, obj.Object)
+		}
+		if _, err := client.PutObject(ctx, obj.Bucket, obj.Object, bytes.NewReader(data), int64(len(data)), minio.PutObjectOptions{}); err != nil {
+			return fmt.Errorf("failed to restore %s: %w", obj.Object, err)
+		}
+	}
+	return nil
+}
+
+// recordRollback writes an ActionRollbackPlan audit entry for backup.
+func recordRollback(ctx context.Context, backup *Backup, rollbackErr error) error {
+	entry := AuditEntry{
+		Time:    time.Now(),
+		Adapter: backup.Adapter,
+		Trigger: RollbackTrigger,
+		Action:  ActionRollbackPlan,
+		Key:     backup.PlanID,
+		Reason:  fmt.Sprintf("%d keys restored from %s", len(backup.Keys), BackupFolder(backup.PlanID)),
+		Outcome: AuditApplied,
+		PlanID:  backup.PlanID,
+		RunID:   RunIDFrom(ctx),
+	}
+	if rollbackErr != nil {
+		entry.Outcome = AuditFailed
+		entry.Error = rollbackErr.Error()
+	}
+	return RecordAudit(ctx, []AuditEntry{entry})
+}
@@ -0,0 +1,135 @@
+package reconcile
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"asset-manager/core/storage/mocks"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryObjects keeps storage objects in memory, by bucket and name.
+type memoryObjects struct {
+	mocks.Client
+	objects map[string][]byte
+}
+
+func (m *memoryObjects) GetObject(ctx context.Context, bucketName, objectName string, opts minio.GetObjectOptions) (io.ReadCloser, error) {
+	data, ok := m.objects[bucketName+"/"+objectName]
+	if !ok {
+		return nil, minio.ErrorResponse{Code: "NoSuchKey"}
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (m *memoryObjects) PutObject(ctx context.Context, bucketName, objectName string, reader io.Reader, objectSize int64, opts minio.PutObjectOptions) (minio.UploadInfo, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return minio.UploadInfo{}, err
+	}
+	m.objects[bucketName+"/"+objectName] = data
+	return minio.UploadInfo{}, nil
+}
+
+func (m *memoryObjects) RemoveObject(ctx context.Context, bucketName, objectName string, opts minio.RemoveObjectOptions) error {
+	delete(m.objects, bucketName+"/"+objectName)
+	return nil
+}
+
+// backingMutator backs up one DB row per key and the objects of storage actions.
+type backingMutator struct {
+	mockMutator
+	client   *memoryObjects
+	restored *Backup
+}
+
+func (m *backingMutator) BackupItems(ctx context.Context, actions []Action, backup *Backup) error {
+	backup.Rows = map[string][]map[string]any{}
+	for _, key := range backup.Keys {
+		backup.Rows[key] = []map[string]any{{"sprite_id": key}}
+	}
+	for _, action := range actions {
+		if action.Type == ActionDeleteStorage || action.Type == ActionDownloadStorage {
+			backup.Objects = append(backup.Objects, BackupObject{Key: action.Key, Bucket: "assets", Object: "furni/" + action.Key + ".nitro"})
+		}
+	}
+	return nil
+}
+
+func (m *backingMutator) RestoreItems(ctx context.Context, backup *Backup) error {
+	m.restored = backup
+	return nil
+}
+
+func (m *backingMutator) DeleteStorage(ctx context.Context, key string) error {
+	return m.client.RemoveObject(ctx, "assets", "furni/"+key+".nitro", minio.RemoveObjectOptions{})
+}
+
+// TestBackupAndRollback tests that ApplyPlan backs up the affected keys and that
+// RollbackPlan restores them: items through the adapter, deleted files from their
+// copies, and files the plan created by removing them.
+func TestBackupAndRollback(t *testing.T) {
+	store := &memoryAuditStore{}
+	SetAuditStore(store)
+	defer SetAuditStore(nil)
+
+	client := &memoryObjects{objects: map[string][]byte{"assets/furni/chair.nitro": []byte("chair")}}
+	mutator := &backingMutator{client: client}
+	spec := &Spec{Adapter: mutator}
+
+	plan := &ReconcilePlan{ID: "p1", Actions: []Action{
+		{Type: ActionDeleteDB, Key: "chair"},
+		{Type: ActionDeleteStorage, Key: "chair"},
+		{Type: ActionDeleteStorage, Key: "lamp"},
+	}}
+	_, err := ApplyPlan(context.Background(), spec, nil, client, "state", plan, ReconcileOptions{Confirmed: true})
+	require.NoError(t, err)
+	assert.Equal(t, "backups/p1/backup.json", plan.Backup)
+	assert.NotContains(t, client.objects, "assets/furni/chair.nitro")
+	assert.Equal(t, []byte("chair"), client.objects["assets/backups/p1/furni/chair.nitro"])
+
+	backup, err := LoadBackup(context.Background(), client, "state", "p1")
+	require.NoError(t, err)
+	assert.Equal(t, "mock", backup.Adapter)
+	assert.Equal(t, []string{"chair", "lamp"}, backup.Keys)
+	assert.Equal(t, []string{SourceDB, SourceStorage}, backup.Sources)
+	require.Len(t, backup.Objects, 2)
+	assert.Equal(t, "backups/p1/furni/chair.nitro", backup.Objects[0].Saved)
+	assert.Empty(t, backup.Objects[1].Saved, "missing files are not copied")
+
+	// A file created after the backup for a key without one is removed
+	client.objects["assets/furni/lamp.nitro"] = []byte("lamp")
+	require.NoError(t, RollbackPlan(context.Background(), spec, client, backup))
+	assert.Equal(t, backup, mutator.restored)
+	assert.Equal(t, []byte("chair"), client.objects["assets/furni/chair.nitro"])
+	assert.NotContains(t, client.objects, "assets/furni/lamp.nitro")
+
+	require.Len(t, store.entries, 1)
+	assert.Equal(t, ActionRollbackPlan, store.entries[0].Action)
+	assert.Equal(t, "p1", store.entries[0].PlanID)
+	assert.Equal(t, AuditApplied, store.entries[0].Outcome)
+}
+
+// TestLoadBackup_Missing tests that plans without a backup are reported as such.
+func TestLoadBackup_Missing(t *testing.T) {
+	client := &memoryObjects{objects: map[string][]byte{}}
+	_, err := LoadBackup(context.Background(), client, "state", "unknown")
+	assert.True(t, errors.Is(err, ErrNoBackup))
+}
+
+// TestApplyPlan_NoBackuper tests that plans of adapters without backup support are not
+// backed up.
+func TestApplyPlan_NoBackuper(t *testing.T) {
+	client := &memoryObjects{objects: map[string][]byte{}}
+	plan := &ReconcilePlan{Actions: []Action{{Type: ActionDeleteDB, Key: "1"}}}
+	_, err := ApplyPlan(context.Background(), &Spec{Adapter: &mockMutator{}}, nil, client, "state", plan, ReconcileOptions{Confirmed: true})
+	require.NoError(t, err)
+	assert.Empty(t, plan.Backup)
+	assert.Empty(t, client.objects)
+}
@@ -3,6 +3,7 @@ package reconcile
 import (
 	"context"
 	"fmt"
+	"maps"
 	"sync"
 	"time"
 
@@ -23,6 +24,21 @@ type ReconcileCache struct {
 	// StorageSet is the set of entity keys present in storage.
 	StorageSet map[string]struct{}
 
+	// Misplaced maps the keys whose storage object is outside its canonical path to
+	// that object (see RecordMisplaced).
+	Misplaced map[string]string
+
+	// Modified maps keys to the last modification of their storage object, where the
+	// storage loader reported it (see RecordModified).
+	Modified map[string]time.Time
+
+	// Extra holds the index of each additional source (Spec.Sources) by source name.
+	Extra map[string]map[string]any
+
+	// Diagnostics holds the key conflicts and missing columns found while the indices
+	// were built (see RecordConflict and RecordMissingColumns).
+	Diagnostics Diagnostics
+
 	// Built is the timestamp when this cache was built.
 	Built time.Time
 
@@ -51,8 +67,14 @@ var globalCacheStore = &cacheStore{
 }
 
 // BuildCache builds a new cache for the given spec by loading all indices.
+// DB reads go to the read replica when one is registered (see SetReadReplica).
 // This function does NOT store the cache; use GetOrBuildCache for that.
 func BuildCache(ctx context.Context, spec *Spec, db *gorm.DB, client storage.Client, bucket string) (*ReconcileCache, error) {
+	return buildCache(ctx, spec, ReadDB(db), client, bucket)
+}
+
+// buildCache loads all indices, reading the database through db as given.
+func buildCache(ctx context.Context, spec *Spec, db *gorm.DB, client storage.Client, bucket string) (*ReconcileCache, error) {
 	var (
 		dbIndex    map[string]DBItem
 		gdIndex    map[string]GDItem
@@ -63,38 +85,69 @@ func BuildCache(ctx context.Context, spec *Spec, db *gorm.DB, client storage.Cli
 		wg         sync.WaitGroup
 	)
 
+	// Loaders report key conflicts and missing columns of their sources
+	ctx, diagnostics := WithDiagnostics(ctx)
+
 	// Build indices concurrently
 	// But first, verify storage is reachable to avoid hanging on retries.
 	{
 		liveCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
 		defer cancel()
-		if exists, err := client.BucketExists(liveCtx, bucket); err != nil {
-			return nil, fmt.Errorf("storage check failed (unreachable?): %w", err)
-		} else if !exists {
-			return nil, fmt.Errorf("storage bucket %s does not exist", bucket)
+		buckets := []string{bucket}
+		if gd := spec.gamedataBucket(bucket); gd != bucket && !IsGamedataURL(spec.GamedataObjectName) {
+			buckets = append(buckets, gd)
+		}
+		for _, name := range buckets {
+			if exists, err := client.BucketExists(liveCtx, name); err != nil {
+				return nil, fmt.Errorf("storage check failed (unreachable?): %w", err)
+			} else if !exists {
+				return nil, fmt.Errorf("storage bucket %s does not exist", name)
+			}
 		}
 	}
 
 	wg.Add(3)
+	progress := progressFrom(ctx)
+	indexed := func(source string, size int, err error) {
+		if err == nil {
+			progress.report(ProgressEvent{Stage: StageIndexed, Source: source, Processed: size})
+		}
+	}
 
 	// Build DB index
 	go func() {
 		defer wg.Done()
 		dbIndex, dbErr = spec.Adapter.LoadDBIndex(ctx, db, spec.ServerProfile)
+		indexed(SourceDB, len(dbIndex), dbErr)
 	}()
 
 	// Build gamedata index
 	go func() {
 		defer wg.Done()
-		gdIndex, gdErr = spec.Adapter.LoadGamedataIndex(ctx, client, bucket, spec.GamedataObjectName, spec.GamedataPaths)
+		gdIndex, gdErr = spec.Adapter.LoadGamedataIndex(ctx, spec.gamedataClient(client), spec.gamedataBucket(bucket), spec.GamedataObjectName, spec.GamedataPaths)
+		indexed(SourceGamedata, len(gdIndex), gdErr)
 	}()
 
 	// Build storage set
 	go func() {
 		defer wg.Done()
 		storageSet, storageErr = spec.Adapter.LoadStorageSet(ctx, client, bucket, spec.StoragePrefix, spec.StorageExtension)
+		indexed(SourceStorage, len(storageSet), storageErr)
 	}()
 
+	// Build additional source indices
+	extra := make(map[string]map[string]any, len(spec.Sources))
+	extraErrs := make([]error, len(spec.Sources))
+	extraIndices := make([]map[string]any, len(spec.Sources))
+	for i, source := range spec.Sources {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			extraIndices[i], extraErrs[i] = source.LoadIndex(ctx, db, client, bucket)
+			indexed(source.Name(), len(extraIndices[i]), extraErrs[i])
+		}()
+	}
+
 	wg.Wait()
 
 	// Check for errors
@@ -114,13 +167,30 @@ func BuildCache(ctx context.Context, spec *Spec, db *gorm.DB, client storage.Cli
 		return nil, storageErr
 	}
 
-	return &ReconcileCache{
+	for i, source := range spec.Sources {
+		if extraErrs[i] != nil {
+			return nil, fmt.Errorf("failed to load source %s: %w", source.Name(), extraErrs[i])
+		}
+		extra[source.Name()] = extraIndices[i]
+	}
+
+	cache := &ReconcileCache{
 		DBIndex:    dbIndex,
 		GDIndex:    gdIndex,
 		StorageSet: storageSet,
+		Misplaced:  diagnostics.Misplaced(),
+		Modified:   diagnostics.Modified(),
+		Extra:      extra,
 		Built:      time.Now(),
 		TTL:        spec.CacheTTL,
-	}, nil
+	}
+
+	// Near-miss keys of different sources must meet in the union
+	if normalizer, ok := spec.Adapter.(KeyNormalizer); ok {
+		normalizeCache(ctx, cache, normalizer)
+	}
+	cache.Diagnostics = diagnostics.Diagnostics()
+	return cache, nil
 }
 
 // GetOrBuildCache retrieves a cache for the given spec from the store,
@@ -129,12 +199,13 @@ func BuildCache(ctx context.Context, spec *Spec, db *gorm.DB, client storage.Cli
 func GetOrBuildCache(ctx context.Context, spec *Spec, db *gorm.DB, client storage.Client, bucket string) (*ReconcileCache, error) {
 	cacheKey := spec.CacheKey()
 
-	// Fast path: check if cache exists and is fresh
+	// Fast path: check if cache exists and is fresh. A spec with caching disabled
+	// never reuses indices, even ones another spec with the same key stored.
 	globalCacheStore.mu.RLock()
 	cache, exists := globalCacheStore.caches[cacheKey]
 	globalCacheStore.mu.RUnlock()
 
-	if exists && !cache.IsExpired() {
+	if exists && spec.CacheTTL > 0 && !cache.IsExpired() {
 		return cache, nil
 	}
 
@@ -145,7 +216,7 @@ func GetOrBuildCache(ctx context.Context, spec *Spec, db *gorm.DB, client storag
 		cache, exists := globalCacheStore.caches[cacheKey]
 		globalCacheStore.mu.RUnlock()
 
-		if exists && !cache.IsExpired() {
+		if exists && spec.CacheTTL > 0 && !cache.IsExpired() {
 			return cache, nil
 		}
 
@@ -170,6 +241,16 @@ func GetOrBuildCache(ctx context.Context, spec *Spec, db *gorm.DB, client storag
 	return result.(*ReconcileCache), nil
 }
 
+// storeCache replaces the stored cache for spec when caching is enabled.
+func storeCache(spec *Spec, cache *ReconcileCache) {
+	if spec.CacheTTL <= 0 {
+		return
+	}
+	globalCacheStore.mu.Lock()
+	globalCacheStore.caches[spec.CacheKey()] = cache
+	globalCacheStore.mu.Unlock()
+}
+
 // InvalidateCache removes the cache for the given spec from the store.
 // This is useful for testing or forcing a rebuild.
 func InvalidateCache(spec *Spec) {
@@ -178,3 +259,91 @@ func InvalidateCache(spec *Spec) {
 	delete(globalCacheStore.caches, cacheKey)
 	globalCacheStore.mu.Unlock()
 }
+
+// ApplyDeletions removes the keys of delete actions from the matching index, and
+// moved keys from Misplaced. Downloaded keys are added to StorageSet. Other action
+// types are ignored. CacheUpdater implementations can call it for the actions they
+// do not need to handle specially.
+func (c *ReconcileCache) ApplyDeletions(actions []Action) {
+	for _, action := range actions {
+		switch action.Type {
+		case ActionDeleteDB:
+			delete(c.DBIndex, action.Key)
+		case ActionDeleteGamedata:
+			delete(c.GDIndex, action.Key)
+		case ActionDeleteStorage:
+			delete(c.StorageSet, action.Key)
+			delete(c.Misplaced, action.Key)
+		case ActionMoveStorage:
+			delete(c.Misplaced, action.Key)
+		case ActionDownloadStorage:
+			c.StorageSet[action.Key] = struct{}{}
+		}
+	}
+}
+
+// PatchCache applies executed actions to the stored cache for spec, so targeted
+// lookups stay warm after mutations instead of rebuilding every index.
+// The patched indices are copies; readers holding the previous cache are unaffected.
+//
+// Adapters implementing CacheUpdater translate the actions themselves; otherwise
+// deletions are applied and any other action invalidates the cache.
+// It is a no-op when no fresh cache is stored.
+func PatchCache(spec *Spec, actions []Action) {
+	cacheKey := spec.CacheKey()
+
+	globalCacheStore.mu.RLock()
+	cache, exists := globalCacheStore.caches[cacheKey]
+	globalCacheStore.mu.RUnlock()
+
+	if !exists || cache.IsExpired() || len(actions) == 0 {
+		return
+	}
+
+	patched := &ReconcileCache{
+		DBIndex:     maps.Clone(cache.DBIndex),
+		GDIndex:     maps.Clone(cache.GDIndex),
+		StorageSet:  maps.Clone(cache.StorageSet),
+		Misplaced:   maps.Clone(cache.Misplaced),
+		Modified:    maps.Clone(cache.Modified),
+		Extra:       make(map[string]map[string]any, len(cache.Extra)),
+		Diagnostics: cache.Diagnostics,
+		Built:       cache.Built,
+		TTL:         cache.TTL,
+	}
+	for name, index := range cache.Extra {
+		patched.Extra[name] = maps.Clone(index)
+	}
+
+	if updater, ok := spec.Adapter.(CacheUpdater); ok {
+		if err := updater.UpdateCache(patched, actions); err != nil {
+			InvalidateCache(spec)
+			return
+		}
+	} else {
+		for _, action := range actions {
+			if !isPresenceChange(action.Type) {
+				InvalidateCache(spec)
+				return
+			}
+		}
+		patched.ApplyDeletions(actions)
+	}
+
+	// Only swap if nobody rebuilt the cache while we were patching
+	globalCacheStore.mu.Lock()
+	if globalCacheStore.caches[cacheKey] == cache {
+		globalCacheStore.caches[cacheKey] = patched
+	}
+	globalCacheStore.mu.Unlock()
+}
+
+// isPresenceChange reports whether an action type only changes which stores hold an
+// entity (or, for moves, where), which ApplyDeletions patches without the adapter.
+func isPresenceChange(actionType ActionType) bool {
+	switch actionType {
+	case ActionDeleteDB, ActionDeleteGamedata, ActionDeleteStorage, ActionMoveStorage, ActionDownloadStorage:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,169 @@
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"asset-manager/core/storage/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// mockCacheUpdater implements CacheUpdater, copying gamedata over the DB item on sync.
+type mockCacheUpdater struct {
+	mockMutator
+	err error
+}
+
+func (m *mockCacheUpdater) UpdateCache(cache *ReconcileCache, actions []Action) error {
+	if m.err != nil {
+		return m.err
+	}
+	for _, action := range actions {
+		if action.Type == ActionSyncDB {
+			cache.DBIndex[action.Key] = action.GDItem
+		}
+	}
+	cache.ApplyDeletions(actions)
+	return nil
+}
+
+// storedCache returns the cache currently held for spec, if any.
+func storedCache(spec *Spec) *ReconcileCache {
+	globalCacheStore.mu.RLock()
+	defer globalCacheStore.mu.RUnlock()
+	return globalCacheStore.caches[spec.CacheKey()]
+}
+
+// TestApplyPlan_PatchesCache tests that applied actions update cached indices without a rebuild.
+func TestApplyPlan_PatchesCache(t *testing.T) {
+	tests := []struct {
+		name        string
+		adapter     Adapter
+		actions     []Action
+		invalidated bool
+		check       func(t *testing.T, cache *ReconcileCache)
+	}{
+		{
+			name: "deletions drop keys from their index",
+			adapter: &mockMutator{mockAdapter: mockAdapter{
+				dbIndex:    map[string]DBItem{"1": "1", "2": "2"},
+				gdIndex:    map[string]GDItem{"2": "2", "3": "3"},
+				storageSet: map[string]struct{}{"2": {}, "4": {}},
+			}},
+			actions: []Action{
+				{Type: ActionDeleteDB, Key: "1"},
+				{Type: ActionDeleteGamedata, Key: "3"},
+				{Type: ActionDeleteStorage, Key: "4"},
+			},
+			check: func(t *testing.T, cache *ReconcileCache) {
+				assert.Equal(t, map[string]DBItem{"2": "2"}, cache.DBIndex)
+				assert.Equal(t, map[string]GDItem{"2": "2"}, cache.GDIndex)
+				assert.Equal(t, map[string]struct{}{"2": {}}, cache.StorageSet)
+			},
+		},
+		{
+			name: "cache updater translates sync actions",
+			adapter: &mockCacheUpdater{mockMutator: mockMutator{mockAdapter: mockAdapter{
+				dbIndex: map[string]DBItem{"1": "old", "2": "2"},
+				gdIndex: map[string]GDItem{"1": "new"},
+			}}},
+			actions: []Action{
+				{Type: ActionSyncDB, Key: "1", GDItem: "new"},
+				{Type: ActionDeleteDB, Key: "2"},
+			},
+			check: func(t *testing.T, cache *ReconcileCache) {
+				assert.Equal(t, map[string]DBItem{"1": "new"}, cache.DBIndex)
+			},
+		},
+		{
+			name: "cache updater error invalidates",
+			adapter: &mockCacheUpdater{mockMutator: mockMutator{mockAdapter: mockAdapter{
+				dbIndex: map[string]DBItem{"1": "old"},
+			}}, err: errors.New("cannot patch")},
+			actions:     []Action{{Type: ActionDeleteDB, Key: "1"}},
+			invalidated: true,
+		},
+		{
+			name:    "moves drop keys from the misplaced objects",
+			adapter: newMisplacedMover(),
+			actions: []Action{{Type: ActionMoveStorage, Key: "2", From: "furniture/old/2.nitro"}},
+			check: func(t *testing.T, cache *ReconcileCache) {
+				assert.Empty(t, cache.Misplaced)
+				assert.Len(t, cache.StorageSet, 2)
+			},
+		},
+		{
+			name: "sync invalidates without a cache updater",
+			adapter: &mockMutator{mockAdapter: mockAdapter{
+				dbIndex: map[string]DBItem{"1": "old"},
+				gdIndex: map[string]GDItem{"1": "new"},
+			}},
+			actions:     []Action{{Type: ActionSyncDB, Key: "1", GDItem: "new"}},
+			invalidated: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := &Spec{Adapter: tt.adapter, CacheTTL: time.Minute, StoragePrefix: t.Name()}
+			defer InvalidateCache(spec)
+
+			mockClient := new(mocks.Client)
+			mockClient.On("BucketExists", mock.Anything, "").Return(true, nil)
+
+			before, err := GetOrBuildCache(context.Background(), spec, nil, mockClient, "")
+			require.NoError(t, err)
+			dbEntries := len(before.DBIndex)
+
+			plan := &ReconcilePlan{Actions: tt.actions}
+			opts := ReconcileOptions{Confirmed: true}
+			_, err = ApplyPlan(context.Background(), spec, nil, mockClient, "", plan, opts)
+			require.NoError(t, err)
+
+			after := storedCache(spec)
+			if tt.invalidated {
+				assert.Nil(t, after)
+				return
+			}
+			require.NotNil(t, after)
+			assert.NotSame(t, before, after)
+			assert.Equal(t, before.Built, after.Built)
+			assert.Len(t, before.DBIndex, dbEntries, "previous cache must not be mutated")
+			tt.check(t, after)
+		})
+	}
+}
+
+// TestGetOrBuildCache_DisabledSpecRebuilds tests that a spec without caching never reuses
+// indices cached by another spec with the same cache key.
+func TestGetOrBuildCache_DisabledSpecRebuilds(t *testing.T) {
+	loads := 0
+	adapter := &mockAdapter{
+		dbLoadFunc: func(ctx context.Context, db *gorm.DB, profile string) (map[string]DBItem, error) {
+			loads++
+			return map[string]DBItem{}, nil
+		},
+	}
+	cached := &Spec{Adapter: adapter, CacheTTL: time.Minute, StoragePrefix: t.Name()}
+	uncached := &Spec{Adapter: adapter, StoragePrefix: t.Name()}
+	defer InvalidateCache(cached)
+
+	mockClient := new(mocks.Client)
+	mockClient.On("BucketExists", mock.Anything, "").Return(true, nil)
+
+	_, err := GetOrBuildCache(context.Background(), cached, nil, mockClient, "")
+	require.NoError(t, err)
+	_, err = GetOrBuildCache(context.Background(), cached, nil, mockClient, "")
+	require.NoError(t, err)
+	assert.Equal(t, 1, loads)
+
+	_, err = GetOrBuildCache(context.Background(), uncached, nil, mockClient, "")
+	require.NoError(t, err)
+	assert.Equal(t, 2, loads)
+}
@@ -0,0 +1,45 @@
+package reconcile
+
+import (
+	"context"
+	"slices"
+)
+
+// ColumnGap lists the columns an adapter maps but a source's table lacks, as in
+// emulator forks dropping optional columns. Fields read from them are not compared
+// (see PartialComparer) rather than reported as mismatching zero values.
+type ColumnGap struct {
+	// Source is the source the table belongs to, usually SourceDB.
+	Source string `json:"source"`
+
+	// Table is the table lacking the columns.
+	Table string `json:"table"`
+
+	// Columns are the missing column names.
+	Columns []string `json:"columns"`
+}
+
+// RecordMissingColumns reports that table of source lacks the given mapped columns.
+// Adapters call it from their index loaders once per run; repeated reports of one
+// table are merged. It is a no-op when ctx carries no recorder (see WithDiagnostics).
+func RecordMissingColumns(ctx context.Context, source, table string, columns ...string) {
+	if recorder := recorderFrom(ctx); recorder != nil && len(columns) > 0 {
+		recorder.recordGap(source, table, columns)
+	}
+}
+
+// recordGap merges columns into the gap of source and table.
+func (r *DiagnosticsRecorder) recordGap(source, table string, columns []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	gap, ok := r.gaps[[2]string{source, table}]
+	if !ok {
+		gap = &ColumnGap{Source: source, Table: table}
+		r.gaps[[2]string{source, table}] = gap
+	}
+	for _, column := range columns {
+		if !slices.Contains(gap.Columns, column) {
+			gap.Columns = append(gap.Columns, column)
+		}
+	}
+}
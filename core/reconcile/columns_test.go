@@ -0,0 +1,50 @@
+package reconcile
+
+import (
+	"context"
+	"testing"
+
+	"asset-manager/core/storage/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// partialAdapter is a mock adapter that could not compare the width of any item.
+type partialAdapter struct {
+	*mockAdapter
+}
+
+func (a *partialAdapter) NotComparable(dbItem DBItem, gdItem GDItem) []string {
+	return []string{"width"}
+}
+
+// TestReconcileWithPlan_MissingColumns tests that missing columns reach the plan and
+// that fields left out of the comparison are reported on each result.
+func TestReconcileWithPlan_MissingColumns(t *testing.T) {
+	adapter := &partialAdapter{&mockAdapter{
+		gdIndex:    map[string]GDItem{"1": "1", "2": "2"},
+		storageSet: map[string]struct{}{"1": {}},
+		mismatches: map[string][]string{},
+		dbLoadFunc: func(ctx context.Context, _ *gorm.DB, _ string) (map[string]DBItem, error) {
+			RecordMissingColumns(ctx, SourceDB, "items_base", "width")
+			return map[string]DBItem{"1": "1"}, nil
+		},
+	}}
+	mockClient := new(mocks.Client)
+	mockClient.On("BucketExists", mock.Anything, "").Return(true, nil)
+
+	plan, err := ReconcileWithPlan(context.Background(), &Spec{Adapter: adapter}, nil, mockClient, "", ReconcileOptions{})
+	require.NoError(t, err)
+	require.NotNil(t, plan.Diagnostics)
+	assert.Empty(t, plan.Diagnostics.KeyConflicts)
+	assert.Equal(t, []ColumnGap{{Source: SourceDB, Table: "items_base", Columns: []string{"width"}}}, plan.Diagnostics.MissingColumns)
+
+	require.Len(t, plan.Results, 2)
+	assert.Equal(t, []string{"width"}, plan.Results[0].NotComparable)
+	assert.Empty(t, plan.Results[0].Mismatch)
+	// Only items present on both sides are compared
+	assert.Nil(t, plan.Results[1].NotComparable)
+}
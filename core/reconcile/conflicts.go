@@ -0,0 +1,46 @@
+package reconcile
+
+import (
+	"context"
+	"slices"
+)
+
+// KeyConflict is a key that several distinct entities of one source map to, e.g. two
+// furniture rows sharing a sprite_id. An index holds one entity per key, so all but
+// one of them are silently left out of the reconciliation.
+type KeyConflict struct {
+	// Source is the source holding the entities (SourceDB, SourceGamedata, SourceStorage
+	// or the name of an additional source).
+	Source string `json:"source"`
+
+	// Key is the entity key the entities collide on.
+	Key string `json:"key"`
+
+	// Entities describes each colliding entity, e.g. "id=12 item_name=chair".
+	Entities []string `json:"entities"`
+}
+
+// RecordConflict reports that the given entities of source all map to key. Adapters
+// call it from their index loaders when a key is already taken; repeated reports of
+// one key are merged. It is a no-op when ctx carries no recorder (see WithDiagnostics).
+func RecordConflict(ctx context.Context, source, key string, entities ...string) {
+	if recorder := recorderFrom(ctx); recorder != nil {
+		recorder.recordConflict(source, key, entities)
+	}
+}
+
+// recordConflict merges entities into the conflict of source and key.
+func (r *DiagnosticsRecorder) recordConflict(source, key string, entities []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	conflict, ok := r.conflicts[[2]string{source, key}]
+	if !ok {
+		conflict = &KeyConflict{Source: source, Key: key}
+		r.conflicts[[2]string{source, key}] = conflict
+	}
+	for _, entity := range entities {
+		if !slices.Contains(conflict.Entities, entity) {
+			conflict.Entities = append(conflict.Entities, entity)
+		}
+	}
+}
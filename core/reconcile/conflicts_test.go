@@ -0,0 +1,111 @@
+package reconcile
+
+import (
+	"context"
+	"testing"
+
+	"asset-manager/core/storage/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// TestDiagnosticsRecorder tests merging of repeated reports and the order of the findings.
+func TestDiagnosticsRecorder(t *testing.T) {
+	// Without a recorder the reports are dropped
+	RecordConflict(context.Background(), SourceDB, "1", "a", "b")
+	RecordMissingColumns(context.Background(), SourceDB, "items_base", "width")
+
+	ctx, recorder := WithDiagnostics(context.Background())
+	assert.True(t, recorder.Diagnostics().Empty())
+
+	RecordConflict(ctx, SourceStorage, "7", "x.nitro", "sub/x.nitro")
+	RecordConflict(ctx, SourceDB, "9", "id=1", "id=2")
+	RecordConflict(ctx, SourceDB, "10", "id=3", "id=4")
+	RecordConflict(ctx, SourceDB, "9", "id=2", "id=5")
+	RecordMissingColumns(ctx, SourceDB, "items_base", "width", "allow_lay")
+	RecordMissingColumns(ctx, SourceDB, "items_base", "width")
+	RecordMissingColumns(ctx, SourceDB, "catalog_items")
+
+	diagnostics := recorder.Diagnostics()
+	assert.Equal(t, []KeyConflict{
+		{Source: SourceDB, Key: "10", Entities: []string{"id=3", "id=4"}},
+		{Source: SourceDB, Key: "9", Entities: []string{"id=1", "id=2", "id=5"}},
+		{Source: SourceStorage, Key: "7", Entities: []string{"x.nitro", "sub/x.nitro"}},
+	}, diagnostics.KeyConflicts)
+	assert.Equal(t, []ColumnGap{
+		{Source: SourceDB, Table: "items_base", Columns: []string{"allow_lay", "width"}},
+	}, diagnostics.MissingColumns)
+	assert.False(t, diagnostics.Empty())
+}
+
+// TestRecordDuplicateName tests merging of repeated reports of one name.
+func TestRecordDuplicateName(t *testing.T) {
+	ctx, recorder := WithDiagnostics(context.Background())
+	RecordDuplicateName(ctx, SourceGamedata, "chair", "100", "200")
+	RecordDuplicateName(ctx, SourceGamedata, "chair", "100", "300")
+	RecordDuplicateName(ctx, SourceGamedata, "bed", "5", "6")
+
+	diagnostics := recorder.Diagnostics()
+	assert.Equal(t, []DuplicateName{
+		{Source: SourceGamedata, Name: "bed", Keys: []string{"5", "6"}},
+		{Source: SourceGamedata, Name: "chair", Keys: []string{"100", "200", "300"}},
+	}, diagnostics.DuplicateNames)
+	assert.False(t, diagnostics.Empty())
+}
+
+// TestReconcileWithPlan_Diagnostics tests that conflicts reported by the loaders reach the plan.
+func TestReconcileWithPlan_Diagnostics(t *testing.T) {
+	adapter := &mockAdapter{
+		gdIndex:    map[string]GDItem{"1": "1"},
+		storageSet: map[string]struct{}{},
+		mismatches: map[string][]string{},
+		dbLoadFunc: func(ctx context.Context, _ *gorm.DB, _ string) (map[string]DBItem, error) {
+			RecordConflict(ctx, SourceDB, "1", "id=10 item_name=chair", "id=11 item_name=chair_b")
+			return map[string]DBItem{"1": "1"}, nil
+		},
+	}
+	mockClient := new(mocks.Client)
+	mockClient.On("BucketExists", mock.Anything, "").Return(true, nil)
+
+	plan, err := ReconcileWithPlan(context.Background(), &Spec{Adapter: adapter}, nil, mockClient, "", ReconcileOptions{})
+	require.NoError(t, err)
+	require.NotNil(t, plan.Diagnostics)
+	assert.Equal(t, 1, plan.Summary.KeyConflicts)
+	assert.Equal(t, []KeyConflict{
+		{Source: SourceDB, Key: "1", Entities: []string{"id=10 item_name=chair", "id=11 item_name=chair_b"}},
+	}, plan.Diagnostics.KeyConflicts)
+
+	// A clean run carries no diagnostics
+	adapter.dbLoadFunc = nil
+	adapter.dbIndex = map[string]DBItem{"1": "1"}
+	plan, err = ReconcileWithPlan(context.Background(), &Spec{Adapter: adapter}, nil, mockClient, "", ReconcileOptions{})
+	require.NoError(t, err)
+	assert.Nil(t, plan.Diagnostics)
+	assert.Zero(t, plan.Summary.KeyConflicts)
+}
+
+// TestBuildCache_NormalizationConflicts tests that raw keys merged by normalization are reported.
+func TestBuildCache_NormalizationConflicts(t *testing.T) {
+	adapter := &zeroTrimAdapter{&mockAdapter{
+		dbIndex:    map[string]DBItem{"7": "7", "07": "07", "007": "007"},
+		gdIndex:    map[string]GDItem{"7": "7"},
+		storageSet: map[string]struct{}{"Chair": {}, "chair": {}},
+		mismatches: map[string][]string{},
+	}}
+	mockClient := new(mocks.Client)
+	mockClient.On("BucketExists", mock.Anything, "").Return(true, nil)
+
+	cache, err := BuildCache(context.Background(), &Spec{Adapter: adapter}, nil, mockClient, "")
+	require.NoError(t, err)
+	require.Len(t, cache.Diagnostics.KeyConflicts, 2)
+
+	assert.Equal(t, SourceDB, cache.Diagnostics.KeyConflicts[0].Source)
+	assert.Equal(t, "7", cache.Diagnostics.KeyConflicts[0].Key)
+	assert.ElementsMatch(t, []string{"7", "07", "007"}, cache.Diagnostics.KeyConflicts[0].Entities)
+	assert.Equal(t, SourceStorage, cache.Diagnostics.KeyConflicts[1].Source)
+	assert.Equal(t, "chair", cache.Diagnostics.KeyConflicts[1].Key)
+	assert.ElementsMatch(t, []string{"Chair", "chair"}, cache.Diagnostics.KeyConflicts[1].Entities)
+}
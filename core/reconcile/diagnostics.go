@@ -0,0 +1,107 @@
+package reconcile
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Diagnostics holds findings about the sources themselves rather than the entities
+// reconciled, which may make the results incomplete.
+type Diagnostics struct {
+	// KeyConflicts lists keys several entities of one source map to, by source and key.
+	KeyConflicts []KeyConflict `json:"key_conflicts"`
+
+	// MissingColumns lists the mapped columns absent from a source's table, by source
+	// and table.
+	MissingColumns []ColumnGap `json:"missing_columns,omitempty"`
+
+	// DuplicateNames lists names several entities of one source carry under different
+	// keys, by source and name.
+	DuplicateNames []DuplicateName `json:"duplicate_names,omitempty"`
+}
+
+// Empty reports whether nothing was found.
+func (d Diagnostics) Empty() bool {
+	return len(d.KeyConflicts) == 0 && len(d.MissingColumns) == 0 && len(d.DuplicateNames) == 0
+}
+
+// DiagnosticsRecorder collects the diagnostics found while indices are built. It is
+// safe for concurrent use by the index loaders.
+type DiagnosticsRecorder struct {
+	mu         sync.Mutex
+	conflicts  map[[2]string]*KeyConflict
+	gaps       map[[2]string]*ColumnGap
+	duplicates map[[2]string]*DuplicateName
+	misplaced  map[string]string
+	modified   map[string]time.Time
+}
+
+// diagnosticsRecorderKey is the context key of the active DiagnosticsRecorder.
+type diagnosticsRecorderKey struct{}
+
+// WithDiagnostics returns a context under which RecordConflict, RecordMissingColumns,
+// RecordDuplicateName, RecordMisplaced and RecordModified collect their findings into the returned recorder.
+func WithDiagnostics(ctx context.Context) (context.Context, *DiagnosticsRecorder) {
+	recorder := &DiagnosticsRecorder{
+		conflicts:  make(map[[2]string]*KeyConflict),
+		gaps:       make(map[[2]string]*ColumnGap),
+		duplicates: make(map[[2]string]*DuplicateName),
+		misplaced:  make(map[string]string),
+		modified:   make(map[string]time.Time),
+	}
+	return context.WithValue(ctx, diagnosticsRecorderKey{}, recorder), recorder
+}
+
+// recorderFrom returns the DiagnosticsRecorder of ctx, or nil.
+func recorderFrom(ctx context.Context) *DiagnosticsRecorder {
+	recorder, _ := ctx.Value(diagnosticsRecorderKey{}).(*DiagnosticsRecorder)
+	return recorder
+}
+
+// Diagnostics returns the recorded findings, each list sorted by source, then by key,
+// table or name.
+func (r *DiagnosticsRecorder) Diagnostics() Diagnostics {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var d Diagnostics
+	for _, conflict := range r.conflicts {
+		c := *conflict
+		c.Entities = slices.Clone(conflict.Entities)
+		d.KeyConflicts = append(d.KeyConflicts, c)
+	}
+	slices.SortFunc(d.KeyConflicts, func(a, b KeyConflict) int {
+		if n := strings.Compare(a.Source, b.Source); n != 0 {
+			return n
+		}
+		return strings.Compare(a.Key, b.Key)
+	})
+
+	for _, gap := range r.gaps {
+		g := *gap
+		g.Columns = slices.Sorted(slices.Values(gap.Columns))
+		d.MissingColumns = append(d.MissingColumns, g)
+	}
+	slices.SortFunc(d.MissingColumns, func(a, b ColumnGap) int {
+		if n := strings.Compare(a.Source, b.Source); n != 0 {
+			return n
+		}
+		return strings.Compare(a.Table, b.Table)
+	})
+
+	for _, duplicate := range r.duplicates {
+		n := *duplicate
+		n.Keys = slices.Clone(duplicate.Keys)
+		d.DuplicateNames = append(d.DuplicateNames, n)
+	}
+	slices.SortFunc(d.DuplicateNames, func(a, b DuplicateName) int {
+		if n := strings.Compare(a.Source, b.Source); n != 0 {
+			return n
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return d
+}
@@ -19,6 +19,8 @@
 //    and gamedata structures (multiple JSON paths).
 //
 // 3. Cache: TTL-based caching layer with stampede protection for fast targeted queries.
+//    After ApplyPlan, the mutated keys are patched into the cached indices instead of
+//    discarding them; adapters implement CacheUpdater to translate their own actions.
 //
 // # Performance
 //
@@ -27,6 +29,7 @@
 //   - Single-pass storage listing (no per-item HEAD calls)
 //   - Batch DB queries (no row-by-row iteration)
 //   - Efficient union operations over in-memory maps
+//   - Results built across GOMAXPROCS workers for large unions, in key order
 //
 // # Usage Example
 //
@@ -42,6 +45,105 @@
 //	// Targeted reconciliation (uses cache)
 //	result, err := reconcile.ReconcileOne(ctx, spec, db, storageClient, bucket, query)
 //
+// # Additional Sources
+//
+// DB, gamedata and storage are the default sources. Domains that need more (e.g. a
+// catalog table, icon set or CDN manifest) implement Source and list it in Spec.Sources:
+//
+//	spec.Sources = []reconcile.Source{catalogSource}
+//
+// Additional sources join the key union, appear in ReconcileResult.Sources, count
+// towards health (flapping) and are summarized in PlanSummary.MissingSources.
+// They are report-only: purge and sync act on the default three.
+//
+// # Key Normalization
+//
+// Adapters whose sources spell the same key differently (padding, case) implement
+// KeyNormalizer. Every index is then re-keyed before the union is built, so "007" in
+// gamedata and "7" in the database are one entity instead of two phantom entries.
+//
+// # Key Conflicts
+//
+// An index holds one entity per key, so entities of one source sharing a key would be
+// merged silently. Loaders report such collisions with RecordConflict, and keys merged
+// by a KeyNormalizer are reported too. ReconcileWithPlan lists them in
+// ReconcilePlan.Diagnostics and counts them in PlanSummary.KeyConflicts.
+//
+// The reverse, one name carried by entities under different keys, breaks lookups by
+// name such as classname-to-ID. Loaders report it with RecordDuplicateName; it is
+// listed in ReconcilePlan.Diagnostics and counted in PlanSummary.DuplicateNames.
+//
+// # Missing Columns
+//
+// Loaders report mapped columns their table lacks with RecordMissingColumns; they are
+// listed in ReconcilePlan.Diagnostics too. Adapters implementing PartialComparer skip
+// the fields of such columns, which end up in ReconcileResult.NotComparable instead of
+// being reported as mismatches.
+//
+// # Severity
+//
+// Adapters implementing SeverityRater rate some fields at SeverityWarning. Their
+// mismatches land in ReconcileResult.Warnings rather than Mismatch, so they do not
+// count as issues, and syncs plan them only for the fields set with SetSyncedWarnings.
+//
+// # Run History
+//
+// With a RunStore registered through SetRunStore, ReconcileWithPlan stores every full
+// run: its summary and the issues (ResultIssues) of each entity that has any. FindRun
+// selects a stored run by ID or time, and DiffRuns lists the entities that broke, were
+// fixed or changed between two runs.
+//
+// # Plan Status
+//
+// With a PlanStatusStore registered through SetPlanStatusStore, ApplyPlan stores the
+// outcome of every applied plan: the actions that ran, failed or were never reached.
+// FindPlanStatus returns it, so an apply that stopped midway can be resumed.
+//
+// # Status Object
+//
+// With SetStatusObject, ReconcileWithPlan also merges a small Status (run time, counts
+// and a healthy flag per adapter) into an object of the gamedata bucket, so release
+// pipelines can gate on it without calling the API. Failures to write it are logged.
+//
+// # Run IDs
+//
+// StartRun tags a context with a unique run ID, keeping one it already has. Plans,
+// stored runs, audit entries, backups and status entries record the run ID of their
+// context, so every artifact of one CLI command, HTTP request or scheduled job can be
+// correlated. ReconcileWithPlan, PlanAll and ApplySafeFixes start a run when
+// their context has none.
+//
+// # Search
+//
+// Search finds entities by a partial or misspelled query over the cached indices,
+// ranking exact, prefix, substring and near-miss matches. It matches keys, names and
+// metadata, or the terms of adapters implementing Searchable.
+//
+// # Progress
+//
+// A context from WithProgress makes ReconcileAll and ReconcileWithPlan report each
+// stage (indexes, each index loaded, union, every ProgressInterval results, summary)
+// to a ProgressFunc, e.g. to stream it to a frontend or draw CLI progress bars.
+// Adapters report listed storage objects with ReportListed. ApplyPlan reports the
+// actions executed, which batch mutators report one at a time with ReportApplied.
+//
+// # Run Lock
+//
+// Mutating runs take AcquireRunLock, a lock object in the bucket, so CLI runs and
+// server instances never apply actions to the same hotel concurrently. A held lock
+// surfaces as a *LockedError naming its owner and start time. The holder renews the
+// lock in the background; takeovers and releases are conditioned on the lock object's
+// ETag, so a lock taken over is never released by its previous holder.
+//
+// # Plan Signatures
+//
+// PlanFile.Sign sets an HMAC-SHA256 of the file's version, adapter, options, data
+// hash, plan ID and actions, keyed by the API key, and CheckSignature refuses a file
+// where any of them changed since. Results, summaries and the sync source items are
+// not covered. NewPlanFile signs every plan it saves, and reconcile furniture apply
+// checks the signature of the loaded file before replanning, so an edited plan file
+// is refused.
+//
 // # Creating Adapters
 //
 // To support a new model (e.g., effects, clothing), implement the Adapter interface
@@ -0,0 +1,48 @@
+package reconcile
+
+import (
+	"context"
+	"slices"
+)
+
+// DuplicateName is a name several entities of one source carry under different keys,
+// e.g. two gamedata items sharing a classname. Lookups by name, such as resolving a
+// storage file to its key, find only one of them; the others look unrelated to their
+// files.
+type DuplicateName struct {
+	// Source is the source holding the entities.
+	Source string `json:"source"`
+
+	// Name is the name the entities share.
+	Name string `json:"name"`
+
+	// Keys are the keys of the entities, in load order. Lookups by name resolve to the
+	// last one.
+	Keys []string `json:"keys"`
+}
+
+// RecordDuplicateName reports that the entities of source under keys all carry name.
+// Adapters call it from their index loaders when a name already maps to another key;
+// repeated reports of one name are merged. It is a no-op when ctx carries no recorder
+// (see WithDiagnostics).
+func RecordDuplicateName(ctx context.Context, source, name string, keys ...string) {
+	if recorder := recorderFrom(ctx); recorder != nil {
+		recorder.recordDuplicateName(source, name, keys)
+	}
+}
+
+// recordDuplicateName merges keys into the duplicate of source and name.
+func (r *DiagnosticsRecorder) recordDuplicateName(source, name string, keys []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	duplicate, ok := r.duplicates[[2]string{source, name}]
+	if !ok {
+		duplicate = &DuplicateName{Source: source, Name: name}
+		r.duplicates[[2]string{source, name}] = duplicate
+	}
+	for _, key := range keys {
+		if !slices.Contains(duplicate.Keys, key) {
+			duplicate.Keys = append(duplicate.Keys, key)
+		}
+	}
+}
@@ -2,7 +2,10 @@ package reconcile
 
 import (
 	"context"
-	"sort"
+	"fmt"
+	"runtime"
+	"slices"
+	"sync"
 
 	"asset-manager/core/storage"
 
@@ -13,33 +16,49 @@ import (
 // It builds indices from all three sources, computes the union of keys,
 // and returns a result for each key indicating presence and mismatches.
 func ReconcileAll(ctx context.Context, spec *Spec, db *gorm.DB, client storage.Client, bucket string) ([]ReconcileResult, error) {
+	progress := progressFrom(ctx)
+	progress.report(ProgressEvent{Stage: StageIndexes})
+
 	// Build cache (which loads all indices concurrently)
 	cache, err := BuildCache(ctx, spec, db, client, bucket)
 	if err != nil {
 		return nil, err
 	}
 
-	// Build union of all keys
-	unionKeys := buildUnion(cache.DBIndex, cache.GDIndex, cache.StorageSet, spec.Adapter)
+	results := buildResults(cache, spec.Adapter, progress)
 
-	// Build results for each key
-	results := make([]ReconcileResult, 0, len(unionKeys))
-	for key := range unionKeys {
-		result := buildResult(key, cache.DBIndex, cache.GDIndex, cache.StorageSet, spec.Adapter)
-		results = append(results, result)
+	// Track health across runs for flapping detection
+	if err := RecordRun(ctx, spec.Adapter.Name(), results); err != nil {
+		return nil, err
 	}
 
-	// Sort results by key for deterministic output
-	sort.Slice(results, func(i, j int) bool {
-		return results[i].ID < results[j].ID
-	})
-
+	if progress != nil {
+		summary := Summarize(results)
+		progress.report(ProgressEvent{Stage: StageSummary, Summary: &summary})
+	}
 	return results, nil
 }
 
 // ReconcileOne performs a targeted reconciliation for a single entity.
 // It uses cached indices if available, or performs targeted queries.
+// The result carries the entity's triage when a triage store is registered.
 func ReconcileOne(ctx context.Context, spec *Spec, db *gorm.DB, client storage.Client, bucket string, query Query) (*ReconcileResult, error) {
+	result, err := reconcileOne(ctx, spec, db, client, bucket, query)
+	if err != nil {
+		return nil, err
+	}
+	if result.ID != "" {
+		results := []ReconcileResult{*result}
+		if err := annotateTriage(ctx, spec.Adapter.Name(), results); err != nil {
+			return nil, err
+		}
+		result = &results[0]
+	}
+	return result, nil
+}
+
+// reconcileOne builds the result of a single entity.
+func reconcileOne(ctx context.Context, spec *Spec, db *gorm.DB, client storage.Client, bucket string, query Query) (*ReconcileResult, error) {
 	// Try to use cache if enabled
 	if spec.CacheTTL > 0 {
 		cache, err := GetOrBuildCache(ctx, spec, db, client, bucket)
@@ -47,7 +66,8 @@ func ReconcileOne(ctx context.Context, spec *Spec, db *gorm.DB, client storage.C
 			return nil, err
 		}
 
-		// Find the key from the query
+		// Find the key from the query, in the form the cached indices hold
+		query.ID = spec.normalizeKey(query.ID)
 		key := findKeyFromQuery(query, cache.DBIndex, cache.GDIndex, spec.Adapter)
 		if key == "" {
 			// Not found in cache
@@ -59,17 +79,18 @@ func ReconcileOne(ctx context.Context, spec *Spec, db *gorm.DB, client storage.C
 			}, nil
 		}
 
-		result := buildResult(key, cache.DBIndex, cache.GDIndex, cache.StorageSet, spec.Adapter)
+		result := buildResult(key, cache, spec.Adapter)
 		return &result, nil
 	}
 
 	// Fast path without cache: use targeted queries
+	db = ReadDB(db)
 	dbItem, err := spec.Adapter.QueryDB(ctx, db, spec.ServerProfile, query)
 	if err != nil {
 		return nil, err
 	}
 
-	gdItem, err := spec.Adapter.QueryGamedata(ctx, client, bucket, spec.GamedataObjectName, spec.GamedataPaths, query)
+	gdItem, err := spec.Adapter.QueryGamedata(ctx, spec.gamedataClient(client), spec.gamedataBucket(bucket), spec.GamedataObjectName, spec.GamedataPaths, query)
 	if err != nil {
 		return nil, err
 	}
@@ -84,6 +105,7 @@ func ReconcileOne(ctx context.Context, spec *Spec, db *gorm.DB, client storage.C
 		// No DB or GD item, use query ID as key
 		key = query.ID
 	}
+	key = spec.normalizeKey(key)
 
 	storagePresent := false
 	if key != "" {
@@ -103,47 +125,173 @@ func ReconcileOne(ctx context.Context, spec *Spec, db *gorm.DB, client storage.C
 		Mismatch:        []string{},
 	}
 
+	// Additional sources have no targeted lookup, so their indices are loaded in full
+	extra := make(map[string]map[string]any, len(spec.Sources))
+	for _, source := range spec.Sources {
+		index, err := source.LoadIndex(ctx, db, client, bucket)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load source %s: %w", source.Name(), err)
+		}
+		if normalizer, ok := spec.Adapter.(KeyNormalizer); ok {
+			index = normalizeIndex(index, normalizer.NormalizeKey, nil)
+		}
+		extra[source.Name()] = index
+	}
+	result.Sources = sourcePresence(key, dbItem != nil, gdItem != nil, storagePresent, extra)
+	markExpectedAbsent(&result, spec.Adapter, dbItem, gdItem)
+
 	if dbItem != nil && gdItem != nil {
-		result.Mismatch = spec.Adapter.CompareFields(dbItem, gdItem)
+		result.Mismatch, result.Warnings, result.NotComparable = compareFields(spec.Adapter, dbItem, gdItem)
 	}
 
 	return &result, nil
 }
 
-// buildUnion creates a union of all keys from DB, gamedata, and storage.
-func buildUnion(dbIndex map[string]DBItem, gdIndex map[string]GDItem, storageSet map[string]struct{}, adapter Adapter) map[string]struct{} {
-	union := make(map[string]struct{})
+// noMismatch is the Mismatch of every result without discrepancies. Results share
+// it; it has no capacity, so appending to one result's Mismatch never affects another.
+var noMismatch = []string{}
+
+// buildResults builds the result of every key in the cache, sorted by key for
+// deterministic output. Keys are sorted before the results are built, so sorting
+// moves strings rather than whole results. Progress is reported to progress, if any.
+func buildResults(cache *ReconcileCache, adapter Adapter, progress ProgressFunc) []ReconcileResult {
+	keys := buildUnion(cache)
+	slices.Sort(keys)
+	progress.report(ProgressEvent{Stage: StageUnion, Total: len(keys)})
+	return buildResultsOf(keys, cache, adapter, progress)
+}
+
+// parallelResultsMin is the number of keys from which results are built concurrently.
+// Below it, starting workers costs more than it saves.
+const parallelResultsMin = 4096
+
+// resultWorkers is the number of goroutines building results; 0 uses GOMAXPROCS.
+// Variable for testing purposes.
+var resultWorkers = 0
+
+// buildResultsOf builds the result of each key, in the order of keys. Large key sets
+// are split into one contiguous chunk per worker; each result only reads the cache,
+// and every worker writes its own part of the slice, so the output order is that of
+// keys however the work is scheduled. Every ProgressInterval results are reported to
+// progress, if any.
+func buildResultsOf(keys []string, cache *ReconcileCache, adapter Adapter, progress ProgressFunc) []ReconcileResult {
+	results := make([]ReconcileResult, len(keys))
+	counter := newResultCounter(progress, len(keys))
+
+	workers := resultWorkers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers == 1 || len(keys) < parallelResultsMin {
+		for i, key := range keys {
+			results[i] = buildResult(key, cache, adapter)
+			counter.add()
+		}
+		return results
+	}
+
+	chunk := (len(keys) + workers - 1) / workers
+	var wg sync.WaitGroup
+	for start := 0; start < len(keys); start += chunk {
+		end := min(start+chunk, len(keys))
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := start; i < end; i++ {
+				results[i] = buildResult(keys[i], cache, adapter)
+				counter.add()
+			}
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// buildUnion returns the union of all keys from DB, gamedata, storage and any additional
+// sources, in no particular order. Each key is taken from the first index holding it,
+// so the union needs no set of its own; it is sized for the largest index up front.
+func buildUnion(cache *ReconcileCache) []string {
+	size := max(len(cache.DBIndex), len(cache.GDIndex), len(cache.StorageSet))
+	for _, index := range cache.Extra {
+		size = max(size, len(index))
+	}
+	keys := make([]string, 0, size)
 
 	// Add DB keys
-	for key := range dbIndex {
-		union[key] = struct{}{}
+	for key := range cache.DBIndex {
+		keys = append(keys, key)
 	}
 
 	// Add gamedata keys
-	for key := range gdIndex {
-		union[key] = struct{}{}
+	for key := range cache.GDIndex {
+		if _, ok := cache.DBIndex[key]; !ok {
+			keys = append(keys, key)
+		}
 	}
 
 	// Add storage keys
-	for key := range storageSet {
-		union[key] = struct{}{}
+	for key := range cache.StorageSet {
+		_, inDB := cache.DBIndex[key]
+		_, inGD := cache.GDIndex[key]
+		if !inDB && !inGD {
+			keys = append(keys, key)
+		}
 	}
 
-	return union
+	// Add additional source keys
+	added := make([]map[string]any, 0, len(cache.Extra))
+	for _, index := range cache.Extra {
+		for key := range index {
+			if !cache.hasCoreKey(key) && !inAny(key, added) {
+				keys = append(keys, key)
+			}
+		}
+		added = append(added, index)
+	}
+
+	return keys
+}
+
+// hasCoreKey reports whether key is in the DB, gamedata or storage index.
+func (c *ReconcileCache) hasCoreKey(key string) bool {
+	if _, ok := c.DBIndex[key]; ok {
+		return true
+	}
+	if _, ok := c.GDIndex[key]; ok {
+		return true
+	}
+	_, ok := c.StorageSet[key]
+	return ok
+}
+
+// inAny reports whether key is in any of indices.
+func inAny(key string, indices []map[string]any) bool {
+	for _, index := range indices {
+		if _, ok := index[key]; ok {
+			return true
+		}
+	}
+	return false
 }
 
 // buildResult creates a ReconcileResult for a single key.
-func buildResult(key string, dbIndex map[string]DBItem, gdIndex map[string]GDItem, storageSet map[string]struct{}, adapter Adapter) ReconcileResult {
-	dbItem, dbPresent := dbIndex[key]
-	gdItem, gdPresent := gdIndex[key]
-	_, storagePresent := storageSet[key]
+func buildResult(key string, cache *ReconcileCache, adapter Adapter) ReconcileResult {
+	dbItem, dbPresent := cache.DBIndex[key]
+	gdItem, gdPresent := cache.GDIndex[key]
+	_, storagePresent := cache.StorageSet[key]
 
 	result := ReconcileResult{
 		ID:              key,
 		DBPresent:       dbPresent,
 		GamedataPresent: gdPresent,
 		StoragePresent:  storagePresent,
-		Mismatch:        []string{},
+		Misplaced:       cache.Misplaced[key],
+		Sources:         sourcePresence(key, dbPresent, gdPresent, storagePresent, cache.Extra),
+		Mismatch:        noMismatch,
+	}
+	if modified, ok := cache.Modified[key]; ok {
+		result.StorageModified = &modified
+		result.OrphanAge = classifyOrphan(result, modified, cache.Built)
 	}
 
 	// Resolve name and metadata
@@ -158,16 +306,44 @@ func buildResult(key string, dbIndex map[string]DBItem, gdIndex map[string]GDIte
 		}
 		result.Name = adapter.ResolveName(dbItemPtr, gdItemPtr)
 		result.Metadata = adapter.GetMetadata(dbItemPtr, gdItemPtr)
+		markExpectedAbsent(&result, adapter, dbItemPtr, gdItemPtr)
 	}
 
 	// Compare fields if both present
 	if dbPresent && gdPresent {
-		result.Mismatch = adapter.CompareFields(dbItem, gdItem)
+		mismatch, warnings, notComparable := compareFields(adapter, dbItem, gdItem)
+		if len(mismatch) > 0 {
+			result.Mismatch = mismatch
+		}
+		result.Warnings = warnings
+		result.NotComparable = notComparable
 	}
 
 	return result
 }
 
+// compareFields returns the mismatches of two items, with those of warning severity
+// apart for a SeverityRater, and for a PartialComparer the fields it could not compare.
+func compareFields(adapter Adapter, dbItem DBItem, gdItem GDItem) (mismatch, warnings, notComparable []string) {
+	mismatch, warnings = splitWarnings(adapter, adapter.CompareFields(dbItem, gdItem))
+	if partial, ok := adapter.(PartialComparer); ok {
+		notComparable = partial.NotComparable(dbItem, gdItem)
+	}
+	return mismatch, warnings, notComparable
+}
+
+// sourcePresence builds the per-source presence map for a key.
+func sourcePresence(key string, dbPresent, gdPresent, storagePresent bool, extra map[string]map[string]any) map[string]bool {
+	presence := make(map[string]bool, 3+len(extra))
+	presence[SourceDB] = dbPresent
+	presence[SourceGamedata] = gdPresent
+	presence[SourceStorage] = storagePresent
+	for name, index := range extra {
+		_, presence[name] = index[key]
+	}
+	return presence
+}
+
 // findKeyFromQuery attempts to find the entity key from a query using cached indices.
 func findKeyFromQuery(query Query, dbIndex map[string]DBItem, gdIndex map[string]GDItem, adapter Adapter) string {
 	// Try direct key match first
@@ -3,6 +3,8 @@ package reconcile
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
@@ -11,6 +13,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"gorm.io/gorm"
 )
 
@@ -517,3 +520,89 @@ func TestReconcileOne_NotFound(t *testing.T) {
 	// Cleanup
 	InvalidateCache(spec)
 }
+
+// TestBuildCache_GamedataBucket tests that gamedata is loaded from the spec's gamedata
+// bucket while storage is listed from the bucket passed to the call.
+func TestBuildCache_GamedataBucket(t *testing.T) {
+	var gdBucket, storageBucket string
+	adapter := &mockAdapter{
+		gdLoadFunc: func(_ context.Context, _ storage.Client, bucket, _ string, _ []string) (map[string]GDItem, error) {
+			gdBucket = bucket
+			return map[string]GDItem{}, nil
+		},
+		storageLoadFunc: func(_ context.Context, _ storage.Client, bucket, _, _ string) (map[string]struct{}, error) {
+			storageBucket = bucket
+			return map[string]struct{}{}, nil
+		},
+	}
+	spec := &Spec{Adapter: adapter, GamedataBucket: "gamedata"}
+
+	mockClient := new(mocks.Client)
+	mockClient.On("BucketExists", mock.Anything, "assets").Return(true, nil)
+	mockClient.On("BucketExists", mock.Anything, "gamedata").Return(true, nil)
+
+	_, err := BuildCache(context.Background(), spec, nil, mockClient, "assets")
+	assert.NoError(t, err)
+	assert.Equal(t, "gamedata", gdBucket)
+	assert.Equal(t, "assets", storageBucket)
+	mockClient.AssertExpectations(t)
+
+	t.Run("missing gamedata bucket", func(t *testing.T) {
+		mockClient := new(mocks.Client)
+		mockClient.On("BucketExists", mock.Anything, "assets").Return(true, nil)
+		mockClient.On("BucketExists", mock.Anything, "gamedata").Return(false, nil)
+
+		_, err := BuildCache(context.Background(), spec, nil, mockClient, "assets")
+		assert.ErrorContains(t, err, "storage bucket gamedata does not exist")
+	})
+}
+
+// TestBuildUnion tests that keys held by several indices appear once.
+func TestBuildUnion(t *testing.T) {
+	cache := &ReconcileCache{
+		DBIndex:    map[string]DBItem{"a": "a", "b": "b"},
+		GDIndex:    map[string]GDItem{"b": "b", "c": "c"},
+		StorageSet: map[string]struct{}{"a": {}, "c": {}, "d": {}},
+		Extra: map[string]map[string]any{
+			"figure": {"d": nil, "e": nil},
+			"badges": {"e": nil, "f": nil, "a": nil},
+		},
+	}
+
+	assert.ElementsMatch(t, []string{"a", "b", "c", "d", "e", "f"}, buildUnion(cache))
+	assert.Empty(t, buildUnion(&ReconcileCache{}))
+}
+
+// TestBuildResults_SortedAndSharedMismatch tests that results are sorted by key and
+// entities without discrepancies report an empty, not nil, Mismatch.
+func TestBuildResults_SortedAndSharedMismatch(t *testing.T) {
+	cache := &ReconcileCache{
+		DBIndex:    map[string]DBItem{"b": "b", "a": "a"},
+		GDIndex:    map[string]GDItem{"b": "b", "a": "a"},
+		StorageSet: map[string]struct{}{"c": {}},
+	}
+	adapter := &mockAdapter{mismatches: map[string][]string{"a": nil, "b": {"name differs"}}}
+
+	results := buildResults(cache, adapter, nil)
+	require.Len(t, results, 3)
+	assert.Equal(t, []string{"a", "b", "c"}, []string{results[0].ID, results[1].ID, results[2].ID})
+	assert.NotNil(t, results[0].Mismatch)
+	assert.Empty(t, results[0].Mismatch)
+	assert.Equal(t, []string{"name differs"}, results[1].Mismatch)
+	assert.Empty(t, results[2].Mismatch)
+}
+
+// TestBuildResults_Parallel tests that concurrent result building matches the serial order.
+func TestBuildResults_Parallel(t *testing.T) {
+	cache, adapter := benchCache(2 * parallelResultsMin)
+	defer func(workers int) { resultWorkers = workers }(resultWorkers)
+
+	resultWorkers = 1
+	serial := buildResults(cache, adapter, nil)
+	resultWorkers = 7
+	parallel := buildResults(cache, adapter, nil)
+
+	require.Len(t, parallel, len(serial))
+	assert.Equal(t, serial, parallel)
+	assert.True(t, slices.IsSortedFunc(parallel, func(a, b ReconcileResult) int { return strings.Compare(a.ID, b.ID) }))
+}
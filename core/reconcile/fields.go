@@ -0,0 +1,124 @@
+package reconcile
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"sync"
+)
+
+// FieldIgnore is the FieldPolicy source of fields that are neither compared nor synced.
+const FieldIgnore = "ignore"
+
+// FieldPolicy maps compared fields, named as in mismatches (e.g. "width"), to the
+// source whose value wins when they differ: SourceGamedata, SourceDB or FieldIgnore.
+// "dimensions" stands for width and length. Unlisted fields follow the sync direction.
+type FieldPolicy map[string]string
+
+// Validate reports entries naming an unknown source.
+func (p FieldPolicy) Validate() error {
+	fields := make([]string, 0, len(p))
+	for field := range p {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		switch p[field] {
+		case SourceGamedata, SourceDB, FieldIgnore:
+		default:
+			return fmt.Errorf("invalid sync source %q for field %s (valid: gamedata, db, ignore)", p[field], field)
+		}
+	}
+	return nil
+}
+
+// Source returns the source that wins field: the one listed, or the one direction
+// syncs from.
+func (p FieldPolicy) Source(field string, direction SyncDirection) string {
+	if source, ok := p[field]; ok {
+		return source
+	}
+	if field == "width" || field == "length" {
+		if source, ok := p["dimensions"]; ok {
+			return source
+		}
+	}
+	if direction == SyncDBToGamedata {
+		return SourceDB
+	}
+	return SourceGamedata
+}
+
+// writes reports which stores syncs under direction may write: the DB when gamedata
+// wins any field, and gamedata when the DB does.
+func (p FieldPolicy) writes(direction SyncDirection) (db, gamedata bool) {
+	db = direction != SyncDBToGamedata
+	gamedata = !db
+	for _, source := range p {
+		switch source {
+		case SourceGamedata:
+			db = true
+		case SourceDB:
+			gamedata = true
+		}
+	}
+	return db, gamedata
+}
+
+// fieldPolicyRegistry holds the field policy compares and syncs follow.
+type fieldPolicyRegistry struct {
+	mu     sync.RWMutex
+	policy FieldPolicy
+}
+
+// globalFieldPolicy is the singleton field policy for all reconcile operations.
+var globalFieldPolicy = &fieldPolicyRegistry{}
+
+// SetFieldPolicy sets the field policy adapters apply when comparing and syncing.
+// Nil lets every field follow the sync direction.
+func SetFieldPolicy(p FieldPolicy) {
+	globalFieldPolicy.mu.Lock()
+	defer globalFieldPolicy.mu.Unlock()
+	globalFieldPolicy.policy = maps.Clone(p)
+}
+
+// FieldPolicies returns the policy set by SetFieldPolicy.
+func FieldPolicies() FieldPolicy {
+	globalFieldPolicy.mu.RLock()
+	defer globalFieldPolicy.mu.RUnlock()
+	return globalFieldPolicy.policy
+}
+
+// IgnoresField reports whether the field policy excludes field from comparison.
+func IgnoresField(field string) bool {
+	return FieldPolicies().Source(field, SyncGamedataToDB) == FieldIgnore
+}
+
+// SyncsField reports whether a sync from source writes field: it must be one of
+// fields, when any are given, and the field policy must let source win it.
+func SyncsField(field, source string, fields []string) bool {
+	if len(fields) > 0 && !slices.Contains(fields, field) {
+		return false
+	}
+	direction := SyncGamedataToDB
+	if source == SourceDB {
+		direction = SyncDBToGamedata
+	}
+	return FieldPolicies().Source(field, direction) == source
+}
+
+// splitSyncMismatches partitions mismatches by the source that wins their field under
+// direction. Mismatches of ignored fields are dropped.
+func splitSyncMismatches(mismatches []string, direction SyncDirection) (fromGamedata, fromDB []string) {
+	policy := FieldPolicies()
+	for _, m := range mismatches {
+		switch policy.Source(mismatchField(m), direction) {
+		case SourceGamedata:
+			fromGamedata = append(fromGamedata, m)
+		case SourceDB:
+			fromDB = append(fromDB, m)
+		}
+	}
+	return fromGamedata, fromDB
+}
@@ -0,0 +1,73 @@
+package reconcile
+
+import (
+	"context"
+	"testing"
+
+	"asset-manager/core/storage/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFieldPolicy tests which source wins listed, grouped and unlisted fields.
+func TestFieldPolicy(t *testing.T) {
+	policy := FieldPolicy{"name": SourceDB, "dimensions": SourceGamedata, "description": FieldIgnore}
+	require.NoError(t, policy.Validate())
+
+	assert.Equal(t, SourceDB, policy.Source("name", SyncGamedataToDB))
+	assert.Equal(t, SourceGamedata, policy.Source("width", SyncDBToGamedata))
+	assert.Equal(t, FieldIgnore, policy.Source("description", SyncGamedataToDB))
+	assert.Equal(t, SourceGamedata, policy.Source("can_sit", SyncGamedataToDB))
+	assert.Equal(t, SourceDB, policy.Source("can_sit", SyncDBToGamedata))
+
+	db, gamedata := policy.writes(SyncGamedataToDB)
+	assert.True(t, db)
+	assert.True(t, gamedata)
+	db, gamedata = FieldPolicy(nil).writes(SyncGamedataToDB)
+	assert.True(t, db)
+	assert.False(t, gamedata)
+
+	assert.ErrorContains(t, FieldPolicy{"name": "emulator"}.Validate(), `invalid sync source "emulator" for field name`)
+}
+
+// TestSyncsField tests that syncs write only the fields of their action that their
+// source wins.
+func TestSyncsField(t *testing.T) {
+	defer SetFieldPolicy(nil)
+	SetFieldPolicy(FieldPolicy{"name": SourceDB, "type": FieldIgnore})
+
+	assert.True(t, SyncsField("width", SourceGamedata, nil))
+	assert.False(t, SyncsField("width", SourceGamedata, []string{"length"}))
+	assert.False(t, SyncsField("name", SourceGamedata, nil))
+	assert.True(t, SyncsField("name", SourceDB, []string{"name"}))
+	assert.False(t, SyncsField("type", SourceDB, nil))
+	assert.True(t, IgnoresField("type"))
+	assert.False(t, IgnoresField("name"))
+}
+
+// TestReconcileWithPlan_FieldPolicy tests that an item's mismatches are split into a
+// DB and a gamedata sync by the source that wins each field.
+func TestReconcileWithPlan_FieldPolicy(t *testing.T) {
+	defer SetFieldPolicy(nil)
+	SetFieldPolicy(FieldPolicy{"name": SourceDB, "type": FieldIgnore})
+
+	mockClient := new(mocks.Client)
+	mockClient.On("BucketExists", mock.Anything, "").Return(true, nil)
+
+	syncer := &mockGamedataSyncer{gamedataSynced: map[string]DBItem{}}
+	syncer.dbIndex = map[string]DBItem{"1": "1"}
+	syncer.gdIndex = map[string]GDItem{"1": "1"}
+	syncer.storageSet = map[string]struct{}{"1": {}}
+	syncer.mismatches = map[string][]string{"1": {"name: gd='A' db='B'", "width: gd=2 db=1", "type: gd='wall' db='s'"}}
+
+	plan, err := ReconcileWithPlan(context.Background(), &Spec{Adapter: syncer}, nil, mockClient, "", ReconcileOptions{DoSync: true})
+	require.NoError(t, err)
+	require.Len(t, plan.Actions, 2)
+	assert.Equal(t, ActionSyncDB, plan.Actions[0].Type)
+	assert.Equal(t, []string{"width"}, plan.Actions[0].Fields)
+	assert.Equal(t, ActionSyncGamedata, plan.Actions[1].Type)
+	assert.Equal(t, []string{"name"}, plan.Actions[1].Fields)
+	assert.Equal(t, 2, plan.Summary.SyncActions)
+}
@@ -0,0 +1,172 @@
+package reconcile
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"asset-manager/core/storage"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// GamedataConfig configures where gamedata is read from.
+type GamedataConfig struct {
+	// FurnitureURL serves FurnitureData.json over http(s), e.g. from a CDN, instead of
+	// the gamedata bucket. Empty reads the bucket.
+	FurnitureURL string `mapstructure:"furniture_url" default:""`
+	// URLCacheTTL is how long a fetched gamedata URL is reused before it is
+	// revalidated with its ETag or Last-Modified.
+	URLCacheTTL time.Duration `mapstructure:"url_cache_ttl" default:"5m"`
+	// URLTimeout bounds one gamedata fetch.
+	URLTimeout time.Duration `mapstructure:"url_timeout" default:"30s"`
+}
+
+// IsGamedataURL reports whether a gamedata object name is an http(s) URL rather than
+// a key in the gamedata bucket.
+func IsGamedataURL(name string) bool {
+	lower := strings.ToLower(name)
+	return strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://")
+}
+
+// urlEntry is a cached gamedata response.
+type urlEntry struct {
+	data         []byte
+	etag         string
+	lastModified string
+	fetchedAt    time.Time
+}
+
+// urlGamedata fetches gamedata served over HTTP and caches each URL.
+type urlGamedata struct {
+	mu      sync.Mutex
+	client  *http.Client
+	ttl     time.Duration
+	entries map[string]*urlEntry
+}
+
+var (
+	// urlGamedataMu guards globalURLGamedata.
+	urlGamedataMu sync.RWMutex
+	// globalURLGamedata is the singleton HTTP gamedata cache for all reconcile operations.
+	globalURLGamedata = newURLGamedata(5*time.Minute, 30*time.Second)
+)
+
+// newURLGamedata returns an empty cache reusing responses for ttl.
+func newURLGamedata(ttl, timeout time.Duration) *urlGamedata {
+	return &urlGamedata{
+		client:  &http.Client{Timeout: timeout},
+		ttl:     ttl,
+		entries: make(map[string]*urlEntry),
+	}
+}
+
+// SetGamedataURLCache replaces the cache of gamedata served over HTTP, reusing each
+// response for ttl and bounding each fetch by timeout.
+func SetGamedataURLCache(ttl, timeout time.Duration) {
+	urlGamedataMu.Lock()
+	defer urlGamedataMu.Unlock()
+	globalURLGamedata = newURLGamedata(ttl, timeout)
+}
+
+// fetch returns the body of url, from the cache while it is fresh. A stale entry is
+// revalidated, so an unchanged file is not downloaded again.
+func (g *urlGamedata) fetch(ctx context.Context, url string) ([]byte, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	entry := g.entries[url]
+	if entry != nil && time.Since(entry.fetchedAt) < g.ttl {
+		return entry.data, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid gamedata URL %s: %w", url, err)
+	}
+	if entry != nil {
+		if entry.etag != "" {
+			req.Header.Set("If-None-Match", entry.etag)
+		}
+		if entry.lastModified != "" {
+			req.Header.Set("If-Modified-Since", entry.lastModified)
+		}
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch gamedata %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && entry != nil:
+		entry.fetchedAt = time.Now()
+		return entry.data, nil
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("failed to fetch gamedata %s: %s", url, resp.Status)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read gamedata %s: %w", url, err)
+	}
+	g.entries[url] = &urlEntry{
+		data:         data,
+		etag:         resp.Header.Get("ETag"),
+		lastModified: resp.Header.Get("Last-Modified"),
+		fetchedAt:    time.Now(),
+	}
+	return data, nil
+}
+
+// urlGamedataClient is a storage client whose GetObject fetches http(s) object names
+// through the HTTP gamedata cache, so adapters read URLs like bucket objects.
+type urlGamedataClient struct {
+	storage.Client
+	gamedata *urlGamedata
+}
+
+// GetObject fetches objectName over HTTP when it is a URL.
+func (c urlGamedataClient) GetObject(ctx context.Context, bucketName, objectName string, opts minio.GetObjectOptions) (io.ReadCloser, error) {
+	if !IsGamedataURL(objectName) {
+		return c.Client.GetObject(ctx, bucketName, objectName, opts)
+	}
+	data, err := c.gamedata.fetch(ctx, objectName)
+	if err != nil {
+		return nil, err
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+// gamedataClient returns the client gamedata of the spec is read with: client itself,
+// or one fetching GamedataObjectName over HTTP when it is a URL.
+func (s *Spec) gamedataClient(client storage.Client) storage.Client {
+	if !IsGamedataURL(s.GamedataObjectName) {
+		return client
+	}
+	urlGamedataMu.RLock()
+	defer urlGamedataMu.RUnlock()
+	return urlGamedataClient{Client: client, gamedata: globalURLGamedata}
+}
+
+// ReadGamedata returns the raw gamedata file of the spec, from the gamedata bucket of
+// a call made with bucket or over HTTP when GamedataObjectName is a URL.
+func (s *Spec) ReadGamedata(ctx context.Context, client storage.Client, bucket string) ([]byte, error) {
+	reader, err := s.gamedataClient(client).GetObject(ctx, s.gamedataBucket(bucket), s.GamedataObjectName, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get gamedata %s: %w", s.GamedataObjectName, err)
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read gamedata %s: %w", s.GamedataObjectName, err)
+	}
+	return data, nil
+}
@@ -0,0 +1,111 @@
+package reconcile
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"asset-manager/core/storage/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// gamedataServer serves body with an ETag, answering a matching If-None-Match with 304.
+// It counts full and revalidated responses.
+func gamedataServer(t *testing.T, body string) (server *httptest.Server, full, notModified *int) {
+	full, notModified = new(int), new(int)
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/FurnitureData.json" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			*notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		*full++
+		io.WriteString(w, body)
+	}))
+	t.Cleanup(server.Close)
+	return server, full, notModified
+}
+
+// readGamedata reads the gamedata object of spec with ReadGamedata.
+func readGamedata(t *testing.T, spec *Spec, client *mocks.Client) (string, error) {
+	data, err := spec.ReadGamedata(context.Background(), client, "assets")
+	return string(data), err
+}
+
+// TestGamedataURL tests that gamedata URLs are fetched over HTTP, reused while fresh
+// and revalidated with their ETag once stale.
+func TestGamedataURL(t *testing.T) {
+	server, full, notModified := gamedataServer(t, `{"roomitemtypes":{}}`)
+	defer SetGamedataURLCache(5*time.Minute, 30*time.Second)
+
+	spec := &Spec{GamedataObjectName: server.URL + "/FurnitureData.json"}
+	assert.True(t, IsGamedataURL(spec.GamedataObjectName))
+	// Storage is never asked for a URL
+	mockClient := new(mocks.Client)
+
+	SetGamedataURLCache(time.Hour, time.Second)
+	for range 2 {
+		data, err := readGamedata(t, spec, mockClient)
+		require.NoError(t, err)
+		assert.Equal(t, `{"roomitemtypes":{}}`, data)
+	}
+	assert.Equal(t, 1, *full)
+	assert.Zero(t, *notModified)
+
+	// A zero TTL revalidates every read; the unchanged file is not downloaded again
+	SetGamedataURLCache(0, time.Second)
+	for range 2 {
+		data, err := readGamedata(t, spec, mockClient)
+		require.NoError(t, err)
+		assert.Equal(t, `{"roomitemtypes":{}}`, data)
+	}
+	assert.Equal(t, 2, *full)
+	assert.Equal(t, 1, *notModified)
+
+	_, err := readGamedata(t, &Spec{GamedataObjectName: server.URL + "/missing.json"}, mockClient)
+	assert.ErrorContains(t, err, "404 Not Found")
+	mockClient.AssertNotCalled(t, "GetObject", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestGamedataURL_Object tests that object names are still read from storage.
+func TestGamedataURL_Object(t *testing.T) {
+	assert.False(t, IsGamedataURL("gamedata/FurnitureData.json"))
+
+	mockClient := new(mocks.Client)
+	mockClient.On("GetObject", mock.Anything, "assets", "gamedata/FurnitureData.json", mock.Anything).
+		Return(io.NopCloser(strings.NewReader("{}")), nil)
+
+	spec := &Spec{GamedataObjectName: "gamedata/FurnitureData.json"}
+	assert.Same(t, mockClient, spec.gamedataClient(mockClient))
+	data, err := readGamedata(t, spec, mockClient)
+	require.NoError(t, err)
+	assert.Equal(t, "{}", data)
+}
+
+// TestCheckPermissions_GamedataURL tests that purges writing gamedata are refused when
+// it is served over HTTP.
+func TestCheckPermissions_GamedataURL(t *testing.T) {
+	spec := &Spec{Adapter: &mockAdapter{}, GamedataObjectName: "https://cdn.example.com/gamedata/FurnitureData.json"}
+
+	report, err := CheckPermissions(context.Background(), spec, nil, new(mocks.Client), "assets",
+		ReconcileOptions{DoPurge: true, PurgePolicy: PurgeGamedataGhosts})
+	require.Error(t, err)
+	assert.Equal(t, []PermissionCheck{{
+		Store:      PermissionStoreStorage,
+		Target:     spec.GamedataObjectName,
+		Permission: "PutObject",
+		Detail:     "gamedata is served over HTTP and cannot be written",
+	}}, report.Missing())
+}
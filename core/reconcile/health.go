@@ -0,0 +1,99 @@
+package reconcile
+
+import (
+	"context"
+	"time"
+
+	"asset-manager/core/storage"
+
+	"gorm.io/gorm"
+)
+
+// DomainHealth is the health of a single adapter's entities.
+type DomainHealth struct {
+	// Adapter is the adapter name (e.g. "furniture").
+	Adapter string `json:"adapter"`
+
+	// Score is the percentage (0-100) of this domain's entities that are healthy.
+	Score float64 `json:"score"`
+
+	// Contribution is this domain's share of the overall score, in points.
+	// Contributions of all domains add up to the overall score.
+	Contribution float64 `json:"contribution"`
+
+	// Total is the number of entities in the domain.
+	Total int `json:"total"`
+
+	// Healthy counts entities present in every source without mismatches.
+	Healthy int `json:"healthy"`
+
+	// Summary holds the domain's presence and mismatch counts.
+	Summary PlanSummary `json:"summary"`
+
+	// Error is set when the domain could not be reconciled; it is then left out of the score.
+	Error string `json:"error,omitempty"`
+}
+
+// HealthReport is the combined health of all registered domains.
+type HealthReport struct {
+	// Score is the percentage (0-100) of healthy entities across all domains.
+	// Domains are weighted by entity count, so large catalogs dominate.
+	Score float64 `json:"score"`
+
+	// Domains lists the per-adapter results, sorted by adapter name.
+	Domains []DomainHealth `json:"domains"`
+
+	// GeneratedAt is when the report was computed (RFC3339).
+	GeneratedAt string `json:"generated_at"`
+}
+
+// ComputeHealth reconciles every spec and combines the results into one health score.
+// Specs with a CacheTTL read their cached indices when fresh, which keeps status pages cheap.
+// Health runs are read-only and are not recorded in the flapping history.
+func ComputeHealth(ctx context.Context, specs []*Spec, db *gorm.DB, client storage.Client, bucket string) *HealthReport {
+	report := &HealthReport{
+		Domains:     make([]DomainHealth, 0, len(specs)),
+		GeneratedAt: time.Now().Format(time.RFC3339),
+	}
+
+	var total, healthy int
+	for _, spec := range specs {
+		domain := DomainHealth{Adapter: spec.Adapter.Name()}
+
+		cache, err := GetOrBuildCache(ctx, spec, db, client, bucket)
+		if err != nil {
+			domain.Error = err.Error()
+			report.Domains = append(report.Domains, domain)
+			continue
+		}
+
+		results, _ := reconcileFromCache(cache, spec.Adapter, nil)
+		domain.Summary = Summarize(results)
+		domain.Total = len(results)
+		for _, result := range results {
+			if isHealthy(result) {
+				domain.Healthy++
+			}
+		}
+		domain.Score = percentage(domain.Healthy, domain.Total)
+
+		total += domain.Total
+		healthy += domain.Healthy
+		report.Domains = append(report.Domains, domain)
+	}
+
+	report.Score = percentage(healthy, total)
+	for i := range report.Domains {
+		report.Domains[i].Contribution = percentage(report.Domains[i].Healthy, total)
+	}
+
+	return report
+}
+
+// percentage returns part/whole as a 0-100 value; an empty whole counts as fully healthy.
+func percentage(part, whole int) float64 {
+	if whole == 0 {
+		return 100
+	}
+	return float64(part) * 100 / float64(whole)
+}
@@ -0,0 +1,89 @@
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"asset-manager/core/storage/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// namedAdapter lets tests register several mock domains side by side.
+type namedAdapter struct {
+	mockAdapter
+	name string
+}
+
+func (n *namedAdapter) Name() string {
+	return n.name
+}
+
+// TestComputeHealth tests the weighted score and per-domain contributions.
+func TestComputeHealth(t *testing.T) {
+	// furniture: 3 of 4 healthy ("2" lacks storage)
+	furniture := &namedAdapter{name: "furniture", mockAdapter: mockAdapter{
+		dbIndex:    map[string]DBItem{"1": "1", "2": "2", "3": "3", "4": "4"},
+		gdIndex:    map[string]GDItem{"1": "1", "2": "2", "3": "3", "4": "4"},
+		storageSet: map[string]struct{}{"1": {}, "3": {}, "4": {}},
+		mismatches: map[string][]string{},
+	}}
+	// effects: 0 of 1 healthy (mismatch)
+	effects := &namedAdapter{name: "effects", mockAdapter: mockAdapter{
+		dbIndex:    map[string]DBItem{"9": "9"},
+		gdIndex:    map[string]GDItem{"9": "9"},
+		storageSet: map[string]struct{}{"9": {}},
+		mismatches: map[string][]string{"9": {"name: gd='a' db='b'"}},
+	}}
+	// clothing: fails to load and is left out of the score
+	clothing := &namedAdapter{name: "clothing", mockAdapter: mockAdapter{
+		dbLoadFunc: func(ctx context.Context, db *gorm.DB, profile string) (map[string]DBItem, error) {
+			return nil, fmt.Errorf("db down")
+		},
+	}}
+
+	specs := []*Spec{{Adapter: clothing}, {Adapter: effects}, {Adapter: furniture}}
+
+	mockClient := new(mocks.Client)
+	mockClient.On("BucketExists", mock.Anything, "").Return(true, nil)
+
+	report := ComputeHealth(context.Background(), specs, nil, mockClient, "")
+	require.Len(t, report.Domains, 3)
+	assert.InDelta(t, 60.0, report.Score, 0.001)
+
+	assert.Equal(t, "db down", report.Domains[0].Error)
+
+	assert.Equal(t, 0, report.Domains[1].Healthy)
+	assert.InDelta(t, 0.0, report.Domains[1].Score, 0.001)
+	assert.Equal(t, 1, report.Domains[1].Summary.Mismatches)
+
+	assert.Equal(t, 3, report.Domains[2].Healthy)
+	assert.InDelta(t, 75.0, report.Domains[2].Score, 0.001)
+	assert.InDelta(t, 60.0, report.Domains[2].Contribution, 0.001)
+}
+
+// TestComputeHealth_Empty tests that no registered domains report full health.
+func TestComputeHealth_Empty(t *testing.T) {
+	report := ComputeHealth(context.Background(), nil, nil, nil, "")
+	assert.Equal(t, float64(100), report.Score)
+	assert.Empty(t, report.Domains)
+}
+
+// TestRegisterSpec tests registration order and replacement by adapter name.
+func TestRegisterSpec(t *testing.T) {
+	defer func() { globalSpecs = &specRegistry{specs: make(map[string]*Spec)} }()
+
+	first := &Spec{Adapter: &namedAdapter{name: "furniture"}, ServerProfile: "arcturus"}
+	RegisterSpec(first)
+	RegisterSpec(&Spec{Adapter: &namedAdapter{name: "effects"}})
+	RegisterSpec(&Spec{Adapter: &namedAdapter{name: "furniture"}, ServerProfile: "comet"})
+
+	specs := RegisteredSpecs()
+	require.Len(t, specs, 2)
+	assert.Equal(t, "effects", specs[0].Adapter.Name())
+	assert.Equal(t, "comet", specs[1].ServerProfile)
+}
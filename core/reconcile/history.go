@@ -0,0 +1,151 @@
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ItemState is the persisted health of a single entity between reconcile runs.
+type ItemState struct {
+	// Healthy is true when the entity was complete and without mismatches.
+	Healthy bool
+
+	// Transitions counts healthy/broken flips within the current flap window.
+	Transitions int
+
+	// LastChanged is when the entity last flipped between healthy and broken.
+	LastChanged time.Time
+}
+
+// HistoryStore persists per-entity health across runs so that flapping items
+// (repeatedly fixed and broken again) can be detected.
+type HistoryStore interface {
+	// LoadItemStates returns the last known state of every entity for the adapter.
+	LoadItemStates(ctx context.Context, adapter string) (map[string]ItemState, error)
+
+	// SaveItemStates replaces the stored states for the adapter.
+	SaveItemStates(ctx context.Context, adapter string, states map[string]ItemState) error
+}
+
+// FlapPolicy controls when an entity is reported as flapping.
+type FlapPolicy struct {
+	// Threshold is the number of transitions within Window that marks an entity as flapping.
+	Threshold int
+
+	// Window is how long a transition counts; a longer stable period resets the count.
+	Window time.Duration
+}
+
+// DefaultFlapPolicy flags entities that flip three or more times within a week.
+var DefaultFlapPolicy = FlapPolicy{
+	Threshold: 3,
+	Window:    7 * 24 * time.Hour,
+}
+
+// historyRegistry holds the process-wide history store used by full scans.
+type historyRegistry struct {
+	mu     sync.RWMutex
+	store  HistoryStore
+	policy FlapPolicy
+	// grace is how long entities that became inconsistent stay pending.
+	grace time.Duration
+}
+
+// globalHistory is the singleton history registry for all reconcile operations.
+var globalHistory = &historyRegistry{policy: DefaultFlapPolicy}
+
+// SetHistoryStore registers the store used to track entity health across runs.
+// Passing nil disables history tracking.
+func SetHistoryStore(store HistoryStore, policy FlapPolicy) {
+	globalHistory.mu.Lock()
+	defer globalHistory.mu.Unlock()
+	globalHistory.store = store
+	globalHistory.policy = policy
+}
+
+// SetGracePeriod sets how long entities that became inconsistent are reported as
+// pending and left out of purges, so purges do not race content uploads still in
+// progress. It needs a history store to know when entities broke; zero disables it.
+func SetGracePeriod(grace time.Duration) {
+	globalHistory.mu.Lock()
+	defer globalHistory.mu.Unlock()
+	globalHistory.grace = grace
+}
+
+// RecordRun updates the stored history with the results of a full scan and
+// annotates each result with its transition count, flapping flag and pending flag.
+// It is a no-op when no history store is registered.
+func RecordRun(ctx context.Context, adapter string, results []ReconcileResult) error {
+	globalHistory.mu.RLock()
+	store, policy, grace := globalHistory.store, globalHistory.policy, globalHistory.grace
+	globalHistory.mu.RUnlock()
+
+	if store == nil {
+		return nil
+	}
+
+	previous, err := store.LoadItemStates(ctx, adapter)
+	if err != nil {
+		return fmt.Errorf("failed to load item history: %w", err)
+	}
+
+	now := time.Now()
+	states := applyTransitions(previous, results, policy, now)
+	markPending(states, results, grace, now)
+
+	if err := store.SaveItemStates(ctx, adapter, states); err != nil {
+		return fmt.Errorf("failed to save item history: %w", err)
+	}
+	return nil
+}
+
+// applyTransitions computes the new states for results given the previous states,
+// and marks results whose transition count reaches the policy threshold.
+func applyTransitions(previous map[string]ItemState, results []ReconcileResult, policy FlapPolicy, now time.Time) map[string]ItemState {
+	states := make(map[string]ItemState, len(results))
+
+	for i := range results {
+		result := &results[i]
+		healthy := isHealthy(*result)
+
+		state, seen := previous[result.ID]
+		if !seen {
+			state = ItemState{Healthy: healthy, LastChanged: now}
+		} else if state.Healthy != healthy {
+			// A stable period longer than the window clears earlier flips
+			if policy.Window > 0 && now.Sub(state.LastChanged) > policy.Window {
+				state.Transitions = 0
+			}
+			state.Healthy = healthy
+			state.Transitions++
+			state.LastChanged = now
+		} else if policy.Window > 0 && now.Sub(state.LastChanged) > policy.Window {
+			// So does staying stable: the entity stopped flapping
+			state.Transitions = 0
+		}
+
+		states[result.ID] = state
+		result.Transitions = state.Transitions
+		result.Flapping = policy.Threshold > 0 && state.Transitions >= policy.Threshold
+	}
+
+	return states
+}
+
+// markPending flags the broken results whose entity broke less than grace before now.
+func markPending(states map[string]ItemState, results []ReconcileResult, grace time.Duration, now time.Time) {
+	if grace <= 0 {
+		return
+	}
+	for i := range results {
+		state := states[results[i].ID]
+		results[i].Pending = !state.Healthy && now.Sub(state.LastChanged) < grace
+	}
+}
+
+// isHealthy reports whether an entity is complete in every source and has no mismatches.
+func isHealthy(result ReconcileResult) bool {
+	return len(result.MissingSources()) == 0 && len(result.Mismatch) == 0
+}
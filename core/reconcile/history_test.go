@@ -0,0 +1,135 @@
+package reconcile
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryHistory is an in-memory HistoryStore for tests.
+type memoryHistory struct {
+	states map[string]map[string]ItemState
+}
+
+func (m *memoryHistory) LoadItemStates(ctx context.Context, adapter string) (map[string]ItemState, error) {
+	return m.states[adapter], nil
+}
+
+func (m *memoryHistory) SaveItemStates(ctx context.Context, adapter string, states map[string]ItemState) error {
+	if m.states == nil {
+		m.states = make(map[string]map[string]ItemState)
+	}
+	m.states[adapter] = states
+	return nil
+}
+
+// TestApplyTransitions_Flapping tests that repeated flips mark an item as flapping.
+func TestApplyTransitions_Flapping(t *testing.T) {
+	policy := FlapPolicy{Threshold: 3, Window: time.Hour}
+	now := time.Now()
+
+	healthy := ReconcileResult{ID: "1", DBPresent: true, StoragePresent: true, GamedataPresent: true}
+	broken := ReconcileResult{ID: "1", DBPresent: true, StoragePresent: true, GamedataPresent: true, Mismatch: []string{"width: gd=2 db=1"}}
+
+	var states map[string]ItemState
+	runs := []ReconcileResult{healthy, broken, healthy, broken}
+	var last []ReconcileResult
+	for i, r := range runs {
+		last = []ReconcileResult{r}
+		states = applyTransitions(states, last, policy, now.Add(time.Duration(i)*time.Minute))
+	}
+
+	assert.Equal(t, 3, last[0].Transitions)
+	assert.True(t, last[0].Flapping)
+}
+
+// TestApplyTransitions_WindowReset tests that a long stable period clears old transitions.
+func TestApplyTransitions_WindowReset(t *testing.T) {
+	policy := FlapPolicy{Threshold: 2, Window: time.Hour}
+	now := time.Now()
+
+	previous := map[string]ItemState{
+		"1": {Healthy: true, Transitions: 5, LastChanged: now.Add(-2 * time.Hour)},
+	}
+	results := []ReconcileResult{{ID: "1", DBPresent: true}}
+
+	states := applyTransitions(previous, results, policy, now)
+
+	assert.Equal(t, 1, states["1"].Transitions)
+	assert.False(t, results[0].Flapping)
+}
+
+// TestApplyTransitions_StableClearsFlapping tests that an item that stopped flipping
+// is no longer flapping once it stayed stable for longer than the window.
+func TestApplyTransitions_StableClearsFlapping(t *testing.T) {
+	policy := FlapPolicy{Threshold: 3, Window: time.Hour}
+	now := time.Now()
+
+	healthy := ReconcileResult{ID: "1", DBPresent: true, StoragePresent: true, GamedataPresent: true}
+	broken := ReconcileResult{ID: "1", DBPresent: true, StoragePresent: true, GamedataPresent: true, Mismatch: []string{"width: gd=2 db=1"}}
+
+	var states map[string]ItemState
+	var last []ReconcileResult
+	for i, r := range []ReconcileResult{healthy, broken, healthy, broken} {
+		last = []ReconcileResult{r}
+		states = applyTransitions(states, last, policy, now.Add(time.Duration(i)*time.Minute))
+	}
+	assert.True(t, last[0].Flapping)
+
+	// Still within the window of the last flip
+	last = []ReconcileResult{broken}
+	states = applyTransitions(states, last, policy, now.Add(30*time.Minute))
+	assert.True(t, last[0].Flapping)
+
+	last = []ReconcileResult{broken}
+	states = applyTransitions(states, last, policy, now.Add(2*time.Hour))
+	assert.False(t, last[0].Flapping)
+	assert.Zero(t, states["1"].Transitions)
+}
+
+// TestRecordRun tests that RecordRun persists states through the registered store.
+func TestRecordRun(t *testing.T) {
+	store := &memoryHistory{}
+	SetHistoryStore(store, FlapPolicy{Threshold: 1, Window: time.Hour})
+	defer SetHistoryStore(nil, DefaultFlapPolicy)
+
+	ctx := context.Background()
+	assert.NoError(t, RecordRun(ctx, "test", []ReconcileResult{{ID: "1", DBPresent: true}}))
+
+	results := []ReconcileResult{{ID: "1", DBPresent: true, StoragePresent: true, GamedataPresent: true}}
+	assert.NoError(t, RecordRun(ctx, "test", results))
+
+	assert.True(t, results[0].Flapping)
+	assert.Equal(t, 1, store.states["test"]["1"].Transitions)
+	assert.Equal(t, 1, Summarize(results).Flapping)
+}
+
+// TestRecordRun_GracePeriod tests that entities that just broke are pending, left out
+// of purges, and no longer pending once the grace period passed.
+func TestRecordRun_GracePeriod(t *testing.T) {
+	now := time.Now()
+	store := &memoryHistory{states: map[string]map[string]ItemState{"test": {
+		"1": {Healthy: false, LastChanged: now.Add(-2 * time.Hour)},
+		"2": {Healthy: true, LastChanged: now.Add(-2 * time.Hour)},
+	}}}
+	SetHistoryStore(store, DefaultFlapPolicy)
+	SetGracePeriod(time.Hour)
+	defer SetHistoryStore(nil, DefaultFlapPolicy)
+	defer SetGracePeriod(0)
+
+	results := []ReconcileResult{
+		{ID: "1", StoragePresent: true},
+		{ID: "2", StoragePresent: true},
+		{ID: "3", DBPresent: true, StoragePresent: true, GamedataPresent: true},
+	}
+	assert.NoError(t, RecordRun(context.Background(), "test", results))
+
+	assert.False(t, results[0].Pending, "broken for longer than the grace period")
+	assert.True(t, results[1].Pending, "just broke")
+	assert.False(t, results[2].Pending, "healthy")
+	assert.Equal(t, 1, Summarize(results).Pending)
+	assert.Equal(t, []ActionType{ActionDeleteStorage}, purgeActionTypes(results[0], PurgeStrict))
+	assert.Empty(t, purgeActionTypes(results[1], PurgeStrict))
+}
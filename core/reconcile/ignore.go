@@ -0,0 +1,122 @@
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrNoIgnoreStore is returned when ignores are added without a registered store.
+var ErrNoIgnoreStore = errors.New("ignore list requires the state store (STATE_PATH)")
+
+// IgnoreEntry excludes one entity from reconcile plans until it expires.
+type IgnoreEntry struct {
+	// Adapter is the adapter the entity belongs to (e.g. "furniture").
+	Adapter string `json:"adapter"`
+
+	// Key is the entity identifier, as reported in results.
+	Key string `json:"key"`
+
+	// Reason explains why the entity is ignored.
+	Reason string `json:"reason"`
+
+	// CreatedAt is when the ignore was added.
+	CreatedAt time.Time `json:"created_at"`
+
+	// ExpiresAt is when the ignore stops applying; nil never expires.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// Active reports whether the ignore still applies at now.
+func (e IgnoreEntry) Active(now time.Time) bool {
+	return e.ExpiresAt == nil || now.Before(*e.ExpiresAt)
+}
+
+// IgnoredResult is a result left out of a plan because its entity is ignored.
+type IgnoredResult struct {
+	ReconcileResult
+	// Ignore is the entry that matched the result.
+	Ignore IgnoreEntry `json:"ignore"`
+}
+
+// IgnoreStore persists per-entity ignores.
+type IgnoreStore interface {
+	// SaveIgnore adds an ignore, replacing any existing one for the same entity.
+	SaveIgnore(ctx context.Context, entry IgnoreEntry) error
+
+	// LoadIgnores returns the ignores of the adapter that are active at now.
+	LoadIgnores(ctx context.Context, adapter string, now time.Time) ([]IgnoreEntry, error)
+}
+
+// ignoreRegistry holds the process-wide ignore store.
+type ignoreRegistry struct {
+	mu    sync.RWMutex
+	store IgnoreStore
+}
+
+// globalIgnores is the singleton ignore registry for all reconcile operations.
+var globalIgnores = &ignoreRegistry{}
+
+// SetIgnoreStore registers the store holding per-entity ignores.
+// Passing nil disables ignores.
+func SetIgnoreStore(store IgnoreStore) {
+	globalIgnores.mu.Lock()
+	defer globalIgnores.mu.Unlock()
+	globalIgnores.store = store
+}
+
+// AddIgnore persists an ignore. It returns ErrNoIgnoreStore when no store is registered.
+func AddIgnore(ctx context.Context, entry IgnoreEntry) error {
+	globalIgnores.mu.RLock()
+	store := globalIgnores.store
+	globalIgnores.mu.RUnlock()
+
+	if store == nil {
+		return ErrNoIgnoreStore
+	}
+	if entry.Adapter == "" || entry.Key == "" {
+		return fmt.Errorf("ignore requires an adapter and a key")
+	}
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+	return store.SaveIgnore(ctx, entry)
+}
+
+// splitIgnored moves the results of ignored entities out of results.
+// It is a no-op when no ignore store is registered.
+func splitIgnored(ctx context.Context, adapter string, results []ReconcileResult) ([]ReconcileResult, []IgnoredResult, error) {
+	globalIgnores.mu.RLock()
+	store := globalIgnores.store
+	globalIgnores.mu.RUnlock()
+
+	if store == nil {
+		return results, nil, nil
+	}
+
+	entries, err := store.LoadIgnores(ctx, adapter, time.Now())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load ignore list: %w", err)
+	}
+	if len(entries) == 0 {
+		return results, nil, nil
+	}
+
+	byKey := make(map[string]IgnoreEntry, len(entries))
+	for _, entry := range entries {
+		byKey[entry.Key] = entry
+	}
+
+	kept := make([]ReconcileResult, 0, len(results))
+	var ignored []IgnoredResult
+	for _, result := range results {
+		if entry, ok := byKey[result.ID]; ok {
+			ignored = append(ignored, IgnoredResult{ReconcileResult: result, Ignore: entry})
+			continue
+		}
+		kept = append(kept, result)
+	}
+	return kept, ignored, nil
+}
@@ -0,0 +1,59 @@
+package reconcile
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryIgnores is an in-memory IgnoreStore for tests.
+type memoryIgnores struct {
+	entries []IgnoreEntry
+}
+
+func (m *memoryIgnores) SaveIgnore(ctx context.Context, entry IgnoreEntry) error {
+	m.entries = append(m.entries, entry)
+	return nil
+}
+
+func (m *memoryIgnores) LoadIgnores(ctx context.Context, adapter string, now time.Time) ([]IgnoreEntry, error) {
+	var active []IgnoreEntry
+	for _, e := range m.entries {
+		if e.Adapter == adapter && e.Active(now) {
+			active = append(active, e)
+		}
+	}
+	return active, nil
+}
+
+// TestAddIgnore_NoStore tests that ignores need a registered store.
+func TestAddIgnore_NoStore(t *testing.T) {
+	SetIgnoreStore(nil)
+	err := AddIgnore(context.Background(), IgnoreEntry{Adapter: "furniture", Key: "1"})
+	assert.ErrorIs(t, err, ErrNoIgnoreStore)
+}
+
+// TestSplitIgnored tests that active ignores move results out and expired ones do not.
+func TestSplitIgnored(t *testing.T) {
+	store := &memoryIgnores{}
+	SetIgnoreStore(store)
+	defer SetIgnoreStore(nil)
+
+	ctx := context.Background()
+	expired := time.Now().Add(-time.Minute)
+	assert.NoError(t, AddIgnore(ctx, IgnoreEntry{Adapter: "furniture", Key: "1", Reason: "custom"}))
+	assert.NoError(t, AddIgnore(ctx, IgnoreEntry{Adapter: "furniture", Key: "2", Reason: "old", ExpiresAt: &expired}))
+	assert.NoError(t, AddIgnore(ctx, IgnoreEntry{Adapter: "other", Key: "3"}))
+	assert.False(t, store.entries[0].CreatedAt.IsZero())
+
+	results := []ReconcileResult{{ID: "1"}, {ID: "2"}, {ID: "3"}}
+	kept, ignored, err := splitIgnored(ctx, "furniture", results)
+	assert.NoError(t, err)
+	assert.Equal(t, []ReconcileResult{{ID: "2"}, {ID: "3"}}, kept)
+	if assert.Len(t, ignored, 1) {
+		assert.Equal(t, "1", ignored[0].ID)
+		assert.Equal(t, "custom", ignored[0].Ignore.Reason)
+	}
+}
@@ -0,0 +1,27 @@
+package reconcile
+
+import "context"
+
+// GamedataInserter extends Mutator for adapters that can add gamedata entries from DB
+// rows. With ReconcileOptions.DoInsertGamedata, entities that exist in the database and
+// storage but not in gamedata, such as custom furniture never added to the gamedata
+// file, are planned as ActionInsertGamedata instead of being purged.
+type GamedataInserter interface {
+	// InsertGamedata adds the gamedata entry of key, built from dbItem. It fails when
+	// gamedata already holds an entry for key.
+	InsertGamedata(ctx context.Context, key string, dbItem DBItem) error
+}
+
+// planGamedataInsert returns the gamedata insert of a result that exists in the
+// database and storage but not in gamedata.
+func planGamedataInsert(result ReconcileResult, cache *ReconcileCache) (Action, bool) {
+	if result.GamedataPresent || !result.DBPresent || !result.StoragePresent {
+		return Action{}, false
+	}
+	return Action{
+		Type:   ActionInsertGamedata,
+		Key:    result.ID,
+		Reason: "missing in gamedata",
+		DBItem: cache.DBIndex[result.ID],
+	}, true
+}
@@ -0,0 +1,75 @@
+package reconcile
+
+import (
+	"context"
+	"testing"
+
+	"asset-manager/core/storage/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// mockInserter implements GamedataInserter, recording the DB item of each key.
+type mockInserter struct {
+	mockMutator
+	inserted map[string]DBItem
+}
+
+func (m *mockInserter) InsertGamedata(ctx context.Context, key string, dbItem DBItem) error {
+	m.inserted[key] = dbItem
+	return nil
+}
+
+// TestReconcileWithPlan_InsertGamedata tests that items in the database and storage
+// but not in gamedata get inserted instead of purged, for inserters only.
+func TestReconcileWithPlan_InsertGamedata(t *testing.T) {
+	mockClient := new(mocks.Client)
+	mockClient.On("BucketExists", mock.Anything, "").Return(true, nil)
+
+	inserter := &mockInserter{inserted: map[string]DBItem{}}
+	inserter.dbIndex = map[string]DBItem{"1": "db1", "2": "db2", "3": "db3"}
+	inserter.gdIndex = map[string]GDItem{"1": "gd1"}
+	inserter.storageSet = map[string]struct{}{"1": {}, "2": {}}
+	opts := ReconcileOptions{DoPurge: true, DoInsertGamedata: true}
+
+	plan, err := ReconcileWithPlan(context.Background(), &Spec{Adapter: inserter}, nil, mockClient, "", opts)
+	require.NoError(t, err)
+	assert.Equal(t, 1, plan.Summary.InsertActions)
+	assert.Contains(t, plan.Actions, Action{Type: ActionInsertGamedata, Key: "2", Reason: "missing in gamedata", DBItem: "db2"})
+	for _, action := range plan.Actions {
+		if action.Key == "2" {
+			assert.Equal(t, ActionInsertGamedata, action.Type, "item 2 is repaired, not purged")
+		}
+	}
+	// Item 3 has no storage object to back an entry and is still purged
+	assert.Contains(t, plan.Actions, Action{Type: ActionDeleteDB, Key: "3", Reason: "missing in: [gamedata storage]"})
+
+	// Adapters that cannot insert keep purging
+	adapter := &mockMutator{}
+	adapter.dbIndex, adapter.gdIndex, adapter.storageSet = inserter.dbIndex, inserter.gdIndex, inserter.storageSet
+	plan, err = ReconcileWithPlan(context.Background(), &Spec{Adapter: adapter}, nil, mockClient, "", opts)
+	require.NoError(t, err)
+	assert.Zero(t, plan.Summary.InsertActions)
+	assert.Contains(t, plan.Actions, Action{Type: ActionDeleteDB, Key: "2", Reason: "missing in: [gamedata]"})
+}
+
+// TestApplyPlan_InsertGamedata tests that inserts reach the inserter and that other
+// adapters refuse them.
+func TestApplyPlan_InsertGamedata(t *testing.T) {
+	opts := ReconcileOptions{DoInsertGamedata: true, Confirmed: true}
+	plan := &ReconcilePlan{Actions: []Action{{Type: ActionInsertGamedata, Key: "2", DBItem: "db2"}}}
+
+	inserter := &mockInserter{inserted: map[string]DBItem{}}
+	executed, err := ApplyPlan(context.Background(), &Spec{Adapter: inserter}, nil, nil, "", plan, opts)
+	require.NoError(t, err)
+	assert.Equal(t, 1, executed)
+	assert.Equal(t, map[string]DBItem{"2": "db2"}, inserter.inserted)
+
+	_, err = ApplyPlan(context.Background(), &Spec{Adapter: &mockMutator{}}, nil, nil, "", plan, opts)
+	assert.ErrorContains(t, err, "does not implement GamedataInserter")
+
+	assert.Equal(t, "still missing in gamedata", verifyAction(plan.Actions[0], ReconcileResult{DBPresent: true, StoragePresent: true}))
+	assert.Empty(t, verifyAction(plan.Actions[0], ReconcileResult{DBPresent: true, StoragePresent: true, GamedataPresent: true}))
+}
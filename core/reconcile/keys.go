@@ -0,0 +1,77 @@
+package reconcile
+
+import "context"
+
+// normalizeCache rewrites every index of cache to the keys of normalizer. Distinct raw
+// keys of one index that normalize to the same key are recorded as key conflicts.
+func normalizeCache(ctx context.Context, cache *ReconcileCache, normalizer KeyNormalizer) {
+	collisions := func(source string) func(string, string, string) {
+		return func(key, kept, dropped string) {
+			RecordConflict(ctx, source, key, kept, dropped)
+		}
+	}
+	cache.DBIndex = normalizeIndex(cache.DBIndex, normalizer.NormalizeKey, collisions(SourceDB))
+	cache.GDIndex = normalizeIndex(cache.GDIndex, normalizer.NormalizeKey, collisions(SourceGamedata))
+	cache.StorageSet = normalizeIndex(cache.StorageSet, normalizer.NormalizeKey, collisions(SourceStorage))
+	cache.Misplaced = normalizeIndex(cache.Misplaced, normalizer.NormalizeKey, nil)
+	cache.Modified = normalizeIndex(cache.Modified, normalizer.NormalizeKey, nil)
+	for name, index := range cache.Extra {
+		cache.Extra[name] = normalizeIndex(index, normalizer.NormalizeKey, collisions(name))
+	}
+}
+
+// normalizeIndex returns index keyed by the normalized form of each key. When several
+// keys of one index normalize to the same key, the entry already in normalized form
+// wins, and otherwise the one with the smallest raw key, so the choice does not
+// depend on map iteration order. Each collision is passed to collide, if not nil, with
+// the normalized key and the raw keys of the entry kept so far and of the other one.
+func normalizeIndex[V any](index map[string]V, normalize func(string) string, collide func(key, kept, dropped string)) map[string]V {
+	if index == nil {
+		return nil
+	}
+	if collide == nil {
+		collide = func(string, string, string) {}
+	}
+
+	out := make(map[string]V, len(index))
+	// raw holds the raw key kept for a normalized key, when it differs from it
+	raw := make(map[string]string)
+	for key, value := range index {
+		norm := normalize(key)
+		kept, rewritten := raw[norm]
+		_, taken := out[norm]
+
+		if norm == key {
+			if rewritten {
+				collide(norm, key, kept)
+			}
+			out[norm] = value
+			delete(raw, norm)
+			continue
+		}
+
+		if taken {
+			if !rewritten {
+				// The kept entry is already normalized
+				collide(norm, norm, key)
+				continue
+			}
+			if kept < key {
+				collide(norm, kept, key)
+				continue
+			}
+			collide(norm, key, kept)
+		}
+		out[norm] = value
+		raw[norm] = key
+	}
+	return out
+}
+
+// normalizeKey returns key as normalized by the adapter of spec, if it normalizes keys.
+func (s *Spec) normalizeKey(key string) string {
+	if normalizer, ok := s.Adapter.(KeyNormalizer); ok && key != "" {
+		return normalizer.NormalizeKey(key)
+	}
+	return key
+}
@@ -0,0 +1,86 @@
+package reconcile
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"asset-manager/core/storage/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// zeroTrimAdapter is a mock adapter whose keys ignore leading zeros and case.
+type zeroTrimAdapter struct {
+	*mockAdapter
+}
+
+func (a *zeroTrimAdapter) NormalizeKey(key string) string {
+	trimmed := strings.TrimLeft(strings.ToLower(key), "0")
+	if trimmed == "" {
+		return "0"
+	}
+	return trimmed
+}
+
+// TestNormalizeIndex tests re-keying and the deterministic choice between colliding keys.
+func TestNormalizeIndex(t *testing.T) {
+	normalize := (&zeroTrimAdapter{}).NormalizeKey
+
+	assert.Nil(t, normalizeIndex[int](nil, normalize, nil))
+	assert.Equal(t, map[string]int{"7": 1, "chair": 2}, normalizeIndex(map[string]int{"007": 1, "Chair": 2}, normalize, nil))
+
+	// The entry already in normalized form wins
+	for i := 0; i < 20; i++ {
+		assert.Equal(t, map[string]string{"7": "plain"}, normalizeIndex(map[string]string{"07": "padded", "7": "plain", "007": "more"}, normalize, nil))
+	}
+	// Otherwise the smallest raw key wins
+	for i := 0; i < 20; i++ {
+		assert.Equal(t, map[string]string{"7": "three"}, normalizeIndex(map[string]string{"07": "two", "007": "three"}, normalize, nil))
+	}
+}
+
+// TestReconcileAll_NormalizesKeys tests that near-miss keys of different sources become one entity.
+func TestReconcileAll_NormalizesKeys(t *testing.T) {
+	adapter := &zeroTrimAdapter{&mockAdapter{
+		dbIndex:    map[string]DBItem{"7": "7", "12": "12"},
+		gdIndex:    map[string]GDItem{"007": "007", "12": "12"},
+		storageSet: map[string]struct{}{"07": {}},
+		mismatches: map[string][]string{},
+	}}
+	mockClient := new(mocks.Client)
+	mockClient.On("BucketExists", mock.Anything, "").Return(true, nil)
+
+	results, err := ReconcileAll(context.Background(), &Spec{Adapter: adapter}, nil, mockClient, "")
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	assert.Equal(t, "12", results[0].ID)
+	assert.Equal(t, "7", results[1].ID)
+	assert.True(t, results[1].DBPresent)
+	assert.True(t, results[1].GamedataPresent)
+	assert.True(t, results[1].StoragePresent)
+}
+
+// TestReconcileOne_NormalizesQuery tests that a targeted lookup finds an entity by a near-miss key.
+func TestReconcileOne_NormalizesQuery(t *testing.T) {
+	adapter := &zeroTrimAdapter{&mockAdapter{
+		dbIndex:    map[string]DBItem{"7": "7"},
+		gdIndex:    map[string]GDItem{"007": "007"},
+		storageSet: map[string]struct{}{},
+		mismatches: map[string][]string{},
+	}}
+	mockClient := new(mocks.Client)
+	mockClient.On("BucketExists", mock.Anything, "").Return(true, nil)
+	spec := &Spec{Adapter: adapter, CacheTTL: time.Minute}
+	defer InvalidateCache(spec)
+
+	result, err := ReconcileOne(context.Background(), spec, nil, mockClient, "", Query{ID: "0007"})
+	require.NoError(t, err)
+	assert.Equal(t, "7", result.ID)
+	assert.True(t, result.DBPresent)
+	assert.True(t, result.GamedataPresent)
+}
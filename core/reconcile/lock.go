@@ -0,0 +1,260 @@
+package reconcile
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"asset-manager/core/json"
+	"asset-manager/core/storage"
+
+	"github.com/minio/minio-go/v7"
+	"go.uber.org/zap"
+)
+
+const (
+	// LockObject is the bucket object that marks a mutating reconcile run in progress.
+	LockObject = ".locks/reconcile.lock"
+
+	// DefaultLockTTL is how long a lock is honored without being renewed before it is
+	// considered stale, e.g. after the holding process crashed without releasing it.
+	DefaultLockTTL = 2 * time.Hour
+)
+
+// LockInfo describes the holder of the run lock.
+type LockInfo struct {
+	// Owner identifies the holding process (role, host and pid).
+	Owner string `json:"owner"`
+
+	// StartedAt is when the lock was acquired.
+	StartedAt time.Time `json:"started_at"`
+
+	// ExpiresAt is when the lock becomes stale and may be taken over.
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// LockedError is returned when another process holds the run lock.
+type LockedError struct {
+	Holder LockInfo
+}
+
+// Error implements error.
+func (e *LockedError) Error() string {
+	return fmt.Sprintf("another reconcile is running (owner %s, started at %s)",
+		e.Holder.Owner, e.Holder.StartedAt.Format(time.RFC3339))
+}
+
+// RunLock is a held run lock. The lock lives in the bucket, so it is shared by every
+// CLI invocation and server instance pointed at the same hotel. While held it is renewed
+// in the background, so a run longer than the TTL keeps it.
+type RunLock struct {
+	client storage.Client
+	bucket string
+	ttl    time.Duration
+
+	mu   sync.Mutex
+	info LockInfo
+	// etag is the ETag of the lock object this process wrote last.
+	etag string
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// lockOwner holds the role reported in lock owners ("cli", "server").
+var lockOwner = struct {
+	mu   sync.RWMutex
+	role string
+}{role: "cli"}
+
+// SetLockOwner sets the role recorded in locks acquired by this process.
+func SetLockOwner(role string) {
+	lockOwner.mu.Lock()
+	defer lockOwner.mu.Unlock()
+	lockOwner.role = role
+}
+
+// currentOwner describes this process for lock holders.
+func currentOwner() string {
+	lockOwner.mu.RLock()
+	role := lockOwner.role
+	lockOwner.mu.RUnlock()
+
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	return fmt.Sprintf("%s@%s (pid %d)", role, host, os.Getpid())
+}
+
+// AcquireRunLock takes the run lock for mutating operations. It returns a *LockedError
+// when another process holds a lock that has not expired. Stale locks are taken over.
+func AcquireRunLock(ctx context.Context, client storage.Client, bucket string, ttl time.Duration) (*RunLock, error) {
+	holder, etag, err := readLock(ctx, client, bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	// If-None-Match makes the write fail when another process created the lock
+	// between our read and this write. A stale lock is overwritten only while it
+	// still carries the ETag we read, so of two processes taking it over one wins.
+	opts := minio.PutObjectOptions{ContentType: "application/json"}
+	now := time.Now()
+	switch {
+	case holder == nil:
+		opts.SetMatchETagExcept("*")
+	case now.Before(holder.ExpiresAt):
+		return nil, &LockedError{Holder: *holder}
+	case etag == "":
+		return nil, fmt.Errorf("stale lock of %s has no ETag to take it over", holder.Owner)
+	default:
+		opts.SetMatchETag(etag)
+	}
+
+	info := LockInfo{Owner: currentOwner(), StartedAt: now, ExpiresAt: now.Add(ttl)}
+	written, err := writeLock(ctx, client, bucket, info, opts)
+	if err != nil {
+		if storage.IsPreconditionFailed(err) {
+			if holder, _, readErr := readLock(ctx, client, bucket); readErr == nil && holder != nil {
+				return nil, &LockedError{Holder: *holder}
+			}
+		}
+		return nil, fmt.Errorf("failed to write lock: %w", err)
+	}
+
+	lock := &RunLock{
+		client: client,
+		bucket: bucket,
+		ttl:    ttl,
+		info:   info,
+		etag:   written,
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	go lock.renew()
+	return lock, nil
+}
+
+// Info returns the lock as recorded in the bucket.
+func (l *RunLock) Info() LockInfo {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.info
+}
+
+// renew pushes the expiry out every third of the TTL until Release, conditioned on
+// the lock still being ours. It stops once another process took the lock over.
+func (l *RunLock) renew() {
+	defer close(l.done)
+	if l.ttl <= 0 {
+		<-l.stop
+		return
+	}
+	ticker := time.NewTicker(l.ttl / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+		}
+
+		l.mu.Lock()
+		info, etag := l.info, l.etag
+		l.mu.Unlock()
+		info.ExpiresAt = time.Now().Add(l.ttl)
+
+		opts := minio.PutObjectOptions{ContentType: "application/json"}
+		opts.SetMatchETag(etag)
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		written, err := writeLock(ctx, l.client, l.bucket, info, opts)
+		cancel()
+		switch {
+		case err == nil:
+			l.mu.Lock()
+			l.info, l.etag = info, written
+			l.mu.Unlock()
+		case storage.IsPreconditionFailed(err):
+			zap.L().Warn("Run lock was taken over by another process", zap.String("owner", info.Owner))
+			return
+		default:
+			zap.L().Warn("Failed to renew run lock", zap.Error(err))
+		}
+	}
+}
+
+// Release stops renewing the lock and removes it, unless another process has taken
+// it over in the meantime. It uses a fresh context so that a cancelled run still
+// releases its lock.
+func (l *RunLock) Release() error {
+	close(l.stop)
+	<-l.done
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	holder, etag, err := readLock(ctx, l.client, l.bucket)
+	if err != nil {
+		return fmt.Errorf("failed to release lock: %w", err)
+	}
+	if holder == nil {
+		return nil
+	}
+	l.mu.Lock()
+	owned := etag == l.etag
+	l.mu.Unlock()
+	if !owned {
+		return fmt.Errorf("failed to release lock: it was taken over by %s", holder.Owner)
+	}
+
+	if err := l.client.RemoveObject(ctx, l.bucket, LockObject, minio.RemoveObjectOptions{}); err != nil {
+		return fmt.Errorf("failed to release lock: %w", err)
+	}
+	return nil
+}
+
+// writeLock stores info as the lock under the conditions of opts and returns the ETag
+// of the written object.
+func writeLock(ctx context.Context, client storage.Client, bucket string, info LockInfo, opts minio.PutObjectOptions) (string, error) {
+	data, err := json.Marshal(info)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal lock: %w", err)
+	}
+	written, err := client.PutObject(ctx, bucket, LockObject, bytes.NewReader(data), int64(len(data)), opts)
+	if err != nil {
+		return "", err
+	}
+	return written.ETag, nil
+}
+
+// readLock returns the current lock holder and the ETag of the lock object, or nil
+// when the lock is free.
+func readLock(ctx context.Context, client storage.Client, bucket string) (*LockInfo, string, error) {
+	reader, err := client.GetObject(ctx, bucket, LockObject, minio.GetObjectOptions{})
+	if err != nil {
+		if storage.IsNoSuchKey(err) {
+			return nil, "", nil
+		}
+		return nil, "", fmt.Errorf("failed to get lock: %w", err)
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		if storage.IsNoSuchKey(err) {
+			return nil, "", nil
+		}
+		return nil, "", fmt.Errorf("failed to read lock: %w", err)
+	}
+
+	var info LockInfo
+	if err := json.Unmarshal(data, &info); err != nil {
+		return nil, "", fmt.Errorf("failed to parse lock: %w", err)
+	}
+	return &info, storage.ObjectETag(reader), nil
+}
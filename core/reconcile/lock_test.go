@@ -0,0 +1,200 @@
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"asset-manager/core/json"
+	"asset-manager/core/storage/mocks"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// etagReader is a lock object reader that reports an ETag like *minio.Object.
+type etagReader struct {
+	io.Reader
+	etag string
+}
+
+func (r *etagReader) Close() error { return nil }
+
+func (r *etagReader) Stat() (minio.ObjectInfo, error) {
+	return minio.ObjectInfo{ETag: r.etag}, nil
+}
+
+// lockReader returns a reader over the serialized lock info with the given ETag.
+func lockReader(t *testing.T, info LockInfo, etag string) io.ReadCloser {
+	data, err := json.Marshal(info)
+	require.NoError(t, err)
+	return &etagReader{Reader: strings.NewReader(string(data)), etag: etag}
+}
+
+// ifMatch matches put options conditioned on etag.
+func ifMatch(etag string) any {
+	return mock.MatchedBy(func(opts minio.PutObjectOptions) bool {
+		return opts.Header().Get("If-Match") == `"`+etag+`"`
+	})
+}
+
+// TestAcquireRunLock_Free tests acquiring and releasing a free lock.
+func TestAcquireRunLock_Free(t *testing.T) {
+	SetLockOwner("server")
+	defer SetLockOwner("cli")
+
+	mockClient := new(mocks.Client)
+	mockClient.On("GetObject", mock.Anything, "assets", LockObject, mock.Anything).
+		Return(io.ReadCloser(nil), minio.ErrorResponse{Code: "NoSuchKey"}).Once()
+	mockClient.On("PutObject", mock.Anything, "assets", LockObject, mock.Anything, mock.Anything, mock.MatchedBy(func(opts minio.PutObjectOptions) bool {
+		return opts.Header().Get("If-None-Match") == "*"
+	})).Return(minio.UploadInfo{ETag: "mine"}, nil)
+	mockClient.On("RemoveObject", mock.Anything, "assets", LockObject, mock.Anything).Return(nil)
+
+	lock, err := AcquireRunLock(context.Background(), mockClient, "assets", time.Hour)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(lock.Info().Owner, "server@"))
+
+	mockClient.On("GetObject", mock.Anything, "assets", LockObject, mock.Anything).
+		Return(lockReader(t, lock.Info(), "mine"), nil).Once()
+
+	assert.NoError(t, lock.Release())
+	mockClient.AssertExpectations(t)
+}
+
+// TestAcquireRunLock_Held tests that a live lock reports its holder.
+func TestAcquireRunLock_Held(t *testing.T) {
+	started := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	holder := LockInfo{Owner: "server@host (pid 1)", StartedAt: started, ExpiresAt: time.Now().Add(time.Hour)}
+
+	mockClient := new(mocks.Client)
+	mockClient.On("GetObject", mock.Anything, "assets", LockObject, mock.Anything).
+		Return(lockReader(t, holder, "theirs"), nil)
+
+	_, err := AcquireRunLock(context.Background(), mockClient, "assets", time.Hour)
+
+	var locked *LockedError
+	require.True(t, errors.As(err, &locked))
+	assert.Equal(t, "server@host (pid 1)", locked.Holder.Owner)
+	assert.Equal(t, "another reconcile is running (owner server@host (pid 1), started at 2026-01-02T03:04:05Z)", err.Error())
+	mockClient.AssertNotCalled(t, "PutObject", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestAcquireRunLock_Stale tests that an expired lock is overwritten while it still
+// carries the ETag that was read.
+func TestAcquireRunLock_Stale(t *testing.T) {
+	holder := LockInfo{Owner: "cli@host (pid 2)", StartedAt: time.Now().Add(-3 * time.Hour), ExpiresAt: time.Now().Add(-time.Hour)}
+
+	mockClient := new(mocks.Client)
+	mockClient.On("GetObject", mock.Anything, "assets", LockObject, mock.Anything).
+		Return(lockReader(t, holder, "stale"), nil)
+	mockClient.On("PutObject", mock.Anything, "assets", LockObject, mock.Anything, mock.Anything, ifMatch("stale")).
+		Return(minio.UploadInfo{ETag: "mine"}, nil)
+
+	lock, err := AcquireRunLock(context.Background(), mockClient, "assets", time.Hour)
+	require.NoError(t, err)
+	assert.True(t, lock.Info().ExpiresAt.After(time.Now()))
+	mockClient.AssertExpectations(t)
+	mockClient.AssertNotCalled(t, "RemoveObject", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestAcquireRunLock_StaleRace tests that of two processes taking over the same stale
+// lock, the one whose conditional write fails reports the winner.
+func TestAcquireRunLock_StaleRace(t *testing.T) {
+	stale := LockInfo{Owner: "cli@host (pid 2)", StartedAt: time.Now().Add(-3 * time.Hour), ExpiresAt: time.Now().Add(-time.Hour)}
+	winner := LockInfo{Owner: "server@host (pid 3)", StartedAt: time.Now(), ExpiresAt: time.Now().Add(time.Hour)}
+
+	mockClient := new(mocks.Client)
+	mockClient.On("GetObject", mock.Anything, "assets", LockObject, mock.Anything).
+		Return(lockReader(t, stale, "stale"), nil).Once()
+	mockClient.On("GetObject", mock.Anything, "assets", LockObject, mock.Anything).
+		Return(lockReader(t, winner, "winner"), nil).Once()
+	mockClient.On("PutObject", mock.Anything, "assets", LockObject, mock.Anything, mock.Anything, ifMatch("stale")).
+		Return(minio.UploadInfo{}, minio.ErrorResponse{Code: "PreconditionFailed"})
+
+	_, err := AcquireRunLock(context.Background(), mockClient, "assets", time.Hour)
+
+	var locked *LockedError
+	require.True(t, errors.As(err, &locked))
+	assert.Equal(t, "server@host (pid 3)", locked.Holder.Owner)
+}
+
+// TestAcquireRunLock_Race tests that losing the conditional write reports the winner.
+func TestAcquireRunLock_Race(t *testing.T) {
+	winner := LockInfo{Owner: "server@host (pid 3)", StartedAt: time.Now(), ExpiresAt: time.Now().Add(time.Hour)}
+
+	mockClient := new(mocks.Client)
+	mockClient.On("GetObject", mock.Anything, "assets", LockObject, mock.Anything).
+		Return(io.ReadCloser(nil), minio.ErrorResponse{Code: "NoSuchKey"}).Once()
+	mockClient.On("GetObject", mock.Anything, "assets", LockObject, mock.Anything).
+		Return(lockReader(t, winner, "winner"), nil).Once()
+	mockClient.On("PutObject", mock.Anything, "assets", LockObject, mock.Anything, mock.Anything, mock.Anything).
+		Return(minio.UploadInfo{}, minio.ErrorResponse{Code: "PreconditionFailed"})
+
+	_, err := AcquireRunLock(context.Background(), mockClient, "assets", time.Hour)
+
+	var locked *LockedError
+	require.True(t, errors.As(err, &locked))
+	assert.Equal(t, "server@host (pid 3)", locked.Holder.Owner)
+}
+
+// TestRunLock_ReleaseAfterTakeover tests that a lock taken over by another process is
+// left in place on release.
+func TestRunLock_ReleaseAfterTakeover(t *testing.T) {
+	other := LockInfo{Owner: "server@host (pid 4)", StartedAt: time.Now(), ExpiresAt: time.Now().Add(time.Hour)}
+
+	mockClient := new(mocks.Client)
+	mockClient.On("GetObject", mock.Anything, "assets", LockObject, mock.Anything).
+		Return(io.ReadCloser(nil), minio.ErrorResponse{Code: "NoSuchKey"}).Once()
+	mockClient.On("PutObject", mock.Anything, "assets", LockObject, mock.Anything, mock.Anything, mock.Anything).
+		Return(minio.UploadInfo{ETag: "mine"}, nil)
+
+	lock, err := AcquireRunLock(context.Background(), mockClient, "assets", time.Hour)
+	require.NoError(t, err)
+
+	mockClient.On("GetObject", mock.Anything, "assets", LockObject, mock.Anything).
+		Return(lockReader(t, other, "theirs"), nil).Once()
+	err = lock.Release()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "taken over by server@host (pid 4)")
+	mockClient.AssertNotCalled(t, "RemoveObject", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestRunLock_Renew tests that a held lock is renewed against its own ETag and that
+// release follows the renewed ETag.
+func TestRunLock_Renew(t *testing.T) {
+	mockClient := new(mocks.Client)
+	mockClient.On("GetObject", mock.Anything, "assets", LockObject, mock.Anything).
+		Return(io.ReadCloser(nil), minio.ErrorResponse{Code: "NoSuchKey"}).Once()
+	mockClient.On("PutObject", mock.Anything, "assets", LockObject, mock.Anything, mock.Anything, mock.MatchedBy(func(opts minio.PutObjectOptions) bool {
+		return opts.Header().Get("If-None-Match") == "*"
+	})).Return(minio.UploadInfo{ETag: "first"}, nil).Once()
+	renewed := make(chan struct{})
+	mockClient.On("PutObject", mock.Anything, "assets", LockObject, mock.Anything, mock.Anything, ifMatch("first")).
+		Run(func(mock.Arguments) { close(renewed) }).
+		Return(minio.UploadInfo{ETag: "renewed"}, nil).Once()
+	mockClient.On("PutObject", mock.Anything, "assets", LockObject, mock.Anything, mock.Anything, ifMatch("renewed")).
+		Return(minio.UploadInfo{ETag: "renewed"}, nil).Maybe()
+	mockClient.On("RemoveObject", mock.Anything, "assets", LockObject, mock.Anything).Return(nil)
+
+	lock, err := AcquireRunLock(context.Background(), mockClient, "assets", 30*time.Millisecond)
+	require.NoError(t, err)
+	started := lock.Info().ExpiresAt
+
+	select {
+	case <-renewed:
+	case <-time.After(time.Second):
+		t.Fatal("lock was not renewed")
+	}
+	require.Eventually(t, func() bool { return lock.Info().ExpiresAt.After(started) }, time.Second, time.Millisecond)
+
+	mockClient.On("GetObject", mock.Anything, "assets", LockObject, mock.Anything).
+		Return(lockReader(t, lock.Info(), "renewed"), nil).Once()
+	require.NoError(t, lock.Release())
+	mockClient.AssertCalled(t, "RemoveObject", mock.Anything, "assets", LockObject, mock.Anything)
+}
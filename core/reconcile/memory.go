@@ -0,0 +1,198 @@
+package reconcile
+
+import (
+	"reflect"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// heapSampleInterval is how often the heap is sampled while a run is in progress.
+const heapSampleInterval = 50 * time.Millisecond
+
+// sizeSampleLimit caps how many index entries are measured before extrapolating.
+const sizeSampleLimit = 1000
+
+// mapEntryOverhead approximates per-entry bucket overhead (tophash, padding, load factor).
+const mapEntryOverhead = 16
+
+// IndexStats describes the size of one reconcile index.
+type IndexStats struct {
+	// Entries is the number of keys in the index.
+	Entries int `json:"entries"`
+
+	// ApproxBytes is the estimated memory held by the index (keys, values and map overhead).
+	ApproxBytes int64 `json:"approx_bytes"`
+}
+
+// MemoryStats reports memory usage of a reconcile run, for sizing deployments.
+type MemoryStats struct {
+	// PeakHeapBytes is the highest sampled live heap during the run.
+	PeakHeapBytes uint64 `json:"peak_heap_bytes"`
+
+	// SysBytes is the total memory obtained from the OS at the end of the run.
+	SysBytes uint64 `json:"sys_bytes"`
+
+	// DBIndex describes the database index.
+	DBIndex IndexStats `json:"db_index"`
+
+	// GamedataIndex describes the gamedata index.
+	GamedataIndex IndexStats `json:"gamedata_index"`
+
+	// StorageSet describes the storage key set.
+	StorageSet IndexStats `json:"storage_set"`
+
+	// Sources describes the index of each additional source by name.
+	Sources map[string]IndexStats `json:"sources,omitempty"`
+
+	// IndexBytes is the estimated total memory held by all indices.
+	IndexBytes int64 `json:"index_bytes"`
+}
+
+// heapSampler tracks the peak heap size in the background until stopped.
+type heapSampler struct {
+	mu       sync.Mutex
+	peak     uint64
+	done     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+// startHeapSampler begins sampling the heap until stop is called.
+func startHeapSampler() *heapSampler {
+	s := &heapSampler{done: make(chan struct{})}
+	s.sample()
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(heapSampleInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.done:
+				return
+			case <-ticker.C:
+				s.sample()
+			}
+		}
+	}()
+
+	return s
+}
+
+// sample records the current heap size if it is a new peak and returns the stats read.
+func (s *heapSampler) sample() runtime.MemStats {
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+
+	s.mu.Lock()
+	if ms.HeapAlloc > s.peak {
+		s.peak = ms.HeapAlloc
+	}
+	s.mu.Unlock()
+
+	return ms
+}
+
+// stop ends background sampling. It is safe to call more than once.
+func (s *heapSampler) stop() {
+	s.stopOnce.Do(func() {
+		close(s.done)
+		s.wg.Wait()
+	})
+}
+
+// Stats stops sampling and returns memory stats for the run, including index sizes from cache.
+func (s *heapSampler) Stats(cache *ReconcileCache) *MemoryStats {
+	s.stop()
+	ms := s.sample()
+
+	stats := &MemoryStats{
+		PeakHeapBytes: s.peak,
+		SysBytes:      ms.Sys,
+	}
+	if cache != nil {
+		stats.DBIndex = measureIndex(cache.DBIndex)
+		stats.GamedataIndex = measureIndex(cache.GDIndex)
+		stats.StorageSet = measureIndex(cache.StorageSet)
+		stats.IndexBytes = stats.DBIndex.ApproxBytes + stats.GamedataIndex.ApproxBytes + stats.StorageSet.ApproxBytes
+		for name, index := range cache.Extra {
+			if stats.Sources == nil {
+				stats.Sources = make(map[string]IndexStats, len(cache.Extra))
+			}
+			stats.Sources[name] = measureIndex(index)
+			stats.IndexBytes += stats.Sources[name].ApproxBytes
+		}
+	}
+	return stats
+}
+
+// measureIndex estimates the size of a string-keyed index by measuring up to
+// sizeSampleLimit entries and extrapolating to the whole map.
+func measureIndex[V any](index map[string]V) IndexStats {
+	stats := IndexStats{Entries: len(index)}
+	if len(index) == 0 {
+		return stats
+	}
+
+	var sampled, sampledBytes int64
+	for key, value := range index {
+		sampledBytes += int64(len(key)) + 16 + approxSize(reflect.ValueOf(&value).Elem()) + mapEntryOverhead
+		sampled++
+		if sampled >= sizeSampleLimit {
+			break
+		}
+	}
+
+	stats.ApproxBytes = sampledBytes * int64(len(index)) / sampled
+	return stats
+}
+
+// approxSize estimates the bytes held by v, following strings, slices, maps,
+// pointers and interfaces. Shared memory is counted once per reference.
+func approxSize(v reflect.Value) int64 {
+	switch v.Kind() {
+	case reflect.String:
+		return 16 + int64(v.Len())
+	case reflect.Slice:
+		size := int64(24)
+		for i := 0; i < v.Len(); i++ {
+			size += approxSize(v.Index(i))
+		}
+		return size
+	case reflect.Array:
+		var size int64
+		for i := 0; i < v.Len(); i++ {
+			size += approxSize(v.Index(i))
+		}
+		return size
+	case reflect.Struct:
+		var size int64
+		for i := 0; i < v.NumField(); i++ {
+			size += approxSize(v.Field(i))
+		}
+		return size
+	case reflect.Map:
+		size := int64(48)
+		iter := v.MapRange()
+		for iter.Next() {
+			size += approxSize(iter.Key()) + approxSize(iter.Value()) + mapEntryOverhead
+		}
+		return size
+	case reflect.Pointer:
+		if v.IsNil() {
+			return 8
+		}
+		return 8 + approxSize(v.Elem())
+	case reflect.Interface:
+		if v.IsNil() {
+			return 16
+		}
+		return 16 + approxSize(v.Elem())
+	case reflect.Invalid:
+		return 0
+	default:
+		return int64(v.Type().Size())
+	}
+}
@@ -0,0 +1,76 @@
+package reconcile
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"asset-manager/core/storage/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestApproxSize tests size estimation of common index value shapes.
+func TestApproxSize(t *testing.T) {
+	type item struct {
+		ID     int
+		Name   string
+		Colors []string
+	}
+
+	tests := []struct {
+		name  string
+		value any
+		want  int64
+	}{
+		{"int", 42, 8},
+		{"string", "abcd", 20},
+		{"struct", item{ID: 1, Name: "ab", Colors: []string{"c"}}, 8 + 18 + 24 + 17},
+		{"nil pointer", (*item)(nil), 8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, approxSize(reflect.ValueOf(tt.value)))
+		})
+	}
+}
+
+// TestMeasureIndex tests that index measurement counts entries and extrapolates bytes.
+func TestMeasureIndex(t *testing.T) {
+	assert.Equal(t, IndexStats{}, measureIndex(map[string]struct{}{}))
+
+	index := make(map[string]struct{})
+	for i := 0; i < sizeSampleLimit*2; i++ {
+		index[string(rune('a'+i%26))+string(rune(i))] = struct{}{}
+	}
+	stats := measureIndex(index)
+	assert.Equal(t, len(index), stats.Entries)
+	assert.Greater(t, stats.ApproxBytes, int64(len(index)*(16+mapEntryOverhead)))
+}
+
+// TestReconcileWithPlan_MemoryStats tests that plans report peak heap and index sizes.
+func TestReconcileWithPlan_MemoryStats(t *testing.T) {
+	adapter := &mockAdapter{
+		dbIndex:    map[string]DBItem{"1": "item1", "2": "item2"},
+		gdIndex:    map[string]GDItem{"1": "item1"},
+		storageSet: map[string]struct{}{"1": {}, "2": {}, "3": {}},
+		mismatches: map[string][]string{},
+	}
+	spec := &Spec{Adapter: adapter}
+
+	mockClient := new(mocks.Client)
+	mockClient.On("BucketExists", mock.Anything, "").Return(true, nil)
+
+	plan, err := ReconcileWithPlan(context.Background(), spec, nil, mockClient, "", ReconcileOptions{DryRun: true})
+	assert.NoError(t, err)
+	if assert.NotNil(t, plan.Summary.Memory) {
+		m := plan.Summary.Memory
+		assert.Greater(t, m.PeakHeapBytes, uint64(0))
+		assert.Equal(t, 2, m.DBIndex.Entries)
+		assert.Equal(t, 1, m.GamedataIndex.Entries)
+		assert.Equal(t, 3, m.StorageSet.Entries)
+		assert.Equal(t, m.DBIndex.ApproxBytes+m.GamedataIndex.ApproxBytes+m.StorageSet.ApproxBytes, m.IndexBytes)
+	}
+}
@@ -0,0 +1,51 @@
+package reconcile
+
+import (
+	"context"
+	"maps"
+)
+
+// StorageMover extends Mutator for adapters whose storage objects can be found outside
+// their canonical path, e.g. nested in a subfolder of the storage prefix. Their
+// storage loaders report such objects with RecordMisplaced; syncs then plan an
+// ActionMoveStorage that relocates the object instead of leaving it where the client
+// cannot load it, or deleting it only to fetch it again.
+type StorageMover interface {
+	// MoveStorage moves the storage object of key from the object it was found at to
+	// its canonical path.
+	MoveStorage(ctx context.Context, key, from string) error
+}
+
+// RecordMisplaced reports that the storage object of key was found at object rather
+// than at its canonical path, which holds nothing. Adapters call it from
+// LoadStorageSet; the key still counts as present in storage. It is a no-op when ctx
+// carries no recorder (see WithDiagnostics).
+func RecordMisplaced(ctx context.Context, key, object string) {
+	if recorder := recorderFrom(ctx); recorder != nil {
+		recorder.mu.Lock()
+		defer recorder.mu.Unlock()
+		if _, ok := recorder.misplaced[key]; !ok {
+			recorder.misplaced[key] = object
+		}
+	}
+}
+
+// Misplaced returns the misplaced storage objects recorded so far, by entity key.
+func (r *DiagnosticsRecorder) Misplaced() map[string]string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return maps.Clone(r.misplaced)
+}
+
+// planMove returns the move action of a result whose storage object is misplaced.
+func planMove(result ReconcileResult) (Action, bool) {
+	if !result.StoragePresent || result.Misplaced == "" {
+		return Action{}, false
+	}
+	return Action{
+		Type:   ActionMoveStorage,
+		Key:    result.ID,
+		Reason: "misplaced at " + result.Misplaced,
+		From:   result.Misplaced,
+	}, true
+}
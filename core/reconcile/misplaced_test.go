@@ -0,0 +1,98 @@
+package reconcile
+
+import (
+	"context"
+	"testing"
+
+	"asset-manager/core/storage"
+	"asset-manager/core/storage/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// mockMover implements StorageMover, recording the objects it moved by key.
+type mockMover struct {
+	mockMutator
+	moved map[string]string
+}
+
+func (m *mockMover) MoveStorage(ctx context.Context, key, from string) error {
+	m.moved[key] = from
+	return nil
+}
+
+// newMisplacedMover returns a mover whose storage loader reports key 2 as misplaced.
+func newMisplacedMover() *mockMover {
+	mover := &mockMover{moved: make(map[string]string)}
+	mover.dbIndex = map[string]DBItem{"1": "1", "2": "2"}
+	mover.gdIndex = map[string]GDItem{"1": "1", "2": "2"}
+	mover.storageLoadFunc = func(ctx context.Context, client storage.Client, bucket, prefix, extension string) (map[string]struct{}, error) {
+		RecordMisplaced(ctx, "2", "furniture/old/2.nitro")
+		RecordMisplaced(ctx, "2", "furniture/older/2.nitro")
+		return map[string]struct{}{"1": {}, "2": {}}, nil
+	}
+	return mover
+}
+
+// TestReconcileWithPlan_MoveActions tests that syncs plan moves of misplaced objects
+// for movers only, and that purges do not.
+func TestReconcileWithPlan_MoveActions(t *testing.T) {
+	mockClient := new(mocks.Client)
+	mockClient.On("BucketExists", mock.Anything, "").Return(true, nil)
+
+	mover := newMisplacedMover()
+	plan, err := ReconcileWithPlan(context.Background(), &Spec{Adapter: mover}, nil, mockClient, "", ReconcileOptions{DoSync: true})
+	require.NoError(t, err)
+	assert.Equal(t, 1, plan.Summary.Misplaced)
+	assert.Equal(t, 1, plan.Summary.MoveActions)
+	assert.Equal(t, []Action{{
+		Type:   ActionMoveStorage,
+		Key:    "2",
+		Reason: "misplaced at furniture/old/2.nitro",
+		From:   "furniture/old/2.nitro",
+	}}, plan.Actions)
+
+	plan, err = ReconcileWithPlan(context.Background(), &Spec{Adapter: newMisplacedMover()}, nil, mockClient, "", ReconcileOptions{DoPurge: true})
+	require.NoError(t, err)
+	assert.Equal(t, 1, plan.Summary.Misplaced)
+	assert.Empty(t, plan.Actions)
+
+	// Adapters that cannot move only report the misplaced object
+	adapter := &mockAdapter{storageLoadFunc: mover.storageLoadFunc}
+	plan, err = ReconcileWithPlan(context.Background(), &Spec{Adapter: adapter}, nil, mockClient, "", ReconcileOptions{DoSync: true})
+	require.NoError(t, err)
+	assert.Equal(t, 1, plan.Summary.Misplaced)
+	assert.Zero(t, plan.Summary.MoveActions)
+}
+
+// TestApplyPlan_MoveStorage tests that moves run through the mover, are refused for
+// other adapters, and are verified against the misplaced objects left.
+func TestApplyPlan_MoveStorage(t *testing.T) {
+	plan := &ReconcilePlan{Actions: []Action{{Type: ActionMoveStorage, Key: "2", From: "furniture/old/2.nitro"}}}
+	opts := ReconcileOptions{DoSync: true, Confirmed: true}
+
+	mover := &mockMover{moved: make(map[string]string)}
+	executed, err := ApplyPlan(context.Background(), &Spec{Adapter: mover}, nil, nil, "", plan, opts)
+	require.NoError(t, err)
+	assert.Equal(t, 1, executed)
+	assert.Equal(t, map[string]string{"2": "furniture/old/2.nitro"}, mover.moved)
+
+	_, err = ApplyPlan(context.Background(), &Spec{Adapter: &mockMutator{}}, nil, nil, "", plan, opts)
+	assert.ErrorContains(t, err, "does not implement StorageMover")
+
+	assert.Equal(t, "still misplaced at furniture/old/2.nitro",
+		verifyAction(plan.Actions[0], ReconcileResult{StoragePresent: true, Misplaced: "furniture/old/2.nitro"}))
+	assert.Equal(t, "missing in storage after the move", verifyAction(plan.Actions[0], ReconcileResult{}))
+	assert.Empty(t, verifyAction(plan.Actions[0], ReconcileResult{StoragePresent: true}))
+}
+
+// TestSuggestActions_Misplaced tests that single-item repairs suggest moving a
+// misplaced object.
+func TestSuggestActions_Misplaced(t *testing.T) {
+	actions := SuggestActions(ReconcileResult{ID: "2", DBPresent: true, GamedataPresent: true, StoragePresent: true, Misplaced: "furniture/old/2.nitro"})
+	require.Len(t, actions, 1)
+	assert.Equal(t, ActionMoveStorage, actions[0].Type)
+	assert.Equal(t, "furniture/old/2.nitro", actions[0].From)
+}
@@ -0,0 +1,219 @@
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"asset-manager/core/storage"
+
+	"gorm.io/gorm"
+)
+
+// DefaultAdapterConcurrency is how many adapters PlanAll and ApplyAll run at once when
+// no limit is given.
+const DefaultAdapterConcurrency = 4
+
+// AdapterRun is the outcome of one adapter in a multi-adapter reconcile.
+type AdapterRun struct {
+	// Adapter is the adapter name (e.g. "furniture").
+	Adapter string `json:"adapter"`
+
+	// Plan is the adapter's plan. Nil when planning failed.
+	Plan *ReconcilePlan `json:"plan,omitempty"`
+
+	// Executed is the number of actions ApplyAll carried out.
+	Executed int `json:"executed"`
+
+	// Skipped explains why ApplyAll left the planned actions alone, e.g. because the
+	// adapter cannot mutate.
+	Skipped string `json:"skipped,omitempty"`
+
+	// Error is set when the adapter could not be planned, applied or verified. Other
+	// adapters carry on regardless.
+	Error string `json:"error,omitempty"`
+
+	// spec is the spec the run was planned with.
+	spec *Spec
+}
+
+// MultiReport combines the runs of every adapter of a multi-adapter reconcile.
+type MultiReport struct {
+	// RunID identifies the run all adapters were planned in (see WithRunID).
+	RunID string `json:"run_id"`
+
+	// Adapters lists the per-adapter runs, in the order of the specs.
+	Adapters []AdapterRun `json:"adapters"`
+
+	// Summary adds up the summaries of every planned adapter.
+	Summary PlanSummary `json:"summary"`
+
+	// Failed counts the adapters whose run ended with an error.
+	Failed int `json:"failed"`
+}
+
+// Actions returns the number of actions planned across all adapters.
+func (r *MultiReport) Actions() int {
+	total := 0
+	for _, run := range r.Adapters {
+		if run.Plan != nil {
+			total += len(run.Plan.Actions)
+		}
+	}
+	return total
+}
+
+// PlanAll plans every spec with opts, running up to concurrency adapters at once (0
+// means DefaultAdapterConcurrency). Mutating options are checked and the schema is
+// prepared first, per adapter, as a single-adapter run does. An adapter that fails is
+// reported in its run and does not stop the others.
+func PlanAll(
+	ctx context.Context,
+	specs []*Spec,
+	db *gorm.DB,
+	client storage.Client,
+	bucket string,
+	opts ReconcileOptions,
+	concurrency int,
+) *MultiReport {
+	ctx, runID := StartRun(ctx)
+	report := &MultiReport{RunID: runID, Adapters: make([]AdapterRun, len(specs))}
+	forEachBounded(len(specs), concurrency, func(i int) {
+		spec := specs[i]
+		run := AdapterRun{Adapter: spec.Adapter.Name(), spec: spec}
+		plan, err := planAdapter(ctx, spec, db, client, bucket, opts)
+		if err != nil {
+			run.Error = err.Error()
+		}
+		run.Plan = plan
+		report.Adapters[i] = run
+	})
+
+	for _, run := range report.Adapters {
+		if run.Error != "" {
+			report.Failed++
+			continue
+		}
+		addSummary(&report.Summary, run.Plan.Summary)
+	}
+	return report
+}
+
+// ApplyAll applies and verifies the plan of every adapter PlanAll planned, up to
+// concurrency adapters at once. Adapters that failed to plan, have nothing to do or
+// do not implement Mutator are left alone. It returns the number of executed actions;
+// failures are reported in the runs and counted in report.Failed.
+func ApplyAll(
+	ctx context.Context,
+	report *MultiReport,
+	db *gorm.DB,
+	client storage.Client,
+	bucket string,
+	opts ReconcileOptions,
+	concurrency int,
+) int {
+	if RunIDFrom(ctx) == "" {
+		ctx = WithRunID(ctx, report.RunID)
+	}
+	forEachBounded(len(report.Adapters), concurrency, func(i int) {
+		run := &report.Adapters[i]
+		if run.Error != "" || run.Plan == nil || len(run.Plan.Actions) == 0 {
+			return
+		}
+		if _, ok := run.spec.Adapter.(Mutator); !ok {
+			run.Skipped = fmt.Sprintf("adapter %s does not implement Mutator interface", run.Adapter)
+			return
+		}
+
+		executed, err := ApplyPlan(ctx, run.spec, db, client, bucket, run.Plan, opts)
+		run.Executed = executed
+		if err != nil {
+			run.Error = fmt.Sprintf("failed to apply plan %s after %d actions: %v", run.Plan.ID, executed, err)
+			return
+		}
+		if _, err := VerifyPlan(ctx, run.spec, db, client, bucket, run.Plan); err != nil {
+			run.Error = fmt.Sprintf("failed to verify plan: %v", err)
+		}
+	})
+
+	executed := 0
+	report.Failed = 0
+	for _, run := range report.Adapters {
+		executed += run.Executed
+		if run.Error != "" {
+			report.Failed++
+		}
+	}
+	return executed
+}
+
+// planAdapter preflights, prepares and plans one spec.
+func planAdapter(ctx context.Context, spec *Spec, db *gorm.DB, client storage.Client, bucket string, opts ReconcileOptions) (*ReconcilePlan, error) {
+	if opts.mutates() {
+		if _, err := CheckPermissions(ctx, spec, db, client, bucket, opts); err != nil {
+			return nil, fmt.Errorf("permissions preflight failed: %w", err)
+		}
+		if err := spec.Adapter.Prepare(ctx, db); err != nil {
+			return nil, fmt.Errorf("failed to prepare schema: %w", err)
+		}
+	}
+	plan, err := ReconcileWithPlan(ctx, spec, db, client, bucket, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to plan reconciliation: %w", err)
+	}
+	return plan, nil
+}
+
+// mutates reports whether the options request any purge, sync or repair.
+func (o ReconcileOptions) mutates() bool {
+	return !o.DryRun && (o.DoPurge || o.DoSync || o.DoFixStorage || o.DoInsertGamedata)
+}
+
+// forEachBounded calls fn for 0..n-1 with at most limit calls running at once (0 means
+// DefaultAdapterConcurrency), and returns when all are done.
+func forEachBounded(n, limit int, fn func(i int)) {
+	if limit <= 0 {
+		limit = DefaultAdapterConcurrency
+	}
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fn(i)
+		}()
+	}
+	wg.Wait()
+}
+
+// addSummary adds the counts of s to total. Memory stats are per run and not added.
+func addSummary(total *PlanSummary, s PlanSummary) {
+	total.TotalItems += s.TotalItems
+	total.MissingGamedata += s.MissingGamedata
+	total.MissingStorage += s.MissingStorage
+	total.MissingDB += s.MissingDB
+	total.Mismatches += s.Mismatches
+	total.Flapping += s.Flapping
+	total.Pending += s.Pending
+	total.ExpectedAbsent += s.ExpectedAbsent
+	total.Ignored += s.Ignored
+	total.KeyConflicts += s.KeyConflicts
+	total.DuplicateNames += s.DuplicateNames
+	total.Misplaced += s.Misplaced
+	total.RecentOrphans += s.RecentOrphans
+	total.StaleOrphans += s.StaleOrphans
+	total.PurgeActions += s.PurgeActions
+	total.SyncActions += s.SyncActions
+	total.MoveActions += s.MoveActions
+	total.DownloadActions += s.DownloadActions
+	total.InsertActions += s.InsertActions
+	for source, count := range s.MissingSources {
+		if total.MissingSources == nil {
+			total.MissingSources = make(map[string]int)
+		}
+		total.MissingSources[source] += count
+	}
+}
@@ -0,0 +1,95 @@
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"asset-manager/core/storage/mocks"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// namedMutator is a mockMutator with its own adapter name.
+type namedMutator struct {
+	mockMutator
+	name string
+}
+
+func (m *namedMutator) Name() string {
+	return m.name
+}
+
+// TestPlanAll tests that every spec is planned, that summaries add up and that a failing
+// adapter does not stop the others.
+func TestPlanAll(t *testing.T) {
+	mockClient := new(mocks.Client)
+	mockClient.On("BucketExists", mock.Anything, "").Return(true, nil)
+
+	badges := &namedMutator{name: "badges"}
+	badges.dbIndex = map[string]DBItem{"1": "1", "2": "2"}
+	badges.gdIndex = map[string]GDItem{"1": "1"}
+	badges.storageSet = map[string]struct{}{"1": {}}
+
+	furniture := &namedMutator{name: "furniture"}
+	furniture.dbIndex = map[string]DBItem{"1": "1"}
+	furniture.gdIndex = map[string]GDItem{"1": "1"}
+	furniture.storageSet = map[string]struct{}{"1": {}, "orphan": {}}
+
+	broken := &namedAdapter{name: "pets"}
+	broken.dbLoadFunc = func(context.Context, *gorm.DB, string) (map[string]DBItem, error) {
+		return nil, errors.New("table missing")
+	}
+
+	specs := []*Spec{{Adapter: badges}, {Adapter: furniture}, {Adapter: broken}}
+	report := PlanAll(context.Background(), specs, nil, mockClient, "", ReconcileOptions{DoPurge: true, DryRun: true}, 2)
+
+	require.Len(t, report.Adapters, 3)
+	assert.Equal(t, []string{"badges", "furniture", "pets"},
+		[]string{report.Adapters[0].Adapter, report.Adapters[1].Adapter, report.Adapters[2].Adapter})
+	assert.Contains(t, report.Adapters[2].Error, "table missing")
+	assert.Nil(t, report.Adapters[2].Plan)
+	assert.Equal(t, 1, report.Failed)
+
+	assert.Equal(t, 4, report.Summary.TotalItems)
+	assert.Equal(t, 2, report.Summary.MissingGamedata)
+	assert.Equal(t, 1, report.Summary.MissingDB)
+	assert.Equal(t, 1, report.Summary.MissingStorage)
+	assert.Equal(t, 2, report.Summary.PurgeActions)
+	assert.Equal(t, 2, report.Actions())
+}
+
+// TestApplyAll tests that plans are applied per adapter, and that adapters that cannot
+// mutate are skipped.
+func TestApplyAll(t *testing.T) {
+	mockClient := new(mocks.Client)
+	mockClient.On("BucketExists", mock.Anything, "").Return(true, nil)
+	// Mutating plans are preflighted first
+	mockClient.On("RemoveObject", mock.Anything, "", mock.Anything, mock.Anything).Return(nil)
+	mockClient.On("PutObject", mock.Anything, "", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(minio.UploadInfo{}, nil)
+
+	furniture := &namedMutator{name: "furniture"}
+	furniture.dbIndex = map[string]DBItem{"1": "1", "2": "2"}
+	furniture.gdIndex = map[string]GDItem{"1": "1"}
+	furniture.storageSet = map[string]struct{}{"1": {}}
+
+	readOnly := &namedAdapter{name: "badges"}
+	readOnly.dbIndex = map[string]DBItem{"1": "1"}
+
+	opts := ReconcileOptions{DoPurge: true}
+	report := PlanAll(context.Background(), []*Spec{{Adapter: readOnly}, {Adapter: furniture}}, nil, mockClient, "", opts, 0)
+	require.Zero(t, report.Failed)
+
+	opts.Confirmed = true
+	executed := ApplyAll(context.Background(), report, nil, mockClient, "", opts, 0)
+	assert.Equal(t, 1, executed)
+	assert.Equal(t, []string{"2"}, furniture.deletedDB)
+	assert.Contains(t, report.Adapters[0].Skipped, "does not implement Mutator")
+	assert.Equal(t, 1, report.Adapters[1].Executed)
+	assert.NotNil(t, report.Adapters[1].Plan.Verification)
+	assert.Zero(t, report.Failed)
+}
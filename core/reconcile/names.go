@@ -0,0 +1,103 @@
+package reconcile
+
+import (
+	"html"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Config holds reconcile settings shared by every adapter.
+type Config struct {
+	// Names controls how display names are normalized before they are compared.
+	Names NameNormalization `mapstructure:"names"`
+	// SyncWarnings lists the fields whose warning-severity mismatches (see
+	// SeverityWarning) syncs repair, e.g. "description". Other warnings are reported only.
+	SyncWarnings []string `mapstructure:"sync_warnings" default:""`
+	// SyncFields decides per field which source wins, e.g. {name: db, dimensions: gamedata}.
+	// It has no environment form and is only read from config.yaml.
+	SyncFields FieldPolicy `mapstructure:"sync_fields"`
+	// OnlineGate holds purge and sync runs back while many users are online.
+	OnlineGate OnlineGateConfig `mapstructure:"online_gate"`
+	// Gamedata configures gamedata served over HTTP instead of from the bucket.
+	Gamedata GamedataConfig `mapstructure:"gamedata"`
+	// StaleOrphanAge is the age from which storage orphans are stale and may be deleted
+	// by the stale-storage-orphans-only purge policy.
+	StaleOrphanAge time.Duration `mapstructure:"stale_orphan_age" default:"168h"`
+	// GracePeriod keeps entities that became inconsistent within it out of purges,
+	// reported as pending. It needs the state store; zero disables it.
+	GracePeriod time.Duration `mapstructure:"grace_period" default:"0s"`
+	// Upstream configures where missing storage objects are downloaded from.
+	Upstream UpstreamConfig `mapstructure:"upstream"`
+	// Adapters tunes each adapter, keyed by its name, e.g. {furniture: {cache_ttl: 10m}}.
+	// It has no environment form and is only read from config.yaml.
+	Adapters AdapterConfig `mapstructure:"adapters"`
+	// LogMutations logs every SQL statement and storage write of applied plans at
+	// info level, for hotels whose change management requires a full command log.
+	LogMutations bool `mapstructure:"log_mutations" default:"false"`
+	// StatusObject is the key in the gamedata bucket every reconciliation publishes a
+	// Status to, for release pipelines. Empty disables it.
+	StatusObject string `mapstructure:"status_object" default:"gamedata/.asset-manager-status.json"`
+	// CatalogPrices bounds the offer prices accepted by the catalog price check.
+	CatalogPrices PriceBounds `mapstructure:"catalog_prices"`
+}
+
+// NameNormalization lists the differences ignored when comparing display names.
+// Older databases often differ from gamedata only in whitespace, case or HTML
+// entities, which would otherwise be reported as name mismatches.
+type NameNormalization struct {
+	// Trim ignores leading and trailing whitespace.
+	Trim bool `mapstructure:"trim" default:"false"`
+	// CollapseSpaces treats runs of whitespace as a single space.
+	CollapseSpaces bool `mapstructure:"collapse_spaces" default:"false"`
+	// CaseInsensitive ignores letter case.
+	CaseInsensitive bool `mapstructure:"case_insensitive" default:"false"`
+	// StripEntities decodes HTML entities (&amp;, &#39;, ...) before comparing.
+	StripEntities bool `mapstructure:"strip_entities" default:"false"`
+}
+
+// Normalize applies the enabled normalizations to name.
+func (n NameNormalization) Normalize(name string) string {
+	if n.StripEntities {
+		name = html.UnescapeString(name)
+	}
+	if n.CollapseSpaces {
+		// Fields also drops the outer whitespace, so collapsing implies trimming
+		name = strings.Join(strings.Fields(name), " ")
+	} else if n.Trim {
+		name = strings.TrimSpace(name)
+	}
+	if n.CaseInsensitive {
+		name = strings.ToLower(name)
+	}
+	return name
+}
+
+// Equal reports whether two names are equal after normalization.
+func (n NameNormalization) Equal(a, b string) bool {
+	return a == b || n.Normalize(a) == n.Normalize(b)
+}
+
+// nameRegistry holds the normalization used by name comparisons.
+type nameRegistry struct {
+	mu sync.RWMutex
+	n  NameNormalization
+}
+
+// globalNames is the singleton name normalization for all reconcile operations.
+var globalNames = &nameRegistry{}
+
+// SetNameNormalization sets the normalization adapters apply when comparing names.
+// The zero value compares names exactly.
+func SetNameNormalization(n NameNormalization) {
+	globalNames.mu.Lock()
+	defer globalNames.mu.Unlock()
+	globalNames.n = n
+}
+
+// Names returns the normalization set by SetNameNormalization.
+func Names() NameNormalization {
+	globalNames.mu.RLock()
+	defer globalNames.mu.RUnlock()
+	return globalNames.n
+}
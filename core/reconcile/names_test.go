@@ -0,0 +1,25 @@
+package reconcile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNameNormalization_Normalize(t *testing.T) {
+	name := "  Rock &amp;\tRoll  Chair "
+
+	assert.Equal(t, name, NameNormalization{}.Normalize(name))
+	assert.Equal(t, "Rock &amp;\tRoll  Chair", NameNormalization{Trim: true}.Normalize(name))
+	assert.Equal(t, "Rock &amp; Roll Chair", NameNormalization{CollapseSpaces: true}.Normalize(name))
+	assert.Equal(t, "  rock &amp;\troll  chair ", NameNormalization{CaseInsensitive: true}.Normalize(name))
+	assert.Equal(t, "rock & roll chair", NameNormalization{CollapseSpaces: true, CaseInsensitive: true, StripEntities: true}.Normalize(name))
+}
+
+func TestNameNormalization_Equal(t *testing.T) {
+	assert.True(t, NameNormalization{}.Equal("Chair", "Chair"))
+	assert.False(t, NameNormalization{}.Equal("Chair", "chair"))
+	assert.True(t, NameNormalization{CaseInsensitive: true}.Equal("Chair", "chair"))
+	assert.True(t, NameNormalization{StripEntities: true}.Equal("Tom&#39;s Sofa", "Tom's Sofa"))
+	assert.False(t, NameNormalization{Trim: true}.Equal("Big  Sofa", "Big Sofa"))
+}
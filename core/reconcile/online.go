@@ -0,0 +1,88 @@
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	// GateRefuse fails a run at once while too many users are online.
+	GateRefuse = "refuse"
+
+	// GateDefer waits for the online count to drop, up to OnlineGateConfig.MaxWait.
+	GateDefer = "defer"
+)
+
+// OnlineGateConfig guards heavy purge and sync runs against live play by checking
+// the emulator's online user count before they mutate anything.
+type OnlineGateConfig struct {
+	// MaxUsers is the highest online count at which runs still proceed. Zero disables the gate.
+	MaxUsers int `mapstructure:"max_users" default:"0"`
+	// Mode is GateRefuse or GateDefer.
+	Mode string `mapstructure:"mode" default:"refuse"`
+	// PollInterval is the time between counts while deferring.
+	PollInterval time.Duration `mapstructure:"poll_interval" default:"1m"`
+	// MaxWait is how long a deferred run waits before giving up.
+	MaxWait time.Duration `mapstructure:"max_wait" default:"30m"`
+}
+
+// OnlineCounter returns the number of users currently online in the emulator.
+type OnlineCounter func(ctx context.Context) (int64, error)
+
+// BusyError is returned when a run is refused, or gave up waiting, because too many
+// users are online.
+type BusyError struct {
+	Online int64
+	Max    int
+	Waited time.Duration
+}
+
+// Error implements error.
+func (e *BusyError) Error() string {
+	if e.Waited > 0 {
+		return fmt.Sprintf("%d users still online after waiting %s, above the limit of %d", e.Online, e.Waited, e.Max)
+	}
+	return fmt.Sprintf("%d users online, above the limit of %d", e.Online, e.Max)
+}
+
+// Wait returns nil once the online count is at most MaxUsers. In refuse mode a busy
+// hotel fails at once; in defer mode the count is polled every PollInterval, calling
+// waiting with each busy count, until it drops or MaxWait passes. Both failures
+// return a *BusyError.
+func (g OnlineGateConfig) Wait(ctx context.Context, count OnlineCounter, waiting func(online int64)) error {
+	if g.MaxUsers <= 0 {
+		return nil
+	}
+	if g.Mode != GateRefuse && g.Mode != GateDefer {
+		return fmt.Errorf("invalid online gate mode %q (want %s or %s)", g.Mode, GateRefuse, GateDefer)
+	}
+
+	start := time.Now()
+	for {
+		online, err := count(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to count online users: %w", err)
+		}
+		if online <= int64(g.MaxUsers) {
+			return nil
+		}
+
+		waited := time.Since(start)
+		if g.Mode == GateRefuse {
+			return &BusyError{Online: online, Max: g.MaxUsers}
+		}
+		if waited+g.PollInterval > g.MaxWait {
+			return &BusyError{Online: online, Max: g.MaxUsers, Waited: waited}
+		}
+		if waiting != nil {
+			waiting(online)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(g.PollInterval):
+		}
+	}
+}
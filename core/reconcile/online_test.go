@@ -0,0 +1,69 @@
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// counts returns a counter reporting each value in turn, then the last one.
+func counts(values ...int64) (OnlineCounter, *int) {
+	calls := 0
+	return func(ctx context.Context) (int64, error) {
+		v := values[min(calls, len(values)-1)]
+		calls++
+		return v, nil
+	}, &calls
+}
+
+// TestOnlineGate_Disabled tests that a zero limit never counts.
+func TestOnlineGate_Disabled(t *testing.T) {
+	count, calls := counts(1000)
+	assert.NoError(t, OnlineGateConfig{}.Wait(context.Background(), count, nil))
+	assert.Equal(t, 0, *calls)
+}
+
+// TestOnlineGate_Refuse tests that refuse mode fails at once on a busy hotel.
+func TestOnlineGate_Refuse(t *testing.T) {
+	gate := OnlineGateConfig{MaxUsers: 10, Mode: GateRefuse}
+
+	count, _ := counts(10)
+	assert.NoError(t, gate.Wait(context.Background(), count, nil))
+
+	count, calls := counts(11)
+	err := gate.Wait(context.Background(), count, nil)
+	var busy *BusyError
+	require.ErrorAs(t, err, &busy)
+	assert.Equal(t, int64(11), busy.Online)
+	assert.Equal(t, 1, *calls)
+}
+
+// TestOnlineGate_Defer tests that defer mode polls until the count drops or time runs out.
+func TestOnlineGate_Defer(t *testing.T) {
+	gate := OnlineGateConfig{MaxUsers: 10, Mode: GateDefer, PollInterval: time.Millisecond, MaxWait: time.Second}
+
+	var waits []int64
+	count, calls := counts(30, 20, 5)
+	assert.NoError(t, gate.Wait(context.Background(), count, func(online int64) { waits = append(waits, online) }))
+	assert.Equal(t, 3, *calls)
+	assert.Equal(t, []int64{30, 20}, waits)
+
+	gate.MaxWait = 5 * time.Millisecond
+	count, _ = counts(30)
+	var busy *BusyError
+	require.ErrorAs(t, gate.Wait(context.Background(), count, nil), &busy)
+	assert.Contains(t, busy.Error(), "after waiting")
+}
+
+// TestOnlineGate_Errors tests invalid modes and counter failures.
+func TestOnlineGate_Errors(t *testing.T) {
+	count, _ := counts(0)
+	assert.ErrorContains(t, OnlineGateConfig{MaxUsers: 1, Mode: "later"}.Wait(context.Background(), count, nil), "invalid online gate mode")
+
+	failing := func(ctx context.Context) (int64, error) { return 0, errors.New("no users table") }
+	assert.ErrorContains(t, OnlineGateConfig{MaxUsers: 1, Mode: GateRefuse}.Wait(context.Background(), failing, nil), "no users table")
+}
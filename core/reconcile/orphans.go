@@ -0,0 +1,89 @@
+package reconcile
+
+import (
+	"context"
+	"maps"
+	"sync"
+	"time"
+)
+
+// OrphanAge groups storage orphans, objects with no DB row and no gamedata entry, by
+// the time since their last modification.
+type OrphanAge string
+
+const (
+	// OrphanRecent marks an orphan modified less than the stale age ago, e.g. an asset
+	// uploaded moments before its DB row is inserted.
+	OrphanRecent OrphanAge = "recent"
+	// OrphanStale marks an orphan modified at least the stale age ago.
+	OrphanStale OrphanAge = "stale"
+)
+
+// RecordModified reports the last modification of the storage object of key. Adapters
+// call it from LoadStorageSet so storage orphans can be grouped by age. Zero times are
+// ignored; it is a no-op when ctx carries no recorder (see WithDiagnostics).
+func RecordModified(ctx context.Context, key string, modified time.Time) {
+	if modified.IsZero() {
+		return
+	}
+	if recorder := recorderFrom(ctx); recorder != nil {
+		recorder.mu.Lock()
+		defer recorder.mu.Unlock()
+		if current, ok := recorder.modified[key]; !ok || modified.After(current) {
+			recorder.modified[key] = modified
+		}
+	}
+}
+
+// Modified returns the storage modification times recorded so far, by entity key.
+func (r *DiagnosticsRecorder) Modified() map[string]time.Time {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return maps.Clone(r.modified)
+}
+
+// defaultStaleOrphanAge is the age from which storage orphans are stale.
+const defaultStaleOrphanAge = 7 * 24 * time.Hour
+
+// orphanAgeRegistry holds the age from which storage orphans are stale.
+type orphanAgeRegistry struct {
+	mu  sync.RWMutex
+	age time.Duration
+}
+
+// globalOrphanAge is the singleton stale orphan age for all reconcile operations.
+var globalOrphanAge = &orphanAgeRegistry{age: defaultStaleOrphanAge}
+
+// SetStaleOrphanAge sets the age from which storage orphans are stale. Zero or less
+// restores the default of 7 days.
+func SetStaleOrphanAge(age time.Duration) {
+	if age <= 0 {
+		age = defaultStaleOrphanAge
+	}
+	globalOrphanAge.mu.Lock()
+	defer globalOrphanAge.mu.Unlock()
+	globalOrphanAge.age = age
+}
+
+// StaleOrphanAge returns the age set by SetStaleOrphanAge.
+func StaleOrphanAge() time.Duration {
+	globalOrphanAge.mu.RLock()
+	defer globalOrphanAge.mu.RUnlock()
+	return globalOrphanAge.age
+}
+
+// classifyOrphan returns the age group of a storage orphan modified at modified, as
+// seen at now, or the current time when now is zero. Entities that are no storage
+// orphan or whose age is unknown have none.
+func classifyOrphan(result ReconcileResult, modified time.Time, now time.Time) OrphanAge {
+	if !result.StoragePresent || result.DBPresent || result.GamedataPresent || modified.IsZero() {
+		return ""
+	}
+	if now.IsZero() {
+		now = time.Now()
+	}
+	if now.Sub(modified) < StaleOrphanAge() {
+		return OrphanRecent
+	}
+	return OrphanStale
+}
@@ -0,0 +1,53 @@
+package reconcile
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"asset-manager/core/storage"
+	"asset-manager/core/storage/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReconcileWithPlan_OrphanAge tests that storage orphans are grouped by age and
+// that the stale policy only deletes stale ones.
+func TestReconcileWithPlan_OrphanAge(t *testing.T) {
+	defer SetStaleOrphanAge(0)
+	SetStaleOrphanAge(24 * time.Hour)
+
+	mockClient := new(mocks.Client)
+	mockClient.On("BucketExists", mock.Anything, "").Return(true, nil)
+
+	adapter := &mockMutator{}
+	adapter.dbIndex = map[string]DBItem{"1": "1"}
+	adapter.gdIndex = map[string]GDItem{"1": "1"}
+	adapter.storageLoadFunc = func(ctx context.Context, client storage.Client, bucket, prefix, extension string) (map[string]struct{}, error) {
+		RecordModified(ctx, "1", time.Now().Add(-48*time.Hour))
+		// The newest object of a key decides its age
+		RecordModified(ctx, "2", time.Now().Add(-time.Hour))
+		RecordModified(ctx, "2", time.Now().Add(-72*time.Hour))
+		RecordModified(ctx, "3", time.Now().Add(-48*time.Hour))
+		return map[string]struct{}{"1": {}, "2": {}, "3": {}, "4": {}}, nil
+	}
+	spec := &Spec{Adapter: adapter}
+
+	plan, err := ReconcileWithPlan(context.Background(), spec, nil, mockClient, "", ReconcileOptions{DoPurge: true, PurgePolicy: PurgeStaleStorageOrphans})
+	require.NoError(t, err)
+	assert.Equal(t, 1, plan.Summary.RecentOrphans)
+	assert.Equal(t, 1, plan.Summary.StaleOrphans)
+
+	ages := map[string]OrphanAge{}
+	for _, result := range plan.Results {
+		ages[result.ID] = result.OrphanAge
+	}
+	assert.Equal(t, map[string]OrphanAge{"1": "", "2": OrphanRecent, "3": OrphanStale, "4": ""}, ages, "complete items and orphans of unknown age have no group")
+	assert.Equal(t, []Action{{Type: ActionDeleteStorage, Key: "3", Reason: plan.Actions[0].Reason}}, plan.Actions)
+
+	policy, err := ParsePurgePolicy("stale-storage-orphans-only")
+	require.NoError(t, err)
+	assert.Equal(t, PurgeStaleStorageOrphans, policy)
+}
@@ -2,10 +2,16 @@ package reconcile
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
+	"asset-manager/core/logger"
 	"asset-manager/core/storage"
 
+	"github.com/google/uuid"
+	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
 
@@ -20,6 +26,13 @@ func ReconcileWithPlan(
 	bucket string,
 	opts ReconcileOptions,
 ) (*ReconcilePlan, error) {
+	ctx, runID := StartRun(ctx)
+	sampler := startHeapSampler()
+	defer sampler.stop()
+
+	progress := progressFrom(ctx)
+	progress.report(ProgressEvent{Stage: StageIndexes})
+
 	// Build cache (which loads all indices concurrently)
 	cache, err := GetOrBuildCache(ctx, spec, db, client, bucket)
 	if err != nil {
@@ -27,19 +40,50 @@ func ReconcileWithPlan(
 	}
 
 	// Build results using existing reconcile logic
-	results, err := reconcileFromCache(cache, spec.Adapter)
+	results, err := reconcileFromCache(cache, spec.Adapter, progress)
 	if err != nil {
 		return nil, err
 	}
 
+	// Track health across runs for flapping detection
+	if err := RecordRun(ctx, spec.Adapter.Name(), results); err != nil {
+		return nil, err
+	}
+
+	// Ignored entities are reported separately and never planned
+	results, ignored, err := splitIgnored(ctx, spec.Adapter.Name(), results)
+	if err != nil {
+		return nil, err
+	}
+	if err := annotateTriage(ctx, spec.Adapter.Name(), results); err != nil {
+		return nil, err
+	}
+
 	// Build summary and actions
 	summary, actions := buildPlanFromResults(results, cache, spec.Adapter, opts)
+	summary.Memory = sampler.Stats(cache)
+	summary.Ignored = len(ignored)
+	summary.KeyConflicts = len(cache.Diagnostics.KeyConflicts)
+	summary.DuplicateNames = len(cache.Diagnostics.DuplicateNames)
 
-	return &ReconcilePlan{
+	// Keep the run so later runs can be compared with it
+	if err := saveRun(ctx, spec.Adapter.Name(), results, summary); err != nil {
+		return nil, err
+	}
+	publishStatus(ctx, spec, client, bucket, results, summary)
+	progress.report(ProgressEvent{Stage: StageSummary, Summary: &summary})
+
+	plan := &ReconcilePlan{
+		RunID:   runID,
 		Results: results,
 		Actions: actions,
 		Summary: summary,
-	}, nil
+		Ignored: ignored,
+	}
+	if diagnostics := cache.Diagnostics; !diagnostics.Empty() {
+		plan.Diagnostics = &diagnostics
+	}
+	return plan, nil
 }
 
 // ApplyPlan executes the actions in a reconcile plan.
@@ -65,12 +109,64 @@ func ApplyPlan(
 		return 0, fmt.Errorf("adapter %s does not implement Mutator interface", spec.Adapter.Name())
 	}
 
+	// A plan applied outside of a run belongs to the run that built it
+	if RunIDFrom(ctx) == "" {
+		ctx = WithRunID(ctx, plan.RunID)
+	}
+	// Record the object versions the plan replaces, so the plan can be undone
+	if plan.ID == "" {
+		plan.ID = NewPlanID()
+	}
+	// Tag logged statements and storage writes with the plan they belong to
+	if l := logger.MutationLog(ctx); l != nil {
+		ctx = logger.WithMutationLog(ctx, l.With(zap.String("plan_id", plan.ID)))
+	}
+	// Write the plan's storage changes to the change log once, after everything else
+	ctx, changes := storage.WithChangeBuffer(ctx)
+	defer changes.Flush()
+	// Keep where the plan stopped, so a failed apply can be resumed
+	outcome := newApplyOutcome()
+	defer func() {
+		if statusErr := savePlanStatus(ctx, outcome.status(ctx, spec, plan, opts, err)); statusErr != nil && err == nil {
+			err = statusErr
+		}
+	}()
+	// Save the state the plan replaces, so the plan can be rolled back
+	if err := backupPlan(ctx, spec, client, bucket, plan); err != nil {
+		return 0, err
+	}
+	ctx, recorder := storage.WithVersionRecorder(ctx)
+	defer func() {
+		plan.Versions = recorder.Versions()
+		if auditErr := recordPlanVersions(ctx, spec, plan, executed, err); auditErr != nil && err == nil {
+			err = auditErr
+		}
+	}()
+
+	// Keep cached indices in step with the stores: patch the mutated keys on
+	// success, drop the cache if we stopped partway through
+	defer func() {
+		switch {
+		case err != nil && executed > 0:
+			InvalidateCache(spec)
+		case err == nil:
+			PatchCache(spec, appliedActions(plan))
+		}
+	}()
+
+	// Report executed actions as they run; batch mutators report each one
+	ctx, applied := withApplyCounter(ctx, len(plan.Actions))
+
 	// Group actions by type for efficient execution
 	var (
 		deleteDBKeys       []string
 		deleteGamedataKeys []string
 		deleteStorageKeys  []string
 		syncActions        []Action
+		gamedataSyncs      []Action
+		moveActions        []Action
+		downloadActions    []Action
+		gamedataInserts    []Action
 	)
 
 	for _, action := range plan.Actions {
@@ -83,6 +179,14 @@ func ApplyPlan(
 			deleteStorageKeys = append(deleteStorageKeys, action.Key)
 		case ActionSyncDB:
 			syncActions = append(syncActions, action)
+		case ActionSyncGamedata:
+			gamedataSyncs = append(gamedataSyncs, action)
+		case ActionMoveStorage:
+			moveActions = append(moveActions, action)
+		case ActionDownloadStorage:
+			downloadActions = append(downloadActions, action)
+		case ActionInsertGamedata:
+			gamedataInserts = append(gamedataInserts, action)
 		}
 	}
 
@@ -96,16 +200,22 @@ func ApplyPlan(
 		}
 		if batchDeleter, ok := mutator.(DBBatchDeleter); ok {
 			if err := batchDeleter.DeleteDBBatch(ctx, deleteDBKeys); err != nil {
+				outcome.fail(ActionDeleteDB, err.Error(), deleteDBKeys...)
 				return executed, fmt.Errorf("failed to batch delete DB keys: %w", err)
 			}
 			executed += len(deleteDBKeys)
+			outcome.done(ActionDeleteDB, deleteDBKeys...)
+			applied.reach(executed)
 		} else {
 			// Fallback to one-at-a-time
 			for _, key := range deleteDBKeys {
 				if err := mutator.DeleteDB(ctx, key); err != nil {
+					outcome.fail(ActionDeleteDB, err.Error(), key)
 					return executed, fmt.Errorf("failed to delete DB key %s: %w", key, err)
 				}
 				executed++
+				outcome.done(ActionDeleteDB, key)
+				applied.reach(executed)
 			}
 		}
 	}
@@ -118,16 +228,22 @@ func ApplyPlan(
 		}
 		if batchDeleter, ok := mutator.(GDBatchDeleter); ok {
 			if err := batchDeleter.DeleteGamedataBatch(ctx, deleteGamedataKeys); err != nil {
+				outcome.fail(ActionDeleteGamedata, err.Error(), deleteGamedataKeys...)
 				return executed, fmt.Errorf("failed to batch delete gamedata keys: %w", err)
 			}
 			executed += len(deleteGamedataKeys)
+			outcome.done(ActionDeleteGamedata, deleteGamedataKeys...)
+			applied.reach(executed)
 		} else {
 			// Fallback to one-at-a-time
 			for _, key := range deleteGamedataKeys {
 				if err := mutator.DeleteGamedata(ctx, key); err != nil {
+					outcome.fail(ActionDeleteGamedata, err.Error(), key)
 					return executed, fmt.Errorf("failed to delete gamedata key %s: %w", key, err)
 				}
 				executed++
+				outcome.done(ActionDeleteGamedata, key)
+				applied.reach(executed)
 			}
 		}
 	}
@@ -140,20 +256,54 @@ func ApplyPlan(
 		}
 		if batchDeleter, ok := mutator.(StorageBatchDeleter); ok {
 			if err := batchDeleter.DeleteStorageBatch(ctx, deleteStorageKeys); err != nil {
+				// Keys without a reported failure were deleted
+				var batchErr *BatchDeleteError
+				if errors.As(err, &batchErr) {
+					plan.Failures = append(plan.Failures, batchErr.Failures...)
+					executed += len(deleteStorageKeys) - len(batchErr.Failures)
+					outcome.done(ActionDeleteStorage, deleteStorageKeys...)
+					for _, f := range batchErr.Failures {
+						outcome.fail(ActionDeleteStorage, f.Message, f.Key)
+					}
+				} else {
+					outcome.fail(ActionDeleteStorage, err.Error(), deleteStorageKeys...)
+				}
 				return executed, fmt.Errorf("failed to batch delete storage keys: %w", err)
 			}
 			executed += len(deleteStorageKeys)
+			outcome.done(ActionDeleteStorage, deleteStorageKeys...)
+			applied.reach(executed)
 		} else {
 			// Fallback to one-at-a-time
 			for _, key := range deleteStorageKeys {
 				if err := mutator.DeleteStorage(ctx, key); err != nil {
+					outcome.fail(ActionDeleteStorage, err.Error(), key)
 					return executed, fmt.Errorf("failed to delete storage key %s: %w", key, err)
 				}
 				executed++
+				outcome.done(ActionDeleteStorage, key)
+				applied.reach(executed)
 			}
 		}
 	}
 
+	// Storage moves
+	if len(moveActions) > 0 {
+		mover, ok := mutator.(StorageMover)
+		if !ok {
+			return executed, fmt.Errorf("adapter %s does not implement StorageMover interface", spec.Adapter.Name())
+		}
+		for _, action := range moveActions {
+			if err := mover.MoveStorage(ctx, action.Key, action.From); err != nil {
+				outcome.fail(ActionMoveStorage, err.Error(), action.Key)
+				return executed, fmt.Errorf("failed to move storage key %s: %w", action.Key, err)
+			}
+			executed++
+			outcome.done(ActionMoveStorage, action.Key)
+			applied.reach(executed)
+		}
+	}
+
 	// Execute syncs
 	if len(syncActions) > 0 {
 		// Try batch sync first
@@ -162,24 +312,186 @@ func ApplyPlan(
 		}
 		if batchSyncer, ok := mutator.(SyncBatcher); ok {
 			if err := batchSyncer.SyncDBBatch(ctx, syncActions); err != nil {
+				outcome.fail(ActionSyncDB, err.Error(), actionKeys(syncActions)...)
 				return executed, fmt.Errorf("failed to batch sync DB: %w", err)
 			}
 			executed += len(syncActions)
+			outcome.done(ActionSyncDB, actionKeys(syncActions)...)
+			applied.reach(executed)
 		} else {
 			// Fallback to one-at-a-time
 			for _, action := range syncActions {
 				if err := mutator.SyncDBFromGamedata(ctx, action.Key, action.GDItem); err != nil {
+					outcome.fail(ActionSyncDB, err.Error(), action.Key)
 					return executed, fmt.Errorf("failed to sync key %s: %w", action.Key, err)
 				}
 				executed++
+				outcome.done(ActionSyncDB, action.Key)
+				applied.reach(executed)
 			}
 		}
 	}
 
+	// Reverse syncs: rewrite gamedata entries from the DB, in one write when batched
+	if len(gamedataSyncs) > 0 {
+		type GamedataSyncBatcher interface {
+			SyncGamedataBatch(ctx context.Context, actions []Action) error
+		}
+		if batchSyncer, ok := mutator.(GamedataSyncBatcher); ok {
+			if err := batchSyncer.SyncGamedataBatch(ctx, gamedataSyncs); err != nil {
+				outcome.fail(ActionSyncGamedata, err.Error(), actionKeys(gamedataSyncs)...)
+				return executed, fmt.Errorf("failed to batch sync gamedata: %w", err)
+			}
+			executed += len(gamedataSyncs)
+			outcome.done(ActionSyncGamedata, actionKeys(gamedataSyncs)...)
+			applied.reach(executed)
+		} else {
+			syncer, ok := mutator.(GamedataSyncer)
+			if !ok {
+				return executed, fmt.Errorf("adapter %s does not implement GamedataSyncer interface", spec.Adapter.Name())
+			}
+			for _, action := range gamedataSyncs {
+				if err := syncer.SyncGamedataFromDB(ctx, action.Key, action.DBItem, action.GDItem); err != nil {
+					outcome.fail(ActionSyncGamedata, err.Error(), action.Key)
+					return executed, fmt.Errorf("failed to sync gamedata key %s: %w", action.Key, err)
+				}
+				executed++
+				outcome.done(ActionSyncGamedata, action.Key)
+				applied.reach(executed)
+			}
+		}
+	}
+
+	// Gamedata inserts: add entries built from DB rows, in one write when batched
+	if len(gamedataInserts) > 0 {
+		type GamedataInsertBatcher interface {
+			InsertGamedataBatch(ctx context.Context, actions []Action) error
+		}
+		if batchInserter, ok := mutator.(GamedataInsertBatcher); ok {
+			if err := batchInserter.InsertGamedataBatch(ctx, gamedataInserts); err != nil {
+				outcome.fail(ActionInsertGamedata, err.Error(), actionKeys(gamedataInserts)...)
+				return executed, fmt.Errorf("failed to batch insert gamedata: %w", err)
+			}
+			executed += len(gamedataInserts)
+			outcome.done(ActionInsertGamedata, actionKeys(gamedataInserts)...)
+			applied.reach(executed)
+		} else {
+			inserter, ok := mutator.(GamedataInserter)
+			if !ok {
+				return executed, fmt.Errorf("adapter %s does not implement GamedataInserter interface", spec.Adapter.Name())
+			}
+			for _, action := range gamedataInserts {
+				if err := inserter.InsertGamedata(ctx, action.Key, action.DBItem); err != nil {
+					outcome.fail(ActionInsertGamedata, err.Error(), action.Key)
+					return executed, fmt.Errorf("failed to insert gamedata key %s: %w", action.Key, err)
+				}
+				executed++
+				outcome.done(ActionInsertGamedata, action.Key)
+				applied.reach(executed)
+			}
+		}
+	}
+
+	// Storage downloads, after everything else so an unreachable upstream cannot
+	// hold back the other repairs
+	if len(downloadActions) > 0 {
+		downloader, ok := mutator.(StorageDownloader)
+		if !ok {
+			return executed, fmt.Errorf("adapter %s does not implement StorageDownloader interface", spec.Adapter.Name())
+		}
+		upstream := RegisteredUpstream()
+		if upstream == nil {
+			return executed, fmt.Errorf("no upstream configured to download storage keys from")
+		}
+		for _, action := range downloadActions {
+			if err := downloader.DownloadStorage(ctx, action.Key, upstream); err != nil {
+				if !errors.Is(err, ErrUpstreamUnavailable) {
+					outcome.fail(ActionDownloadStorage, err.Error(), action.Key)
+					return executed, fmt.Errorf("failed to download storage key %s: %w", action.Key, err)
+				}
+				plan.SkippedDownloads = append(plan.SkippedDownloads, SkippedDownload{Key: action.Key, Reason: err.Error()})
+				continue
+			}
+			executed++
+			outcome.done(ActionDownloadStorage, action.Key)
+			applied.reach(executed)
+		}
+	}
+
 	return executed, nil
 }
 
+// appliedActions returns the actions of a plan ApplyPlan carried out: all of them
+// but the skipped downloads.
+func appliedActions(plan *ReconcilePlan) []Action {
+	if len(plan.SkippedDownloads) == 0 {
+		return plan.Actions
+	}
+	skipped := make(map[string]bool, len(plan.SkippedDownloads))
+	for _, s := range plan.SkippedDownloads {
+		skipped[s.Key] = true
+	}
+	actions := make([]Action, 0, len(plan.Actions))
+	for _, action := range plan.Actions {
+		if action.Type != ActionDownloadStorage || !skipped[action.Key] {
+			actions = append(actions, action)
+		}
+	}
+	return actions
+}
+
+// NewPlanID returns a unique plan ID.
+func NewPlanID() string {
+	return uuid.New().String()
+}
+
+// recordPlanVersions writes an ActionApplyPlan audit entry holding the versions the
+// plan replaced. Plans that replaced no versioned object are not recorded.
+func recordPlanVersions(ctx context.Context, spec *Spec, plan *ReconcilePlan, executed int, applyErr error) error {
+	if len(plan.Versions) == 0 {
+		return nil
+	}
+
+	entry := AuditEntry{
+		Time:     time.Now(),
+		Adapter:  spec.Adapter.Name(),
+		Trigger:  ApplyTrigger,
+		Action:   ActionApplyPlan,
+		Key:      plan.ID,
+		Reason:   fmt.Sprintf("%d of %d actions applied", executed, len(plan.Actions)),
+		Outcome:  AuditApplied,
+		PlanID:   plan.ID,
+		RunID:    RunIDFrom(ctx),
+		Versions: plan.Versions,
+	}
+	if applyErr != nil {
+		entry.Outcome = AuditFailed
+		entry.Error = applyErr.Error()
+	}
+	return RecordAudit(ctx, []AuditEntry{entry})
+}
+
+// BatchDeleteError reports the keys a batch deletion could not remove.
+// Every other key in the batch was deleted.
+type BatchDeleteError struct {
+	Failures []DeleteFailure
+}
+
+// Error summarizes every failed key.
+func (e *BatchDeleteError) Error() string {
+	parts := make([]string, 0, len(e.Failures))
+	for _, f := range e.Failures {
+		part := f.Key
+		if f.Code != "" {
+			part += " (" + f.Code + ")"
+		}
+		parts = append(parts, part+": "+f.Message)
+	}
+	return fmt.Sprintf("batch delete had %d errors: %s", len(e.Failures), strings.Join(parts, "; "))
+}
+
 // ReconcileAndApply is a convenience wrapper that plans and optionally applies actions.
+// When actions were executed, the plan is verified afterwards (see VerifyPlan).
 // It returns the plan, number of actions executed, and any error.
 func ReconcileAndApply(
 	ctx context.Context,
@@ -195,29 +507,29 @@ func ReconcileAndApply(
 	}
 
 	executed, err := ApplyPlan(ctx, spec, db, client, bucket, plan, opts)
-	return plan, executed, err
-}
-
-// reconcileFromCache builds results from a cache (extracted from ReconcileAll logic).
-func reconcileFromCache(cache *ReconcileCache, adapter Adapter) ([]ReconcileResult, error) {
-	// Build union of all keys
-	unionKeys := buildUnion(cache.DBIndex, cache.GDIndex, cache.StorageSet, adapter)
+	if err != nil || executed == 0 {
+		return plan, executed, err
+	}
 
-	// Build results for each key
-	results := make([]ReconcileResult, 0, len(unionKeys))
-	for key := range unionKeys {
-		result := buildResult(key, cache.DBIndex, cache.GDIndex, cache.StorageSet, adapter)
-		results = append(results, result)
+	// Second pass: confirm the applied actions actually took effect
+	if _, err := VerifyPlan(ctx, spec, db, client, bucket, plan); err != nil {
+		return plan, executed, err
 	}
+	return plan, executed, nil
+}
 
-	return results, nil
+// reconcileFromCache builds results from a cache (extracted from ReconcileAll logic).
+// Progress is reported to progress, if any.
+func reconcileFromCache(cache *ReconcileCache, adapter Adapter, progress ProgressFunc) ([]ReconcileResult, error) {
+	// Build results for each key in the union of all keys, sorted like Reconcile
+	return buildResults(cache, adapter, progress), nil
 }
 
-// buildPlanFromResults generates a summary and action plan from reconciliation results.
-func buildPlanFromResults(results []ReconcileResult, cache *ReconcileCache, adapter Adapter, opts ReconcileOptions) (PlanSummary, []Action) {
+// Summarize computes aggregate presence and mismatch counts for a set of results.
+// It is the single source of truth for counts so every surface (CLI, HTTP, plans)
+// reports identical numbers for the same underlying state.
+func Summarize(results []ReconcileResult) PlanSummary {
 	var summary PlanSummary
-	var actions []Action
-
 	summary.TotalItems = len(results)
 
 	for _, result := range results {
@@ -226,52 +538,102 @@ func buildPlanFromResults(results []ReconcileResult, cache *ReconcileCache, adap
 		// - gamedata_missing: Items in (DB OR storage) that don't have gamedata
 		// - db_missing: Items in (gamedata OR storage) that don't have DB
 
+		// Sources an entity is expected to be absent from do not count as missing
 		// storage_missing: in (DB OR gamedata) but NOT in storage
-		if (result.DBPresent || result.GamedataPresent) && !result.StoragePresent {
+		if (result.DBPresent || result.GamedataPresent) && result.lacks(SourceStorage) {
 			summary.MissingStorage++
 		}
 
 		// gamedata_missing: in (DB OR storage) but NOT in gamedata
-		if (result.DBPresent || result.StoragePresent) && !result.GamedataPresent {
+		if (result.DBPresent || result.StoragePresent) && result.lacks(SourceGamedata) {
 			summary.MissingGamedata++
 		}
 
 		// db_missing: in (gamedata OR storage) but NOT in DB
-		if (result.GamedataPresent || result.StoragePresent) && !result.DBPresent {
+		if (result.GamedataPresent || result.StoragePresent) && result.lacks(SourceDB) {
 			summary.MissingDB++
 		}
+		if len(result.ExpectedAbsent) > 0 {
+			summary.ExpectedAbsent++
+		}
 
 		// Count mismatches
 		if len(result.Mismatch) > 0 {
 			summary.Mismatches++
 		}
 
-		// Plan purge actions: delete if missing in ANY store
-		if opts.DoPurge {
-			missingInAny := !result.GamedataPresent || !result.StoragePresent || !result.DBPresent
-			if missingInAny {
-				// Delete from all stores
-				if result.DBPresent {
-					actions = append(actions, Action{
-						Type:   ActionDeleteDB,
-						Key:    result.ID,
-						Reason: getMissingReason(result),
-					})
-					summary.PurgeActions++
-				}
-				if result.GamedataPresent {
-					actions = append(actions, Action{
-						Type:   ActionDeleteGamedata,
-						Key:    result.ID,
-						Reason: getMissingReason(result),
-					})
-					summary.PurgeActions++
-				}
-				if result.StoragePresent {
+		if result.Misplaced != "" {
+			summary.Misplaced++
+		}
+
+		switch result.OrphanAge {
+		case OrphanRecent:
+			summary.RecentOrphans++
+		case OrphanStale:
+			summary.StaleOrphans++
+		}
+
+		// Additional sources: in the union (so present elsewhere) but NOT in the source
+		for source, present := range result.Sources {
+			if present || isDefaultSource(source) {
+				continue
+			}
+			if summary.MissingSources == nil {
+				summary.MissingSources = make(map[string]int)
+			}
+			summary.MissingSources[source]++
+		}
+
+		if result.Flapping {
+			summary.Flapping++
+		}
+		if result.Pending {
+			summary.Pending++
+		}
+	}
+
+	return summary
+}
+
+// buildPlanFromResults generates a summary and action plan from reconciliation results.
+func buildPlanFromResults(results []ReconcileResult, cache *ReconcileCache, adapter Adapter, opts ReconcileOptions) (PlanSummary, []Action) {
+	summary := Summarize(results)
+	var actions []Action
+	_, mover := adapter.(StorageMover)
+	_, downloader := adapter.(StorageDownloader)
+	_, gamedataSyncer := adapter.(GamedataSyncer)
+	_, inserter := adapter.(GamedataInserter)
+
+	for _, result := range results {
+		// Plan downloads: restore missing storage objects from the upstream
+		repairing := false
+		if opts.DoFixStorage && downloader {
+			if action, ok := planDownload(result); ok {
+				actions = append(actions, action)
+				summary.DownloadActions++
+				repairing = true
+			}
+		}
+
+		// Plan gamedata inserts: add entries for items the database and storage agree on
+		if opts.DoInsertGamedata && inserter {
+			if action, ok := planGamedataInsert(result, cache); ok {
+				actions = append(actions, action)
+				summary.InsertActions++
+				repairing = true
+			}
+		}
+
+		// Plan purge actions according to the configured policy; items being
+		// downloaded or inserted are repaired instead
+		if opts.DoPurge && !repairing {
+			if purgeTypes := purgeActionTypes(result, opts.PurgePolicy); len(purgeTypes) > 0 {
+				reason := getMissingReason(result)
+				for _, actionType := range purgeTypes {
 					actions = append(actions, Action{
-						Type:   ActionDeleteStorage,
+						Type:   actionType,
 						Key:    result.ID,
-						Reason: getMissingReason(result),
+						Reason: reason,
 					})
 					summary.PurgeActions++
 				}
@@ -280,24 +642,166 @@ func buildPlanFromResults(results []ReconcileResult, cache *ReconcileCache, adap
 			}
 		}
 
-		// Plan sync actions: update DB from gamedata if mismatches exist
-		if opts.DoSync && len(result.Mismatch) > 0 {
-			if result.DBPresent && result.GamedataPresent {
-				gdItem := cache.GDIndex[result.ID]
+		// Plan sync actions: update the DB from gamedata, and gamedata from the DB, for
+		// the mismatched fields each source wins (see FieldPolicy)
+		if opts.DoSync && plansSync(result) {
+			fromGamedata, fromDB := splitSyncMismatches(syncMismatches(result), opts.SyncDirection)
+			if len(fromGamedata) > 0 {
 				actions = append(actions, Action{
 					Type:   ActionSyncDB,
 					Key:    result.ID,
-					Reason: fmt.Sprintf("mismatch: %v", result.Mismatch),
-					GDItem: gdItem,
+					Reason: fmt.Sprintf("mismatch: %v", fromGamedata),
+					Fields: MismatchFields(fromGamedata),
+					GDItem: cache.GDIndex[result.ID],
+				})
+				summary.SyncActions++
+			}
+			if len(fromDB) > 0 && gamedataSyncer {
+				actions = append(actions, Action{
+					Type:   ActionSyncGamedata,
+					Key:    result.ID,
+					Reason: fmt.Sprintf("mismatch: %v", fromDB),
+					Fields: MismatchFields(fromDB),
+					GDItem: cache.GDIndex[result.ID],
+					DBItem: cache.DBIndex[result.ID],
 				})
 				summary.SyncActions++
 			}
 		}
+
+		// Plan moves: relocate misplaced storage objects instead of leaving them
+		if opts.DoSync && mover {
+			if action, ok := planMove(result); ok {
+				actions = append(actions, action)
+				summary.MoveActions++
+			}
+		}
 	}
 
 	return summary, actions
 }
 
+// plansSync reports whether a sync plans an update of the result's DB row: it has
+// mismatches a sync repairs (see syncMismatches) and both a DB row and a gamedata
+// entry to sync from.
+func plansSync(result ReconcileResult) bool {
+	return result.DBPresent && result.GamedataPresent && len(syncMismatches(result)) > 0
+}
+
+// purgeActionTypes returns the delete actions a purge policy plans for a result.
+// Strict deletes anything missing in any store from every store holding it;
+// the narrower policies only delete entities that exist in a single store.
+func purgeActionTypes(result ReconcileResult, policy PurgePolicy) []ActionType {
+	// Entities that just broke may be uploads still in progress
+	if result.Pending {
+		return nil
+	}
+	onlyDB := result.DBPresent && !result.GamedataPresent && !result.StoragePresent
+	onlyGD := result.GamedataPresent && !result.DBPresent && !result.StoragePresent
+	onlyStorage := result.StoragePresent && !result.DBPresent && !result.GamedataPresent
+
+	switch policy {
+	case PurgeStorageOrphans:
+		if onlyStorage {
+			return []ActionType{ActionDeleteStorage}
+		}
+		return nil
+	case PurgeStaleStorageOrphans:
+		if onlyStorage && result.OrphanAge == OrphanStale {
+			return []ActionType{ActionDeleteStorage}
+		}
+		return nil
+	case PurgeDBOrphans:
+		if onlyDB {
+			return []ActionType{ActionDeleteDB}
+		}
+		return nil
+	case PurgeGamedataGhosts:
+		if onlyGD {
+			return []ActionType{ActionDeleteGamedata}
+		}
+		return nil
+	}
+
+	// Strict: delete if missing in ANY store, from all stores; being absent where the
+	// emulator expects it does not count
+	if !result.lacks(SourceGamedata) && !result.lacks(SourceStorage) && !result.lacks(SourceDB) {
+		return nil
+	}
+	var types []ActionType
+	if result.DBPresent {
+		types = append(types, ActionDeleteDB)
+	}
+	if result.GamedataPresent {
+		types = append(types, ActionDeleteGamedata)
+	}
+	if result.StoragePresent {
+		types = append(types, ActionDeleteStorage)
+	}
+	return types
+}
+
+// SuggestActions derives the repair actions for a single reconciled entity.
+// Unlike buildPlanFromResults it favours repair over purge: items known to gamedata
+// get insert/fetch/sync suggestions, while items unknown to gamedata get deletions
+// from the stores that still hold them.
+func SuggestActions(result ReconcileResult) []Action {
+	actions := make([]Action, 0)
+
+	if !result.GamedataPresent {
+		if !result.DBPresent && !result.StoragePresent {
+			return actions
+		}
+		reason := getMissingReason(result)
+		if result.DBPresent {
+			actions = append(actions, Action{Type: ActionDeleteDB, Key: result.ID, Reason: reason})
+		}
+		if result.StoragePresent {
+			actions = append(actions, Action{Type: ActionDeleteStorage, Key: result.ID, Reason: reason})
+		}
+		return actions
+	}
+
+	if !result.DBPresent {
+		actions = append(actions, Action{Type: ActionInsertDB, Key: result.ID, Reason: "missing in database"})
+	}
+	if result.lacks(SourceStorage) {
+		actions = append(actions, Action{Type: ActionFetchStorage, Key: result.ID, Reason: "missing in storage"})
+	}
+	if mismatches := syncMismatches(result); result.DBPresent && len(mismatches) > 0 {
+		actions = append(actions, Action{
+			Type:   ActionSyncDB,
+			Key:    result.ID,
+			Reason: fmt.Sprintf("mismatch: %v", mismatches),
+			Fields: MismatchFields(mismatches),
+		})
+	}
+	if action, ok := planMove(result); ok {
+		actions = append(actions, action)
+	}
+
+	return actions
+}
+
+// MismatchFields extracts the field labels from mismatch descriptions.
+// Example: ["width: gd=2 db=1", "name: gd='a' db='b'"] -> ["width", "name"].
+func MismatchFields(mismatches []string) []string {
+	fields := make([]string, 0, len(mismatches))
+	seen := make(map[string]struct{}, len(mismatches))
+	for _, m := range mismatches {
+		field := mismatchField(m)
+		if field == "" {
+			continue
+		}
+		if _, ok := seen[field]; ok {
+			continue
+		}
+		seen[field] = struct{}{}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
 // getMissingReason builds a reason string for why an entity should be purged.
 func getMissingReason(result ReconcileResult) string {
 	var missing []string
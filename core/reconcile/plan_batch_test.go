@@ -146,12 +146,42 @@ func TestApplyPlan_LargeScaleBatch(t *testing.T) {
 	assert.Len(t, mutator.deletedStorage, 0, "Should NOT use individual calls")
 }
 
+// TestApplyPlan_RecordsDeleteFailures tests that per-key batch failures land on the plan
+// and that the keys deleted before the failure are counted.
+func TestApplyPlan_RecordsDeleteFailures(t *testing.T) {
+	failure := DeleteFailure{
+		Key:       "21",
+		Action:    ActionDeleteStorage,
+		Object:    "bundled/furniture/chair.nitro",
+		Code:      "SlowDown",
+		Message:   "please reduce your request rate",
+		Retriable: true,
+		Attempts:  3,
+	}
+	mutator := &mockBatchMutator{storageErr: &BatchDeleteError{Failures: []DeleteFailure{failure}}}
+	spec := &Spec{Adapter: mutator}
+
+	plan := &ReconcilePlan{
+		Actions: []Action{
+			{Type: ActionDeleteStorage, Key: "20"},
+			{Type: ActionDeleteStorage, Key: "21"},
+			{Type: ActionDeleteStorage, Key: "22"},
+		},
+	}
+
+	executed, err := ApplyPlan(context.Background(), spec, nil, nil, "", plan, ReconcileOptions{Confirmed: true})
+	assert.ErrorContains(t, err, "21 (SlowDown): please reduce your request rate")
+	assert.Equal(t, 2, executed)
+	assert.Equal(t, []DeleteFailure{failure}, plan.Failures)
+}
+
 // mockBatchMutator implements batch deletion methods for testing.
 type mockBatchMutator struct {
 	mockMutator
 	batchDBCalls       [][]string
 	batchGamedataCalls [][]string
 	batchStorageCalls  [][]string
+	storageErr         error
 }
 
 func (m *mockBatchMutator) DeleteDBBatch(ctx context.Context, keys []string) error {
@@ -166,5 +196,5 @@ func (m *mockBatchMutator) DeleteGamedataBatch(ctx context.Context, keys []strin
 
 func (m *mockBatchMutator) DeleteStorageBatch(ctx context.Context, keys []string) error {
 	m.batchStorageCalls = append(m.batchStorageCalls, keys)
-	return nil
+	return m.storageErr
 }
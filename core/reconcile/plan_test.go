@@ -4,10 +4,14 @@ import (
 	"context"
 	"testing"
 
+	"asset-manager/core/logger"
 	"asset-manager/core/storage/mocks"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 // TestReconcileWithPlan_PurgeActions tests that purge actions are planned correctly.
@@ -228,6 +232,31 @@ func TestApplyPlan_ConfirmationGating(t *testing.T) {
 	assert.Equal(t, "2", mutator.deletedGamedata[0])
 }
 
+// loggingMutator logs a line to the mutation log of each DeleteDB context.
+type loggingMutator struct {
+	mockMutator
+}
+
+func (m *loggingMutator) DeleteDB(ctx context.Context, key string) error {
+	if l := logger.MutationLog(ctx); l != nil {
+		l.Info("Executed SQL", zap.String("key", key))
+	}
+	return nil
+}
+
+// TestApplyPlan_MutationLog tests that mutations logged during ApplyPlan carry the
+// plan ID.
+func TestApplyPlan_MutationLog(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	ctx := logger.WithMutationLog(context.Background(), zap.New(core).With(zap.String("ray_id", "ray-1")))
+	plan := &ReconcilePlan{ID: "plan-1", Actions: []Action{{Type: ActionDeleteDB, Key: "1"}}}
+
+	_, err := ApplyPlan(ctx, &Spec{Adapter: &loggingMutator{}}, nil, nil, "", plan, ReconcileOptions{Confirmed: true})
+	require.NoError(t, err)
+	require.Len(t, logs.All(), 1)
+	assert.Equal(t, map[string]any{"ray_id": "ray-1", "plan_id": "plan-1", "key": "1"}, logs.All()[0].ContextMap())
+}
+
 // mockMutator implements both Adapter and Mutator for testing.
 type mockMutator struct {
 	mockAdapter
@@ -256,3 +285,82 @@ func (m *mockMutator) SyncDBFromGamedata(ctx context.Context, key string, gdItem
 	m.synced = append(m.synced, key)
 	return nil
 }
+
+// TestSuggestActions tests single-item repair suggestions for each presence combination.
+func TestSuggestActions(t *testing.T) {
+	tests := []struct {
+		name   string
+		result ReconcileResult
+		want   []ActionType
+	}{
+		{"complete", ReconcileResult{ID: "1", DBPresent: true, GamedataPresent: true, StoragePresent: true}, []ActionType{}},
+		{"missing db", ReconcileResult{ID: "1", GamedataPresent: true, StoragePresent: true}, []ActionType{ActionInsertDB}},
+		{"missing storage", ReconcileResult{ID: "1", DBPresent: true, GamedataPresent: true}, []ActionType{ActionFetchStorage}},
+		{"mismatch", ReconcileResult{ID: "1", DBPresent: true, GamedataPresent: true, StoragePresent: true, Mismatch: []string{"width: gd=2 db=1"}}, []ActionType{ActionSyncDB}},
+		{"orphan db and storage", ReconcileResult{ID: "1", DBPresent: true, StoragePresent: true}, []ActionType{ActionDeleteDB, ActionDeleteStorage}},
+		{"not found", ReconcileResult{ID: "1"}, []ActionType{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actions := SuggestActions(tt.result)
+			got := make([]ActionType, 0, len(actions))
+			for _, a := range actions {
+				got = append(got, a.Type)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// TestMismatchFields tests extraction of field labels from mismatch descriptions.
+func TestMismatchFields(t *testing.T) {
+	fields := MismatchFields([]string{"width: gd=2 db=1", "name: gd='a' db='b'", "width: again", "no-colon"})
+	assert.Equal(t, []string{"width", "name", "no-colon"}, fields)
+}
+
+// TestPurgeActionTypes_Policies tests which deletions each purge policy plans.
+func TestPurgeActionTypes_Policies(t *testing.T) {
+	onlyDB := ReconcileResult{ID: "1", DBPresent: true}
+	onlyGD := ReconcileResult{ID: "2", GamedataPresent: true}
+	onlyStorage := ReconcileResult{ID: "3", StoragePresent: true}
+	dbAndGD := ReconcileResult{ID: "4", DBPresent: true, GamedataPresent: true}
+	complete := ReconcileResult{ID: "5", DBPresent: true, GamedataPresent: true, StoragePresent: true}
+
+	tests := []struct {
+		name   string
+		policy PurgePolicy
+		result ReconcileResult
+		want   []ActionType
+	}{
+		{"strict db+gd", PurgeStrict, dbAndGD, []ActionType{ActionDeleteDB, ActionDeleteGamedata}},
+		{"strict complete", PurgeStrict, complete, nil},
+		{"empty policy is strict", "", onlyStorage, []ActionType{ActionDeleteStorage}},
+		{"storage orphans", PurgeStorageOrphans, onlyStorage, []ActionType{ActionDeleteStorage}},
+		{"storage orphans skips db", PurgeStorageOrphans, onlyDB, nil},
+		{"db orphans", PurgeDBOrphans, onlyDB, []ActionType{ActionDeleteDB}},
+		{"db orphans skips partial", PurgeDBOrphans, dbAndGD, nil},
+		{"gamedata ghosts", PurgeGamedataGhosts, onlyGD, []ActionType{ActionDeleteGamedata}},
+		{"gamedata ghosts skips storage", PurgeGamedataGhosts, onlyStorage, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, purgeActionTypes(tt.result, tt.policy))
+		})
+	}
+}
+
+// TestParsePurgePolicy tests policy name validation.
+func TestParsePurgePolicy(t *testing.T) {
+	policy, err := ParsePurgePolicy("")
+	assert.NoError(t, err)
+	assert.Equal(t, PurgeStrict, policy)
+
+	policy, err = ParsePurgePolicy("db-orphans-only")
+	assert.NoError(t, err)
+	assert.Equal(t, PurgeDBOrphans, policy)
+
+	_, err = ParsePurgePolicy("everything")
+	assert.Error(t, err)
+}
@@ -0,0 +1,236 @@
+package reconcile
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"asset-manager/core/json"
+)
+
+// PlanFileVersion is the format version of plan files written by SavePlanFile.
+// Version 2 signs the whole file rather than the plan alone.
+const PlanFileVersion = 2
+
+// ErrPlanStale is returned when the data a plan file was planned from changed since.
+var ErrPlanStale = errors.New("the data changed since the plan was saved")
+
+// PlanFile is a plan saved by one run, to be reviewed and applied by another, e.g. in
+// a maintenance window. Actions carry no item data; applying checks the file's
+// signature, replans with the saved options and checks that the data still hashes to
+// DataHash.
+type PlanFile struct {
+	// Version is the file format version (PlanFileVersion).
+	Version int `json:"version"`
+
+	// Adapter is the name of the adapter the plan was built for.
+	Adapter string `json:"adapter"`
+
+	// CreatedAt is when the plan was saved.
+	CreatedAt time.Time `json:"created_at"`
+
+	// Options are the plan options the actions were planned with.
+	Options PlanFileOptions `json:"options"`
+
+	// DataHash fingerprints the results the plan was built from (see DataHash).
+	DataHash string `json:"data_hash"`
+
+	// Plan holds the plan ID, summary and actions. Results are left out.
+	Plan *ReconcilePlan `json:"plan"`
+
+	// Signature is the HMAC of the version, adapter, options, data hash, plan ID and
+	// actions set by Sign.
+	Signature string `json:"signature"`
+}
+
+// PlanFileOptions are the ReconcileOptions that decide which actions are planned.
+type PlanFileOptions struct {
+	DoPurge          bool          `json:"purge,omitempty"`
+	PurgePolicy      PurgePolicy   `json:"purge_policy,omitempty"`
+	DoSync           bool          `json:"sync,omitempty"`
+	SyncDirection    SyncDirection `json:"sync_direction,omitempty"`
+	DoFixStorage     bool          `json:"fix_storage,omitempty"`
+	DoInsertGamedata bool          `json:"insert_gamedata,omitempty"`
+}
+
+// planFileOptions returns the options of opts that decide which actions are planned.
+func planFileOptions(opts ReconcileOptions) PlanFileOptions {
+	return PlanFileOptions{
+		DoPurge:          opts.DoPurge,
+		PurgePolicy:      opts.PurgePolicy,
+		DoSync:           opts.DoSync,
+		SyncDirection:    opts.SyncDirection,
+		DoFixStorage:     opts.DoFixStorage,
+		DoInsertGamedata: opts.DoInsertGamedata,
+	}
+}
+
+// ReconcileOptions returns the options to replan with. They are not confirmed.
+func (o PlanFileOptions) ReconcileOptions() ReconcileOptions {
+	return ReconcileOptions{
+		DoPurge:          o.DoPurge,
+		PurgePolicy:      o.PurgePolicy,
+		DoSync:           o.DoSync,
+		SyncDirection:    o.SyncDirection,
+		DoFixStorage:     o.DoFixStorage,
+		DoInsertGamedata: o.DoInsertGamedata,
+	}
+}
+
+// NewPlanFile prepares plan, planned by adapter with opts, to be saved. The plan gets
+// an ID if it has none, so the applying run records it under the same ID, and the file
+// is signed with secret (the API key), so the applying run can refuse a file whose
+// options, data hash, ID or actions were edited since (see PlanFile.CheckSignature).
+func NewPlanFile(adapter string, opts ReconcileOptions, plan *ReconcilePlan, secret string) (*PlanFile, error) {
+	hash, err := DataHash(plan.Results)
+	if err != nil {
+		return nil, err
+	}
+	if plan.ID == "" {
+		plan.ID = NewPlanID()
+	}
+	saved := &ReconcilePlan{
+		ID:          plan.ID,
+		Actions:     plan.Actions,
+		Summary:     plan.Summary,
+		Diagnostics: plan.Diagnostics,
+	}
+	f := &PlanFile{
+		Version:   PlanFileVersion,
+		Adapter:   adapter,
+		CreatedAt: time.Now().UTC(),
+		Options:   planFileOptions(opts),
+		DataHash:  hash,
+		Plan:      saved,
+	}
+	if err := f.Sign(secret); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// SavePlanFile writes f to path as indented JSON.
+func SavePlanFile(path string, f *PlanFile) error {
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode plan file: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write plan file: %w", err)
+	}
+	return nil
+}
+
+// LoadPlanFile reads a plan file written by SavePlanFile.
+func LoadPlanFile(path string) (*PlanFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plan file: %w", err)
+	}
+	var f PlanFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse plan file %s: %w", path, err)
+	}
+	if f.Version != PlanFileVersion {
+		return nil, fmt.Errorf("unsupported plan file version %d (want %d)", f.Version, PlanFileVersion)
+	}
+	if f.Plan == nil {
+		return nil, fmt.Errorf("plan file %s holds no plan", path)
+	}
+	return &f, nil
+}
+
+// Resolve checks that fresh, replanned from current data with f.Options, was built from
+// the data f was saved from, and returns it narrowed to the saved actions under the
+// saved plan ID. Fresh actions carry the item data sync and insert actions need. It
+// returns ErrPlanStale when the data hash differs.
+func (f *PlanFile) Resolve(fresh *ReconcilePlan) (*ReconcilePlan, error) {
+	hash, err := DataHash(fresh.Results)
+	if err != nil {
+		return nil, err
+	}
+	if hash != f.DataHash {
+		return nil, fmt.Errorf("%w (saved %s, now %s); plan again", ErrPlanStale, shortHash(f.DataHash), shortHash(hash))
+	}
+
+	byKey := make(map[string]Action, len(fresh.Actions))
+	for _, action := range fresh.Actions {
+		byKey[approvalKey(action)] = action
+	}
+	actions := make([]Action, 0, len(f.Plan.Actions))
+	for _, saved := range f.Plan.Actions {
+		action, ok := byKey[approvalKey(saved)]
+		if !ok {
+			return nil, fmt.Errorf("%w: action %s of %s is no longer planned", ErrPlanStale, saved.Type, saved.Key)
+		}
+		actions = append(actions, action)
+	}
+
+	fresh.ID = f.Plan.ID
+	fresh.Actions = actions
+	return fresh, nil
+}
+
+// hashedResult is the part of a result that decides which actions are planned.
+type hashedResult struct {
+	ID             string    `json:"id"`
+	Present        []string  `json:"present"`
+	Misplaced      string    `json:"misplaced,omitempty"`
+	OrphanAge      OrphanAge `json:"orphan_age,omitempty"`
+	Mismatch       []string  `json:"mismatch,omitempty"`
+	Warnings       []string  `json:"warnings,omitempty"`
+	Pending        bool      `json:"pending,omitempty"`
+	ExpectedAbsent []string  `json:"expected_absent,omitempty"`
+}
+
+// DataHash returns the SHA-256 of what results say about each entity: where it is
+// present, its mismatches with their values, and its misplaced file, orphan age,
+// grace period and expected absences. Results must be in key order, as planned.
+func DataHash(results []ReconcileResult) (string, error) {
+	h := sha256.New()
+	enc := json.NewEncoder(h)
+	for _, r := range results {
+		err := enc.Encode(hashedResult{
+			ID:             r.ID,
+			Present:        presentSources(r),
+			Misplaced:      r.Misplaced,
+			OrphanAge:      r.OrphanAge,
+			Mismatch:       r.Mismatch,
+			Warnings:       r.Warnings,
+			Pending:        r.Pending,
+			ExpectedAbsent: r.ExpectedAbsent,
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to hash results: %w", err)
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// presentSources returns the sources r is present in, sorted.
+func presentSources(r ReconcileResult) []string {
+	sources := r.Sources
+	if sources == nil {
+		sources = map[string]bool{SourceDB: r.DBPresent, SourceGamedata: r.GamedataPresent, SourceStorage: r.StoragePresent}
+	}
+	present := make([]string, 0, len(sources))
+	for source, ok := range sources {
+		if ok {
+			present = append(present, source)
+		}
+	}
+	sort.Strings(present)
+	return present
+}
+
+// shortHash abbreviates a data hash for error messages.
+func shortHash(hash string) string {
+	if len(hash) > 12 {
+		return hash[:12]
+	}
+	return hash
+}
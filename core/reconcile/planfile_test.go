@@ -0,0 +1,183 @@
+package reconcile
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"asset-manager/core/json"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// planFileFixture returns a plan of two items as ReconcileWithPlan would build it.
+func planFileFixture() *ReconcilePlan {
+	return &ReconcilePlan{
+		Results: []ReconcileResult{
+			{ID: "1", DBPresent: true, GamedataPresent: true},
+			{ID: "2", DBPresent: true, GamedataPresent: true, StoragePresent: true, Mismatch: []string{"name: gd=a db=b"}},
+		},
+		Actions: []Action{
+			{Type: ActionDeleteDB, Key: "1", Reason: "missing in: [storage]"},
+			{Type: ActionSyncDB, Key: "2", Reason: "mismatch: [name: gd=a db=b]", Fields: []string{"name"}, GDItem: "gd-2"},
+		},
+	}
+}
+
+// TestPlanFile tests that a saved plan applies to an unchanged replan, keeping the
+// item data of the replanned actions and the saved plan ID.
+func TestPlanFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plan.json")
+	opts := ReconcileOptions{DoPurge: true, PurgePolicy: PurgeStrict, DoSync: true, Confirmed: true, DryRun: true}
+
+	f, err := NewPlanFile("furniture", opts, planFileFixture(), "secret")
+	require.NoError(t, err)
+	require.NoError(t, SavePlanFile(path, f))
+
+	loaded, err := LoadPlanFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "furniture", loaded.Adapter)
+	assert.Equal(t, f.DataHash, loaded.DataHash)
+	assert.Empty(t, loaded.Plan.Results, "results are not saved")
+	assert.Equal(t, ReconcileOptions{DoPurge: true, PurgePolicy: PurgeStrict, DoSync: true}, loaded.Options.ReconcileOptions(),
+		"confirmation and dry-run are not saved")
+
+	plan, err := loaded.Resolve(planFileFixture())
+	require.NoError(t, err)
+	assert.Equal(t, f.Plan.ID, plan.ID)
+	require.Len(t, plan.Actions, 2)
+	assert.Equal(t, "gd-2", plan.Actions[1].GDItem)
+}
+
+// TestPlanFile_Narrowed tests that only the saved actions of a narrowed plan are applied.
+func TestPlanFile_Narrowed(t *testing.T) {
+	saved := planFileFixture()
+	saved.Actions = saved.Actions[1:]
+	f, err := NewPlanFile("furniture", ReconcileOptions{}, saved, "secret")
+	require.NoError(t, err)
+
+	plan, err := f.Resolve(planFileFixture())
+	require.NoError(t, err)
+	require.Len(t, plan.Actions, 1)
+	assert.Equal(t, "2", plan.Actions[0].Key)
+}
+
+// TestPlanFile_Stale tests that a plan is refused once the data it was built from changed.
+func TestPlanFile_Stale(t *testing.T) {
+	f, err := NewPlanFile("furniture", ReconcileOptions{}, planFileFixture(), "secret")
+	require.NoError(t, err)
+
+	changed := planFileFixture()
+	changed.Results[1].Mismatch = []string{"name: gd=a db=c"}
+	_, err = f.Resolve(changed)
+	assert.True(t, errors.Is(err, ErrPlanStale))
+
+	appeared := planFileFixture()
+	appeared.Results[0].StoragePresent = true
+	_, err = f.Resolve(appeared)
+	assert.True(t, errors.Is(err, ErrPlanStale))
+
+	// Same data, but an action the saved plan holds is no longer planned
+	dropped := planFileFixture()
+	dropped.Actions = dropped.Actions[:1]
+	_, err = f.Resolve(dropped)
+	assert.True(t, errors.Is(err, ErrPlanStale))
+}
+
+// TestLoadPlanFile_Invalid tests that unreadable and foreign files are rejected.
+func TestLoadPlanFile_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	_, err := LoadPlanFile(filepath.Join(dir, "missing.json"))
+	assert.Error(t, err)
+
+	path := filepath.Join(dir, "future.json")
+	require.NoError(t, SavePlanFile(path, &PlanFile{Version: PlanFileVersion + 1, Plan: &ReconcilePlan{}}))
+	_, err = LoadPlanFile(path)
+	assert.ErrorContains(t, err, "unsupported plan file version")
+}
+
+// TestPlanFile_Signature tests that a saved plan is refused once its file was edited,
+// through the same save, load and check an apply goes through.
+func TestPlanFile_Signature(t *testing.T) {
+	save := func(t *testing.T) string {
+		path := filepath.Join(t.TempDir(), "plan.json")
+		f, err := NewPlanFile("furniture", ReconcileOptions{DoPurge: true}, planFileFixture(), "secret")
+		require.NoError(t, err)
+		require.NoError(t, SavePlanFile(path, f))
+		return path
+	}
+	// edit rewrites the file at path
+	edit := func(t *testing.T, path string, change func(doc map[string]any)) {
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		var doc map[string]any
+		require.NoError(t, json.Unmarshal(data, &doc))
+		change(doc)
+		data, err = json.Marshal(doc)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(path, data, 0o644))
+	}
+	load := func(t *testing.T, path string) *PlanFile {
+		f, err := LoadPlanFile(path)
+		require.NoError(t, err)
+		return f
+	}
+	plan := func(doc map[string]any) map[string]any {
+		return doc["plan"].(map[string]any)
+	}
+
+	t.Run("untouched", func(t *testing.T) {
+		f := load(t, save(t))
+		assert.Len(t, f.Signature, 64)
+		assert.NoError(t, f.CheckSignature("secret"))
+		assert.ErrorIs(t, f.CheckSignature("other"), ErrPlanTampered)
+	})
+	t.Run("summary and creation time", func(t *testing.T) {
+		path := save(t)
+		edit(t, path, func(doc map[string]any) {
+			doc["created_at"] = "2020-01-01T00:00:00Z"
+			plan(doc)["summary"] = map[string]any{}
+		})
+		assert.NoError(t, load(t, path).CheckSignature("secret"))
+	})
+
+	tampered := []struct {
+		name   string
+		change func(doc map[string]any)
+	}{
+		{"added action", func(doc map[string]any) {
+			actions := plan(doc)["actions"].([]any)
+			plan(doc)["actions"] = append(actions, map[string]any{"type": string(ActionDeleteStorage), "key": "3", "reason": "injected"})
+		}},
+		{"changed action", func(doc map[string]any) {
+			plan(doc)["actions"].([]any)[1].(map[string]any)["key"] = "7"
+		}},
+		{"changed id", func(doc map[string]any) { plan(doc)["id"] = NewPlanID() }},
+		{"changed data hash", func(doc map[string]any) { doc["data_hash"] = strings.Repeat("0", 64) }},
+		{"changed options", func(doc map[string]any) {
+			doc["options"] = map[string]any{"purge": true, "purge_policy": string(PurgeDBOrphans)}
+		}},
+		{"changed adapter", func(doc map[string]any) { doc["adapter"] = "catalog" }},
+	}
+	for _, tt := range tampered {
+		t.Run(tt.name, func(t *testing.T) {
+			path := save(t)
+			edit(t, path, tt.change)
+			assert.ErrorIs(t, load(t, path).CheckSignature("secret"), ErrPlanTampered)
+		})
+	}
+
+	t.Run("signature removed", func(t *testing.T) {
+		path := save(t)
+		edit(t, path, func(doc map[string]any) { delete(doc, "signature") })
+		assert.ErrorIs(t, load(t, path).CheckSignature("secret"), ErrPlanUnsigned)
+	})
+	t.Run("no key", func(t *testing.T) {
+		_, err := NewPlanFile("furniture", ReconcileOptions{}, planFileFixture(), "")
+		assert.ErrorIs(t, err, ErrNoSigningKey)
+		assert.ErrorIs(t, load(t, save(t)).CheckSignature(""), ErrNoSigningKey)
+	})
+}
@@ -0,0 +1,217 @@
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrNoPlanStatusStore is returned when a plan status is read without a registered store.
+	ErrNoPlanStatusStore = errors.New("plan statuses require the state store (STATE_PATH)")
+
+	// ErrPlanStatusNotFound is returned when no applied plan has the requested ID.
+	ErrPlanStatusNotFound = errors.New("plan status not found")
+)
+
+// Plan states.
+const (
+	// PlanApplied marks a plan whose actions all ran.
+	PlanApplied = "applied"
+	// PlanPartial marks a plan that stopped after some of its actions ran.
+	PlanPartial = "partial"
+	// PlanFailed marks a plan that stopped before any of its actions ran.
+	PlanFailed = "failed"
+)
+
+// PlanStatus is the stored outcome of applying a plan: which actions ran, which failed
+// and which were never reached, so an apply that stopped midway can be resumed by
+// replanning with Options and applying the remaining actions.
+type PlanStatus struct {
+	// PlanID is the ID of the applied plan.
+	PlanID string `json:"plan_id"`
+
+	// RunID is the run that applied the plan (see WithRunID).
+	RunID string `json:"run_id,omitempty"`
+
+	// Adapter is the name of the adapter the plan belongs to.
+	Adapter string `json:"adapter"`
+
+	// Time is when the apply finished or stopped.
+	Time time.Time `json:"time"`
+
+	// State is PlanApplied, PlanPartial or PlanFailed. An applied plan may still
+	// carry an Error, e.g. when its audit entry could not be written.
+	State string `json:"state"`
+
+	// Options are the plan options the actions were planned with.
+	Options PlanFileOptions `json:"options"`
+
+	// Total is the number of actions of the plan.
+	Total int `json:"total"`
+
+	// Executed lists the actions that ran.
+	Executed []Action `json:"executed"`
+
+	// Failed lists the actions that ran and failed.
+	Failed []FailedAction `json:"failed"`
+
+	// Skipped lists the downloads skipped because the upstream lacks their object.
+	Skipped []Action `json:"skipped,omitempty"`
+
+	// Remaining lists the actions never reached, in plan order.
+	Remaining []Action `json:"remaining"`
+
+	// Error is the error the apply stopped with.
+	Error string `json:"error,omitempty"`
+}
+
+// FailedAction is an action whose execution failed.
+type FailedAction struct {
+	Action
+
+	// Error is the failure message.
+	Error string `json:"error"`
+}
+
+// PlanStatusStore persists the outcome of applied plans.
+type PlanStatusStore interface {
+	// SavePlanStatus stores status, replacing an earlier one of the same plan.
+	SavePlanStatus(ctx context.Context, status *PlanStatus) error
+
+	// LoadPlanStatus returns the status of the plan, or ErrPlanStatusNotFound.
+	LoadPlanStatus(ctx context.Context, planID string) (*PlanStatus, error)
+}
+
+// planStatusRegistry holds the process-wide plan status store.
+type planStatusRegistry struct {
+	mu    sync.RWMutex
+	store PlanStatusStore
+}
+
+// globalPlanStatuses is the singleton plan status registry for all reconcile operations.
+var globalPlanStatuses = &planStatusRegistry{}
+
+// SetPlanStatusStore registers the store keeping the outcome of applied plans.
+// Passing nil disables it.
+func SetPlanStatusStore(store PlanStatusStore) {
+	globalPlanStatuses.mu.Lock()
+	defer globalPlanStatuses.mu.Unlock()
+	globalPlanStatuses.store = store
+}
+
+// currentPlanStatusStore returns the registered plan status store, or nil.
+func currentPlanStatusStore() PlanStatusStore {
+	globalPlanStatuses.mu.RLock()
+	defer globalPlanStatuses.mu.RUnlock()
+	return globalPlanStatuses.store
+}
+
+// FindPlanStatus returns the stored outcome of the plan with the given ID.
+func FindPlanStatus(ctx context.Context, planID string) (*PlanStatus, error) {
+	store := currentPlanStatusStore()
+	if store == nil {
+		return nil, ErrNoPlanStatusStore
+	}
+	return store.LoadPlanStatus(ctx, planID)
+}
+
+// applyOutcome tracks the actions ApplyPlan carried out, by type and key.
+type applyOutcome struct {
+	executed map[actionRef]bool
+	failed   map[actionRef]string
+}
+
+// actionRef identifies an action within a plan.
+type actionRef struct {
+	typ ActionType
+	key string
+}
+
+// newApplyOutcome returns an empty outcome.
+func newApplyOutcome() *applyOutcome {
+	return &applyOutcome{executed: make(map[actionRef]bool), failed: make(map[actionRef]string)}
+}
+
+// done marks the actions of type typ on keys as executed.
+func (o *applyOutcome) done(typ ActionType, keys ...string) {
+	for _, key := range keys {
+		o.executed[actionRef{typ, key}] = true
+	}
+}
+
+// fail marks the actions of type typ on keys as failed with message.
+func (o *applyOutcome) fail(typ ActionType, message string, keys ...string) {
+	for _, key := range keys {
+		o.failed[actionRef{typ, key}] = message
+	}
+}
+
+// actionKeys returns the keys of actions.
+func actionKeys(actions []Action) []string {
+	keys := make([]string, len(actions))
+	for i, action := range actions {
+		keys[i] = action.Key
+	}
+	return keys
+}
+
+// status builds the status of plan, applied with opts, that stopped with applyErr.
+func (o *applyOutcome) status(ctx context.Context, spec *Spec, plan *ReconcilePlan, opts ReconcileOptions, applyErr error) *PlanStatus {
+	status := &PlanStatus{
+		PlanID:    plan.ID,
+		RunID:     RunIDFrom(ctx),
+		Adapter:   spec.Adapter.Name(),
+		Time:      time.Now().UTC(),
+		Options:   planFileOptions(opts),
+		Total:     len(plan.Actions),
+		Executed:  make([]Action, 0),
+		Failed:    make([]FailedAction, 0),
+		Remaining: make([]Action, 0),
+	}
+	skipped := make(map[string]bool, len(plan.SkippedDownloads))
+	for _, s := range plan.SkippedDownloads {
+		skipped[s.Key] = true
+	}
+	for _, action := range plan.Actions {
+		ref := actionRef{action.Type, action.Key}
+		switch message, failed := o.failed[ref]; {
+		case failed:
+			status.Failed = append(status.Failed, FailedAction{Action: action, Error: message})
+		case o.executed[ref]:
+			status.Executed = append(status.Executed, action)
+		case action.Type == ActionDownloadStorage && skipped[action.Key]:
+			status.Skipped = append(status.Skipped, action)
+		default:
+			status.Remaining = append(status.Remaining, action)
+		}
+	}
+
+	switch {
+	case len(status.Failed) == 0 && len(status.Remaining) == 0:
+		status.State = PlanApplied
+	case len(status.Executed) > 0:
+		status.State = PlanPartial
+	default:
+		status.State = PlanFailed
+	}
+	if applyErr != nil {
+		status.Error = applyErr.Error()
+	}
+	return status
+}
+
+// savePlanStatus stores the outcome of an applied plan. It is a no-op when no plan
+// status store is registered.
+func savePlanStatus(ctx context.Context, status *PlanStatus) error {
+	store := currentPlanStatusStore()
+	if store == nil {
+		return nil
+	}
+	if err := store.SavePlanStatus(ctx, status); err != nil {
+		return fmt.Errorf("failed to save plan status: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package reconcile
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryPlanStatuses is an in-memory PlanStatusStore for tests.
+type memoryPlanStatuses struct {
+	statuses map[string]PlanStatus
+}
+
+func (m *memoryPlanStatuses) SavePlanStatus(ctx context.Context, status *PlanStatus) error {
+	m.statuses[status.PlanID] = *status
+	return nil
+}
+
+func (m *memoryPlanStatuses) LoadPlanStatus(ctx context.Context, planID string) (*PlanStatus, error) {
+	status, ok := m.statuses[planID]
+	if !ok {
+		return nil, ErrPlanStatusNotFound
+	}
+	return &status, nil
+}
+
+// TestApplyPlan_SavesPartialStatus tests that an apply stopping midway stores which
+// actions ran, which failed and which were never reached.
+func TestApplyPlan_SavesPartialStatus(t *testing.T) {
+	_, err := FindPlanStatus(context.Background(), "plan-1")
+	assert.ErrorIs(t, err, ErrNoPlanStatusStore)
+
+	store := &memoryPlanStatuses{statuses: make(map[string]PlanStatus)}
+	SetPlanStatusStore(store)
+	defer SetPlanStatusStore(nil)
+
+	failure := DeleteFailure{Key: "21", Action: ActionDeleteStorage, Code: "AccessDenied", Message: "access denied"}
+	mutator := &mockBatchMutator{storageErr: &BatchDeleteError{Failures: []DeleteFailure{failure}}}
+	spec := &Spec{Adapter: mutator}
+	plan := &ReconcilePlan{
+		ID: "plan-1",
+		Actions: []Action{
+			{Type: ActionDeleteDB, Key: "1"},
+			{Type: ActionDeleteStorage, Key: "20"},
+			{Type: ActionDeleteStorage, Key: "21"},
+			{Type: ActionSyncDB, Key: "3", Fields: []string{"width"}},
+		},
+	}
+	ctx := WithRunID(context.Background(), "run-1")
+
+	_, err = ApplyPlan(ctx, spec, nil, nil, "", plan, ReconcileOptions{Confirmed: true, DoPurge: true, DoSync: true})
+	require.Error(t, err)
+
+	status, err := FindPlanStatus(context.Background(), "plan-1")
+	require.NoError(t, err)
+	assert.Equal(t, PlanPartial, status.State)
+	assert.Equal(t, "run-1", status.RunID)
+	assert.Equal(t, "mock", status.Adapter)
+	assert.Equal(t, 4, status.Total)
+	assert.True(t, status.Options.DoPurge)
+	assert.True(t, status.Options.DoSync)
+	assert.Equal(t, []Action{{Type: ActionDeleteDB, Key: "1"}, {Type: ActionDeleteStorage, Key: "20"}}, status.Executed)
+	assert.Equal(t, []FailedAction{{Action: Action{Type: ActionDeleteStorage, Key: "21"}, Error: "access denied"}}, status.Failed)
+	assert.Equal(t, []Action{{Type: ActionSyncDB, Key: "3", Fields: []string{"width"}}}, status.Remaining)
+	assert.Contains(t, status.Error, "failed to batch delete storage keys")
+
+	// A plan that runs to the end has nothing left
+	mutator.storageErr = nil
+	plan.ID = "plan-2"
+	_, err = ApplyPlan(ctx, spec, nil, nil, "", plan, ReconcileOptions{Confirmed: true})
+	require.NoError(t, err)
+
+	status, err = FindPlanStatus(context.Background(), "plan-2")
+	require.NoError(t, err)
+	assert.Equal(t, PlanApplied, status.State)
+	assert.Len(t, status.Executed, 4)
+	assert.Empty(t, status.Failed)
+	assert.Empty(t, status.Remaining)
+	assert.Empty(t, status.Error)
+
+	_, err = FindPlanStatus(context.Background(), "plan-3")
+	assert.ErrorIs(t, err, ErrPlanStatusNotFound)
+}
@@ -0,0 +1,235 @@
+package reconcile
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"asset-manager/core/database"
+	"asset-manager/core/storage"
+
+	"github.com/minio/minio-go/v7"
+	"gorm.io/gorm"
+)
+
+// Permission stores reported by the preflight.
+const (
+	PermissionStoreDB      = "db"
+	PermissionStoreStorage = "storage"
+)
+
+// preflightProbe is the object name written and removed to test storage rights.
+const preflightProbe = ".asset-manager-preflight"
+
+// PermissionCheck is one right a mutating run needs.
+type PermissionCheck struct {
+	// Store is PermissionStoreDB or PermissionStoreStorage.
+	Store string `json:"store"`
+
+	// Target is the table ("emulator.items_base") or object prefix ("assets/bundled/furniture").
+	Target string `json:"target"`
+
+	// Permission is the SQL privilege (DELETE, UPDATE, ALTER) or storage action (PutObject, DeleteObject).
+	Permission string `json:"permission"`
+
+	// Granted is true when the right is available.
+	Granted bool `json:"granted"`
+
+	// Detail explains a denied check (e.g. the storage error).
+	Detail string `json:"detail,omitempty"`
+}
+
+// PermissionReport lists the rights checked before a mutating run.
+type PermissionReport struct {
+	Checks []PermissionCheck `json:"checks"`
+}
+
+// Missing returns the checks that were not granted.
+func (r *PermissionReport) Missing() []PermissionCheck {
+	var missing []PermissionCheck
+	for _, c := range r.Checks {
+		if !c.Granted {
+			missing = append(missing, c)
+		}
+	}
+	return missing
+}
+
+// PermissionError is returned when a mutating run lacks required rights.
+type PermissionError struct {
+	Report *PermissionReport
+}
+
+// Error implements error.
+func (e *PermissionError) Error() string {
+	missing := e.Report.Missing()
+	parts := make([]string, 0, len(missing))
+	for _, c := range missing {
+		parts = append(parts, fmt.Sprintf("%s on %s %s", c.Permission, c.Store, c.Target))
+	}
+	return "missing permissions: " + strings.Join(parts, "; ")
+}
+
+// CheckPermissions verifies that the DB user and storage credentials hold every right
+// the options need, before anything is planned or applied. It returns a
+// *PermissionError with the full report when a right is missing.
+//
+// DB grants are only inspected on MySQL. Storage rights are probed by writing and
+// removing a small object next to the data, never by touching real assets.
+func CheckPermissions(
+	ctx context.Context,
+	spec *Spec,
+	db *gorm.DB,
+	client storage.Client,
+	bucket string,
+	opts ReconcileOptions,
+) (*PermissionReport, error) {
+	report := &PermissionReport{Checks: make([]PermissionCheck, 0)}
+	if !opts.mutates() {
+		return report, nil
+	}
+
+	stores := purgeStores(opts)
+
+	// Prepare widens columns before any mutating run
+	privileges := []string{"ALTER"}
+	_, gamedataSyncer := spec.Adapter.(GamedataSyncer)
+	syncsDB, syncsGamedata := FieldPolicies().writes(opts.SyncDirection)
+	reverse := opts.DoSync && syncsGamedata && gamedataSyncer
+	if opts.DoSync && syncsDB {
+		privileges = append(privileges, "UPDATE")
+	}
+	if stores[SourceDB] {
+		privileges = append(privileges, "DELETE")
+	}
+	if err := checkDBPrivileges(db, spec, privileges, report); err != nil {
+		return nil, err
+	}
+
+	if stores[SourceStorage] {
+		prefix := path.Join(spec.StoragePrefix, preflightProbe)
+		report.Checks = append(report.Checks, probeDelete(ctx, client, bucket, prefix))
+	}
+	// Syncs move misplaced objects, which writes and removes under the storage prefix
+	_, mover := spec.Adapter.(StorageMover)
+	moving := mover && opts.DoSync
+	if moving {
+		probe := path.Join(spec.StoragePrefix, preflightProbe)
+		report.Checks = append(report.Checks, probePut(ctx, client, bucket, probe))
+		if !stores[SourceStorage] {
+			report.Checks = append(report.Checks, probeDelete(ctx, client, bucket, probe))
+		}
+	}
+	// Downloads write under the storage prefix
+	if _, downloader := spec.Adapter.(StorageDownloader); downloader && opts.DoFixStorage && !moving {
+		probe := path.Join(spec.StoragePrefix, preflightProbe)
+		report.Checks = append(report.Checks, probePut(ctx, client, bucket, probe))
+	}
+	// Reverse syncs and inserts write gamedata like gamedata purges do
+	_, inserter := spec.Adapter.(GamedataInserter)
+	writesGamedata := stores[SourceGamedata] || reverse || (opts.DoInsertGamedata && inserter)
+	if writesGamedata && IsGamedataURL(spec.GamedataObjectName) {
+		report.Checks = append(report.Checks, PermissionCheck{
+			Store:      PermissionStoreStorage,
+			Target:     spec.GamedataObjectName,
+			Permission: "PutObject",
+			Detail:     "gamedata is served over HTTP and cannot be written",
+		})
+	} else if writesGamedata {
+		probe := path.Join(path.Dir(spec.GamedataObjectName), preflightProbe)
+		report.Checks = append(report.Checks, probePut(ctx, client, spec.gamedataBucket(bucket), probe))
+	}
+
+	if len(report.Missing()) > 0 {
+		return report, &PermissionError{Report: report}
+	}
+	return report, nil
+}
+
+// purgeStores returns the stores a purge under the options deletes from.
+func purgeStores(opts ReconcileOptions) map[string]bool {
+	if !opts.DoPurge {
+		return map[string]bool{}
+	}
+	switch opts.PurgePolicy {
+	case PurgeStorageOrphans, PurgeStaleStorageOrphans:
+		return map[string]bool{SourceStorage: true}
+	case PurgeDBOrphans:
+		return map[string]bool{SourceDB: true}
+	case PurgeGamedataGhosts:
+		return map[string]bool{SourceGamedata: true}
+	default:
+		return map[string]bool{SourceDB: true, SourceGamedata: true, SourceStorage: true}
+	}
+}
+
+// checkDBPrivileges appends one check per privilege on the adapter's table.
+func checkDBPrivileges(db *gorm.DB, spec *Spec, privileges []string, report *PermissionReport) error {
+	if db == nil || db.Dialector.Name() != "mysql" {
+		return nil
+	}
+
+	grants, err := database.GetCurrentGrants(db)
+	if err != nil {
+		return err
+	}
+
+	var schema string
+	if err := db.Raw("SELECT DATABASE()").Scan(&schema).Error; err != nil {
+		return fmt.Errorf("failed to read current database: %w", err)
+	}
+
+	table := ""
+	target := schema + ".*"
+	if namer, ok := spec.Adapter.(TableNamer); ok {
+		table = namer.TableName(spec.ServerProfile)
+		target = schema + "." + table
+	}
+
+	for _, privilege := range privileges {
+		report.Checks = append(report.Checks, PermissionCheck{
+			Store:      PermissionStoreDB,
+			Target:     target,
+			Permission: privilege,
+			Granted:    database.HasPrivilege(grants, privilege, schema, table),
+		})
+	}
+	return nil
+}
+
+// probeDelete checks DeleteObject by removing an object that does not exist,
+// which S3-compatible stores accept only when the right is granted.
+func probeDelete(ctx context.Context, client storage.Client, bucket, object string) PermissionCheck {
+	check := PermissionCheck{
+		Store:      PermissionStoreStorage,
+		Target:     path.Join(bucket, path.Dir(object)),
+		Permission: "DeleteObject",
+	}
+	if err := client.RemoveObject(ctx, bucket, object, minio.RemoveObjectOptions{}); err != nil && !storage.IsNoSuchKey(err) {
+		check.Detail = err.Error()
+		return check
+	}
+	check.Granted = true
+	return check
+}
+
+// probePut checks PutObject by writing an empty probe object and removing it again.
+func probePut(ctx context.Context, client storage.Client, bucket, object string) PermissionCheck {
+	check := PermissionCheck{
+		Store:      PermissionStoreStorage,
+		Target:     path.Join(bucket, path.Dir(object)),
+		Permission: "PutObject",
+	}
+	_, err := client.PutObject(ctx, bucket, object, bytes.NewReader(nil), 0, minio.PutObjectOptions{})
+	if err != nil {
+		check.Detail = err.Error()
+		return check
+	}
+	check.Granted = true
+
+	// Best effort: a leftover probe is harmless and ignored by every index
+	_ = client.RemoveObject(ctx, bucket, object, minio.RemoveObjectOptions{})
+	return check
+}
@@ -0,0 +1,99 @@
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"asset-manager/core/storage/mocks"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+// tableAdapter is a mockAdapter that names its DB table.
+type tableAdapter struct {
+	mockAdapter
+}
+
+func (a *tableAdapter) TableName(serverProfile string) string {
+	return "items_base"
+}
+
+// mysqlMock opens a GORM MySQL connection backed by sqlmock.
+func mysqlMock(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
+	conn, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	db, err := gorm.Open(mysql.New(mysql.Config{Conn: conn, SkipInitializeWithVersion: true}), &gorm.Config{})
+	require.NoError(t, err)
+	return db, sqlMock
+}
+
+// TestCheckPermissions_ReportOnly tests that runs without mutations check nothing.
+func TestCheckPermissions_ReportOnly(t *testing.T) {
+	spec := &Spec{Adapter: &mockAdapter{}}
+
+	for _, opts := range []ReconcileOptions{{}, {DoPurge: true, DoSync: true, DryRun: true}} {
+		report, err := CheckPermissions(context.Background(), spec, nil, new(mocks.Client), "assets", opts)
+		assert.NoError(t, err)
+		assert.Empty(t, report.Checks)
+	}
+}
+
+// TestCheckPermissions_DBGrants tests that privileges are checked on the adapter's table.
+func TestCheckPermissions_DBGrants(t *testing.T) {
+	db, sqlMock := mysqlMock(t)
+	sqlMock.ExpectQuery("SHOW GRANTS FOR CURRENT_USER\\(\\)").WillReturnRows(
+		sqlmock.NewRows([]string{"Grants for am@%"}).
+			AddRow("GRANT SELECT, UPDATE ON `emulator`.* TO `am`@`%`").
+			AddRow("GRANT ALTER ON `emulator`.`items_base` TO `am`@`%`"))
+	sqlMock.ExpectQuery("SELECT DATABASE\\(\\)").WillReturnRows(
+		sqlmock.NewRows([]string{"DATABASE()"}).AddRow("emulator"))
+
+	spec := &Spec{Adapter: &tableAdapter{}}
+	opts := ReconcileOptions{DoPurge: true, PurgePolicy: PurgeDBOrphans, DoSync: true}
+
+	report, err := CheckPermissions(context.Background(), spec, db, new(mocks.Client), "assets", opts)
+
+	var permErr *PermissionError
+	require.True(t, errors.As(err, &permErr))
+	assert.Len(t, report.Checks, 3)
+	assert.Equal(t, []PermissionCheck{
+		{Store: PermissionStoreDB, Target: "emulator.items_base", Permission: "DELETE"},
+	}, report.Missing())
+	assert.Equal(t, "missing permissions: DELETE on db emulator.items_base", err.Error())
+}
+
+// TestCheckPermissions_Storage tests that storage rights are probed for the purged stores.
+func TestCheckPermissions_Storage(t *testing.T) {
+	spec := &Spec{
+		Adapter:            &mockAdapter{},
+		StoragePrefix:      "bundled/furniture",
+		GamedataObjectName: "gamedata/FurnitureData.json",
+	}
+
+	mockClient := new(mocks.Client)
+	mockClient.On("RemoveObject", mock.Anything, "assets", "bundled/furniture/"+preflightProbe, mock.Anything).
+		Return(minio.ErrorResponse{Code: "AccessDenied", Message: "Access Denied."})
+	mockClient.On("PutObject", mock.Anything, "assets", "gamedata/"+preflightProbe, mock.Anything, int64(0), mock.Anything).
+		Return(minio.UploadInfo{}, nil)
+	mockClient.On("RemoveObject", mock.Anything, "assets", "gamedata/"+preflightProbe, mock.Anything).
+		Return(nil)
+
+	report, err := CheckPermissions(context.Background(), spec, nil, mockClient, "assets", ReconcileOptions{DoPurge: true})
+
+	var permErr *PermissionError
+	require.True(t, errors.As(err, &permErr))
+	missing := report.Missing()
+	require.Len(t, missing, 1)
+	assert.Equal(t, "DeleteObject", missing[0].Permission)
+	assert.Equal(t, "assets/bundled/furniture", missing[0].Target)
+	assert.Equal(t, "Access Denied.", missing[0].Detail)
+	mockClient.AssertExpectations(t)
+}
@@ -0,0 +1,11 @@
+package reconcile
+
+// PriceBounds are the highest catalog offer prices the catalog price check accepts,
+// per currency. Zero disables a bound.
+type PriceBounds struct {
+	// MaxCredits bounds the credit price (cost_credits).
+	MaxCredits int `mapstructure:"max_credits" default:"100000"`
+	// MaxPoints bounds the activity point and diamond prices (cost_points,
+	// cost_pixels, cost_diamonds).
+	MaxPoints int `mapstructure:"max_points" default:"100000"`
+}
@@ -0,0 +1,201 @@
+package reconcile
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// ProgressStage names a step of a full reconciliation.
+type ProgressStage string
+
+const (
+	// StageIndexes is reported before the DB, gamedata, storage and source indices are loaded.
+	StageIndexes ProgressStage = "indexes"
+
+	// StageListing is reported every ProgressInterval storage objects an adapter lists
+	// (see ReportListed); Processed is the number listed so far.
+	StageListing ProgressStage = "listing"
+
+	// StageIndexed is reported as each index finishes loading; Source names it and
+	// Processed is its size.
+	StageIndexed ProgressStage = "indexed"
+
+	// StageUnion is reported once the union of keys is known; Total is its size.
+	StageUnion ProgressStage = "union"
+
+	// StageResults is reported every ProgressInterval results and after the last one.
+	StageResults ProgressStage = "results"
+
+	// StageSummary is reported last, with the summary of the run.
+	StageSummary ProgressStage = "summary"
+
+	// StageApplying is reported by ApplyPlan as actions run; Processed is the number
+	// executed and Total the number planned.
+	StageApplying ProgressStage = "applying"
+)
+
+// ProgressInterval is the number of results between two StageResults events.
+const ProgressInterval = 1000
+
+// ProgressEvent is one step of a full reconciliation.
+type ProgressEvent struct {
+	// Stage is the step reached.
+	Stage ProgressStage `json:"stage"`
+
+	// Source is the index an event is about (StageListing and StageIndexed), e.g.
+	// SourceDB or the name of an additional source.
+	Source string `json:"source,omitempty"`
+
+	// Processed is the number of results built (StageResults), objects listed
+	// (StageListing), entries indexed (StageIndexed) or actions executed (StageApplying)
+	// so far.
+	Processed int `json:"processed,omitempty"`
+
+	// Total is the number of keys in the union (StageUnion and StageResults) or of
+	// planned actions (StageApplying).
+	Total int `json:"total,omitempty"`
+
+	// Summary holds the counts of the run (StageSummary).
+	Summary *PlanSummary `json:"summary,omitempty"`
+}
+
+// ProgressFunc receives the progress events of a reconciliation. Events of one run
+// are delivered one at a time and in order, but not always from the same goroutine.
+type ProgressFunc func(ProgressEvent)
+
+// progressKey is the context key of the active ProgressFunc.
+type progressKey struct{}
+
+// WithProgress returns a context under which ReconcileAll, ReconcileWithPlan and
+// ApplyPlan report their progress to fn. Indices load concurrently, so fn is called
+// under a lock.
+func WithProgress(ctx context.Context, fn ProgressFunc) context.Context {
+	if fn == nil {
+		return ctx
+	}
+	var mu sync.Mutex
+	serialized := ProgressFunc(func(event ProgressEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		fn(event)
+	})
+	return context.WithValue(ctx, progressKey{}, serialized)
+}
+
+// ReportListed lets adapters report the storage objects they listed so far under a
+// context from WithProgress. Only every ProgressInterval-th count is sent on.
+func ReportListed(ctx context.Context, listed int) {
+	if listed > 0 && listed%ProgressInterval == 0 {
+		progressFrom(ctx).report(ProgressEvent{Stage: StageListing, Source: SourceStorage, Processed: listed})
+	}
+}
+
+// ReportApplied lets batch mutators report each action they executed, so ApplyPlan
+// progress moves during a batch instead of once at its end.
+func ReportApplied(ctx context.Context) {
+	if counter, ok := ctx.Value(applyKey{}).(*applyCounter); ok {
+		counter.add(1)
+	}
+}
+
+// progressFrom returns the ProgressFunc of ctx, or nil.
+func progressFrom(ctx context.Context) ProgressFunc {
+	fn, _ := ctx.Value(progressKey{}).(ProgressFunc)
+	return fn
+}
+
+// report sends event to fn when there is one.
+func (fn ProgressFunc) report(event ProgressEvent) {
+	if fn != nil {
+		fn(event)
+	}
+}
+
+// resultCounter counts built results for any number of workers and reports every
+// ProgressInterval-th and the last one, never going backwards.
+type resultCounter struct {
+	fn       ProgressFunc
+	total    int
+	done     atomic.Int64
+	mu       sync.Mutex
+	reported int
+}
+
+// newResultCounter returns a counter reporting to fn, or nil when fn is nil.
+func newResultCounter(fn ProgressFunc, total int) *resultCounter {
+	if fn == nil {
+		return nil
+	}
+	return &resultCounter{fn: fn, total: total}
+}
+
+// add counts one result.
+func (c *resultCounter) add() {
+	if c == nil {
+		return
+	}
+	n := int(c.done.Add(1))
+	if n%ProgressInterval != 0 && n != c.total {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// Workers crossing two intervals at once may arrive out of order
+	if n > c.reported {
+		c.reported = n
+		c.fn(ProgressEvent{Stage: StageResults, Processed: n, Total: c.total})
+	}
+}
+
+// applyKey is the context key of the active applyCounter.
+type applyKey struct{}
+
+// applyCounter counts the actions ApplyPlan executed, both those reported one at a
+// time by batch mutators and those ApplyPlan counts itself, never going backwards.
+type applyCounter struct {
+	fn    ProgressFunc
+	total int
+	mu    sync.Mutex
+	done  int
+}
+
+// withApplyCounter returns a context carrying a counter of total actions reporting to
+// the ProgressFunc of ctx, or ctx and nil when there is none.
+func withApplyCounter(ctx context.Context, total int) (context.Context, *applyCounter) {
+	fn := progressFrom(ctx)
+	if fn == nil {
+		return ctx, nil
+	}
+	counter := &applyCounter{fn: fn, total: total}
+	return context.WithValue(ctx, applyKey{}, counter), counter
+}
+
+// add counts n executed actions.
+func (c *applyCounter) add(n int) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	done := min(c.done+n, c.total)
+	if done == c.done {
+		return
+	}
+	c.done = done
+	c.fn(ProgressEvent{Stage: StageApplying, Processed: c.done, Total: c.total})
+}
+
+// reach raises the count to executed when batch mutators reported fewer actions.
+func (c *applyCounter) reach(executed int) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if executed > c.done {
+		c.done = executed
+		c.fn(ProgressEvent{Stage: StageApplying, Processed: c.done, Total: c.total})
+	}
+}
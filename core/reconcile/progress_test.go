@@ -0,0 +1,122 @@
+package reconcile
+
+import (
+	"context"
+	"testing"
+
+	"asset-manager/core/storage/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReconcileAll_Progress tests the stages reported for a full reconciliation.
+func TestReconcileAll_Progress(t *testing.T) {
+	adapter := &mockAdapter{
+		dbIndex:    map[string]DBItem{"A": "A", "B": "B"},
+		gdIndex:    map[string]GDItem{"B": "B", "C": "C"},
+		storageSet: map[string]struct{}{"C": {}},
+		mismatches: map[string][]string{},
+	}
+	mockClient := new(mocks.Client)
+	mockClient.On("BucketExists", mock.Anything, "").Return(true, nil)
+
+	var events []ProgressEvent
+	ctx := WithProgress(context.Background(), func(e ProgressEvent) { events = append(events, e) })
+	_, err := ReconcileAll(ctx, &Spec{Adapter: adapter}, nil, mockClient, "")
+	require.NoError(t, err)
+
+	require.Len(t, events, 7)
+	assert.Equal(t, ProgressEvent{Stage: StageIndexes}, events[0])
+	// Indices load concurrently, so they finish in any order
+	assert.ElementsMatch(t, []ProgressEvent{
+		{Stage: StageIndexed, Source: SourceDB, Processed: 2},
+		{Stage: StageIndexed, Source: SourceGamedata, Processed: 2},
+		{Stage: StageIndexed, Source: SourceStorage, Processed: 1},
+	}, events[1:4])
+	assert.Equal(t, ProgressEvent{Stage: StageUnion, Total: 3}, events[4])
+	assert.Equal(t, ProgressEvent{Stage: StageResults, Processed: 3, Total: 3}, events[5])
+	assert.Equal(t, StageSummary, events[6].Stage)
+	require.NotNil(t, events[6].Summary)
+	assert.Equal(t, 3, events[6].Summary.TotalItems)
+}
+
+// TestApplyPlan_Progress tests that executed actions are reported as they run, and
+// that counts reported by batch mutators never exceed the plan.
+func TestApplyPlan_Progress(t *testing.T) {
+	mutator := &mockMutator{}
+	plan := &ReconcilePlan{
+		Actions: []Action{
+			{Type: ActionDeleteDB, Key: "1"},
+			{Type: ActionDeleteDB, Key: "2"},
+			{Type: ActionDeleteStorage, Key: "20"},
+		},
+	}
+
+	var events []ProgressEvent
+	ctx := WithProgress(context.Background(), func(e ProgressEvent) { events = append(events, e) })
+	executed, err := ApplyPlan(ctx, &Spec{Adapter: mutator}, nil, nil, "", plan, ReconcileOptions{Confirmed: true})
+	require.NoError(t, err)
+	assert.Equal(t, 3, executed)
+	assert.Equal(t, []ProgressEvent{
+		{Stage: StageApplying, Processed: 1, Total: 3},
+		{Stage: StageApplying, Processed: 2, Total: 3},
+		{Stage: StageApplying, Processed: 3, Total: 3},
+	}, events)
+
+	events = nil
+	ctx, counter := withApplyCounter(ctx, 2)
+	for range 3 {
+		ReportApplied(ctx)
+	}
+	counter.reach(2)
+	assert.Equal(t, []ProgressEvent{
+		{Stage: StageApplying, Processed: 1, Total: 2},
+		{Stage: StageApplying, Processed: 2, Total: 2},
+	}, events)
+}
+
+// TestReportListed tests that listed objects are reported every interval.
+func TestReportListed(t *testing.T) {
+	var events []ProgressEvent
+	ctx := WithProgress(context.Background(), func(e ProgressEvent) { events = append(events, e) })
+	for n := 1; n <= 2*ProgressInterval+1; n++ {
+		ReportListed(ctx, n)
+	}
+	assert.Equal(t, []ProgressEvent{
+		{Stage: StageListing, Source: SourceStorage, Processed: ProgressInterval},
+		{Stage: StageListing, Source: SourceStorage, Processed: 2 * ProgressInterval},
+	}, events)
+
+	// Without a ProgressFunc nothing is reported
+	ReportListed(context.Background(), ProgressInterval)
+	ReportApplied(context.Background())
+}
+
+// TestBuildResults_Progress tests that results are reported every interval, in order,
+// whether they are built serially or by several workers.
+func TestBuildResults_Progress(t *testing.T) {
+	cache, adapter := benchCache(parallelResultsMin + 500)
+	defer func(workers int) { resultWorkers = workers }(resultWorkers)
+
+	for _, workers := range []int{1, 4} {
+		resultWorkers = workers
+		var processed []int
+		buildResults(cache, adapter, func(e ProgressEvent) {
+			if e.Stage == StageResults {
+				processed = append(processed, e.Processed)
+			}
+		})
+
+		require.NotEmpty(t, processed)
+		assert.Equal(t, parallelResultsMin+500, processed[len(processed)-1])
+		assert.IsIncreasing(t, processed)
+		for _, n := range processed[:len(processed)-1] {
+			assert.Zero(t, n%ProgressInterval)
+		}
+		if workers == 1 {
+			assert.Len(t, processed, (parallelResultsMin+500)/ProgressInterval+1)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package reconcile
+
+import "slices"
+
+// Fix recipe categories, in the order FixRecipes returns them.
+const (
+	// RecipeMismatches covers entities whose DB fields differ from gamedata.
+	RecipeMismatches = "mismatches"
+
+	// RecipeStorageOrphans covers storage objects with no DB row and no gamedata entry.
+	RecipeStorageOrphans = "storage_orphans"
+
+	// RecipeDBOrphans covers DB rows with no gamedata entry and no storage object.
+	RecipeDBOrphans = "db_orphans"
+
+	// RecipeGamedataGhosts covers gamedata entries with no DB row and no storage object.
+	RecipeGamedataGhosts = "gamedata_ghosts"
+
+	// RecipeIncomplete covers entities missing from one store only.
+	RecipeIncomplete = "incomplete"
+)
+
+// FixRecipe is the follow-up run that would address one category of issues.
+type FixRecipe struct {
+	// Category names the issues addressed (see RecipeMismatches and the other categories).
+	Category string `json:"category"`
+
+	// Count is the number of entities of the category the run would act on.
+	Count int `json:"count"`
+
+	// Args are the flags to pass to the adapter's reconcile command, e.g. ["--sync"].
+	Args []string `json:"args"`
+
+	// Fields lists the mismatching fields a sync would update (RecipeMismatches).
+	Fields []string `json:"fields,omitempty"`
+
+	// Description says what the run would do.
+	Description string `json:"description"`
+}
+
+// recipeDefinition ties a category to the plan options addressing it.
+type recipeDefinition struct {
+	category    string
+	opts        ReconcileOptions
+	args        []string
+	description string
+}
+
+// recipeDefinitions lists the recipes from the least to the most destructive.
+var recipeDefinitions = []recipeDefinition{
+	{RecipeMismatches, ReconcileOptions{DoSync: true}, []string{"--sync"},
+		"update the DB rows from gamedata"},
+	{RecipeStorageOrphans, ReconcileOptions{DoPurge: true, PurgePolicy: PurgeStorageOrphans}, []string{"--purge", "--purge-policy", string(PurgeStorageOrphans)},
+		"delete storage objects nothing else references"},
+	{RecipeDBOrphans, ReconcileOptions{DoPurge: true, PurgePolicy: PurgeDBOrphans}, []string{"--purge", "--purge-policy", string(PurgeDBOrphans)},
+		"delete DB rows nothing else references"},
+	{RecipeGamedataGhosts, ReconcileOptions{DoPurge: true, PurgePolicy: PurgeGamedataGhosts}, []string{"--purge", "--purge-policy", string(PurgeGamedataGhosts)},
+		"delete gamedata entries nothing else references"},
+	{RecipeIncomplete, ReconcileOptions{DoPurge: true, PurgePolicy: PurgeStrict}, []string{"--purge"},
+		"delete entities missing from one store from the other two, and every orphan above; restoring the missing part may be the better fix"},
+}
+
+// FixRecipes returns, for each category of issues in results, the flags of the run
+// that would address it. Counts follow the plan builder: they are the entities a plan
+// with those flags would act on. Categories without issues are left out.
+func FixRecipes(results []ReconcileResult) []FixRecipe {
+	var recipes []FixRecipe
+	for _, def := range recipeDefinitions {
+		recipe := FixRecipe{Category: def.category, Args: def.args, Description: def.description}
+		for _, result := range results {
+			switch {
+			case def.opts.DoSync:
+				if !plansSync(result) {
+					continue
+				}
+				for _, field := range MismatchFields(syncMismatches(result)) {
+					if !slices.Contains(recipe.Fields, field) {
+						recipe.Fields = append(recipe.Fields, field)
+					}
+				}
+			case def.category == RecipeIncomplete:
+				// Orphans have recipes of their own; count what only the strict purge adds
+				if len(purgeActionTypes(result, def.opts.PurgePolicy)) < 2 {
+					continue
+				}
+			default:
+				if len(purgeActionTypes(result, def.opts.PurgePolicy)) == 0 {
+					continue
+				}
+			}
+			recipe.Count++
+		}
+		if recipe.Count > 0 {
+			slices.Sort(recipe.Fields)
+			recipes = append(recipes, recipe)
+		}
+	}
+	return recipes
+}
@@ -0,0 +1,46 @@
+package reconcile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFixRecipes tests that each category gets the flags of the plan acting on it.
+func TestFixRecipes(t *testing.T) {
+	assert.Empty(t, FixRecipes(nil))
+
+	results := []ReconcileResult{
+		{ID: "1", DBPresent: true, GamedataPresent: true, StoragePresent: true},
+		{ID: "2", DBPresent: true, GamedataPresent: true, StoragePresent: true, Mismatch: []string{"width: gd=2 db=1", "name: gd='a' db='b'"}},
+		{ID: "3", DBPresent: true, GamedataPresent: true, StoragePresent: true, Mismatch: []string{"width: gd=3 db=1"}},
+		{ID: "4", StoragePresent: true},
+		{ID: "5", StoragePresent: true},
+		{ID: "6", DBPresent: true},
+		{ID: "7", DBPresent: true, GamedataPresent: true},
+		// Mismatches without a storage object are purged rather than synced by a strict
+		// purge, but a sync alone still updates them
+		{ID: "8", DBPresent: true, GamedataPresent: true, Mismatch: []string{"length: gd=2 db=1"}},
+	}
+
+	recipes := FixRecipes(results)
+	require.Len(t, recipes, 4)
+
+	assert.Equal(t, RecipeMismatches, recipes[0].Category)
+	assert.Equal(t, 3, recipes[0].Count)
+	assert.Equal(t, []string{"--sync"}, recipes[0].Args)
+	assert.Equal(t, []string{"length", "name", "width"}, recipes[0].Fields)
+
+	assert.Equal(t, RecipeStorageOrphans, recipes[1].Category)
+	assert.Equal(t, 2, recipes[1].Count)
+	assert.Equal(t, []string{"--purge", "--purge-policy", "storage-orphans-only"}, recipes[1].Args)
+
+	assert.Equal(t, RecipeDBOrphans, recipes[2].Category)
+	assert.Equal(t, 1, recipes[2].Count)
+
+	assert.Equal(t, RecipeIncomplete, recipes[3].Category)
+	assert.Equal(t, 2, recipes[3].Count)
+	assert.Equal(t, []string{"--purge"}, recipes[3].Args)
+	assert.Empty(t, recipes[3].Fields)
+}
@@ -0,0 +1,38 @@
+package reconcile
+
+import (
+	"sort"
+	"sync"
+)
+
+// specRegistry holds the reconcile specs of every domain the application serves.
+type specRegistry struct {
+	mu    sync.RWMutex
+	specs map[string]*Spec
+}
+
+// globalSpecs is the singleton registry used by cross-adapter operations such as health.
+var globalSpecs = &specRegistry{specs: make(map[string]*Spec)}
+
+// RegisterSpec makes a spec available to cross-adapter operations.
+// Registering again for the same adapter name replaces the previous spec.
+func RegisterSpec(spec *Spec) {
+	globalSpecs.mu.Lock()
+	defer globalSpecs.mu.Unlock()
+	globalSpecs.specs[spec.Adapter.Name()] = spec
+}
+
+// RegisteredSpecs returns every registered spec, sorted by adapter name.
+func RegisteredSpecs() []*Spec {
+	globalSpecs.mu.RLock()
+	defer globalSpecs.mu.RUnlock()
+
+	specs := make([]*Spec, 0, len(globalSpecs.specs))
+	for _, spec := range globalSpecs.specs {
+		specs = append(specs, spec)
+	}
+	sort.Slice(specs, func(i, j int) bool {
+		return specs[i].Adapter.Name() < specs[j].Adapter.Name()
+	})
+	return specs
+}
@@ -0,0 +1,42 @@
+package reconcile
+
+import (
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// replicaRegistry holds the optional read replica used to build indices.
+type replicaRegistry struct {
+	mu sync.RWMutex
+	db *gorm.DB
+}
+
+// globalReplica is the singleton replica registry for all reconcile operations.
+var globalReplica = &replicaRegistry{}
+
+// SetReadReplica registers a read-only connection for LoadDBIndex, QueryDB and
+// additional sources. Mutations, schema preparation, permission checks and
+// post-apply verification keep using the primary passed by the caller, so that
+// replication lag never hides the effect of an applied plan.
+// Passing nil reads from the primary again.
+func SetReadReplica(db *gorm.DB) {
+	globalReplica.mu.Lock()
+	defer globalReplica.mu.Unlock()
+	globalReplica.db = db
+}
+
+// ReadDB returns the connection to read indices from. Without a primary (no
+// database configured) there is nothing to read, so nil is kept.
+func ReadDB(primary *gorm.DB) *gorm.DB {
+	if primary == nil {
+		return nil
+	}
+
+	globalReplica.mu.RLock()
+	defer globalReplica.mu.RUnlock()
+	if globalReplica.db != nil {
+		return globalReplica.db
+	}
+	return primary
+}
@@ -0,0 +1,54 @@
+package reconcile
+
+import (
+	"context"
+	"testing"
+
+	"asset-manager/core/storage/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// TestReadReplica_Routing tests that index builds read from the replica while
+// verification reads from the primary.
+func TestReadReplica_Routing(t *testing.T) {
+	primary, replica := &gorm.DB{}, &gorm.DB{}
+	SetReadReplica(replica)
+	defer SetReadReplica(nil)
+
+	var used []*gorm.DB
+	adapter := &mockAdapter{
+		dbLoadFunc: func(ctx context.Context, db *gorm.DB, serverProfile string) (map[string]DBItem, error) {
+			used = append(used, db)
+			return map[string]DBItem{"1": "1"}, nil
+		},
+	}
+	spec := &Spec{Adapter: adapter}
+
+	mockClient := new(mocks.Client)
+	mockClient.On("BucketExists", mock.Anything, "").Return(true, nil)
+
+	_, err := BuildCache(context.Background(), spec, primary, mockClient, "")
+	require.NoError(t, err)
+
+	plan := &ReconcilePlan{Actions: []Action{{Type: ActionDeleteDB, Key: "1"}}}
+	_, err = VerifyPlan(context.Background(), spec, primary, mockClient, "", plan)
+	require.NoError(t, err)
+
+	require.Len(t, used, 2)
+	assert.Same(t, replica, used[0])
+	assert.Same(t, primary, used[1])
+}
+
+// TestReadDB tests fallbacks when no replica or no primary is configured.
+func TestReadDB(t *testing.T) {
+	primary := &gorm.DB{}
+	assert.Same(t, primary, ReadDB(primary))
+
+	SetReadReplica(&gorm.DB{})
+	defer SetReadReplica(nil)
+	assert.Nil(t, ReadDB(nil))
+}
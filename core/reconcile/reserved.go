@@ -0,0 +1,39 @@
+package reconcile
+
+import "slices"
+
+// AbsenceExpecter is implemented by adapters whose emulator reserves entities that
+// legitimately lack some sources, such as room ads or gift wrapping without a storage
+// bundle. Such entities are reported as expected absent instead of missing there: they
+// are not counted, purged, downloaded or suggested a fetch for those sources.
+type AbsenceExpecter interface {
+	// ExpectedAbsent returns the sources the entity of key may be missing from. dbItem
+	// and gdItem are nil when the entity is absent from the database or gamedata.
+	ExpectedAbsent(key string, dbItem DBItem, gdItem GDItem) []string
+}
+
+// ExpectsAbsent reports whether the entity is missing from source as expected (see
+// AbsenceExpecter).
+func (r ReconcileResult) ExpectsAbsent(source string) bool {
+	return slices.Contains(r.ExpectedAbsent, source)
+}
+
+// lacks reports whether the entity is missing from source without being expected to.
+func (r ReconcileResult) lacks(source string) bool {
+	return !r.Present(source) && !r.ExpectsAbsent(source)
+}
+
+// markExpectedAbsent records the sources result is missing from that adapter expects
+// it to be, if adapter is an AbsenceExpecter. Entities known to neither the database
+// nor gamedata are never reserved.
+func markExpectedAbsent(result *ReconcileResult, adapter Adapter, dbItem DBItem, gdItem GDItem) {
+	expecter, ok := adapter.(AbsenceExpecter)
+	if !ok || (dbItem == nil && gdItem == nil) {
+		return
+	}
+	for _, source := range expecter.ExpectedAbsent(result.ID, dbItem, gdItem) {
+		if !result.Present(source) && !result.ExpectsAbsent(source) {
+			result.ExpectedAbsent = append(result.ExpectedAbsent, source)
+		}
+	}
+}
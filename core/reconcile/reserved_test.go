@@ -0,0 +1,58 @@
+package reconcile
+
+import (
+	"context"
+	"testing"
+
+	"asset-manager/core/storage/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// reservingAdapter is a mockMutator whose reserved keys are expected absent from storage.
+type reservingAdapter struct {
+	mockMutator
+	reserved map[string]bool
+}
+
+func (m *reservingAdapter) ExpectedAbsent(key string, dbItem DBItem, gdItem GDItem) []string {
+	if m.reserved[key] {
+		return []string{SourceStorage}
+	}
+	return nil
+}
+
+// TestReconcileWithPlan_ExpectedAbsent tests that reserved items missing from storage are
+// reported as expected absent instead of missing, and are not purged.
+func TestReconcileWithPlan_ExpectedAbsent(t *testing.T) {
+	mockClient := new(mocks.Client)
+	mockClient.On("BucketExists", mock.Anything, "").Return(true, nil)
+
+	adapter := &reservingAdapter{reserved: map[string]bool{"1": true, "3": true}}
+	adapter.dbIndex = map[string]DBItem{"1": "ads", "2": "chair"}
+	adapter.gdIndex = map[string]GDItem{"1": "ads", "2": "chair"}
+	adapter.storageSet = map[string]struct{}{"3": {}}
+
+	plan, err := ReconcileWithPlan(context.Background(), &Spec{Adapter: adapter}, nil, mockClient, "", ReconcileOptions{DoPurge: true})
+	require.NoError(t, err)
+	require.Len(t, plan.Results, 3)
+
+	reserved := plan.Results[0]
+	assert.Equal(t, []string{SourceStorage}, reserved.ExpectedAbsent)
+	assert.Empty(t, reserved.MissingSources())
+	assert.Empty(t, ResultIssues(reserved))
+	_, download := planDownload(reserved)
+	assert.False(t, download)
+
+	// Storage orphans are never reserved, whatever their key
+	assert.Empty(t, plan.Results[2].ExpectedAbsent)
+
+	assert.Equal(t, 1, plan.Summary.ExpectedAbsent)
+	assert.Equal(t, 1, plan.Summary.MissingStorage)
+	for _, action := range plan.Actions {
+		assert.NotEqual(t, "1", action.Key, "reserved items are not purged")
+	}
+	assert.Contains(t, plan.Actions, Action{Type: ActionDeleteDB, Key: "2", Reason: "missing in: [storage]"})
+}
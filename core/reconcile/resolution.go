@@ -0,0 +1,213 @@
+package reconcile
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"asset-manager/core/json"
+)
+
+// Decision is an operator's choice for the planned actions of one item.
+type Decision string
+
+const (
+	// DecisionKeep leaves the item as it is; none of its actions are applied.
+	DecisionKeep Decision = "keep"
+	// DecisionDelete applies the item's delete actions.
+	DecisionDelete Decision = "delete"
+	// DecisionSync applies the item's repair actions: syncs, moves, downloads and
+	// gamedata inserts.
+	DecisionSync Decision = "sync"
+	// DecisionSkip leaves the item undecided. It is not recorded, so a resumed
+	// resolution asks again.
+	DecisionSkip Decision = "skip"
+)
+
+// PendingItem groups the planned actions of one key for an operator to resolve.
+type PendingItem struct {
+	// Key is the entity identifier.
+	Key string
+
+	// Actions are the planned actions of Key, in plan order.
+	Actions []Action
+
+	// Kind describes the actions so that items of the same kind can be resolved at
+	// once: their types with the repaired fields of syncs, or the reason otherwise.
+	Kind string
+}
+
+// Choices returns the decisions that apply to the item: keep and skip always, delete
+// and sync when it has actions of that kind.
+func (p PendingItem) Choices() []Decision {
+	choices := []Decision{DecisionKeep}
+	if len(p.actionsFor(DecisionDelete)) > 0 {
+		choices = append(choices, DecisionDelete)
+	}
+	if len(p.actionsFor(DecisionSync)) > 0 {
+		choices = append(choices, DecisionSync)
+	}
+	return append(choices, DecisionSkip)
+}
+
+// actionsFor returns the actions of the item that decision approves.
+func (p PendingItem) actionsFor(decision Decision) []Action {
+	var approved []Action
+	for _, action := range p.Actions {
+		if decisionOf(action.Type) == decision {
+			approved = append(approved, action)
+		}
+	}
+	return approved
+}
+
+// decisionOf returns the decision that approves actions of type t.
+func decisionOf(t ActionType) Decision {
+	switch t {
+	case ActionDeleteDB, ActionDeleteGamedata, ActionDeleteStorage:
+		return DecisionDelete
+	}
+	return DecisionSync
+}
+
+// PendingItems groups plan actions by key, in the order keys first appear.
+func PendingItems(plan *ReconcilePlan) []PendingItem {
+	index := make(map[string]int)
+	var items []PendingItem
+	for _, action := range plan.Actions {
+		i, ok := index[action.Key]
+		if !ok {
+			i = len(items)
+			index[action.Key] = i
+			items = append(items, PendingItem{Key: action.Key})
+		}
+		items[i].Actions = append(items[i].Actions, action)
+	}
+	for i := range items {
+		items[i].Kind = itemKind(items[i].Actions)
+	}
+	return items
+}
+
+// itemKind describes actions by type, with the fields of syncs and the reason of other
+// actions. Sync reasons carry the mismatched values, which rarely repeat.
+func itemKind(actions []Action) string {
+	parts := make([]string, 0, len(actions))
+	for _, action := range actions {
+		detail := action.Reason
+		if len(action.Fields) > 0 {
+			detail = strings.Join(action.Fields, ",")
+		}
+		parts = append(parts, fmt.Sprintf("%s(%s)", action.Type, detail))
+	}
+	return strings.Join(parts, " ")
+}
+
+// ResolvedItem records the decision taken for one key and the actions it approved.
+type ResolvedItem struct {
+	// Key is the entity identifier.
+	Key string `json:"key"`
+
+	// Decision is the operator's choice. Never DecisionSkip.
+	Decision Decision `json:"decision"`
+
+	// Actions are the approved actions; empty for DecisionKeep.
+	Actions []Action `json:"actions,omitempty"`
+}
+
+// Resolution is the state of an interactive resolution, saved to a plan file after
+// every decision so that an interrupted session can be resumed.
+type Resolution struct {
+	// Items lists the decided keys in the order they were decided.
+	Items []ResolvedItem `json:"items"`
+}
+
+// LoadResolution reads the resolution saved at path. A missing file is an empty
+// resolution, so new and resumed sessions start the same way.
+func LoadResolution(path string) (*Resolution, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &Resolution{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plan file: %w", err)
+	}
+	var res Resolution
+	if err := json.Unmarshal(data, &res); err != nil {
+		return nil, fmt.Errorf("failed to parse plan file %s: %w", path, err)
+	}
+	return &res, nil
+}
+
+// Save writes the resolution to path through a temporary file, so an interruption
+// never leaves a truncated plan file behind.
+func (r *Resolution) Save(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode plan file: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write plan file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write plan file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write plan file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write plan file: %w", err)
+	}
+	return nil
+}
+
+// Decided reports whether key was already resolved.
+func (r *Resolution) Decided(key string) bool {
+	for _, item := range r.Items {
+		if item.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// Decide records decision for item. DecisionSkip records nothing.
+func (r *Resolution) Decide(item PendingItem, decision Decision) {
+	if decision == DecisionSkip {
+		return
+	}
+	r.Items = append(r.Items, ResolvedItem{
+		Key:      item.Key,
+		Decision: decision,
+		Actions:  item.actionsFor(decision),
+	})
+}
+
+// Approved returns the actions of plan the resolution approved, in plan order. Actions
+// are matched by key, type and fields, so a replanned item whose actions changed since
+// it was decided is left alone.
+func (r *Resolution) Approved(plan *ReconcilePlan) []Action {
+	approved := make(map[string]struct{})
+	for _, item := range r.Items {
+		for _, action := range item.Actions {
+			approved[approvalKey(action)] = struct{}{}
+		}
+	}
+	var actions []Action
+	for _, action := range plan.Actions {
+		if _, ok := approved[approvalKey(action)]; ok {
+			actions = append(actions, action)
+		}
+	}
+	return actions
+}
+
+// approvalKey identifies an action across plans of the same items.
+func approvalKey(action Action) string {
+	return action.Key + "|" + string(action.Type) + "|" + strings.Join(action.Fields, ",") + "|" + action.From
+}
@@ -0,0 +1,60 @@
+package reconcile
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPendingItems tests that actions are grouped per key with their choices and kind.
+func TestPendingItems(t *testing.T) {
+	plan := &ReconcilePlan{Actions: []Action{
+		{Type: ActionDeleteDB, Key: "1", Reason: "missing in: [storage]"},
+		{Type: ActionSyncDB, Key: "2", Reason: "mismatch: [name: gd=a db=b]", Fields: []string{"name"}},
+		{Type: ActionDeleteGamedata, Key: "1", Reason: "missing in: [storage]"},
+		{Type: ActionSyncDB, Key: "3", Reason: "mismatch: [name: gd=c db=d]", Fields: []string{"name"}},
+	}}
+
+	items := PendingItems(plan)
+	require.Len(t, items, 3)
+	assert.Equal(t, "1", items[0].Key)
+	assert.Len(t, items[0].Actions, 2)
+	assert.Equal(t, []Decision{DecisionKeep, DecisionDelete, DecisionSkip}, items[0].Choices())
+	assert.Equal(t, []Decision{DecisionKeep, DecisionSync, DecisionSkip}, items[1].Choices())
+	assert.Equal(t, items[1].Kind, items[2].Kind, "syncs of the same fields are similar")
+	assert.NotEqual(t, items[0].Kind, items[1].Kind)
+}
+
+// TestResolution tests that decisions survive a save and load, and approve only the
+// actions they were taken for.
+func TestResolution(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plan.json")
+	res, err := LoadResolution(path)
+	require.NoError(t, err)
+	assert.Empty(t, res.Items)
+
+	plan := &ReconcilePlan{Actions: []Action{
+		{Type: ActionDeleteDB, Key: "1"},
+		{Type: ActionSyncDB, Key: "1", Fields: []string{"name"}},
+		{Type: ActionSyncDB, Key: "2", Fields: []string{"name"}},
+		{Type: ActionDeleteStorage, Key: "3"},
+	}}
+	items := PendingItems(plan)
+	res.Decide(items[0], DecisionDelete)
+	res.Decide(items[1], DecisionKeep)
+	res.Decide(items[2], DecisionSkip)
+	require.NoError(t, res.Save(path))
+
+	loaded, err := LoadResolution(path)
+	require.NoError(t, err)
+	assert.True(t, loaded.Decided("1"))
+	assert.True(t, loaded.Decided("2"))
+	assert.False(t, loaded.Decided("3"), "skipped items stay undecided")
+	assert.Equal(t, []Action{{Type: ActionDeleteDB, Key: "1"}}, loaded.Approved(plan))
+
+	// A replanned item with other actions is not approved by an old decision
+	replanned := &ReconcilePlan{Actions: []Action{{Type: ActionDeleteStorage, Key: "1"}}}
+	assert.Empty(t, loaded.Approved(replanned))
+}
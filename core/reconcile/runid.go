@@ -0,0 +1,47 @@
+package reconcile
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// RunIDHeader carries the run ID of HTTP requests that run a reconciliation.
+const RunIDHeader = "X-Run-ID"
+
+// runIDKey is the context key of the run ID.
+type runIDKey struct{}
+
+// NewRunID returns a unique run ID.
+func NewRunID() string {
+	return uuid.New().String()
+}
+
+// WithRunID returns a context under which every reconciliation, stored run, audit
+// entry and status update carries id, so all artifacts of one CLI command, HTTP
+// request or scheduled job can be correlated. An empty id leaves ctx unchanged.
+func WithRunID(ctx context.Context, id string) context.Context {
+	if id == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, runIDKey{}, id)
+}
+
+// RunIDFrom returns the run ID of ctx, or "".
+func RunIDFrom(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(runIDKey{}).(string)
+	return id
+}
+
+// StartRun returns ctx with a run ID and that ID. The ID ctx already carries is kept,
+// so the reconciliations of one execution share it.
+func StartRun(ctx context.Context) (context.Context, string) {
+	if id := RunIDFrom(ctx); id != "" {
+		return ctx, id
+	}
+	id := NewRunID()
+	return WithRunID(ctx, id), id
+}
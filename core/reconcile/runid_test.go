@@ -0,0 +1,69 @@
+package reconcile
+
+import (
+	"context"
+	"testing"
+
+	"asset-manager/core/storage/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestStartRun tests that a run ID is only created when the context has none.
+func TestStartRun(t *testing.T) {
+	assert.Empty(t, RunIDFrom(context.Background()))
+	assert.Equal(t, context.Background(), WithRunID(context.Background(), ""))
+
+	ctx, id := StartRun(context.Background())
+	require.NotEmpty(t, id)
+	assert.Equal(t, id, RunIDFrom(ctx))
+
+	again, same := StartRun(ctx)
+	assert.Equal(t, id, same)
+	assert.Equal(t, ctx, again)
+
+	_, other := StartRun(context.Background())
+	assert.NotEqual(t, id, other)
+}
+
+// TestReconcileWithPlan_RunID tests that the run ID of the context reaches the plan,
+// the stored run and the audit entries of its fixes.
+func TestReconcileWithPlan_RunID(t *testing.T) {
+	runs := &memoryRuns{}
+	SetRunStore(runs)
+	defer SetRunStore(nil)
+	audit := &memoryAuditStore{}
+	SetAuditStore(audit)
+	defer SetAuditStore(nil)
+
+	mutator := &mockMutator{mockAdapter: mockAdapter{
+		dbIndex:    map[string]DBItem{"1": "1"},
+		gdIndex:    map[string]GDItem{"1": "1"},
+		storageSet: map[string]struct{}{"1": {}},
+		mismatches: map[string][]string{"1": {"width: gd=2 db=1"}},
+	}}
+	spec := &Spec{Adapter: mutator}
+	mockClient := new(mocks.Client)
+	mockClient.On("BucketExists", mock.Anything, "").Return(true, nil)
+
+	ctx := WithRunID(context.Background(), "run-1")
+	plan, err := ReconcileWithPlan(ctx, spec, nil, mockClient, "", ReconcileOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "run-1", plan.RunID)
+	require.Len(t, runs.runs, 1)
+	assert.Equal(t, "run-1", runs.runs[0].RunID)
+
+	// Without one, a run ID is generated and shared by the safe fixes
+	result, err := ApplySafeFixes(context.Background(), spec, nil, mockClient, "", SafeFixPolicy{SyncFields: []string{"width"}, MaxActions: 10})
+	require.NoError(t, err)
+	require.NotEmpty(t, result.RunID)
+	assert.NotEqual(t, "run-1", result.RunID)
+	require.Len(t, runs.runs, 2)
+	assert.Equal(t, result.RunID, runs.runs[1].RunID)
+	require.NotEmpty(t, audit.entries)
+	for _, entry := range audit.entries {
+		assert.Equal(t, result.RunID, entry.RunID)
+	}
+}
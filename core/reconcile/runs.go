@@ -0,0 +1,250 @@
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrNoRunStore is returned when run history is read without a registered store.
+	ErrNoRunStore = errors.New("run history requires the state store (STATE_PATH)")
+
+	// ErrRunNotFound is returned when no stored run matches a reference.
+	ErrRunNotFound = errors.New("run not found")
+
+	// ErrInvalidRunRef is returned for a run reference that is neither an ID nor a time.
+	ErrInvalidRunRef = errors.New("invalid run reference")
+)
+
+// Run is the stored outcome of one full reconciliation: its summary and the entities
+// with issues. Healthy entities are not stored.
+type Run struct {
+	// ID identifies the run within the store; IDs grow with every run.
+	ID uint64 `json:"id"`
+
+	// RunID is the ID of the execution the run belongs to (see WithRunID). Unlike ID
+	// it is known before the run is stored and shared with its audit entries.
+	RunID string `json:"run_id,omitempty"`
+
+	// Adapter is the name of the adapter reconciled.
+	Adapter string `json:"adapter"`
+
+	// Time is when the run finished.
+	Time time.Time `json:"time"`
+
+	// Summary holds the counts of the run. Planned actions are not kept, since they
+	// depend on the options of the run rather than the state of the hotel.
+	Summary PlanSummary `json:"summary"`
+
+	// Items lists the entities with issues, sorted by key. Run lists leave it empty.
+	Items []RunItem `json:"items,omitempty"`
+}
+
+// RunItem is an entity with issues in a stored run.
+type RunItem struct {
+	// Key is the entity key.
+	Key string `json:"key"`
+
+	// Name is the display name of the entity.
+	Name string `json:"name,omitempty"`
+
+	// Issues lists the entity's issues (see ResultIssues).
+	Issues []string `json:"issues"`
+}
+
+// RunStore persists the runs of every adapter.
+type RunStore interface {
+	// SaveRun stores run and sets its ID.
+	SaveRun(ctx context.Context, run *Run) error
+
+	// ListRuns returns at most limit runs of the adapter, newest first, without items.
+	ListRuns(ctx context.Context, adapter string, limit int) ([]Run, error)
+
+	// LoadRun returns the run of the adapter with the given ID, or ErrRunNotFound.
+	LoadRun(ctx context.Context, adapter string, id uint64) (*Run, error)
+
+	// LoadRunAt returns the latest run of the adapter finished at or before t, or
+	// ErrRunNotFound.
+	LoadRunAt(ctx context.Context, adapter string, t time.Time) (*Run, error)
+}
+
+// runRegistry holds the process-wide run store.
+type runRegistry struct {
+	mu    sync.RWMutex
+	store RunStore
+}
+
+// globalRuns is the singleton run registry for all reconcile operations.
+var globalRuns = &runRegistry{}
+
+// SetRunStore registers the store keeping the history of full reconciliations.
+// Passing nil disables the history.
+func SetRunStore(store RunStore) {
+	globalRuns.mu.Lock()
+	defer globalRuns.mu.Unlock()
+	globalRuns.store = store
+}
+
+// currentRunStore returns the registered run store, or nil.
+func currentRunStore() RunStore {
+	globalRuns.mu.RLock()
+	defer globalRuns.mu.RUnlock()
+	return globalRuns.store
+}
+
+// saveRun stores the summary and the entities with issues of a full reconciliation.
+// It is a no-op when no run store is registered.
+func saveRun(ctx context.Context, adapter string, results []ReconcileResult, summary PlanSummary) error {
+	store := currentRunStore()
+	if store == nil {
+		return nil
+	}
+
+	summary.Memory = nil
+	summary.PurgeActions = 0
+	summary.SyncActions = 0
+	run := &Run{RunID: RunIDFrom(ctx), Adapter: adapter, Time: time.Now().UTC(), Summary: summary}
+	for _, result := range results {
+		if issues := ResultIssues(result); len(issues) > 0 {
+			run.Items = append(run.Items, RunItem{Key: result.ID, Name: result.Name, Issues: issues})
+		}
+	}
+
+	if err := store.SaveRun(ctx, run); err != nil {
+		return fmt.Errorf("failed to save run: %w", err)
+	}
+	return nil
+}
+
+// ResultIssues returns the issues of a result: "missing_<source>" for every source
+// lacking the entity other than those it is expected to be absent from, then "mismatch: <description>" for every field mismatch.
+func ResultIssues(result ReconcileResult) []string {
+	var issues []string
+	if result.lacks(SourceDB) {
+		issues = append(issues, "missing_"+SourceDB)
+	}
+	if result.lacks(SourceGamedata) {
+		issues = append(issues, "missing_"+SourceGamedata)
+	}
+	if result.lacks(SourceStorage) {
+		issues = append(issues, "missing_"+SourceStorage)
+	}
+	var extra []string
+	for source, present := range result.Sources {
+		if !present && !isDefaultSource(source) && !result.ExpectsAbsent(source) {
+			extra = append(extra, "missing_"+source)
+		}
+	}
+	slices.Sort(extra)
+	issues = append(issues, extra...)
+
+	for _, mismatch := range result.Mismatch {
+		issues = append(issues, "mismatch: "+mismatch)
+	}
+	return issues
+}
+
+// RunHistory returns at most limit stored runs of the adapter, newest first, without
+// their items. It returns ErrNoRunStore when no store is registered.
+func RunHistory(ctx context.Context, adapter string, limit int) ([]Run, error) {
+	store := currentRunStore()
+	if store == nil {
+		return nil, ErrNoRunStore
+	}
+	return store.ListRuns(ctx, adapter, limit)
+}
+
+// FindRun returns the stored run of the adapter a reference names: a run ID, an
+// RFC 3339 time or a date (YYYY-MM-DD, UTC), the latter two selecting the latest run
+// finished at or before that time or by the end of that day. It returns ErrNoRunStore
+// when no store is registered and ErrRunNotFound when no run matches.
+func FindRun(ctx context.Context, adapter, ref string) (*Run, error) {
+	store := currentRunStore()
+	if store == nil {
+		return nil, ErrNoRunStore
+	}
+
+	ref = strings.TrimSpace(ref)
+	if id, err := strconv.ParseUint(ref, 10, 64); err == nil {
+		return store.LoadRun(ctx, adapter, id)
+	}
+	if t, err := time.Parse(time.RFC3339, ref); err == nil {
+		return store.LoadRunAt(ctx, adapter, t)
+	}
+	if day, err := time.Parse(time.DateOnly, ref); err == nil {
+		return store.LoadRunAt(ctx, adapter, day.AddDate(0, 0, 1).Add(-time.Nanosecond))
+	}
+	return nil, fmt.Errorf("%w %q: want a run ID, an RFC 3339 time or a YYYY-MM-DD date", ErrInvalidRunRef, ref)
+}
+
+// RunDiff lists how the entities with issues changed between two runs.
+type RunDiff struct {
+	// From and To are the compared runs, without their items.
+	From Run `json:"from"`
+	To   Run `json:"to"`
+
+	// Broken lists entities healthy in From with issues in To.
+	Broken []RunItemChange `json:"broken"`
+
+	// Fixed lists entities with issues in From that are healthy or gone in To.
+	Fixed []RunItemChange `json:"fixed"`
+
+	// Changed lists entities with issues in both runs whose issues differ.
+	Changed []RunItemChange `json:"changed"`
+}
+
+// RunItemChange is an entity whose issues differ between two runs.
+type RunItemChange struct {
+	// Key is the entity key.
+	Key string `json:"key"`
+
+	// Name is the display name of the entity in the later run it appears in.
+	Name string `json:"name,omitempty"`
+
+	// Before and After are the issues in the earlier and the later run.
+	Before []string `json:"before,omitempty"`
+	After  []string `json:"after,omitempty"`
+}
+
+// DiffRuns compares the entities with issues of two runs. Each list is sorted by key.
+func DiffRuns(from, to *Run) *RunDiff {
+	diff := &RunDiff{
+		Broken:  []RunItemChange{},
+		Fixed:   []RunItemChange{},
+		Changed: []RunItemChange{},
+	}
+	diff.From, diff.From.Items = *from, nil
+	diff.To, diff.To.Items = *to, nil
+
+	before := make(map[string]RunItem, len(from.Items))
+	for _, item := range from.Items {
+		before[item.Key] = item
+	}
+	after := make(map[string]RunItem, len(to.Items))
+	for _, item := range to.Items {
+		after[item.Key] = item
+		previous, existed := before[item.Key]
+		switch {
+		case !existed:
+			diff.Broken = append(diff.Broken, RunItemChange{Key: item.Key, Name: item.Name, After: item.Issues})
+		case !slices.Equal(previous.Issues, item.Issues):
+			diff.Changed = append(diff.Changed, RunItemChange{Key: item.Key, Name: item.Name, Before: previous.Issues, After: item.Issues})
+		}
+	}
+	for _, item := range from.Items {
+		if _, remains := after[item.Key]; !remains {
+			diff.Fixed = append(diff.Fixed, RunItemChange{Key: item.Key, Name: item.Name, Before: item.Issues})
+		}
+	}
+
+	for _, list := range [][]RunItemChange{diff.Broken, diff.Fixed, diff.Changed} {
+		slices.SortFunc(list, func(a, b RunItemChange) int { return strings.Compare(a.Key, b.Key) })
+	}
+	return diff
+}
@@ -0,0 +1,131 @@
+package reconcile
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"asset-manager/core/storage/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryRuns is an in-memory RunStore for tests.
+type memoryRuns struct {
+	runs []Run
+}
+
+func (m *memoryRuns) SaveRun(ctx context.Context, run *Run) error {
+	run.ID = uint64(len(m.runs) + 1)
+	m.runs = append(m.runs, *run)
+	return nil
+}
+
+func (m *memoryRuns) ListRuns(ctx context.Context, adapter string, limit int) ([]Run, error) {
+	var runs []Run
+	for i := len(m.runs) - 1; i >= 0 && len(runs) < limit; i-- {
+		if m.runs[i].Adapter == adapter {
+			run := m.runs[i]
+			run.Items = nil
+			runs = append(runs, run)
+		}
+	}
+	return runs, nil
+}
+
+func (m *memoryRuns) LoadRun(ctx context.Context, adapter string, id uint64) (*Run, error) {
+	for _, run := range m.runs {
+		if run.Adapter == adapter && run.ID == id {
+			return &run, nil
+		}
+	}
+	return nil, ErrRunNotFound
+}
+
+func (m *memoryRuns) LoadRunAt(ctx context.Context, adapter string, t time.Time) (*Run, error) {
+	for i := len(m.runs) - 1; i >= 0; i-- {
+		if run := m.runs[i]; run.Adapter == adapter && !run.Time.After(t) {
+			return &run, nil
+		}
+	}
+	return nil, ErrRunNotFound
+}
+
+// TestResultIssues tests the issues listed for a result.
+func TestResultIssues(t *testing.T) {
+	assert.Empty(t, ResultIssues(ReconcileResult{DBPresent: true, GamedataPresent: true, StoragePresent: true}))
+	assert.Equal(t, []string{"missing_storage", "missing_catalog", "mismatch: width: gd=2 db=1"}, ResultIssues(ReconcileResult{
+		DBPresent:       true,
+		GamedataPresent: true,
+		Sources:         map[string]bool{SourceDB: true, SourceGamedata: true, SourceStorage: false, "catalog": false},
+		Mismatch:        []string{"width: gd=2 db=1"},
+	}))
+}
+
+// TestReconcileWithPlan_SavesRun tests that full scans are stored and can be diffed.
+func TestReconcileWithPlan_SavesRun(t *testing.T) {
+	_, err := RunHistory(context.Background(), "mock", 10)
+	assert.ErrorIs(t, err, ErrNoRunStore)
+	_, err = FindRun(context.Background(), "mock", "1")
+	assert.ErrorIs(t, err, ErrNoRunStore)
+
+	store := &memoryRuns{}
+	SetRunStore(store)
+	defer SetRunStore(nil)
+
+	adapter := &mockAdapter{
+		dbIndex:    map[string]DBItem{"1": "1", "2": "2"},
+		gdIndex:    map[string]GDItem{"1": "1", "2": "2"},
+		storageSet: map[string]struct{}{"1": {}},
+		mismatches: map[string][]string{},
+	}
+	mockClient := new(mocks.Client)
+	mockClient.On("BucketExists", mock.Anything, "").Return(true, nil)
+	spec := &Spec{Adapter: adapter}
+
+	_, err = ReconcileWithPlan(context.Background(), spec, nil, mockClient, "", ReconcileOptions{DoPurge: true})
+	require.NoError(t, err)
+
+	// Item 2 gets its file back, item 1 loses it
+	adapter.storageSet = map[string]struct{}{"2": {}}
+	_, err = ReconcileWithPlan(context.Background(), spec, nil, mockClient, "", ReconcileOptions{})
+	require.NoError(t, err)
+
+	runs, err := RunHistory(context.Background(), "mock", 10)
+	require.NoError(t, err)
+	require.Len(t, runs, 2)
+	assert.Equal(t, uint64(2), runs[0].ID)
+	assert.Equal(t, 2, runs[0].Summary.TotalItems)
+	assert.Equal(t, 1, runs[0].Summary.MissingStorage)
+	assert.Zero(t, store.runs[0].Summary.PurgeActions)
+
+	from, err := FindRun(context.Background(), "mock", "1")
+	require.NoError(t, err)
+	to, err := FindRun(context.Background(), "mock", time.Now().UTC().Format(time.DateOnly))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), to.ID)
+
+	diff := DiffRuns(from, to)
+	assert.Equal(t, []RunItemChange{{Key: "1", Name: "db-name", After: []string{"missing_storage"}}}, diff.Broken)
+	assert.Equal(t, []RunItemChange{{Key: "2", Name: "db-name", Before: []string{"missing_storage"}}}, diff.Fixed)
+	assert.Empty(t, diff.Changed)
+	assert.Empty(t, diff.From.Items)
+
+	_, err = FindRun(context.Background(), "mock", "yesterday")
+	assert.ErrorIs(t, err, ErrInvalidRunRef)
+	_, err = FindRun(context.Background(), "mock", "9")
+	assert.ErrorIs(t, err, ErrRunNotFound)
+}
+
+// TestDiffRuns_Changed tests that entities whose issues differ are listed as changed.
+func TestDiffRuns_Changed(t *testing.T) {
+	from := &Run{ID: 1, Items: []RunItem{{Key: "3", Issues: []string{"missing_db"}}, {Key: "4", Issues: []string{"missing_db"}}}}
+	to := &Run{ID: 2, Items: []RunItem{{Key: "3", Issues: []string{"missing_db", "missing_storage"}}, {Key: "4", Issues: []string{"missing_db"}}}}
+
+	diff := DiffRuns(from, to)
+	assert.Empty(t, diff.Broken)
+	assert.Empty(t, diff.Fixed)
+	assert.Equal(t, []RunItemChange{{Key: "3", Before: []string{"missing_db"}, After: []string{"missing_db", "missing_storage"}}}, diff.Changed)
+}
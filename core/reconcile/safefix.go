@@ -0,0 +1,137 @@
+package reconcile
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"asset-manager/core/storage"
+
+	"gorm.io/gorm"
+)
+
+// SafeFixTrigger is the audit trigger recorded for unattended safe fixes.
+const SafeFixTrigger = "safe-fix"
+
+// SafeFixPolicy whitelists the actions that may be applied without human confirmation.
+// Deletions are never safe; only sync actions whose every mismatched field is listed qualify.
+type SafeFixPolicy struct {
+	// SyncFields lists the mismatch field labels (e.g. "width", "length") a sync may repair.
+	SyncFields []string
+
+	// MaxActions caps how many actions one run applies. Zero or less applies nothing.
+	MaxActions int
+}
+
+// SafeFixResult reports what an unattended safe-fix run did.
+type SafeFixResult struct {
+	// Planned is the number of actions the sync plan contained.
+	Planned int `json:"planned"`
+
+	// Skipped counts actions outside the whitelist.
+	Skipped int `json:"skipped"`
+
+	// Capped counts whitelisted actions left for a later run by MaxActions.
+	Capped int `json:"capped"`
+
+	// PlanID identifies the applied actions in the audit log; empty when none were applied.
+	PlanID string `json:"plan_id,omitempty"`
+
+	// RunID identifies the run (see WithRunID).
+	RunID string `json:"run_id"`
+
+	// Audit holds one entry per attempted action.
+	Audit []AuditEntry `json:"audit"`
+}
+
+// SelectSafeActions returns the whitelisted actions, up to the policy cap, plus the
+// number of actions skipped as unsafe and the number held back by the cap.
+func SelectSafeActions(actions []Action, policy SafeFixPolicy) (safe []Action, skipped, capped int) {
+	safe = make([]Action, 0)
+	for _, action := range actions {
+		if !policy.allows(action) {
+			skipped++
+			continue
+		}
+		if len(safe) >= policy.MaxActions {
+			capped++
+			continue
+		}
+		safe = append(safe, action)
+	}
+	return safe, skipped, capped
+}
+
+// allows reports whether the action may run unattended under this policy.
+func (p SafeFixPolicy) allows(action Action) bool {
+	if action.Type != ActionSyncDB || len(action.Fields) == 0 {
+		return false
+	}
+	for _, field := range action.Fields {
+		if !slices.Contains(p.SyncFields, field) {
+			return false
+		}
+	}
+	return true
+}
+
+// ApplySafeFixes plans a sync for the spec and applies the whitelisted subset without
+// confirmation. Every attempted action is written to the audit log. The spec's adapter
+// must implement Mutator with its mutation context already set.
+func ApplySafeFixes(
+	ctx context.Context,
+	spec *Spec,
+	db *gorm.DB,
+	client storage.Client,
+	bucket string,
+	policy SafeFixPolicy,
+) (*SafeFixResult, error) {
+	ctx, runID := StartRun(ctx)
+	plan, err := ReconcileWithPlan(ctx, spec, db, client, bucket, ReconcileOptions{DoSync: true})
+	if err != nil {
+		return nil, err
+	}
+
+	safe, skipped, capped := SelectSafeActions(plan.Actions, policy)
+	result := &SafeFixResult{
+		Planned: len(plan.Actions),
+		Skipped: skipped,
+		Capped:  capped,
+		RunID:   runID,
+		Audit:   make([]AuditEntry, 0, len(safe)),
+	}
+	if len(safe) == 0 {
+		return result, nil
+	}
+
+	opts := ReconcileOptions{DoSync: true, Confirmed: true}
+	result.PlanID = NewPlanID()
+	_, applyErr := ApplyPlan(ctx, spec, db, client, bucket, &ReconcilePlan{ID: result.PlanID, Actions: safe}, opts)
+
+	// Batch syncs do not report per-key outcomes, so a failed apply marks the whole batch
+	now := time.Now()
+	for _, action := range safe {
+		entry := AuditEntry{
+			Time:    now,
+			Adapter: spec.Adapter.Name(),
+			Trigger: SafeFixTrigger,
+			Action:  action.Type,
+			Key:     action.Key,
+			Fields:  action.Fields,
+			Reason:  action.Reason,
+			Outcome: AuditApplied,
+			PlanID:  result.PlanID,
+			RunID:   runID,
+		}
+		if applyErr != nil {
+			entry.Outcome = AuditFailed
+			entry.Error = applyErr.Error()
+		}
+		result.Audit = append(result.Audit, entry)
+	}
+
+	if err := RecordAudit(ctx, result.Audit); err != nil {
+		return result, err
+	}
+	return result, applyErr
+}
@@ -0,0 +1,105 @@
+package reconcile
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"asset-manager/core/storage/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryAuditStore collects audit entries in memory.
+type memoryAuditStore struct {
+	entries []AuditEntry
+}
+
+func (s *memoryAuditStore) RecordAudit(ctx context.Context, entries []AuditEntry) error {
+	s.entries = append(s.entries, entries...)
+	return nil
+}
+
+// TestSelectSafeActions tests the whitelist and the per-run cap.
+func TestSelectSafeActions(t *testing.T) {
+	policy := SafeFixPolicy{SyncFields: []string{"width", "length"}, MaxActions: 2}
+
+	actions := []Action{
+		{Type: ActionDeleteDB, Key: "1"},
+		{Type: ActionSyncDB, Key: "2", Fields: []string{"width"}},
+		{Type: ActionSyncDB, Key: "3", Fields: []string{"width", "name"}},
+		{Type: ActionSyncDB, Key: "4"},
+		{Type: ActionSyncDB, Key: "5", Fields: []string{"length", "width"}},
+		{Type: ActionSyncDB, Key: "6", Fields: []string{"length"}},
+	}
+
+	safe, skipped, capped := SelectSafeActions(actions, policy)
+
+	keys := make([]string, 0, len(safe))
+	for _, a := range safe {
+		keys = append(keys, a.Key)
+	}
+	assert.Equal(t, []string{"2", "5"}, keys)
+	assert.Equal(t, 3, skipped)
+	assert.Equal(t, 1, capped)
+}
+
+// TestSelectSafeActions_ZeroCap tests that a zero cap applies nothing.
+func TestSelectSafeActions_ZeroCap(t *testing.T) {
+	safe, skipped, capped := SelectSafeActions(
+		[]Action{{Type: ActionSyncDB, Key: "1", Fields: []string{"width"}}},
+		SafeFixPolicy{SyncFields: []string{"width"}},
+	)
+
+	assert.Empty(t, safe)
+	assert.Equal(t, 0, skipped)
+	assert.Equal(t, 1, capped)
+}
+
+// TestApplySafeFixes tests that only whitelisted syncs run and each is audited.
+func TestApplySafeFixes(t *testing.T) {
+	store := &memoryAuditStore{}
+	SetAuditStore(store)
+	defer SetAuditStore(nil)
+
+	mutator := &mockMutator{mockAdapter: mockAdapter{
+		dbIndex:    map[string]DBItem{"1": "1", "2": "2", "3": "3"},
+		gdIndex:    map[string]GDItem{"1": "1", "2": "2", "3": "3", "4": "4"},
+		storageSet: map[string]struct{}{"1": {}, "2": {}, "3": {}, "4": {}},
+		mismatches: map[string][]string{
+			"1": {"width: gd=2 db=1"},
+			"2": {"name: gd='a' db='b'"},
+			"3": {"width: gd=2 db=1", "length: gd=3 db=1"},
+		},
+	}}
+	spec := &Spec{Adapter: mutator}
+
+	mockClient := new(mocks.Client)
+	mockClient.On("BucketExists", mock.Anything, "").Return(true, nil)
+
+	policy := SafeFixPolicy{SyncFields: []string{"width", "length"}, MaxActions: 10}
+	result, err := ApplySafeFixes(context.Background(), spec, nil, mockClient, "", policy)
+	require.NoError(t, err)
+
+	// Key 4 is missing from the DB, which is never fixed unattended
+	assert.Equal(t, 3, result.Planned)
+	assert.Equal(t, 1, result.Skipped)
+	assert.Equal(t, 0, result.Capped)
+
+	sort.Strings(mutator.synced)
+	assert.Equal(t, []string{"1", "3"}, mutator.synced)
+	assert.Empty(t, mutator.deletedDB)
+	assert.Empty(t, mutator.deletedGamedata)
+	assert.Empty(t, mutator.deletedStorage)
+
+	require.Len(t, store.entries, 2)
+	for _, e := range store.entries {
+		assert.Equal(t, "mock", e.Adapter)
+		assert.Equal(t, SafeFixTrigger, e.Trigger)
+		assert.Equal(t, ActionSyncDB, e.Action)
+		assert.Equal(t, AuditApplied, e.Outcome)
+	}
+	assert.Equal(t, result.Audit, store.entries)
+}
@@ -0,0 +1,167 @@
+package reconcile
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"asset-manager/core/storage"
+
+	"gorm.io/gorm"
+)
+
+// Scores of the ways a search query can match a key or search term. A term that
+// matches in several ways takes the highest score.
+const (
+	// ScoreExact is the score of a term equal to the query, ignoring case.
+	ScoreExact = 100
+
+	// ScorePrefix is the score of a term starting with the query.
+	ScorePrefix = 80
+
+	// ScoreContains is the score of a term containing the query.
+	ScoreContains = 60
+
+	// ScoreFuzzy is the score of a term, or its start, within a few typos of the
+	// query; each typo costs one point.
+	ScoreFuzzy = 40
+)
+
+// SearchMatch is an entity matching a search query, with its reconcile result.
+type SearchMatch struct {
+	ReconcileResult
+
+	// Score ranks the match; higher is better (see ScoreExact and the other scores).
+	Score int `json:"score"`
+
+	// Matched is the key or search term the query matched best.
+	Matched string `json:"matched"`
+}
+
+// Search returns the entities whose key or search terms (see Searchable) match query,
+// best first and at most limit of them. Matching ignores case and accepts exact,
+// prefix, substring and near-miss (typo) matches. It runs over the cached indices
+// of spec, building them when they are missing or expired.
+func Search(ctx context.Context, spec *Spec, db *gorm.DB, client storage.Client, bucket, query string, limit int) ([]SearchMatch, error) {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" || limit <= 0 {
+		return []SearchMatch{}, nil
+	}
+
+	cache, err := GetOrBuildCache(ctx, spec, db, client, bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []SearchMatch
+	for _, key := range buildUnion(cache) {
+		dbItem, gdItem := cache.DBIndex[key], cache.GDIndex[key]
+
+		best := SearchMatch{Score: matchScore(query, key), Matched: key}
+		for _, term := range searchTerms(spec.Adapter, dbItem, gdItem) {
+			if score := matchScore(query, term); score > best.Score {
+				best.Score, best.Matched = score, term
+			}
+		}
+		if best.Score > 0 {
+			best.ID = key
+			matches = append(matches, best)
+		}
+	}
+
+	slices.SortFunc(matches, func(a, b SearchMatch) int {
+		if a.Score != b.Score {
+			return b.Score - a.Score
+		}
+		// Among equal matches the shorter term is the closer one
+		if n := len(a.Matched) - len(b.Matched); n != 0 {
+			return n
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	// Only the returned matches need a full result
+	for i := range matches {
+		matches[i].ReconcileResult = buildResult(matches[i].ID, cache, spec.Adapter)
+	}
+	return matches, nil
+}
+
+// searchTerms returns the texts an entity can be found by, besides its key.
+func searchTerms(adapter Adapter, dbItem DBItem, gdItem GDItem) []string {
+	if dbItem == nil && gdItem == nil {
+		return nil
+	}
+	if searchable, ok := adapter.(Searchable); ok {
+		return searchable.SearchTerms(dbItem, gdItem)
+	}
+
+	terms := []string{adapter.ResolveName(dbItem, gdItem)}
+	for _, value := range adapter.GetMetadata(dbItem, gdItem) {
+		terms = append(terms, value)
+	}
+	return terms
+}
+
+// matchScore returns how well term matches query, which must be lower case, or 0
+// when it does not match.
+func matchScore(query, term string) int {
+	if term == "" {
+		return 0
+	}
+	term = strings.ToLower(term)
+	switch {
+	case term == query:
+		return ScoreExact
+	case strings.HasPrefix(term, query):
+		return ScorePrefix
+	case strings.Contains(term, query):
+		return ScoreContains
+	}
+
+	// Short queries match too much by accident to allow typos
+	if len(query) < 3 {
+		return 0
+	}
+	maxTypos := max(1, len(query)/4)
+	typos := editDistance(query, term)
+	if len(term) > len(query) {
+		// The query may be the start of the term, typed with a typo
+		typos = min(typos, editDistance(query, term[:len(query)]))
+	}
+	if typos > maxTypos {
+		return 0
+	}
+	return ScoreFuzzy - typos
+}
+
+// editDistance returns the optimal string alignment distance between a and b: the
+// number of inserted, deleted, substituted or swapped adjacent bytes turning a into b.
+func editDistance(a, b string) int {
+	// Three rows suffice: the current one, the previous one and the one before it
+	prev2 := make([]int, len(b)+1)
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				cur[j] = min(cur[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,108 @@
+package reconcile
+
+import (
+	"context"
+	"testing"
+
+	"asset-manager/core/storage/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// searchableAdapter is a mock adapter searched on its own terms.
+type searchableAdapter struct {
+	*mockAdapter
+	terms map[string][]string
+}
+
+func (a *searchableAdapter) SearchTerms(dbItem DBItem, gdItem GDItem) []string {
+	if dbItem != nil {
+		return a.terms[dbItem.(string)]
+	}
+	return a.terms[gdItem.(string)]
+}
+
+// TestMatchScore tests the ranking of exact, prefix, substring and near-miss matches.
+func TestMatchScore(t *testing.T) {
+	assert.Equal(t, ScoreExact, matchScore("throne", "Throne"))
+	assert.Equal(t, ScorePrefix, matchScore("thr", "throne_gold"))
+	assert.Equal(t, ScoreContains, matchScore("rone", "throne"))
+	assert.Equal(t, ScoreFuzzy-1, matchScore("thorne", "throne"))
+	assert.Equal(t, ScoreFuzzy-1, matchScore("thron", "thorne_gold"))
+	assert.Equal(t, 0, matchScore("sofa", "throne"))
+	assert.Equal(t, 0, matchScore("xy", "yx"))
+	assert.Equal(t, 0, matchScore("thr", ""))
+}
+
+// TestEditDistance tests edits and adjacent swaps.
+func TestEditDistance(t *testing.T) {
+	assert.Equal(t, 0, editDistance("chair", "chair"))
+	assert.Equal(t, 1, editDistance("chair", "chiar"))
+	assert.Equal(t, 1, editDistance("chair", "chairs"))
+	assert.Equal(t, 2, editDistance("chair", "cahir_"))
+	assert.Equal(t, 5, editDistance("", "chair"))
+}
+
+// TestSearch tests that matches come from keys, names and search terms, best first.
+func TestSearch(t *testing.T) {
+	mockClient := new(mocks.Client)
+	mockClient.On("BucketExists", mock.Anything, "").Return(true, nil)
+	names := func(dbItem DBItem, gdItem GDItem) string {
+		if dbItem != nil {
+			return dbItem.(string)
+		}
+		return gdItem.(string)
+	}
+
+	adapter := &mockAdapter{
+		dbIndex:      map[string]DBItem{"1": "throne", "2": "chair", "3": "throne_gold"},
+		gdIndex:      map[string]GDItem{"2": "chair", "4": "thorne_blue"},
+		storageSet:   map[string]struct{}{"1": {}, "13": {}},
+		mismatches:   map[string][]string{},
+		nameResolver: names,
+	}
+	spec := &Spec{Adapter: adapter}
+
+	matches, err := Search(context.Background(), spec, nil, mockClient, "", "THR", 10)
+	require.NoError(t, err)
+	require.Len(t, matches, 3)
+	assert.Equal(t, "1", matches[0].ID)
+	assert.Equal(t, ScorePrefix, matches[0].Score)
+	assert.Equal(t, "throne", matches[0].Matched)
+	assert.Equal(t, "3", matches[1].ID)
+	assert.Equal(t, "4", matches[2].ID)
+	assert.Equal(t, ScoreFuzzy-1, matches[2].Score)
+
+	// Matches carry their reconcile status
+	assert.True(t, matches[0].DBPresent)
+	assert.True(t, matches[0].StoragePresent)
+	assert.False(t, matches[0].GamedataPresent)
+	assert.Equal(t, "throne", matches[0].Name)
+
+	// Keys match too, including storage-only ones
+	matches, err = Search(context.Background(), spec, nil, mockClient, "", "1", 10)
+	require.NoError(t, err)
+	require.Len(t, matches, 2)
+	assert.Equal(t, "1", matches[0].ID)
+	assert.Equal(t, ScoreExact, matches[0].Score)
+	assert.Equal(t, "13", matches[1].ID)
+	assert.True(t, matches[1].StoragePresent)
+
+	matches, err = Search(context.Background(), spec, nil, mockClient, "", "thr", 1)
+	require.NoError(t, err)
+	assert.Len(t, matches, 1)
+
+	matches, err = Search(context.Background(), spec, nil, mockClient, "", "  ", 10)
+	require.NoError(t, err)
+	assert.Empty(t, matches)
+
+	// Searchable adapters choose the terms
+	searchable := &searchableAdapter{mockAdapter: adapter, terms: map[string][]string{"chair": {"seat_basic"}}}
+	matches, err = Search(context.Background(), &Spec{Adapter: searchable}, nil, mockClient, "", "seat", 10)
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	assert.Equal(t, "2", matches[0].ID)
+	assert.Equal(t, "seat_basic", matches[0].Matched)
+}
@@ -0,0 +1,94 @@
+package reconcile
+
+import (
+	"fmt"
+	"maps"
+	"sort"
+	"sync"
+	"time"
+)
+
+// AdapterSettings tunes the specs and adapter of one domain without code changes. Zero
+// values keep the defaults the adapter is built with.
+type AdapterSettings struct {
+	// CacheTTL replaces the adapter's default TTL for specs that cache their indices,
+	// such as the server's. Negative disables caching. Full scans and mutations never
+	// cache, whatever the setting.
+	CacheTTL time.Duration `mapstructure:"cache_ttl"`
+	// Concurrency is the number of workers of batch mutations, e.g. storage deletions.
+	Concurrency int `mapstructure:"concurrency"`
+	// StoragePrefix is the storage folder reconciled assets are listed from and
+	// deleted in.
+	StoragePrefix string `mapstructure:"storage_prefix"`
+	// StorageExtension is the file extension of reconciled assets, e.g. ".nitro".
+	StorageExtension string `mapstructure:"storage_extension"`
+	// GamedataObject is the storage key of the adapter's gamedata file.
+	GamedataObject string `mapstructure:"gamedata_object"`
+	// IgnoreFields lists fields, named as in mismatches, that the adapter does not
+	// compare and therefore never syncs. It adds to the ignores of the field policy.
+	IgnoreFields []string `mapstructure:"ignore_fields"`
+}
+
+// Validate reports settings that cannot be applied.
+func (s AdapterSettings) Validate() error {
+	if s.Concurrency < 0 {
+		return fmt.Errorf("concurrency must not be negative, got %d", s.Concurrency)
+	}
+	return nil
+}
+
+// CacheTTLOr returns the TTL a caching spec uses when its adapter defaults to
+// defaultTTL: CacheTTL when set, zero when it is negative.
+func (s AdapterSettings) CacheTTLOr(defaultTTL time.Duration) time.Duration {
+	switch {
+	case s.CacheTTL < 0:
+		return 0
+	case s.CacheTTL > 0:
+		return s.CacheTTL
+	}
+	return defaultTTL
+}
+
+// AdapterConfig maps adapter names (e.g. "furniture") to their settings. It has no
+// environment form and is only read from config.yaml.
+type AdapterConfig map[string]AdapterSettings
+
+// Validate reports the first adapter whose settings cannot be applied.
+func (c AdapterConfig) Validate() error {
+	names := make([]string, 0, len(c))
+	for name := range c {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := c[name].Validate(); err != nil {
+			return fmt.Errorf("adapter %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// adapterSettingsRegistry holds the settings of every configured adapter.
+type adapterSettingsRegistry struct {
+	mu       sync.RWMutex
+	settings AdapterConfig
+}
+
+// globalAdapterSettings is the singleton adapter configuration for all reconcile operations.
+var globalAdapterSettings = &adapterSettingsRegistry{}
+
+// SetAdapterSettings sets the per-adapter settings specs and adapters built afterwards
+// apply. Nil restores the defaults of every adapter.
+func SetAdapterSettings(c AdapterConfig) {
+	globalAdapterSettings.mu.Lock()
+	defer globalAdapterSettings.mu.Unlock()
+	globalAdapterSettings.settings = maps.Clone(c)
+}
+
+// SettingsFor returns the settings of the named adapter, or zero settings when it is
+// not configured.
+func SettingsFor(adapter string) AdapterSettings {
+	globalAdapterSettings.mu.RLock()
+	defer globalAdapterSettings.mu.RUnlock()
+	return globalAdapterSettings.settings[adapter]
+}
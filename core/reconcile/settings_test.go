@@ -0,0 +1,23 @@
+package reconcile
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestAdapterSettings tests the cache TTL resolution, validation and the registry.
+func TestAdapterSettings(t *testing.T) {
+	assert.Equal(t, 5*time.Minute, AdapterSettings{}.CacheTTLOr(5*time.Minute))
+	assert.Equal(t, time.Minute, AdapterSettings{CacheTTL: time.Minute}.CacheTTLOr(5*time.Minute))
+	assert.Zero(t, AdapterSettings{CacheTTL: -1}.CacheTTLOr(5*time.Minute))
+
+	assert.NoError(t, AdapterConfig{"furniture": {Concurrency: 8}}.Validate())
+	assert.ErrorContains(t, AdapterConfig{"badges": {Concurrency: -1}}.Validate(), "adapter badges: concurrency")
+
+	SetAdapterSettings(AdapterConfig{"furniture": {StoragePrefix: "furni"}})
+	defer SetAdapterSettings(nil)
+	assert.Equal(t, "furni", SettingsFor("furniture").StoragePrefix)
+	assert.Zero(t, SettingsFor("badges"))
+}
@@ -0,0 +1,89 @@
+package reconcile
+
+import (
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Severity grades a field mismatch between the DB and gamedata.
+type Severity string
+
+const (
+	// SeverityError marks a mismatch that makes the entity unhealthy. It is the
+	// severity of every mismatch of an adapter that is not a SeverityRater.
+	SeverityError Severity = "error"
+
+	// SeverityWarning marks a mismatch that is reported but not counted as an issue:
+	// it does not affect health, history or run diffs, and a sync only repairs it
+	// when its field is listed in Config.SyncWarnings.
+	SeverityWarning Severity = "warning"
+)
+
+// splitWarnings moves the mismatches an adapter rates at warning severity out of
+// mismatch.
+func splitWarnings(adapter Adapter, mismatch []string) (issues, warnings []string) {
+	rater, ok := adapter.(SeverityRater)
+	if !ok {
+		return mismatch, nil
+	}
+	for _, m := range mismatch {
+		if rater.FieldSeverity(mismatchField(m)) == SeverityWarning {
+			warnings = append(warnings, m)
+		} else {
+			issues = append(issues, m)
+		}
+	}
+	return issues, warnings
+}
+
+// mismatchField returns the field label of a mismatch description.
+func mismatchField(mismatch string) string {
+	field, _, _ := strings.Cut(mismatch, ":")
+	return strings.TrimSpace(field)
+}
+
+// syncMismatches returns the mismatches a sync of the result repairs: every error and
+// the warnings of fields listed by SetSyncedWarnings.
+func syncMismatches(result ReconcileResult) []string {
+	synced := SyncedWarnings()
+	if len(synced) == 0 || len(result.Warnings) == 0 {
+		return result.Mismatch
+	}
+	mismatches := slices.Clone(result.Mismatch)
+	for _, w := range result.Warnings {
+		if slices.Contains(synced, mismatchField(w)) {
+			mismatches = append(mismatches, w)
+		}
+	}
+	return mismatches
+}
+
+// warningRegistry holds the warning fields syncs repair.
+type warningRegistry struct {
+	mu     sync.RWMutex
+	fields []string
+}
+
+// globalWarnings is the singleton synced warning list for all reconcile operations.
+var globalWarnings = &warningRegistry{}
+
+// SetSyncedWarnings sets the fields whose warning-severity mismatches syncs repair,
+// e.g. "description". Warnings of other fields are reported only.
+func SetSyncedWarnings(fields []string) {
+	globalWarnings.mu.Lock()
+	defer globalWarnings.mu.Unlock()
+	globalWarnings.fields = slices.Clone(fields)
+}
+
+// SyncedWarnings returns the fields set by SetSyncedWarnings.
+func SyncedWarnings() []string {
+	globalWarnings.mu.RLock()
+	defer globalWarnings.mu.RUnlock()
+	return globalWarnings.fields
+}
+
+// SyncsWarning reports whether syncs repair warning-severity mismatches of field.
+func SyncsWarning(field string) bool {
+	return slices.Contains(SyncedWarnings(), field)
+}
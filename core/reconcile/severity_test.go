@@ -0,0 +1,78 @@
+package reconcile
+
+import (
+	"context"
+	"testing"
+
+	"asset-manager/core/storage/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// ratedAdapter is a mock adapter that rates description mismatches as warnings.
+type ratedAdapter struct {
+	*mockAdapter
+}
+
+func (a *ratedAdapter) FieldSeverity(field string) Severity {
+	if field == "description" {
+		return SeverityWarning
+	}
+	return SeverityError
+}
+
+// TestReconcileWithPlan_Warnings tests that warning-severity mismatches are reported
+// apart from issues and only planned for sync when their field is enabled.
+func TestReconcileWithPlan_Warnings(t *testing.T) {
+	adapter := &ratedAdapter{&mockAdapter{
+		dbIndex:    map[string]DBItem{"1": "1", "2": "2"},
+		gdIndex:    map[string]GDItem{"1": "1", "2": "2"},
+		storageSet: map[string]struct{}{"1": {}, "2": {}},
+		mismatches: map[string][]string{
+			"1": {"description: gd='New' db='Old'"},
+			"2": {"width: gd=2 db=1", "description: gd='New' db=''"},
+		},
+	}}
+	reconcile := func() *ReconcilePlan {
+		mockClient := new(mocks.Client)
+		mockClient.On("BucketExists", mock.Anything, "").Return(true, nil)
+		plan, err := ReconcileWithPlan(context.Background(), &Spec{Adapter: adapter}, nil, mockClient, "", ReconcileOptions{DoSync: true})
+		require.NoError(t, err)
+		return plan
+	}
+
+	plan := reconcile()
+	require.Len(t, plan.Results, 2)
+	for _, result := range plan.Results {
+		switch result.ID {
+		case "1":
+			assert.Empty(t, result.Mismatch)
+			assert.Equal(t, []string{"description: gd='New' db='Old'"}, result.Warnings)
+		case "2":
+			assert.Equal(t, []string{"width: gd=2 db=1"}, result.Mismatch)
+			assert.Equal(t, []string{"description: gd='New' db=''"}, result.Warnings)
+		}
+	}
+	require.Len(t, plan.Actions, 1)
+	assert.Equal(t, "2", plan.Actions[0].Key)
+	assert.Equal(t, []string{"width"}, plan.Actions[0].Fields)
+
+	SetSyncedWarnings([]string{"description"})
+	defer SetSyncedWarnings(nil)
+
+	plan = reconcile()
+	require.Len(t, plan.Actions, 2)
+	fields := make(map[string][]string)
+	for _, action := range plan.Actions {
+		fields[action.Key] = action.Fields
+	}
+	assert.Equal(t, map[string][]string{"1": {"description"}, "2": {"width", "description"}}, fields)
+	// Warnings stay out of the issue list even when synced
+	for _, result := range plan.Results {
+		if result.ID == "1" {
+			assert.Empty(t, result.Mismatch)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package reconcile
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"asset-manager/core/json"
+)
+
+var (
+	// ErrNoSigningKey is returned when a plan file is signed or checked without a secret.
+	ErrNoSigningKey = errors.New("plan signing requires the API key (SERVER_API_KEY)")
+
+	// ErrPlanUnsigned is returned when a plan file to check carries no signature.
+	ErrPlanUnsigned = errors.New("plan is not signed")
+
+	// ErrPlanTampered is returned when a plan file no longer matches its signature.
+	ErrPlanTampered = errors.New("plan was modified after it was signed")
+)
+
+// signedAction is the part of an action covered by the plan signature.
+type signedAction struct {
+	Type   ActionType `json:"type"`
+	Key    string     `json:"key"`
+	Reason string     `json:"reason"`
+	Fields []string   `json:"fields,omitempty"`
+	From   string     `json:"from,omitempty"`
+}
+
+// signedPlan is the part of a saved plan covered by the signature: its ID and the
+// actions it would apply. Results and summaries are informational and may be rebuilt.
+type signedPlan struct {
+	ID      string         `json:"id"`
+	Actions []signedAction `json:"actions"`
+}
+
+// signedPlanFile is the part of a plan file covered by its signature: everything that
+// decides what an apply does, from the replan options to the data the plan expects.
+type signedPlanFile struct {
+	Version  int             `json:"version"`
+	Adapter  string          `json:"adapter"`
+	Options  PlanFileOptions `json:"options"`
+	DataHash string          `json:"data_hash"`
+	Plan     signedPlan      `json:"plan"`
+}
+
+// Sign sets Signature to an HMAC-SHA256 of the file's version, adapter, options, data
+// hash, plan ID and actions, keyed by secret. NewPlanFile signs every saved plan, and
+// the applying run checks it with CheckSignature before replanning.
+func (f *PlanFile) Sign(secret string) error {
+	signature, err := planFileSignature(f, secret)
+	if err != nil {
+		return err
+	}
+	f.Signature = signature
+	return nil
+}
+
+// CheckSignature verifies that nothing covered by the signature changed since the file
+// was signed. It returns ErrPlanUnsigned for a file without a signature and
+// ErrPlanTampered when the options, data hash, plan ID or actions were edited.
+func (f *PlanFile) CheckSignature(secret string) error {
+	if f.Signature == "" {
+		return ErrPlanUnsigned
+	}
+	expected, err := planFileSignature(f, secret)
+	if err != nil {
+		return err
+	}
+	if !hmac.Equal([]byte(expected), []byte(f.Signature)) {
+		return ErrPlanTampered
+	}
+	return nil
+}
+
+// planFileSignature returns the hex HMAC-SHA256 of the signed part of f.
+func planFileSignature(f *PlanFile, secret string) (string, error) {
+	if secret == "" {
+		return "", ErrNoSigningKey
+	}
+
+	payload := signedPlanFile{
+		Version:  f.Version,
+		Adapter:  f.Adapter,
+		Options:  f.Options,
+		DataHash: f.DataHash,
+	}
+	if f.Plan != nil {
+		payload.Plan.ID = f.Plan.ID
+		payload.Plan.Actions = make([]signedAction, 0, len(f.Plan.Actions))
+		for _, a := range f.Plan.Actions {
+			payload.Plan.Actions = append(payload.Plan.Actions, signedAction{Type: a.Type, Key: a.Key, Reason: a.Reason, Fields: a.Fields, From: a.From})
+		}
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode plan for signing: %w", err)
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(data)
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
@@ -0,0 +1,62 @@
+package reconcile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPlanFileSignature tests signing, checking and detecting modified plan files.
+func TestPlanFileSignature(t *testing.T) {
+	newFile := func() *PlanFile {
+		return &PlanFile{
+			Version:  PlanFileVersion,
+			Adapter:  "furniture",
+			Options:  PlanFileOptions{DoPurge: true},
+			DataHash: "hash",
+			Plan: &ReconcilePlan{ID: "plan-id", Actions: []Action{
+				{Type: ActionDeleteStorage, Key: "1", Reason: "missing in db"},
+				{Type: ActionSyncDB, Key: "2", Reason: "mismatch", Fields: []string{"width"}},
+			}},
+		}
+	}
+
+	f := newFile()
+	require.NoError(t, f.Sign("secret"))
+	assert.Len(t, f.Signature, 64)
+	assert.NoError(t, f.CheckSignature("secret"))
+
+	// Results and summaries are not covered
+	f.Plan.Results = []ReconcileResult{{ID: "1"}}
+	f.Plan.Summary.TotalItems = 3
+	assert.NoError(t, f.CheckSignature("secret"))
+
+	assert.ErrorIs(t, f.CheckSignature("other"), ErrPlanTampered)
+
+	f.Plan.Actions[0].Key = "3"
+	assert.ErrorIs(t, f.CheckSignature("secret"), ErrPlanTampered)
+	f.Plan.Actions[0].Key = "1"
+
+	f.Plan.Actions = append(f.Plan.Actions, Action{Type: ActionDeleteDB, Key: "4"})
+	assert.ErrorIs(t, f.CheckSignature("secret"), ErrPlanTampered)
+	f.Plan.Actions = f.Plan.Actions[:2]
+
+	f.Plan.ID = "other-id"
+	assert.ErrorIs(t, f.CheckSignature("secret"), ErrPlanTampered)
+	f.Plan.ID = "plan-id"
+
+	f.DataHash = "other"
+	assert.ErrorIs(t, f.CheckSignature("secret"), ErrPlanTampered)
+	f.DataHash = "hash"
+
+	f.Options.DoSync = true
+	assert.ErrorIs(t, f.CheckSignature("secret"), ErrPlanTampered)
+	f.Options.DoSync = false
+
+	assert.NoError(t, f.CheckSignature("secret"))
+
+	unsigned := newFile()
+	assert.ErrorIs(t, unsigned.CheckSignature("secret"), ErrPlanUnsigned)
+	assert.ErrorIs(t, unsigned.Sign(""), ErrNoSigningKey)
+}
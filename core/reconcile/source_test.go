@@ -0,0 +1,101 @@
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"asset-manager/core/storage"
+	"asset-manager/core/storage/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// mockSource is an additional source backed by a fixed index.
+type mockSource struct {
+	name  string
+	index map[string]any
+	err   error
+}
+
+func (m *mockSource) Name() string {
+	return m.name
+}
+
+func (m *mockSource) LoadIndex(ctx context.Context, db *gorm.DB, client storage.Client, bucket string) (map[string]any, error) {
+	return m.index, m.err
+}
+
+// newSourceSpec builds a spec with one fully healthy key "A" (missing only in the
+// catalog source) and a catalog-only key "E".
+func newSourceSpec(catalog *mockSource) *Spec {
+	adapter := &mockAdapter{
+		dbIndex:    map[string]DBItem{"A": "A", "B": "B"},
+		gdIndex:    map[string]GDItem{"A": "A", "B": "B"},
+		storageSet: map[string]struct{}{"A": {}, "B": {}},
+		mismatches: map[string][]string{},
+	}
+	return &Spec{Adapter: adapter, Sources: []Source{catalog}}
+}
+
+// TestReconcileAll_AdditionalSources tests that additional sources join the union and presence model.
+func TestReconcileAll_AdditionalSources(t *testing.T) {
+	catalog := &mockSource{name: "catalog", index: map[string]any{"B": "B", "E": "E"}}
+	spec := newSourceSpec(catalog)
+
+	mockClient := new(mocks.Client)
+	mockClient.On("BucketExists", mock.Anything, "").Return(true, nil)
+
+	results, err := ReconcileAll(context.Background(), spec, nil, mockClient, "")
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+
+	byKey := make(map[string]ReconcileResult)
+	for _, r := range results {
+		byKey[r.ID] = r
+	}
+
+	assert.Equal(t, []string{"catalog"}, byKey["A"].MissingSources())
+	assert.Empty(t, byKey["B"].MissingSources())
+	assert.True(t, isHealthy(byKey["B"]))
+	assert.False(t, isHealthy(byKey["A"]))
+
+	assert.True(t, byKey["E"].Present("catalog"))
+	assert.False(t, byKey["E"].DBPresent)
+	assert.Equal(t, []string{SourceDB, SourceGamedata, SourceStorage}, byKey["E"].MissingSources())
+
+	summary := Summarize(results)
+	assert.Equal(t, map[string]int{"catalog": 1}, summary.MissingSources)
+	// Default counts keep their semantics: E is not in gamedata or storage, so not "missing DB"
+	assert.Equal(t, 0, summary.MissingDB)
+}
+
+// TestReconcileAll_SourceError tests that a failing additional source fails the run.
+func TestReconcileAll_SourceError(t *testing.T) {
+	spec := newSourceSpec(&mockSource{name: "catalog", err: fmt.Errorf("catalog down")})
+
+	mockClient := new(mocks.Client)
+	mockClient.On("BucketExists", mock.Anything, "").Return(true, nil)
+
+	_, err := ReconcileAll(context.Background(), spec, nil, mockClient, "")
+	assert.ErrorContains(t, err, "failed to load source catalog: catalog down")
+}
+
+// TestSpec_CacheKeyIncludesSources tests that specs with different sources do not share a cache.
+func TestSpec_CacheKeyIncludesSources(t *testing.T) {
+	withSource := newSourceSpec(&mockSource{name: "catalog"})
+	without := &Spec{Adapter: withSource.Adapter}
+	assert.NotEqual(t, withSource.CacheKey(), without.CacheKey())
+}
+
+// TestReconcileResult_PresentFallback tests presence lookups on results built without a Sources map.
+func TestReconcileResult_PresentFallback(t *testing.T) {
+	result := ReconcileResult{DBPresent: true, GamedataPresent: true}
+	assert.True(t, result.Present(SourceDB))
+	assert.False(t, result.Present(SourceStorage))
+	assert.False(t, result.Present("catalog"))
+	assert.Equal(t, []string{SourceStorage}, result.MissingSources())
+}
@@ -0,0 +1,162 @@
+package reconcile
+
+import (
+	"bytes"
+	"context"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"asset-manager/core/json"
+	"asset-manager/core/storage"
+
+	"github.com/minio/minio-go/v7"
+	"go.uber.org/zap"
+)
+
+// StatusVersion is the format version of the status object.
+const StatusVersion = 1
+
+// Status is the health snapshot published to storage after every reconciliation, so
+// deploy pipelines can gate releases on hotel integrity by reading one small object
+// instead of calling the API.
+type Status struct {
+	// Version is the format version (StatusVersion).
+	Version int `json:"version"`
+
+	// Manager is the version of the asset manager that wrote the status, when known.
+	Manager string `json:"manager,omitempty"`
+
+	// UpdatedAt is when the status was last written.
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Adapters holds the last run of each adapter, by adapter name.
+	Adapters map[string]AdapterStatus `json:"adapters"`
+}
+
+// AdapterStatus summarizes the last run of one adapter.
+type AdapterStatus struct {
+	// RunAt is when the run finished.
+	RunAt time.Time `json:"run_at"`
+
+	// RunID identifies the run (see WithRunID).
+	RunID string `json:"run_id,omitempty"`
+
+	// Healthy is true when no entity has issues and no key conflicts or duplicate
+	// names were found.
+	Healthy bool `json:"healthy"`
+
+	// Issues counts the entities with issues (see ResultIssues).
+	Issues int `json:"issues"`
+
+	TotalItems      int `json:"total_items"`
+	MissingGamedata int `json:"missing_gamedata"`
+	MissingStorage  int `json:"missing_storage"`
+	MissingDB       int `json:"missing_db"`
+	Mismatches      int `json:"mismatches"`
+	KeyConflicts    int `json:"key_conflicts"`
+	DuplicateNames  int `json:"duplicate_names"`
+}
+
+// statusRegistry holds the key the status object is published to.
+type statusRegistry struct {
+	mu     sync.RWMutex
+	object string
+	// write serializes the read-modify-write of the object within this process.
+	write sync.Mutex
+}
+
+// globalStatus is the singleton status registry for all reconcile operations.
+var globalStatus = &statusRegistry{}
+
+// SetStatusObject sets the key in the gamedata bucket each reconciliation publishes
+// its Status to. Empty stops publishing.
+func SetStatusObject(object string) {
+	globalStatus.mu.Lock()
+	defer globalStatus.mu.Unlock()
+	globalStatus.object = object
+}
+
+// statusObject returns the key the status is published to, or "".
+func statusObject() string {
+	globalStatus.mu.RLock()
+	defer globalStatus.mu.RUnlock()
+	return globalStatus.object
+}
+
+// publishStatus merges the run of spec into the status object in the gamedata bucket.
+// Other adapters keep their last run. Failures are logged and never fail the run.
+func publishStatus(ctx context.Context, spec *Spec, client storage.Client, bucket string, results []ReconcileResult, summary PlanSummary) {
+	object := statusObject()
+	if object == "" {
+		return
+	}
+	bucket = spec.gamedataBucket(bucket)
+
+	globalStatus.write.Lock()
+	defer globalStatus.write.Unlock()
+
+	status := &Status{}
+	if data, found, err := storage.ReadObject(ctx, client, bucket, object); err == nil && found {
+		// A corrupt status is replaced rather than kept failing
+		_ = json.Unmarshal(data, status)
+	}
+	if status.Adapters == nil {
+		status.Adapters = make(map[string]AdapterStatus)
+	}
+	now := time.Now().UTC()
+	status.Version = StatusVersion
+	status.Manager = managerVersion()
+	status.UpdatedAt = now
+	run := adapterStatus(now, results, summary)
+	run.RunID = RunIDFrom(ctx)
+	status.Adapters[spec.Adapter.Name()] = run
+
+	data, err := json.MarshalIndent(status, "", "  ")
+	if err == nil {
+		_, err = client.PutObject(ctx, bucket, object, bytes.NewReader(data), int64(len(data)), minio.PutObjectOptions{ContentType: "application/json"})
+	}
+	if err != nil {
+		zap.L().Warn("Failed to publish reconcile status", zap.String("bucket", bucket), zap.String("key", object), zap.Error(err))
+	}
+}
+
+// adapterStatus summarizes a run finished at runAt.
+func adapterStatus(runAt time.Time, results []ReconcileResult, summary PlanSummary) AdapterStatus {
+	issues := 0
+	for _, result := range results {
+		if len(ResultIssues(result)) > 0 {
+			issues++
+		}
+	}
+	return AdapterStatus{
+		RunAt:           runAt,
+		Healthy:         issues == 0 && summary.KeyConflicts == 0 && summary.DuplicateNames == 0,
+		Issues:          issues,
+		TotalItems:      summary.TotalItems,
+		MissingGamedata: summary.MissingGamedata,
+		MissingStorage:  summary.MissingStorage,
+		MissingDB:       summary.MissingDB,
+		Mismatches:      summary.Mismatches,
+		KeyConflicts:    summary.KeyConflicts,
+		DuplicateNames:  summary.DuplicateNames,
+	}
+}
+
+// managerVersion returns the module version of the running binary, or its VCS
+// revision for development builds.
+func managerVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	if v := info.Main.Version; v != "" && v != "(devel)" {
+		return v
+	}
+	for _, setting := range info.Settings {
+		if setting.Key == "vcs.revision" {
+			return setting.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,54 @@
+package reconcile
+
+import (
+	"context"
+	"testing"
+
+	"asset-manager/core/json"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReconcileWithPlan_PublishesStatus tests that every run updates its adapter's
+// entry of the status object in the gamedata bucket and keeps the others.
+func TestReconcileWithPlan_PublishesStatus(t *testing.T) {
+	SetStatusObject("gamedata/.status.json")
+	defer SetStatusObject("")
+
+	client := &memoryObjects{objects: map[string][]byte{
+		"gamedata/gamedata/.status.json": []byte(`{"version":1,"adapters":{"badges":{"healthy":true}}}`),
+	}}
+	client.On("BucketExists", mock.Anything, mock.Anything).Return(true, nil)
+	adapter := &mockAdapter{
+		dbIndex:    map[string]DBItem{"1": "1", "2": "2"},
+		gdIndex:    map[string]GDItem{"1": "1", "2": "2"},
+		storageSet: map[string]struct{}{"1": {}},
+		mismatches: map[string][]string{},
+	}
+	spec := &Spec{Adapter: adapter, GamedataBucket: "gamedata"}
+
+	_, err := ReconcileWithPlan(context.Background(), spec, nil, client, "assets", ReconcileOptions{})
+	require.NoError(t, err)
+
+	var status Status
+	require.NoError(t, json.Unmarshal(client.objects["gamedata/gamedata/.status.json"], &status))
+	assert.Equal(t, StatusVersion, status.Version)
+	assert.False(t, status.UpdatedAt.IsZero())
+	assert.True(t, status.Adapters["badges"].Healthy)
+
+	run := status.Adapters["mock"]
+	assert.False(t, run.Healthy)
+	assert.Equal(t, 1, run.Issues)
+	assert.Equal(t, 2, run.TotalItems)
+	assert.Equal(t, 1, run.MissingStorage)
+	assert.Equal(t, status.UpdatedAt, run.RunAt)
+
+	// Without an object nothing is published
+	SetStatusObject("")
+	delete(client.objects, "gamedata/gamedata/.status.json")
+	_, err = ReconcileWithPlan(context.Background(), spec, nil, client, "assets", ReconcileOptions{})
+	require.NoError(t, err)
+	assert.NotContains(t, client.objects, "gamedata/gamedata/.status.json")
+}
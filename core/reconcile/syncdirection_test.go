@@ -0,0 +1,90 @@
+package reconcile
+
+import (
+	"context"
+	"testing"
+
+	"asset-manager/core/storage/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// mockGamedataSyncer implements GamedataSyncer, recording the DB item of each key.
+type mockGamedataSyncer struct {
+	mockMutator
+	gamedataSynced map[string]DBItem
+}
+
+func (m *mockGamedataSyncer) SyncGamedataFromDB(ctx context.Context, key string, dbItem DBItem, gdItem GDItem) error {
+	m.gamedataSynced[key] = dbItem
+	return nil
+}
+
+// TestParseSyncDirection tests direction names and the gamedata-to-db default.
+func TestParseSyncDirection(t *testing.T) {
+	direction, err := ParseSyncDirection("")
+	assert.NoError(t, err)
+	assert.Equal(t, SyncGamedataToDB, direction)
+
+	direction, err = ParseSyncDirection("db-to-gamedata")
+	assert.NoError(t, err)
+	assert.Equal(t, SyncDBToGamedata, direction)
+
+	_, err = ParseSyncDirection("both")
+	assert.ErrorContains(t, err, "valid: gamedata-to-db, db-to-gamedata")
+}
+
+// TestReconcileWithPlan_SyncDirection tests that reverse syncs plan gamedata syncs
+// carrying the DB item, for gamedata syncers only.
+func TestReconcileWithPlan_SyncDirection(t *testing.T) {
+	mockClient := new(mocks.Client)
+	mockClient.On("BucketExists", mock.Anything, "").Return(true, nil)
+
+	syncer := &mockGamedataSyncer{gamedataSynced: map[string]DBItem{}}
+	syncer.dbIndex = map[string]DBItem{"1": "1", "2": "2"}
+	syncer.gdIndex = map[string]GDItem{"1": "1", "2": "2"}
+	syncer.storageSet = map[string]struct{}{"1": {}, "2": {}}
+	syncer.mismatches = map[string][]string{"2": {"width: gd=2 db=1"}}
+
+	plan, err := ReconcileWithPlan(context.Background(), &Spec{Adapter: syncer}, nil, mockClient, "", ReconcileOptions{DoSync: true})
+	require.NoError(t, err)
+	require.Len(t, plan.Actions, 1)
+	assert.Equal(t, ActionSyncDB, plan.Actions[0].Type)
+
+	opts := ReconcileOptions{DoSync: true, SyncDirection: SyncDBToGamedata}
+	plan, err = ReconcileWithPlan(context.Background(), &Spec{Adapter: syncer}, nil, mockClient, "", opts)
+	require.NoError(t, err)
+	require.Len(t, plan.Actions, 1)
+	assert.Equal(t, ActionSyncGamedata, plan.Actions[0].Type)
+	assert.Equal(t, DBItem("2"), plan.Actions[0].DBItem)
+	assert.Equal(t, GDItem("2"), plan.Actions[0].GDItem)
+	assert.Equal(t, 1, plan.Summary.SyncActions)
+
+	// Adapters that cannot write gamedata plan no syncs in reverse
+	adapter := &mockMutator{}
+	adapter.dbIndex, adapter.gdIndex, adapter.storageSet, adapter.mismatches = syncer.dbIndex, syncer.gdIndex, syncer.storageSet, syncer.mismatches
+	plan, err = ReconcileWithPlan(context.Background(), &Spec{Adapter: adapter}, nil, mockClient, "", opts)
+	require.NoError(t, err)
+	assert.Empty(t, plan.Actions)
+}
+
+// TestApplyPlan_SyncGamedata tests that gamedata syncs reach the syncer and that other
+// adapters refuse them.
+func TestApplyPlan_SyncGamedata(t *testing.T) {
+	opts := ReconcileOptions{DoSync: true, SyncDirection: SyncDBToGamedata, Confirmed: true}
+	plan := &ReconcilePlan{Actions: []Action{{Type: ActionSyncGamedata, Key: "1", DBItem: "db1", GDItem: "gd1"}}}
+
+	syncer := &mockGamedataSyncer{gamedataSynced: map[string]DBItem{}}
+	executed, err := ApplyPlan(context.Background(), &Spec{Adapter: syncer}, nil, nil, "", plan, opts)
+	require.NoError(t, err)
+	assert.Equal(t, 1, executed)
+	assert.Equal(t, map[string]DBItem{"1": "db1"}, syncer.gamedataSynced)
+	assert.Empty(t, syncer.synced)
+
+	_, err = ApplyPlan(context.Background(), &Spec{Adapter: &mockMutator{}}, nil, nil, "", plan, opts)
+	assert.ErrorContains(t, err, "does not implement GamedataSyncer")
+
+	assert.Contains(t, verifyAction(plan.Actions[0], ReconcileResult{DBPresent: true, GamedataPresent: true, Mismatch: []string{"width: gd=2 db=1"}}), "still mismatched")
+}
@@ -0,0 +1,101 @@
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrNoTriageStore is returned when triage is set without a registered store.
+var ErrNoTriageStore = errors.New("triage requires the state store (STATE_PATH)")
+
+// Triage is the staff workflow state attached to an entity's issues.
+type Triage struct {
+	// Acknowledged is true once someone has looked at the issue.
+	Acknowledged bool `json:"acknowledged"`
+
+	// AssignedTo names who is working on the issue.
+	AssignedTo string `json:"assigned_to,omitempty"`
+
+	// Note is free text, e.g. what is blocking a fix.
+	Note string `json:"note,omitempty"`
+
+	// UpdatedAt is when the triage was last changed.
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// IsZero reports whether t carries no workflow state, which clears a stored triage.
+func (t Triage) IsZero() bool {
+	return !t.Acknowledged && t.AssignedTo == "" && t.Note == ""
+}
+
+// TriageStore persists triage per entity.
+type TriageStore interface {
+	// SaveTriage stores the triage of an entity, replacing any earlier one.
+	// A zero triage (see Triage.IsZero) deletes it.
+	SaveTriage(ctx context.Context, adapter, key string, triage Triage) error
+
+	// LoadTriage returns the triage of every entity of the adapter, by key.
+	LoadTriage(ctx context.Context, adapter string) (map[string]Triage, error)
+}
+
+// triageRegistry holds the process-wide triage store.
+type triageRegistry struct {
+	mu    sync.RWMutex
+	store TriageStore
+}
+
+// globalTriage is the singleton triage registry for all reconcile operations.
+var globalTriage = &triageRegistry{}
+
+// SetTriageStore registers the store holding triage state.
+// Passing nil disables triage.
+func SetTriageStore(store TriageStore) {
+	globalTriage.mu.Lock()
+	defer globalTriage.mu.Unlock()
+	globalTriage.store = store
+}
+
+// SetTriage stores the triage of an entity; a zero triage clears it.
+// It returns ErrNoTriageStore when no store is registered.
+func SetTriage(ctx context.Context, adapter, key string, triage Triage) error {
+	globalTriage.mu.RLock()
+	store := globalTriage.store
+	globalTriage.mu.RUnlock()
+
+	if store == nil {
+		return ErrNoTriageStore
+	}
+	if adapter == "" || key == "" {
+		return fmt.Errorf("triage requires an adapter and a key")
+	}
+	if triage.UpdatedAt.IsZero() {
+		triage.UpdatedAt = time.Now()
+	}
+	return store.SaveTriage(ctx, adapter, key, triage)
+}
+
+// annotateTriage attaches the stored triage to results.
+// It is a no-op when no triage store is registered.
+func annotateTriage(ctx context.Context, adapter string, results []ReconcileResult) error {
+	globalTriage.mu.RLock()
+	store := globalTriage.store
+	globalTriage.mu.RUnlock()
+
+	if store == nil {
+		return nil
+	}
+
+	triage, err := store.LoadTriage(ctx, adapter)
+	if err != nil {
+		return fmt.Errorf("failed to load triage: %w", err)
+	}
+	for i := range results {
+		if t, ok := triage[results[i].ID]; ok {
+			results[i].Triage = &t
+		}
+	}
+	return nil
+}
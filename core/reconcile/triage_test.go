@@ -0,0 +1,52 @@
+package reconcile
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryTriage is an in-memory TriageStore for tests.
+type memoryTriage struct {
+	triage map[string]Triage
+}
+
+func (m *memoryTriage) SaveTriage(ctx context.Context, adapter, key string, triage Triage) error {
+	if triage.IsZero() {
+		delete(m.triage, key)
+		return nil
+	}
+	m.triage[key] = triage
+	return nil
+}
+
+func (m *memoryTriage) LoadTriage(ctx context.Context, adapter string) (map[string]Triage, error) {
+	return m.triage, nil
+}
+
+// TestSetTriage_NoStore tests that triage needs a registered store.
+func TestSetTriage_NoStore(t *testing.T) {
+	SetTriageStore(nil)
+	err := SetTriage(context.Background(), "furniture", "1", Triage{Acknowledged: true})
+	assert.ErrorIs(t, err, ErrNoTriageStore)
+}
+
+// TestAnnotateTriage tests that stored triage is attached to matching results only.
+func TestAnnotateTriage(t *testing.T) {
+	SetTriageStore(&memoryTriage{triage: map[string]Triage{}})
+	defer SetTriageStore(nil)
+
+	ctx := context.Background()
+	assert.NoError(t, SetTriage(ctx, "furniture", "1", Triage{Acknowledged: true, AssignedTo: "alice"}))
+	assert.NoError(t, SetTriage(ctx, "furniture", "2", Triage{Note: "later"}))
+	assert.NoError(t, SetTriage(ctx, "furniture", "2", Triage{}))
+
+	results := []ReconcileResult{{ID: "1"}, {ID: "2"}}
+	assert.NoError(t, annotateTriage(ctx, "furniture", results))
+	if assert.NotNil(t, results[0].Triage) {
+		assert.Equal(t, "alice", results[0].Triage.AssignedTo)
+		assert.False(t, results[0].Triage.UpdatedAt.IsZero())
+	}
+	assert.Nil(t, results[1].Triage)
+}
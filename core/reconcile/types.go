@@ -1,6 +1,22 @@
 package reconcile
 
-import "time"
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"asset-manager/core/storage"
+)
+
+// Default source names. Every reconcile includes these three; Spec.Sources adds more.
+const (
+	// SourceDB is the emulator database.
+	SourceDB = "db"
+	// SourceGamedata is the gamedata JSON file.
+	SourceGamedata = "gamedata"
+	// SourceStorage is the bundled asset storage.
+	SourceStorage = "storage"
+)
 
 // ReconcileResult represents the reconciliation output for a single entity.
 // It contains presence flags for each source and any detected mismatches.
@@ -20,12 +36,100 @@ type ReconcileResult struct {
 	// GamedataPresent indicates whether the entity exists in gamedata JSON.
 	GamedataPresent bool `json:"gamedata_present"`
 
+	// Misplaced is the storage object found outside the canonical path of the entity
+	// (see RecordMisplaced); it still counts as present in storage.
+	Misplaced string `json:"misplaced,omitempty"`
+
+	// StorageModified is the last modification of the storage object, where the
+	// storage loader reported it (see RecordModified).
+	StorageModified *time.Time `json:"storage_modified,omitempty"`
+
+	// OrphanAge groups storage orphans by StorageModified. Empty for other entities
+	// and orphans of unknown age.
+	OrphanAge OrphanAge `json:"orphan_age,omitempty"`
+
+	// Sources maps every reconciled source name (the default three plus Spec.Sources)
+	// to whether the entity exists there.
+	Sources map[string]bool `json:"sources,omitempty"`
+
 	// Mismatch contains descriptions of field mismatches between DB and gamedata.
 	// Each string describes a specific mismatch, e.g., "sprite_id: gd=0 db=1".
 	Mismatch []string `json:"mismatch"`
 
+	// Warnings contains mismatches of warning severity (see SeverityRater), described
+	// like Mismatch. They are reported but do not count as issues.
+	Warnings []string `json:"warnings,omitempty"`
+
+	// NotComparable lists the fields that could not be compared, e.g. because the DB
+	// lacks their column (see PartialComparer). They are never reported as mismatches.
+	NotComparable []string `json:"not_comparable,omitempty"`
+
 	// Metadata contains model-specific arbitrary data (e.g., classname, category).
 	Metadata map[string]string `json:"metadata"`
+
+	// Transitions counts how often the entity flipped between healthy and broken
+	// across recent runs. Only populated when a history store is registered.
+	Transitions int `json:"transitions,omitempty"`
+
+	// Flapping indicates the entity keeps oscillating between fixed and broken,
+	// which usually means another tool is rewriting one of the sources.
+	Flapping bool `json:"flapping,omitempty"`
+
+	// Pending indicates the entity became inconsistent within the grace period (see
+	// SetGracePeriod). Its issues are reported, but purges leave it alone.
+	Pending bool `json:"pending,omitempty"`
+
+	// ExpectedAbsent lists the sources the entity is missing from as its emulator
+	// expects, e.g. storage for reserved room ad items (see AbsenceExpecter). They are
+	// left out of MissingSources and are not counted as missing.
+	ExpectedAbsent []string `json:"expected_absent,omitempty"`
+
+	// Triage is the staff workflow state of the entity's issues.
+	// Only populated when a triage store is registered and the entity was triaged.
+	Triage *Triage `json:"triage,omitempty"`
+}
+
+// Present reports whether the entity exists in the named source.
+// The default sources fall back to their dedicated flags when Sources is unset.
+func (r ReconcileResult) Present(source string) bool {
+	if present, ok := r.Sources[source]; ok {
+		return present
+	}
+	switch source {
+	case SourceDB:
+		return r.DBPresent
+	case SourceGamedata:
+		return r.GamedataPresent
+	case SourceStorage:
+		return r.StoragePresent
+	}
+	return false
+}
+
+// MissingSources returns the sorted names of sources the entity is missing from,
+// except those it is expected to be absent from.
+func (r ReconcileResult) MissingSources() []string {
+	missing := make([]string, 0)
+	for _, source := range defaultSources {
+		if r.lacks(source) {
+			missing = append(missing, source)
+		}
+	}
+	for source, present := range r.Sources {
+		if !present && !isDefaultSource(source) && !r.ExpectsAbsent(source) {
+			missing = append(missing, source)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// defaultSources lists the sources every reconcile includes.
+var defaultSources = []string{SourceDB, SourceGamedata, SourceStorage}
+
+// isDefaultSource reports whether name is one of the default three sources.
+func isDefaultSource(name string) bool {
+	return name == SourceDB || name == SourceGamedata || name == SourceStorage
 }
 
 // Query represents a search query for targeted reconciliation.
@@ -61,12 +165,21 @@ type Spec struct {
 	// Example: ["roomitemtypes.furnitype", "roomitemtypes.wallitemtypes"]
 	GamedataPaths []string
 
-	// GamedataObjectName is the name of the gamedata JSON object in storage.
+	// GamedataObjectName is the name of the gamedata JSON object in storage, or an
+	// http(s) URL serving it (see IsGamedataURL), which is fetched and cached instead.
 	// Example: "gamedata/FurnitureData.json"
 	GamedataObjectName string
 
+	// GamedataBucket is the bucket holding GamedataObjectName when gamedata is kept
+	// apart from assets. Empty uses the bucket passed to each call.
+	GamedataBucket string
+
 	// ServerProfile is the emulator-specific configuration (e.g., "arcturus", "comet").
 	ServerProfile string
+
+	// Sources lists additional sources reconciled alongside DB, gamedata and storage.
+	// Empty means the default three only.
+	Sources []Source
 }
 
 // CacheKey returns a unique key for caching based on spec parameters.
@@ -77,9 +190,23 @@ func (s *Spec) CacheKey() string {
 	for _, path := range s.GamedataPaths {
 		key += "|" + path
 	}
+	for _, source := range s.Sources {
+		key += "|source:" + source.Name()
+	}
+	if s.GamedataBucket != "" {
+		key += "|gamedata-bucket:" + s.GamedataBucket
+	}
 	return key
 }
 
+// gamedataBucket returns the bucket holding gamedata for a call made with bucket.
+func (s *Spec) gamedataBucket(bucket string) string {
+	if s.GamedataBucket != "" {
+		return s.GamedataBucket
+	}
+	return bucket
+}
+
 // DBItem represents a database entity with arbitrary fields.
 // Adapters define the concrete type and provide a way to extract this.
 type DBItem any
@@ -100,6 +227,25 @@ const (
 	ActionDeleteStorage ActionType = "delete_storage"
 	// ActionSyncDB syncs database fields from gamedata.
 	ActionSyncDB ActionType = "sync_db"
+	// ActionSyncGamedata syncs gamedata fields from the database.
+	// It is only planned with SyncDBToGamedata for adapters implementing GamedataSyncer.
+	ActionSyncGamedata ActionType = "sync_gamedata"
+	// ActionInsertDB inserts a missing database row from gamedata.
+	// It is only suggested for single items and is not executed by ApplyPlan.
+	ActionInsertDB ActionType = "insert_db"
+	// ActionFetchStorage restores a missing storage object.
+	// It is only suggested for single items and is not executed by ApplyPlan.
+	ActionFetchStorage ActionType = "fetch_storage"
+	// ActionMoveStorage moves a misplaced storage object to its canonical path.
+	// It is only planned for adapters implementing StorageMover.
+	ActionMoveStorage ActionType = "move_storage"
+	// ActionDownloadStorage downloads a missing storage object from the upstream.
+	// It is only planned for adapters implementing StorageDownloader.
+	ActionDownloadStorage ActionType = "download_storage"
+	// ActionInsertGamedata adds the gamedata entry of an entity that exists in the
+	// database and storage, built from its DB row. It is only planned for adapters
+	// implementing GamedataInserter.
+	ActionInsertGamedata ActionType = "insert_gamedata"
 )
 
 // Action represents a planned mutation operation.
@@ -113,13 +259,30 @@ type Action struct {
 	// Reason explains why this action is needed.
 	Reason string `json:"reason"`
 
-	// GDItem stores the gamedata source for sync actions.
-	// Only populated for ActionSyncDB.
+	// Fields lists the mismatched field labels a sync action would repair.
+	Fields []string `json:"fields,omitempty"`
+
+	// From is the object a move action relocates. Only populated for ActionMoveStorage.
+	From string `json:"from,omitempty"`
+
+	// GDItem stores the gamedata source for sync actions, and the entry being
+	// rewritten for ActionSyncGamedata.
+	// Only populated for ActionSyncDB and ActionSyncGamedata.
 	GDItem GDItem `json:"-"`
+
+	// DBItem stores the database source for reverse sync and gamedata insert actions.
+	// Only populated for ActionSyncGamedata and ActionInsertGamedata.
+	DBItem DBItem `json:"-"`
 }
 
 // ReconcilePlan contains reconciliation results and planned actions.
 type ReconcilePlan struct {
+	// ID identifies the plan in the audit log. ApplyPlan assigns one when empty.
+	ID string `json:"id,omitempty"`
+
+	// RunID identifies the run that built the plan (see WithRunID).
+	RunID string `json:"run_id,omitempty"`
+
 	// Results contains per-entity reconciliation data.
 	Results []ReconcileResult `json:"results"`
 
@@ -128,6 +291,91 @@ type ReconcilePlan struct {
 
 	// Summary provides aggregate counts.
 	Summary PlanSummary `json:"summary"`
+
+	// Verification reports the post-apply state of affected keys.
+	// Only populated after VerifyPlan runs.
+	Verification *PlanVerification `json:"verification,omitempty"`
+
+	// Failures lists the keys a batch deletion could not remove.
+	// Only populated by ApplyPlan when a batch deleter reports per-key failures.
+	Failures []DeleteFailure `json:"failures,omitempty"`
+
+	// SkippedDownloads lists the downloads ApplyPlan skipped because the upstream
+	// does not hold their object (see ErrUpstreamUnavailable).
+	SkippedDownloads []SkippedDownload `json:"skipped_downloads,omitempty"`
+
+	// Ignored holds the results of entities on the ignore list (see SetIgnoreStore).
+	// They are left out of Results, Actions and the summary counts.
+	Ignored []IgnoredResult `json:"ignored,omitempty"`
+
+	// Versions lists the object versions ApplyPlan deleted or overwrote, when the
+	// bucket has versioning enabled (see UndoPlan).
+	Versions []storage.ObjectVersion `json:"versions,omitempty"`
+
+	// Backup is the manifest of the backup ApplyPlan took before applying the plan,
+	// for adapters implementing Backuper (see RollbackPlan).
+	Backup string `json:"backup,omitempty"`
+
+	// Diagnostics reports problems of the sources found while building the indices,
+	// such as key conflicts. Nil when there were none.
+	Diagnostics *Diagnostics `json:"diagnostics,omitempty"`
+}
+
+// PlanVerification summarizes whether applied actions actually took effect.
+type PlanVerification struct {
+	// Checked is the number of distinct keys re-reconciled.
+	Checked int `json:"checked"`
+
+	// Consistent lists keys whose actions all took effect.
+	Consistent []string `json:"consistent"`
+
+	// StillBroken lists actions whose expected outcome was not observed.
+	StillBroken []VerificationFailure `json:"still_broken"`
+}
+
+// VerificationFailure describes an applied action that did not take effect.
+type VerificationFailure struct {
+	// Key is the entity identifier.
+	Key string `json:"key"`
+
+	// Action is the action type that was applied.
+	Action ActionType `json:"action"`
+
+	// Reason explains which expectation failed.
+	Reason string `json:"reason"`
+}
+
+// SkippedDownload describes a download the upstream could not serve.
+type SkippedDownload struct {
+	// Key is the entity identifier.
+	Key string `json:"key"`
+
+	// Reason is the error of the download.
+	Reason string `json:"reason"`
+}
+
+// DeleteFailure describes a key that a batch deletion could not remove.
+type DeleteFailure struct {
+	// Key is the entity identifier.
+	Key string `json:"key"`
+
+	// Action is the deletion that failed.
+	Action ActionType `json:"action"`
+
+	// Object is the store-specific name of the item (e.g. the storage object key).
+	Object string `json:"object,omitempty"`
+
+	// Code is the store error code (e.g. "SlowDown"), when the store returned one.
+	Code string `json:"code,omitempty"`
+
+	// Message is the error from the last attempt.
+	Message string `json:"message"`
+
+	// Retriable is true when the failure was transient and was retried.
+	Retriable bool `json:"retriable"`
+
+	// Attempts is the number of deletions tried for the key.
+	Attempts int `json:"attempts"`
 }
 
 // PlanSummary provides aggregate statistics for a reconcile plan.
@@ -147,11 +395,108 @@ type PlanSummary struct {
 	// Mismatches counts entities with field discrepancies.
 	Mismatches int `json:"mismatches"`
 
+	// Flapping counts entities that keep oscillating between fixed and broken.
+	Flapping int `json:"flapping"`
+
+	// Pending counts inconsistent entities still within the grace period.
+	Pending int `json:"pending,omitempty"`
+
+	// ExpectedAbsent counts entities missing from a source as their emulator expects
+	// (see AbsenceExpecter). They are not counted as missing there.
+	ExpectedAbsent int `json:"expected_absent,omitempty"`
+
+	// Ignored counts entities left out of the plan by the ignore list.
+	Ignored int `json:"ignored"`
+
+	// KeyConflicts counts keys several entities of one source map to (see Diagnostics).
+	KeyConflicts int `json:"key_conflicts"`
+
+	// DuplicateNames counts names several entities of one source carry under different
+	// keys (see Diagnostics).
+	DuplicateNames int `json:"duplicate_names,omitempty"`
+
+	// MissingSources counts entities missing in each additional source (Spec.Sources).
+	// The default three sources are counted by their dedicated fields.
+	MissingSources map[string]int `json:"missing_sources,omitempty"`
+
+	// Misplaced counts entities whose storage object is outside its canonical path.
+	Misplaced int `json:"misplaced,omitempty"`
+
+	// RecentOrphans counts storage orphans modified less than StaleOrphanAge ago.
+	RecentOrphans int `json:"recent_storage_orphans,omitempty"`
+
+	// StaleOrphans counts storage orphans modified at least StaleOrphanAge ago.
+	StaleOrphans int `json:"stale_storage_orphans,omitempty"`
+
 	// PurgeActions counts planned purge (delete) actions.
 	PurgeActions int `json:"purge_actions"`
 
 	// SyncActions counts planned sync (update) actions.
 	SyncActions int `json:"sync_actions"`
+
+	// MoveActions counts planned moves of misplaced storage objects.
+	MoveActions int `json:"move_actions,omitempty"`
+
+	// DownloadActions counts planned downloads of missing storage objects.
+	DownloadActions int `json:"download_actions,omitempty"`
+
+	// InsertActions counts planned gamedata entries added from DB rows.
+	InsertActions int `json:"insert_actions,omitempty"`
+
+	// Memory reports peak heap and index sizes of the run that built this summary.
+	Memory *MemoryStats `json:"memory,omitempty"`
+}
+
+// PurgePolicy selects which incomplete entities a purge is allowed to delete.
+type PurgePolicy string
+
+const (
+	// PurgeStrict deletes entities missing in any store from every store holding them.
+	PurgeStrict PurgePolicy = "strict"
+	// PurgeStorageOrphans only deletes storage objects with no DB row and no gamedata entry.
+	PurgeStorageOrphans PurgePolicy = "storage-orphans-only"
+	// PurgeDBOrphans only deletes DB rows with no gamedata entry and no storage object.
+	PurgeDBOrphans PurgePolicy = "db-orphans-only"
+	// PurgeGamedataGhosts only deletes gamedata entries with no DB row and no storage object.
+	PurgeGamedataGhosts PurgePolicy = "gamedata-ghosts-only"
+	// PurgeStaleStorageOrphans only deletes storage orphans modified at least
+	// StaleOrphanAge ago, sparing assets uploaded moments before their DB rows.
+	PurgeStaleStorageOrphans PurgePolicy = "stale-storage-orphans-only"
+)
+
+// ParsePurgePolicy validates a policy name. An empty name selects PurgeStrict.
+func ParsePurgePolicy(name string) (PurgePolicy, error) {
+	switch PurgePolicy(name) {
+	case "", PurgeStrict:
+		return PurgeStrict, nil
+	case PurgeStorageOrphans, PurgeStaleStorageOrphans, PurgeDBOrphans, PurgeGamedataGhosts:
+		return PurgePolicy(name), nil
+	default:
+		return "", fmt.Errorf("unknown purge policy %q (valid: strict, storage-orphans-only, stale-storage-orphans-only, db-orphans-only, gamedata-ghosts-only)", name)
+	}
+}
+
+// SyncDirection selects which store a sync repairs from the other.
+type SyncDirection string
+
+const (
+	// SyncGamedataToDB updates DB rows from gamedata. It is the default.
+	SyncGamedataToDB SyncDirection = "gamedata-to-db"
+	// SyncDBToGamedata updates gamedata entries from DB rows, for hotels that treat
+	// the database as the source of truth.
+	SyncDBToGamedata SyncDirection = "db-to-gamedata"
+)
+
+// ParseSyncDirection validates a direction name. An empty name selects SyncGamedataToDB.
+func ParseSyncDirection(name string) (SyncDirection, error) {
+	switch SyncDirection(name) {
+	case "", SyncGamedataToDB:
+		return SyncGamedataToDB, nil
+	case SyncDBToGamedata:
+		return SyncDBToGamedata, nil
+	default:
+		return "", fmt.Errorf("unknown sync direction %q (valid: gamedata-to-db, db-to-gamedata)", name)
+	}
 }
 
 // ReconcileOptions controls reconcile behavior for purge/sync operations.
@@ -162,9 +507,26 @@ type ReconcileOptions struct {
 	// DoPurge enables deletion of entities missing in any store.
 	DoPurge bool
 
+	// PurgePolicy narrows which entities a purge deletes. Empty means PurgeStrict.
+	PurgePolicy PurgePolicy
+
 	// DoSync enables syncing of mismatched fields from gamedata to DB.
 	DoSync bool
 
+	// SyncDirection reverses syncs to update gamedata from the DB when set to
+	// SyncDBToGamedata. Empty means SyncGamedataToDB.
+	SyncDirection SyncDirection
+
+	// DoFixStorage enables downloading storage objects missing for entities known to
+	// gamedata from the registered upstream (see SetUpstream). Such entities are
+	// repaired rather than purged.
+	DoFixStorage bool
+
+	// DoInsertGamedata enables adding gamedata entries for entities that exist in the
+	// database and storage but not in gamedata, built from their DB rows (see
+	// GamedataInserter). Such entities are repaired rather than purged.
+	DoInsertGamedata bool
+
 	// Confirmed indicates user has confirmed destructive actions.
 	// If false, mutations will not execute regardless of DryRun.
 	Confirmed bool
@@ -0,0 +1,76 @@
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"asset-manager/core/storage"
+)
+
+// ErrNothingToUndo is returned when the audit log holds no versions for a plan.
+var ErrNothingToUndo = errors.New("no recorded object versions for plan")
+
+// UndoResult reports what UndoPlan restored.
+type UndoResult struct {
+	// PlanID is the undone plan.
+	PlanID string `json:"plan_id"`
+
+	// Restored lists the versions made current again.
+	Restored []storage.ObjectVersion `json:"restored"`
+
+	// Failures lists the versions that could not be restored.
+	Failures []UndoFailure `json:"failures,omitempty"`
+}
+
+// UndoFailure describes a version UndoPlan could not restore.
+type UndoFailure struct {
+	storage.ObjectVersion
+	// Message is the restore error.
+	Message string `json:"message"`
+}
+
+// PlanVersions collects the versions recorded for planID in entries. When a plan
+// replaced an object more than once, only the first version (the one from before the
+// plan) is kept.
+func PlanVersions(entries []AuditEntry, planID string) []storage.ObjectVersion {
+	seen := make(map[string]bool)
+	var versions []storage.ObjectVersion
+	for _, entry := range entries {
+		if entry.PlanID != planID {
+			continue
+		}
+		for _, v := range entry.Versions {
+			id := v.Bucket + "/" + v.Key
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			versions = append(versions, v)
+		}
+	}
+	return versions
+}
+
+// UndoPlan restores every version to be the current content of its object again.
+// Database changes of the plan are not undone. It keeps going past failures and
+// returns an error when any version could not be restored.
+func UndoPlan(ctx context.Context, client storage.Client, planID string, versions []storage.ObjectVersion) (*UndoResult, error) {
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("%w %s", ErrNothingToUndo, planID)
+	}
+
+	result := &UndoResult{PlanID: planID, Restored: make([]storage.ObjectVersion, 0, len(versions))}
+	for _, v := range versions {
+		if err := storage.RestoreVersion(ctx, client, v); err != nil {
+			result.Failures = append(result.Failures, UndoFailure{ObjectVersion: v, Message: err.Error()})
+			continue
+		}
+		result.Restored = append(result.Restored, v)
+	}
+
+	if len(result.Failures) > 0 {
+		return result, fmt.Errorf("failed to restore %d of %d objects", len(result.Failures), len(versions))
+	}
+	return result, nil
+}
@@ -0,0 +1,127 @@
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"asset-manager/core/storage"
+	"asset-manager/core/storage/mocks"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// versionStatter reports every object at version "v-<key>".
+type versionStatter struct{}
+
+func (versionStatter) StatObject(ctx context.Context, bucketName, objectName string, opts minio.StatObjectOptions) (minio.ObjectInfo, error) {
+	return minio.ObjectInfo{Key: objectName, VersionID: "v-" + objectName}, nil
+}
+
+// storageMutator deletes storage keys through a real client wrapper.
+type storageMutator struct {
+	mockMutator
+	client storage.Client
+}
+
+func (m *storageMutator) DeleteStorage(ctx context.Context, key string) error {
+	return m.client.RemoveObject(ctx, "assets", key+".nitro", minio.RemoveObjectOptions{})
+}
+
+// TestApplyPlan_RecordsVersions tests that replaced versions reach the plan and the audit log.
+func TestApplyPlan_RecordsVersions(t *testing.T) {
+	store := &memoryAuditStore{}
+	SetAuditStore(store)
+	defer SetAuditStore(nil)
+
+	inner := new(mocks.Client)
+	inner.On("RemoveObject", mock.Anything, "assets", mock.Anything, mock.Anything).Return(nil)
+	mutator := &storageMutator{client: storage.WithVersionRecording(inner, versionStatter{})}
+	spec := &Spec{Adapter: mutator}
+
+	plan := &ReconcilePlan{Actions: []Action{
+		{Type: ActionDeleteStorage, Key: "chair"},
+		{Type: ActionDeleteStorage, Key: "table"},
+	}}
+	executed, err := ApplyPlan(context.Background(), spec, nil, nil, "", plan, ReconcileOptions{Confirmed: true})
+	require.NoError(t, err)
+	assert.Equal(t, 2, executed)
+
+	require.NotEmpty(t, plan.ID)
+	want := []storage.ObjectVersion{
+		{Bucket: "assets", Key: "chair.nitro", VersionID: "v-chair.nitro"},
+		{Bucket: "assets", Key: "table.nitro", VersionID: "v-table.nitro"},
+	}
+	assert.Equal(t, want, plan.Versions)
+
+	require.Len(t, store.entries, 1)
+	entry := store.entries[0]
+	assert.Equal(t, ActionApplyPlan, entry.Action)
+	assert.Equal(t, ApplyTrigger, entry.Trigger)
+	assert.Equal(t, plan.ID, entry.PlanID)
+	assert.Equal(t, AuditApplied, entry.Outcome)
+	assert.Equal(t, want, entry.Versions)
+}
+
+// TestApplyPlan_NoVersionsNotAudited tests that plans replacing nothing versioned leave no entry.
+func TestApplyPlan_NoVersionsNotAudited(t *testing.T) {
+	store := &memoryAuditStore{}
+	SetAuditStore(store)
+	defer SetAuditStore(nil)
+
+	spec := &Spec{Adapter: &mockMutator{}}
+	plan := &ReconcilePlan{ID: "fixed", Actions: []Action{{Type: ActionDeleteDB, Key: "1"}}}
+	_, err := ApplyPlan(context.Background(), spec, nil, nil, "", plan, ReconcileOptions{Confirmed: true})
+	require.NoError(t, err)
+
+	assert.Equal(t, "fixed", plan.ID)
+	assert.Empty(t, plan.Versions)
+	assert.Empty(t, store.entries)
+}
+
+// TestPlanVersions tests filtering by plan and keeping the first version of each object.
+func TestPlanVersions(t *testing.T) {
+	entries := []AuditEntry{
+		{PlanID: "a", Versions: []storage.ObjectVersion{
+			{Bucket: "assets", Key: "gamedata/FurnitureData.json", VersionID: "1"},
+			{Bucket: "assets", Key: "chair.nitro", VersionID: "2"},
+		}},
+		{PlanID: "b", Versions: []storage.ObjectVersion{{Bucket: "assets", Key: "other.nitro", VersionID: "3"}}},
+		{PlanID: "a", Versions: []storage.ObjectVersion{{Bucket: "assets", Key: "gamedata/FurnitureData.json", VersionID: "4"}}},
+	}
+
+	assert.Equal(t, []storage.ObjectVersion{
+		{Bucket: "assets", Key: "gamedata/FurnitureData.json", VersionID: "1"},
+		{Bucket: "assets", Key: "chair.nitro", VersionID: "2"},
+	}, PlanVersions(entries, "a"))
+	assert.Empty(t, PlanVersions(entries, "c"))
+}
+
+// TestUndoPlan tests restoring versions and reporting failures.
+func TestUndoPlan(t *testing.T) {
+	client := new(mocks.Client)
+	client.On("GetObject", mock.Anything, "assets", "chair.nitro", minio.GetObjectOptions{VersionID: "1"}).
+		Return(io.NopCloser(strings.NewReader("chair")), nil)
+	client.On("GetObject", mock.Anything, "assets", "gone.nitro", mock.Anything).
+		Return(io.ReadCloser(nil), errors.New("NoSuchVersion"))
+	client.On("PutObject", mock.Anything, "assets", "chair.nitro", mock.Anything, int64(5), mock.Anything).
+		Return(minio.UploadInfo{}, nil)
+
+	versions := []storage.ObjectVersion{
+		{Bucket: "assets", Key: "chair.nitro", VersionID: "1"},
+		{Bucket: "assets", Key: "gone.nitro", VersionID: "2"},
+	}
+	result, err := UndoPlan(context.Background(), client, "plan", versions)
+	require.Error(t, err)
+	assert.Equal(t, versions[:1], result.Restored)
+	require.Len(t, result.Failures, 1)
+	assert.Equal(t, "gone.nitro", result.Failures[0].Key)
+
+	_, err = UndoPlan(context.Background(), client, "plan", nil)
+	assert.ErrorIs(t, err, ErrNothingToUndo)
+}
@@ -0,0 +1,165 @@
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"asset-manager/core/storage"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// ErrUpstreamUnavailable is returned by downloads whose object the upstream lacks or
+// serves unusable. ApplyPlan skips such downloads instead of failing the run.
+var ErrUpstreamUnavailable = errors.New("not available upstream")
+
+// UpstreamConfig configures the asset source objects missing in storage are downloaded
+// from. At most one of URL and Bucket may be set.
+type UpstreamConfig struct {
+	// URL is an http(s) base URL mirroring the storage layout, e.g. a public Nitro
+	// CDN: object bundled/furniture/chair.nitro is fetched from URL/bundled/furniture/chair.nitro.
+	URL string `mapstructure:"url" default:""`
+	// Bucket is another bucket of the same storage holding the objects under the same keys.
+	Bucket string `mapstructure:"bucket" default:""`
+	// Timeout bounds one download from URL.
+	Timeout time.Duration `mapstructure:"timeout" default:"1m"`
+}
+
+// Upstream is an asset source that objects missing in storage are downloaded from.
+type Upstream interface {
+	// Fetch returns the content of object. It returns an error wrapping
+	// ErrUpstreamUnavailable when the upstream does not hold it. client is the
+	// storage client of the run, for upstreams living in the same storage.
+	Fetch(ctx context.Context, client storage.Client, object string) ([]byte, error)
+}
+
+// StorageDownloader extends Mutator for adapters that can restore storage objects from
+// an upstream. With ReconcileOptions.DoFixStorage, entities known to gamedata but
+// missing in storage are planned as ActionDownloadStorage.
+type StorageDownloader interface {
+	// DownloadStorage fetches the storage object of key from upstream and writes it to
+	// its canonical path.
+	DownloadStorage(ctx context.Context, key string, upstream Upstream) error
+}
+
+// NewUpstream returns the upstream configured by cfg, or nil when none is.
+func NewUpstream(cfg UpstreamConfig) (Upstream, error) {
+	switch {
+	case cfg.URL != "" && cfg.Bucket != "":
+		return nil, fmt.Errorf("upstream url and bucket are mutually exclusive")
+	case cfg.Bucket != "":
+		return BucketUpstream{Bucket: cfg.Bucket}, nil
+	case cfg.URL == "":
+		return nil, nil
+	}
+
+	base, err := url.Parse(cfg.URL)
+	if err != nil || (base.Scheme != "http" && base.Scheme != "https") || base.Host == "" {
+		return nil, fmt.Errorf("unsupported upstream url %q", cfg.URL)
+	}
+	return &HTTPUpstream{BaseURL: cfg.URL, Client: &http.Client{Timeout: cfg.Timeout}}, nil
+}
+
+// HTTPUpstream downloads objects over http(s) below a base URL.
+type HTTPUpstream struct {
+	BaseURL string
+	Client  *http.Client
+}
+
+// Fetch implements Upstream. 404 and 410 responses are ErrUpstreamUnavailable.
+func (u *HTTPUpstream) Fetch(ctx context.Context, client storage.Client, object string) ([]byte, error) {
+	target := strings.TrimSuffix(u.BaseURL, "/") + "/" + strings.TrimPrefix(object, "/")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid upstream URL %s: %w", target, err)
+	}
+
+	resp, err := u.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", target, err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound, http.StatusGone:
+		return nil, fmt.Errorf("%s: %w", target, ErrUpstreamUnavailable)
+	default:
+		return nil, fmt.Errorf("failed to download %s: %s", target, resp.Status)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", target, err)
+	}
+	return data, nil
+}
+
+// BucketUpstream copies objects from another bucket of the same storage.
+type BucketUpstream struct {
+	Bucket string
+}
+
+// Fetch implements Upstream. Missing objects are ErrUpstreamUnavailable.
+func (u BucketUpstream) Fetch(ctx context.Context, client storage.Client, object string) ([]byte, error) {
+	reader, err := client.GetObject(ctx, u.Bucket, object, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, u.fetchError(object, err)
+	}
+	defer reader.Close()
+
+	// Minio reports a missing object on the first read
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, u.fetchError(object, err)
+	}
+	return data, nil
+}
+
+// fetchError describes a failed read of object.
+func (u BucketUpstream) fetchError(object string, err error) error {
+	if storage.IsNoSuchKey(err) {
+		return fmt.Errorf("%s/%s: %w", u.Bucket, object, ErrUpstreamUnavailable)
+	}
+	return fmt.Errorf("failed to read %s/%s: %w", u.Bucket, object, err)
+}
+
+// upstreamRegistry holds the upstream downloads use.
+type upstreamRegistry struct {
+	mu       sync.RWMutex
+	upstream Upstream
+}
+
+// globalUpstream is the singleton upstream for all reconcile operations.
+var globalUpstream = &upstreamRegistry{}
+
+// SetUpstream sets the upstream ActionDownloadStorage fetches from. Nil disables
+// downloads, which is the default.
+func SetUpstream(upstream Upstream) {
+	globalUpstream.mu.Lock()
+	defer globalUpstream.mu.Unlock()
+	globalUpstream.upstream = upstream
+}
+
+// RegisteredUpstream returns the upstream set by SetUpstream, if any.
+func RegisteredUpstream() Upstream {
+	globalUpstream.mu.RLock()
+	defer globalUpstream.mu.RUnlock()
+	return globalUpstream.upstream
+}
+
+// planDownload returns the download action of a result known to gamedata but missing
+// in storage, unless it is expected to be absent there.
+func planDownload(result ReconcileResult) (Action, bool) {
+	if !result.lacks(SourceStorage) || !result.GamedataPresent {
+		return Action{}, false
+	}
+	return Action{Type: ActionDownloadStorage, Key: result.ID, Reason: "missing in storage"}, true
+}
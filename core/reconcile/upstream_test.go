@@ -0,0 +1,179 @@
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"asset-manager/core/storage"
+	"asset-manager/core/storage/mocks"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// mockDownloader implements StorageDownloader, failing keys listed in unavailable.
+type mockDownloader struct {
+	mockMutator
+	downloaded  []string
+	unavailable map[string]bool
+}
+
+func (m *mockDownloader) DownloadStorage(ctx context.Context, key string, upstream Upstream) error {
+	if m.unavailable[key] {
+		return errors.Join(errors.New("gone"), ErrUpstreamUnavailable)
+	}
+	m.downloaded = append(m.downloaded, key)
+	return nil
+}
+
+// stubUpstream serves nothing; downloads are faked by mockDownloader.
+type stubUpstream struct{}
+
+func (stubUpstream) Fetch(ctx context.Context, client storage.Client, object string) ([]byte, error) {
+	return nil, ErrUpstreamUnavailable
+}
+
+// TestNewUpstream tests which upstream each configuration yields.
+func TestNewUpstream(t *testing.T) {
+	upstream, err := NewUpstream(UpstreamConfig{})
+	require.NoError(t, err)
+	assert.Nil(t, upstream)
+
+	upstream, err = NewUpstream(UpstreamConfig{Bucket: "mirror"})
+	require.NoError(t, err)
+	assert.Equal(t, BucketUpstream{Bucket: "mirror"}, upstream)
+
+	upstream, err = NewUpstream(UpstreamConfig{URL: "https://cdn.example.com/assets"})
+	require.NoError(t, err)
+	assert.Equal(t, "https://cdn.example.com/assets", upstream.(*HTTPUpstream).BaseURL)
+
+	_, err = NewUpstream(UpstreamConfig{URL: "https://cdn.example.com", Bucket: "mirror"})
+	assert.ErrorContains(t, err, "mutually exclusive")
+	_, err = NewUpstream(UpstreamConfig{URL: "ftp://cdn.example.com"})
+	assert.ErrorContains(t, err, "unsupported upstream url")
+}
+
+// TestHTTPUpstream_Fetch tests that objects are fetched below the base URL and that
+// missing ones are ErrUpstreamUnavailable.
+func TestHTTPUpstream_Fetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/nitro/bundled/furniture/chair.nitro":
+			w.Write([]byte("chair"))
+		case "/nitro/bundled/furniture/broken.nitro":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	upstream := &HTTPUpstream{BaseURL: server.URL + "/nitro/", Client: server.Client()}
+	data, err := upstream.Fetch(context.Background(), nil, "bundled/furniture/chair.nitro")
+	require.NoError(t, err)
+	assert.Equal(t, "chair", string(data))
+
+	_, err = upstream.Fetch(context.Background(), nil, "bundled/furniture/lamp.nitro")
+	assert.ErrorIs(t, err, ErrUpstreamUnavailable)
+
+	_, err = upstream.Fetch(context.Background(), nil, "bundled/furniture/broken.nitro")
+	assert.ErrorContains(t, err, "500")
+	assert.NotErrorIs(t, err, ErrUpstreamUnavailable)
+}
+
+// TestBucketUpstream_Fetch tests that objects are read from the upstream bucket and
+// that missing ones are ErrUpstreamUnavailable.
+func TestBucketUpstream_Fetch(t *testing.T) {
+	mockClient := new(mocks.Client)
+	mockClient.On("GetObject", mock.Anything, "mirror", "bundled/furniture/chair.nitro", mock.Anything).
+		Return(io.NopCloser(strings.NewReader("chair")), nil)
+	mockClient.On("GetObject", mock.Anything, "mirror", "bundled/furniture/lamp.nitro", mock.Anything).
+		Return(io.ReadCloser(nil), minio.ErrorResponse{Code: "NoSuchKey"})
+	mockClient.On("GetObject", mock.Anything, "mirror", "bundled/furniture/table.nitro", mock.Anything).
+		Return(io.ReadCloser(nil), minio.ErrorResponse{Code: "AccessDenied"})
+
+	upstream := BucketUpstream{Bucket: "mirror"}
+	data, err := upstream.Fetch(context.Background(), mockClient, "bundled/furniture/chair.nitro")
+	require.NoError(t, err)
+	assert.Equal(t, "chair", string(data))
+
+	_, err = upstream.Fetch(context.Background(), mockClient, "bundled/furniture/lamp.nitro")
+	assert.ErrorIs(t, err, ErrUpstreamUnavailable)
+
+	_, err = upstream.Fetch(context.Background(), mockClient, "bundled/furniture/table.nitro")
+	assert.ErrorContains(t, err, "failed to read mirror/bundled/furniture/table.nitro")
+	assert.NotErrorIs(t, err, ErrUpstreamUnavailable)
+}
+
+// TestReconcileWithPlan_DownloadActions tests that items known to gamedata but missing
+// in storage are downloaded instead of purged, for downloaders only.
+func TestReconcileWithPlan_DownloadActions(t *testing.T) {
+	mockClient := new(mocks.Client)
+	mockClient.On("BucketExists", mock.Anything, "").Return(true, nil)
+
+	downloader := &mockDownloader{}
+	downloader.dbIndex = map[string]DBItem{"1": "1", "2": "2", "3": "3"}
+	downloader.gdIndex = map[string]GDItem{"1": "1", "2": "2"}
+	downloader.storageSet = map[string]struct{}{"1": {}}
+	opts := ReconcileOptions{DoPurge: true, DoFixStorage: true}
+
+	plan, err := ReconcileWithPlan(context.Background(), &Spec{Adapter: downloader}, nil, mockClient, "", opts)
+	require.NoError(t, err)
+	assert.Equal(t, 1, plan.Summary.DownloadActions)
+	assert.Contains(t, plan.Actions, Action{Type: ActionDownloadStorage, Key: "2", Reason: "missing in storage"})
+	for _, action := range plan.Actions {
+		if action.Key == "2" {
+			assert.Equal(t, ActionDownloadStorage, action.Type, "item 2 is repaired, not purged")
+		}
+	}
+
+	// Adapters that cannot download keep purging
+	adapter := &mockMutator{}
+	adapter.dbIndex, adapter.gdIndex, adapter.storageSet = downloader.dbIndex, downloader.gdIndex, downloader.storageSet
+	plan, err = ReconcileWithPlan(context.Background(), &Spec{Adapter: adapter}, nil, mockClient, "", opts)
+	require.NoError(t, err)
+	assert.Zero(t, plan.Summary.DownloadActions)
+	for _, action := range plan.Actions {
+		assert.NotEqual(t, ActionDownloadStorage, action.Type)
+	}
+}
+
+// TestApplyPlan_DownloadStorage tests that downloads need an upstream, and that the
+// upstream lacking an object skips it instead of failing the run.
+func TestApplyPlan_DownloadStorage(t *testing.T) {
+	defer SetUpstream(nil)
+	opts := ReconcileOptions{DoFixStorage: true, Confirmed: true}
+	newPlan := func() *ReconcilePlan {
+		return &ReconcilePlan{Actions: []Action{
+			{Type: ActionDownloadStorage, Key: "1"},
+			{Type: ActionDownloadStorage, Key: "2"},
+		}}
+	}
+
+	downloader := &mockDownloader{unavailable: map[string]bool{"2": true}}
+	_, err := ApplyPlan(context.Background(), &Spec{Adapter: downloader}, nil, nil, "", newPlan(), opts)
+	assert.ErrorContains(t, err, "no upstream configured")
+
+	SetUpstream(stubUpstream{})
+	plan := newPlan()
+	executed, err := ApplyPlan(context.Background(), &Spec{Adapter: downloader}, nil, nil, "", plan, opts)
+	require.NoError(t, err)
+	assert.Equal(t, 1, executed)
+	assert.Equal(t, []string{"1"}, downloader.downloaded)
+	require.Len(t, plan.SkippedDownloads, 1)
+	assert.Equal(t, "2", plan.SkippedDownloads[0].Key)
+	assert.Equal(t, []Action{{Type: ActionDownloadStorage, Key: "1"}}, appliedActions(plan))
+
+	_, err = ApplyPlan(context.Background(), &Spec{Adapter: &mockMutator{}}, nil, nil, "", newPlan(), opts)
+	assert.ErrorContains(t, err, "does not implement StorageDownloader")
+
+	assert.Equal(t, "still missing in storage", verifyAction(plan.Actions[0], ReconcileResult{GamedataPresent: true}))
+	assert.Empty(t, verifyAction(plan.Actions[0], ReconcileResult{GamedataPresent: true, StoragePresent: true}))
+}
@@ -0,0 +1,115 @@
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"asset-manager/core/storage"
+
+	"gorm.io/gorm"
+)
+
+// VerifyPlan re-reads all sources after ApplyPlan and checks that every key touched
+// by the plan reached its expected state. This catches mutations that reported
+// success but had no effect (e.g. deletes silently ignored by the store).
+// The verification is also stored on plan.Verification.
+func VerifyPlan(
+	ctx context.Context,
+	spec *Spec,
+	db *gorm.DB,
+	client storage.Client,
+	bucket string,
+	plan *ReconcilePlan,
+) (*PlanVerification, error) {
+	verification := &PlanVerification{
+		Consistent:  make([]string, 0),
+		StillBroken: make([]VerificationFailure, 0),
+	}
+
+	// Group actions by key so each key is judged once
+	actionsByKey := make(map[string][]Action)
+	for _, action := range plan.Actions {
+		actionsByKey[action.Key] = append(actionsByKey[action.Key], action)
+	}
+	if len(actionsByKey) == 0 {
+		plan.Verification = verification
+		return verification, nil
+	}
+
+	// Always rebuild from the sources: the cached indices are patched from the
+	// plan itself, so they would report every action as successful. Read from the
+	// primary, since a lagging replica would report applied actions as failed.
+	cache, err := buildCache(ctx, spec, db, client, bucket)
+	if err != nil {
+		return nil, fmt.Errorf("failed to rebuild indices for verification: %w", err)
+	}
+	storeCache(spec, cache)
+
+	keys := make([]string, 0, len(actionsByKey))
+	for key := range actionsByKey {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		result := buildResult(key, cache, spec.Adapter)
+		verification.Checked++
+
+		consistent := true
+		for _, action := range actionsByKey[key] {
+			if reason := verifyAction(action, result); reason != "" {
+				verification.StillBroken = append(verification.StillBroken, VerificationFailure{
+					Key:    key,
+					Action: action.Type,
+					Reason: reason,
+				})
+				consistent = false
+			}
+		}
+		if consistent {
+			verification.Consistent = append(verification.Consistent, key)
+		}
+	}
+
+	plan.Verification = verification
+	return verification, nil
+}
+
+// verifyAction returns why the post-apply result contradicts the action, or "" if it held.
+func verifyAction(action Action, result ReconcileResult) string {
+	switch action.Type {
+	case ActionDeleteDB:
+		if result.DBPresent {
+			return "still present in database"
+		}
+	case ActionDeleteGamedata:
+		if result.GamedataPresent {
+			return "still present in gamedata"
+		}
+	case ActionDeleteStorage:
+		if result.StoragePresent {
+			return "still present in storage"
+		}
+	case ActionSyncDB, ActionSyncGamedata:
+		if mismatches := syncMismatches(result); len(mismatches) > 0 {
+			return fmt.Sprintf("still mismatched: %v", mismatches)
+		}
+	case ActionDownloadStorage:
+		if !result.StoragePresent {
+			return "still missing in storage"
+		}
+	case ActionInsertGamedata:
+		if !result.GamedataPresent {
+			return "still missing in gamedata"
+		}
+	case ActionMoveStorage:
+		if !result.StoragePresent {
+			return "missing in storage after the move"
+		}
+		if result.Misplaced != "" {
+			return "still misplaced at " + result.Misplaced
+		}
+	}
+	return ""
+}
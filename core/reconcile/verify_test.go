@@ -0,0 +1,57 @@
+package reconcile
+
+import (
+	"context"
+	"testing"
+
+	"asset-manager/core/storage/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestVerifyPlan tests that actions without effect are reported as still broken.
+func TestVerifyPlan(t *testing.T) {
+	// Post-apply state: key 1 was deleted from DB, key 2 is still in storage,
+	// key 3 still has a mismatch after sync.
+	adapter := &mockAdapter{
+		dbIndex:    map[string]DBItem{"3": "3"},
+		gdIndex:    map[string]GDItem{"3": "3"},
+		storageSet: map[string]struct{}{"2": {}, "3": {}},
+		mismatches: map[string][]string{"3": {"width: gd=2 db=1"}},
+	}
+	spec := &Spec{Adapter: adapter}
+
+	plan := &ReconcilePlan{
+		Actions: []Action{
+			{Type: ActionDeleteDB, Key: "1"},
+			{Type: ActionDeleteStorage, Key: "2"},
+			{Type: ActionSyncDB, Key: "3"},
+		},
+	}
+
+	mockClient := new(mocks.Client)
+	mockClient.On("BucketExists", mock.Anything, "").Return(true, nil)
+
+	verification, err := VerifyPlan(context.Background(), spec, nil, mockClient, "", plan)
+	assert.NoError(t, err)
+	assert.Same(t, verification, plan.Verification)
+
+	assert.Equal(t, 3, verification.Checked)
+	assert.Equal(t, []string{"1"}, verification.Consistent)
+	assert.Len(t, verification.StillBroken, 2)
+	assert.Equal(t, "2", verification.StillBroken[0].Key)
+	assert.Equal(t, ActionDeleteStorage, verification.StillBroken[0].Action)
+	assert.Equal(t, "3", verification.StillBroken[1].Key)
+	assert.Equal(t, ActionSyncDB, verification.StillBroken[1].Action)
+}
+
+// TestVerifyPlan_NoActions tests that an empty plan skips re-reading sources.
+func TestVerifyPlan_NoActions(t *testing.T) {
+	spec := &Spec{Adapter: &mockAdapter{}}
+	plan := &ReconcilePlan{}
+
+	verification, err := VerifyPlan(context.Background(), spec, nil, nil, "", plan)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, verification.Checked)
+}
@@ -0,0 +1,68 @@
+package scheduler
+
+import (
+	"time"
+
+	"asset-manager/core/reconcile"
+)
+
+// Config holds configuration for background jobs.
+type Config struct {
+	// Timezone is the IANA time zone (e.g. Europe/Madrid) windows are read in; "Local"
+	// uses the server's zone.
+	Timezone string `mapstructure:"timezone" default:"Local"`
+	// SafeFix configures the unattended safe-fix job.
+	SafeFix SafeFixConfig `mapstructure:"safefix"`
+	// Integrity configures the scheduled full integrity check.
+	Integrity IntegrityConfig `mapstructure:"integrity"`
+}
+
+// IntegrityConfig controls the scheduled full furniture integrity check.
+type IntegrityConfig struct {
+	// Schedule is a cron expression read in the scheduler time zone, e.g. "0 3 * * *".
+	// Empty disables the check.
+	Schedule string `mapstructure:"schedule" default:""`
+	// Keep is the number of stored reports kept; older ones are deleted.
+	Keep int `mapstructure:"keep" default:"30"`
+	// WebhookURL receives a POST when a run finds issues the previous report did not
+	// have. Empty disables the webhook.
+	WebhookURL string `mapstructure:"webhook_url" default:""`
+	// WebhookSecret signs webhook bodies with HMAC-SHA256. Empty sends them unsigned.
+	WebhookSecret string `mapstructure:"webhook_secret" default:""`
+}
+
+// SafeFixConfig controls which reconcile actions are applied without confirmation.
+type SafeFixConfig struct {
+	// Enabled turns on the scheduled safe-fix job.
+	Enabled bool `mapstructure:"enabled" default:"false"`
+	// Interval is the time between runs.
+	Interval time.Duration `mapstructure:"interval" default:"24h"`
+	// SyncFields lists the mismatch fields a sync may repair unattended.
+	SyncFields []string `mapstructure:"sync_fields" default:"width,length"`
+	// MaxActions caps the number of actions applied per run.
+	MaxActions int `mapstructure:"max_actions" default:"100"`
+	// Window limits runs to a daily maintenance window in hotel time, e.g. "03:00-05:00".
+	// Empty runs at any time.
+	Window string `mapstructure:"window" default:""`
+	// MaxOnlineUsers skips runs while more users than this are online in the emulator.
+	// Zero disables the check.
+	MaxOnlineUsers int `mapstructure:"max_online_users" default:"0"`
+}
+
+// SafeFixWindow returns the safe-fix maintenance window, or nil when none is set.
+func (c Config) SafeFixWindow() (*Window, error) {
+	return ParseWindow(c.SafeFix.Window, c.Timezone)
+}
+
+// IntegritySchedule returns the integrity check schedule, or nil when none is set.
+func (c Config) IntegritySchedule() (*Cron, error) {
+	return ParseCron(c.Integrity.Schedule, c.Timezone)
+}
+
+// Policy converts the configuration into a reconcile safe-fix policy.
+func (c SafeFixConfig) Policy() reconcile.SafeFixPolicy {
+	return reconcile.SafeFixPolicy{
+		SyncFields: c.SyncFields,
+		MaxActions: c.MaxActions,
+	}
+}
@@ -0,0 +1,195 @@
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronMacros maps the supported "@" shorthands to their five-field form.
+var cronMacros = map[string]string{
+	"@hourly":   "0 * * * *",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@weekly":   "0 0 * * 0",
+	"@monthly":  "0 0 1 * *",
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+}
+
+// cronField describes the bounds of one cron field.
+type cronField struct {
+	name     string
+	min, max int
+}
+
+var cronFields = [5]cronField{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7},
+}
+
+// Cron is a standard five-field cron schedule (minute, hour, day of month, month, day
+// of week) read in a time zone. Fields accept "*", values, ranges ("1-5"), steps
+// ("*/15", "0-30/10") and comma-separated lists of these. Day of week runs from 0
+// (Sunday) to 6, with 7 also meaning Sunday. As in cron(8), when both day fields are
+// restricted a day matching either of them matches.
+type Cron struct {
+	spec     string
+	minutes  uint64
+	hours    uint64
+	days     uint64
+	months   uint64
+	weekdays uint64
+	// anyDay and anyWeekday are set for a "*" day of month and day of week.
+	anyDay     bool
+	anyWeekday bool
+
+	// Location is the time zone the schedule is read in.
+	Location *time.Location
+}
+
+// ParseCron parses a five-field cron expression, or one of @hourly, @daily, @weekly,
+// @monthly and @yearly, read in the named IANA time zone ("Local" or "" for the
+// server's zone). An empty spec returns nil: no schedule.
+func ParseCron(spec, timezone string) (*Cron, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return nil, nil
+	}
+
+	loc, err := LoadLocation(timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	expr := spec
+	if macro, ok := cronMacros[strings.ToLower(spec)]; ok {
+		expr = macro
+	}
+	fields := strings.Fields(expr)
+	if len(fields) != len(cronFields) {
+		return nil, fmt.Errorf("invalid schedule %q: want 5 fields (minute hour day month weekday)", spec)
+	}
+
+	var sets [5]uint64
+	for i, field := range fields {
+		if sets[i], err = parseCronField(field, cronFields[i]); err != nil {
+			return nil, fmt.Errorf("invalid schedule %q: %w", spec, err)
+		}
+	}
+	// Sunday is both 0 and 7
+	if sets[4]&(1<<7) != 0 {
+		sets[4] |= 1
+	}
+
+	return &Cron{
+		spec:       spec,
+		minutes:    sets[0],
+		hours:      sets[1],
+		days:       sets[2],
+		months:     sets[3],
+		weekdays:   sets[4],
+		anyDay:     fields[2] == "*",
+		anyWeekday: fields[4] == "*",
+		Location:   loc,
+	}, nil
+}
+
+// parseCronField returns the values of one field as a bit set.
+func parseCronField(field string, bounds cronField) (uint64, error) {
+	var set uint64
+	for _, part := range strings.Split(field, ",") {
+		rng, stepSpec, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepSpec)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step %q in %s", stepSpec, bounds.name)
+			}
+			step = n
+		}
+
+		lo, hi := bounds.min, bounds.max
+		if rng != "*" {
+			loSpec, hiSpec, isRange := strings.Cut(rng, "-")
+			var err error
+			if lo, err = strconv.Atoi(loSpec); err != nil {
+				return 0, fmt.Errorf("invalid value %q in %s", loSpec, bounds.name)
+			}
+			hi = lo
+			if isRange {
+				if hi, err = strconv.Atoi(hiSpec); err != nil {
+					return 0, fmt.Errorf("invalid value %q in %s", hiSpec, bounds.name)
+				}
+			} else if hasStep {
+				// "5/15" runs from 5 to the end of the range
+				hi = bounds.max
+			}
+		}
+		if lo < bounds.min || hi > bounds.max || lo > hi {
+			return 0, fmt.Errorf("%s %q out of range %d-%d", bounds.name, rng, bounds.min, bounds.max)
+		}
+
+		for v := lo; v <= hi; v += step {
+			set |= 1 << v
+		}
+	}
+	return set, nil
+}
+
+// Next returns the first time strictly after t, truncated to the minute, that matches
+// the schedule. It returns the zero time when nothing matches within five years, as
+// for "0 0 31 2 *".
+func (c *Cron) Next(t time.Time) time.Time {
+	t = t.In(c.Location).Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if c.months&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, c.Location)
+			continue
+		}
+		if !c.matchesDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, c.Location)
+			continue
+		}
+		if c.hours&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, c.Location)
+			continue
+		}
+		if c.minutes&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// matchesDay reports whether the date of t matches the day of month and day of week fields.
+func (c *Cron) matchesDay(t time.Time) bool {
+	day := c.days&(1<<uint(t.Day())) != 0
+	weekday := c.weekdays&(1<<uint(t.Weekday())) != 0
+	switch {
+	case c.anyDay && c.anyWeekday:
+		return true
+	case c.anyDay:
+		return weekday
+	case c.anyWeekday:
+		return day
+	default:
+		return day || weekday
+	}
+}
+
+// String returns the schedule as "<spec> <zone>".
+func (c *Cron) String() string {
+	if c == nil {
+		return "never"
+	}
+	return c.spec + " " + c.Location.String()
+}
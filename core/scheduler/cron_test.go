@@ -0,0 +1,93 @@
+package scheduler
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// TestParseCron tests parsing of cron specs, macros and invalid fields.
+func TestParseCron(t *testing.T) {
+	c, err := ParseCron("", "Local")
+	require.NoError(t, err)
+	assert.Nil(t, c)
+	assert.Equal(t, "never", c.String())
+
+	c, err = ParseCron("0 3 * * *", "UTC")
+	require.NoError(t, err)
+	assert.Equal(t, "0 3 * * * UTC", c.String())
+
+	_, err = ParseCron("@daily", "UTC")
+	assert.NoError(t, err)
+
+	for _, spec := range []string{"0 3 * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "* * * * 8", "*/0 * * * *", "5-1 * * * *", "a * * * *"} {
+		_, err := ParseCron(spec, "UTC")
+		assert.Error(t, err, spec)
+	}
+	_, err = ParseCron("0 3 * * *", "Mars/Olympus")
+	assert.Error(t, err)
+}
+
+// TestCron_Next tests the next run time of common schedules.
+func TestCron_Next(t *testing.T) {
+	at := func(spec string) *Cron {
+		c, err := ParseCron(spec, "UTC")
+		require.NoError(t, err, spec)
+		return c
+	}
+	from := time.Date(2024, 1, 10, 3, 0, 0, 0, time.UTC) // a Wednesday
+
+	// Strictly after the given time
+	assert.Equal(t, time.Date(2024, 1, 11, 3, 0, 0, 0, time.UTC), at("0 3 * * *").Next(from))
+	assert.Equal(t, time.Date(2024, 1, 10, 3, 15, 0, 0, time.UTC), at("*/15 * * * *").Next(from.Add(30*time.Second)))
+	assert.Equal(t, time.Date(2024, 1, 10, 4, 0, 0, 0, time.UTC), at("@hourly").Next(from))
+	// Weekdays 1-5 at 09:30, and Sunday written as 7
+	assert.Equal(t, time.Date(2024, 1, 10, 9, 30, 0, 0, time.UTC), at("30 9 * * 1-5").Next(from))
+	assert.Equal(t, time.Date(2024, 1, 14, 0, 0, 0, 0, time.UTC), at("0 0 * * 7").Next(from))
+	// Either day field matches when both are restricted
+	assert.Equal(t, time.Date(2024, 1, 12, 0, 0, 0, 0, time.UTC), at("0 0 15 * 5").Next(from))
+	assert.Equal(t, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), at("@monthly").Next(from))
+	assert.Equal(t, time.Date(2024, 2, 29, 12, 0, 0, 0, time.UTC), at("0 12 29 2 *").Next(from))
+	// Impossible dates never match
+	assert.True(t, at("0 0 31 2 *").Next(from).IsZero())
+}
+
+// TestCron_NextInZone tests that the schedule is read in its time zone.
+func TestCron_NextInZone(t *testing.T) {
+	madrid, err := time.LoadLocation("Europe/Madrid")
+	require.NoError(t, err)
+	c, err := ParseCron("0 3 * * *", "Europe/Madrid")
+	require.NoError(t, err)
+
+	next := c.Next(time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC))
+	assert.Equal(t, time.Date(2024, 1, 11, 3, 0, 0, 0, madrid), next)
+	assert.Equal(t, time.Date(2024, 1, 11, 2, 0, 0, 0, time.UTC), next.UTC())
+}
+
+// TestScheduler_CronJob tests that a scheduled job runs at its next time.
+func TestScheduler_CronJob(t *testing.T) {
+	c, err := ParseCron("* * * * *", "UTC")
+	require.NoError(t, err)
+
+	var runs atomic.Int32
+	s := New(zap.NewNop())
+	// Pretend every run starts just before a minute boundary
+	s.now = func() time.Time { return time.Now().Truncate(time.Minute).Add(time.Minute - 5*time.Millisecond) }
+	s.Add(Job{
+		Name:     "cron",
+		Schedule: c,
+		Run: func(ctx context.Context) error {
+			runs.Add(1)
+			return nil
+		},
+	})
+
+	s.Start(context.Background())
+	assert.Eventually(t, func() bool { return runs.Load() >= 2 }, time.Second, time.Millisecond)
+	s.Stop()
+}
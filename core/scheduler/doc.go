@@ -0,0 +1,38 @@
+// Package scheduler runs background jobs on a fixed interval or a cron schedule inside
+// the server process.
+//
+// Jobs never overlap: a tick that arrives while the previous run is still going is
+// dropped. Each run is logged with its duration, and errors are logged rather than
+// stopping the job. A job may be limited to a daily maintenance Window, read in a
+// configured time zone, and to runs its Precheck accepts; other ticks are skipped.
+//
+// # Safe-Fix
+//
+// The unattended safe-fix run applies a whitelisted subset of reconcile sync actions
+// (never deletions) up to a per-run cap and writes each applied action to the audit log.
+//
+// # Integrity Check
+//
+// The scheduled integrity check runs a full furniture reconciliation at the times of a
+// cron schedule (see ParseCron), stores its report and may post new issues to a webhook.
+//
+// # Configuration
+//
+//	SCHEDULER_SAFEFIX_ENABLED=true
+//	SCHEDULER_SAFEFIX_INTERVAL=24h
+//	SCHEDULER_SAFEFIX_SYNC_FIELDS=width,length
+//	SCHEDULER_SAFEFIX_MAX_ACTIONS=100
+//	SCHEDULER_SAFEFIX_WINDOW=03:00-05:00
+//	SCHEDULER_SAFEFIX_MAX_ONLINE_USERS=50
+//	SCHEDULER_INTEGRITY_SCHEDULE=0 3 * * *
+//	SCHEDULER_INTEGRITY_WEBHOOK_URL=https://hooks.example.com/asset-manager
+//	SCHEDULER_TIMEZONE=Europe/Madrid
+//
+// # Usage
+//
+//	s := scheduler.New(logger)
+//	s.Add(scheduler.Job{Name: "safe-fix", Interval: 24 * time.Hour, Run: run})
+//	s.Add(scheduler.Job{Name: "integrity", Schedule: cron, Run: check})
+//	s.Start(ctx)
+//	defer s.Stop()
+package scheduler
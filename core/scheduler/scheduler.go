@@ -0,0 +1,148 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Job is a unit of background work run on a fixed interval.
+type Job struct {
+	// Name identifies the job in logs.
+	Name string
+
+	// Interval is the time between runs. The first run happens one interval after Start.
+	Interval time.Duration
+
+	// Schedule runs the job at the times of a cron schedule instead of every Interval.
+	Schedule *Cron
+
+	// Window restricts runs to a daily time range. Ticks outside it are skipped; nil
+	// runs on every tick.
+	Window *Window
+
+	// Precheck is called before each run inside the window. A returned error skips the
+	// run and is logged as the reason; nil runs on every tick.
+	Precheck func(ctx context.Context) error
+
+	// Run performs the work. Returned errors are logged.
+	Run func(ctx context.Context) error
+}
+
+// Scheduler runs registered jobs until stopped.
+type Scheduler struct {
+	logger *zap.Logger
+	jobs   []Job
+	now    func() time.Time
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New creates an empty scheduler.
+func New(logger *zap.Logger) *Scheduler {
+	return &Scheduler{logger: logger, now: time.Now}
+}
+
+// Add registers a job. Jobs must be added before Start.
+func (s *Scheduler) Add(job Job) {
+	s.jobs = append(s.jobs, job)
+}
+
+// Start launches one goroutine per job. Jobs stop when ctx is cancelled or Stop is called.
+func (s *Scheduler) Start(ctx context.Context) {
+	ctx, s.cancel = context.WithCancel(ctx)
+
+	for _, job := range s.jobs {
+		s.wg.Add(1)
+		go s.loop(ctx, job)
+	}
+}
+
+// Stop cancels all jobs and waits for running ones to return.
+func (s *Scheduler) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+}
+
+// loop runs the job on every tick. The ticker drops ticks while a run is in
+// progress, so a slow run never overlaps with the next one.
+func (s *Scheduler) loop(ctx context.Context, job Job) {
+	defer s.wg.Done()
+
+	if job.Schedule != nil {
+		s.cronLoop(ctx, job)
+		return
+	}
+
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	s.logger.Info("Scheduled job",
+		zap.String("job", job.Name),
+		zap.Duration("interval", job.Interval),
+		zap.Stringer("window", job.Window))
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.run(ctx, job)
+		}
+	}
+}
+
+// cronLoop runs the job at every time of its schedule. The next time is computed
+// after each run returns, so times missed by a slow run are skipped.
+func (s *Scheduler) cronLoop(ctx context.Context, job Job) {
+	s.logger.Info("Scheduled job",
+		zap.String("job", job.Name),
+		zap.Stringer("schedule", job.Schedule),
+		zap.Stringer("window", job.Window))
+
+	for {
+		next := job.Schedule.Next(s.now())
+		if next.IsZero() {
+			s.logger.Warn("Scheduled job never runs", zap.String("job", job.Name), zap.Stringer("schedule", job.Schedule))
+			return
+		}
+
+		timer := time.NewTimer(next.Sub(s.now()))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+			s.run(ctx, job)
+		}
+	}
+}
+
+// run executes the job once and logs the outcome. Runs outside the job's window or
+// refused by its precheck are skipped.
+func (s *Scheduler) run(ctx context.Context, job Job) {
+	start := s.now()
+	if !job.Window.Contains(start) {
+		s.logger.Debug("Scheduled job outside its window", zap.String("job", job.Name), zap.Stringer("window", job.Window))
+		return
+	}
+	if job.Precheck != nil {
+		if err := job.Precheck(ctx); err != nil {
+			s.logger.Warn("Scheduled job skipped", zap.String("job", job.Name), zap.Error(err))
+			return
+		}
+	}
+
+	err := job.Run(ctx)
+
+	fields := []zap.Field{zap.String("job", job.Name), zap.Duration("duration", time.Since(start))}
+	if err != nil {
+		s.logger.Error("Scheduled job failed", append(fields, zap.Error(err))...)
+		return
+	}
+	s.logger.Info("Scheduled job finished", fields...)
+}
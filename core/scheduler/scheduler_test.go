@@ -0,0 +1,102 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// TestScheduler_RunsJobUntilStopped tests that a job runs repeatedly and stops on Stop.
+func TestScheduler_RunsJobUntilStopped(t *testing.T) {
+	var runs atomic.Int32
+	s := New(zap.NewNop())
+	s.Add(Job{
+		Name:     "tick",
+		Interval: 5 * time.Millisecond,
+		Run: func(ctx context.Context) error {
+			runs.Add(1)
+			return errors.New("logged, not fatal")
+		},
+	})
+
+	s.Start(context.Background())
+	assert.Eventually(t, func() bool { return runs.Load() >= 2 }, time.Second, time.Millisecond)
+	s.Stop()
+
+	stopped := runs.Load()
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, stopped, runs.Load())
+}
+
+// TestScheduler_NoOverlap tests that a slow run is never started twice concurrently.
+func TestScheduler_NoOverlap(t *testing.T) {
+	var active, maxActive atomic.Int32
+	s := New(zap.NewNop())
+	s.Add(Job{
+		Name:     "slow",
+		Interval: time.Millisecond,
+		Run: func(ctx context.Context) error {
+			n := active.Add(1)
+			if n > maxActive.Load() {
+				maxActive.Store(n)
+			}
+			time.Sleep(10 * time.Millisecond)
+			active.Add(-1)
+			return nil
+		},
+	})
+
+	s.Start(context.Background())
+	time.Sleep(50 * time.Millisecond)
+	s.Stop()
+
+	assert.Equal(t, int32(1), maxActive.Load())
+}
+
+// TestScheduler_SkipsOutsideWindowAndPrecheck tests that runs outside the window or
+// refused by the precheck do not call Run.
+func TestScheduler_SkipsOutsideWindowAndPrecheck(t *testing.T) {
+	window, err := ParseWindow("03:00-05:00", "UTC")
+	assert.NoError(t, err)
+
+	var runs atomic.Int32
+	refuse := errors.New("too busy")
+	var precheckErr error
+	job := Job{
+		Name:     "windowed",
+		Window:   window,
+		Precheck: func(ctx context.Context) error { return precheckErr },
+		Run: func(ctx context.Context) error {
+			runs.Add(1)
+			return nil
+		},
+	}
+
+	s := New(zap.NewNop())
+	s.now = func() time.Time { return time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC) }
+	s.run(context.Background(), job)
+	assert.Equal(t, int32(0), runs.Load())
+
+	s.now = func() time.Time { return time.Date(2024, 1, 10, 4, 0, 0, 0, time.UTC) }
+	precheckErr = refuse
+	s.run(context.Background(), job)
+	assert.Equal(t, int32(0), runs.Load())
+
+	precheckErr = nil
+	s.run(context.Background(), job)
+	assert.Equal(t, int32(1), runs.Load())
+}
+
+// TestSafeFixConfig_Policy tests conversion of the configuration into a policy.
+func TestSafeFixConfig_Policy(t *testing.T) {
+	cfg := SafeFixConfig{SyncFields: []string{"width"}, MaxActions: 5}
+	policy := cfg.Policy()
+
+	assert.Equal(t, []string{"width"}, policy.SyncFields)
+	assert.Equal(t, 5, policy.MaxActions)
+}
@@ -0,0 +1,97 @@
+package scheduler
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Window is a daily time range, in a time zone, during which a job may run.
+// A window whose end is before its start wraps past midnight (e.g. 23:00-02:00).
+type Window struct {
+	// Start and End are offsets from midnight.
+	Start time.Duration
+	End   time.Duration
+
+	// Location is the time zone the offsets are read in.
+	Location *time.Location
+}
+
+// ParseWindow parses a "HH:MM-HH:MM" range read in the named IANA time zone
+// ("Local" or "" for the server's zone). An empty spec returns nil: no window.
+func ParseWindow(spec, timezone string) (*Window, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return nil, nil
+	}
+
+	loc, err := LoadLocation(timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	startSpec, endSpec, ok := strings.Cut(spec, "-")
+	if !ok {
+		return nil, fmt.Errorf("invalid window %q: want HH:MM-HH:MM", spec)
+	}
+	start, err := parseClock(startSpec)
+	if err != nil {
+		return nil, fmt.Errorf("invalid window %q: %w", spec, err)
+	}
+	end, err := parseClock(endSpec)
+	if err != nil {
+		return nil, fmt.Errorf("invalid window %q: %w", spec, err)
+	}
+	if start == end {
+		return nil, fmt.Errorf("invalid window %q: start equals end", spec)
+	}
+
+	return &Window{Start: start, End: end, Location: loc}, nil
+}
+
+// LoadLocation returns the named IANA time zone, or the server's zone for "" and "Local".
+func LoadLocation(timezone string) (*time.Location, error) {
+	timezone = strings.TrimSpace(timezone)
+	if timezone == "" || timezone == "Local" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time zone %q: %w", timezone, err)
+	}
+	return loc, nil
+}
+
+// parseClock parses "HH:MM" into an offset from midnight.
+func parseClock(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q: want HH:MM", strings.TrimSpace(s))
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// Contains reports whether t falls inside the window. The start is inclusive and the
+// end exclusive.
+func (w *Window) Contains(t time.Time) bool {
+	if w == nil {
+		return true
+	}
+	t = t.In(w.Location)
+	offset := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second
+	if w.Start < w.End {
+		return offset >= w.Start && offset < w.End
+	}
+	return offset >= w.Start || offset < w.End
+}
+
+// String returns the window as "HH:MM-HH:MM <zone>".
+func (w *Window) String() string {
+	if w == nil {
+		return "always"
+	}
+	clock := func(d time.Duration) string {
+		return fmt.Sprintf("%02d:%02d", int(d.Hours()), int(d.Minutes())%60)
+	}
+	return clock(w.Start) + "-" + clock(w.End) + " " + w.Location.String()
+}
@@ -0,0 +1,55 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseWindow tests parsing of window specs and time zones.
+func TestParseWindow(t *testing.T) {
+	w, err := ParseWindow("", "Local")
+	require.NoError(t, err)
+	assert.Nil(t, w)
+
+	w, err = ParseWindow("03:00-05:30", "UTC")
+	require.NoError(t, err)
+	assert.Equal(t, 3*time.Hour, w.Start)
+	assert.Equal(t, 5*time.Hour+30*time.Minute, w.End)
+	assert.Equal(t, "03:00-05:30 UTC", w.String())
+
+	for _, spec := range []string{"03:00", "3-5", "25:00-05:00", "04:00-04:00"} {
+		_, err := ParseWindow(spec, "UTC")
+		assert.Error(t, err, spec)
+	}
+	_, err = ParseWindow("03:00-05:00", "Mars/Olympus")
+	assert.Error(t, err)
+}
+
+// TestWindow_Contains tests same-day and midnight-wrapping windows in a time zone.
+func TestWindow_Contains(t *testing.T) {
+	madrid, err := time.LoadLocation("Europe/Madrid")
+	require.NoError(t, err)
+
+	night, err := ParseWindow("03:00-05:00", "Europe/Madrid")
+	require.NoError(t, err)
+	assert.True(t, night.Contains(time.Date(2024, 1, 10, 3, 0, 0, 0, madrid)))
+	assert.True(t, night.Contains(time.Date(2024, 1, 10, 4, 59, 59, 0, madrid)))
+	assert.False(t, night.Contains(time.Date(2024, 1, 10, 5, 0, 0, 0, madrid)))
+	// 03:30 UTC is 04:30 in Madrid
+	assert.True(t, night.Contains(time.Date(2024, 1, 10, 3, 30, 0, 0, time.UTC)))
+	// 02:30 UTC is 03:30 in Madrid
+	assert.True(t, night.Contains(time.Date(2024, 1, 10, 2, 30, 0, 0, time.UTC)))
+	assert.False(t, night.Contains(time.Date(2024, 1, 10, 4, 30, 0, 0, time.UTC)))
+
+	wrap, err := ParseWindow("23:00-02:00", "UTC")
+	require.NoError(t, err)
+	assert.True(t, wrap.Contains(time.Date(2024, 1, 10, 23, 30, 0, 0, time.UTC)))
+	assert.True(t, wrap.Contains(time.Date(2024, 1, 10, 1, 0, 0, 0, time.UTC)))
+	assert.False(t, wrap.Contains(time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)))
+
+	var always *Window
+	assert.True(t, always.Contains(time.Now()))
+}
@@ -1,27 +1,85 @@
 package server
 
+import (
+	"errors"
+	"net"
+	"time"
+)
+
 // Config holds configuration for the HTTP server.
 type Config struct {
+	// Host is the address the server listens on; empty listens on every interface.
+	Host string `mapstructure:"host" default:""`
 	// Port is the port where the server will listen.
 	Port string `mapstructure:"port" default:"8080"`
+	// TLSCert is the PEM certificate file used to serve HTTPS. Requires TLSKey.
+	TLSCert string `mapstructure:"tls_cert" default:""`
+	// TLSKey is the PEM private key file of TLSCert.
+	TLSKey string `mapstructure:"tls_key" default:""`
+	// HTTP2 serves HTTP/2 (negotiated over TLS) through net/http instead of fasthttp.
+	HTTP2 bool `mapstructure:"http2" default:"false"`
 	// ApiKey is the secret key required to access the API.
 	ApiKey string `mapstructure:"api_key" default:""`
-	// Emulator specifies the emulator type (arcturus, plusemu, comet).
+	// Emulator specifies the emulator type (arcturus, arcturus-ms, plusemu, comet), or
+	// auto to detect it from the database schema on connect. An arcturus database
+	// using the Morningstar 4.x schema is detected as arcturus-ms.
 	Emulator string `mapstructure:"emulator" default:"arcturus"`
+	// MutationsRequireConfirmation makes HTTP-triggered fixes, gamedata restores, imports
+	// and conversions answer with their plan and a confirmation token, applying them only
+	// when the token is echoed back.
+	MutationsRequireConfirmation bool `mapstructure:"mutations_require_confirmation" default:"false"`
+	// ConfirmationTTL is how long a confirmation token stays valid.
+	ConfirmationTTL time.Duration `mapstructure:"confirmation_ttl" default:"5m"`
+	// ServeAssets mounts the public read-through asset routes (/assets/...).
+	ServeAssets bool `mapstructure:"serve_assets" default:"false"`
+	// AssetsMaxAge is the Cache-Control max-age of served assets.
+	AssetsMaxAge time.Duration `mapstructure:"assets_max_age" default:"24h"`
 }
 
 const (
-	EmulatorArcturus = "arcturus"
-	EmulatorPlus     = "plusemu"
-	EmulatorComet    = "comet"
+	EmulatorArcturus   = "arcturus"
+	EmulatorArcturusMS = "arcturus-ms"
+	EmulatorPlus       = "plusemu"
+	EmulatorComet      = "comet"
+	// EmulatorAuto detects the emulator from the database schema (see
+	// database.DetectEmulator).
+	EmulatorAuto = "auto"
 )
 
 // IsValidEmulator checks if the configured emulator is valid.
 func (c Config) IsValidEmulator() bool {
 	switch c.Emulator {
-	case EmulatorArcturus, EmulatorPlus, EmulatorComet:
+	case EmulatorArcturus, EmulatorArcturusMS, EmulatorPlus, EmulatorComet, EmulatorAuto:
 		return true
 	default:
 		return false
 	}
 }
+
+// Addr returns the host:port address the server listens on.
+func (c Config) Addr() string {
+	return net.JoinHostPort(c.Host, c.Port)
+}
+
+// TLSEnabled reports whether the server terminates TLS itself.
+func (c Config) TLSEnabled() bool {
+	return c.TLSCert != "" || c.TLSKey != ""
+}
+
+// Validate checks that the listen settings can be served.
+func (c Config) Validate() error {
+	if c.Port == "" {
+		return errors.New("server port is required")
+	}
+	if (c.TLSCert == "") != (c.TLSKey == "") {
+		return errors.New("SERVER_TLS_CERT and SERVER_TLS_KEY must be set together")
+	}
+	if c.HTTP2 && !c.TLSEnabled() {
+		// Browsers and most clients only speak HTTP/2 over TLS; cleartext h2c is not supported
+		return errors.New("SERVER_HTTP2 requires SERVER_TLS_CERT and SERVER_TLS_KEY")
+	}
+	if c.MutationsRequireConfirmation && c.ConfirmationTTL <= 0 {
+		return errors.New("SERVER_CONFIRMATION_TTL must be positive when SERVER_MUTATIONS_REQUIRE_CONFIRMATION is set")
+	}
+	return nil
+}
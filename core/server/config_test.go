@@ -2,6 +2,7 @@ package server
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -13,8 +14,10 @@ func TestIsValidEmulator(t *testing.T) {
 		want     bool
 	}{
 		{"arcturus", EmulatorArcturus, true},
+		{"arcturus-ms", EmulatorArcturusMS, true},
 		{"plusemu", EmulatorPlus, true},
 		{"comet", EmulatorComet, true},
+		{"auto", EmulatorAuto, true},
 		{"invalid", "unknown", false},
 		{"empty", "", false},
 	}
@@ -26,3 +29,37 @@ func TestIsValidEmulator(t *testing.T) {
 		})
 	}
 }
+
+func TestConfig_Addr(t *testing.T) {
+	assert.Equal(t, ":8080", Config{Port: "8080"}.Addr())
+	assert.Equal(t, "127.0.0.1:8443", Config{Host: "127.0.0.1", Port: "8443"}.Addr())
+	assert.Equal(t, "[::1]:8080", Config{Host: "::1", Port: "8080"}.Addr())
+}
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr string
+	}{
+		{"plain", Config{Port: "8080"}, ""},
+		{"tls", Config{Port: "8443", TLSCert: "cert.pem", TLSKey: "key.pem"}, ""},
+		{"http2", Config{Port: "8443", TLSCert: "cert.pem", TLSKey: "key.pem", HTTP2: true}, ""},
+		{"no port", Config{}, "port is required"},
+		{"cert without key", Config{Port: "8443", TLSCert: "cert.pem"}, "must be set together"},
+		{"http2 without tls", Config{Port: "8080", HTTP2: true}, "SERVER_HTTP2 requires"},
+		{"confirmation", Config{Port: "8080", MutationsRequireConfirmation: true, ConfirmationTTL: time.Minute}, ""},
+		{"confirmation without ttl", Config{Port: "8080", MutationsRequireConfirmation: true}, "SERVER_CONFIRMATION_TTL must be positive"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -6,8 +6,10 @@
 //
 // # Configuration
 //
-// The Config struct defines the HTTP port, API key, and the target emulator
-// (Arcturus, Plus, Comet).
+// The Config struct defines the listen address (host and port), optional TLS
+// certificate and key, HTTP/2, the API key, and the target emulator (Arcturus,
+// Plus, Comet). HTTP/2 needs TLS and is served through net/http, since fasthttp
+// only speaks HTTP/1.1.
 //
 // # Usage
 //
@@ -0,0 +1,144 @@
+package snapshot
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// ErrReadOnly is returned by every Client operation that would change storage.
+var ErrReadOnly = errors.New("snapshot storage is read-only")
+
+// Client is a read-only storage client over the listing and files of a snapshot.
+// It ignores bucket names: a snapshot holds a single storage.
+type Client struct {
+	// objects holds the files of the snapshot by object key.
+	objects map[string][]byte
+	// listed holds the keys of the storage listing.
+	listed map[string]bool
+}
+
+// loadListing adds the keys of a storage listing, one per line. Blank lines are skipped.
+func (c *Client) loadListing(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if key := strings.TrimSpace(scanner.Text()); key != "" {
+			c.listed[key] = true
+		}
+	}
+	return scanner.Err()
+}
+
+// loadObject adds a file served at key.
+func (c *Client) loadObject(key string, r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	c.objects[key] = data
+	return nil
+}
+
+// BucketExists reports every bucket as present.
+func (c *Client) BucketExists(ctx context.Context, bucketName string) (bool, error) {
+	return true, nil
+}
+
+// MakeBucket fails with ErrReadOnly.
+func (c *Client) MakeBucket(ctx context.Context, bucketName string, opts minio.MakeBucketOptions) error {
+	return ErrReadOnly
+}
+
+// PutObject fails with ErrReadOnly.
+func (c *Client) PutObject(ctx context.Context, bucketName, objectName string, reader io.Reader, objectSize int64, opts minio.PutObjectOptions) (minio.UploadInfo, error) {
+	return minio.UploadInfo{}, ErrReadOnly
+}
+
+// GetObject returns a file of the snapshot, or a NoSuchKey error response.
+func (c *Client) GetObject(ctx context.Context, bucketName, objectName string, opts minio.GetObjectOptions) (io.ReadCloser, error) {
+	data, ok := c.objects[objectName]
+	if !ok {
+		return nil, minio.ErrorResponse{
+			Code:       "NoSuchKey",
+			Message:    "The specified key does not exist in the snapshot.",
+			Key:        objectName,
+			StatusCode: http.StatusNotFound,
+		}
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+// ListObjects lists the keys of the storage listing and files under opts.Prefix, in
+// key order. Without opts.Recursive, keys below the next "/" are folded into one
+// folder entry, as S3 does.
+func (c *Client) ListObjects(ctx context.Context, bucketName string, opts minio.ListObjectsOptions) <-chan minio.ObjectInfo {
+	entries := make(map[string]minio.ObjectInfo)
+	add := func(key string, size int64) {
+		if !strings.HasPrefix(key, opts.Prefix) {
+			return
+		}
+		if !opts.Recursive {
+			if i := strings.Index(key[len(opts.Prefix):], "/"); i >= 0 {
+				folder := key[:len(opts.Prefix)+i+1]
+				entries[folder] = minio.ObjectInfo{Key: folder}
+				return
+			}
+		}
+		entries[key] = minio.ObjectInfo{Key: key, Size: size}
+	}
+	for key := range c.listed {
+		add(key, 0)
+	}
+	for key, data := range c.objects {
+		add(key, int64(len(data)))
+	}
+
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	ch := make(chan minio.ObjectInfo, len(keys))
+	for _, key := range keys {
+		ch <- entries[key]
+	}
+	close(ch)
+	return ch
+}
+
+// RemoveObject fails with ErrReadOnly.
+func (c *Client) RemoveObject(ctx context.Context, bucketName, objectName string, opts minio.RemoveObjectOptions) error {
+	return ErrReadOnly
+}
+
+// RemoveObjects reports every object as failed with ErrReadOnly.
+func (c *Client) RemoveObjects(ctx context.Context, bucketName string, objectsCh <-chan minio.ObjectInfo, opts minio.RemoveObjectsOptions) <-chan minio.RemoveObjectError {
+	errCh := make(chan minio.RemoveObjectError)
+	go func() {
+		defer close(errCh)
+		for obj := range objectsCh {
+			errCh <- minio.RemoveObjectError{ObjectName: obj.Key, Err: ErrReadOnly}
+		}
+	}()
+	return errCh
+}
+
+// PresignedGetObject fails with ErrReadOnly: snapshot files have no URL.
+func (c *Client) PresignedGetObject(ctx context.Context, bucketName, objectName string, expiry time.Duration, reqParams url.Values) (*url.URL, error) {
+	return nil, ErrReadOnly
+}
+
+// PresignedPutObject fails with ErrReadOnly.
+func (c *Client) PresignedPutObject(ctx context.Context, bucketName, objectName string, expiry time.Duration) (*url.URL, error) {
+	return nil, ErrReadOnly
+}
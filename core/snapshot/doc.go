@@ -0,0 +1,21 @@
+// Package snapshot reads and writes exported copies of a hotel's data so reconciles
+// can run offline.
+//
+// A snapshot is a tar archive, gzipped or not, holding what the reconcile engine reads
+// from the live systems:
+//
+//   - db/<table>.csv: the rows of one database table, with a header row of column names.
+//     A \N cell is NULL, as in MySQL exports.
+//   - storage.txt: the storage listing, one object key per line.
+//   - manifest.json (optional): the emulator whose schema the tables follow.
+//
+// Every other file is served as a storage object under its path in the archive, e.g.
+// gamedata/FurnitureData.json.
+//
+// # Components
+//
+//   - Snapshot: The tables, loaded into an in-memory SQLite database, and a Client.
+//   - Client: A read-only storage.Client answering from the listing and files. Buckets
+//     are ignored, and every write fails with ErrReadOnly.
+//   - Writer: Writes a gzipped archive that Read loads back, e.g. from live systems.
+package snapshot
@@ -0,0 +1,208 @@
+package snapshot
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"asset-manager/core/json"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const (
+	manifestFile = "manifest.json"
+	listingFile  = "storage.txt"
+	tablesDir    = "db/"
+
+	// nullCell is how MySQL exports write NULL.
+	nullCell = `\N`
+)
+
+// Manifest describes where a snapshot was exported from.
+type Manifest struct {
+	// Emulator is the server profile the tables follow; empty uses the configured one.
+	Emulator string `json:"emulator,omitempty"`
+	// ExportedAt is when the snapshot was taken, if known.
+	ExportedAt *time.Time `json:"exported_at,omitempty"`
+}
+
+// Snapshot is an exported copy of a hotel's database tables and storage.
+type Snapshot struct {
+	Manifest Manifest
+	// DB holds one table per CSV file of the snapshot.
+	DB *gorm.DB
+	// Client serves the storage listing and files of the snapshot.
+	Client *Client
+	// Tables lists the loaded tables in archive order.
+	Tables []string
+}
+
+// Open reads the snapshot archive at path.
+func Open(path string) (*Snapshot, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open snapshot: %w", err)
+	}
+	defer file.Close()
+	return Read(file)
+}
+
+// Read reads a snapshot archive, gzipped or not. The snapshot must be closed.
+func Read(r io.Reader) (*Snapshot, error) {
+	buffered := bufio.NewReader(r)
+	var archive io.Reader = buffered
+	if magic, _ := buffered.Peek(2); bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		gz, err := gzip.NewReader(buffered)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open snapshot: %w", err)
+		}
+		defer gz.Close()
+		archive = gz
+	}
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open snapshot database: %w", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sql.DB: %w", err)
+	}
+	// Every connection to :memory: opens its own empty database
+	sqlDB.SetMaxOpenConns(1)
+
+	s := &Snapshot{DB: db, Client: &Client{objects: make(map[string][]byte), listed: make(map[string]bool)}}
+	if err := s.load(archive); err != nil {
+		sqlDB.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// Close releases the snapshot database.
+func (s *Snapshot) Close() error {
+	sqlDB, err := s.DB.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
+
+// load fills the snapshot from the entries of a tar archive.
+func (s *Snapshot) load(archive io.Reader) error {
+	tr := tar.NewReader(archive)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read snapshot: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		name := strings.TrimPrefix(path.Clean(header.Name), "./")
+		switch {
+		case name == manifestFile:
+			err = s.loadManifest(tr)
+		case name == listingFile:
+			err = s.Client.loadListing(tr)
+		case strings.HasPrefix(name, tablesDir) && path.Ext(name) == ".csv":
+			table := strings.TrimSuffix(strings.TrimPrefix(name, tablesDir), ".csv")
+			if strings.Contains(table, "/") {
+				return fmt.Errorf("invalid table path %s", name)
+			}
+			err = s.loadTable(table, tr)
+			s.Tables = append(s.Tables, table)
+		default:
+			err = s.Client.loadObject(name, tr)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to load %s: %w", name, err)
+		}
+	}
+
+	if len(s.Tables) == 0 {
+		return fmt.Errorf("snapshot holds no %s*.csv table", tablesDir)
+	}
+	return nil
+}
+
+// loadManifest parses manifest.json.
+func (s *Snapshot) loadManifest(r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, &s.Manifest)
+}
+
+// loadTable creates table with the columns of the CSV header and inserts its rows.
+// Columns are untyped, so cells keep the text they were exported as.
+func (s *Snapshot) loadTable(table string, r io.Reader) error {
+	reader := csv.NewReader(r)
+	columns, err := reader.Read()
+	if err == io.EOF {
+		return fmt.Errorf("missing header row")
+	}
+	if err != nil {
+		return err
+	}
+
+	quoted := make([]string, len(columns))
+	seen := make(map[string]bool, len(columns))
+	for i, column := range columns {
+		// Spreadsheet exports may start with a byte order mark
+		column = strings.TrimSpace(strings.TrimPrefix(column, "\ufeff"))
+		if column == "" || seen[column] {
+			return fmt.Errorf("invalid header: empty or duplicate column %q", column)
+		}
+		seen[column] = true
+		quoted[i] = quoteIdent(column)
+	}
+
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(fmt.Sprintf("CREATE TABLE %s (%s)", quoteIdent(table), strings.Join(quoted, ", "))).Error; err != nil {
+			return err
+		}
+		insert := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
+			quoteIdent(table), strings.Join(quoted, ", "), strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", "))
+
+		for {
+			record, err := reader.Read()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			values := make([]any, len(record))
+			for i, cell := range record {
+				if cell != nullCell {
+					values[i] = cell
+				}
+			}
+			if err := tx.Exec(insert, values...).Error; err != nil {
+				return err
+			}
+		}
+	})
+}
+
+// quoteIdent quotes a table or column name for SQLite.
+func quoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
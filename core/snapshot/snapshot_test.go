@@ -0,0 +1,163 @@
+package snapshot
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// buildArchive writes files into a tar archive, gzipped when zipped is set.
+func buildArchive(t *testing.T, zipped bool, files map[string]string) []byte {
+	var buf bytes.Buffer
+	var w io.Writer = &buf
+	var gz *gzip.Writer
+	if zipped {
+		gz = gzip.NewWriter(&buf)
+		w = gz
+	}
+	tw := tar.NewWriter(w)
+	for name, content := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content))}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	if gz != nil {
+		require.NoError(t, gz.Close())
+	}
+	return buf.Bytes()
+}
+
+// testFiles is a small furniture snapshot.
+var testFiles = map[string]string{
+	"manifest.json":               `{"emulator": "comet"}`,
+	"db/furniture.csv":            "\ufeffid,sprite_id,item_name,public_name\n1,100,chair,\"Chair, red\"\n2,200,table,\\N\n",
+	"storage.txt":                 "bundled/furniture/chair.nitro\n\nbundled/furniture/old/lamp.nitro\n",
+	"gamedata/FurnitureData.json": `{"roomitemtypes":{"furnitype":[]}}`,
+}
+
+// TestRead tests that tables, listing, files and manifest are loaded, from gzipped
+// and plain archives alike.
+func TestRead(t *testing.T) {
+	for _, zipped := range []bool{true, false} {
+		snap, err := Read(bytes.NewReader(buildArchive(t, zipped, testFiles)))
+		require.NoError(t, err)
+
+		assert.Equal(t, "comet", snap.Manifest.Emulator)
+		assert.Equal(t, []string{"furniture"}, snap.Tables)
+
+		var rows []struct {
+			SpriteID   string
+			PublicName *string
+		}
+		require.NoError(t, snap.DB.Raw("SELECT sprite_id, public_name FROM furniture ORDER BY id").Scan(&rows).Error)
+		require.Len(t, rows, 2)
+		assert.Equal(t, "100", rows[0].SpriteID)
+		assert.Equal(t, "Chair, red", *rows[0].PublicName)
+		assert.Nil(t, rows[1].PublicName)
+		assert.NoError(t, snap.Close())
+	}
+}
+
+// TestRead_Invalid tests that archives without tables or with broken CSV are refused.
+func TestRead_Invalid(t *testing.T) {
+	_, err := Read(bytes.NewReader(buildArchive(t, true, map[string]string{"storage.txt": "a.nitro\n"})))
+	assert.ErrorContains(t, err, "no db/*.csv table")
+
+	_, err = Read(bytes.NewReader(buildArchive(t, true, map[string]string{"db/items_base.csv": "id,id\n1,2\n"})))
+	assert.ErrorContains(t, err, "duplicate column")
+
+	_, err = Read(bytes.NewReader(buildArchive(t, true, map[string]string{"db/items_base.csv": "id,sprite_id\n1\n"})))
+	assert.ErrorContains(t, err, "failed to load db/items_base.csv")
+}
+
+// TestClient tests that the client serves the listing and files and refuses writes.
+func TestClient(t *testing.T) {
+	snap, err := Read(bytes.NewReader(buildArchive(t, true, testFiles)))
+	require.NoError(t, err)
+	defer snap.Close()
+	ctx := context.Background()
+
+	keys := func(opts minio.ListObjectsOptions) []string {
+		var listed []string
+		for obj := range snap.Client.ListObjects(ctx, "any", opts) {
+			listed = append(listed, obj.Key)
+		}
+		return listed
+	}
+	assert.Equal(t, []string{"bundled/furniture/chair.nitro", "bundled/furniture/old/lamp.nitro"},
+		keys(minio.ListObjectsOptions{Prefix: "bundled/furniture/", Recursive: true}))
+	assert.Equal(t, []string{"bundled/furniture/chair.nitro", "bundled/furniture/old/"},
+		keys(minio.ListObjectsOptions{Prefix: "bundled/furniture/"}))
+	assert.Equal(t, []string{"gamedata/FurnitureData.json"}, keys(minio.ListObjectsOptions{Prefix: "gamedata/", Recursive: true}))
+
+	reader, err := snap.Client.GetObject(ctx, "any", "gamedata/FurnitureData.json", minio.GetObjectOptions{})
+	require.NoError(t, err)
+	data, _ := io.ReadAll(reader)
+	assert.Equal(t, testFiles["gamedata/FurnitureData.json"], string(data))
+
+	// Listed objects have no content in a snapshot
+	_, err = snap.Client.GetObject(ctx, "any", "bundled/furniture/chair.nitro", minio.GetObjectOptions{})
+	var resp minio.ErrorResponse
+	require.True(t, errors.As(err, &resp))
+	assert.Equal(t, "NoSuchKey", resp.Code)
+
+	_, err = snap.Client.PutObject(ctx, "any", "a", bytes.NewReader(nil), 0, minio.PutObjectOptions{})
+	assert.ErrorIs(t, err, ErrReadOnly)
+	assert.ErrorIs(t, snap.Client.RemoveObject(ctx, "any", "a", minio.RemoveObjectOptions{}), ErrReadOnly)
+}
+
+// TestWriter tests that an exported table, listing and file read back unchanged,
+// NULLs included.
+func TestWriter(t *testing.T) {
+	source, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, source.Exec("CREATE TABLE items_base (id INTEGER, item_name TEXT, public_name TEXT)").Error)
+	require.NoError(t, source.Exec(`INSERT INTO items_base VALUES (1, 'chair', 'Chair, "red"'), (2, 'table', NULL)`).Error)
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	now := time.Now()
+	require.NoError(t, w.WriteManifest(Manifest{Emulator: "arcturus", ExportedAt: &now}))
+	rows, err := w.WriteTable(context.Background(), source, "items_base")
+	require.NoError(t, err)
+	assert.Equal(t, 2, rows)
+	require.NoError(t, w.WriteFile("gamedata/FurnitureData.json", []byte(`{}`)))
+	require.NoError(t, w.WriteListing([]string{"bundled/furniture/chair.nitro"}))
+	require.NoError(t, w.Close())
+
+	snap, err := Read(&buf)
+	require.NoError(t, err)
+	defer snap.Close()
+	assert.Equal(t, "arcturus", snap.Manifest.Emulator)
+	assert.NotNil(t, snap.Manifest.ExportedAt)
+	assert.Equal(t, []string{"items_base"}, snap.Tables)
+
+	var read []struct {
+		ItemName   string
+		PublicName *string
+	}
+	require.NoError(t, snap.DB.Raw("SELECT item_name, public_name FROM items_base ORDER BY id").Scan(&read).Error)
+	require.Len(t, read, 2)
+	assert.Equal(t, `Chair, "red"`, *read[0].PublicName)
+	assert.Equal(t, "table", read[1].ItemName)
+	assert.Nil(t, read[1].PublicName)
+
+	var listed []string
+	for obj := range snap.Client.ListObjects(context.Background(), "", minio.ListObjectsOptions{Recursive: true}) {
+		listed = append(listed, obj.Key)
+	}
+	assert.Equal(t, []string{"bundled/furniture/chair.nitro", "gamedata/FurnitureData.json"}, listed)
+}
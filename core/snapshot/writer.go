@@ -0,0 +1,126 @@
+package snapshot
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"asset-manager/core/json"
+
+	"gorm.io/gorm"
+)
+
+// Writer writes a gzipped snapshot archive that Read loads back. Only what is passed
+// in is written: no connection details, credentials or bucket names.
+type Writer struct {
+	gz *gzip.Writer
+	tw *tar.Writer
+	// modTime stamps every archive entry.
+	modTime time.Time
+}
+
+// NewWriter starts a snapshot archive on w. Close must be called to finish it.
+func NewWriter(w io.Writer) *Writer {
+	gz := gzip.NewWriter(w)
+	return &Writer{gz: gz, tw: tar.NewWriter(gz), modTime: time.Now()}
+}
+
+// WriteManifest writes manifest.json.
+func (w *Writer) WriteManifest(m Manifest) error {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	return w.WriteFile(manifestFile, data)
+}
+
+// WriteTable writes every row of table to db/<table>.csv and returns the row count.
+// NULL is written as \N, as in MySQL exports.
+func (w *Writer) WriteTable(ctx context.Context, db *gorm.DB, table string) (int, error) {
+	rows, err := db.WithContext(ctx).Table(table).Rows()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read table %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read columns of %s: %w", table, err)
+	}
+
+	var buf bytes.Buffer
+	out := csv.NewWriter(&buf)
+	if err := out.Write(columns); err != nil {
+		return 0, err
+	}
+
+	values := make([]sql.NullString, len(columns))
+	dest := make([]any, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	record := make([]string, len(columns))
+	count := 0
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return count, fmt.Errorf("failed to read row of %s: %w", table, err)
+		}
+		for i, value := range values {
+			record[i] = nullCell
+			if value.Valid {
+				record[i] = value.String
+			}
+		}
+		if err := out.Write(record); err != nil {
+			return count, err
+		}
+		count++
+	}
+	if err := rows.Err(); err != nil {
+		return count, fmt.Errorf("failed to read table %s: %w", table, err)
+	}
+	out.Flush()
+	if err := out.Error(); err != nil {
+		return count, err
+	}
+
+	return count, w.WriteFile(tablesDir+table+".csv", buf.Bytes())
+}
+
+// WriteListing writes the storage listing, one object key per line.
+func (w *Writer) WriteListing(keys []string) error {
+	var buf strings.Builder
+	for _, key := range keys {
+		buf.WriteString(key)
+		buf.WriteByte('\n')
+	}
+	return w.WriteFile(listingFile, []byte(buf.String()))
+}
+
+// WriteFile writes data at name, which Read serves as a storage object unless it is
+// one of the reserved files.
+func (w *Writer) WriteFile(name string, data []byte) error {
+	header := &tar.Header{Name: name, Mode: 0o644, Size: int64(len(data)), ModTime: w.modTime}
+	if err := w.tw.WriteHeader(header); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	if _, err := w.tw.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+// Close finishes the archive. It does not close the underlying writer.
+func (w *Writer) Close() error {
+	if err := w.tw.Close(); err != nil {
+		return err
+	}
+	return w.gz.Close()
+}
@@ -0,0 +1,134 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"asset-manager/core/json"
+	"asset-manager/core/reconcile"
+	"asset-manager/core/storage"
+
+	"gorm.io/gorm"
+)
+
+// auditRecord is the persisted form of reconcile.AuditEntry.
+type auditRecord struct {
+	ID      uint      `gorm:"primaryKey"`
+	Time    time.Time `gorm:"index"`
+	Adapter string
+	Trigger string
+	Action  string
+	Key     string
+	Fields  string
+	Reason  string
+	Outcome string
+	Error   string
+	PlanID  string `gorm:"index"`
+	RunID   string `gorm:"index"`
+	// Versions holds the replaced object versions as JSON.
+	Versions string
+}
+
+// TableName overrides the table name for audit records.
+func (auditRecord) TableName() string {
+	return "reconcile_audit_log"
+}
+
+// AuditStore implements reconcile.AuditStore on top of the state database.
+type AuditStore struct {
+	db *gorm.DB
+}
+
+// NewAuditStore creates an audit store and migrates its table.
+func NewAuditStore(db *gorm.DB) (*AuditStore, error) {
+	if err := db.AutoMigrate(&auditRecord{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate audit table: %w", err)
+	}
+	return &AuditStore{db: db}, nil
+}
+
+// RecordAudit appends entries to the audit log.
+func (s *AuditStore) RecordAudit(ctx context.Context, entries []reconcile.AuditEntry) error {
+	records := make([]auditRecord, 0, len(entries))
+	for _, e := range entries {
+		var versions string
+		if len(e.Versions) > 0 {
+			data, err := json.Marshal(e.Versions)
+			if err != nil {
+				return fmt.Errorf("failed to encode audit versions: %w", err)
+			}
+			versions = string(data)
+		}
+		records = append(records, auditRecord{
+			Time:     e.Time,
+			Adapter:  e.Adapter,
+			Trigger:  e.Trigger,
+			Action:   string(e.Action),
+			Key:      e.Key,
+			Fields:   strings.Join(e.Fields, ","),
+			Reason:   e.Reason,
+			Outcome:  e.Outcome,
+			Error:    e.Error,
+			PlanID:   e.PlanID,
+			RunID:    e.RunID,
+			Versions: versions,
+		})
+	}
+
+	if err := s.db.WithContext(ctx).CreateInBatches(records, 500).Error; err != nil {
+		return fmt.Errorf("failed to save audit entries: %w", err)
+	}
+	return nil
+}
+
+// ListAudit returns the most recent audit entries, newest first.
+func (s *AuditStore) ListAudit(ctx context.Context, limit int) ([]reconcile.AuditEntry, error) {
+	var records []auditRecord
+	if err := s.db.WithContext(ctx).Order("id DESC").Limit(limit).Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("failed to load audit entries: %w", err)
+	}
+	return toAuditEntries(records)
+}
+
+// PlanAudit returns the audit entries of an applied plan, oldest first.
+func (s *AuditStore) PlanAudit(ctx context.Context, planID string) ([]reconcile.AuditEntry, error) {
+	var records []auditRecord
+	if err := s.db.WithContext(ctx).Where("plan_id = ?", planID).Order("id").Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("failed to load audit entries: %w", err)
+	}
+	return toAuditEntries(records)
+}
+
+// toAuditEntries converts persisted records back to audit entries.
+func toAuditEntries(records []auditRecord) ([]reconcile.AuditEntry, error) {
+	entries := make([]reconcile.AuditEntry, 0, len(records))
+	for _, r := range records {
+		var fields []string
+		if r.Fields != "" {
+			fields = strings.Split(r.Fields, ",")
+		}
+		var versions []storage.ObjectVersion
+		if r.Versions != "" {
+			if err := json.Unmarshal([]byte(r.Versions), &versions); err != nil {
+				return nil, fmt.Errorf("failed to decode audit versions: %w", err)
+			}
+		}
+		entries = append(entries, reconcile.AuditEntry{
+			Time:     r.Time,
+			Adapter:  r.Adapter,
+			Trigger:  r.Trigger,
+			Action:   reconcile.ActionType(r.Action),
+			Key:      r.Key,
+			Fields:   fields,
+			Reason:   r.Reason,
+			Outcome:  r.Outcome,
+			Error:    r.Error,
+			PlanID:   r.PlanID,
+			RunID:    r.RunID,
+			Versions: versions,
+		})
+	}
+	return entries, nil
+}
@@ -0,0 +1,11 @@
+package state
+
+// Config holds configuration for the local state store.
+type Config struct {
+	// Path is the SQLite file used for persistent state. Empty disables persistence.
+	Path string `mapstructure:"path" default:"data/state.db"`
+
+	// RunsKeep is the number of reconcile runs kept per adapter for history and diffs,
+	// and of applied plan statuses.
+	RunsKeep int `mapstructure:"runs_keep" default:"90"`
+}
@@ -0,0 +1,21 @@
+// Package state provides the local persistent store for Asset Manager's own data.
+//
+// Unlike core/database, which connects to the emulator's MySQL database, this package
+// owns a small SQLite file holding data that must survive between runs but does not
+// belong in the emulator schema.
+//
+// # Stores
+//
+//   - HistoryStore: Per-entity reconcile health used for flapping detection.
+//   - AuditStore: Append-only log of actions applied without human confirmation, and of
+//     the object versions applied plans replaced (read back by plan ID for undo).
+//   - IgnoreStore: Per-entity ignores, with reason and optional expiry, left out of reconcile plans.
+//   - TriageStore: Staff workflow state (acknowledged, assignee, note) merged into reports.
+//   - ReportStore: The most recent reports of scheduled integrity checks, kept as JSON.
+//   - RunStore: The summary and per-entity issues of recent reconcile runs, for history and diffs.
+//
+// # Configuration
+//
+// The file location is set via STATE_PATH. An empty path disables persistence.
+// STATE_RUNS_KEEP sets how many reconcile runs are kept per adapter.
+package state
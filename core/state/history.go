@@ -0,0 +1,83 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"asset-manager/core/reconcile"
+
+	"gorm.io/gorm"
+)
+
+// itemStateRecord is the persisted form of reconcile.ItemState.
+type itemStateRecord struct {
+	Adapter     string `gorm:"primaryKey"`
+	Key         string `gorm:"primaryKey"`
+	Healthy     bool
+	Transitions int
+	LastChanged time.Time
+}
+
+// TableName overrides the table name for item states.
+func (itemStateRecord) TableName() string {
+	return "reconcile_item_states"
+}
+
+// HistoryStore implements reconcile.HistoryStore on top of the state database.
+type HistoryStore struct {
+	db *gorm.DB
+}
+
+// NewHistoryStore creates a history store and migrates its table.
+func NewHistoryStore(db *gorm.DB) (*HistoryStore, error) {
+	if err := db.AutoMigrate(&itemStateRecord{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate history table: %w", err)
+	}
+	return &HistoryStore{db: db}, nil
+}
+
+// LoadItemStates returns the last known state of every entity for the adapter.
+func (s *HistoryStore) LoadItemStates(ctx context.Context, adapter string) (map[string]reconcile.ItemState, error) {
+	var records []itemStateRecord
+	if err := s.db.WithContext(ctx).Where("adapter = ?", adapter).Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("failed to load item states: %w", err)
+	}
+
+	states := make(map[string]reconcile.ItemState, len(records))
+	for _, r := range records {
+		states[r.Key] = reconcile.ItemState{
+			Healthy:     r.Healthy,
+			Transitions: r.Transitions,
+			LastChanged: r.LastChanged,
+		}
+	}
+	return states, nil
+}
+
+// SaveItemStates replaces the stored states for the adapter in a single transaction.
+func (s *HistoryStore) SaveItemStates(ctx context.Context, adapter string, states map[string]reconcile.ItemState) error {
+	records := make([]itemStateRecord, 0, len(states))
+	for key, st := range states {
+		records = append(records, itemStateRecord{
+			Adapter:     adapter,
+			Key:         key,
+			Healthy:     st.Healthy,
+			Transitions: st.Transitions,
+			LastChanged: st.LastChanged,
+		})
+	}
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("adapter = ?", adapter).Delete(&itemStateRecord{}).Error; err != nil {
+			return fmt.Errorf("failed to clear item states: %w", err)
+		}
+		if len(records) == 0 {
+			return nil
+		}
+		if err := tx.CreateInBatches(records, 500).Error; err != nil {
+			return fmt.Errorf("failed to save item states: %w", err)
+		}
+		return nil
+	})
+}
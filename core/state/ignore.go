@@ -0,0 +1,78 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"asset-manager/core/reconcile"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ignoreRecord is the persisted form of reconcile.IgnoreEntry.
+type ignoreRecord struct {
+	Adapter   string `gorm:"primaryKey"`
+	Key       string `gorm:"primaryKey"`
+	Reason    string
+	CreatedAt time.Time
+	ExpiresAt *time.Time `gorm:"index"`
+}
+
+// TableName overrides the table name for ignore entries.
+func (ignoreRecord) TableName() string {
+	return "reconcile_ignores"
+}
+
+// IgnoreStore implements reconcile.IgnoreStore on top of the state database.
+type IgnoreStore struct {
+	db *gorm.DB
+}
+
+// NewIgnoreStore creates an ignore store and migrates its table.
+func NewIgnoreStore(db *gorm.DB) (*IgnoreStore, error) {
+	if err := db.AutoMigrate(&ignoreRecord{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate ignore table: %w", err)
+	}
+	return &IgnoreStore{db: db}, nil
+}
+
+// SaveIgnore adds an ignore, replacing any existing one for the same entity.
+func (s *IgnoreStore) SaveIgnore(ctx context.Context, entry reconcile.IgnoreEntry) error {
+	record := ignoreRecord{
+		Adapter:   entry.Adapter,
+		Key:       entry.Key,
+		Reason:    entry.Reason,
+		CreatedAt: entry.CreatedAt,
+		ExpiresAt: entry.ExpiresAt,
+	}
+	if err := s.db.WithContext(ctx).Clauses(clause.OnConflict{UpdateAll: true}).Create(&record).Error; err != nil {
+		return fmt.Errorf("failed to save ignore: %w", err)
+	}
+	return nil
+}
+
+// LoadIgnores returns the ignores of the adapter that have not expired at now.
+func (s *IgnoreStore) LoadIgnores(ctx context.Context, adapter string, now time.Time) ([]reconcile.IgnoreEntry, error) {
+	var records []ignoreRecord
+	err := s.db.WithContext(ctx).
+		Where("adapter = ? AND (expires_at IS NULL OR expires_at > ?)", adapter, now).
+		Order("key").
+		Find(&records).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load ignores: %w", err)
+	}
+
+	entries := make([]reconcile.IgnoreEntry, 0, len(records))
+	for _, r := range records {
+		entries = append(entries, reconcile.IgnoreEntry{
+			Adapter:   r.Adapter,
+			Key:       r.Key,
+			Reason:    r.Reason,
+			CreatedAt: r.CreatedAt,
+			ExpiresAt: r.ExpiresAt,
+		})
+	}
+	return entries, nil
+}
@@ -0,0 +1,99 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"asset-manager/core/json"
+	"asset-manager/core/reconcile"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// planStatusRecord is the persisted form of reconcile.PlanStatus; the action lists are
+// kept as JSON with the rest of the status.
+type planStatusRecord struct {
+	PlanID  string `gorm:"primaryKey"`
+	RunID   string `gorm:"index"`
+	Adapter string `gorm:"index"`
+	Time    time.Time
+	State   string
+	Status  string
+}
+
+// TableName overrides the table name for plan statuses.
+func (planStatusRecord) TableName() string {
+	return "reconcile_plan_statuses"
+}
+
+// PlanStatusStore implements reconcile.PlanStatusStore on top of the state database.
+type PlanStatusStore struct {
+	db   *gorm.DB
+	keep int
+}
+
+// NewPlanStatusStore creates a plan status store keeping the statuses of the last keep
+// applied plans of each adapter and migrates its table. A keep below one keeps only
+// the latest one.
+func NewPlanStatusStore(db *gorm.DB, keep int) (*PlanStatusStore, error) {
+	if err := db.AutoMigrate(&planStatusRecord{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate plan status table: %w", err)
+	}
+	return &PlanStatusStore{db: db, keep: max(keep, 1)}, nil
+}
+
+// SavePlanStatus stores status, replacing an earlier status of the same plan, and
+// deletes the statuses of its adapter beyond the retained count.
+func (s *PlanStatusStore) SavePlanStatus(ctx context.Context, status *reconcile.PlanStatus) error {
+	data, err := json.Marshal(status)
+	if err != nil {
+		return fmt.Errorf("failed to encode plan status: %w", err)
+	}
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		record := planStatusRecord{
+			PlanID:  status.PlanID,
+			RunID:   status.RunID,
+			Adapter: status.Adapter,
+			Time:    status.Time,
+			State:   status.State,
+			Status:  string(data),
+		}
+		if err := tx.Clauses(clause.OnConflict{UpdateAll: true}).Create(&record).Error; err != nil {
+			return fmt.Errorf("failed to save plan status: %w", err)
+		}
+
+		var oldest []string
+		err := tx.Model(&planStatusRecord{}).Where("adapter = ?", status.Adapter).Order("time DESC").Offset(s.keep).Pluck("plan_id", &oldest).Error
+		if err != nil {
+			return fmt.Errorf("failed to list old plan statuses: %w", err)
+		}
+		if len(oldest) > 0 {
+			if err := tx.Where("plan_id IN ?", oldest).Delete(&planStatusRecord{}).Error; err != nil {
+				return fmt.Errorf("failed to delete old plan statuses: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// LoadPlanStatus returns the status of the plan, or reconcile.ErrPlanStatusNotFound.
+func (s *PlanStatusStore) LoadPlanStatus(ctx context.Context, planID string) (*reconcile.PlanStatus, error) {
+	var record planStatusRecord
+	err := s.db.WithContext(ctx).Where("plan_id = ?", planID).Take(&record).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, reconcile.ErrPlanStatusNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load plan status: %w", err)
+	}
+
+	status := &reconcile.PlanStatus{}
+	if err := json.Unmarshal([]byte(record.Status), status); err != nil {
+		return nil, fmt.Errorf("failed to decode plan %s status: %w", planID, err)
+	}
+	return status, nil
+}
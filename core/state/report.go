@@ -0,0 +1,86 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"asset-manager/core/json"
+
+	"gorm.io/gorm"
+)
+
+// reportRecord is one stored report, kept as JSON.
+type reportRecord struct {
+	ID     uint   `gorm:"primaryKey"`
+	Kind   string `gorm:"index"`
+	Time   time.Time
+	Report string
+}
+
+// TableName overrides the table name for stored reports.
+func (reportRecord) TableName() string {
+	return "integrity_reports"
+}
+
+// ReportStore keeps the most recent reports of each kind, such as those of scheduled
+// integrity checks.
+type ReportStore struct {
+	db   *gorm.DB
+	keep int
+}
+
+// NewReportStore creates a report store keeping the last keep reports of each kind
+// and migrates its table. A keep below one keeps only the latest report.
+func NewReportStore(db *gorm.DB, keep int) (*ReportStore, error) {
+	if err := db.AutoMigrate(&reportRecord{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate report table: %w", err)
+	}
+	return &ReportStore{db: db, keep: max(keep, 1)}, nil
+}
+
+// SaveReport stores report as the latest of kind and deletes the reports of kind
+// beyond the retained count.
+func (s *ReportStore) SaveReport(ctx context.Context, kind string, at time.Time, report any) error {
+	data, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("failed to encode report: %w", err)
+	}
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&reportRecord{Kind: kind, Time: at, Report: string(data)}).Error; err != nil {
+			return fmt.Errorf("failed to save report: %w", err)
+		}
+
+		var oldest []uint
+		err := tx.Model(&reportRecord{}).Where("kind = ?", kind).Order("id DESC").Offset(s.keep).Pluck("id", &oldest).Error
+		if err != nil {
+			return fmt.Errorf("failed to list old reports: %w", err)
+		}
+		if len(oldest) > 0 {
+			if err := tx.Delete(&reportRecord{}, oldest).Error; err != nil {
+				return fmt.Errorf("failed to delete old reports: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// LatestReport decodes the latest report of kind into report. It returns false when
+// no report of kind is stored.
+func (s *ReportStore) LatestReport(ctx context.Context, kind string, report any) (bool, error) {
+	var record reportRecord
+	err := s.db.WithContext(ctx).Where("kind = ?", kind).Order("id DESC").Take(&record).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to load report: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(record.Report), report); err != nil {
+		return false, fmt.Errorf("failed to decode report: %w", err)
+	}
+	return true, nil
+}
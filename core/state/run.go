@@ -0,0 +1,138 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"asset-manager/core/json"
+	"asset-manager/core/reconcile"
+
+	"gorm.io/gorm"
+)
+
+// runRecord is the persisted form of reconcile.Run; summary and items are kept as JSON.
+type runRecord struct {
+	ID      uint64 `gorm:"primaryKey"`
+	RunID   string `gorm:"index"`
+	Adapter string `gorm:"index"`
+	Time    time.Time
+	Summary string
+	Items   string
+}
+
+// TableName overrides the table name for stored runs.
+func (runRecord) TableName() string {
+	return "reconcile_runs"
+}
+
+// RunStore implements reconcile.RunStore on top of the state database.
+type RunStore struct {
+	db   *gorm.DB
+	keep int
+}
+
+// NewRunStore creates a run store keeping the last keep runs of each adapter and
+// migrates its table. A keep below one keeps only the latest run.
+func NewRunStore(db *gorm.DB, keep int) (*RunStore, error) {
+	if err := db.AutoMigrate(&runRecord{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate run table: %w", err)
+	}
+	return &RunStore{db: db, keep: max(keep, 1)}, nil
+}
+
+// SaveRun stores run, sets its ID and deletes the runs of its adapter beyond the
+// retained count.
+func (s *RunStore) SaveRun(ctx context.Context, run *reconcile.Run) error {
+	summary, err := json.Marshal(run.Summary)
+	if err != nil {
+		return fmt.Errorf("failed to encode run summary: %w", err)
+	}
+	items, err := json.Marshal(run.Items)
+	if err != nil {
+		return fmt.Errorf("failed to encode run items: %w", err)
+	}
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		record := runRecord{RunID: run.RunID, Adapter: run.Adapter, Time: run.Time, Summary: string(summary), Items: string(items)}
+		if err := tx.Create(&record).Error; err != nil {
+			return fmt.Errorf("failed to save run: %w", err)
+		}
+		run.ID = record.ID
+
+		var oldest []uint64
+		err := tx.Model(&runRecord{}).Where("adapter = ?", run.Adapter).Order("id DESC").Offset(s.keep).Pluck("id", &oldest).Error
+		if err != nil {
+			return fmt.Errorf("failed to list old runs: %w", err)
+		}
+		if len(oldest) > 0 {
+			if err := tx.Delete(&runRecord{}, oldest).Error; err != nil {
+				return fmt.Errorf("failed to delete old runs: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// ListRuns returns at most limit runs of the adapter, newest first, without items.
+func (s *RunStore) ListRuns(ctx context.Context, adapter string, limit int) ([]reconcile.Run, error) {
+	var records []runRecord
+	err := s.db.WithContext(ctx).
+		Select("id, run_id, adapter, time, summary").
+		Where("adapter = ?", adapter).
+		Order("id DESC").
+		Limit(limit).
+		Find(&records).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list runs: %w", err)
+	}
+
+	runs := make([]reconcile.Run, 0, len(records))
+	for _, record := range records {
+		run, err := record.decode(false)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, *run)
+	}
+	return runs, nil
+}
+
+// LoadRun returns the run of the adapter with the given ID, or reconcile.ErrRunNotFound.
+func (s *RunStore) LoadRun(ctx context.Context, adapter string, id uint64) (*reconcile.Run, error) {
+	return s.loadRun(s.db.WithContext(ctx).Where("adapter = ? AND id = ?", adapter, id))
+}
+
+// LoadRunAt returns the latest run of the adapter finished at or before t, or
+// reconcile.ErrRunNotFound.
+func (s *RunStore) LoadRunAt(ctx context.Context, adapter string, t time.Time) (*reconcile.Run, error) {
+	return s.loadRun(s.db.WithContext(ctx).Where("adapter = ? AND time <= ?", adapter, t.UTC()).Order("time DESC, id DESC"))
+}
+
+// loadRun decodes the first run query selects.
+func (s *RunStore) loadRun(query *gorm.DB) (*reconcile.Run, error) {
+	var record runRecord
+	err := query.Take(&record).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, reconcile.ErrRunNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load run: %w", err)
+	}
+	return record.decode(true)
+}
+
+// decode converts the record to a run, with its items when withItems is set.
+func (r runRecord) decode(withItems bool) (*reconcile.Run, error) {
+	run := &reconcile.Run{ID: r.ID, RunID: r.RunID, Adapter: r.Adapter, Time: r.Time}
+	if err := json.Unmarshal([]byte(r.Summary), &run.Summary); err != nil {
+		return nil, fmt.Errorf("failed to decode run %d summary: %w", r.ID, err)
+	}
+	if withItems && r.Items != "" {
+		if err := json.Unmarshal([]byte(r.Items), &run.Items); err != nil {
+			return nil, fmt.Errorf("failed to decode run %d items: %w", r.ID, err)
+		}
+	}
+	return run, nil
+}
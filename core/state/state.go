@@ -0,0 +1,42 @@
+package state
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Open opens (and creates if needed) the SQLite state database.
+// It returns an error if the path is empty or the file cannot be opened.
+func Open(cfg Config) (*gorm.DB, error) {
+	if cfg.Path == "" {
+		return nil, fmt.Errorf("state path is not configured")
+	}
+
+	if dir := filepath.Dir(cfg.Path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create state directory: %w", err)
+		}
+	}
+
+	db, err := gorm.Open(sqlite.Open(cfg.Path), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open state database: %w", err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sql.DB: %w", err)
+	}
+
+	// SQLite allows a single writer; serialize access to avoid lock errors
+	sqlDB.SetMaxOpenConns(1)
+
+	return db, nil
+}
@@ -0,0 +1,283 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"asset-manager/core/reconcile"
+	"asset-manager/core/storage"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestOpen_EmptyPath tests that an empty path is rejected.
+func TestOpen_EmptyPath(t *testing.T) {
+	_, err := Open(Config{})
+	assert.Error(t, err)
+}
+
+// TestHistoryStore_RoundTrip tests saving and loading item states.
+func TestHistoryStore_RoundTrip(t *testing.T) {
+	db, err := Open(Config{Path: filepath.Join(t.TempDir(), "nested", "state.db")})
+	assert.NoError(t, err)
+
+	store, err := NewHistoryStore(db)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	changed := time.Now().UTC().Truncate(time.Second)
+	states := map[string]reconcile.ItemState{
+		"1": {Healthy: true, Transitions: 2, LastChanged: changed},
+		"2": {Healthy: false, Transitions: 0, LastChanged: changed},
+	}
+
+	assert.NoError(t, store.SaveItemStates(ctx, "furniture", states))
+	assert.NoError(t, store.SaveItemStates(ctx, "other", map[string]reconcile.ItemState{"9": {}}))
+
+	loaded, err := store.LoadItemStates(ctx, "furniture")
+	assert.NoError(t, err)
+	assert.Len(t, loaded, 2)
+	assert.Equal(t, 2, loaded["1"].Transitions)
+	assert.True(t, loaded["1"].LastChanged.Equal(changed))
+
+	// Saving again replaces the previous set
+	assert.NoError(t, store.SaveItemStates(ctx, "furniture", map[string]reconcile.ItemState{"3": {Healthy: true}}))
+	loaded, err = store.LoadItemStates(ctx, "furniture")
+	assert.NoError(t, err)
+	assert.Len(t, loaded, 1)
+	assert.Contains(t, loaded, "3")
+}
+
+// TestAuditStore_RoundTrip tests appending and listing audit entries.
+func TestAuditStore_RoundTrip(t *testing.T) {
+	db, err := Open(Config{Path: filepath.Join(t.TempDir(), "state.db")})
+	assert.NoError(t, err)
+
+	store, err := NewAuditStore(db)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+	assert.NoError(t, store.RecordAudit(ctx, []reconcile.AuditEntry{
+		{Time: now, Adapter: "furniture", Trigger: "safe-fix", Action: reconcile.ActionSyncDB, Key: "1", Fields: []string{"width", "length"}, Outcome: reconcile.AuditApplied},
+		{Time: now, Adapter: "furniture", Trigger: "safe-fix", Action: reconcile.ActionSyncDB, Key: "2", Outcome: reconcile.AuditFailed, Error: "boom", RunID: "run-1"},
+	}))
+
+	entries, err := store.ListAudit(ctx, 10)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "2", entries[0].Key)
+	assert.Equal(t, "boom", entries[0].Error)
+	assert.Equal(t, "run-1", entries[0].RunID)
+	assert.Nil(t, entries[0].Fields)
+	assert.Equal(t, []string{"width", "length"}, entries[1].Fields)
+	assert.True(t, entries[1].Time.Equal(now))
+}
+
+// TestAuditStore_PlanAudit tests reading the entries and versions of one plan.
+func TestAuditStore_PlanAudit(t *testing.T) {
+	db, err := Open(Config{Path: filepath.Join(t.TempDir(), "state.db")})
+	assert.NoError(t, err)
+
+	store, err := NewAuditStore(db)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	versions := []storage.ObjectVersion{{Bucket: "assets", Key: "chair.nitro", VersionID: "v1"}}
+	assert.NoError(t, store.RecordAudit(ctx, []reconcile.AuditEntry{
+		{Adapter: "furniture", Trigger: reconcile.ApplyTrigger, Action: reconcile.ActionApplyPlan, Key: "p1", PlanID: "p1", Versions: versions},
+		{Adapter: "furniture", Trigger: reconcile.ApplyTrigger, Action: reconcile.ActionApplyPlan, Key: "p2", PlanID: "p2"},
+	}))
+
+	entries, err := store.PlanAudit(ctx, "p1")
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, versions, entries[0].Versions)
+
+	entries, err = store.PlanAudit(ctx, "unknown")
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+// TestIgnoreStore_RoundTrip tests saving, replacing and expiring ignores.
+func TestIgnoreStore_RoundTrip(t *testing.T) {
+	db, err := Open(Config{Path: filepath.Join(t.TempDir(), "state.db")})
+	assert.NoError(t, err)
+
+	store, err := NewIgnoreStore(db)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+	expired, later := now.Add(-time.Hour), now.Add(time.Hour)
+
+	assert.NoError(t, store.SaveIgnore(ctx, reconcile.IgnoreEntry{Adapter: "furniture", Key: "1", Reason: "first", CreatedAt: now}))
+	assert.NoError(t, store.SaveIgnore(ctx, reconcile.IgnoreEntry{Adapter: "furniture", Key: "1", Reason: "custom item", CreatedAt: now, ExpiresAt: &later}))
+	assert.NoError(t, store.SaveIgnore(ctx, reconcile.IgnoreEntry{Adapter: "furniture", Key: "2", Reason: "old", CreatedAt: now, ExpiresAt: &expired}))
+	assert.NoError(t, store.SaveIgnore(ctx, reconcile.IgnoreEntry{Adapter: "other", Key: "3", CreatedAt: now}))
+
+	entries, err := store.LoadIgnores(ctx, "furniture", now)
+	assert.NoError(t, err)
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, "1", entries[0].Key)
+		assert.Equal(t, "custom item", entries[0].Reason)
+		if assert.NotNil(t, entries[0].ExpiresAt) {
+			assert.True(t, later.Equal(*entries[0].ExpiresAt))
+		}
+	}
+}
+
+// TestTriageStore_RoundTrip tests saving, replacing and clearing triage.
+func TestTriageStore_RoundTrip(t *testing.T) {
+	db, err := Open(Config{Path: filepath.Join(t.TempDir(), "state.db")})
+	assert.NoError(t, err)
+
+	store, err := NewTriageStore(db)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	assert.NoError(t, store.SaveTriage(ctx, "furniture", "1", reconcile.Triage{Acknowledged: true, UpdatedAt: now}))
+	assert.NoError(t, store.SaveTriage(ctx, "furniture", "1", reconcile.Triage{Acknowledged: true, AssignedTo: "alice", Note: "waiting for file", UpdatedAt: now}))
+	assert.NoError(t, store.SaveTriage(ctx, "furniture", "2", reconcile.Triage{Note: "check", UpdatedAt: now}))
+	assert.NoError(t, store.SaveTriage(ctx, "furniture", "2", reconcile.Triage{}))
+
+	triage, err := store.LoadTriage(ctx, "furniture")
+	assert.NoError(t, err)
+	assert.Len(t, triage, 1)
+	assert.Equal(t, "alice", triage["1"].AssignedTo)
+	assert.Equal(t, "waiting for file", triage["1"].Note)
+	assert.True(t, triage["1"].Acknowledged)
+}
+
+// TestReportStore_LatestAndRetention tests reading the latest report and pruning old ones.
+func TestReportStore_LatestAndRetention(t *testing.T) {
+	db, err := Open(Config{Path: filepath.Join(t.TempDir(), "state.db")})
+	assert.NoError(t, err)
+
+	store, err := NewReportStore(db, 2)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	var report map[string]int
+	found, err := store.LatestReport(ctx, "furniture", &report)
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	now := time.Now()
+	for i := 1; i <= 3; i++ {
+		assert.NoError(t, store.SaveReport(ctx, "furniture", now, map[string]int{"run": i}))
+	}
+	assert.NoError(t, store.SaveReport(ctx, "other", now, map[string]int{"run": 9}))
+
+	found, err = store.LatestReport(ctx, "furniture", &report)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, 3, report["run"])
+
+	var count int64
+	assert.NoError(t, db.Model(&reportRecord{}).Where("kind = ?", "furniture").Count(&count).Error)
+	assert.Equal(t, int64(2), count)
+	assert.NoError(t, db.Model(&reportRecord{}).Where("kind = ?", "other").Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+}
+
+// TestRunStore_RoundTrip tests storing runs, looking them up by ID and time, and pruning old ones.
+func TestRunStore_RoundTrip(t *testing.T) {
+	db, err := Open(Config{Path: filepath.Join(t.TempDir(), "state.db")})
+	assert.NoError(t, err)
+
+	store, err := NewRunStore(db, 2)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	day := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	var ids []uint64
+	for i := 0; i < 3; i++ {
+		run := &reconcile.Run{
+			RunID:   fmt.Sprintf("run-%d", i),
+			Adapter: "furniture",
+			Time:    day.AddDate(0, 0, i),
+			Summary: reconcile.PlanSummary{TotalItems: 10 + i},
+			Items:   []reconcile.RunItem{{Key: "1", Name: "chair", Issues: []string{"missing_storage"}}},
+		}
+		assert.NoError(t, store.SaveRun(ctx, run))
+		ids = append(ids, run.ID)
+	}
+	assert.NoError(t, store.SaveRun(ctx, &reconcile.Run{Adapter: "other", Time: day}))
+
+	runs, err := store.ListRuns(ctx, "furniture", 10)
+	assert.NoError(t, err)
+	assert.Len(t, runs, 2)
+	assert.Equal(t, ids[2], runs[0].ID)
+	assert.Equal(t, 12, runs[0].Summary.TotalItems)
+	assert.Equal(t, "run-2", runs[0].RunID)
+	assert.Empty(t, runs[0].Items)
+
+	run, err := store.LoadRun(ctx, "furniture", ids[1])
+	assert.NoError(t, err)
+	assert.Equal(t, []reconcile.RunItem{{Key: "1", Name: "chair", Issues: []string{"missing_storage"}}}, run.Items)
+
+	// The first run was pruned, and runs of one adapter are not found through another
+	_, err = store.LoadRun(ctx, "furniture", ids[0])
+	assert.ErrorIs(t, err, reconcile.ErrRunNotFound)
+	_, err = store.LoadRun(ctx, "other", ids[1])
+	assert.ErrorIs(t, err, reconcile.ErrRunNotFound)
+
+	run, err = store.LoadRunAt(ctx, "furniture", day.AddDate(0, 0, 1).Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, ids[1], run.ID)
+	_, err = store.LoadRunAt(ctx, "furniture", day)
+	assert.ErrorIs(t, err, reconcile.ErrRunNotFound)
+}
+
+func TestPlanStatusStore_RoundTrip(t *testing.T) {
+	db, err := Open(Config{Path: filepath.Join(t.TempDir(), "state.db")})
+	assert.NoError(t, err)
+
+	store, err := NewPlanStatusStore(db, 2)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	_, err = store.LoadPlanStatus(ctx, "plan-0")
+	assert.ErrorIs(t, err, reconcile.ErrPlanStatusNotFound)
+
+	day := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, store.SavePlanStatus(ctx, &reconcile.PlanStatus{
+			PlanID:   fmt.Sprintf("plan-%d", i),
+			Adapter:  "furniture",
+			Time:     day.AddDate(0, 0, i),
+			State:    reconcile.PlanPartial,
+			Total:    2,
+			Executed: []reconcile.Action{{Type: reconcile.ActionDeleteDB, Key: "1"}},
+			Failed: []reconcile.FailedAction{{
+				Action: reconcile.Action{Type: reconcile.ActionDeleteStorage, Key: "2"},
+				Error:  "access denied",
+			}},
+		}))
+	}
+
+	// Saving a plan again replaces its status
+	assert.NoError(t, store.SavePlanStatus(ctx, &reconcile.PlanStatus{PlanID: "plan-2", Adapter: "furniture", Time: day.AddDate(0, 0, 3), State: reconcile.PlanApplied}))
+
+	_, err = store.LoadPlanStatus(ctx, "plan-0")
+	assert.ErrorIs(t, err, reconcile.ErrPlanStatusNotFound)
+
+	status, err := store.LoadPlanStatus(ctx, "plan-1")
+	assert.NoError(t, err)
+	assert.Equal(t, reconcile.PlanPartial, status.State)
+	assert.Equal(t, []reconcile.Action{{Type: reconcile.ActionDeleteDB, Key: "1"}}, status.Executed)
+	if assert.Len(t, status.Failed, 1) {
+		assert.Equal(t, "2", status.Failed[0].Key)
+		assert.Equal(t, "access denied", status.Failed[0].Error)
+	}
+
+	status, err = store.LoadPlanStatus(ctx, "plan-2")
+	assert.NoError(t, err)
+	assert.Equal(t, reconcile.PlanApplied, status.State)
+}
@@ -0,0 +1,83 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"asset-manager/core/reconcile"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// triageRecord is the persisted form of reconcile.Triage.
+type triageRecord struct {
+	Adapter      string `gorm:"primaryKey"`
+	Key          string `gorm:"primaryKey"`
+	Acknowledged bool
+	AssignedTo   string
+	Note         string
+	UpdatedAt    time.Time
+}
+
+// TableName overrides the table name for triage records.
+func (triageRecord) TableName() string {
+	return "reconcile_triage"
+}
+
+// TriageStore implements reconcile.TriageStore on top of the state database.
+type TriageStore struct {
+	db *gorm.DB
+}
+
+// NewTriageStore creates a triage store and migrates its table.
+func NewTriageStore(db *gorm.DB) (*TriageStore, error) {
+	if err := db.AutoMigrate(&triageRecord{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate triage table: %w", err)
+	}
+	return &TriageStore{db: db}, nil
+}
+
+// SaveTriage stores the triage of an entity; a zero triage deletes it.
+func (s *TriageStore) SaveTriage(ctx context.Context, adapter, key string, triage reconcile.Triage) error {
+	db := s.db.WithContext(ctx)
+	if triage.IsZero() {
+		if err := db.Where("adapter = ? AND key = ?", adapter, key).Delete(&triageRecord{}).Error; err != nil {
+			return fmt.Errorf("failed to clear triage: %w", err)
+		}
+		return nil
+	}
+
+	record := triageRecord{
+		Adapter:      adapter,
+		Key:          key,
+		Acknowledged: triage.Acknowledged,
+		AssignedTo:   triage.AssignedTo,
+		Note:         triage.Note,
+		UpdatedAt:    triage.UpdatedAt,
+	}
+	if err := db.Clauses(clause.OnConflict{UpdateAll: true}).Create(&record).Error; err != nil {
+		return fmt.Errorf("failed to save triage: %w", err)
+	}
+	return nil
+}
+
+// LoadTriage returns the triage of every entity of the adapter, by key.
+func (s *TriageStore) LoadTriage(ctx context.Context, adapter string) (map[string]reconcile.Triage, error) {
+	var records []triageRecord
+	if err := s.db.WithContext(ctx).Where("adapter = ?", adapter).Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("failed to load triage: %w", err)
+	}
+
+	triage := make(map[string]reconcile.Triage, len(records))
+	for _, r := range records {
+		triage[r.Key] = reconcile.Triage{
+			Acknowledged: r.Acknowledged,
+			AssignedTo:   r.AssignedTo,
+			Note:         r.Note,
+			UpdatedAt:    r.UpdatedAt,
+		}
+	}
+	return triage, nil
+}
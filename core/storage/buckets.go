@@ -0,0 +1,32 @@
+package storage
+
+// GamedataFolder is the top-level folder holding gamedata files.
+const GamedataFolder = "gamedata"
+
+// Buckets names the bucket used for each purpose. Deployments may keep gamedata and
+// bundled assets in separate buckets, or everything in one. Object keys are the same
+// either way (e.g. "gamedata/FurnitureData.json").
+type Buckets struct {
+	// Assets holds bundled assets and every folder other than gamedata.
+	Assets string
+	// Gamedata holds the gamedata folder.
+	Gamedata string
+}
+
+// SingleBucket returns Buckets that keep everything in one bucket.
+func SingleBucket(name string) Buckets {
+	return Buckets{Assets: name, Gamedata: name}
+}
+
+// ForFolder returns the bucket holding a top-level folder.
+func (b Buckets) ForFolder(folder string) string {
+	if folder == GamedataFolder {
+		return b.Gamedata
+	}
+	return b.Assets
+}
+
+// Split reports whether gamedata lives in its own bucket.
+func (b Buckets) Split() bool {
+	return b.Gamedata != b.Assets
+}
@@ -0,0 +1,19 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigBuckets(t *testing.T) {
+	single := Config{Bucket: "assets"}.Buckets()
+	assert.Equal(t, SingleBucket("assets"), single)
+	assert.False(t, single.Split())
+	assert.Equal(t, "assets", single.ForFolder(GamedataFolder))
+
+	split := Config{Bucket: "assets", GamedataBucket: "gamedata"}.Buckets()
+	assert.True(t, split.Split())
+	assert.Equal(t, "gamedata", split.ForFolder(GamedataFolder))
+	assert.Equal(t, "assets", split.ForFolder("bundled"))
+}
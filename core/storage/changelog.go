@@ -0,0 +1,294 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"asset-manager/core/json"
+
+	"github.com/minio/minio-go/v7"
+	"go.uber.org/zap"
+)
+
+// Change operations recorded in the change log.
+const (
+	ChangePut    = "put"
+	ChangeRemove = "remove"
+)
+
+// ChangeEntry is one line of the change log.
+type ChangeEntry struct {
+	Time   time.Time `json:"time"`
+	Op     string    `json:"op"`
+	Bucket string    `json:"bucket"`
+	Key    string    `json:"key"`
+	// Size is the written size in bytes; omitted for removals.
+	Size int64 `json:"size,omitempty"`
+	// Host is the machine that issued the change.
+	Host string `json:"host,omitempty"`
+}
+
+// changeLogClient records successful writes and removals of the wrapped client.
+type changeLogClient struct {
+	Client
+	prefix string
+	host   string
+	// mu serializes the read-modify-write of the log objects within this process.
+	mu  sync.Mutex
+	now func() time.Time
+}
+
+// WithChangeLog wraps client so every successful PutObject, RemoveObject and
+// RemoveObjects appends an entry to <prefix>YYYY-MM-DD.ndjson in the changed bucket.
+//
+// S3 has no append, so each write rewrites the day's log object, conditioned on the
+// ETag it read and retried when another process wrote in between. Under a context from
+// WithChangeBuffer the entries are held and written once at Flush, so bulk operations
+// do not rewrite the log for every change. The log is history, not an audit trail:
+// failures to write it are logged and never fail the change itself. Internal objects (any
+// path segment starting with a dot, such as the run lock) and the log itself are skipped.
+func WithChangeLog(client Client, prefix string) Client {
+	host, _ := os.Hostname()
+	return &changeLogClient{Client: client, prefix: prefix, host: host, now: time.Now}
+}
+
+// PutObject uploads an object and records it.
+func (c *changeLogClient) PutObject(ctx context.Context, bucketName, objectName string, reader io.Reader, objectSize int64, opts minio.PutObjectOptions) (minio.UploadInfo, error) {
+	info, err := c.Client.PutObject(ctx, bucketName, objectName, reader, objectSize, opts)
+	if err == nil {
+		size := info.Size
+		if size == 0 {
+			size = objectSize
+		}
+		c.record(ctx, bucketName, ChangeEntry{Op: ChangePut, Key: objectName, Size: size})
+	}
+	return info, err
+}
+
+// RemoveObject deletes an object and records it.
+func (c *changeLogClient) RemoveObject(ctx context.Context, bucketName, objectName string, opts minio.RemoveObjectOptions) error {
+	err := c.Client.RemoveObject(ctx, bucketName, objectName, opts)
+	if err == nil {
+		c.record(ctx, bucketName, ChangeEntry{Op: ChangeRemove, Key: objectName})
+	}
+	return err
+}
+
+// RemoveObjects deletes objects and records the ones removed, in one log write once
+// the batch is done.
+func (c *changeLogClient) RemoveObjects(ctx context.Context, bucketName string, objectsCh <-chan minio.ObjectInfo, opts minio.RemoveObjectsOptions) <-chan minio.RemoveObjectError {
+	// Forward the objects, noting the ones handed over. stop ends the forwarding when
+	// the removal ends early (e.g. a cancelled context) so nothing blocks.
+	var names []string
+	forwarded := make(chan minio.ObjectInfo)
+	stop, done := make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(done)
+		defer close(forwarded)
+		for {
+			select {
+			case object, ok := <-objectsCh:
+				if !ok {
+					return
+				}
+				select {
+				case forwarded <- object:
+					names = append(names, object.Key)
+				case <-stop:
+					return
+				}
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	errs := c.Client.RemoveObjects(ctx, bucketName, forwarded, opts)
+	out := make(chan minio.RemoveObjectError)
+	go func() {
+		defer close(out)
+		failed := make(map[string]bool)
+		for removeErr := range errs {
+			failed[removeErr.ObjectName] = true
+			out <- removeErr
+		}
+		close(stop)
+		<-done
+
+		entries := make([]ChangeEntry, 0, len(names))
+		for _, name := range names {
+			if !failed[name] {
+				entries = append(entries, ChangeEntry{Op: ChangeRemove, Key: name})
+			}
+		}
+		c.record(ctx, bucketName, entries...)
+	}()
+	return out
+}
+
+// record stamps entries and appends them to the day's log of bucket, or holds them in
+// the ChangeBuffer of ctx until it is flushed.
+func (c *changeLogClient) record(ctx context.Context, bucket string, entries ...ChangeEntry) {
+	now := c.now().UTC()
+	stamped := make([]ChangeEntry, 0, len(entries))
+	for _, entry := range entries {
+		if c.skip(entry.Key) {
+			continue
+		}
+		entry.Time, entry.Bucket, entry.Host = now, bucket, c.host
+		stamped = append(stamped, entry)
+	}
+	if len(stamped) == 0 {
+		return
+	}
+
+	if buffer := changeBufferFrom(ctx); buffer != nil {
+		buffer.add(c, bucket, stamped)
+		return
+	}
+	c.write(ctx, bucket, stamped)
+}
+
+// write appends entries to the daily logs of bucket, one write per day.
+func (c *changeLogClient) write(ctx context.Context, bucket string, entries []ChangeEntry) {
+	var days []string
+	lines := make(map[string]*bytes.Buffer)
+	for _, entry := range entries {
+		data, err := json.Marshal(entry)
+		if err != nil {
+			continue
+		}
+		key := c.prefix + entry.Time.Format("2006-01-02") + ".ndjson"
+		if lines[key] == nil {
+			days = append(days, key)
+			lines[key] = &bytes.Buffer{}
+		}
+		lines[key].Write(data)
+		lines[key].WriteByte('\n')
+	}
+
+	for _, key := range days {
+		if err := c.appendObject(ctx, bucket, key, lines[key].Bytes()); err != nil {
+			zap.L().Warn("Failed to write storage change log", zap.String("bucket", bucket), zap.String("key", key), zap.Error(err))
+		}
+	}
+}
+
+// skip reports whether key is the log itself or an internal object.
+func (c *changeLogClient) skip(key string) bool {
+	if strings.HasPrefix(key, c.prefix) {
+		return true
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if strings.HasPrefix(segment, ".") {
+			return true
+		}
+	}
+	return false
+}
+
+// changeLogAttempts is how often an append is retried after losing to another writer.
+const changeLogAttempts = 5
+
+// appendObject rewrites bucket/key with lines appended to its current content. The
+// write is conditioned on the ETag that was read (or on the log not existing yet) and
+// retried when another writer changed the log in between.
+func (c *changeLogClient) appendObject(ctx context.Context, bucket, key string, lines []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var err error
+	for attempt := 0; attempt < changeLogAttempts; attempt++ {
+		var existing []byte
+		opts := minio.PutObjectOptions{ContentType: "application/x-ndjson"}
+		// GetObject is lazy on minio: a missing log only fails once it is read
+		reader, readErr := c.Client.GetObject(ctx, bucket, key, minio.GetObjectOptions{})
+		if readErr == nil {
+			existing, readErr = io.ReadAll(reader)
+			if readErr == nil {
+				if etag := ObjectETag(reader); etag != "" {
+					opts.SetMatchETag(etag)
+				}
+			}
+			reader.Close()
+		}
+		if IsNoSuchKey(readErr) {
+			existing, readErr = nil, nil
+			opts.SetMatchETagExcept("*")
+		}
+		if readErr != nil {
+			return fmt.Errorf("failed to read change log: %w", readErr)
+		}
+
+		data := append(existing, lines...)
+		_, err = c.Client.PutObject(ctx, bucket, key, bytes.NewReader(data), int64(len(data)), opts)
+		if !IsPreconditionFailed(err) {
+			return err
+		}
+	}
+	return fmt.Errorf("change log kept changing after %d attempts: %w", changeLogAttempts, err)
+}
+
+// ChangeBuffer holds the change log entries recorded under WithChangeBuffer until
+// Flush writes them.
+type ChangeBuffer struct {
+	ctx     context.Context
+	mu      sync.Mutex
+	pending []pendingChanges
+}
+
+// pendingChanges are the buffered entries of one log client and bucket.
+type pendingChanges struct {
+	client  *changeLogClient
+	bucket  string
+	entries []ChangeEntry
+}
+
+// changeBufferKey is the context key of the active ChangeBuffer.
+type changeBufferKey struct{}
+
+// WithChangeBuffer returns a context under which a client wrapped by WithChangeLog
+// holds its entries instead of writing them, so a bulk operation rewrites each daily
+// log once when Flush is called instead of once per change.
+func WithChangeBuffer(ctx context.Context) (context.Context, *ChangeBuffer) {
+	buffer := &ChangeBuffer{ctx: context.WithoutCancel(ctx)}
+	return context.WithValue(ctx, changeBufferKey{}, buffer), buffer
+}
+
+// changeBufferFrom returns the ChangeBuffer of ctx, or nil.
+func changeBufferFrom(ctx context.Context) *ChangeBuffer {
+	buffer, _ := ctx.Value(changeBufferKey{}).(*ChangeBuffer)
+	return buffer
+}
+
+// add holds entries of client for bucket.
+func (b *ChangeBuffer) add(client *changeLogClient, bucket string, entries []ChangeEntry) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for i := range b.pending {
+		if b.pending[i].client == client && b.pending[i].bucket == bucket {
+			b.pending[i].entries = append(b.pending[i].entries, entries...)
+			return
+		}
+	}
+	b.pending = append(b.pending, pendingChanges{client: client, bucket: bucket, entries: entries})
+}
+
+// Flush writes the held entries, one append per daily log, and empties the buffer.
+// It runs even when the context the buffer came from was cancelled.
+func (b *ChangeBuffer) Flush() {
+	b.mu.Lock()
+	pending := b.pending
+	b.pending = nil
+	b.mu.Unlock()
+
+	for _, changes := range pending {
+		changes.client.write(b.ctx, changes.bucket, changes.entries)
+	}
+}
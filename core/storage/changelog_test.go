@@ -0,0 +1,209 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"asset-manager/core/json"
+	"asset-manager/core/storage/mocks"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const testChangeLog = "_changes/2026-10-16.ndjson"
+
+// newTestChangeLog wraps a mock whose change log starts with existing and returns
+// the wrapped client and a function reading the log written last.
+func newTestChangeLog(t *testing.T, existing string) (*mocks.Client, Client, func() []ChangeEntry) {
+	inner := new(mocks.Client)
+	if existing == "" {
+		inner.On("GetObject", mock.Anything, "assets", testChangeLog, mock.Anything).
+			Return(io.ReadCloser(nil), minio.ErrorResponse{Code: "NoSuchKey"})
+	} else {
+		inner.On("GetObject", mock.Anything, "assets", testChangeLog, mock.Anything).
+			Return(io.NopCloser(strings.NewReader(existing)), nil)
+	}
+
+	var written []byte
+	inner.On("PutObject", mock.Anything, "assets", testChangeLog, mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			written, _ = io.ReadAll(args.Get(3).(io.Reader))
+		}).
+		Return(minio.UploadInfo{}, nil)
+
+	client := WithChangeLog(inner, "_changes/")
+	client.(*changeLogClient).now = func() time.Time {
+		return time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	}
+
+	read := func() []ChangeEntry {
+		var entries []ChangeEntry
+		for _, line := range strings.Split(strings.TrimSpace(string(written)), "\n") {
+			var entry ChangeEntry
+			require.NoError(t, json.Unmarshal([]byte(line), &entry))
+			entries = append(entries, entry)
+		}
+		return entries
+	}
+	return inner, client, read
+}
+
+func TestChangeLog_PutObject(t *testing.T) {
+	inner, client, read := newTestChangeLog(t, `{"op":"put","key":"earlier"}`+"\n")
+	inner.On("PutObject", mock.Anything, "assets", "bundled/furniture/chair.nitro", mock.Anything, int64(5), mock.Anything).
+		Return(minio.UploadInfo{}, nil)
+
+	_, err := client.PutObject(context.Background(), "assets", "bundled/furniture/chair.nitro", bytes.NewReader([]byte("nitro")), 5, minio.PutObjectOptions{})
+	require.NoError(t, err)
+
+	entries := read()
+	require.Len(t, entries, 2)
+	assert.Equal(t, "earlier", entries[0].Key)
+	assert.Equal(t, ChangePut, entries[1].Op)
+	assert.Equal(t, "assets", entries[1].Bucket)
+	assert.Equal(t, "bundled/furniture/chair.nitro", entries[1].Key)
+	assert.Equal(t, int64(5), entries[1].Size)
+}
+
+func TestChangeLog_FailedAndInternalChangesAreSkipped(t *testing.T) {
+	inner, client, _ := newTestChangeLog(t, "")
+	inner.On("RemoveObject", mock.Anything, "assets", "missing.nitro", mock.Anything).Return(errors.New("denied"))
+	inner.On("RemoveObject", mock.Anything, "assets", ".locks/reconcile.lock", mock.Anything).Return(nil)
+
+	assert.Error(t, client.RemoveObject(context.Background(), "assets", "missing.nitro", minio.RemoveObjectOptions{}))
+	assert.NoError(t, client.RemoveObject(context.Background(), "assets", ".locks/reconcile.lock", minio.RemoveObjectOptions{}))
+	inner.AssertNotCalled(t, "PutObject", mock.Anything, "assets", testChangeLog, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestChangeLog_RemoveObjects(t *testing.T) {
+	inner, client, read := newTestChangeLog(t, "")
+	errs := make(chan minio.RemoveObjectError, 1)
+	inner.On("RemoveObjects", mock.Anything, "assets", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			objects := args.Get(2).(<-chan minio.ObjectInfo)
+			go func() {
+				defer close(errs)
+				for object := range objects {
+					if object.Key == "b.nitro" {
+						errs <- minio.RemoveObjectError{ObjectName: object.Key, Err: errors.New("denied")}
+					}
+				}
+			}()
+		}).
+		Return((<-chan minio.RemoveObjectError)(errs))
+
+	objects := make(chan minio.ObjectInfo, 3)
+	for _, key := range []string{"a.nitro", "b.nitro", "c.nitro"} {
+		objects <- minio.ObjectInfo{Key: key}
+	}
+	close(objects)
+
+	var failed []string
+	for removeErr := range client.RemoveObjects(context.Background(), "assets", objects, minio.RemoveObjectsOptions{}) {
+		failed = append(failed, removeErr.ObjectName)
+	}
+	assert.Equal(t, []string{"b.nitro"}, failed)
+
+	entries := read()
+	require.Len(t, entries, 2)
+	assert.Equal(t, "a.nitro", entries[0].Key)
+	assert.Equal(t, "c.nitro", entries[1].Key)
+	assert.Equal(t, ChangeRemove, entries[1].Op)
+	inner.AssertNumberOfCalls(t, "PutObject", 1)
+}
+
+func TestChangeLog_BufferedChangesAreWrittenOnce(t *testing.T) {
+	inner, client, read := newTestChangeLog(t, "")
+	inner.On("PutObject", mock.Anything, "assets", mock.MatchedBy(func(key string) bool {
+		return strings.HasSuffix(key, ".nitro")
+	}), mock.Anything, mock.Anything, mock.Anything).Return(minio.UploadInfo{}, nil)
+	inner.On("RemoveObject", mock.Anything, "assets", "c.nitro", mock.Anything).Return(nil)
+
+	ctx, buffer := WithChangeBuffer(context.Background())
+	for _, key := range []string{"a.nitro", "b.nitro"} {
+		_, err := client.PutObject(ctx, "assets", key, bytes.NewReader([]byte("nitro")), 5, minio.PutObjectOptions{})
+		require.NoError(t, err)
+	}
+	require.NoError(t, client.RemoveObject(ctx, "assets", "c.nitro", minio.RemoveObjectOptions{}))
+	inner.AssertNotCalled(t, "PutObject", mock.Anything, "assets", testChangeLog, mock.Anything, mock.Anything, mock.Anything)
+
+	buffer.Flush()
+	entries := read()
+	require.Len(t, entries, 3)
+	assert.Equal(t, []string{"a.nitro", "b.nitro", "c.nitro"}, []string{entries[0].Key, entries[1].Key, entries[2].Key})
+	inner.AssertNumberOfCalls(t, "GetObject", 1)
+
+	buffer.Flush()
+	inner.AssertNumberOfCalls(t, "GetObject", 1)
+}
+
+func TestChangeLog_AppendRetriesWhenLogChanged(t *testing.T) {
+	inner := new(mocks.Client)
+	inner.On("GetObject", mock.Anything, "assets", testChangeLog, mock.Anything).
+		Return(&etagReader{Reader: strings.NewReader(`{"op":"put","key":"first"}` + "\n"), etag: "v1"}, nil).Once()
+	inner.On("GetObject", mock.Anything, "assets", testChangeLog, mock.Anything).
+		Return(&etagReader{Reader: strings.NewReader(`{"op":"put","key":"first"}` + "\n" + `{"op":"put","key":"other"}` + "\n"), etag: "v2"}, nil).Once()
+	inner.On("PutObject", mock.Anything, "assets", testChangeLog, mock.Anything, mock.Anything, mock.MatchedBy(func(opts minio.PutObjectOptions) bool {
+		return opts.Header().Get("If-Match") == `"v1"`
+	})).Return(minio.UploadInfo{}, minio.ErrorResponse{Code: "PreconditionFailed"}).Once()
+	var written []byte
+	inner.On("PutObject", mock.Anything, "assets", testChangeLog, mock.Anything, mock.Anything, mock.MatchedBy(func(opts minio.PutObjectOptions) bool {
+		return opts.Header().Get("If-Match") == `"v2"`
+	})).Run(func(args mock.Arguments) {
+		written, _ = io.ReadAll(args.Get(3).(io.Reader))
+	}).Return(minio.UploadInfo{}, nil).Once()
+	inner.On("RemoveObject", mock.Anything, "assets", "a.nitro", mock.Anything).Return(nil)
+
+	client := WithChangeLog(inner, "_changes/")
+	client.(*changeLogClient).now = func() time.Time {
+		return time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	}
+	require.NoError(t, client.RemoveObject(context.Background(), "assets", "a.nitro", minio.RemoveObjectOptions{}))
+
+	lines := strings.Split(strings.TrimSpace(string(written)), "\n")
+	require.Len(t, lines, 3)
+	assert.Contains(t, lines[1], `"other"`)
+	assert.Contains(t, lines[2], `"a.nitro"`)
+	inner.AssertExpectations(t)
+}
+
+// missingReader fails like a lazy minio object whose key does not exist.
+type missingReader struct{}
+
+func (missingReader) Read([]byte) (int, error) { return 0, minio.ErrorResponse{Code: "NoSuchKey"} }
+
+func (missingReader) Close() error { return nil }
+
+// TestChangeLog_FirstEntryOfTheDay tests that a log missing on read, as minio reports
+// it, is created instead of failing the append.
+func TestChangeLog_FirstEntryOfTheDay(t *testing.T) {
+	inner := new(mocks.Client)
+	inner.On("GetObject", mock.Anything, "assets", testChangeLog, mock.Anything).
+		Return(io.ReadCloser(missingReader{}), nil)
+	var written []byte
+	inner.On("PutObject", mock.Anything, "assets", testChangeLog, mock.Anything, mock.Anything, mock.MatchedBy(func(opts minio.PutObjectOptions) bool {
+		return opts.Header().Get("If-None-Match") == "*"
+	})).Run(func(args mock.Arguments) {
+		written, _ = io.ReadAll(args.Get(3).(io.Reader))
+	}).Return(minio.UploadInfo{}, nil).Once()
+	inner.On("RemoveObject", mock.Anything, "assets", "a.nitro", mock.Anything).Return(nil)
+
+	client := WithChangeLog(inner, "_changes/")
+	client.(*changeLogClient).now = func() time.Time {
+		return time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	}
+	require.NoError(t, client.RemoveObject(context.Background(), "assets", "a.nitro", minio.RemoveObjectOptions{}))
+
+	lines := strings.Split(strings.TrimSpace(string(written)), "\n")
+	require.Len(t, lines, 1)
+	assert.Contains(t, lines[0], `"a.nitro"`)
+	inner.AssertExpectations(t)
+}
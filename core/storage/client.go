@@ -6,6 +6,7 @@ import (
 	"io"
 	"net"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 
@@ -30,14 +31,91 @@ type Client interface {
 	// RemoveObjects deletes multiple objects from a bucket efficiently.
 	// objectsCh is a channel of object names to delete.
 	RemoveObjects(ctx context.Context, bucketName string, objectsCh <-chan minio.ObjectInfo, opts minio.RemoveObjectsOptions) <-chan minio.RemoveObjectError
+	// PresignedGetObject returns a URL that downloads an object without credentials until expiry.
+	// reqParams override response headers (e.g. response-content-disposition).
+	PresignedGetObject(ctx context.Context, bucketName, objectName string, expiry time.Duration, reqParams url.Values) (*url.URL, error)
+	// PresignedPutObject returns a URL that uploads an object without credentials until expiry.
+	PresignedPutObject(ctx context.Context, bucketName, objectName string, expiry time.Duration) (*url.URL, error)
 }
 
+// Addressing styles accepted by Config.Addressing.
+const (
+	AddressingAuto    = "auto"
+	AddressingPath    = "path"
+	AddressingVirtual = "virtual"
+)
+
+// RequesterPaysHeader is the header acknowledging that the requester is billed.
+const RequesterPaysHeader = "X-Amz-Request-Payer"
+
 // NewClient creates a new Minio client based on the configuration.
 func NewClient(cfg Config) (Client, error) {
 	// Minio expects endpoint without scheme
 	endpoint := strings.TrimPrefix(cfg.Endpoint, "http://")
 	endpoint = strings.TrimPrefix(endpoint, "https://")
 
+	lookup, err := bucketLookup(cfg.Addressing)
+	if err != nil {
+		return nil, err
+	}
+
+	minioClient, err := minio.New(endpoint, &minio.Options{
+		Creds:        credentials.NewStaticV4(cfg.AccessKey, cfg.SecretKey, ""),
+		Secure:       cfg.UseSSL,
+		Region:       cfg.Region,
+		Transport:    newTransport(cfg),
+		BucketLookup: lookup,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create minio client: %w", err)
+	}
+	// Note: Minio client performs lazy connection, so we can't ping here easily without a bucket check
+	// But ListBuckets or similar would verify. We rely on operation-level timeouts from Context for the rest.
+	// The transport timeouts ensure we don't hang on connection setup.
+
+	base := &minioClientWrapper{Client: minioClient, presigner: minioClient, requesterPays: cfg.RequesterPays}
+
+	// Presigned URLs are signed for the host clients reach, which may differ from the internal endpoint
+	if cfg.Presign.Endpoint != "" {
+		presignEndpoint, secure := presignHost(cfg.Presign.Endpoint, cfg.UseSSL)
+		base.presigner, err = minio.New(presignEndpoint, &minio.Options{
+			Creds:        credentials.NewStaticV4(cfg.AccessKey, cfg.SecretKey, ""),
+			Secure:       secure,
+			Region:       cfg.Region,
+			Transport:    newTransport(cfg),
+			BucketLookup: lookup,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create presign client: %w", err)
+		}
+	}
+	var client Client = base
+	if cfg.ChangeLog.Enabled {
+		client = WithChangeLog(client, cfg.ChangeLog.Prefix)
+	}
+	if cfg.History.Enabled {
+		client = WithHistory(client, cfg.History)
+	}
+	// Outside the change log and history, so their own writes are never recorded as replaced
+	// versions nor logged as mutations
+	return WithMutationLogging(WithVersionRecording(client, base)), nil
+}
+
+// presignHost strips the scheme from a public endpoint. An https:// or http:// scheme
+// selects TLS; without one the storage UseSSL setting applies.
+func presignHost(endpoint string, useSSL bool) (string, bool) {
+	switch {
+	case strings.HasPrefix(endpoint, "https://"):
+		return strings.TrimSuffix(strings.TrimPrefix(endpoint, "https://"), "/"), true
+	case strings.HasPrefix(endpoint, "http://"):
+		return strings.TrimSuffix(strings.TrimPrefix(endpoint, "http://"), "/"), false
+	default:
+		return strings.TrimSuffix(endpoint, "/"), useSSL
+	}
+}
+
+// newTransport builds the HTTP transport with the configured timeouts.
+func newTransport(cfg Config) *http.Transport {
 	// Ensure timeout defaults if not set
 	timeout := cfg.TimeoutSeconds
 	if timeout <= 0 {
@@ -45,8 +123,18 @@ func NewClient(cfg Config) (Client, error) {
 	}
 	timeoutDuration := time.Duration(timeout) * time.Second
 
+	responseTimeout := timeoutDuration
+	if cfg.ResponseTimeoutSeconds > 0 {
+		responseTimeout = time.Duration(cfg.ResponseTimeoutSeconds) * time.Second
+	}
+
+	idleTimeout := 90 * time.Second
+	if cfg.IdleTimeoutSeconds > 0 {
+		idleTimeout = time.Duration(cfg.IdleTimeoutSeconds) * time.Second
+	}
+
 	// Create custom transport with strict timeouts
-	transport := &http.Transport{
+	return &http.Transport{
 		Proxy: http.ProxyFromEnvironment,
 		DialContext: (&net.Dialer{
 			Timeout:   timeoutDuration, // Connection setup timeout
@@ -54,32 +142,79 @@ func NewClient(cfg Config) (Client, error) {
 		}).DialContext,
 		ForceAttemptHTTP2:     true,
 		MaxIdleConns:          100,
-		IdleConnTimeout:       90 * time.Second,
+		IdleConnTimeout:       idleTimeout,
 		TLSHandshakeTimeout:   timeoutDuration, // TLS Handshake timeout
 		ExpectContinueTimeout: 1 * time.Second,
-		ResponseHeaderTimeout: timeoutDuration, // Wait for first response byte timeout
+		ResponseHeaderTimeout: responseTimeout, // Wait for first response byte timeout
 	}
+}
 
-	minioClient, err := minio.New(endpoint, &minio.Options{
-		Creds:     credentials.NewStaticV4(cfg.AccessKey, cfg.SecretKey, ""),
-		Secure:    cfg.UseSSL,
-		Region:    cfg.Region,
-		Transport: transport,
-	})
-	if err != nil {
-		return nil, fmt.Errorf("failed to create minio client: %w", err)
+// bucketLookup maps an addressing style to the Minio bucket lookup type.
+func bucketLookup(addressing string) (minio.BucketLookupType, error) {
+	switch strings.ToLower(addressing) {
+	case "", AddressingAuto:
+		return minio.BucketLookupAuto, nil
+	case AddressingPath:
+		return minio.BucketLookupPath, nil
+	case AddressingVirtual:
+		return minio.BucketLookupDNS, nil
+	default:
+		return minio.BucketLookupAuto, fmt.Errorf("invalid storage addressing %q (want auto, path or virtual)", addressing)
 	}
-	// Note: Minio client performs lazy connection, so we can't ping here easily without a bucket check
-	// But ListBuckets or similar would verify. We rely on operation-level timeouts from Context for the rest.
-	// The transport timeouts ensure we don't hang on connection setup.
-
-	return &minioClientWrapper{Client: minioClient}, nil
 }
 
 type minioClientWrapper struct {
 	*minio.Client
+	// presigner signs presigned URLs; it is Client unless a public endpoint is configured.
+	presigner     *minio.Client
+	requesterPays bool
 }
 
+// PresignedGetObject presigns a download on the public endpoint. With requester pays
+// the URL carries the x-amz-request-payer parameter, which S3 accepts in the query.
+func (c *minioClientWrapper) PresignedGetObject(ctx context.Context, bucketName, objectName string, expiry time.Duration, reqParams url.Values) (*url.URL, error) {
+	if c.requesterPays {
+		params := make(url.Values, len(reqParams)+1)
+		for k, v := range reqParams {
+			params[k] = v
+		}
+		params.Set(strings.ToLower(RequesterPaysHeader), "requester")
+		reqParams = params
+	}
+	return c.presigner.PresignedGetObject(ctx, bucketName, objectName, expiry, reqParams)
+}
+
+// PresignedPutObject presigns an upload on the public endpoint.
+func (c *minioClientWrapper) PresignedPutObject(ctx context.Context, bucketName, objectName string, expiry time.Duration) (*url.URL, error) {
+	return c.presigner.PresignedPutObject(ctx, bucketName, objectName, expiry)
+}
+
+// GetObject downloads an object, adding the requester-pays header when enabled.
 func (c *minioClientWrapper) GetObject(ctx context.Context, bucketName, objectName string, opts minio.GetObjectOptions) (io.ReadCloser, error) {
+	if c.requesterPays {
+		opts.Set(RequesterPaysHeader, "requester")
+	}
 	return c.Client.GetObject(ctx, bucketName, objectName, opts)
 }
+
+// ListObjects lists objects, adding the requester-pays header when enabled.
+func (c *minioClientWrapper) ListObjects(ctx context.Context, bucketName string, opts minio.ListObjectsOptions) <-chan minio.ObjectInfo {
+	if c.requesterPays {
+		opts.Set(RequesterPaysHeader, "requester")
+	}
+	return c.Client.ListObjects(ctx, bucketName, opts)
+}
+
+// PutObject uploads an object, adding the requester-pays header when enabled.
+// Minio sends amz headers found in UserMetadata verbatim, so they are signed with the request.
+func (c *minioClientWrapper) PutObject(ctx context.Context, bucketName, objectName string, reader io.Reader, objectSize int64, opts minio.PutObjectOptions) (minio.UploadInfo, error) {
+	if c.requesterPays {
+		metadata := make(map[string]string, len(opts.UserMetadata)+1)
+		for k, v := range opts.UserMetadata {
+			metadata[k] = v
+		}
+		metadata[RequesterPaysHeader] = "requester"
+		opts.UserMetadata = metadata
+	}
+	return c.Client.PutObject(ctx, bucketName, objectName, reader, objectSize, opts)
+}
@@ -1,9 +1,16 @@
 package storage
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/minio/minio-go/v7"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewClient(t *testing.T) {
@@ -53,3 +60,112 @@ func TestNewClient(t *testing.T) {
 		})
 	}
 }
+
+func TestBucketLookup(t *testing.T) {
+	tests := map[string]minio.BucketLookupType{
+		"":        minio.BucketLookupAuto,
+		"auto":    minio.BucketLookupAuto,
+		"path":    minio.BucketLookupPath,
+		"Virtual": minio.BucketLookupDNS,
+	}
+	for addressing, want := range tests {
+		got, err := bucketLookup(addressing)
+		assert.NoError(t, err, addressing)
+		assert.Equal(t, want, got, addressing)
+	}
+
+	_, err := bucketLookup("dns")
+	assert.ErrorContains(t, err, "invalid storage addressing")
+
+	_, err = NewClient(Config{Endpoint: "localhost:9000", Addressing: "dns"})
+	assert.Error(t, err)
+}
+
+func TestNewTransport(t *testing.T) {
+	transport := newTransport(Config{TimeoutSeconds: 5})
+	assert.Equal(t, 5*time.Second, transport.ResponseHeaderTimeout)
+	assert.Equal(t, 90*time.Second, transport.IdleConnTimeout)
+
+	transport = newTransport(Config{TimeoutSeconds: 5, ResponseTimeoutSeconds: 120, IdleTimeoutSeconds: 10})
+	assert.Equal(t, 5*time.Second, transport.TLSHandshakeTimeout)
+	assert.Equal(t, 120*time.Second, transport.ResponseHeaderTimeout)
+	assert.Equal(t, 10*time.Second, transport.IdleConnTimeout)
+}
+
+func TestRequesterPays(t *testing.T) {
+	var mu sync.Mutex
+	var payers []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		payers = append(payers, r.Header.Get(RequesterPaysHeader))
+		mu.Unlock()
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	for _, enabled := range []bool{true, false} {
+		mu.Lock()
+		payers = nil
+		mu.Unlock()
+
+		client, err := NewClient(Config{
+			Endpoint:      server.URL,
+			AccessKey:     "key",
+			SecretKey:     "secret",
+			Region:        "us-east-1",
+			Addressing:    AddressingPath,
+			RequesterPays: enabled,
+		})
+		require.NoError(t, err)
+
+		for range client.ListObjects(context.Background(), "assets", minio.ListObjectsOptions{Prefix: "gamedata/"}) {
+		}
+
+		mu.Lock()
+		require.NotEmpty(t, payers)
+		for _, payer := range payers {
+			if enabled {
+				assert.Equal(t, "requester", payer)
+			} else {
+				assert.Empty(t, payer)
+			}
+		}
+		mu.Unlock()
+	}
+}
+
+// TestPresign tests that presigned URLs are signed for the public endpoint.
+func TestPresign(t *testing.T) {
+	cfg := Config{
+		Endpoint:      "minio:9000",
+		AccessKey:     "key",
+		SecretKey:     "secret",
+		Region:        "us-east-1",
+		Addressing:    AddressingPath,
+		RequesterPays: true,
+		Presign:       Presign{Endpoint: "https://cdn.example.com/"},
+	}
+	client, err := NewClient(cfg)
+	require.NoError(t, err)
+
+	get, err := client.PresignedGetObject(context.Background(), "assets", "bundled/furniture/throne.nitro", time.Minute, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "https", get.Scheme)
+	assert.Equal(t, "cdn.example.com", get.Host)
+	assert.Equal(t, "/assets/bundled/furniture/throne.nitro", get.Path)
+	assert.Equal(t, "60", get.Query().Get("X-Amz-Expires"))
+	assert.Equal(t, "requester", get.Query().Get("x-amz-request-payer"))
+
+	put, err := client.PresignedPutObject(context.Background(), "assets", "bundled/furniture/throne.nitro", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, "cdn.example.com", put.Host)
+
+	// Without a public endpoint URLs point at the storage endpoint
+	cfg.Presign.Endpoint = ""
+	client, err = NewClient(cfg)
+	require.NoError(t, err)
+	get, err = client.PresignedGetObject(context.Background(), "assets", "gamedata/FurnitureData.json", time.Minute, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "http", get.Scheme)
+	assert.Equal(t, "minio:9000", get.Host)
+}
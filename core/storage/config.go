@@ -1,5 +1,7 @@
 package storage
 
+import "time"
+
 // Config holds configuration for the storage provider.
 type Config struct {
 	// Endpoint is the URL of the storage service.
@@ -12,8 +14,120 @@ type Config struct {
 	UseSSL bool `mapstructure:"use_ssl" default:"false"`
 	// Bucket is the name of the bucket to store assets in.
 	Bucket string `mapstructure:"bucket" default:"assets"`
+	// GamedataBucket is the bucket holding gamedata files. Empty keeps them in Bucket.
+	GamedataBucket string `mapstructure:"gamedata_bucket" default:""`
 	// Region is the location of the bucket (e.g., us-east-1).
 	Region string `mapstructure:"region" default:""`
 	// TimeoutSeconds is the connection timeout in seconds.
 	TimeoutSeconds int `mapstructure:"timeout_seconds" default:"30"`
+	// ResponseTimeoutSeconds is how long to wait for response headers. Zero uses TimeoutSeconds.
+	ResponseTimeoutSeconds int `mapstructure:"response_timeout_seconds" default:"0"`
+	// IdleTimeoutSeconds is how long idle keep-alive connections are kept open.
+	IdleTimeoutSeconds int `mapstructure:"idle_timeout_seconds" default:"90"`
+	// Addressing selects bucket addressing: "auto", "path" or "virtual" (virtual-host style).
+	Addressing string `mapstructure:"addressing" default:"auto"`
+	// RequesterPays sends the requester-pays header so reads and writes on buckets
+	// billed to the requester are accepted.
+	RequesterPays bool `mapstructure:"requester_pays" default:"false"`
+	// Layout lists the folders the integrity checks require.
+	Layout Layout `mapstructure:"layout"`
+	// ChangeLog records every object written or removed by the manager in the bucket.
+	ChangeLog ChangeLog `mapstructure:"changelog"`
+	// History keeps snapshots of the furniture gamedata before each change.
+	History History `mapstructure:"history"`
+	// Presign configures presigned URLs handed to clients.
+	Presign Presign `mapstructure:"presign"`
+}
+
+// Presign configures presigned URLs, which let clients read or write objects directly
+// in storage instead of through the manager.
+type Presign struct {
+	// Endpoint is the storage URL clients reach (e.g. https://cdn-s3.example.com) when it
+	// differs from Endpoint. URLs are signed for this host. Empty uses Endpoint.
+	Endpoint string `mapstructure:"endpoint" default:""`
+	// Expiry is how long a URL stays valid when the request sets none.
+	Expiry time.Duration `mapstructure:"expiry" default:"15m"`
+	// MaxExpiry caps the validity a request may ask for. S3 allows at most 7 days.
+	MaxExpiry time.Duration `mapstructure:"max_expiry" default:"24h"`
+	// AllowPut enables presigned uploads. They bypass the upload size, content and
+	// malware checks, so they are off by default.
+	AllowPut bool `mapstructure:"allow_put" default:"false"`
+	// Prefixes are the key prefixes URLs may be presigned for. Internal objects (run
+	// lock, backups, change log, gamedata history, quarantine) stay outside them.
+	Prefixes []string `mapstructure:"prefixes" default:"bundled/,c_images/,dcr/,images/,logos/,sounds/"`
+	// StagingPrefix is where presigned uploads may replace existing objects. Elsewhere
+	// under Prefixes they may only create objects that do not exist yet. Empty
+	// disables staging.
+	StagingPrefix string `mapstructure:"staging_prefix" default:""`
+}
+
+// ChangeLog configures the log of manager-originated changes kept in the bucket itself,
+// for bucket-level history without S3 versioning.
+type ChangeLog struct {
+	// Enabled turns the change log on.
+	Enabled bool `mapstructure:"enabled" default:"false"`
+	// Prefix is where the daily NDJSON logs are written (<prefix>YYYY-MM-DD.ndjson, UTC).
+	Prefix string `mapstructure:"prefix" default:"_changes/"`
+}
+
+// DefaultHistoryObject is the object History snapshots when neither it nor the
+// furniture gamedata_object of reconcile names one.
+const DefaultHistoryObject = "gamedata/FurnitureData.json"
+
+// History configures the snapshots kept of one object, by default the furniture
+// gamedata, before every write or removal of it through the manager.
+type History struct {
+	// Enabled turns the snapshots on.
+	Enabled bool `mapstructure:"enabled" default:"true"`
+	// Object is the key of the snapshotted object. Empty follows the furniture
+	// gamedata_object of reconcile, or DefaultHistoryObject (see config.LoadConfig).
+	Object string `mapstructure:"object" default:""`
+	// Prefix is where snapshots are written (<prefix><name>.<timestamp><ext>), in the
+	// bucket of the object.
+	Prefix string `mapstructure:"prefix" default:"gamedata/history/"`
+	// Keep is the number of newest snapshots kept. Zero keeps all of them.
+	Keep int `mapstructure:"keep" default:"50"`
+	// MaxAge removes snapshots older than this. Zero keeps them regardless of age.
+	MaxAge time.Duration `mapstructure:"max_age" default:"0"`
+}
+
+// Layout lists the folders that must exist in storage. Hotels with extra asset
+// categories add them here so the structure and bundled checks cover them.
+type Layout struct {
+	// Folders are the top-level folders checked by the structure check.
+	Folders []string `mapstructure:"folders" default:"bundled,c_images,dcr,gamedata,images,logos,sounds"`
+	// BundledFolders are the bundled asset folders checked by the bundled check.
+	BundledFolders []string `mapstructure:"bundled_folders" default:"bundled/effect,bundled/figure,bundled/furniture,bundled/generic,bundled/pet"`
+	// FolderMode is "placeholder" (fixes write zero-byte "folder/" objects) or "prefix"
+	// (a folder exists once any object lives under it; fixes write nothing unless
+	// ".keep" objects are requested explicitly).
+	FolderMode string `mapstructure:"folder_mode" default:"placeholder"`
+	// GamedataMaxAge flags gamedata files unchanged for longer. Zero disables the check.
+	GamedataMaxAge time.Duration `mapstructure:"gamedata_max_age" default:"720h"`
+	// GamedataMinSize flags gamedata files smaller than this many bytes (truncated uploads).
+	GamedataMinSize int64 `mapstructure:"gamedata_min_size" default:"64"`
+	// CatalogImagesPrefix is where catalog page icons and images live.
+	CatalogImagesPrefix string `mapstructure:"catalog_images_prefix" default:"c_images/catalogue"`
+	// BadgesPrefix is where badge images (<CODE>.gif or .png) live.
+	BadgesPrefix string `mapstructure:"badges_prefix" default:"c_images/album1584"`
+}
+
+// Folder modes accepted by Layout.FolderMode.
+const (
+	FolderModePlaceholder = "placeholder"
+	FolderModePrefix      = "prefix"
+)
+
+// PrefixFolders reports whether folders are plain prefixes without placeholder objects.
+func (l Layout) PrefixFolders() bool {
+	return l.FolderMode == FolderModePrefix
+}
+
+// Buckets resolves the bucket used for each purpose.
+func (c Config) Buckets() Buckets {
+	buckets := SingleBucket(c.Bucket)
+	if c.GamedataBucket != "" {
+		buckets.Gamedata = c.GamedataBucket
+	}
+	return buckets
 }
@@ -16,6 +16,52 @@
 //   - PutObject: Uploads content (with size and options).
 //   - GetObject: Retrieves content as a stream.
 //   - ListObjects: Lists objects in a bucket (supports prefix/recursive).
+//   - PresignedGetObject, PresignedPutObject: Sign URLs that read or write one object
+//     without credentials until they expire.
+//
+// # Buckets
+//
+// Buckets names the bucket for each purpose. Config.Buckets keeps everything in
+// Bucket unless GamedataBucket is set, in which case the gamedata folder lives there.
+//
+// # Provider Options
+//
+// Config.Addressing forces path-style or virtual-host bucket addressing for providers
+// that support only one. Config.RequesterPays adds the requester-pays header to reads,
+// lists and uploads; Minio offers no hook to sign it on deletes or bucket checks.
+//
+// Presigned URLs are signed offline for Config.Presign.Endpoint when set, so clients
+// are sent to the public host of the storage (a CDN or provider domain) rather than
+// the endpoint the manager reaches internally. Set Region to avoid a bucket location
+// lookup against that host.
+//
+// # Change Log
+//
+// With Config.ChangeLog enabled, NewClient wraps the client with WithChangeLog: every
+// successful write and removal is appended to a daily NDJSON object under the change
+// log prefix of the changed bucket, giving bucket-level history without S3 versioning.
+// Bulk operations run under WithChangeBuffer so the log is rewritten once per
+// operation; each rewrite is conditioned on the ETag it read and retried on conflict.
+//
+// # History
+//
+// With Config.History enabled (the default), NewClient wraps the client with
+// WithHistory: before the furniture gamedata is overwritten or removed, its current
+// content is copied to gamedata/history/FurnitureData.<timestamp>.json. ListHistory
+// lists the snapshots and RestoreHistory writes one back. Unlike object versions, this
+// works on buckets without versioning.
+//
+// # Object Versions
+//
+// In a bucket with versioning enabled, overwrites and removals keep the replaced
+// version. NewClient wraps the client with WithVersionRecording: calls made with a
+// context from WithVersionRecorder record the version each change replaced, and
+// RestoreVersion later writes such a version back as the current one.
+//
+// # Mutation Log
+//
+// NewClient wraps the client with WithMutationLogging: writes and removals made with a
+// context from logger.WithMutationLog are logged to that logger with bucket and key.
 //
 // # Usage
 //
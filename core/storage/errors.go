@@ -0,0 +1,37 @@
+package storage
+
+import (
+	"errors"
+	"io"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// IsNoSuchKey reports whether err means the object does not exist.
+func IsNoSuchKey(err error) bool {
+	var resp minio.ErrorResponse
+	return errors.As(err, &resp) && resp.Code == "NoSuchKey"
+}
+
+// IsPreconditionFailed reports whether err means a conditional write lost to another
+// writer (If-Match or If-None-Match did not hold).
+func IsPreconditionFailed(err error) bool {
+	var resp minio.ErrorResponse
+	return errors.As(err, &resp) && resp.Code == "PreconditionFailed"
+}
+
+// ObjectETag returns the ETag of an object opened with GetObject, or "" when the
+// reader does not expose the object's metadata.
+func ObjectETag(reader io.Reader) string {
+	object, ok := reader.(interface {
+		Stat() (minio.ObjectInfo, error)
+	})
+	if !ok {
+		return ""
+	}
+	info, err := object.Stat()
+	if err != nil {
+		return ""
+	}
+	return info.ETag
+}
@@ -0,0 +1,40 @@
+package storage
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+)
+
+// etagReader is an object reader that reports an ETag like *minio.Object.
+type etagReader struct {
+	io.Reader
+	etag string
+}
+
+func (r *etagReader) Close() error { return nil }
+
+func (r *etagReader) Stat() (minio.ObjectInfo, error) {
+	return minio.ObjectInfo{ETag: r.etag}, nil
+}
+
+func TestIsNoSuchKey(t *testing.T) {
+	assert.True(t, IsNoSuchKey(minio.ErrorResponse{Code: "NoSuchKey"}))
+	assert.True(t, IsNoSuchKey(fmt.Errorf("wrapped: %w", minio.ErrorResponse{Code: "NoSuchKey"})))
+	assert.False(t, IsNoSuchKey(minio.ErrorResponse{Code: "AccessDenied"}))
+	assert.False(t, IsNoSuchKey(assert.AnError))
+}
+
+func TestIsPreconditionFailed(t *testing.T) {
+	assert.True(t, IsPreconditionFailed(fmt.Errorf("wrapped: %w", minio.ErrorResponse{Code: "PreconditionFailed"})))
+	assert.False(t, IsPreconditionFailed(minio.ErrorResponse{Code: "NoSuchKey"}))
+}
+
+func TestObjectETag(t *testing.T) {
+	assert.Equal(t, "abc", ObjectETag(&etagReader{Reader: strings.NewReader(""), etag: "abc"}))
+	assert.Equal(t, "", ObjectETag(strings.NewReader("")))
+}
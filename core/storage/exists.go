@@ -0,0 +1,21 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// ObjectExists reports whether key exists in bucket.
+func ObjectExists(ctx context.Context, client Client, bucket, key string) (bool, error) {
+	for obj := range client.ListObjects(ctx, bucket, minio.ListObjectsOptions{Prefix: key}) {
+		if obj.Err != nil {
+			return false, fmt.Errorf("failed to list objects: %w", obj.Err)
+		}
+		if obj.Key == key {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,192 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"go.uber.org/zap"
+)
+
+// HistoryTimeFormat is the UTC timestamp naming a history snapshot, which is also its
+// version. Versions sort chronologically as strings.
+const HistoryTimeFormat = "20060102T150405.000Z"
+
+// ErrNoHistoryVersion is returned when restoring a version the history does not hold.
+var ErrNoHistoryVersion = errors.New("no such history version")
+
+// ErrInvalidHistoryVersion is returned for versions not in HistoryTimeFormat.
+var ErrInvalidHistoryVersion = errors.New("invalid history version")
+
+// HistoryVersion is one snapshot of the history object.
+type HistoryVersion struct {
+	// Version is the snapshot timestamp in HistoryTimeFormat.
+	Version string `json:"version"`
+	// Key is the snapshot object, e.g. gamedata/history/FurnitureData.<version>.json.
+	Key string `json:"key"`
+	// Size is the snapshot size in bytes.
+	Size int64 `json:"size"`
+	// Time is when the snapshot was taken, parsed from Version.
+	Time time.Time `json:"time"`
+}
+
+// historyClient snapshots the history object of the wrapped client before changes.
+type historyClient struct {
+	Client
+	history History
+	now     func() time.Time
+}
+
+// WithHistory wraps client so PutObject and RemoveObject of the object configured in
+// history first copy its current content to <prefix><name>.<timestamp><ext> in the same
+// bucket, e.g. gamedata/history/FurnitureData.20260301T120000.000Z.json. A change is
+// refused when the snapshot cannot be written; nothing is copied when the object does
+// not exist yet. Snapshots beyond history.Keep or older than history.MaxAge are removed
+// afterwards; failures to do so are logged and never fail the change.
+func WithHistory(client Client, history History) Client {
+	return &historyClient{Client: client, history: history, now: time.Now}
+}
+
+// PutObject uploads an object, snapshotting the history object first.
+func (c *historyClient) PutObject(ctx context.Context, bucketName, objectName string, reader io.Reader, objectSize int64, opts minio.PutObjectOptions) (minio.UploadInfo, error) {
+	if err := c.snapshot(ctx, bucketName, objectName); err != nil {
+		return minio.UploadInfo{}, err
+	}
+	return c.Client.PutObject(ctx, bucketName, objectName, reader, objectSize, opts)
+}
+
+// RemoveObject deletes an object, snapshotting the history object first.
+func (c *historyClient) RemoveObject(ctx context.Context, bucketName, objectName string, opts minio.RemoveObjectOptions) error {
+	if err := c.snapshot(ctx, bucketName, objectName); err != nil {
+		return err
+	}
+	return c.Client.RemoveObject(ctx, bucketName, objectName, opts)
+}
+
+// snapshot copies the current content of objectName to the history when it is the
+// history object, then prunes the history.
+func (c *historyClient) snapshot(ctx context.Context, bucket, objectName string) error {
+	if objectName != c.history.Object {
+		return nil
+	}
+
+	reader, err := c.Client.GetObject(ctx, bucket, objectName, minio.GetObjectOptions{})
+	var data []byte
+	if err == nil {
+		data, err = io.ReadAll(reader)
+		reader.Close()
+	}
+	if err != nil {
+		if IsNoSuchKey(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read %s for its history: %w", objectName, err)
+	}
+
+	now := c.now().UTC()
+	key := c.history.snapshotKey(now.Format(HistoryTimeFormat))
+	if _, err := c.Client.PutObject(ctx, bucket, key, bytes.NewReader(data), int64(len(data)), minio.PutObjectOptions{ContentType: "application/json"}); err != nil {
+		return fmt.Errorf("failed to write history snapshot %s: %w", key, err)
+	}
+
+	if err := c.prune(ctx, bucket, now); err != nil {
+		zap.L().Warn("Failed to prune storage history", zap.String("bucket", bucket), zap.String("prefix", c.history.Prefix), zap.Error(err))
+	}
+	return nil
+}
+
+// prune removes the snapshots beyond Keep, newest first, and those older than MaxAge
+// at now.
+func (c *historyClient) prune(ctx context.Context, bucket string, now time.Time) error {
+	if c.history.Keep <= 0 && c.history.MaxAge <= 0 {
+		return nil
+	}
+	versions, err := ListHistory(ctx, c.Client, bucket, c.history)
+	if err != nil {
+		return err
+	}
+
+	cutoff := now.Add(-c.history.MaxAge)
+	for i, v := range versions {
+		expired := c.history.MaxAge > 0 && v.Time.Before(cutoff)
+		if (c.history.Keep > 0 && i >= c.history.Keep) || expired {
+			if err := c.Client.RemoveObject(ctx, bucket, v.Key, minio.RemoveObjectOptions{}); err != nil {
+				return fmt.Errorf("failed to remove %s: %w", v.Key, err)
+			}
+		}
+	}
+	return nil
+}
+
+// snapshotKey returns the key of the snapshot of version.
+func (h History) snapshotKey(version string) string {
+	name, ext := h.nameParts()
+	return h.Prefix + name + "." + version + ext
+}
+
+// nameParts splits the base name of the history object into name and extension.
+func (h History) nameParts() (string, string) {
+	base := path.Base(h.Object)
+	ext := path.Ext(base)
+	return strings.TrimSuffix(base, ext), ext
+}
+
+// ListHistory returns the snapshots of the history object in bucket, newest first.
+func ListHistory(ctx context.Context, client Client, bucket string, history History) ([]HistoryVersion, error) {
+	name, ext := history.nameParts()
+	prefix := history.Prefix + name + "."
+
+	versions := []HistoryVersion{}
+	for obj := range client.ListObjects(ctx, bucket, minio.ListObjectsOptions{Prefix: prefix}) {
+		if obj.Err != nil {
+			return nil, fmt.Errorf("failed to list history: %w", obj.Err)
+		}
+		version, ok := strings.CutSuffix(strings.TrimPrefix(obj.Key, prefix), ext)
+		if !ok {
+			continue
+		}
+		taken, err := time.Parse(HistoryTimeFormat, version)
+		if err != nil {
+			continue
+		}
+		versions = append(versions, HistoryVersion{Version: version, Key: obj.Key, Size: obj.Size, Time: taken})
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i].Version > versions[j].Version })
+	return versions, nil
+}
+
+// RestoreHistory writes the snapshot version back as the history object in bucket.
+// Through a client wrapped by WithHistory the replaced content is snapshotted first,
+// so a restore can be undone by restoring that snapshot.
+func RestoreHistory(ctx context.Context, client Client, bucket string, history History, version string) (*HistoryVersion, error) {
+	taken, err := time.Parse(HistoryTimeFormat, version)
+	if err != nil {
+		return nil, fmt.Errorf("%w %q: want a timestamp like %s", ErrInvalidHistoryVersion, version, HistoryTimeFormat)
+	}
+
+	key := history.snapshotKey(version)
+	reader, err := client.GetObject(ctx, bucket, key, minio.GetObjectOptions{})
+	var data []byte
+	if err == nil {
+		data, err = io.ReadAll(reader)
+		reader.Close()
+	}
+	if err != nil {
+		if IsNoSuchKey(err) {
+			return nil, fmt.Errorf("%w %s", ErrNoHistoryVersion, version)
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", key, err)
+	}
+
+	if _, err := client.PutObject(ctx, bucket, history.Object, bytes.NewReader(data), int64(len(data)), minio.PutObjectOptions{ContentType: "application/json"}); err != nil {
+		return nil, fmt.Errorf("failed to restore %s: %w", history.Object, err)
+	}
+	return &HistoryVersion{Version: version, Key: key, Size: int64(len(data)), Time: taken}, nil
+}
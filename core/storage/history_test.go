@@ -0,0 +1,151 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"asset-manager/core/storage/mocks"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryClient keeps the objects of one bucket in memory.
+type memoryClient struct {
+	mocks.Client
+	objects map[string][]byte
+}
+
+func (m *memoryClient) GetObject(ctx context.Context, bucketName, objectName string, opts minio.GetObjectOptions) (io.ReadCloser, error) {
+	data, ok := m.objects[objectName]
+	if !ok {
+		return nil, minio.ErrorResponse{Code: "NoSuchKey"}
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (m *memoryClient) PutObject(ctx context.Context, bucketName, objectName string, reader io.Reader, objectSize int64, opts minio.PutObjectOptions) (minio.UploadInfo, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return minio.UploadInfo{}, err
+	}
+	m.objects[objectName] = data
+	return minio.UploadInfo{Size: int64(len(data))}, nil
+}
+
+func (m *memoryClient) RemoveObject(ctx context.Context, bucketName, objectName string, opts minio.RemoveObjectOptions) error {
+	delete(m.objects, objectName)
+	return nil
+}
+
+func (m *memoryClient) ListObjects(ctx context.Context, bucketName string, opts minio.ListObjectsOptions) <-chan minio.ObjectInfo {
+	var keys []string
+	for key := range m.objects {
+		if strings.HasPrefix(key, opts.Prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	ch := make(chan minio.ObjectInfo, len(keys))
+	for _, key := range keys {
+		ch <- minio.ObjectInfo{Key: key, Size: int64(len(m.objects[key]))}
+	}
+	close(ch)
+	return ch
+}
+
+// testHistory snapshots gamedata/FurnitureData.json, keeping two snapshots.
+var testHistory = History{Enabled: true, Object: "gamedata/FurnitureData.json", Prefix: "gamedata/history/", Keep: 2}
+
+// newTestHistory wraps a memory client whose clock advances one second per snapshot.
+func newTestHistory(history History) (*memoryClient, Client) {
+	inner := &memoryClient{objects: make(map[string][]byte)}
+	client := WithHistory(inner, history)
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	client.(*historyClient).now = func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+	return inner, client
+}
+
+// put writes content as the gamedata through client.
+func put(t *testing.T, client Client, content string) {
+	_, err := client.PutObject(context.Background(), "gamedata", "gamedata/FurnitureData.json", strings.NewReader(content), int64(len(content)), minio.PutObjectOptions{})
+	require.NoError(t, err)
+}
+
+// TestHistory_SnapshotsAndPrunes tests that each change of the object snapshots its
+// previous content, that other objects are left alone and that old snapshots are pruned.
+func TestHistory_SnapshotsAndPrunes(t *testing.T) {
+	inner, client := newTestHistory(testHistory)
+	ctx := context.Background()
+
+	// Nothing to snapshot yet
+	put(t, client, "v1")
+	assert.Len(t, inner.objects, 1)
+
+	put(t, client, "v2")
+	put(t, client, "v3")
+	_, err := client.PutObject(ctx, "gamedata", "gamedata/FigureData.json", strings.NewReader("figure"), 6, minio.PutObjectOptions{})
+	require.NoError(t, err)
+
+	versions, err := ListHistory(ctx, inner, "gamedata", testHistory)
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+	assert.Equal(t, "20261016T120002.000Z", versions[0].Version)
+	assert.Equal(t, "gamedata/history/FurnitureData.20261016T120002.000Z.json", versions[0].Key)
+	assert.Equal(t, "v2", string(inner.objects[versions[0].Key]))
+	assert.Equal(t, "v1", string(inner.objects[versions[1].Key]))
+
+	// The third snapshot pushes out the oldest
+	require.NoError(t, client.RemoveObject(ctx, "gamedata", "gamedata/FurnitureData.json", minio.RemoveObjectOptions{}))
+	versions, err = ListHistory(ctx, inner, "gamedata", testHistory)
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+	assert.Equal(t, "v3", string(inner.objects[versions[0].Key]))
+	assert.Equal(t, "v2", string(inner.objects[versions[1].Key]))
+}
+
+// TestHistory_MaxAge tests that snapshots older than MaxAge are pruned.
+func TestHistory_MaxAge(t *testing.T) {
+	inner, client := newTestHistory(History{Object: "gamedata/FurnitureData.json", Prefix: "gamedata/history/", MaxAge: time.Second})
+	inner.objects["gamedata/history/FurnitureData.20200101T000000.000Z.json"] = []byte("old")
+
+	put(t, client, "v1")
+	put(t, client, "v2")
+
+	versions, err := ListHistory(context.Background(), inner, "gamedata", testHistory)
+	require.NoError(t, err)
+	require.Len(t, versions, 1)
+	assert.Equal(t, "v1", string(inner.objects[versions[0].Key]))
+}
+
+// TestRestoreHistory tests restoring a snapshot, which snapshots the replaced content.
+func TestRestoreHistory(t *testing.T) {
+	inner, client := newTestHistory(History{Object: "gamedata/FurnitureData.json", Prefix: "gamedata/history/"})
+	ctx := context.Background()
+	put(t, client, "v1")
+	put(t, client, "v2")
+
+	restored, err := RestoreHistory(ctx, client, "gamedata", testHistory, "20261016T120001.000Z")
+	require.NoError(t, err)
+	assert.Equal(t, "gamedata/history/FurnitureData.20261016T120001.000Z.json", restored.Key)
+	assert.Equal(t, "v1", string(inner.objects["gamedata/FurnitureData.json"]))
+
+	versions, err := ListHistory(ctx, inner, "gamedata", testHistory)
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+	assert.Equal(t, "v2", string(inner.objects[versions[0].Key]))
+
+	_, err = RestoreHistory(ctx, client, "gamedata", testHistory, "20261016T130000.000Z")
+	assert.ErrorIs(t, err, ErrNoHistoryVersion)
+	_, err = RestoreHistory(ctx, client, "gamedata", testHistory, "latest")
+	assert.ErrorIs(t, err, ErrInvalidHistoryVersion)
+}
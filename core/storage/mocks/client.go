@@ -3,6 +3,8 @@ package mocks
 import (
 	"context"
 	"io"
+	"net/url"
+	"time"
 
 	"github.com/minio/minio-go/v7"
 	"github.com/stretchr/testify/mock"
@@ -60,3 +62,19 @@ func (m *Client) RemoveObjects(ctx context.Context, bucketName string, objectsCh
 	close(ch)
 	return ch
 }
+
+func (m *Client) PresignedGetObject(ctx context.Context, bucketName, objectName string, expiry time.Duration, reqParams url.Values) (*url.URL, error) {
+	args := m.Called(ctx, bucketName, objectName, expiry, reqParams)
+	if u, ok := args.Get(0).(*url.URL); ok {
+		return u, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *Client) PresignedPutObject(ctx context.Context, bucketName, objectName string, expiry time.Duration) (*url.URL, error) {
+	args := m.Called(ctx, bucketName, objectName, expiry)
+	if u, ok := args.Get(0).(*url.URL); ok {
+		return u, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
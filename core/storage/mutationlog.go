@@ -0,0 +1,93 @@
+package storage
+
+import (
+	"context"
+	"io"
+
+	"asset-manager/core/logger"
+
+	"github.com/minio/minio-go/v7"
+	"go.uber.org/zap"
+)
+
+// mutationLogClient logs the writes and removals of the wrapped client.
+type mutationLogClient struct {
+	Client
+}
+
+// WithMutationLogging wraps client so PutObject, RemoveObject and RemoveObjects
+// called with a context from logger.WithMutationLog log their bucket and key to that
+// logger at info level. Without a mutation logger the calls pass through unchanged.
+func WithMutationLogging(client Client) Client {
+	return &mutationLogClient{Client: client}
+}
+
+// PutObject uploads an object and logs it.
+func (c *mutationLogClient) PutObject(ctx context.Context, bucketName, objectName string, reader io.Reader, objectSize int64, opts minio.PutObjectOptions) (minio.UploadInfo, error) {
+	info, err := c.Client.PutObject(ctx, bucketName, objectName, reader, objectSize, opts)
+	if l := logger.MutationLog(ctx); l != nil {
+		l.Info("Executed storage write", mutationFields(bucketName, objectName, err, zap.Int64("size", objectSize))...)
+	}
+	return info, err
+}
+
+// RemoveObject deletes an object and logs it.
+func (c *mutationLogClient) RemoveObject(ctx context.Context, bucketName, objectName string, opts minio.RemoveObjectOptions) error {
+	err := c.Client.RemoveObject(ctx, bucketName, objectName, opts)
+	if l := logger.MutationLog(ctx); l != nil {
+		l.Info("Executed storage removal", mutationFields(bucketName, objectName, err)...)
+	}
+	return err
+}
+
+// RemoveObjects deletes objects, logging each one as it is handed to the batch.
+// Failures are reported on the returned channel as usual.
+func (c *mutationLogClient) RemoveObjects(ctx context.Context, bucketName string, objectsCh <-chan minio.ObjectInfo, opts minio.RemoveObjectsOptions) <-chan minio.RemoveObjectError {
+	l := logger.MutationLog(ctx)
+	if l == nil {
+		return c.Client.RemoveObjects(ctx, bucketName, objectsCh, opts)
+	}
+
+	// stop ends the forwarding when the removal ends early so nothing blocks
+	forwarded := make(chan minio.ObjectInfo)
+	stop := make(chan struct{})
+	go func() {
+		defer close(forwarded)
+		for {
+			select {
+			case object, ok := <-objectsCh:
+				if !ok {
+					return
+				}
+				select {
+				case forwarded <- object:
+					l.Info("Executed storage removal", mutationFields(bucketName, object.Key, nil, zap.Bool("batch", true))...)
+				case <-stop:
+					return
+				}
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	errs := c.Client.RemoveObjects(ctx, bucketName, forwarded, opts)
+	out := make(chan minio.RemoveObjectError)
+	go func() {
+		defer close(out)
+		for removeErr := range errs {
+			out <- removeErr
+		}
+		close(stop)
+	}()
+	return out
+}
+
+// mutationFields returns the log fields of a change of bucket/key.
+func mutationFields(bucket, key string, err error, extra ...zap.Field) []zap.Field {
+	fields := append([]zap.Field{zap.String("bucket", bucket), zap.String("key", key)}, extra...)
+	if err != nil {
+		fields = append(fields, zap.Error(err))
+	}
+	return fields
+}
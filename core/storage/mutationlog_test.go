@@ -0,0 +1,67 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"asset-manager/core/logger"
+	"asset-manager/core/storage/mocks"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// TestWithMutationLogging tests that writes and removals under a mutation log are
+// logged with their keys, and pass through unlogged otherwise.
+func TestWithMutationLogging(t *testing.T) {
+	inner := new(mocks.Client)
+	inner.On("PutObject", mock.Anything, "assets", "bundled/furniture/chair.nitro", mock.Anything, int64(5), mock.Anything).
+		Return(minio.UploadInfo{}, nil)
+	inner.On("RemoveObject", mock.Anything, "assets", "bundled/furniture/lamp.nitro", mock.Anything).Return(errors.New("denied"))
+	results := make(chan minio.RemoveObjectError)
+	close(results)
+	inner.On("RemoveObjects", mock.Anything, "assets", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			for range args.Get(2).(<-chan minio.ObjectInfo) {
+			}
+		}).
+		Return((<-chan minio.RemoveObjectError)(results))
+
+	client := WithMutationLogging(inner)
+	core, logs := observer.New(zap.InfoLevel)
+	ctx := logger.WithMutationLog(context.Background(), zap.New(core))
+
+	_, err := client.PutObject(ctx, "assets", "bundled/furniture/chair.nitro", strings.NewReader("chair"), 5, minio.PutObjectOptions{})
+	require.NoError(t, err)
+	assert.Error(t, client.RemoveObject(ctx, "assets", "bundled/furniture/lamp.nitro", minio.RemoveObjectOptions{}))
+
+	objects := make(chan minio.ObjectInfo, 2)
+	objects <- minio.ObjectInfo{Key: "bundled/furniture/a.nitro"}
+	objects <- minio.ObjectInfo{Key: "bundled/furniture/b.nitro"}
+	close(objects)
+	for range client.RemoveObjects(ctx, "assets", objects, minio.RemoveObjectsOptions{}) {
+	}
+
+	var keys []string
+	for _, entry := range logs.All() {
+		keys = append(keys, entry.Message+" "+entry.ContextMap()["key"].(string))
+	}
+	assert.Equal(t, []string{
+		"Executed storage write bundled/furniture/chair.nitro",
+		"Executed storage removal bundled/furniture/lamp.nitro",
+		"Executed storage removal bundled/furniture/a.nitro",
+		"Executed storage removal bundled/furniture/b.nitro",
+	}, keys)
+	assert.Equal(t, "denied", logs.All()[1].ContextMap()["error"])
+
+	// Without a mutation log nothing is logged
+	_, err = client.PutObject(context.Background(), "assets", "bundled/furniture/chair.nitro", strings.NewReader("chair"), 5, minio.PutObjectOptions{})
+	require.NoError(t, err)
+	assert.Len(t, logs.All(), 4)
+}
@@ -0,0 +1,30 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// ReadObject returns the content of key in bucket, or false when it does not exist.
+func ReadObject(ctx context.Context, client Client, bucket, key string) ([]byte, bool, error) {
+	reader, err := client.GetObject(ctx, bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		if IsNoSuchKey(err) {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("failed to get %s: %w", key, err)
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		if IsNoSuchKey(err) {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("failed to read %s: %w", key, err)
+	}
+	return data, true, nil
+}
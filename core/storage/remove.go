@@ -0,0 +1,133 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// RemoveFailure describes an object that a batch removal could not delete.
+type RemoveFailure struct {
+	// Object is the storage key that was not deleted.
+	Object string
+	// Code is the S3 error code (e.g. "SlowDown"); empty for transport errors.
+	Code string
+	// Err is the error returned by the last attempt.
+	Err error
+	// Retriable is true when the failure was transient; it was retried until Attempts ran out.
+	Retriable bool
+	// Attempts is the number of removals tried for the object.
+	Attempts int
+}
+
+// RemoveRetry controls how retriable removal failures are retried.
+type RemoveRetry struct {
+	// Attempts is the total number of tries per object, including the first.
+	Attempts int
+	// Backoff is the wait before the first retry; it grows linearly per attempt.
+	Backoff time.Duration
+}
+
+// DefaultRemoveRetry tries each object up to three times.
+var DefaultRemoveRetry = RemoveRetry{
+	Attempts: 3,
+	Backoff:  500 * time.Millisecond,
+}
+
+// retriableCodes lists the S3 error codes that indicate a transient failure.
+var retriableCodes = map[string]struct{}{
+	"InternalError":      {},
+	"OperationAborted":   {},
+	"RequestTimeout":     {},
+	"ServiceUnavailable": {},
+	"SlowDown":           {},
+}
+
+// RemoveObjectsWithRetry deletes objects with the batch API and retries the ones that
+// failed with a retriable error. It returns the failures left after the last attempt.
+func RemoveObjectsWithRetry(ctx context.Context, client Client, bucket string, objects []string, retry RemoveRetry) []RemoveFailure {
+	attempts := retry.Attempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	pending := objects
+	var failures, retriable []RemoveFailure
+	for attempt := 1; len(pending) > 0; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-ctx.Done():
+				// Report the failures that were waiting for a retry as they stand
+				return append(failures, retriable...)
+			case <-time.After(time.Duration(attempt-1) * retry.Backoff):
+			}
+		}
+
+		retriable = nil
+		for _, failure := range removeBatch(ctx, client, bucket, pending, attempt) {
+			if failure.Retriable && attempt < attempts {
+				retriable = append(retriable, failure)
+			} else {
+				failures = append(failures, failure)
+			}
+		}
+
+		pending = make([]string, 0, len(retriable))
+		for _, failure := range retriable {
+			pending = append(pending, failure.Object)
+		}
+	}
+	return failures
+}
+
+// removeBatch runs one batch removal and classifies every failure.
+func removeBatch(ctx context.Context, client Client, bucket string, objects []string, attempt int) []RemoveFailure {
+	objectsCh := make(chan minio.ObjectInfo, len(objects))
+	for _, object := range objects {
+		objectsCh <- minio.ObjectInfo{Key: object}
+	}
+	close(objectsCh)
+
+	var failures []RemoveFailure
+	for result := range client.RemoveObjects(ctx, bucket, objectsCh, minio.RemoveObjectsOptions{}) {
+		if result.Err == nil {
+			continue
+		}
+		code, retriable := ClassifyError(result.Err)
+		failures = append(failures, RemoveFailure{
+			Object:    result.ObjectName,
+			Code:      code,
+			Err:       result.Err,
+			Retriable: retriable,
+			Attempts:  attempt,
+		})
+	}
+	return failures
+}
+
+// ClassifyError returns the S3 error code of err and whether retrying may succeed.
+// Throttling, server-side errors and network timeouts are retriable; context
+// cancellation and client errors such as AccessDenied are not.
+func ClassifyError(err error) (code string, retriable bool) {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return "", false
+	}
+
+	var resp minio.ErrorResponse
+	if errors.As(err, &resp) && resp.Code != "" {
+		if _, ok := retriableCodes[resp.Code]; ok {
+			return resp.Code, true
+		}
+		return resp.Code, resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return "", true
+	}
+	return "", false
+}
@@ -0,0 +1,101 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"asset-manager/core/storage/mocks"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// removeResults returns a closed channel holding the given removal errors.
+func removeResults(errs ...minio.RemoveObjectError) <-chan minio.RemoveObjectError {
+	ch := make(chan minio.RemoveObjectError, len(errs))
+	for _, err := range errs {
+		ch <- err
+	}
+	close(ch)
+	return ch
+}
+
+// removedObjects matches a RemoveObjects call whose channel holds exactly objects.
+func removedObjects(objects ...string) any {
+	return mock.MatchedBy(func(ch <-chan minio.ObjectInfo) bool {
+		if len(ch) != len(objects) {
+			return false
+		}
+		for _, object := range objects {
+			info := <-ch
+			if info.Key != object {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		code      string
+		retriable bool
+	}{
+		{"slow down", minio.ErrorResponse{Code: "SlowDown", StatusCode: http.StatusServiceUnavailable}, "SlowDown", true},
+		{"server error", minio.ErrorResponse{Code: "NotImplemented", StatusCode: http.StatusBadGateway}, "NotImplemented", true},
+		{"access denied", minio.ErrorResponse{Code: "AccessDenied", StatusCode: http.StatusForbidden}, "AccessDenied", false},
+		{"canceled", context.Canceled, "", false},
+		{"plain", errors.New("boom"), "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, retriable := ClassifyError(tt.err)
+			assert.Equal(t, tt.code, code)
+			assert.Equal(t, tt.retriable, retriable)
+		})
+	}
+}
+
+func TestRemoveObjectsWithRetry(t *testing.T) {
+	slowDown := minio.ErrorResponse{Code: "SlowDown", StatusCode: http.StatusServiceUnavailable}
+	denied := minio.ErrorResponse{Code: "AccessDenied", StatusCode: http.StatusForbidden}
+	retry := RemoveRetry{Attempts: 3}
+
+	t.Run("retries only retriable failures", func(t *testing.T) {
+		client := new(mocks.Client)
+		client.On("RemoveObjects", mock.Anything, "assets", removedObjects("a", "b", "c"), mock.Anything).
+			Return(removeResults(
+				minio.RemoveObjectError{ObjectName: "a", Err: slowDown},
+				minio.RemoveObjectError{ObjectName: "b", Err: denied},
+			)).Once()
+		client.On("RemoveObjects", mock.Anything, "assets", removedObjects("a"), mock.Anything).
+			Return(removeResults()).Once()
+
+		failures := RemoveObjectsWithRetry(context.Background(), client, "assets", []string{"a", "b", "c"}, retry)
+		client.AssertExpectations(t)
+		assert.Len(t, failures, 1)
+		assert.Equal(t, "b", failures[0].Object)
+		assert.Equal(t, "AccessDenied", failures[0].Code)
+		assert.False(t, failures[0].Retriable)
+		assert.Equal(t, 1, failures[0].Attempts)
+	})
+
+	t.Run("gives up after the last attempt", func(t *testing.T) {
+		client := new(mocks.Client)
+		for i := 0; i < 3; i++ {
+			client.On("RemoveObjects", mock.Anything, "assets", mock.Anything, mock.Anything).
+				Return(removeResults(minio.RemoveObjectError{ObjectName: "a", Err: slowDown})).Once()
+		}
+
+		failures := RemoveObjectsWithRetry(context.Background(), client, "assets", []string{"a"}, retry)
+		client.AssertNumberOfCalls(t, "RemoveObjects", 3)
+		assert.Len(t, failures, 1)
+		assert.True(t, failures[0].Retriable)
+		assert.Equal(t, 3, failures[0].Attempts)
+	})
+}
@@ -0,0 +1,201 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// ErrNoVersion is returned when restoring an object version without a version ID.
+var ErrNoVersion = errors.New("object version has no version ID")
+
+// ObjectVersion identifies one stored version of an object in a versioned bucket.
+type ObjectVersion struct {
+	Bucket    string `json:"bucket"`
+	Key       string `json:"key"`
+	VersionID string `json:"version_id"`
+}
+
+// VersionRecorder collects the versions that writes and removals replaced.
+type VersionRecorder struct {
+	mu       sync.Mutex
+	versions []ObjectVersion
+}
+
+// versionRecorderKey is the context key of the active VersionRecorder.
+type versionRecorderKey struct{}
+
+// WithVersionRecorder returns a context that records, through a client wrapped by
+// WithVersionRecording, the current version of every object overwritten or removed
+// under it.
+func WithVersionRecorder(ctx context.Context) (context.Context, *VersionRecorder) {
+	recorder := &VersionRecorder{}
+	return context.WithValue(ctx, versionRecorderKey{}, recorder), recorder
+}
+
+// Versions returns the recorded versions, in the order they were replaced.
+func (r *VersionRecorder) Versions() []ObjectVersion {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]ObjectVersion(nil), r.versions...)
+}
+
+// add records versions.
+func (r *VersionRecorder) add(versions ...ObjectVersion) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.versions = append(r.versions, versions...)
+}
+
+// Statter reads object metadata, including the current version ID.
+type Statter interface {
+	StatObject(ctx context.Context, bucketName, objectName string, opts minio.StatObjectOptions) (minio.ObjectInfo, error)
+}
+
+// versionClient records the replaced versions of the wrapped client's writes.
+type versionClient struct {
+	Client
+	statter Statter
+}
+
+// WithVersionRecording wraps client so PutObject, RemoveObject and RemoveObjects
+// called with a context from WithVersionRecorder first read the current version of the
+// object and record it once the change succeeded. Without a recorder the calls pass
+// through unchanged.
+//
+// Only versioned buckets keep replaced versions: objects without a version ID (or with
+// the "null" version of an unversioned bucket) and new objects are not recorded.
+func WithVersionRecording(client Client, statter Statter) Client {
+	return &versionClient{Client: client, statter: statter}
+}
+
+// PutObject uploads an object, recording the version it overwrites.
+func (c *versionClient) PutObject(ctx context.Context, bucketName, objectName string, reader io.Reader, objectSize int64, opts minio.PutObjectOptions) (minio.UploadInfo, error) {
+	recorder := recorderFrom(ctx)
+	if recorder == nil {
+		return c.Client.PutObject(ctx, bucketName, objectName, reader, objectSize, opts)
+	}
+
+	prior, ok := c.current(ctx, bucketName, objectName)
+	info, err := c.Client.PutObject(ctx, bucketName, objectName, reader, objectSize, opts)
+	if err == nil && ok {
+		recorder.add(prior)
+	}
+	return info, err
+}
+
+// RemoveObject deletes an object, recording the version it removes.
+func (c *versionClient) RemoveObject(ctx context.Context, bucketName, objectName string, opts minio.RemoveObjectOptions) error {
+	recorder := recorderFrom(ctx)
+	if recorder == nil {
+		return c.Client.RemoveObject(ctx, bucketName, objectName, opts)
+	}
+
+	prior, ok := c.current(ctx, bucketName, objectName)
+	err := c.Client.RemoveObject(ctx, bucketName, objectName, opts)
+	if err == nil && ok {
+		recorder.add(prior)
+	}
+	return err
+}
+
+// RemoveObjects deletes objects, recording the versions of the ones removed once the
+// batch is done.
+func (c *versionClient) RemoveObjects(ctx context.Context, bucketName string, objectsCh <-chan minio.ObjectInfo, opts minio.RemoveObjectsOptions) <-chan minio.RemoveObjectError {
+	recorder := recorderFrom(ctx)
+	if recorder == nil {
+		return c.Client.RemoveObjects(ctx, bucketName, objectsCh, opts)
+	}
+
+	// Read each object's version as it is handed over. stop ends the forwarding when
+	// the removal ends early so nothing blocks.
+	var priors []ObjectVersion
+	forwarded := make(chan minio.ObjectInfo)
+	stop, done := make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(done)
+		defer close(forwarded)
+		for {
+			select {
+			case object, ok := <-objectsCh:
+				if !ok {
+					return
+				}
+				prior, known := c.current(ctx, bucketName, object.Key)
+				select {
+				case forwarded <- object:
+					if known {
+						priors = append(priors, prior)
+					}
+				case <-stop:
+					return
+				}
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	errs := c.Client.RemoveObjects(ctx, bucketName, forwarded, opts)
+	out := make(chan minio.RemoveObjectError)
+	go func() {
+		defer close(out)
+		failed := make(map[string]bool)
+		for removeErr := range errs {
+			failed[removeErr.ObjectName] = true
+			out <- removeErr
+		}
+		close(stop)
+		<-done
+
+		for _, prior := range priors {
+			if !failed[prior.Key] {
+				recorder.add(prior)
+			}
+		}
+	}()
+	return out
+}
+
+// current returns the current version of bucket/key, if it has a restorable one.
+func (c *versionClient) current(ctx context.Context, bucket, key string) (ObjectVersion, bool) {
+	info, err := c.statter.StatObject(ctx, bucket, key, minio.StatObjectOptions{})
+	if err != nil || info.VersionID == "" || info.VersionID == "null" {
+		return ObjectVersion{}, false
+	}
+	return ObjectVersion{Bucket: bucket, Key: key, VersionID: info.VersionID}, true
+}
+
+// recorderFrom returns the recorder of ctx, or nil.
+func recorderFrom(ctx context.Context) *VersionRecorder {
+	recorder, _ := ctx.Value(versionRecorderKey{}).(*VersionRecorder)
+	return recorder
+}
+
+// RestoreVersion makes version the current content of its object again, by reading it
+// and writing it back as a new version. Earlier and later versions are kept.
+func RestoreVersion(ctx context.Context, client Client, version ObjectVersion) error {
+	if version.VersionID == "" {
+		return ErrNoVersion
+	}
+
+	reader, err := client.GetObject(ctx, version.Bucket, version.Key, minio.GetObjectOptions{VersionID: version.VersionID})
+	if err != nil {
+		return fmt.Errorf("failed to read %s version %s: %w", version.Key, version.VersionID, err)
+	}
+	data, err := io.ReadAll(reader)
+	reader.Close()
+	if err != nil {
+		return fmt.Errorf("failed to read %s version %s: %w", version.Key, version.VersionID, err)
+	}
+
+	if _, err := client.PutObject(ctx, version.Bucket, version.Key, bytes.NewReader(data), int64(len(data)), minio.PutObjectOptions{}); err != nil {
+		return fmt.Errorf("failed to restore %s version %s: %w", version.Key, version.VersionID, err)
+	}
+	return nil
+}
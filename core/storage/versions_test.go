@@ -0,0 +1,111 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"asset-manager/core/storage/mocks"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeStatter returns the version IDs of known keys and NoSuchKey otherwise.
+type fakeStatter map[string]string
+
+func (s fakeStatter) StatObject(ctx context.Context, bucketName, objectName string, opts minio.StatObjectOptions) (minio.ObjectInfo, error) {
+	versionID, ok := s[objectName]
+	if !ok {
+		return minio.ObjectInfo{}, minio.ErrorResponse{Code: "NoSuchKey"}
+	}
+	return minio.ObjectInfo{Key: objectName, VersionID: versionID}, nil
+}
+
+func TestVersionRecording_PutAndRemove(t *testing.T) {
+	inner := new(mocks.Client)
+	inner.On("PutObject", mock.Anything, "assets", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(minio.UploadInfo{}, nil)
+	inner.On("RemoveObject", mock.Anything, "assets", "chair.nitro", mock.Anything).Return(nil)
+	inner.On("RemoveObject", mock.Anything, "assets", "denied.nitro", mock.Anything).Return(errors.New("denied"))
+
+	client := WithVersionRecording(inner, fakeStatter{
+		"gamedata/FurnitureData.json": "v1",
+		"chair.nitro":                 "v2",
+		"denied.nitro":                "v3",
+		"unversioned.nitro":           "null",
+	})
+
+	// Without a recorder nothing is read or recorded
+	_, err := client.PutObject(context.Background(), "assets", "gamedata/FurnitureData.json", bytes.NewReader(nil), 0, minio.PutObjectOptions{})
+	require.NoError(t, err)
+
+	ctx, recorder := WithVersionRecorder(context.Background())
+	_, err = client.PutObject(ctx, "assets", "gamedata/FurnitureData.json", bytes.NewReader(nil), 0, minio.PutObjectOptions{})
+	require.NoError(t, err)
+	_, err = client.PutObject(ctx, "assets", "new.nitro", bytes.NewReader(nil), 0, minio.PutObjectOptions{})
+	require.NoError(t, err)
+	_, err = client.PutObject(ctx, "assets", "unversioned.nitro", bytes.NewReader(nil), 0, minio.PutObjectOptions{})
+	require.NoError(t, err)
+	require.NoError(t, client.RemoveObject(ctx, "assets", "chair.nitro", minio.RemoveObjectOptions{}))
+	require.Error(t, client.RemoveObject(ctx, "assets", "denied.nitro", minio.RemoveObjectOptions{}))
+
+	assert.Equal(t, []ObjectVersion{
+		{Bucket: "assets", Key: "gamedata/FurnitureData.json", VersionID: "v1"},
+		{Bucket: "assets", Key: "chair.nitro", VersionID: "v2"},
+	}, recorder.Versions())
+}
+
+func TestVersionRecording_RemoveObjects(t *testing.T) {
+	inner := new(mocks.Client)
+	errs := make(chan minio.RemoveObjectError, 1)
+	errs <- minio.RemoveObjectError{ObjectName: "b.nitro", Err: errors.New("denied")}
+	close(errs)
+	inner.On("RemoveObjects", mock.Anything, "assets", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			for range args.Get(2).(<-chan minio.ObjectInfo) {
+			}
+		}).
+		Return((<-chan minio.RemoveObjectError)(errs))
+
+	client := WithVersionRecording(inner, fakeStatter{"a.nitro": "va", "b.nitro": "vb"})
+	ctx, recorder := WithVersionRecorder(context.Background())
+
+	objects := make(chan minio.ObjectInfo, 3)
+	objects <- minio.ObjectInfo{Key: "a.nitro"}
+	objects <- minio.ObjectInfo{Key: "b.nitro"}
+	objects <- minio.ObjectInfo{Key: "c.nitro"}
+	close(objects)
+
+	var failed []string
+	for removeErr := range client.RemoveObjects(ctx, "assets", objects, minio.RemoveObjectsOptions{}) {
+		failed = append(failed, removeErr.ObjectName)
+	}
+
+	assert.Equal(t, []string{"b.nitro"}, failed)
+	assert.Equal(t, []ObjectVersion{{Bucket: "assets", Key: "a.nitro", VersionID: "va"}}, recorder.Versions())
+}
+
+func TestRestoreVersion(t *testing.T) {
+	client := new(mocks.Client)
+	client.On("GetObject", mock.Anything, "assets", "chair.nitro", minio.GetObjectOptions{VersionID: "v2"}).
+		Return(io.NopCloser(strings.NewReader("nitro")), nil)
+	var written []byte
+	client.On("PutObject", mock.Anything, "assets", "chair.nitro", mock.Anything, int64(5), mock.Anything).
+		Run(func(args mock.Arguments) {
+			written, _ = io.ReadAll(args.Get(3).(io.Reader))
+		}).
+		Return(minio.UploadInfo{}, nil)
+
+	err := RestoreVersion(context.Background(), client, ObjectVersion{Bucket: "assets", Key: "chair.nitro", VersionID: "v2"})
+	require.NoError(t, err)
+	assert.Equal(t, "nitro", string(written))
+
+	err = RestoreVersion(context.Background(), client, ObjectVersion{Bucket: "assets", Key: "chair.nitro"})
+	assert.ErrorIs(t, err, ErrNoVersion)
+}
@@ -0,0 +1,79 @@
+package upload
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+// clamChunkSize is the INSTREAM chunk size, well below clamd's StreamMaxLength.
+const clamChunkSize = 64 << 10
+
+// ClamAV scans with a clamd daemon using the INSTREAM command.
+type ClamAV struct {
+	// Addr is the clamd TCP address (host:port).
+	Addr string
+	// Timeout bounds the whole scan.
+	Timeout time.Duration
+}
+
+// Scan streams r to clamd and parses its reply ("stream: OK" or "stream: <name> FOUND").
+func (c *ClamAV) Scan(ctx context.Context, name string, r io.Reader) (Verdict, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.Timeout)
+	defer cancel()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", c.Addr)
+	if err != nil {
+		return Verdict{}, fmt.Errorf("failed to connect to clamd: %w", err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	if _, err := io.WriteString(conn, "zINSTREAM\x00"); err != nil {
+		return Verdict{}, fmt.Errorf("failed to send to clamd: %w", err)
+	}
+	buf := make([]byte, clamChunkSize)
+	size := make([]byte, 4)
+	for {
+		n, readErr := r.Read(buf)
+		if n > 0 {
+			binary.BigEndian.PutUint32(size, uint32(n))
+			if _, err := conn.Write(append(size, buf[:n]...)); err != nil {
+				return Verdict{}, fmt.Errorf("failed to send to clamd: %w", err)
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return Verdict{}, readErr
+		}
+	}
+	// A zero-length chunk ends the stream
+	binary.BigEndian.PutUint32(size, 0)
+	if _, err := conn.Write(size); err != nil {
+		return Verdict{}, fmt.Errorf("failed to send to clamd: %w", err)
+	}
+
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil && reply == "" {
+		return Verdict{}, fmt.Errorf("failed to read clamd reply: %w", err)
+	}
+	reply = strings.TrimSpace(strings.TrimRight(reply, "\x00"))
+	result := strings.TrimSpace(strings.TrimPrefix(reply, "stream:"))
+	switch {
+	case result == "OK":
+		return Verdict{Clean: true}, nil
+	case strings.HasSuffix(result, " FOUND"):
+		return Verdict{Signature: strings.TrimSuffix(result, " FOUND")}, nil
+	}
+	return Verdict{}, fmt.Errorf("clamd: %s", reply)
+}
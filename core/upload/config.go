@@ -0,0 +1,51 @@
+package upload
+
+import "time"
+
+// Config holds the limits applied to uploaded assets.
+type Config struct {
+	// MaxBodySize is the largest request body the server reads on any route, in bytes.
+	MaxBodySize int64 `mapstructure:"max_body_size" default:"67108864"`
+	// MaxImportSize is the largest request body of the furniture import route, in
+	// bytes; zero leaves the route to MaxBodySize.
+	MaxImportSize int64 `mapstructure:"max_import_size" default:"33554432"`
+	// MaxFileSize is the largest single uploaded file, in bytes.
+	MaxFileSize int64 `mapstructure:"max_file_size" default:"16777216"`
+	// Extensions lists the accepted file extensions, with the leading dot.
+	Extensions []string `mapstructure:"extensions" default:".nitro"`
+	// Scan configures the optional malware scan of uploaded and imported files.
+	Scan ScanConfig `mapstructure:"scan"`
+	// Convert configures the optional conversion of legacy .swf furniture.
+	Convert ConvertConfig `mapstructure:"convert"`
+}
+
+// ConvertConfig configures the external converter turning legacy .swf furniture into
+// Nitro bundles.
+type ConvertConfig struct {
+	// Command runs a converter per file, e.g. "swf2nitro {input} {output}". Without
+	// placeholders the .swf is piped to stdin and the bundle read from stdout.
+	Command string `mapstructure:"command" default:""`
+	// URL posts each .swf to a conversion service answering with the bundle. It takes
+	// precedence over Command; both empty disable conversion.
+	URL string `mapstructure:"url" default:""`
+	// Timeout bounds one conversion.
+	Timeout time.Duration `mapstructure:"timeout" default:"2m"`
+	// SourcePrefix is where legacy .swf files are looked for, in the assets bucket.
+	SourcePrefix string `mapstructure:"source_prefix" default:"swf/"`
+}
+
+// Enabled reports whether a converter is configured.
+func (c ConvertConfig) Enabled() bool {
+	return c.Command != "" || c.URL != ""
+}
+
+// ScanConfig configures the malware scanner invoked before files are written.
+type ScanConfig struct {
+	// URL selects the scanner: clamd://host:3310 (ClamAV INSTREAM) or
+	// icap://host:1344/service (ICAP RESPMOD). Empty disables scanning.
+	URL string `mapstructure:"url" default:""`
+	// Timeout bounds one scan, including the connection.
+	Timeout time.Duration `mapstructure:"timeout" default:"30s"`
+	// QuarantinePrefix is where rejected files are kept for inspection, in the assets bucket.
+	QuarantinePrefix string `mapstructure:"quarantine_prefix" default:"quarantine/"`
+}
@@ -0,0 +1,199 @@
+package upload
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrNoConverter is returned by ConvertSWF when no converter is configured.
+	ErrNoConverter = errors.New("no swf converter configured")
+	// ErrConversion is returned when the converter fails or returns no valid Nitro bundle.
+	ErrConversion = errors.New("swf conversion failed")
+)
+
+// Converter converts legacy .swf furniture files to Nitro bundles.
+type Converter interface {
+	// Convert returns the Nitro bundle of the .swf file name holding data.
+	Convert(ctx context.Context, name string, data []byte) ([]byte, error)
+}
+
+// IsSWF reports whether name is a legacy .swf file.
+func IsSWF(name string) bool {
+	return strings.EqualFold(path.Ext(name), ".swf")
+}
+
+// NewConverter returns the converter selected by cfg: an HTTP service for URL, else an
+// external command for Command, or nil when conversion is disabled.
+func NewConverter(cfg ConvertConfig) (Converter, error) {
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = 2 * time.Minute
+	}
+	switch {
+	case cfg.URL != "":
+		u, err := url.Parse(cfg.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return nil, fmt.Errorf("unsupported convert url %q: must start with http:// or https://", cfg.URL)
+		}
+		return &HTTPConverter{URL: cfg.URL, Client: &http.Client{Timeout: timeout}}, nil
+	case cfg.Command != "":
+		return &CommandConverter{Args: strings.Fields(cfg.Command), Timeout: timeout}, nil
+	}
+	return nil, nil
+}
+
+// CommandConverter runs an external converter per file. "{input}" and "{output}" in
+// Args are replaced by temporary file paths; without them the .swf is written to the
+// command's stdin and the bundle read from its stdout.
+type CommandConverter struct {
+	// Args is the command and its arguments.
+	Args []string
+	// Timeout bounds one conversion.
+	Timeout time.Duration
+}
+
+// Convert runs the command on data.
+func (c *CommandConverter) Convert(ctx context.Context, name string, data []byte) ([]byte, error) {
+	if len(c.Args) == 0 {
+		return nil, errors.New("empty convert command")
+	}
+	ctx, cancel := context.WithTimeout(ctx, c.Timeout)
+	defer cancel()
+
+	dir, err := os.MkdirTemp("", "swf-convert-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	base := strings.TrimSuffix(path.Base(name), path.Ext(name))
+	input := filepath.Join(dir, base+".swf")
+	output := filepath.Join(dir, base+".nitro")
+	args := make([]string, len(c.Args))
+	usesInput, usesOutput := false, false
+	for i, arg := range c.Args {
+		usesInput = usesInput || strings.Contains(arg, "{input}")
+		usesOutput = usesOutput || strings.Contains(arg, "{output}")
+		args[i] = strings.NewReplacer("{input}", input, "{output}", output).Replace(arg)
+	}
+
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	if usesInput {
+		if err := os.WriteFile(input, data, 0o600); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", input, err)
+		}
+	} else {
+		cmd.Stdin = bytes.NewReader(data)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+
+	if !usesOutput {
+		return stdout.Bytes(), nil
+	}
+	bundle, err := os.ReadFile(output)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read converter output: %w", err)
+	}
+	return bundle, nil
+}
+
+// HTTPConverter posts each .swf file to a conversion service, which answers with the
+// Nitro bundle. The file name is sent in the "name" query parameter.
+type HTTPConverter struct {
+	// URL is the conversion endpoint.
+	URL string
+	// Client sends the requests; its timeout bounds one conversion.
+	Client *http.Client
+}
+
+// Convert posts data to the service.
+func (c *HTTPConverter) Convert(ctx context.Context, name string, data []byte) ([]byte, error) {
+	u, err := url.Parse(c.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid convert url: %w", err)
+	}
+	query := u.Query()
+	query.Set("name", path.Base(name))
+	u.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-shockwave-flash")
+
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach converter: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read converter response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("converter: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
+
+// convertRegistry holds the process-wide converter.
+type convertRegistry struct {
+	mu        sync.RWMutex
+	converter Converter
+}
+
+// globalConvert is the singleton converter used by imports and conversion runs.
+var globalConvert = &convertRegistry{}
+
+// SetConverter registers the converter run by ConvertSWF. Passing nil disables conversion.
+func SetConverter(converter Converter) {
+	globalConvert.mu.Lock()
+	defer globalConvert.mu.Unlock()
+	globalConvert.converter = converter
+}
+
+// CanConvert reports whether a converter is registered.
+func CanConvert() bool {
+	globalConvert.mu.RLock()
+	defer globalConvert.mu.RUnlock()
+	return globalConvert.converter != nil
+}
+
+// ConvertSWF converts the .swf file name with the registered converter and checks the
+// result is a Nitro bundle. Errors wrap ErrNoConverter or ErrConversion.
+func ConvertSWF(ctx context.Context, name string, data []byte) ([]byte, error) {
+	globalConvert.mu.RLock()
+	converter := globalConvert.converter
+	globalConvert.mu.RUnlock()
+
+	if converter == nil {
+		return nil, ErrNoConverter
+	}
+	bundle, err := converter.Convert(ctx, name, data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrConversion, name, err)
+	}
+	if err := SniffNitro(bundle); err != nil {
+		return nil, fmt.Errorf("%w: %s: converter output is not a Nitro bundle: %v", ErrConversion, name, err)
+	}
+	return bundle, nil
+}
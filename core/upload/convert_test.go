@@ -0,0 +1,91 @@
+package upload
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeConverter answers every file with bundle.
+type fakeConverter struct {
+	bundle []byte
+}
+
+func (f fakeConverter) Convert(ctx context.Context, name string, data []byte) ([]byte, error) {
+	return f.bundle, nil
+}
+
+func TestNewConverter(t *testing.T) {
+	converter, err := NewConverter(ConvertConfig{})
+	assert.NoError(t, err)
+	assert.Nil(t, converter)
+
+	converter, err = NewConverter(ConvertConfig{Command: "swf2nitro {input} {output}"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"swf2nitro", "{input}", "{output}"}, converter.(*CommandConverter).Args)
+
+	converter, err = NewConverter(ConvertConfig{Command: "swf2nitro", URL: "http://converter/convert"})
+	require.NoError(t, err)
+	assert.Equal(t, "http://converter/convert", converter.(*HTTPConverter).URL)
+
+	_, err = NewConverter(ConvertConfig{URL: "ftp://converter"})
+	assert.ErrorContains(t, err, "unsupported convert url")
+}
+
+func TestConverters(t *testing.T) {
+	bundle := nitroBundle(t, "chair.json", []byte(`{"name":"chair"}`))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "chair.swf", r.URL.Query().Get("name"))
+		assert.Equal(t, "application/x-shockwave-flash", r.Header.Get("Content-Type"))
+		io.Copy(w, r.Body)
+	}))
+	defer server.Close()
+
+	converters := map[string]Converter{
+		"stdio": &CommandConverter{Args: []string{"cat"}, Timeout: time.Second},
+		"files": &CommandConverter{Args: []string{"cp", "{input}", "{output}"}, Timeout: time.Second},
+		"http":  &HTTPConverter{URL: server.URL + "/convert", Client: server.Client()},
+	}
+	for name, converter := range converters {
+		t.Run(name, func(t *testing.T) {
+			out, err := converter.Convert(context.Background(), "swf/chair.swf", bundle)
+			require.NoError(t, err)
+			assert.Equal(t, bundle, out)
+		})
+	}
+
+	_, err := (&CommandConverter{Args: []string{"false"}, Timeout: time.Second}).Convert(context.Background(), "chair.swf", bundle)
+	assert.ErrorContains(t, err, "false: exit status 1")
+}
+
+func TestConvertSWF(t *testing.T) {
+	ctx := context.Background()
+	assert.True(t, IsSWF("swf/Chair.SWF"))
+	assert.False(t, IsSWF("bundled/furniture/chair.nitro"))
+
+	SetConverter(nil)
+	assert.False(t, CanConvert())
+	_, err := ConvertSWF(ctx, "chair.swf", []byte("FWS"))
+	assert.ErrorIs(t, err, ErrNoConverter)
+
+	bundle := nitroBundle(t, "chair.json", []byte(`{"name":"chair"}`))
+	SetConverter(fakeConverter{bundle: bundle})
+	defer SetConverter(nil)
+	assert.True(t, CanConvert())
+	out, err := ConvertSWF(ctx, "chair.swf", []byte("FWS"))
+	require.NoError(t, err)
+	assert.Equal(t, bundle, out)
+
+	// Output that is not a Nitro bundle is refused
+	SetConverter(fakeConverter{bundle: []byte("\x89PNG\r\n")})
+	_, err = ConvertSWF(ctx, "chair.swf", []byte("FWS"))
+	assert.ErrorIs(t, err, ErrConversion)
+	assert.ErrorContains(t, err, "not a Nitro bundle")
+}
@@ -0,0 +1,22 @@
+// Package upload guards the endpoints that accept asset files.
+//
+// Every file is checked before it reaches a bucket:
+//
+//   - Size: requests above the route limit are refused with 413 before they are read
+//     (Limit), and single files above MaxFileSize are refused by Check.
+//   - Extension: only the configured extensions (default .nitro) are accepted.
+//   - Content: .nitro payloads must start with a valid Nitro bundle header (file count,
+//     file name and a zlib stream), so renamed images, archives or executables are
+//     rejected even with the right extension.
+//
+// With a scanner configured (ClamAV or ICAP, see NewScanner), ScanObject scans each file
+// before it is written and moves rejected files to a quarantine prefix. Scanning fails
+// closed: a file that could not be scanned is not written.
+//
+// With a converter configured (NewConverter: an external command or an HTTP service),
+// ConvertSWF turns legacy .swf furniture into Nitro bundles, refusing output that is
+// not one.
+//
+// The server applies MaxBodySize to every request; the furniture import route adds its
+// own, lower MaxImportSize with Limit.
+package upload
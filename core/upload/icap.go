@@ -0,0 +1,97 @@
+package upload
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/textproto"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ICAP scans with an ICAP server (c-icap, Kaspersky, ...) using RESPMOD: the file is
+// sent as the body of an HTTP response, and anything but 204 No Content means the
+// server wanted to change it, i.e. it found something.
+type ICAP struct {
+	// URL is the service URL, e.g. icap://scanner:1344/avscan.
+	URL *url.URL
+	// Timeout bounds the whole scan.
+	Timeout time.Duration
+}
+
+// Scan sends r to the ICAP service and interprets the status of its reply.
+func (s *ICAP) Scan(ctx context.Context, name string, r io.Reader) (Verdict, error) {
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return Verdict{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.Timeout)
+	defer cancel()
+
+	addr := s.URL.Host
+	if s.URL.Port() == "" {
+		addr = net.JoinHostPort(s.URL.Hostname(), "1344")
+	}
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return Verdict{}, fmt.Errorf("failed to connect to icap server: %w", err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	httpHeader := fmt.Sprintf("HTTP/1.1 200 OK\r\nContent-Type: application/octet-stream\r\nContent-Length: %d\r\n\r\n", len(body))
+	var req bytes.Buffer
+	fmt.Fprintf(&req, "RESPMOD %s ICAP/1.0\r\n", s.URL.String())
+	fmt.Fprintf(&req, "Host: %s\r\n", s.URL.Host)
+	fmt.Fprintf(&req, "Allow: 204\r\n")
+	fmt.Fprintf(&req, "X-Filename: %s\r\n", name)
+	fmt.Fprintf(&req, "Encapsulated: res-hdr=0, res-body=%d\r\n\r\n", len(httpHeader))
+	req.WriteString(httpHeader)
+	fmt.Fprintf(&req, "%x\r\n", len(body))
+	req.Write(body)
+	req.WriteString("\r\n0\r\n\r\n")
+	if _, err := conn.Write(req.Bytes()); err != nil {
+		return Verdict{}, fmt.Errorf("failed to send to icap server: %w", err)
+	}
+
+	tp := textproto.NewReader(bufio.NewReader(conn))
+	status, err := tp.ReadLine()
+	if err != nil {
+		return Verdict{}, fmt.Errorf("failed to read icap reply: %w", err)
+	}
+	header, _ := tp.ReadMIMEHeader()
+
+	fields := strings.Fields(status)
+	if len(fields) < 2 || !strings.HasPrefix(fields[0], "ICAP/") {
+		return Verdict{}, fmt.Errorf("invalid icap reply %q", status)
+	}
+	switch fields[1] {
+	case "204":
+		return Verdict{Clean: true}, nil
+	case "200":
+		return Verdict{Signature: icapThreat(header)}, nil
+	}
+	return Verdict{}, fmt.Errorf("icap server answered %q", status)
+}
+
+// icapThreat extracts the threat name from the headers servers commonly set.
+func icapThreat(header textproto.MIMEHeader) string {
+	// X-Infection-Found: Type=0; Resolution=2; Threat=Eicar-Test-Signature;
+	for _, part := range strings.Split(header.Get("X-Infection-Found"), ";") {
+		if threat, ok := strings.CutPrefix(strings.TrimSpace(part), "Threat="); ok && threat != "" {
+			return threat
+		}
+	}
+	if id := header.Get("X-Virus-ID"); id != "" {
+		return id
+	}
+	return "threat"
+}
@@ -0,0 +1,51 @@
+package upload
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// SniffNitro checks that data starts like a Nitro bundle: a big-endian uint16 file
+// count, then per file a uint16 name length, the name, a uint32 data length and a
+// zlib stream. Only the first entry is inspected; decompression is left to the client.
+func SniffNitro(data []byte) error {
+	if len(data) < 2 {
+		return errors.New("too short for a nitro bundle")
+	}
+	if count := binary.BigEndian.Uint16(data); count == 0 {
+		return errors.New("nitro bundle holds no files")
+	}
+	rest := data[2:]
+
+	if len(rest) < 2 {
+		return errors.New("truncated nitro file name")
+	}
+	nameLen := int(binary.BigEndian.Uint16(rest))
+	rest = rest[2:]
+	if nameLen == 0 || nameLen > len(rest) {
+		return errors.New("invalid nitro file name length")
+	}
+	for _, b := range rest[:nameLen] {
+		if b < 0x20 || b > 0x7e {
+			return errors.New("nitro file name is not printable")
+		}
+	}
+	rest = rest[nameLen:]
+
+	if len(rest) < 4 {
+		return errors.New("truncated nitro file length")
+	}
+	size := binary.BigEndian.Uint32(rest)
+	rest = rest[4:]
+	if size < 2 || uint64(size) > uint64(len(rest)) {
+		return fmt.Errorf("nitro file length %d exceeds the payload", size)
+	}
+
+	// zlib header: deflate method with a valid header checksum (RFC 1950)
+	cmf, flg := rest[0], rest[1]
+	if cmf&0x0f != 8 || (uint16(cmf)<<8|uint16(flg))%31 != 0 {
+		return errors.New("nitro file data is not zlib compressed")
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package upload
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"asset-manager/core/storage"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// ErrInfected is returned when the scanner rejects a file.
+var ErrInfected = errors.New("file rejected by malware scan")
+
+// InfectedError reports a file rejected by the scanner and where it was quarantined.
+type InfectedError struct {
+	// Key is the object the file was meant to be written to.
+	Key string
+	// Signature names what the scanner found.
+	Signature string
+	// Quarantine is the object holding the file; empty when quarantining failed.
+	Quarantine string
+}
+
+func (e *InfectedError) Error() string {
+	msg := fmt.Sprintf("%s: %s found", e.Key, e.Signature)
+	if e.Quarantine != "" {
+		msg += ", quarantined as " + e.Quarantine
+	}
+	return msg
+}
+
+// Is makes errors.Is(err, ErrInfected) match.
+func (e *InfectedError) Is(target error) bool {
+	return target == ErrInfected
+}
+
+// Verdict is the outcome of a scan.
+type Verdict struct {
+	// Clean is true when nothing was found.
+	Clean bool
+	// Signature names the threat found when Clean is false.
+	Signature string
+}
+
+// Scanner scans file contents for malware.
+type Scanner interface {
+	// Scan reads r to the end and reports what was found. An error means the file
+	// could not be scanned, not that it is infected.
+	Scan(ctx context.Context, name string, r io.Reader) (Verdict, error)
+}
+
+// NewScanner returns the scanner selected by cfg.URL, or nil when scanning is disabled.
+func NewScanner(cfg ScanConfig) (Scanner, error) {
+	if cfg.URL == "" {
+		return nil, nil
+	}
+	u, err := url.Parse(cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid scan url: %w", err)
+	}
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	switch u.Scheme {
+	case "clamd":
+		return &ClamAV{Addr: u.Host, Timeout: timeout}, nil
+	case "icap":
+		return &ICAP{URL: u, Timeout: timeout}, nil
+	}
+	return nil, fmt.Errorf("unsupported scan url %q: must start with clamd:// or icap://", cfg.URL)
+}
+
+// scanRegistry holds the process-wide scanner.
+type scanRegistry struct {
+	mu         sync.RWMutex
+	scanner    Scanner
+	quarantine string
+}
+
+// globalScan is the singleton scanner used by uploads and imports.
+var globalScan = &scanRegistry{}
+
+// SetScanner registers the scanner run by ScanObject and the prefix rejected files
+// are quarantined under. Passing nil disables scanning.
+func SetScanner(scanner Scanner, quarantinePrefix string) {
+	globalScan.mu.Lock()
+	defer globalScan.mu.Unlock()
+	globalScan.scanner = scanner
+	globalScan.quarantine = quarantinePrefix
+}
+
+// ScanObject scans data meant for bucket/key before it is written. A rejected file is
+// written to the quarantine prefix instead and an *InfectedError is returned; the
+// caller must not write key. Without a registered scanner it returns nil.
+func ScanObject(ctx context.Context, client storage.Client, bucket, key string, data []byte) error {
+	globalScan.mu.RLock()
+	scanner, prefix := globalScan.scanner, globalScan.quarantine
+	globalScan.mu.RUnlock()
+
+	if scanner == nil {
+		return nil
+	}
+
+	verdict, err := scanner.Scan(ctx, key, bytes.NewReader(data))
+	if err != nil {
+		// Fail closed: an unscanned file is never written
+		return fmt.Errorf("failed to scan %s: %w", key, err)
+	}
+	if verdict.Clean {
+		return nil
+	}
+
+	infected := &InfectedError{Key: key, Signature: verdict.Signature}
+	if prefix == "" {
+		return infected
+	}
+	quarantine := strings.TrimSuffix(prefix, "/") + "/" + key
+	if _, err := client.PutObject(ctx, bucket, quarantine, bytes.NewReader(data), int64(len(data)), minio.PutObjectOptions{}); err != nil {
+		return fmt.Errorf("%w (failed to quarantine: %v)", infected, err)
+	}
+	infected.Quarantine = quarantine
+	return infected
+}
@@ -0,0 +1,172 @@
+package upload
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+
+	"asset-manager/core/storage/mocks"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// eicar is the standard antivirus test string.
+const eicar = `X5O!P%@AP[4\PZX54(P^)7CC)7}$EICAR-STANDARD-ANTIVIRUS-TEST-FILE!$H+H*`
+
+// serve accepts one connection per scan on a local port and answers with handle.
+func serve(t *testing.T, handle func(conn net.Conn)) string {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			handle(conn)
+			conn.Close()
+		}
+	}()
+	return ln.Addr().String()
+}
+
+// fakeClamd reads an INSTREAM request and reports eicar as infected.
+func fakeClamd(conn net.Conn) {
+	r := bufio.NewReader(conn)
+	if cmd, _ := r.ReadString(0); cmd != "zINSTREAM\x00" {
+		io.WriteString(conn, "UNKNOWN COMMAND\x00")
+		return
+	}
+	var data []byte
+	size := make([]byte, 4)
+	for {
+		io.ReadFull(r, size)
+		n := binary.BigEndian.Uint32(size)
+		if n == 0 {
+			break
+		}
+		chunk := make([]byte, n)
+		io.ReadFull(r, chunk)
+		data = append(data, chunk...)
+	}
+	if strings.Contains(string(data), "EICAR") {
+		io.WriteString(conn, "stream: Eicar-Test-Signature FOUND\x00")
+		return
+	}
+	io.WriteString(conn, "stream: OK\x00")
+}
+
+// fakeICAP reads a RESPMOD request and reports eicar as infected.
+func fakeICAP(conn net.Conn) {
+	tp := textproto.NewReader(bufio.NewReader(conn))
+	line, _ := tp.ReadLine()
+	if !strings.HasPrefix(line, "RESPMOD ") {
+		io.WriteString(conn, "ICAP/1.0 400 Bad Request\r\n\r\n")
+		return
+	}
+	tp.ReadMIMEHeader() // ICAP headers
+	tp.ReadLine()       // encapsulated HTTP status line
+	tp.ReadMIMEHeader() // encapsulated HTTP headers
+	var data strings.Builder
+	for {
+		size, _ := tp.ReadLine()
+		if size == "0" || size == "" {
+			break
+		}
+		chunk, _ := tp.ReadLine()
+		data.WriteString(chunk)
+	}
+	if strings.Contains(data.String(), "EICAR") {
+		io.WriteString(conn, "ICAP/1.0 200 OK\r\nX-Infection-Found: Type=0; Resolution=2; Threat=Eicar-Test-Signature;\r\n\r\n")
+		return
+	}
+	io.WriteString(conn, "ICAP/1.0 204 No Content\r\n\r\n")
+}
+
+func TestNewScanner(t *testing.T) {
+	scanner, err := NewScanner(ScanConfig{})
+	assert.NoError(t, err)
+	assert.Nil(t, scanner)
+
+	scanner, err = NewScanner(ScanConfig{URL: "clamd://clamav:3310"})
+	require.NoError(t, err)
+	assert.Equal(t, "clamav:3310", scanner.(*ClamAV).Addr)
+
+	scanner, err = NewScanner(ScanConfig{URL: "icap://scanner/avscan"})
+	require.NoError(t, err)
+	assert.Equal(t, "/avscan", scanner.(*ICAP).URL.Path)
+
+	_, err = NewScanner(ScanConfig{URL: "http://scanner"})
+	assert.ErrorContains(t, err, "unsupported scan url")
+}
+
+func TestScanners(t *testing.T) {
+	clamd, err := NewScanner(ScanConfig{URL: "clamd://" + serve(t, fakeClamd), Timeout: time.Second})
+	require.NoError(t, err)
+	icap, err := NewScanner(ScanConfig{URL: "icap://" + serve(t, fakeICAP) + "/avscan", Timeout: time.Second})
+	require.NoError(t, err)
+
+	for name, scanner := range map[string]Scanner{"clamd": clamd, "icap": icap} {
+		t.Run(name, func(t *testing.T) {
+			verdict, err := scanner.Scan(context.Background(), "chair.nitro", strings.NewReader("clean content"))
+			require.NoError(t, err)
+			assert.True(t, verdict.Clean)
+
+			verdict, err = scanner.Scan(context.Background(), "chair.nitro", strings.NewReader(eicar))
+			require.NoError(t, err)
+			assert.False(t, verdict.Clean)
+			assert.Equal(t, "Eicar-Test-Signature", verdict.Signature)
+		})
+	}
+}
+
+func TestScanObject(t *testing.T) {
+	ctx := context.Background()
+	mockClient := new(mocks.Client)
+
+	// No scanner: nothing is scanned
+	SetScanner(nil, "")
+	assert.NoError(t, ScanObject(ctx, mockClient, "assets", "bundled/furniture/chair.nitro", []byte(eicar)))
+
+	scanner, err := NewScanner(ScanConfig{URL: "clamd://" + serve(t, fakeClamd), Timeout: time.Second})
+	require.NoError(t, err)
+	SetScanner(scanner, "quarantine/")
+	defer SetScanner(nil, "")
+
+	assert.NoError(t, ScanObject(ctx, mockClient, "assets", "bundled/furniture/chair.nitro", []byte("clean")))
+
+	mockClient.On("PutObject", mock.Anything, "assets", "quarantine/bundled/furniture/chair.nitro", mock.Anything, int64(len(eicar)), mock.Anything).
+		Return(minio.UploadInfo{}, nil)
+	err = ScanObject(ctx, mockClient, "assets", "bundled/furniture/chair.nitro", []byte(eicar))
+	assert.ErrorIs(t, err, ErrInfected)
+	var infected *InfectedError
+	require.ErrorAs(t, err, &infected)
+	assert.Equal(t, "quarantine/bundled/furniture/chair.nitro", infected.Quarantine)
+	mockClient.AssertNumberOfCalls(t, "PutObject", 1)
+}
+
+func TestScanObject_Unreachable(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := ln.Addr().String()
+	ln.Close()
+
+	scanner, err := NewScanner(ScanConfig{URL: "clamd://" + addr, Timeout: time.Second})
+	require.NoError(t, err)
+	SetScanner(scanner, "quarantine/")
+	defer SetScanner(nil, "")
+
+	err = ScanObject(context.Background(), new(mocks.Client), "assets", "chair.nitro", []byte("data"))
+	assert.ErrorContains(t, err, "failed to scan chair.nitro")
+	assert.NotErrorIs(t, err, ErrInfected)
+}
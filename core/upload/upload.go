@@ -0,0 +1,89 @@
+package upload
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+var (
+	// ErrTooLarge is returned for files above the size limit.
+	ErrTooLarge = errors.New("file too large")
+	// ErrExtension is returned for files whose extension is not accepted.
+	ErrExtension = errors.New("file extension not allowed")
+	// ErrContent is returned when the content does not match the extension.
+	ErrContent = errors.New("file content does not match its extension")
+)
+
+// Check validates one uploaded file against cfg: its size, its extension and, for
+// .nitro files, its content. Errors wrap ErrTooLarge, ErrExtension or ErrContent.
+func (c Config) Check(name string, data []byte) error {
+	if c.MaxFileSize > 0 && int64(len(data)) > c.MaxFileSize {
+		return fmt.Errorf("%w: %s is %d bytes (limit %d)", ErrTooLarge, name, len(data), c.MaxFileSize)
+	}
+
+	if err := c.CheckName(name); err != nil {
+		return err
+	}
+
+	if strings.EqualFold(path.Ext(name), ".nitro") {
+		if err := SniffNitro(data); err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrContent, name, err)
+		}
+	}
+	return nil
+}
+
+// CheckName validates the extension of a file name alone, for writes whose content
+// the manager never sees. Errors wrap ErrExtension.
+func (c Config) CheckName(name string) error {
+	if !c.allowed(strings.ToLower(path.Ext(name))) {
+		return fmt.Errorf("%w: %q (allowed: %s)", ErrExtension, name, strings.Join(c.Extensions, ", "))
+	}
+	return nil
+}
+
+// allowed reports whether ext is one of the accepted extensions.
+func (c Config) allowed(ext string) bool {
+	for _, allowed := range c.Extensions {
+		if strings.EqualFold(strings.TrimSpace(allowed), ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// Limit returns a middleware refusing request bodies larger than max bytes with 413.
+// Requests announcing their size are refused before the body is used. A max of zero
+// disables the limit.
+func Limit(max int64) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if max <= 0 {
+			return c.Next()
+		}
+		size := int64(c.Request().Header.ContentLength())
+		if body := int64(len(c.Body())); body > size {
+			size = body
+		}
+		if size > max {
+			return c.Status(fiber.StatusRequestEntityTooLarge).JSON(fiber.Map{
+				"error": fmt.Sprintf("request body is %d bytes (limit %d)", size, max),
+			})
+		}
+		return c.Next()
+	}
+}
+
+// StatusCode returns the HTTP status for an error returned by Check.
+func StatusCode(err error) int {
+	switch {
+	case errors.Is(err, ErrTooLarge):
+		return fiber.StatusRequestEntityTooLarge
+	case errors.Is(err, ErrExtension), errors.Is(err, ErrContent):
+		return fiber.StatusUnsupportedMediaType
+	}
+	return fiber.StatusBadRequest
+}
@@ -0,0 +1,97 @@
+package upload
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// nitroBundle builds a Nitro bundle holding one file.
+func nitroBundle(t *testing.T, name string, content []byte) []byte {
+	var compressed bytes.Buffer
+	zw := zlib.NewWriter(&compressed)
+	_, err := zw.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.BigEndian, uint16(1))
+	binary.Write(&buf, binary.BigEndian, uint16(len(name)))
+	buf.WriteString(name)
+	binary.Write(&buf, binary.BigEndian, uint32(compressed.Len()))
+	buf.Write(compressed.Bytes())
+	return buf.Bytes()
+}
+
+func testConfig() Config {
+	return Config{MaxFileSize: 1024, Extensions: []string{".nitro"}}
+}
+
+func TestSniffNitro(t *testing.T) {
+	assert.NoError(t, SniffNitro(nitroBundle(t, "chair.json", []byte(`{"name":"chair"}`))))
+
+	png := append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 32)...)
+	assert.Error(t, SniffNitro(png))
+	assert.Error(t, SniffNitro([]byte("PK\x03\x04 zip archive")))
+	assert.Error(t, SniffNitro(nil))
+
+	// Valid header but raw (uncompressed) data
+	var raw bytes.Buffer
+	binary.Write(&raw, binary.BigEndian, uint16(1))
+	binary.Write(&raw, binary.BigEndian, uint16(5))
+	raw.WriteString("a.png")
+	binary.Write(&raw, binary.BigEndian, uint32(4))
+	raw.WriteString("data")
+	assert.ErrorContains(t, SniffNitro(raw.Bytes()), "not zlib")
+}
+
+func TestCheck(t *testing.T) {
+	cfg := testConfig()
+	bundle := nitroBundle(t, "chair.json", []byte("{}"))
+
+	assert.NoError(t, cfg.Check("chair.nitro", bundle))
+	assert.NoError(t, cfg.Check("CHAIR.NITRO", bundle))
+
+	err := cfg.Check("chair.exe", bundle)
+	assert.ErrorIs(t, err, ErrExtension)
+	assert.Equal(t, 415, StatusCode(err))
+
+	err = cfg.Check("chair.nitro", []byte("MZ\x90\x00 disguised executable"))
+	assert.ErrorIs(t, err, ErrContent)
+	assert.Equal(t, 415, StatusCode(err))
+
+	err = cfg.Check("chair.nitro", make([]byte, 2048))
+	assert.ErrorIs(t, err, ErrTooLarge)
+	assert.Equal(t, 413, StatusCode(err))
+}
+
+func TestLimit(t *testing.T) {
+	app := fiber.New()
+	app.Post("/upload", Limit(8), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusCreated)
+	})
+
+	resp, err := app.Test(httptest.NewRequest("POST", "/upload", strings.NewReader("small")))
+	require.NoError(t, err)
+	assert.Equal(t, 201, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest("POST", "/upload", strings.NewReader("far too large a body")))
+	require.NoError(t, err)
+	assert.Equal(t, 413, resp.StatusCode)
+
+	// Zero disables the limit
+	app = fiber.New()
+	app.Post("/upload", Limit(0), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusCreated)
+	})
+	resp, err = app.Test(httptest.NewRequest("POST", "/upload", strings.NewReader("far too large a body")))
+	require.NoError(t, err)
+	assert.Equal(t, 201, resp.StatusCode)
+}
@@ -3,7 +3,6 @@ package utils
 import (
 	"fmt"
 	"strconv"
-	"strings"
 )
 
 // ToInt converts various types to int using explicit type switching.
@@ -58,21 +57,3 @@ func ToString(val any) string {
 		return fmt.Sprintf("%v", v)
 	}
 }
-
-// ToBool converts various types to bool.
-// It handles bool, numeric types (1=true), and strings ("1", "true").
-func ToBool(val any) bool {
-	switch v := val.(type) {
-	case bool:
-		return v
-	case int, int64, int32, int16, int8, uint, uint64, uint32, uint16, uint8:
-		return ToInt(v) == 1
-	case string:
-		return v == "1" || strings.ToLower(v) == "true"
-	case []byte:
-		s := string(v)
-		return s == "1" || strings.ToLower(s) == "true"
-	default:
-		return false
-	}
-}
@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseDecimal converts a numeric database value to float64.
+// Strings may use a comma as decimal separator ("1,5"), as found in Comet's varchar
+// stack_height column, and surrounding whitespace is ignored.
+func ParseDecimal(val any) (float64, error) {
+	switch v := val.(type) {
+	case float64:
+		return v, nil
+	case float32:
+		return float64(v), nil
+	case int, int64, int32, int16, int8, uint, uint64, uint32, uint16, uint8:
+		return float64(ToInt(v)), nil
+	case string:
+		return parseDecimalString(v)
+	case []byte:
+		return parseDecimalString(string(v))
+	case nil:
+		return 0, fmt.Errorf("empty decimal")
+	default:
+		return parseDecimalString(fmt.Sprintf("%v", v))
+	}
+}
+
+// ToFloat converts a numeric database value to float64, returning 0 when it cannot be parsed.
+func ToFloat(val any) float64 {
+	f, _ := ParseDecimal(val)
+	return f
+}
+
+// FormatDecimal renders a float in the canonical form stored in varchar columns:
+// dot separator and no trailing zeros (1 -> "1", 1.50 -> "1.5").
+func FormatDecimal(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// NormalizeDecimal returns the canonical form of a decimal value and whether it parsed.
+func NormalizeDecimal(val any) (string, bool) {
+	f, err := ParseDecimal(val)
+	if err != nil {
+		return "", false
+	}
+	return FormatDecimal(f), true
+}
+
+// parseDecimalString parses a decimal string accepting a comma separator.
+func parseDecimalString(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("empty decimal")
+	}
+
+	// A single comma is a decimal separator; anything else is not a number we write
+	if strings.Count(s, ",") == 1 && !strings.Contains(s, ".") {
+		s = strings.Replace(s, ",", ".", 1)
+	}
+
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid decimal %q: %w", s, err)
+	}
+	return f, nil
+}
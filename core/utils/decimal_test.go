@@ -0,0 +1,54 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		name    string
+		val     any
+		want    float64
+		wantErr bool
+	}{
+		{"float", 1.5, 1.5, false},
+		{"int", int64(2), 2, false},
+		{"dot string", "1.50", 1.5, false},
+		{"comma string", "1,5", 1.5, false},
+		{"padded bytes", []byte(" 0,25 "), 0.25, false},
+		{"integer string", "3", 3, false},
+		{"empty", "", 0, true},
+		{"nil", nil, 0, true},
+		{"thousands separators", "1,000.5", 0, true},
+		{"text", "abc", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDecimal(tt.val)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNormalizeDecimal(t *testing.T) {
+	got, ok := NormalizeDecimal("1,50")
+	assert.True(t, ok)
+	assert.Equal(t, "1.5", got)
+
+	got, ok = NormalizeDecimal([]byte("1.0"))
+	assert.True(t, ok)
+	assert.Equal(t, "1", got)
+
+	_, ok = NormalizeDecimal("n/a")
+	assert.False(t, ok)
+
+	assert.Equal(t, 0.0, ToFloat("n/a"))
+}
@@ -0,0 +1,12 @@
+// Package webhook delivers JSON event notifications to operator-configured URLs.
+//
+// Each event is POSTed once as application/json with a 10 second timeout; delivery
+// failures are returned to the caller, which logs them. When a secret is set, the
+// body is signed with HMAC-SHA256 and the hex digest is sent in the
+// X-Asset-Manager-Signature header as "sha256=<digest>", so receivers can reject
+// forged requests.
+//
+// # Usage
+//
+//	err := webhook.Post(ctx, url, secret, event)
+package webhook
@@ -0,0 +1,63 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"asset-manager/core/json"
+)
+
+// SignatureHeader carries the HMAC-SHA256 of the body when a secret is set.
+const SignatureHeader = "X-Asset-Manager-Signature"
+
+// Timeout bounds one delivery, including the connection.
+const Timeout = 10 * time.Second
+
+// client is the HTTP client deliveries are sent with.
+var client = &http.Client{Timeout: Timeout}
+
+// Post sends payload as JSON to url, signed with secret when it is not empty. A
+// response outside 2xx is returned as an error.
+func Post(ctx context.Context, url, secret string, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("invalid webhook URL: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "asset-manager")
+	if secret != "" {
+		req.Header.Set(SignatureHeader, Sign(secret, body))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook delivery failed: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook answered %s", resp.Status)
+	}
+	return nil
+}
+
+// Sign returns the signature header value of body: "sha256=" and the hex HMAC-SHA256
+// keyed by secret.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
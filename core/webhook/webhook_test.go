@@ -0,0 +1,49 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPost tests the body, headers and signature of a delivery.
+func TestPost(t *testing.T) {
+	var body []byte
+	var header http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		header = r.Header
+	}))
+	defer srv.Close()
+
+	err := Post(context.Background(), srv.URL, "secret", map[string]int{"new": 2})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"new":2}`, string(body))
+	assert.Equal(t, "application/json", header.Get("Content-Type"))
+	assert.Equal(t, Sign("secret", body), header.Get(SignatureHeader))
+
+	// Unsigned without a secret
+	assert.NoError(t, Post(context.Background(), srv.URL, "", map[string]int{}))
+	assert.Empty(t, header.Get(SignatureHeader))
+}
+
+// TestPost_ErrorStatus tests that a non-2xx answer is an error.
+func TestPost_ErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	err := Post(context.Background(), srv.URL, "", struct{}{})
+	assert.ErrorContains(t, err, "502")
+}
+
+// TestSign tests the signature format against a known digest.
+func TestSign(t *testing.T) {
+	assert.Equal(t, "sha256=f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8",
+		Sign("key", []byte("The quick brown fox jumps over the lazy dog")))
+}
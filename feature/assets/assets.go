@@ -0,0 +1,77 @@
+package assets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+
+	"asset-manager/core/storage"
+	furnitureAdp "asset-manager/feature/furniture/reconcile"
+
+	"github.com/minio/minio-go/v7"
+)
+
+var (
+	// ErrInvalidName is returned for names that are not plain file names.
+	ErrInvalidName = errors.New("invalid asset name")
+
+	// ErrNotFound is returned when the asset does not exist in storage.
+	ErrNotFound = errors.New("asset not found")
+)
+
+// namePattern matches classnames that are safe as storage file names.
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// Asset is an open asset object. The caller must close Reader.
+type Asset struct {
+	Reader io.ReadCloser
+
+	// Info holds the object's size, ETag and modification time. Size is -1 and the
+	// other fields are empty when the storage client cannot report them.
+	Info minio.ObjectInfo
+}
+
+// statReader is implemented by minio objects, whose Stat reads the object headers.
+type statReader interface {
+	Stat() (minio.ObjectInfo, error)
+}
+
+// FurnitureKey returns the storage key of a furniture classname's bundle.
+func FurnitureKey(classname string) (string, error) {
+	if !namePattern.MatchString(classname) || classname == "." || classname == ".." {
+		return "", fmt.Errorf("%w %q", ErrInvalidName, classname)
+	}
+	return path.Join(furnitureAdp.StoragePrefix, classname+furnitureAdp.StorageExtension), nil
+}
+
+// Open opens an object for streaming. It returns ErrNotFound when the object does
+// not exist.
+func Open(ctx context.Context, client storage.Client, bucket, key string) (*Asset, error) {
+	reader, err := client.GetObject(ctx, bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, openError(key, err)
+	}
+
+	info := minio.ObjectInfo{Key: key, Size: -1}
+	if s, ok := reader.(statReader); ok {
+		// minio only sends the request on first use, so a missing object surfaces here
+		stat, err := s.Stat()
+		if err != nil {
+			reader.Close()
+			return nil, openError(key, err)
+		}
+		info = stat
+	}
+	return &Asset{Reader: reader, Info: info}, nil
+}
+
+// openError maps a missing object to ErrNotFound.
+func openError(key string, err error) error {
+	if storage.IsNoSuchKey(err) {
+		return fmt.Errorf("%w: %s", ErrNotFound, key)
+	}
+	return fmt.Errorf("failed to open %s: %w", key, err)
+}
@@ -0,0 +1,95 @@
+package assets
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"asset-manager/core/storage/mocks"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// statObject is a readable object reporting info, like a minio object.
+type statObject struct {
+	io.ReadCloser
+	info minio.ObjectInfo
+	err  error
+}
+
+func (o *statObject) Stat() (minio.ObjectInfo, error) {
+	return o.info, o.err
+}
+
+// seekObject is a seekable object reporting info, like a minio object.
+type seekObject struct {
+	*strings.Reader
+	info minio.ObjectInfo
+}
+
+func (o *seekObject) Stat() (minio.ObjectInfo, error) {
+	return o.info, nil
+}
+
+func (o *seekObject) Close() error {
+	return nil
+}
+
+// modified is the modification time of test objects.
+var modified = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+// mockAssetStorage serves throne.nitro and reports every other key as missing.
+func mockAssetStorage() *mocks.Client {
+	client := new(mocks.Client)
+	client.On("GetObject", mock.Anything, "assets", "bundled/furniture/throne.nitro", mock.Anything).
+		Return(&seekObject{
+			Reader: strings.NewReader("nitro"),
+			info:   minio.ObjectInfo{Key: "bundled/furniture/throne.nitro", Size: 5, ETag: "abc123", LastModified: modified},
+		}, nil)
+	client.On("GetObject", mock.Anything, "assets", mock.Anything, mock.Anything).
+		Return(&statObject{
+			ReadCloser: io.NopCloser(strings.NewReader("")),
+			err:        minio.ErrorResponse{Code: "NoSuchKey", StatusCode: 404},
+		}, nil)
+	return client
+}
+
+func TestFurnitureKey(t *testing.T) {
+	key, err := FurnitureKey("throne")
+	require.NoError(t, err)
+	assert.Equal(t, "bundled/furniture/throne.nitro", key)
+
+	for _, name := range []string{"", "..", "../secret", "a/b", "a b"} {
+		_, err := FurnitureKey(name)
+		assert.ErrorIs(t, err, ErrInvalidName, name)
+	}
+}
+
+func TestOpen(t *testing.T) {
+	client := mockAssetStorage()
+
+	asset, err := Open(context.Background(), client, "assets", "bundled/furniture/throne.nitro")
+	require.NoError(t, err)
+	defer asset.Reader.Close()
+	assert.Equal(t, int64(5), asset.Info.Size)
+	assert.Equal(t, "abc123", asset.Info.ETag)
+
+	_, err = Open(context.Background(), client, "assets", "bundled/furniture/missing.nitro")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestOpen_WithoutStat(t *testing.T) {
+	client := new(mocks.Client)
+	client.On("GetObject", mock.Anything, "assets", "key", mock.Anything).
+		Return(io.NopCloser(strings.NewReader("data")), nil)
+
+	asset, err := Open(context.Background(), client, "assets", "key")
+	require.NoError(t, err)
+	assert.Equal(t, int64(-1), asset.Info.Size)
+	assert.Empty(t, asset.Info.ETag)
+}
@@ -0,0 +1,22 @@
+// Package assets serves bundled assets straight from storage, so small hotels can point
+// the Nitro client at the manager instead of exposing the bucket publicly.
+//
+// The routes are public (no API key) and only mounted when SERVER_SERVE_ASSETS=true.
+// Responses carry the object's ETag and Last-Modified, answer conditional requests
+// with 304 Not Modified or 412 Precondition Failed, and are cacheable for
+// SERVER_ASSETS_MAX_AGE. A single byte range is served as 206 Partial Content so
+// Nitro clients and CDNs can resume downloads; several ranges get the full object.
+//
+// Presigned URLs let a CMS send clients straight to storage instead. They require the
+// API key, are signed for STORAGE_PRESIGN_ENDPOINT and expire after STORAGE_PRESIGN_EXPIRY
+// unless the request asks for another validity up to STORAGE_PRESIGN_MAX_EXPIRY.
+// Keys must lie under STORAGE_PRESIGN_PREFIXES and never name internal objects.
+// Presigned uploads are off unless STORAGE_PRESIGN_ALLOW_PUT is set, only accept the
+// upload extensions and only create new objects outside STORAGE_PRESIGN_STAGING_PREFIX;
+// the manager never sees their content.
+//
+// # HTTP Endpoints
+//
+//   - GET /assets/furniture/:classname.nitro : Stream bundled/furniture/<classname>.nitro (public).
+//   - GET /assets/*/url : Presign a GET or PUT of the object key in the path.
+package assets
@@ -0,0 +1,272 @@
+package assets
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"asset-manager/core/logger"
+	"asset-manager/core/upload"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// Handler handles HTTP requests for assets.
+type Handler struct {
+	service *Service
+}
+
+// NewHandler creates a new HTTP handler.
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// RegisterRoutes registers the public asset proxy routes.
+func (h *Handler) RegisterRoutes(app fiber.Router) {
+	group := app.Group("/assets")
+	group.Get("/furniture/:classname.nitro", h.HandleGetFurniture)
+}
+
+// RegisterPresignRoutes registers the presigned URL route, which requires the API key.
+func (h *Handler) RegisterPresignRoutes(app fiber.Router) {
+	app.Get("/assets/*/url", h.HandlePresign)
+}
+
+// HandleGetFurniture streams a furniture bundle from storage.
+// @Summary Get Furniture Bundle
+// @Description Stream bundled/furniture/<classname>.nitro from storage with ETag, Last-Modified and Cache-Control headers. Single byte ranges are answered with 206 (honouring If-Range), If-None-Match and If-Modified-Since with 304, and If-Match and If-Unmodified-Since with 412. Public, only mounted when SERVER_SERVE_ASSETS is enabled.
+// @Tags assets
+// @Produce octet-stream
+// @Param classname path string true "Furniture classname (e.g. 'throne')"
+// @Success 200 {file} file "Nitro bundle"
+// @Success 206 {file} file "Partial bundle"
+// @Success 304 "Not Modified"
+// @Failure 400 {object} map[string]string "Invalid classname"
+// @Failure 404 {object} map[string]string "Asset not found"
+// @Failure 412 "Precondition Failed"
+// @Failure 416 "Range Not Satisfiable"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /assets/furniture/{classname}.nitro [get]
+func (h *Handler) HandleGetFurniture(c *fiber.Ctx) error {
+	l := logger.WithRayID(h.service.logger, c)
+
+	asset, err := h.service.OpenFurniture(c.Context(), c.Params("classname"))
+	switch {
+	case errors.Is(err, ErrInvalidName):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, ErrNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case err != nil:
+		l.Error("Failed to open asset", zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return h.send(c, asset)
+}
+
+// HandlePresign returns a presigned URL for direct access to an object in storage.
+// @Summary Presign Asset URL
+// @Description Return a URL that reads (GET) or writes (PUT) one object directly in storage until it expires, so large files need not pass through the API. The path is the object key, e.g. bundled/furniture/throne.nitro, and must lie under STORAGE_PRESIGN_PREFIXES. PUT requires STORAGE_PRESIGN_ALLOW_PUT and an accepted upload extension, and only creates new objects unless the key is under STORAGE_PRESIGN_STAGING_PREFIX; presigned uploads skip the upload size, content and malware checks.
+// @Tags assets
+// @Produce json
+// @Param path path string true "Object key (e.g. 'bundled/furniture/throne.nitro')"
+// @Param method query string false "GET (default) or PUT"
+// @Param expires query string false "Validity as a duration (e.g. '10m'); defaults to STORAGE_PRESIGN_EXPIRY, capped by STORAGE_PRESIGN_MAX_EXPIRY"
+// @Success 200 {object} PresignedURL "Presigned URL"
+// @Failure 400 {object} map[string]string "Invalid key, method or expiry"
+// @Failure 403 {object} map[string]string "Presigned uploads disabled or key outside the presignable prefixes"
+// @Failure 409 {object} map[string]string "Upload would replace an existing object"
+// @Failure 415 {object} map[string]string "Extension not accepted for uploads"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /assets/{path}/url [get]
+func (h *Handler) HandlePresign(c *fiber.Ctx) error {
+	l := logger.WithRayID(h.service.logger, c)
+
+	key, err := url.PathUnescape(c.Params("*"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid path: " + err.Error(),
+		})
+	}
+	req := PresignRequest{Key: key, Method: c.Query("method")}
+	if expires := c.Query("expires"); expires != "" {
+		req.Expiry, err = time.ParseDuration(expires)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "invalid expires: " + err.Error(),
+			})
+		}
+	}
+
+	signed, err := h.service.Presign(c.Context(), req)
+	switch {
+	case errors.Is(err, ErrInvalidName), errors.Is(err, ErrInvalidRequest):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, ErrPutDisabled), errors.Is(err, ErrKeyNotAllowed):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, ErrObjectExists):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, upload.ErrExtension):
+		return c.Status(upload.StatusCode(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case err != nil:
+		l.Error("Failed to presign asset", zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	l.Info("Asset URL presigned",
+		zap.String("method", signed.Method),
+		zap.String("bucket", signed.Bucket),
+		zap.String("key", signed.Key),
+		zap.Time("expires_at", signed.ExpiresAt))
+	return c.JSON(signed)
+}
+
+// send writes the caching headers of asset and streams it. Conditional requests are
+// answered with 304 or 412, and a single byte range with 206 when the object's size
+// is known and its reader can seek.
+func (h *Handler) send(c *fiber.Ctx, asset *Asset) error {
+	info := asset.Info
+
+	c.Set(fiber.HeaderCacheControl, fmt.Sprintf("public, max-age=%d", int(h.service.opts.MaxAge/time.Second)))
+	etag := ""
+	if info.ETag != "" {
+		etag = `"` + strings.Trim(info.ETag, `"`) + `"`
+		c.Set(fiber.HeaderETag, etag)
+	}
+	if !info.LastModified.IsZero() {
+		c.Set(fiber.HeaderLastModified, info.LastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if preconditionFailed(c, etag, info.LastModified) {
+		asset.Reader.Close()
+		return c.SendStatus(fiber.StatusPreconditionFailed)
+	}
+	if notModified(c, etag, info.LastModified) {
+		asset.Reader.Close()
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	c.Set(fiber.HeaderContentType, fiber.MIMEOctetStream)
+
+	seeker, seekable := asset.Reader.(io.Seeker)
+	if info.Size < 0 || !seekable {
+		// The stream is closed once it has been sent
+		return c.SendStream(asset.Reader, int(info.Size))
+	}
+	c.Set(fiber.HeaderAcceptRanges, "bytes")
+
+	header := c.Get(fiber.HeaderRange)
+	if header == "" || !rangeApplies(c, etag, info.LastModified) {
+		return c.SendStream(asset.Reader, int(info.Size))
+	}
+	r, ok, err := parseRange(header, info.Size)
+	if errors.Is(err, errUnsatisfiable) {
+		asset.Reader.Close()
+		c.Set(fiber.HeaderContentRange, "bytes */"+strconv.FormatInt(info.Size, 10))
+		return c.SendStatus(fiber.StatusRequestedRangeNotSatisfiable)
+	}
+	if !ok {
+		return c.SendStream(asset.Reader, int(info.Size))
+	}
+
+	// Seeking a minio object makes its next read a ranged request
+	if _, err := seeker.Seek(r.start, io.SeekStart); err != nil {
+		asset.Reader.Close()
+		return fmt.Errorf("failed to seek asset: %w", err)
+	}
+	c.Set(fiber.HeaderContentRange, r.contentRange(info.Size))
+	c.Status(fiber.StatusPartialContent)
+	return c.SendStream(limitedReadCloser{Reader: io.LimitReader(asset.Reader, r.length), Closer: asset.Reader}, int(r.length))
+}
+
+// limitedReadCloser reads a range of a stream and closes the whole stream.
+type limitedReadCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// etagMatches reports whether an If-Match, If-None-Match or If-Range list names etag.
+// Weak comparison ignores the W/ prefix of listed tags; strong comparison rejects them.
+func etagMatches(list, etag string, weak bool) bool {
+	if etag == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(list, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" {
+			return true
+		}
+		if strings.HasPrefix(candidate, "W/") {
+			if !weak {
+				continue
+			}
+			candidate = strings.TrimPrefix(candidate, "W/")
+		}
+		if candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// preconditionFailed reports whether If-Match or If-Unmodified-Since rule the request
+// out. If-Match takes precedence, as in RFC 9110.
+func preconditionFailed(c *fiber.Ctx, etag string, modified time.Time) bool {
+	if match := c.Get(fiber.HeaderIfMatch); match != "" {
+		return !etagMatches(match, etag, false)
+	}
+	if since := c.Get(fiber.HeaderIfUnmodifiedSince); since != "" && !modified.IsZero() {
+		t, err := http.ParseTime(since)
+		return err == nil && modified.Truncate(time.Second).After(t)
+	}
+	return false
+}
+
+// notModified reports whether the request's validators match the object. If-None-Match
+// takes precedence over If-Modified-Since, as in RFC 9110.
+func notModified(c *fiber.Ctx, etag string, modified time.Time) bool {
+	if match := c.Get(fiber.HeaderIfNoneMatch); match != "" {
+		return etagMatches(match, etag, true)
+	}
+	if since := c.Get(fiber.HeaderIfModifiedSince); since != "" && !modified.IsZero() {
+		t, err := http.ParseTime(since)
+		return err == nil && !modified.Truncate(time.Second).After(t)
+	}
+	return false
+}
+
+// rangeApplies reports whether a Range header may be honored: without If-Range, or
+// when If-Range names the current entity tag or modification time.
+func rangeApplies(c *fiber.Ctx, etag string, modified time.Time) bool {
+	ifRange := strings.TrimSpace(c.Get(fiber.HeaderIfRange))
+	if ifRange == "" {
+		return true
+	}
+	if strings.HasPrefix(ifRange, `"`) || strings.HasPrefix(ifRange, "W/") {
+		return etagMatches(ifRange, etag, false)
+	}
+	t, err := http.ParseTime(ifRange)
+	return err == nil && !modified.IsZero() && modified.Truncate(time.Second).Equal(t)
+}
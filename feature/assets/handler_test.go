@@ -0,0 +1,164 @@
+package assets
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"asset-manager/core/json"
+	"asset-manager/core/storage"
+	"asset-manager/core/upload"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// newTestApp mounts the asset routes over mockAssetStorage.
+func newTestApp() *fiber.App {
+	svc := NewService(mockAssetStorage(), storage.SingleBucket("assets"), zap.NewNop(), Options{MaxAge: time.Hour})
+	app := fiber.New()
+	NewHandler(svc).RegisterRoutes(app)
+	return app
+}
+
+func TestHandler_HandleGetFurniture(t *testing.T) {
+	resp, err := newTestApp().Test(httptest.NewRequest("GET", "/assets/furniture/throne.nitro", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "nitro", string(body))
+	assert.Equal(t, "public, max-age=3600", resp.Header.Get("Cache-Control"))
+	assert.Equal(t, `"abc123"`, resp.Header.Get("ETag"))
+	assert.Equal(t, modified.Format(http.TimeFormat), resp.Header.Get("Last-Modified"))
+	assert.Equal(t, "application/octet-stream", resp.Header.Get("Content-Type"))
+	assert.Equal(t, "bytes", resp.Header.Get("Accept-Ranges"))
+}
+
+func TestHandler_HandleGetFurniture_Range(t *testing.T) {
+	tests := []struct {
+		name         string
+		headers      map[string]string
+		want         int
+		body         string
+		contentRange string
+	}{
+		{"first bytes", map[string]string{"Range": "bytes=0-1"}, 206, "ni", "bytes 0-1/5"},
+		{"open ended", map[string]string{"Range": "bytes=2-"}, 206, "tro", "bytes 2-4/5"},
+		{"suffix", map[string]string{"Range": "bytes=-2"}, 206, "ro", "bytes 3-4/5"},
+		{"end past size", map[string]string{"Range": "bytes=3-100"}, 206, "ro", "bytes 3-4/5"},
+		{"unsatisfiable", map[string]string{"Range": "bytes=9-"}, 416, "", "bytes */5"},
+		{"several ranges", map[string]string{"Range": "bytes=0-1,3-4"}, 200, "nitro", ""},
+		{"not bytes", map[string]string{"Range": "items=0-1"}, 200, "nitro", ""},
+		{"if-range match", map[string]string{"Range": "bytes=0-1", "If-Range": `"abc123"`}, 206, "ni", "bytes 0-1/5"},
+		{"if-range stale", map[string]string{"Range": "bytes=0-1", "If-Range": `"old"`}, 200, "nitro", ""},
+		{"if-range date", map[string]string{"Range": "bytes=0-1", "If-Range": modified.Format(http.TimeFormat)}, 206, "ni", "bytes 0-1/5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/assets/furniture/throne.nitro", nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			resp, err := newTestApp().Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, resp.StatusCode)
+			assert.Equal(t, tt.contentRange, resp.Header.Get("Content-Range"))
+			if tt.body != "" {
+				body, err := io.ReadAll(resp.Body)
+				require.NoError(t, err)
+				assert.Equal(t, tt.body, string(body))
+			}
+		})
+	}
+}
+
+func TestHandler_HandleGetFurniture_Conditional(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		value  string
+		want   int
+	}{
+		{"etag match", "If-None-Match", `"abc123"`, 304},
+		{"weak etag match", "If-None-Match", `W/"abc123", "other"`, 304},
+		{"etag mismatch", "If-None-Match", `"old"`, 200},
+		{"not modified since", "If-Modified-Since", modified.Format(http.TimeFormat), 304},
+		{"modified since", "If-Modified-Since", modified.Add(-time.Hour).Format(http.TimeFormat), 200},
+		{"if-match", "If-Match", `"abc123"`, 200},
+		{"if-match mismatch", "If-Match", `"old"`, 412},
+		{"if-match weak", "If-Match", `W/"abc123"`, 412},
+		{"unmodified since", "If-Unmodified-Since", modified.Format(http.TimeFormat), 200},
+		{"modified after", "If-Unmodified-Since", modified.Add(-time.Hour).Format(http.TimeFormat), 412},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/assets/furniture/throne.nitro", nil)
+			req.Header.Set(tt.header, tt.value)
+			resp, err := newTestApp().Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, resp.StatusCode)
+		})
+	}
+}
+
+func TestHandler_HandleGetFurniture_Errors(t *testing.T) {
+	app := newTestApp()
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/assets/furniture/missing.nitro", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 404, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/assets/furniture/bad%20name.nitro", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 400, resp.StatusCode)
+}
+
+func TestHandler_HandlePresign(t *testing.T) {
+	svc := NewService(mockPresignStorage(), storage.SingleBucket("assets"), zap.NewNop(), Options{
+		Presign: presignConfig,
+		Upload:  upload.Config{Extensions: []string{".nitro"}},
+	})
+	app := fiber.New()
+	NewHandler(svc).RegisterPresignRoutes(app)
+
+	tests := []struct {
+		name string
+		path string
+		want int
+	}{
+		{"get", "/assets/bundled/furniture/throne.nitro/url", 200},
+		{"escaped key", "/assets/gamedata%2FFurnitureData.json/url?expires=5m", 200},
+		{"put", "/assets/bundled/furniture/throne.nitro/url?method=PUT", 200},
+		{"put extension", "/assets/bundled/furniture/throne.exe/url?method=PUT", 415},
+		{"bad expires", "/assets/bundled/furniture/throne.nitro/url?expires=soon", 400},
+		{"expires above max", "/assets/bundled/furniture/throne.nitro/url?expires=48h", 400},
+		{"climbing key", "/assets/bundled/%2E%2E/x/url", 400},
+		{"internal key", "/assets/.locks/reconcile.lock/url", 403},
+		{"unlisted prefix", "/assets/backups/plan.json/url", 403},
+		{"put existing", "/assets/bundled/furniture/existing.nitro/url?method=PUT", 409},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := app.Test(httptest.NewRequest("GET", tt.path, nil))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, resp.StatusCode)
+			if tt.want != 200 {
+				return
+			}
+
+			var signed PresignedURL
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&signed))
+			assert.Equal(t, "https://cdn.example.com/assets/object?X-Amz-Signature=sig", signed.URL)
+			assert.NotEmpty(t, signed.Key)
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package assets
+
+import (
+	"asset-manager/core/storage"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// Feature implements the loader.Feature interface.
+type Feature struct {
+	service *Service
+	handler *Handler
+	enabled bool
+}
+
+// NewFeature creates a new Assets feature serving the public proxy routes. It is only
+// enabled when the server is configured to serve assets.
+func NewFeature(client storage.Client, buckets storage.Buckets, logger *zap.Logger, enabled bool, opts Options) *Feature {
+	svc := NewService(client, buckets, logger, opts)
+	h := NewHandler(svc)
+	return &Feature{service: svc, handler: h, enabled: enabled}
+}
+
+// Name returns the name of the feature.
+func (f *Feature) Name() string {
+	return "assets"
+}
+
+// IsEnabled checks if the feature is enabled.
+func (f *Feature) IsEnabled() bool {
+	return f.enabled
+}
+
+// Load registers the feature's routes.
+func (f *Feature) Load(app fiber.Router) error {
+	f.handler.RegisterRoutes(app)
+	return nil
+}
+
+// PresignFeature implements the loader.Feature interface for the presigned URL route.
+type PresignFeature struct {
+	handler *Handler
+}
+
+// NewPresignFeature creates the feature serving presigned URLs. Its route must be
+// loaded behind the API key.
+func NewPresignFeature(client storage.Client, buckets storage.Buckets, logger *zap.Logger, opts Options) *PresignFeature {
+	return &PresignFeature{handler: NewHandler(NewService(client, buckets, logger, opts))}
+}
+
+// Name returns the name of the feature.
+func (f *PresignFeature) Name() string {
+	return "assets-presign"
+}
+
+// IsEnabled checks if the feature is enabled.
+func (f *PresignFeature) IsEnabled() bool {
+	return true
+}
+
+// Load registers the feature's routes.
+func (f *PresignFeature) Load(app fiber.Router) error {
+	f.handler.RegisterPresignRoutes(app)
+	return nil
+}
@@ -0,0 +1,157 @@
+package assets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"asset-manager/core/storage"
+	"asset-manager/core/upload"
+)
+
+var (
+	// ErrPutDisabled is returned for presigned uploads when STORAGE_PRESIGN_ALLOW_PUT is off.
+	ErrPutDisabled = errors.New("presigned uploads are disabled (STORAGE_PRESIGN_ALLOW_PUT)")
+
+	// ErrInvalidRequest is returned for unsupported methods and expiries.
+	ErrInvalidRequest = errors.New("invalid presign request")
+
+	// ErrKeyNotAllowed is returned for keys outside the presignable prefixes
+	// (STORAGE_PRESIGN_PREFIXES) or naming an internal object.
+	ErrKeyNotAllowed = errors.New("key may not be presigned")
+
+	// ErrObjectExists is returned for presigned uploads that would replace an
+	// existing object outside the staging prefix.
+	ErrObjectExists = errors.New("object already exists")
+)
+
+// Presign methods accepted by PresignRequest.Method.
+const (
+	MethodGet = "GET"
+	MethodPut = "PUT"
+)
+
+// PresignRequest asks for a presigned URL of one object.
+type PresignRequest struct {
+	// Key is the object key, e.g. bundled/furniture/throne.nitro.
+	Key string
+	// Method is MethodGet or MethodPut.
+	Method string
+	// Expiry is how long the URL stays valid. Zero uses the configured default.
+	Expiry time.Duration
+}
+
+// PresignedURL is a URL granting temporary access to one object.
+type PresignedURL struct {
+	URL       string    `json:"url"`
+	Method    string    `json:"method"`
+	Bucket    string    `json:"bucket"`
+	Key       string    `json:"key"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// ObjectKey cleans an object key taken from a request. Keys must be relative and may
+// not climb out of the bucket or name a folder.
+func ObjectKey(raw string) (string, error) {
+	key := strings.TrimPrefix(raw, "/")
+	if key == "" || strings.HasSuffix(key, "/") {
+		return "", fmt.Errorf("%w %q", ErrInvalidName, raw)
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return "", fmt.Errorf("%w %q", ErrInvalidName, raw)
+		}
+	}
+	return key, nil
+}
+
+// presignable reports whether key may be presigned: it lies under one of prefixes (or
+// the staging prefix for uploads) and no path segment starts with a dot.
+func presignable(key string, prefixes []string) bool {
+	for _, segment := range strings.Split(key, "/") {
+		if strings.HasPrefix(segment, ".") {
+			return false
+		}
+	}
+	for _, prefix := range prefixes {
+		if prefix != "" && strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Presign returns a presigned URL for req. The bucket is the one holding the key's
+// top-level folder. Keys must lie under cfg.Prefixes. Uploads must be allowed by cfg
+// and use an extension accepted by uploads; they may replace existing objects only
+// under cfg.StagingPrefix and otherwise only create new ones.
+func Presign(ctx context.Context, client storage.Client, buckets storage.Buckets, cfg storage.Presign, uploads upload.Config, req PresignRequest) (*PresignedURL, error) {
+	key, err := ObjectKey(req.Key)
+	if err != nil {
+		return nil, err
+	}
+	method := strings.ToUpper(req.Method)
+	prefixes := cfg.Prefixes
+	if method == MethodPut && cfg.StagingPrefix != "" {
+		prefixes = append([]string{cfg.StagingPrefix}, prefixes...)
+	}
+	if !presignable(key, prefixes) {
+		return nil, fmt.Errorf("%w: %s", ErrKeyNotAllowed, key)
+	}
+
+	expiry := req.Expiry
+	if expiry == 0 {
+		expiry = cfg.Expiry
+	}
+	if expiry < time.Second || (cfg.MaxExpiry > 0 && expiry > cfg.MaxExpiry) {
+		return nil, fmt.Errorf("%w: expiry %s must be between 1s and %s", ErrInvalidRequest, expiry, cfg.MaxExpiry)
+	}
+
+	folder, _, _ := strings.Cut(key, "/")
+	bucket := buckets.ForFolder(folder)
+
+	var signed string
+	switch method {
+	case "", MethodGet:
+		method = MethodGet
+		u, err := client.PresignedGetObject(ctx, bucket, key, expiry, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to presign %s: %w", key, err)
+		}
+		signed = u.String()
+	case MethodPut:
+		if !cfg.AllowPut {
+			return nil, ErrPutDisabled
+		}
+		if err := uploads.CheckName(path.Base(key)); err != nil {
+			return nil, err
+		}
+		if cfg.StagingPrefix == "" || !strings.HasPrefix(key, cfg.StagingPrefix) {
+			exists, err := storage.ObjectExists(ctx, client, bucket, key)
+			if err != nil {
+				return nil, err
+			}
+			if exists {
+				return nil, fmt.Errorf("%w: %s", ErrObjectExists, key)
+			}
+		}
+		u, err := client.PresignedPutObject(ctx, bucket, key, expiry)
+		if err != nil {
+			return nil, fmt.Errorf("failed to presign %s: %w", key, err)
+		}
+		signed = u.String()
+	default:
+		return nil, fmt.Errorf("%w: method %q (want GET or PUT)", ErrInvalidRequest, req.Method)
+	}
+
+	return &PresignedURL{
+		URL:       signed,
+		Method:    method,
+		Bucket:    bucket,
+		Key:       key,
+		ExpiresAt: time.Now().Add(expiry).UTC(),
+	}, nil
+}
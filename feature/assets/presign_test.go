@@ -0,0 +1,129 @@
+package assets
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"asset-manager/core/storage"
+	"asset-manager/core/storage/mocks"
+	"asset-manager/core/upload"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// presignConfig allows uploads valid for up to an hour under bundled/ and gamedata/.
+var presignConfig = storage.Presign{Expiry: 15 * time.Minute, MaxExpiry: time.Hour, AllowPut: true, Prefixes: []string{"bundled/", "gamedata/"}}
+
+// listing returns a closed object listing of keys.
+func listing(keys ...string) <-chan minio.ObjectInfo {
+	ch := make(chan minio.ObjectInfo, len(keys))
+	for _, key := range keys {
+		ch <- minio.ObjectInfo{Key: key}
+	}
+	close(ch)
+	return ch
+}
+
+// mockPresignStorage presigns every object at a fixed URL. Only
+// bundled/furniture/existing.nitro exists.
+func mockPresignStorage() *mocks.Client {
+	signed := &url.URL{Scheme: "https", Host: "cdn.example.com", Path: "/assets/object", RawQuery: "X-Amz-Signature=sig"}
+	client := new(mocks.Client)
+	client.On("PresignedGetObject", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(signed, nil)
+	client.On("PresignedPutObject", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(signed, nil)
+	client.On("ListObjects", mock.Anything, mock.Anything, minio.ListObjectsOptions{Prefix: "bundled/furniture/existing.nitro"}).
+		Return(listing("bundled/furniture/existing.nitro"))
+	client.On("ListObjects", mock.Anything, mock.Anything, mock.Anything).Return(listing())
+	return client
+}
+
+func TestObjectKey(t *testing.T) {
+	key, err := ObjectKey("/bundled/furniture/throne.nitro")
+	require.NoError(t, err)
+	assert.Equal(t, "bundled/furniture/throne.nitro", key)
+
+	for _, raw := range []string{"", "/", "bundled/", "bundled//x", "../secret", "bundled/../../x", "./x"} {
+		_, err := ObjectKey(raw)
+		assert.ErrorIs(t, err, ErrInvalidName, raw)
+	}
+}
+
+func TestPresign(t *testing.T) {
+	buckets := storage.Buckets{Assets: "assets", Gamedata: "gamedata-bucket"}
+	uploads := upload.Config{Extensions: []string{".nitro"}}
+	ctx := context.Background()
+
+	client := new(mocks.Client)
+	client.On("PresignedGetObject", ctx, "gamedata-bucket", "gamedata/FurnitureData.json", 15*time.Minute, url.Values(nil)).
+		Return(&url.URL{Scheme: "https", Host: "cdn.example.com", Path: "/gamedata-bucket/gamedata/FurnitureData.json"}, nil)
+	client.On("PresignedPutObject", ctx, "assets", "bundled/furniture/throne.nitro", 10*time.Minute).
+		Return(&url.URL{Scheme: "https", Host: "cdn.example.com", Path: "/assets/bundled/furniture/throne.nitro"}, nil)
+	client.On("ListObjects", ctx, "assets", minio.ListObjectsOptions{Prefix: "bundled/furniture/throne.nitro"}).Return(listing())
+
+	get, err := Presign(ctx, client, buckets, presignConfig, uploads, PresignRequest{Key: "gamedata/FurnitureData.json"})
+	require.NoError(t, err)
+	assert.Equal(t, MethodGet, get.Method)
+	assert.Equal(t, "gamedata-bucket", get.Bucket)
+	assert.Equal(t, "https://cdn.example.com/gamedata-bucket/gamedata/FurnitureData.json", get.URL)
+	assert.WithinDuration(t, time.Now().Add(15*time.Minute), get.ExpiresAt, time.Minute)
+
+	put, err := Presign(ctx, client, buckets, presignConfig, uploads, PresignRequest{Key: "bundled/furniture/throne.nitro", Method: "put", Expiry: 10 * time.Minute})
+	require.NoError(t, err)
+	assert.Equal(t, MethodPut, put.Method)
+	assert.Equal(t, "assets", put.Bucket)
+
+	_, err = Presign(ctx, client, buckets, presignConfig, uploads, PresignRequest{Key: "bundled/furniture/throne.exe", Method: MethodPut})
+	assert.ErrorIs(t, err, upload.ErrExtension)
+
+	_, err = Presign(ctx, client, buckets, storage.Presign{Expiry: time.Minute, Prefixes: []string{"bundled/"}}, uploads, PresignRequest{Key: "bundled/b.nitro", Method: MethodPut})
+	assert.ErrorIs(t, err, ErrPutDisabled)
+
+	_, err = Presign(ctx, client, buckets, presignConfig, uploads, PresignRequest{Key: "bundled/b.nitro", Expiry: 2 * time.Hour})
+	assert.ErrorIs(t, err, ErrInvalidRequest)
+
+	_, err = Presign(ctx, client, buckets, presignConfig, uploads, PresignRequest{Key: "bundled/b.nitro", Method: "DELETE"})
+	assert.ErrorIs(t, err, ErrInvalidRequest)
+}
+
+// TestPresign_InternalKeys tests that keys outside the presignable prefixes or naming
+// internal objects are refused for either method.
+func TestPresign_InternalKeys(t *testing.T) {
+	client := mockPresignStorage()
+	uploads := upload.Config{Extensions: []string{".nitro", ".json", ".lock", ".ndjson"}}
+	for _, key := range []string{".locks/reconcile.lock", "backups/plan.json", "_changes/2026-10-16.ndjson", "quarantine/x.nitro", "bundled/.hidden/x.nitro", "a/b.nitro"} {
+		for _, method := range []string{MethodGet, MethodPut} {
+			_, err := Presign(context.Background(), client, storage.SingleBucket("assets"), presignConfig, uploads, PresignRequest{Key: key, Method: method})
+			assert.ErrorIs(t, err, ErrKeyNotAllowed, "%s %s", method, key)
+		}
+	}
+	client.AssertNotCalled(t, "PresignedGetObject", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	client.AssertNotCalled(t, "PresignedPutObject", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestPresign_PutExisting tests that uploads only replace existing objects under the
+// staging prefix.
+func TestPresign_PutExisting(t *testing.T) {
+	client := mockPresignStorage()
+	uploads := upload.Config{Extensions: []string{".nitro"}}
+	buckets := storage.SingleBucket("assets")
+
+	_, err := Presign(context.Background(), client, buckets, presignConfig, uploads, PresignRequest{Key: "bundled/furniture/existing.nitro", Method: MethodPut})
+	assert.ErrorIs(t, err, ErrObjectExists)
+
+	// Staging keys are not checked for existence and need not be under Prefixes
+	staging := presignConfig
+	staging.StagingPrefix = "staging/"
+	put, err := Presign(context.Background(), client, buckets, staging, uploads, PresignRequest{Key: "staging/existing.nitro", Method: MethodPut})
+	require.NoError(t, err)
+	assert.Equal(t, "staging/existing.nitro", put.Key)
+	client.AssertNotCalled(t, "ListObjects", mock.Anything, mock.Anything, minio.ListObjectsOptions{Prefix: "staging/existing.nitro"})
+
+	// Staging is for uploads; reading it back still needs a listed prefix
+	_, err = Presign(context.Background(), client, buckets, staging, uploads, PresignRequest{Key: "staging/existing.nitro"})
+	assert.ErrorIs(t, err, ErrKeyNotAllowed)
+}
@@ -0,0 +1,64 @@
+package assets
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// errUnsatisfiable is returned for ranges outside the object.
+var errUnsatisfiable = errors.New("range not satisfiable")
+
+// byteRange is one satisfiable range of an object.
+type byteRange struct {
+	start, length int64
+}
+
+// contentRange returns the Content-Range value of r within an object of size bytes.
+func (r byteRange) contentRange(size int64) string {
+	return "bytes " + strconv.FormatInt(r.start, 10) + "-" + strconv.FormatInt(r.start+r.length-1, 10) + "/" + strconv.FormatInt(size, 10)
+}
+
+// parseRange parses a Range header for an object of size bytes. It returns false for
+// headers served as a full response: empty, malformed, not in bytes, or asking for
+// several ranges. errUnsatisfiable is returned when no requested byte exists.
+func parseRange(header string, size int64) (byteRange, bool, error) {
+	spec, ok := strings.CutPrefix(strings.TrimSpace(header), "bytes=")
+	if !ok || strings.Contains(spec, ",") {
+		return byteRange{}, false, nil
+	}
+	first, last, ok := strings.Cut(strings.TrimSpace(spec), "-")
+	if !ok {
+		return byteRange{}, false, nil
+	}
+
+	// Suffix range: the last N bytes
+	if first == "" {
+		n, err := strconv.ParseInt(last, 10, 64)
+		if err != nil || n < 0 {
+			return byteRange{}, false, nil
+		}
+		if n == 0 || size == 0 {
+			return byteRange{}, false, errUnsatisfiable
+		}
+		n = min(n, size)
+		return byteRange{start: size - n, length: n}, true, nil
+	}
+
+	start, err := strconv.ParseInt(first, 10, 64)
+	if err != nil || start < 0 {
+		return byteRange{}, false, nil
+	}
+	end := size - 1
+	if last != "" {
+		end, err = strconv.ParseInt(last, 10, 64)
+		if err != nil || end < start {
+			return byteRange{}, false, nil
+		}
+		end = min(end, size-1)
+	}
+	if start >= size {
+		return byteRange{}, false, errUnsatisfiable
+	}
+	return byteRange{start: start, length: end - start + 1}, true, nil
+}
@@ -0,0 +1,53 @@
+package assets
+
+import (
+	"context"
+	"time"
+
+	"asset-manager/core/storage"
+	"asset-manager/core/upload"
+
+	"go.uber.org/zap"
+)
+
+// Options configures the asset routes.
+type Options struct {
+	// MaxAge is the Cache-Control max-age of proxied assets.
+	MaxAge time.Duration
+	// Presign configures presigned URLs.
+	Presign storage.Presign
+	// Upload lists the extensions accepted for presigned uploads.
+	Upload upload.Config
+}
+
+// Service opens assets for the proxy routes and presigns direct storage access.
+type Service struct {
+	client  storage.Client
+	buckets storage.Buckets
+	logger  *zap.Logger
+	opts    Options
+}
+
+// NewService creates a new asset service.
+func NewService(client storage.Client, buckets storage.Buckets, logger *zap.Logger, opts Options) *Service {
+	return &Service{
+		client:  client,
+		buckets: buckets,
+		logger:  logger,
+		opts:    opts,
+	}
+}
+
+// OpenFurniture opens the bundle of a furniture classname.
+func (s *Service) OpenFurniture(ctx context.Context, classname string) (*Asset, error) {
+	key, err := FurnitureKey(classname)
+	if err != nil {
+		return nil, err
+	}
+	return Open(ctx, s.client, s.buckets.Assets, key)
+}
+
+// Presign returns a presigned URL for req.
+func (s *Service) Presign(ctx context.Context, req PresignRequest) (*PresignedURL, error) {
+	return Presign(ctx, s.client, s.buckets, s.opts.Presign, s.opts.Upload, req)
+}
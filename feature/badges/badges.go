@@ -0,0 +1,217 @@
+package badges
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"asset-manager/core/json"
+	"asset-manager/core/server"
+	"asset-manager/core/storage"
+
+	"github.com/minio/minio-go/v7"
+	"gorm.io/gorm"
+)
+
+// TextsObject is the gamedata file holding badge names and descriptions.
+const TextsObject = "gamedata/ExternalTexts.json"
+
+// Sources a badge code can be defined in.
+const (
+	SourceTexts    = "texts"
+	SourceDatabase = "db"
+)
+
+// textPrefixes are the ExternalTexts key prefixes that define a badge code.
+var textPrefixes = []string{"badge_name_", "badge_desc_"}
+
+// imageExtensions are the badge image formats served to the client.
+var imageExtensions = map[string]bool{".gif": true, ".png": true}
+
+// BadgeTable is the emulator table listing the badges owned by users.
+type BadgeTable struct {
+	Table  string
+	Column string
+}
+
+// badgeTables maps each emulator to its badge table.
+var badgeTables = map[string]BadgeTable{
+	server.EmulatorArcturus:   {Table: "users_badges", Column: "badge_code"},
+	server.EmulatorArcturusMS: {Table: "users_badges", Column: "badge_code"},
+	server.EmulatorPlus:       {Table: "user_badges", Column: "badge_id"},
+	server.EmulatorComet:      {Table: "player_badges", Column: "badge_code"},
+}
+
+// TableFor returns the badge table of emulator.
+func TableFor(emulator string) (BadgeTable, bool) {
+	table, ok := badgeTables[strings.ToLower(emulator)]
+	return table, ok
+}
+
+// MissingBadge is a defined badge code without an image.
+type MissingBadge struct {
+	Code string `json:"code"`
+	// Sources lists where the code is defined ("texts", "db").
+	Sources []string `json:"sources"`
+}
+
+// OrphanedBadge is a badge image whose code is defined nowhere.
+type OrphanedBadge struct {
+	Code string `json:"code"`
+	Key  string `json:"key"`
+}
+
+// Report is the result of a badge check.
+type Report struct {
+	// Prefix is where badge images were looked up.
+	Prefix string `json:"prefix"`
+	// Files is the number of badge images in storage.
+	Files int `json:"files"`
+	// Texts is the number of codes defined in ExternalTexts.json.
+	Texts int `json:"texts"`
+	// Database is the number of distinct codes owned in the badge table.
+	Database int             `json:"database"`
+	Missing  []MissingBadge  `json:"missing"`
+	Orphaned []OrphanedBadge `json:"orphaned"`
+	// Warnings lists sources that could not be read; the check ran without them.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// Check compares the badge images under prefix with the codes defined in
+// ExternalTexts.json and, when db is set, the emulator's badge table.
+func Check(ctx context.Context, client storage.Client, buckets storage.Buckets, db *gorm.DB, emulator, prefix string) (*Report, error) {
+	prefix = strings.Trim(prefix, "/")
+	report := &Report{Prefix: prefix, Missing: []MissingBadge{}, Orphaned: []OrphanedBadge{}}
+
+	defined := make(map[string][]string)
+
+	texts, err := loadTextCodes(ctx, client, buckets.Gamedata)
+	switch {
+	case errors.Is(err, errNoTexts):
+		report.Warnings = append(report.Warnings, TextsObject+" not found")
+	case err != nil:
+		return nil, err
+	}
+	for _, code := range texts {
+		defined[code] = append(defined[code], SourceTexts)
+	}
+	report.Texts = len(texts)
+
+	if db != nil {
+		owned, err := loadOwnedCodes(ctx, db, emulator)
+		if err != nil {
+			return nil, err
+		}
+		for _, code := range owned {
+			defined[code] = append(defined[code], SourceDatabase)
+		}
+		report.Database = len(owned)
+	}
+
+	files, err := listBadgeFiles(ctx, client, buckets.Assets, prefix)
+	if err != nil {
+		return nil, err
+	}
+	report.Files = len(files)
+
+	present := make(map[string]bool, len(files))
+	for _, file := range files {
+		present[file.code] = true
+		if _, ok := defined[file.code]; !ok {
+			report.Orphaned = append(report.Orphaned, OrphanedBadge{Code: file.code, Key: file.key})
+		}
+	}
+	for code, sources := range defined {
+		if !present[code] {
+			report.Missing = append(report.Missing, MissingBadge{Code: code, Sources: sources})
+		}
+	}
+	sort.Slice(report.Missing, func(i, j int) bool { return report.Missing[i].Code < report.Missing[j].Code })
+
+	return report, nil
+}
+
+// errNoTexts is returned by loadTextCodes when ExternalTexts.json does not exist.
+var errNoTexts = errors.New("texts not found")
+
+// loadTextCodes returns the badge codes defined in ExternalTexts.json, sorted.
+func loadTextCodes(ctx context.Context, client storage.Client, bucket string) ([]string, error) {
+	reader, err := client.GetObject(ctx, bucket, TextsObject, minio.GetObjectOptions{})
+	var data []byte
+	if err == nil {
+		data, err = io.ReadAll(reader)
+		reader.Close()
+	}
+	if err != nil {
+		if storage.IsNoSuchKey(err) {
+			return nil, errNoTexts
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", TextsObject, err)
+	}
+
+	var texts map[string]any
+	if err := json.Unmarshal(data, &texts); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", TextsObject, err)
+	}
+
+	seen := make(map[string]bool)
+	var codes []string
+	for key := range texts {
+		for _, prefix := range textPrefixes {
+			if code, ok := strings.CutPrefix(key, prefix); ok && code != "" && !seen[code] {
+				seen[code] = true
+				codes = append(codes, code)
+			}
+		}
+	}
+	sort.Strings(codes)
+	return codes, nil
+}
+
+// loadOwnedCodes returns the distinct badge codes in the emulator's badge table.
+func loadOwnedCodes(ctx context.Context, db *gorm.DB, emulator string) ([]string, error) {
+	table, ok := TableFor(emulator)
+	if !ok {
+		return nil, fmt.Errorf("unsupported emulator for badges: %s", emulator)
+	}
+	var codes []string
+	err := db.WithContext(ctx).
+		Table(table.Table).
+		Where(table.Column+" <> ''").
+		Distinct(table.Column).
+		Pluck(table.Column, &codes).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", table.Table, err)
+	}
+	return codes, nil
+}
+
+// badgeFile is a badge image in storage.
+type badgeFile struct {
+	code string
+	key  string
+}
+
+// listBadgeFiles lists the badge images under prefix, sorted by key.
+func listBadgeFiles(ctx context.Context, client storage.Client, bucket, prefix string) ([]badgeFile, error) {
+	opts := minio.ListObjectsOptions{Prefix: prefix + "/", Recursive: true}
+
+	var files []badgeFile
+	for obj := range client.ListObjects(ctx, bucket, opts) {
+		if obj.Err != nil {
+			return nil, fmt.Errorf("failed to list badges: %w", obj.Err)
+		}
+		name := path.Base(obj.Key)
+		ext := strings.ToLower(path.Ext(name))
+		if !imageExtensions[ext] {
+			continue
+		}
+		files = append(files, badgeFile{code: strings.TrimSuffix(name, path.Ext(name)), key: obj.Key})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].key < files[j].key })
+	return files, nil
+}
@@ -0,0 +1,106 @@
+package badges
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"asset-manager/core/storage"
+	"asset-manager/core/storage/mocks"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+const textsJSON = `{
+	"badge_name_ADM": "Staff",
+	"badge_desc_ADM": "Hotel staff",
+	"badge_name_ACH_Login1": "Login I",
+	"badge_desc_FRNDS": "Many friends",
+	"furni_chair_name": "Chair"
+}`
+
+// setupBadgeDB creates an Arcturus users_badges table holding codes.
+func setupBadgeDB(t *testing.T, codes ...string) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.Exec(`CREATE TABLE users_badges (id INTEGER PRIMARY KEY, user_id INTEGER, badge_code VARCHAR(32))`).Error)
+	for i, code := range codes {
+		require.NoError(t, db.Exec(`INSERT INTO users_badges (user_id, badge_code) VALUES (?, ?)`, i, code).Error)
+	}
+	return db
+}
+
+// mockBadgeStorage serves texts (NoSuchKey when empty) and the badge images keys.
+func mockBadgeStorage(texts string, keys ...string) *mocks.Client {
+	mockClient := new(mocks.Client)
+	if texts == "" {
+		mockClient.On("GetObject", mock.Anything, "test-bucket", TextsObject, mock.Anything).
+			Return(io.ReadCloser(nil), minio.ErrorResponse{Code: "NoSuchKey"})
+	} else {
+		mockClient.On("GetObject", mock.Anything, "test-bucket", TextsObject, mock.Anything).
+			Return(io.NopCloser(strings.NewReader(texts)), nil)
+	}
+
+	objects := make(chan minio.ObjectInfo, len(keys))
+	for _, key := range keys {
+		objects <- minio.ObjectInfo{Key: key}
+	}
+	close(objects)
+	mockClient.On("ListObjects", mock.Anything, "test-bucket", minio.ListObjectsOptions{Prefix: "c_images/album1584/", Recursive: true}).
+		Return((<-chan minio.ObjectInfo)(objects))
+	return mockClient
+}
+
+func TestCheck(t *testing.T) {
+	mockClient := mockBadgeStorage(textsJSON,
+		"c_images/album1584/ADM.gif",
+		"c_images/album1584/ACH_Login1.png",
+		"c_images/album1584/OLD.gif",
+		"c_images/album1584/Thumbs.db",
+	)
+	db := setupBadgeDB(t, "ADM", "VIP", "VIP", "")
+
+	report, err := Check(context.Background(), mockClient, storage.SingleBucket("test-bucket"), db, "arcturus", "/c_images/album1584/")
+	require.NoError(t, err)
+
+	assert.Equal(t, "c_images/album1584", report.Prefix)
+	assert.Equal(t, 3, report.Files)
+	assert.Equal(t, 3, report.Texts)
+	assert.Equal(t, 2, report.Database)
+	assert.Equal(t, []MissingBadge{
+		{Code: "FRNDS", Sources: []string{SourceTexts}},
+		{Code: "VIP", Sources: []string{SourceDatabase}},
+	}, report.Missing)
+	assert.Equal(t, []OrphanedBadge{{Code: "OLD", Key: "c_images/album1584/OLD.gif"}}, report.Orphaned)
+	assert.Empty(t, report.Warnings)
+}
+
+func TestCheck_NoTextsNoDB(t *testing.T) {
+	mockClient := mockBadgeStorage("", "c_images/album1584/ADM.gif")
+
+	report, err := Check(context.Background(), mockClient, storage.SingleBucket("test-bucket"), nil, "arcturus", "c_images/album1584")
+	require.NoError(t, err)
+	assert.Equal(t, []string{TextsObject + " not found"}, report.Warnings)
+	assert.Empty(t, report.Missing)
+	assert.Len(t, report.Orphaned, 1)
+}
+
+func TestTableFor(t *testing.T) {
+	table, ok := TableFor("plusemu")
+	require.True(t, ok)
+	assert.Equal(t, BadgeTable{Table: "user_badges", Column: "badge_id"}, table)
+
+	table, ok = TableFor("comet")
+	require.True(t, ok)
+	assert.Equal(t, "player_badges", table.Table)
+
+	_, ok = TableFor("unknown")
+	assert.False(t, ok)
+}
@@ -0,0 +1,22 @@
+// Package badges checks badge images in storage against the badges the hotel defines.
+//
+// Badge images live under a configurable prefix (STORAGE_LAYOUT_BADGES_PREFIX, default
+// c_images/album1584) as <CODE>.gif or <CODE>.png. A badge code is defined when
+// gamedata/ExternalTexts.json has a badge_name_<CODE> or badge_desc_<CODE> entry, or
+// when a user owns it in the emulator's badge table:
+//
+//   - Arcturus: users_badges.badge_code
+//   - Plus: user_badges.badge_id
+//   - Comet: player_badges.badge_code
+//
+// # Report
+//
+//   - Missing: defined codes without an image, with where each code is defined.
+//   - Orphaned: images whose code is defined nowhere.
+//
+// Without a database only the texts are used, and owned-only badges count as orphaned.
+//
+// # HTTP Endpoints
+//
+//   - GET /integrity/badges : Run the badge check.
+package badges
@@ -0,0 +1,54 @@
+package badges
+
+import (
+	"asset-manager/core/logger"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// Handler handles HTTP requests for badge checks.
+type Handler struct {
+	service *Service
+}
+
+// NewHandler creates a new HTTP handler.
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// RegisterRoutes registers the badge routes.
+func (h *Handler) RegisterRoutes(app fiber.Router) {
+	app.Get("/integrity/badges", h.HandleBadgeCheck)
+}
+
+// HandleBadgeCheck checks badge images against the defined badges.
+// @Summary Check Badge Images
+// @Description Compares badge images under the configured badges prefix with the badge codes defined in ExternalTexts.json (badge_name_/badge_desc_ keys) and the emulator's badge table. Reports defined badges without an image and images of undefined badges.
+// @Tags integrity
+// @Accept json
+// @Produce json
+// @Success 200 {object} badges.Report "Badge Report"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /integrity/badges [get]
+func (h *Handler) HandleBadgeCheck(c *fiber.Ctx) error {
+	l := logger.WithRayID(h.service.logger, c)
+	l.Info("Starting badge check")
+
+	report, err := h.service.Check(c.Context())
+	if err != nil {
+		l.Error("Badge check failed", zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	for _, warning := range report.Warnings {
+		l.Warn("Badge check warning", zap.String("warning", warning))
+	}
+	if len(report.Missing) > 0 || len(report.Orphaned) > 0 {
+		l.Warn("Badge issues detected", zap.Int("missing", len(report.Missing)), zap.Int("orphaned", len(report.Orphaned)))
+	}
+
+	return c.JSON(report)
+}
@@ -0,0 +1,33 @@
+package badges
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"asset-manager/core/json"
+	"asset-manager/core/storage"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestHandler_HandleBadgeCheck(t *testing.T) {
+	mockClient := mockBadgeStorage(textsJSON, "c_images/album1584/ADM.gif")
+	layout := storage.Layout{BadgesPrefix: "c_images/album1584"}
+	svc := NewService(mockClient, storage.SingleBucket("test-bucket"), layout, zap.NewNop(), nil, "arcturus")
+
+	app := fiber.New()
+	NewHandler(svc).RegisterRoutes(app)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/integrity/badges", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var report Report
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&report))
+	assert.Equal(t, 1, report.Files)
+	assert.Len(t, report.Missing, 2)
+	assert.Empty(t, report.Orphaned)
+}
@@ -0,0 +1,42 @@
+package badges
+
+import (
+	"context"
+
+	"asset-manager/core/reconcile"
+	"asset-manager/core/storage"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// Service runs badge checks.
+type Service struct {
+	client   storage.Client
+	buckets  storage.Buckets
+	layout   storage.Layout
+	logger   *zap.Logger
+	db       *gorm.DB
+	emulator string
+}
+
+// NewService creates a new badge service. A nil db checks against ExternalTexts.json only.
+func NewService(client storage.Client, buckets storage.Buckets, layout storage.Layout, logger *zap.Logger, db *gorm.DB, emulator string) *Service {
+	return &Service{
+		client:   client,
+		buckets:  buckets,
+		layout:   layout,
+		logger:   logger,
+		db:       db,
+		emulator: emulator,
+	}
+}
+
+// Check reports missing and orphaned badge images.
+func (s *Service) Check(ctx context.Context) (*Report, error) {
+	var db *gorm.DB
+	if s.db != nil {
+		db = reconcile.ReadDB(s.db)
+	}
+	return Check(ctx, s.client, s.buckets, db, s.emulator, s.layout.BadgesPrefix)
+}
@@ -0,0 +1,421 @@
+package catalog
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"asset-manager/core/reconcile"
+	"asset-manager/core/storage"
+	furnitureAdp "asset-manager/feature/furniture/reconcile"
+
+	"gorm.io/gorm"
+)
+
+// Adapter reconciles catalog offers, keyed by offer ID, against the furniture they
+// sell. Its database index holds the catalog_items rows (Offer). In place of a
+// gamedata index it holds the furniture rows each offer references (Listing), read
+// from the emulator furniture table. The catalog has no storage, so every offer is
+// expected absent from it.
+//
+// An offer whose every referenced furniture row is gone has no listing and is
+// purged from the database; a single-item offer whose catalog_name differs from the
+// item_name is synced. Offers referencing some deleted furniture are reported with
+// an item_ids warning.
+type Adapter struct {
+	db       *gorm.DB
+	emulator string
+}
+
+// NewAdapter creates a catalog adapter reading furniture rows from, and writing
+// offers to, db with the furniture table of emulator.
+func NewAdapter(db *gorm.DB, emulator string) *Adapter {
+	return &Adapter{db: db, emulator: emulator}
+}
+
+// FurnitureItem is one furniture row as the catalog sees it.
+type FurnitureItem struct {
+	ID       int    `json:"id"`
+	ItemName string `json:"item_name"`
+}
+
+// Listing is the furniture an offer sells, as found in the furniture table.
+type Listing struct {
+	OfferID int `json:"offer_id"`
+	// Items holds the referenced furniture rows that exist, in the order of the offer.
+	Items []FurnitureItem `json:"items"`
+	// Missing lists the referenced furniture IDs without a row.
+	Missing []int `json:"missing,omitempty"`
+}
+
+// newListing returns the listing of offer among the furniture names, by ID. An offer
+// referencing furniture of which none exists has no listing.
+func newListing(offer Offer, names map[int]string) (Listing, bool) {
+	listing := Listing{OfferID: offer.ID, Items: []FurnitureItem{}}
+	for _, id := range offer.ItemIDs {
+		if name, ok := names[id]; ok {
+			listing.Items = append(listing.Items, FurnitureItem{ID: id, ItemName: name})
+		} else {
+			listing.Missing = append(listing.Missing, id)
+		}
+	}
+	return listing, len(listing.Items) > 0 || len(offer.ItemIDs) == 0
+}
+
+// offerRow is one catalog_items row as read here.
+type offerRow struct {
+	ID          int
+	PageID      sql.NullInt64
+	CatalogName sql.NullString
+	Items       sql.NullString
+}
+
+// offer converts the row.
+func (row offerRow) offer() Offer {
+	return Offer{
+		ID:          row.ID,
+		PageID:      int(row.PageID.Int64),
+		CatalogName: row.CatalogName.String,
+		ItemIDs:     ParseItemIDs(row.Items.String),
+	}
+}
+
+// offerColumns returns the catalog_items columns read into an offerRow.
+func offerColumns(emulator string) string {
+	return "id, page_id, " + catalogNameColumn + ", " + ItemsColumn(emulator) + " AS items"
+}
+
+// loadOffers reads every catalog_items row, ordered by ID.
+func loadOffers(ctx context.Context, db *gorm.DB, emulator string) ([]Offer, error) {
+	var rows []offerRow
+	if err := db.WithContext(ctx).Table(ItemsTable).Select(offerColumns(emulator)).Order("id").Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", ItemsTable, err)
+	}
+	offers := make([]Offer, len(rows))
+	for i, row := range rows {
+		offers[i] = row.offer()
+	}
+	return offers, nil
+}
+
+// loadFurniture returns the item_name of the furniture rows of emulator by ID, of
+// every row or, with ids, of those rows only.
+func loadFurniture(ctx context.Context, db *gorm.DB, emulator string, ids ...int) (map[int]string, error) {
+	profile := furnitureAdp.GetProfileByName(emulator)
+	idColumn := profile.Columns[furnitureAdp.ColID]
+	query := db.WithContext(ctx).
+		Table(profile.TableName).
+		Select(idColumn + " AS id, " + profile.Columns[furnitureAdp.ColItemName] + " AS item_name")
+	if len(ids) > 0 {
+		query = query.Where(idColumn+" IN ?", ids)
+	}
+
+	var rows []struct {
+		ID       int
+		ItemName sql.NullString
+	}
+	if err := query.Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", profile.TableName, err)
+	}
+	names := make(map[int]string, len(rows))
+	for _, row := range rows {
+		names[row.ID] = row.ItemName.String
+	}
+	return names, nil
+}
+
+// Name returns the adapter name.
+func (a *Adapter) Name() string {
+	return AdapterName
+}
+
+// TableName returns the catalog_items table the adapter mutates.
+func (a *Adapter) TableName(serverProfile string) string {
+	return ItemsTable
+}
+
+// LoadDBIndex loads every offer, keyed by offer ID.
+func (a *Adapter) LoadDBIndex(ctx context.Context, db *gorm.DB, serverProfile string) (map[string]reconcile.DBItem, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database connection is nil")
+	}
+	offers, err := loadOffers(ctx, db, serverProfile)
+	if err != nil {
+		return nil, err
+	}
+	index := make(map[string]reconcile.DBItem, len(offers))
+	for _, offer := range offers {
+		index[strconv.Itoa(offer.ID)] = offer
+	}
+	return index, nil
+}
+
+// LoadGamedataIndex loads the listing of every offer, keyed by offer ID, from the
+// furniture table. The storage arguments are unused: the catalog has no gamedata.
+func (a *Adapter) LoadGamedataIndex(ctx context.Context, client storage.Client, bucket, objectName string, paths []string) (map[string]reconcile.GDItem, error) {
+	db := reconcile.ReadDB(a.db)
+	if db == nil {
+		return nil, fmt.Errorf("database connection is nil")
+	}
+	offers, err := loadOffers(ctx, db, a.emulator)
+	if err != nil {
+		return nil, err
+	}
+	names, err := loadFurniture(ctx, db, a.emulator)
+	if err != nil {
+		return nil, err
+	}
+
+	index := make(map[string]reconcile.GDItem, len(offers))
+	for _, offer := range offers {
+		if listing, ok := newListing(offer, names); ok {
+			index[strconv.Itoa(offer.ID)] = listing
+		}
+	}
+	return index, nil
+}
+
+// LoadStorageSet returns an empty set: offers have no storage objects.
+func (a *Adapter) LoadStorageSet(ctx context.Context, client storage.Client, bucket, prefix, extension string) (map[string]struct{}, error) {
+	return map[string]struct{}{}, nil
+}
+
+// ExtractDBKey returns the offer ID.
+func (a *Adapter) ExtractDBKey(item reconcile.DBItem) string {
+	return strconv.Itoa(item.(Offer).ID)
+}
+
+// ExtractGDKey returns the offer ID of a listing.
+func (a *Adapter) ExtractGDKey(item reconcile.GDItem) string {
+	return strconv.Itoa(item.(Listing).OfferID)
+}
+
+// ExtractStorageKey never matches: offers have no storage objects.
+func (a *Adapter) ExtractStorageKey(objectKey, prefix, extension string) (string, bool) {
+	return "", false
+}
+
+// ResolveName returns the catalog_name of the offer, or the item_name of the first
+// furniture it sells.
+func (a *Adapter) ResolveName(dbItem reconcile.DBItem, gdItem reconcile.GDItem) string {
+	if offer, ok := dbItem.(Offer); ok && offer.CatalogName != "" {
+		return offer.CatalogName
+	}
+	if listing, ok := gdItem.(Listing); ok && len(listing.Items) > 0 {
+		return listing.Items[0].ItemName
+	}
+	return ""
+}
+
+// CompareFields reports the catalog_name of a single-item offer differing from the
+// item_name, and the referenced furniture IDs without a row as an item_ids warning.
+func (a *Adapter) CompareFields(dbItem reconcile.DBItem, gdItem reconcile.GDItem) []string {
+	offer := dbItem.(Offer)
+	listing := gdItem.(Listing)
+
+	var mismatches []string
+	if len(listing.Missing) > 0 {
+		mismatches = append(mismatches, fmt.Sprintf("%s: gd=%v db=%v", itemIDsField, listingIDs(listing), offer.ItemIDs))
+		return mismatches
+	}
+	if itemName := listingName(offer, listing); itemName != "" && offer.CatalogName != itemName {
+		mismatches = append(mismatches, fmt.Sprintf("%s: gd='%s' db='%s'", catalogNameColumn, itemName, offer.CatalogName))
+	}
+	return mismatches
+}
+
+// FieldSeverity rates the item_ids of partly deleted bundles a warning: they still
+// sell something, so a sync leaves them alone.
+func (a *Adapter) FieldSeverity(field string) reconcile.Severity {
+	if field == itemIDsField {
+		return reconcile.SeverityWarning
+	}
+	return reconcile.SeverityError
+}
+
+// ExpectedAbsent reports every offer as expected absent from storage.
+func (a *Adapter) ExpectedAbsent(key string, dbItem reconcile.DBItem, gdItem reconcile.GDItem) []string {
+	return []string{reconcile.SourceStorage}
+}
+
+// listingIDs returns the IDs of the furniture rows of listing.
+func listingIDs(listing Listing) []int {
+	ids := make([]int, len(listing.Items))
+	for i, item := range listing.Items {
+		ids[i] = item.ID
+	}
+	return ids
+}
+
+// listingName returns the item_name a single-item offer should carry, or "" for
+// bundles and nameless rows.
+func listingName(offer Offer, listing Listing) string {
+	if len(offer.ItemIDs) != 1 || len(listing.Items) != 1 {
+		return ""
+	}
+	return listing.Items[0].ItemName
+}
+
+// QueryDB returns the offer of query.ID, or nil.
+func (a *Adapter) QueryDB(ctx context.Context, db *gorm.DB, serverProfile string, query reconcile.Query) (reconcile.DBItem, error) {
+	offer, found, err := queryOffer(ctx, db, serverProfile, query.ID)
+	if err != nil || !found {
+		return nil, err
+	}
+	return offer, nil
+}
+
+// QueryGamedata returns the listing of the offer of query.ID, or nil.
+func (a *Adapter) QueryGamedata(ctx context.Context, client storage.Client, bucket, objectName string, paths []string, query reconcile.Query) (reconcile.GDItem, error) {
+	db := reconcile.ReadDB(a.db)
+	offer, found, err := queryOffer(ctx, db, a.emulator, query.ID)
+	if err != nil || !found {
+		return nil, err
+	}
+
+	names := map[int]string{}
+	if len(offer.ItemIDs) > 0 {
+		if names, err = loadFurniture(ctx, db, a.emulator, offer.ItemIDs...); err != nil {
+			return nil, err
+		}
+	}
+	if listing, ok := newListing(offer, names); ok {
+		return listing, nil
+	}
+	return nil, nil
+}
+
+// queryOffer reads the offer with the given ID.
+func queryOffer(ctx context.Context, db *gorm.DB, emulator, id string) (Offer, bool, error) {
+	if db == nil {
+		return Offer{}, false, fmt.Errorf("database connection is nil")
+	}
+	if _, err := strconv.Atoi(id); err != nil {
+		return Offer{}, false, nil
+	}
+	var rows []offerRow
+	if err := db.WithContext(ctx).Table(ItemsTable).Select(offerColumns(emulator)).Where("id = ?", id).Limit(1).Scan(&rows).Error; err != nil {
+		return Offer{}, false, fmt.Errorf("failed to read %s: %w", ItemsTable, err)
+	}
+	if len(rows) == 0 {
+		return Offer{}, false, nil
+	}
+	return rows[0].offer(), true, nil
+}
+
+// CheckStorage reports false: offers have no storage objects.
+func (a *Adapter) CheckStorage(ctx context.Context, client storage.Client, bucket, prefix, extension string, key string) (bool, error) {
+	return false, nil
+}
+
+// GetMetadata returns the page, the referenced furniture IDs and, when some rows are
+// gone, the missing IDs of an offer, and the item_name of a single-item offer. ID
+// lists are separated by ";" like item_ids.
+func (a *Adapter) GetMetadata(dbItem reconcile.DBItem, gdItem reconcile.GDItem) map[string]string {
+	metadata := make(map[string]string)
+	offer, ok := dbItem.(Offer)
+	if !ok {
+		return metadata
+	}
+	metadata["page_id"] = strconv.Itoa(offer.PageID)
+	metadata[catalogNameColumn] = offer.CatalogName
+	metadata[itemIDsField] = joinIDs(offer.ItemIDs)
+
+	listing, ok := gdItem.(Listing)
+	if !ok {
+		// Every referenced row is gone
+		metadata["missing"] = joinIDs(offer.ItemIDs)
+		return metadata
+	}
+	if len(listing.Missing) > 0 {
+		metadata["missing"] = joinIDs(listing.Missing)
+	}
+	if itemName := listingName(offer, listing); itemName != "" {
+		metadata["item_name"] = itemName
+	}
+	return metadata
+}
+
+// joinIDs formats ids as an item_ids value.
+func joinIDs(ids []int) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.Itoa(id)
+	}
+	return strings.Join(parts, ";")
+}
+
+// Prepare does nothing: the catalog schema is left alone.
+func (a *Adapter) Prepare(ctx context.Context, db *gorm.DB) error {
+	return nil
+}
+
+// errNoCatalogStore is returned for deletions from stores the catalog does not have.
+var errNoCatalogStore = errors.New("catalog offers have no gamedata entry or storage object")
+
+// DeleteDB deletes the offer of key.
+func (a *Adapter) DeleteDB(ctx context.Context, key string) error {
+	return a.DeleteDBBatch(ctx, []string{key})
+}
+
+// DeleteDBBatch deletes the offers of keys in one transaction.
+func (a *Adapter) DeleteDBBatch(ctx context.Context, keys []string) error {
+	if a.db == nil {
+		return fmt.Errorf("database connection is nil")
+	}
+	return a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, key := range keys {
+			if err := tx.Table(ItemsTable).Where("id = ?", key).Delete(nil).Error; err != nil {
+				return fmt.Errorf("failed to delete offer %s: %w", key, err)
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteGamedata fails: offers have no gamedata entry.
+func (a *Adapter) DeleteGamedata(ctx context.Context, key string) error {
+	return errNoCatalogStore
+}
+
+// DeleteStorage fails: offers have no storage object.
+func (a *Adapter) DeleteStorage(ctx context.Context, key string) error {
+	return errNoCatalogStore
+}
+
+// SyncDBFromGamedata syncs the offer of key (see SyncDBBatch).
+func (a *Adapter) SyncDBFromGamedata(ctx context.Context, key string, gdItem reconcile.GDItem) error {
+	return a.SyncDBBatch(ctx, []reconcile.Action{{Type: reconcile.ActionSyncDB, Key: key, GDItem: gdItem}})
+}
+
+// SyncDBBatch runs the syncs in one transaction. A Listing sets the catalog_name of
+// a single-item offer to its item_name; a map, as planned by PlanOffers, holds the
+// commerce columns to write.
+func (a *Adapter) SyncDBBatch(ctx context.Context, actions []reconcile.Action) error {
+	if a.db == nil {
+		return fmt.Errorf("database connection is nil")
+	}
+	return a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, action := range actions {
+			var updates map[string]any
+			switch value := action.GDItem.(type) {
+			case Listing:
+				if len(value.Items) != 1 {
+					return fmt.Errorf("offer %s does not sell a single item to take its name from", action.Key)
+				}
+				updates = map[string]any{catalogNameColumn: value.Items[0].ItemName}
+			case map[string]any:
+				updates = value
+			default:
+				return fmt.Errorf("sync of offer %s has no values to write", action.Key)
+			}
+			if err := tx.Table(ItemsTable).Where("id = ?", action.Key).Updates(updates).Error; err != nil {
+				return fmt.Errorf("failed to sync offer %s: %w", action.Key, err)
+			}
+		}
+		return nil
+	})
+}
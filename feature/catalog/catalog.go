@@ -0,0 +1,214 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"asset-manager/core/reconcile"
+	"asset-manager/core/storage"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// ItemsTable is the emulator table holding catalog offers.
+	ItemsTable = "catalog_items"
+
+	// PagesTable is the emulator table holding catalog pages.
+	PagesTable = "catalog_pages"
+
+	// AdapterName is the name of the catalog adapter in audit entries.
+	AdapterName = "catalog"
+
+	// catalogNameColumn is the catalog_items column repeating the furniture item_name.
+	catalogNameColumn = "catalog_name"
+
+	// itemIDsField labels the mismatch of offers referencing deleted furniture.
+	itemIDsField = "item_ids"
+)
+
+// ItemsColumn returns the catalog_items column referencing furniture rows. PlusEMU
+// offers hold one item_id; Arcturus and Comet hold an item_ids list for bundles.
+func ItemsColumn(emulator string) string {
+	switch emulator {
+	case "plus", "plusemu":
+		return "item_id"
+	default:
+		return "item_ids"
+	}
+}
+
+// ParseItemIDs returns the furniture IDs in an item_ids value. Entries are separated
+// by ";" or ","; an amount suffix ("12:3" or "12*3") is dropped, as are zero and
+// unparsable entries.
+func ParseItemIDs(value string) []int {
+	var ids []int
+	for _, part := range strings.FieldsFunc(value, func(r rune) bool { return r == ';' || r == ',' }) {
+		if i := strings.IndexAny(part, ":*"); i >= 0 {
+			part = part[:i]
+		}
+		id, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || id <= 0 {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// Offer is one catalog_items row.
+type Offer struct {
+	ID          int    `json:"id"`
+	PageID      int    `json:"page_id"`
+	CatalogName string `json:"catalog_name"`
+	ItemIDs     []int  `json:"item_ids"`
+}
+
+// DanglingOffer is an offer referencing furniture rows that no longer exist.
+type DanglingOffer struct {
+	Offer
+	// Missing lists the referenced furniture IDs without a row.
+	Missing []int `json:"missing"`
+}
+
+// NameMismatch is a single-item offer whose catalog_name differs from the item_name.
+type NameMismatch struct {
+	OfferID     int    `json:"offer_id"`
+	ItemID      int    `json:"item_id"`
+	CatalogName string `json:"catalog_name"`
+	ItemName    string `json:"item_name"`
+}
+
+// Report contains the results of a catalog reconciliation.
+type Report struct {
+	// Offers, Pages and Furniture count the rows read from each table.
+	Offers    int `json:"offers"`
+	Pages     int `json:"pages"`
+	Furniture int `json:"furniture"`
+
+	// Dangling lists offers pointing at deleted furniture.
+	Dangling []DanglingOffer `json:"dangling"`
+	// Pageless lists offers on a page that does not exist.
+	Pageless []Offer `json:"pageless"`
+	// Unpublished lists furniture not sold on any existing page.
+	Unpublished []FurnitureItem `json:"unpublished"`
+	// Mismatches lists offers whose catalog_name differs from their item's item_name.
+	Mismatches []NameMismatch `json:"mismatches"`
+
+	// Summary holds the engine counts of the run, ignored offers included.
+	Summary reconcile.PlanSummary `json:"summary"`
+
+	// Actions holds the planned purge and sync actions, keyed by offer ID.
+	Actions []reconcile.Action `json:"actions"`
+
+	// Plan is the engine plan the report was built from, for ApplyPlan.
+	Plan *reconcile.ReconcilePlan `json:"-"`
+}
+
+// Reconcile cross-checks catalog_items and catalog_pages against the furniture table
+// of the emulator through the reconcile engine, and plans actions for the requested
+// operations:
+//   - opts.DoPurge deletes offers whose every referenced furniture row is gone;
+//   - opts.DoSync sets the catalog_name of single-item offers to the item_name.
+//
+// Offers still selling some existing furniture are reported but never purged.
+// Ignored offers are left out of the report like any other ignored entity.
+func Reconcile(ctx context.Context, spec *reconcile.Spec, db *gorm.DB, client storage.Client, bucket string, opts reconcile.ReconcileOptions) (*Report, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database connection is nil")
+	}
+	plan, err := reconcile.ReconcileWithPlan(ctx, spec, db, client, bucket, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	db = reconcile.ReadDB(db)
+	var pages int64
+	if err := db.WithContext(ctx).Table(PagesTable).Count(&pages).Error; err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", PagesTable, err)
+	}
+	names, err := loadFurniture(ctx, db, spec.ServerProfile)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{
+		Pages:       int(pages),
+		Furniture:   len(names),
+		Dangling:    make([]DanglingOffer, 0),
+		Pageless:    make([]Offer, 0),
+		Unpublished: make([]FurnitureItem, 0),
+		Mismatches:  make([]NameMismatch, 0),
+		Summary:     plan.Summary,
+		Actions:     plan.Actions,
+		Plan:        plan,
+	}
+	if report.Actions == nil {
+		report.Actions = make([]reconcile.Action, 0)
+	}
+
+	results := slices.Clone(plan.Results)
+	sort.Slice(results, func(i, j int) bool { return offerID(results[i]) < offerID(results[j]) })
+
+	published := make(map[int]bool)
+	for _, result := range results {
+		if !result.DBPresent {
+			continue
+		}
+		report.Offers++
+		offer := resultOffer(result)
+
+		onPage := result.Present(SourcePages)
+		if !onPage {
+			report.Pageless = append(report.Pageless, offer)
+		}
+		for _, id := range offer.ItemIDs {
+			if _, ok := names[id]; ok && onPage {
+				published[id] = true
+			}
+		}
+
+		if missing := ParseItemIDs(result.Metadata["missing"]); len(missing) > 0 {
+			report.Dangling = append(report.Dangling, DanglingOffer{Offer: offer, Missing: missing})
+			continue
+		}
+		if slices.Contains(reconcile.MismatchFields(result.Mismatch), catalogNameColumn) {
+			report.Mismatches = append(report.Mismatches, NameMismatch{
+				OfferID:     offer.ID,
+				ItemID:      offer.ItemIDs[0],
+				CatalogName: offer.CatalogName,
+				ItemName:    result.Metadata["item_name"],
+			})
+		}
+	}
+
+	for id, name := range names {
+		if !published[id] {
+			report.Unpublished = append(report.Unpublished, FurnitureItem{ID: id, ItemName: name})
+		}
+	}
+	sort.Slice(report.Unpublished, func(i, j int) bool { return report.Unpublished[i].ID < report.Unpublished[j].ID })
+
+	return report, nil
+}
+
+// offerID returns the offer ID of a result.
+func offerID(result reconcile.ReconcileResult) int {
+	id, _ := strconv.Atoi(result.ID)
+	return id
+}
+
+// resultOffer rebuilds the offer of a result from its metadata (see GetMetadata).
+func resultOffer(result reconcile.ReconcileResult) Offer {
+	pageID, _ := strconv.Atoi(result.Metadata["page_id"])
+	return Offer{
+		ID:          offerID(result),
+		PageID:      pageID,
+		CatalogName: result.Metadata[catalogNameColumn],
+		ItemIDs:     ParseItemIDs(result.Metadata[itemIDsField]),
+	}
+}
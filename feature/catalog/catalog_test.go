@@ -0,0 +1,149 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"asset-manager/core/reconcile"
+	"asset-manager/core/storage/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// setupCatalogDB creates Arcturus catalog and furniture tables:
+//   - furniture 1 chair, 2 table, 3 lamp (never sold), 4 rug (only on a missing page)
+//   - offer 10 sells 1 on page 1; offer 11 sells deleted 9; offer 12 bundles 2 and
+//     deleted 8; offer 13 sells 4 on missing page 7; offer 14 sells 2 as "old_table"
+func setupCatalogDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.Exec(`CREATE TABLE items_base (id INTEGER PRIMARY KEY, item_name VARCHAR(70))`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE catalog_pages (id INTEGER PRIMARY KEY, caption VARCHAR(128))`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE catalog_items (id INTEGER PRIMARY KEY, page_id INTEGER, item_ids VARCHAR(666), catalog_name VARCHAR(100))`).Error)
+
+	require.NoError(t, db.Exec(`INSERT INTO items_base (id, item_name) VALUES (1, 'chair'), (2, 'table'), (3, 'lamp'), (4, 'rug')`).Error)
+	require.NoError(t, db.Exec(`INSERT INTO catalog_pages (id, caption) VALUES (1, 'Shop')`).Error)
+	require.NoError(t, db.Exec(`INSERT INTO catalog_items (id, page_id, item_ids, catalog_name) VALUES
+		(10, 1, '1', 'chair'),
+		(11, 1, '9', 'ghost'),
+		(12, 1, '2;8:2', 'bundle'),
+		(13, 7, '4', 'rug'),
+		(14, 1, '2', 'old_table')`).Error)
+	return db
+}
+
+func TestParseItemIDs(t *testing.T) {
+	assert.Equal(t, []int{12}, ParseItemIDs("12"))
+	assert.Equal(t, []int{1, 2, 3}, ParseItemIDs("1;2:5, 3*2"))
+	assert.Empty(t, ParseItemIDs(""))
+	assert.Empty(t, ParseItemIDs("0;abc"))
+}
+
+func TestItemsColumn(t *testing.T) {
+	assert.Equal(t, "item_ids", ItemsColumn("arcturus"))
+	assert.Equal(t, "item_ids", ItemsColumn("comet"))
+	assert.Equal(t, "item_id", ItemsColumn("plusemu"))
+}
+
+// catalogClient returns a storage client whose assets bucket exists; the engine
+// checks storage is reachable before every run.
+func catalogClient() *mocks.Client {
+	client := new(mocks.Client)
+	client.On("BucketExists", mock.Anything, "assets").Return(true, nil)
+	return client
+}
+
+// reconcileCatalog reconciles the arcturus catalog of db through the engine.
+func reconcileCatalog(t *testing.T, db *gorm.DB, opts reconcile.ReconcileOptions) *Report {
+	report, err := Reconcile(context.Background(), NewSpec(NewAdapter(db, "arcturus"), "arcturus"), db, catalogClient(), "assets", opts)
+	require.NoError(t, err)
+	return report
+}
+
+func TestReconcile_Report(t *testing.T) {
+	db := setupCatalogDB(t)
+
+	report := reconcileCatalog(t, db, reconcile.ReconcileOptions{})
+
+	assert.Equal(t, 5, report.Offers)
+	assert.Equal(t, 1, report.Pages)
+	assert.Equal(t, 4, report.Furniture)
+
+	require.Len(t, report.Dangling, 2)
+	assert.Equal(t, 11, report.Dangling[0].ID)
+	assert.Equal(t, []int{9}, report.Dangling[0].Missing)
+	assert.Equal(t, 12, report.Dangling[1].ID)
+	assert.Equal(t, []int{8}, report.Dangling[1].Missing)
+
+	require.Len(t, report.Pageless, 1)
+	assert.Equal(t, 13, report.Pageless[0].ID)
+
+	assert.Equal(t, []FurnitureItem{{ID: 3, ItemName: "lamp"}, {ID: 4, ItemName: "rug"}}, report.Unpublished)
+	assert.Equal(t, []NameMismatch{{OfferID: 14, ItemID: 2, CatalogName: "old_table", ItemName: "table"}}, report.Mismatches)
+	assert.Empty(t, report.Actions)
+	assert.Equal(t, 1, report.Summary.MissingSources[SourcePages])
+}
+
+func TestReconcile_PurgeAndSync(t *testing.T) {
+	db := setupCatalogDB(t)
+	ctx := context.Background()
+	opts := reconcile.ReconcileOptions{DoPurge: true, DoSync: true}
+	spec := NewSpec(NewAdapter(db, "arcturus"), "arcturus")
+	client := catalogClient()
+
+	report, err := Reconcile(ctx, spec, db, client, "assets", opts)
+	require.NoError(t, err)
+	require.Len(t, report.Actions, 2)
+	assert.Equal(t, reconcile.ActionDeleteDB, report.Actions[0].Type)
+	assert.Equal(t, "11", report.Actions[0].Key)
+	assert.Equal(t, reconcile.ActionSyncDB, report.Actions[1].Type)
+	assert.Equal(t, "14", report.Actions[1].Key)
+	assert.Equal(t, []string{catalogNameColumn}, report.Actions[1].Fields)
+
+	opts.Confirmed = true
+	executed, err := reconcile.ApplyPlan(ctx, spec, db, client, "assets", report.Plan, opts)
+	require.NoError(t, err)
+	assert.Equal(t, 2, executed)
+
+	var count int64
+	db.Table(ItemsTable).Where("id = ?", 11).Count(&count)
+	assert.Zero(t, count)
+	var name string
+	db.Table(ItemsTable).Where("id = ?", 14).Pluck("catalog_name", &name)
+	assert.Equal(t, "table", name)
+
+	// The partially dangling bundle is never purged
+	report = reconcileCatalog(t, db, reconcile.ReconcileOptions{DoPurge: true, DoSync: true})
+	assert.Empty(t, report.Actions)
+	assert.Len(t, report.Dangling, 1)
+}
+
+func TestAdapter_QueryOffer(t *testing.T) {
+	db := setupCatalogDB(t)
+	ctx := context.Background()
+	adapter := NewAdapter(db, "arcturus")
+
+	dbItem, err := adapter.QueryDB(ctx, db, "arcturus", reconcile.Query{ID: "12"})
+	require.NoError(t, err)
+	assert.Equal(t, Offer{ID: 12, PageID: 1, CatalogName: "bundle", ItemIDs: []int{2, 8}}, dbItem)
+
+	gdItem, err := adapter.QueryGamedata(ctx, nil, "", "", nil, reconcile.Query{ID: "12"})
+	require.NoError(t, err)
+	assert.Equal(t, Listing{OfferID: 12, Items: []FurnitureItem{{ID: 2, ItemName: "table"}}, Missing: []int{8}}, gdItem)
+	assert.Equal(t, []string{"item_ids: gd=[2] db=[2 8]"}, adapter.CompareFields(dbItem, gdItem))
+	assert.Equal(t, reconcile.SeverityWarning, adapter.FieldSeverity(itemIDsField))
+
+	// An offer selling only deleted furniture has no listing
+	gdItem, err = adapter.QueryGamedata(ctx, nil, "", "", nil, reconcile.Query{ID: "11"})
+	require.NoError(t, err)
+	assert.Nil(t, gdItem)
+
+	dbItem, err = adapter.QueryDB(ctx, db, "arcturus", reconcile.Query{ID: "99"})
+	require.NoError(t, err)
+	assert.Nil(t, dbItem)
+}
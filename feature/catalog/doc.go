@@ -0,0 +1,49 @@
+// Package catalog reconciles the emulator catalog against its furniture table.
+//
+// Offers in catalog_items reference furniture rows (items_base on Arcturus, furniture
+// on Plus and Comet) through item_ids, or item_id on Plus. Bundles list several IDs,
+// separated by ";" or ",", optionally with an amount ("12:3").
+//
+// # Report
+//
+//   - Dangling: offers referencing furniture rows that no longer exist.
+//   - Pageless: offers on a catalog_pages id that does not exist.
+//   - Unpublished: furniture rows no offer on an existing page sells.
+//   - Mismatches: single-item offers whose catalog_name differs from the item_name.
+//
+// # Engine
+//
+// Adapter reconciles offers through the reconcile engine, keyed by offer ID. Its
+// database index holds the offers and its gamedata index the furniture each offer
+// sells (Listing); the pages source reports offers on a missing page. NewSpec builds
+// the spec and Register adds it to the registry, so the ignore list, history, plan
+// files, verification, reconcile all and the health score cover the catalog.
+//
+// # Actions
+//
+// Reconcile plans reconcile.Action values keyed by offer ID: with DoPurge, a
+// delete_db for every offer selling only deleted furniture; with DoSync, a sync_db
+// setting catalog_name to the item_name. reconcile.ApplyPlan runs them through the
+// adapter, each kind in one transaction. Unpublished furniture is report-only;
+// publishing needs a page and a price.
+//
+// # Offer Sync
+//
+// PlanOffers compares the commerce columns of single-item offers (offer ID, buyout
+// and rent flags, mapped by the OfferColumns of the server profile) with the
+// gamedata entry of their furniture, loaded with LoadGamedata. Its sync_db actions
+// carry the columns to write and run through the adapter as well.
+//
+// # Price Sanity
+//
+// CheckPrices is an opt-in check reporting offers whose every price is zero,
+// negative prices and prices above reconcile.PriceBounds, read from whichever
+// cost_* columns catalog_items has. The HTTP check uses the bounds set by
+// SetPriceBounds.
+//
+// # HTTP Endpoints
+//
+//   - GET /reconcile/catalog : Run the catalog reconciliation (report only).
+//   - GET /reconcile/catalog/offers : Preview the offer sync (report only).
+//   - GET /reconcile/catalog/prices : Check offer prices (report only).
+package catalog
@@ -0,0 +1,110 @@
+package catalog
+
+import (
+	"asset-manager/core/logger"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// Handler handles HTTP requests for catalog reconciliation.
+type Handler struct {
+	service *Service
+}
+
+// NewHandler creates a new HTTP handler.
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// RegisterRoutes registers the catalog routes.
+func (h *Handler) RegisterRoutes(app fiber.Router) {
+	app.Get("/reconcile/catalog", h.HandleReconcileCatalog)
+	app.Get("/reconcile/catalog/offers", h.HandleOfferPreview)
+	app.Get("/reconcile/catalog/prices", h.HandlePriceCheck)
+}
+
+// HandleReconcileCatalog cross-checks the catalog against the furniture table.
+// @Summary Reconcile Catalog
+// @Description Cross-checks catalog_items and catalog_pages against the emulator furniture table. Reports offers pointing at deleted furniture, offers on missing pages, furniture not sold on any page and offers whose catalog_name differs from the item_name. Report only; purge and sync run from the CLI.
+// @Tags reconcile
+// @Accept json
+// @Produce json
+// @Success 200 {object} catalog.Report "Catalog Report"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /reconcile/catalog [get]
+func (h *Handler) HandleReconcileCatalog(c *fiber.Ctx) error {
+	l := logger.WithRayID(h.service.logger, c)
+	l.Info("Starting catalog reconciliation")
+
+	report, err := h.service.Reconcile(c.Context())
+	if err != nil {
+		l.Error("Catalog reconciliation failed", zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	if len(report.Dangling) > 0 || len(report.Pageless) > 0 {
+		l.Warn("Catalog issues detected",
+			zap.Int("dangling", len(report.Dangling)),
+			zap.Int("pageless", len(report.Pageless)),
+			zap.Int("unpublished", len(report.Unpublished)),
+			zap.Int("mismatches", len(report.Mismatches)))
+	}
+
+	return c.JSON(report)
+}
+
+// HandleOfferPreview previews the sync of gamedata commerce data into the catalog.
+// @Summary Preview Catalog Offer Sync
+// @Description Compares the offer ID, buyout and rent columns of single-item catalog offers with the offerid, buyout, rentofferid and rentbuyout of their furniture in FurnitureData.json, and lists the offers a sync would change with each column's current and gamedata value. The columns come from the server profile; unmapped or missing ones are listed as skipped. Report only; the sync runs from the CLI.
+// @Tags reconcile
+// @Accept json
+// @Produce json
+// @Success 200 {object} catalog.OfferReport "Offer Sync Preview"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /reconcile/catalog/offers [get]
+func (h *Handler) HandleOfferPreview(c *fiber.Ctx) error {
+	l := logger.WithRayID(h.service.logger, c)
+
+	report, err := h.service.PreviewOffers(c.Context())
+	if err != nil {
+		l.Error("Catalog offer preview failed", zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(report)
+}
+
+// HandlePriceCheck reports catalog offers with implausible prices.
+// @Summary Check Catalog Prices
+// @Description Reads the price columns of catalog_items (cost_credits, cost_points, cost_pixels, cost_diamonds, where present) and reports offers whose every price is zero, negative prices, and prices above RECONCILE_CATALOG_PRICES_MAX_CREDITS or RECONCILE_CATALOG_PRICES_MAX_POINTS. Report only.
+// @Tags reconcile
+// @Accept json
+// @Produce json
+// @Success 200 {object} catalog.PriceReport "Price Report"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /reconcile/catalog/prices [get]
+func (h *Handler) HandlePriceCheck(c *fiber.Ctx) error {
+	l := logger.WithRayID(h.service.logger, c)
+
+	report, err := h.service.CheckPrices(c.Context())
+	if err != nil {
+		l.Error("Catalog price check failed", zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	if len(report.Issues) > 0 {
+		l.Warn("Catalog price issues detected",
+			zap.Int("free", report.Free),
+			zap.Int("negative", report.Negative),
+			zap.Int("too_high", report.TooHigh))
+	}
+
+	return c.JSON(report)
+}
@@ -0,0 +1,59 @@
+package catalog
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"asset-manager/core/json"
+	"asset-manager/core/reconcile"
+	"asset-manager/core/storage"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestHandler_HandleReconcileCatalog(t *testing.T) {
+	db := setupCatalogDB(t)
+	app := fiber.New()
+	NewHandler(NewService(catalogClient(), storage.Buckets{Assets: "assets"}, db, "arcturus", zap.NewNop())).RegisterRoutes(app)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/reconcile/catalog", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var report Report
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&report))
+	assert.Len(t, report.Dangling, 2)
+	assert.Len(t, report.Unpublished, 2)
+}
+
+func TestHandler_HandleReconcileCatalog_Error(t *testing.T) {
+	db := setupCatalogDB(t)
+	require.NoError(t, db.Exec(`DROP TABLE catalog_pages`).Error)
+	app := fiber.New()
+	NewHandler(NewService(catalogClient(), storage.Buckets{Assets: "assets"}, db, "arcturus", zap.NewNop())).RegisterRoutes(app)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/reconcile/catalog", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 500, resp.StatusCode)
+}
+
+func TestHandler_HandlePriceCheck(t *testing.T) {
+	db := setupPricesDB(t)
+	SetPriceBounds(reconcile.PriceBounds{MaxCredits: 1000})
+	defer SetPriceBounds(defaultPriceBounds)
+	app := fiber.New()
+	NewHandler(NewService(nil, storage.Buckets{}, db, "arcturus", zap.NewNop())).RegisterRoutes(app)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/reconcile/catalog/prices", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var report PriceReport
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&report))
+	assert.Equal(t, 1000, report.Bounds.MaxCredits)
+	assert.Equal(t, 1, report.TooHigh)
+	assert.Equal(t, 2, report.Free)
+}
@@ -0,0 +1,39 @@
+package catalog
+
+import (
+	"asset-manager/core/storage"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// Feature implements the loader.Feature interface.
+type Feature struct {
+	service *Service
+	handler *Handler
+}
+
+// NewFeature creates a new Catalog feature.
+func NewFeature(client storage.Client, buckets storage.Buckets, db *gorm.DB, emulator string, logger *zap.Logger) *Feature {
+	svc := NewService(client, buckets, db, emulator, logger)
+	h := NewHandler(svc)
+	return &Feature{service: svc, handler: h}
+}
+
+// Name returns the name of the feature.
+func (f *Feature) Name() string {
+	return "catalog"
+}
+
+// IsEnabled reports whether a database is configured; the catalog lives there.
+func (f *Feature) IsEnabled() bool {
+	return f.service.db != nil
+}
+
+// Load registers the feature's routes and its reconcile spec.
+func (f *Feature) Load(app fiber.Router) error {
+	Register(f.service.db, f.service.emulator)
+	f.handler.RegisterRoutes(app)
+	return nil
+}
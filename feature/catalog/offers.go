@@ -0,0 +1,232 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"asset-manager/core/reconcile"
+	"asset-manager/core/storage"
+	"asset-manager/core/utils"
+	furnitureAdp "asset-manager/feature/furniture/reconcile"
+
+	"gorm.io/gorm"
+)
+
+// offerFields lists the commerce fields the offer sync writes, in report order.
+var offerFields = []string{
+	furnitureAdp.OfferFieldID,
+	furnitureAdp.OfferFieldBuyout,
+	furnitureAdp.OfferFieldRentID,
+	furnitureAdp.OfferFieldRentBuyout,
+}
+
+// OfferChange is one catalog_items column an offer sync would rewrite.
+type OfferChange struct {
+	Field   string `json:"field"`
+	Column  string `json:"column"`
+	Current string `json:"current"`
+	Target  string `json:"target"`
+}
+
+// OfferSync is a single-item offer whose commerce columns differ from the gamedata
+// entry of the furniture it sells.
+type OfferSync struct {
+	OfferID     int           `json:"offer_id"`
+	PageID      int           `json:"page_id"`
+	CatalogName string        `json:"catalog_name"`
+	ItemID      int           `json:"item_id"`
+	SpriteID    int           `json:"sprite_id"`
+	Changes     []OfferChange `json:"changes"`
+}
+
+// OfferReport previews the sync of gamedata commerce data (offerid, buyout,
+// rentofferid, rentbuyout) into catalog_items.
+type OfferReport struct {
+	// Checked counts the single-item offers whose furniture has a gamedata entry.
+	Checked int `json:"checked"`
+	// Columns lists the catalog_items columns the sync writes.
+	Columns []string `json:"columns"`
+	// Skipped lists the commerce fields not synced, because the server profile does
+	// not map them or catalog_items lacks their column.
+	Skipped []string `json:"skipped,omitempty"`
+	// Syncs lists the offers the sync would change, with each changed column.
+	Syncs []OfferSync `json:"syncs"`
+
+	// Actions holds one sync_db action per offer in Syncs, keyed by offer ID.
+	Actions []reconcile.Action `json:"actions"`
+}
+
+// spriteRow is one furniture row with the sprite_id keying its gamedata entry.
+type spriteRow struct {
+	ID       int
+	SpriteID int
+}
+
+// LoadGamedata reads the furniture gamedata entries keyed by ID, as the offer sync
+// expects them.
+func LoadGamedata(ctx context.Context, client storage.Client, bucket string) (map[string]reconcile.GDItem, error) {
+	index, err := furnitureAdp.NewAdapter().LoadGamedataIndex(ctx, client, bucket, furnitureAdp.GamedataObject, furnitureAdp.GamedataPaths)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load furniture gamedata: %w", err)
+	}
+	return index, nil
+}
+
+// PlanOffers compares the commerce columns of every single-item offer with the
+// gamedata entry of its furniture and plans a sync_db action for each offer that
+// differs. The columns come from the OfferColumns of the emulator's server profile;
+// bundles and offers of furniture without a gamedata entry are left alone.
+func PlanOffers(ctx context.Context, db *gorm.DB, emulator string, gamedata map[string]reconcile.GDItem) (*OfferReport, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database connection is nil")
+	}
+	profile := furnitureAdp.GetProfileByName(emulator)
+
+	report := &OfferReport{
+		Columns: make([]string, 0),
+		Syncs:   make([]OfferSync, 0),
+		Actions: make([]reconcile.Action, 0),
+	}
+	var fields []string
+	for _, field := range offerFields {
+		col, ok := profile.OfferColumns[field]
+		if !ok || !db.Migrator().HasColumn(ItemsTable, col) {
+			report.Skipped = append(report.Skipped, field)
+			continue
+		}
+		fields = append(fields, field)
+		report.Columns = append(report.Columns, col)
+	}
+	if len(fields) == 0 {
+		return report, nil
+	}
+
+	var furniture []spriteRow
+	err := db.WithContext(ctx).
+		Table(profile.TableName).
+		Select(profile.Columns[furnitureAdp.ColID] + " AS id, " + profile.Columns[furnitureAdp.ColSpriteID] + " AS sprite_id").
+		Scan(&furniture).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", profile.TableName, err)
+	}
+	sprites := make(map[int]int, len(furniture))
+	for _, row := range furniture {
+		sprites[row.ID] = row.SpriteID
+	}
+
+	selected := "id, page_id, " + catalogNameColumn + ", " + ItemsColumn(emulator) + " AS items"
+	for _, col := range report.Columns {
+		selected += ", " + col
+	}
+	rows, err := db.WithContext(ctx).Table(ItemsTable).Select(selected).Order("id").Rows()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", ItemsTable, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row offerRow
+		current := make([]any, len(fields))
+		dest := []any{&row.ID, &row.PageID, &row.CatalogName, &row.Items}
+		for i := range current {
+			dest = append(dest, &current[i])
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("failed to scan %s: %w", ItemsTable, err)
+		}
+
+		itemIDs := ParseItemIDs(row.Items.String)
+		if len(itemIDs) != 1 {
+			continue
+		}
+		spriteID, ok := sprites[itemIDs[0]]
+		if !ok {
+			continue
+		}
+		gdItem, ok := gamedata[strconv.Itoa(spriteID)]
+		if !ok {
+			continue
+		}
+		report.Checked++
+
+		planned := OfferSync{
+			OfferID:     row.ID,
+			PageID:      int(row.PageID.Int64),
+			CatalogName: row.CatalogName.String,
+			ItemID:      itemIDs[0],
+			SpriteID:    spriteID,
+		}
+		updates := make(map[string]any)
+		for i, field := range fields {
+			if change, value, ok := offerChange(field, report.Columns[i], current[i], gdItem.(furnitureAdp.GDItem)); ok {
+				planned.Changes = append(planned.Changes, change)
+				updates[change.Column] = value
+			}
+		}
+		if len(planned.Changes) == 0 {
+			continue
+		}
+
+		columns := make([]string, 0, len(planned.Changes))
+		for _, change := range planned.Changes {
+			columns = append(columns, change.Column)
+		}
+		report.Syncs = append(report.Syncs, planned)
+		report.Actions = append(report.Actions, reconcile.Action{
+			Type:   reconcile.ActionSyncDB,
+			Key:    strconv.Itoa(row.ID),
+			Reason: fmt.Sprintf("commerce columns %v differ from gamedata of sprite %d", columns, spriteID),
+			Fields: columns,
+			GDItem: updates,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", ItemsTable, err)
+	}
+	return report, nil
+}
+
+// offerChange compares the current value of a commerce column with the gamedata
+// field it mirrors, returning the change and the value to write when they differ.
+// Flags are written as "1" or "0", which suits both tinyint and enum('0','1') columns.
+func offerChange(field, column string, current any, gd furnitureAdp.GDItem) (OfferChange, any, bool) {
+	var target any
+	switch field {
+	case furnitureAdp.OfferFieldID:
+		target = gd.OfferID
+	case furnitureAdp.OfferFieldRentID:
+		target = gd.RentOfferID
+	case furnitureAdp.OfferFieldBuyout:
+		target = flagValue(gd.Buyout)
+	case furnitureAdp.OfferFieldRentBuyout:
+		target = flagValue(gd.RentBuyout)
+	}
+
+	var currentValue string
+	switch v := current.(type) {
+	case nil:
+	case bool:
+		currentValue = flagValue(v)
+	default:
+		currentValue = utils.ToString(v)
+	}
+	if currentValue == utils.ToString(target) {
+		return OfferChange{}, nil, false
+	}
+
+	return OfferChange{
+		Field:   field,
+		Column:  column,
+		Current: currentValue,
+		Target:  utils.ToString(target),
+	}, target, true
+}
+
+// flagValue returns the "1" or "0" written for a commerce flag.
+func flagValue(flag bool) string {
+	if flag {
+		return "1"
+	}
+	return "0"
+}
@@ -0,0 +1,101 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"asset-manager/core/reconcile"
+	furnitureAdp "asset-manager/feature/furniture/reconcile"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// setupOffersDB creates Arcturus catalog_items with commerce columns:
+//   - furniture 1 chair (sprite 100), 2 table (sprite 200), 3 lamp (sprite 300, no gamedata)
+//   - offer 10 sells 1 in sync; offer 11 sells 2 with a stale offer_id and have_offer;
+//     offer 12 bundles 1 and 2; offer 13 sells 3
+func setupOffersDB(t *testing.T, commerceColumns string) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.Exec(`CREATE TABLE items_base (id INTEGER PRIMARY KEY, sprite_id INTEGER, item_name VARCHAR(70))`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE catalog_items (id INTEGER PRIMARY KEY, page_id INTEGER, item_ids VARCHAR(666), catalog_name VARCHAR(100)`+commerceColumns+`)`).Error)
+
+	require.NoError(t, db.Exec(`INSERT INTO items_base (id, sprite_id, item_name) VALUES (1, 100, 'chair'), (2, 200, 'table'), (3, 300, 'lamp')`).Error)
+	require.NoError(t, db.Exec(`INSERT INTO catalog_items (id, page_id, item_ids, catalog_name) VALUES
+		(10, 1, '1', 'chair'),
+		(11, 1, '2', 'table'),
+		(12, 1, '1;2', 'bundle'),
+		(13, 1, '3', 'lamp')`).Error)
+	return db
+}
+
+// offersGamedata returns gamedata entries for the chair and the table.
+func offersGamedata() map[string]reconcile.GDItem {
+	return map[string]reconcile.GDItem{
+		"100": furnitureAdp.GDItem{ID: 100, ClassName: "chair", OfferID: 100, Buyout: true},
+		"200": furnitureAdp.GDItem{ID: 200, ClassName: "table", OfferID: 201, Buyout: false},
+	}
+}
+
+// TestPlanOffers tests that single-item offers get the offer ID and buyout flag of
+// their furniture's gamedata entry.
+func TestPlanOffers(t *testing.T) {
+	db := setupOffersDB(t, `, offer_id INTEGER, have_offer VARCHAR(1)`)
+	require.NoError(t, db.Exec(`UPDATE catalog_items SET offer_id = 100, have_offer = '1' WHERE id = 10`).Error)
+	require.NoError(t, db.Exec(`UPDATE catalog_items SET offer_id = -1, have_offer = '1' WHERE id = 11`).Error)
+	ctx := context.Background()
+
+	report, err := PlanOffers(ctx, db, "arcturus", offersGamedata())
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.Checked)
+	assert.Equal(t, []string{"offer_id", "have_offer"}, report.Columns)
+	// Arcturus has no rent columns
+	assert.Equal(t, []string{furnitureAdp.OfferFieldRentID, furnitureAdp.OfferFieldRentBuyout}, report.Skipped)
+	assert.Equal(t, []OfferSync{{
+		OfferID:     11,
+		PageID:      1,
+		CatalogName: "table",
+		ItemID:      2,
+		SpriteID:    200,
+		Changes: []OfferChange{
+			{Field: furnitureAdp.OfferFieldID, Column: "offer_id", Current: "-1", Target: "201"},
+			{Field: furnitureAdp.OfferFieldBuyout, Column: "have_offer", Current: "1", Target: "0"},
+		},
+	}}, report.Syncs)
+	require.Len(t, report.Actions, 1)
+	assert.Equal(t, reconcile.ActionSyncDB, report.Actions[0].Type)
+	assert.Equal(t, "11", report.Actions[0].Key)
+	assert.Equal(t, []string{"offer_id", "have_offer"}, report.Actions[0].Fields)
+
+	require.NoError(t, NewAdapter(db, "arcturus").SyncDBBatch(ctx, report.Actions))
+
+	report, err = PlanOffers(ctx, db, "arcturus", offersGamedata())
+	require.NoError(t, err)
+	assert.Empty(t, report.Syncs)
+	assert.Empty(t, report.Actions)
+}
+
+// TestPlanOffers_MissingColumns tests that mapped columns catalog_items lacks are
+// skipped instead of failing the sync.
+func TestPlanOffers_MissingColumns(t *testing.T) {
+	db := setupOffersDB(t, `, have_offer VARCHAR(1)`)
+
+	report, err := PlanOffers(context.Background(), db, "arcturus", offersGamedata())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"have_offer"}, report.Columns)
+	assert.Contains(t, report.Skipped, furnitureAdp.OfferFieldID)
+	require.Len(t, report.Syncs, 2)
+	assert.Equal(t, []OfferChange{{Field: furnitureAdp.OfferFieldBuyout, Column: "have_offer", Current: "", Target: "1"}}, report.Syncs[0].Changes)
+
+	// Without any column there is nothing to compare
+	require.NoError(t, db.Exec(`ALTER TABLE catalog_items DROP COLUMN have_offer`).Error)
+	report, err = PlanOffers(context.Background(), db, "arcturus", offersGamedata())
+	require.NoError(t, err)
+	assert.Empty(t, report.Columns)
+	assert.Len(t, report.Skipped, 4)
+	assert.Zero(t, report.Checked)
+}
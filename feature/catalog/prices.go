@@ -0,0 +1,159 @@
+package catalog
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+
+	"asset-manager/core/reconcile"
+
+	"gorm.io/gorm"
+)
+
+// priceColumns lists the catalog_items price columns the price check reads, in report
+// order. Arcturus has cost_credits and cost_points, PlusEMU and Comet cost_credits,
+// cost_pixels and cost_diamonds.
+var priceColumns = []string{"cost_credits", "cost_points", "cost_pixels", "cost_diamonds"}
+
+// Price problem kinds.
+const (
+	// PriceFree marks an offer whose every price is zero.
+	PriceFree = "free"
+	// PriceNegative marks a negative price.
+	PriceNegative = "negative"
+	// PriceTooHigh marks a price above its configured bound.
+	PriceTooHigh = "too_high"
+)
+
+// defaultPriceBounds are the bounds used until SetPriceBounds is called, matching the
+// RECONCILE_CATALOG_PRICES defaults.
+var defaultPriceBounds = reconcile.PriceBounds{MaxCredits: 100000, MaxPoints: 100000}
+
+// priceBounds holds the process-wide bounds of the HTTP price check.
+var priceBounds = struct {
+	sync.RWMutex
+	bounds reconcile.PriceBounds
+}{bounds: defaultPriceBounds}
+
+// SetPriceBounds sets the bounds the service's price check applies.
+func SetPriceBounds(bounds reconcile.PriceBounds) {
+	priceBounds.Lock()
+	defer priceBounds.Unlock()
+	priceBounds.bounds = bounds
+}
+
+// PriceBounds returns the bounds set by SetPriceBounds.
+func PriceBounds() reconcile.PriceBounds {
+	priceBounds.RLock()
+	defer priceBounds.RUnlock()
+	return priceBounds.bounds
+}
+
+// PriceIssue is an offer price that is free, negative or above its bound.
+type PriceIssue struct {
+	OfferID     int    `json:"offer_id"`
+	PageID      int    `json:"page_id"`
+	CatalogName string `json:"catalog_name"`
+	// Kind is PriceFree, PriceNegative or PriceTooHigh.
+	Kind string `json:"kind"`
+	// Column is the offending price column; empty for free offers.
+	Column string `json:"column,omitempty"`
+	// Price is the value of Column.
+	Price int64 `json:"price"`
+	// Max is the bound Price exceeds, for PriceTooHigh.
+	Max int `json:"max,omitempty"`
+}
+
+// PriceReport contains the results of the catalog price check.
+type PriceReport struct {
+	// Checked counts the offers read.
+	Checked int `json:"checked"`
+	// Columns lists the price columns found in catalog_items.
+	Columns []string `json:"columns"`
+	// Bounds are the bounds the prices were checked against.
+	Bounds reconcile.PriceBounds `json:"bounds"`
+	// Free, Negative and TooHigh count the issues of each kind.
+	Free     int `json:"free"`
+	Negative int `json:"negative"`
+	TooHigh  int `json:"too_high"`
+	// Issues lists every problem found, by offer ID.
+	Issues []PriceIssue `json:"issues"`
+}
+
+// CheckPrices reports offers sold for nothing, at a negative price or above bounds,
+// typical of botched catalog imports. Every price column catalog_items has is read;
+// NULL prices count as zero. cost_credits is held to bounds.MaxCredits and the other
+// columns to bounds.MaxPoints. The check is report only.
+func CheckPrices(ctx context.Context, db *gorm.DB, bounds reconcile.PriceBounds) (*PriceReport, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database connection is nil")
+	}
+
+	report := &PriceReport{Columns: make([]string, 0), Bounds: bounds, Issues: make([]PriceIssue, 0)}
+	for _, col := range priceColumns {
+		if db.Migrator().HasColumn(ItemsTable, col) {
+			report.Columns = append(report.Columns, col)
+		}
+	}
+	if len(report.Columns) == 0 {
+		return report, nil
+	}
+
+	selected := "id, page_id, " + catalogNameColumn
+	for _, col := range report.Columns {
+		selected += ", " + col
+	}
+	rows, err := db.WithContext(ctx).Table(ItemsTable).Select(selected).Order("id").Rows()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", ItemsTable, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row offerRow
+		prices := make([]sql.NullInt64, len(report.Columns))
+		dest := []any{&row.ID, &row.PageID, &row.CatalogName}
+		for i := range prices {
+			dest = append(dest, &prices[i])
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("failed to scan %s: %w", ItemsTable, err)
+		}
+		report.Checked++
+
+		issue := PriceIssue{OfferID: row.ID, PageID: int(row.PageID.Int64), CatalogName: row.CatalogName.String}
+		free := true
+		for i, col := range report.Columns {
+			price := prices[i].Int64
+			if price != 0 {
+				free = false
+			}
+			limit := bounds.MaxPoints
+			if col == "cost_credits" {
+				limit = bounds.MaxCredits
+			}
+
+			switch {
+			case price < 0:
+				issue.Kind, issue.Column, issue.Price, issue.Max = PriceNegative, col, price, 0
+				report.Negative++
+			case limit > 0 && price > int64(limit):
+				issue.Kind, issue.Column, issue.Price, issue.Max = PriceTooHigh, col, price, limit
+				report.TooHigh++
+			default:
+				continue
+			}
+			report.Issues = append(report.Issues, issue)
+		}
+		if free {
+			issue.Kind, issue.Column, issue.Price, issue.Max = PriceFree, "", 0, 0
+			report.Free++
+			report.Issues = append(report.Issues, issue)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", ItemsTable, err)
+	}
+	return report, nil
+}
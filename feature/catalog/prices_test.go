@@ -0,0 +1,71 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"asset-manager/core/reconcile"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// setupPricesDB creates an Arcturus catalog_items table with prices:
+//   - offer 10 costs 5 credits; offer 11 is free; offer 12 costs -3 points;
+//     offer 13 costs 250000 credits; offer 14 has no prices set
+func setupPricesDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.Exec(`CREATE TABLE catalog_items (id INTEGER PRIMARY KEY, page_id INTEGER, item_ids VARCHAR(666), catalog_name VARCHAR(100), cost_credits INTEGER, cost_points INTEGER)`).Error)
+	require.NoError(t, db.Exec(`INSERT INTO catalog_items (id, page_id, item_ids, catalog_name, cost_credits, cost_points) VALUES
+		(10, 1, '1', 'chair', 5, 0),
+		(11, 1, '2', 'table', 0, 0),
+		(12, 2, '3', 'lamp', 10, -3),
+		(13, 2, '4', 'throne', 250000, 0),
+		(14, 3, '5', 'rug', NULL, NULL)`).Error)
+	return db
+}
+
+// TestCheckPrices tests that free, negative and out-of-bounds prices are reported.
+func TestCheckPrices(t *testing.T) {
+	db := setupPricesDB(t)
+	bounds := reconcile.PriceBounds{MaxCredits: 100000, MaxPoints: 100000}
+
+	report, err := CheckPrices(context.Background(), db, bounds)
+	require.NoError(t, err)
+	assert.Equal(t, 5, report.Checked)
+	assert.Equal(t, []string{"cost_credits", "cost_points"}, report.Columns)
+	assert.Equal(t, bounds, report.Bounds)
+	assert.Equal(t, 2, report.Free)
+	assert.Equal(t, 1, report.Negative)
+	assert.Equal(t, 1, report.TooHigh)
+	assert.Equal(t, []PriceIssue{
+		{OfferID: 11, PageID: 1, CatalogName: "table", Kind: PriceFree},
+		{OfferID: 12, PageID: 2, CatalogName: "lamp", Kind: PriceNegative, Column: "cost_points", Price: -3},
+		{OfferID: 13, PageID: 2, CatalogName: "throne", Kind: PriceTooHigh, Column: "cost_credits", Price: 250000, Max: 100000},
+		{OfferID: 14, PageID: 3, CatalogName: "rug", Kind: PriceFree},
+	}, report.Issues)
+
+	// A zero bound disables the upper check
+	report, err = CheckPrices(context.Background(), db, reconcile.PriceBounds{})
+	require.NoError(t, err)
+	assert.Zero(t, report.TooHigh)
+	assert.Len(t, report.Issues, 3)
+}
+
+// TestCheckPrices_NoPriceColumns tests that a catalog without price columns checks nothing.
+func TestCheckPrices_NoPriceColumns(t *testing.T) {
+	db := setupCatalogDB(t)
+
+	report, err := CheckPrices(context.Background(), db, PriceBounds())
+	require.NoError(t, err)
+	assert.Zero(t, report.Checked)
+	assert.Empty(t, report.Columns)
+	assert.Empty(t, report.Issues)
+
+	_, err = CheckPrices(context.Background(), nil, PriceBounds())
+	assert.Error(t, err)
+}
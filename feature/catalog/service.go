@@ -0,0 +1,49 @@
+package catalog
+
+import (
+	"context"
+
+	"asset-manager/core/reconcile"
+	"asset-manager/core/storage"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// Service runs catalog reconciliations.
+type Service struct {
+	client   storage.Client
+	buckets  storage.Buckets
+	db       *gorm.DB
+	emulator string
+	logger   *zap.Logger
+}
+
+// NewService creates a new catalog service. The storage client reads the furniture
+// gamedata for the offer sync; reconciliations only check it is reachable, like every
+// other engine run.
+func NewService(client storage.Client, buckets storage.Buckets, db *gorm.DB, emulator string, logger *zap.Logger) *Service {
+	return &Service{client: client, buckets: buckets, db: db, emulator: emulator, logger: logger}
+}
+
+// Reconcile reports catalog issues without planning actions.
+func (s *Service) Reconcile(ctx context.Context) (*Report, error) {
+	spec := NewSpec(NewAdapter(s.db, s.emulator), s.emulator)
+	return Reconcile(ctx, spec, s.db, s.client, s.buckets.Assets, reconcile.ReconcileOptions{})
+}
+
+// PreviewOffers reports the offers whose commerce columns differ from gamedata,
+// without changing them.
+func (s *Service) PreviewOffers(ctx context.Context) (*OfferReport, error) {
+	gamedata, err := LoadGamedata(ctx, s.client, s.buckets.Gamedata)
+	if err != nil {
+		return nil, err
+	}
+	return PlanOffers(ctx, reconcile.ReadDB(s.db), s.emulator, gamedata)
+}
+
+// CheckPrices reports free, negative and out-of-bounds offer prices, against the
+// bounds set by SetPriceBounds.
+func (s *Service) CheckPrices(ctx context.Context) (*PriceReport, error) {
+	return CheckPrices(ctx, reconcile.ReadDB(s.db), PriceBounds())
+}
@@ -0,0 +1,60 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"asset-manager/core/reconcile"
+	"asset-manager/core/storage"
+
+	"gorm.io/gorm"
+)
+
+// SourcePages is the source reporting whether the page of an offer exists.
+const SourcePages = PagesTable
+
+// pageSource reports the offers whose page exists in catalog_pages. Offers on a
+// missing page are reported, never purged: the page may simply not be created yet.
+type pageSource struct{}
+
+// Name returns the source name.
+func (pageSource) Name() string {
+	return SourcePages
+}
+
+// LoadIndex loads the offers on an existing page, keyed by offer ID.
+func (pageSource) LoadIndex(ctx context.Context, db *gorm.DB, client storage.Client, bucket string) (map[string]any, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database connection is nil")
+	}
+	var ids []int
+	err := db.WithContext(ctx).
+		Table(ItemsTable).
+		Joins("JOIN "+PagesTable+" ON "+PagesTable+".id = "+ItemsTable+".page_id").
+		Pluck(ItemsTable+".id", &ids).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", PagesTable, err)
+	}
+	index := make(map[string]any, len(ids))
+	for _, id := range ids {
+		index[strconv.Itoa(id)] = struct{}{}
+	}
+	return index, nil
+}
+
+// NewSpec builds the reconcile spec of the catalog. Offers are always read fresh:
+// the catalog is small and edited by hand in the emulator database.
+func NewSpec(adapter *Adapter, emulator string) *reconcile.Spec {
+	return &reconcile.Spec{
+		Adapter:       adapter,
+		ServerProfile: emulator,
+		Sources:       []reconcile.Source{pageSource{}},
+	}
+}
+
+// Register makes the catalog spec available to cross-adapter operations such as
+// the combined health score and reconcile all.
+func Register(db *gorm.DB, emulator string) {
+	reconcile.RegisterSpec(NewSpec(NewAdapter(db, emulator), emulator))
+}
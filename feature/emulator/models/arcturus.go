@@ -33,3 +33,40 @@ type ArcturusItemsBase struct {
 func (ArcturusItemsBase) TableName() string {
 	return "items_base"
 }
+
+// ArcturusMSItemsBase is items_base as shipped by Arcturus Morningstar 4.x, which
+// stores the flags as enum('0','1'), no longer bounds interaction_type to 500
+// characters and keeps customparams in a text column.
+type ArcturusMSItemsBase struct {
+	ID                    int     `gorm:"primaryKey;column:id"`
+	SpriteID              int     `gorm:"column:sprite_id;default:0"`
+	ItemName              string  `gorm:"column:item_name;type:varchar(70);default:0"`
+	PublicName            string  `gorm:"column:public_name;type:varchar(56);default:0"`
+	Width                 int     `gorm:"column:width;default:1"`
+	Length                int     `gorm:"column:length;default:1"`
+	StackHeight           float64 `gorm:"column:stack_height;type:double(4,2);default:0.00"`
+	AllowStack            string  `gorm:"column:allow_stack;type:enum('0','1');default:1"`
+	AllowSit              string  `gorm:"column:allow_sit;type:enum('0','1');default:0"`
+	AllowLay              string  `gorm:"column:allow_lay;type:enum('0','1');default:0"`
+	AllowWalk             string  `gorm:"column:allow_walk;type:enum('0','1');default:0"`
+	AllowGift             string  `gorm:"column:allow_gift;type:enum('0','1');default:1"`
+	AllowTrade            string  `gorm:"column:allow_trade;type:enum('0','1');default:1"`
+	AllowRecycle          string  `gorm:"column:allow_recycle;type:enum('0','1');default:0"`
+	AllowMarketplaceSell  string  `gorm:"column:allow_marketplace_sell;type:enum('0','1');default:0"`
+	AllowInventoryStack   string  `gorm:"column:allow_inventory_stack;type:enum('0','1');default:1"`
+	Type                  string  `gorm:"column:type;type:varchar(3);default:s"`
+	InteractionType       string  `gorm:"column:interaction_type;type:varchar;default:default"`
+	InteractionModesCount int     `gorm:"column:interaction_modes_count;default:1"`
+	VendingIDs            string  `gorm:"column:vending_ids;type:varchar(255);default:0"`
+	MultiHeight           string  `gorm:"column:multiheight;type:varchar(50);default:0"`
+	CustomParams          *string `gorm:"column:customparams;type:text;default:NULL"` // Any text size
+	EffectIDMale          int     `gorm:"column:effect_id_male;default:0"`
+	EffectIDFemale        int     `gorm:"column:effect_id_female;default:0"`
+	ClothingOnWalk        *string `gorm:"column:clothing_on_walk;type:varchar(255);default:NULL"`
+	PageID                *string `gorm:"column:page_id;type:varchar(250);default:NULL"`
+	Rare                  string  `gorm:"column:rare;type:enum('0','1','2','3','4');default:0"`
+}
+
+func (ArcturusMSItemsBase) TableName() string {
+	return "items_base"
+}
@@ -6,7 +6,9 @@
 //
 // # Supported Emulators
 //
-//   - Arcturus: Uses 'items_base' table.
+//   - Arcturus: Uses 'items_base' table with tinyint(1) flags.
+//   - Arcturus-MS: Uses the Morningstar 4.x 'items_base' table, with enum('0','1') flags
+//     and unbounded interaction_type and customparams columns.
 //   - Comet: Uses 'furniture' table.
 //   - Plus: Uses 'furniture' table.
 //
@@ -13,6 +13,7 @@ func TestTableNames(t *testing.T) {
 		wantTable string
 	}{
 		{"Arcturus", ArcturusItemsBase{}, "items_base"},
+		{"ArcturusMS", ArcturusMSItemsBase{}, "items_base"},
 		{"Comet", CometFurniture{}, "furniture"},
 		{"Plus", PlusFurniture{}, "furniture"},
 	}
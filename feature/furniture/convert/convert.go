@@ -0,0 +1,195 @@
+package convert
+
+import (
+	"fmt"
+
+	"asset-manager/core/reconcile"
+	"asset-manager/feature/furniture/models"
+)
+
+// ToReport converts reconcile results to the aggregate furniture Report.
+// Timing fields (GeneratedAt, ExecutionTime) are left for the caller to fill.
+func ToReport(results []reconcile.ReconcileResult) *models.Report {
+	var missingAssets []string
+	var unregisteredAssets []string
+	var malformedAssets []string
+	var parameterMismatches []string
+	var fieldWarnings []string
+	var flappingItems []string
+	var triage map[string]reconcile.Triage
+
+	totalExpected := 0
+	totalFound := 0
+
+	for _, r := range results {
+		// Count expected items (in gamedata)
+		if r.GamedataPresent {
+			totalExpected++
+		}
+
+		// Count found items (in storage)
+		if r.StoragePresent {
+			totalFound++
+		}
+
+		// Missing assets: in gamedata but not in storage, unless reserved by the emulator
+		if r.GamedataPresent && !r.StoragePresent && !r.ExpectsAbsent(reconcile.SourceStorage) {
+			missingAssets = append(missingAssets, assetFilename(r))
+		}
+
+		// Unregistered assets: in storage but not in gamedata
+		if r.StoragePresent && !r.GamedataPresent {
+			unregisteredAssets = append(unregisteredAssets, assetFilename(r))
+		}
+
+		// Parameter mismatches
+		for _, mismatch := range r.Mismatch {
+			parameterMismatches = append(parameterMismatches, fmt.Sprintf("ID %s: %s", r.ID, mismatch))
+		}
+		for _, warning := range r.Warnings {
+			fieldWarnings = append(fieldWarnings, fmt.Sprintf("ID %s: %s", r.ID, warning))
+		}
+
+		if r.Flapping {
+			flappingItems = append(flappingItems, fmt.Sprintf("ID %s: %d transitions", r.ID, r.Transitions))
+		}
+
+		if r.Triage != nil {
+			if triage == nil {
+				triage = make(map[string]reconcile.Triage)
+			}
+			triage[r.ID] = *r.Triage
+		}
+	}
+
+	return &models.Report{
+		TotalExpected:       totalExpected,
+		TotalFound:          totalFound,
+		MissingAssets:       missingAssets,
+		UnregisteredAssets:  unregisteredAssets,
+		MalformedAssets:     malformedAssets,
+		ParameterMismatches: parameterMismatches,
+		FieldWarnings:       fieldWarnings,
+		FlappingItems:       flappingItems,
+		Triage:              triage,
+		Summary:             reconcile.Summarize(results),
+	}
+}
+
+// ToIgnored converts the ignored results of a plan to report entries.
+func ToIgnored(ignored []reconcile.IgnoredResult) []models.IgnoredItem {
+	if len(ignored) == 0 {
+		return nil
+	}
+
+	items := make([]models.IgnoredItem, 0, len(ignored))
+	for _, r := range ignored {
+		items = append(items, models.IgnoredItem{
+			ID:        r.ID,
+			Name:      r.Name,
+			Reason:    r.Ignore.Reason,
+			ExpiresAt: r.Ignore.ExpiresAt,
+			Missing:   r.MissingSources(),
+			Mismatch:  r.Mismatch,
+		})
+	}
+	return items
+}
+
+// ToDetailReport converts a single reconcile result to a detail report.
+func ToDetailReport(result *reconcile.ReconcileResult) *models.FurnitureDetailReport {
+	report := &models.FurnitureDetailReport{
+		InFurniData:     result.GamedataPresent,
+		InDB:            result.DBPresent,
+		FileExists:      result.StoragePresent,
+		IntegrityStatus: "PASS",
+		Name:            result.Name,
+		Mismatches:      make([]string, 0),
+		Warnings:        result.Warnings,
+		NotComparable:   result.NotComparable,
+		ExpectedAbsent:  result.ExpectedAbsent,
+	}
+
+	// Try to parse ID as int
+	var id int
+	if _, err := fmt.Sscanf(result.ID, "%d", &id); err == nil {
+		report.ID = id
+	}
+
+	// Set classname and nitro file
+	if classname, ok := result.Metadata["classname"]; ok && classname != "" {
+		report.ClassName = classname
+		report.NitroFile = classname + ".nitro"
+	} else if result.Name != "" {
+		// Fallback for edge cases (though unlikely for furniture)
+		report.ClassName = result.Name
+		report.NitroFile = result.Name + ".nitro"
+	}
+
+	// Determine status
+	if !result.GamedataPresent {
+		report.Mismatches = append(report.Mismatches, "Missing in FurniData")
+		report.IntegrityStatus = "FAIL"
+	}
+	if !result.DBPresent && result.GamedataPresent {
+		report.Mismatches = append(report.Mismatches, "Missing in Database")
+		report.IntegrityStatus = "FAIL"
+	}
+	if !result.StoragePresent && !result.ExpectsAbsent(reconcile.SourceStorage) {
+		report.Mismatches = append(report.Mismatches, "Missing .nitro file in storage")
+		report.IntegrityStatus = "FAIL"
+	}
+
+	// Add field mismatches
+	report.Mismatches = append(report.Mismatches, result.Mismatch...)
+
+	if (len(report.Mismatches) > 0 || len(report.Warnings) > 0) && report.IntegrityStatus == "PASS" {
+		report.IntegrityStatus = "WARNING"
+	}
+
+	report.SuggestedActions = reconcile.SuggestActions(*result)
+	report.Triage = result.Triage
+
+	return report
+}
+
+// ToIssues converts reconcile results to the list of items with at least one problem.
+// Complete items without mismatches are omitted.
+func ToIssues(results []reconcile.ReconcileResult) []models.FurnitureIssue {
+	issues := make([]models.FurnitureIssue, 0)
+	for _, r := range results {
+		storageMissing := !r.StoragePresent && !r.ExpectsAbsent(reconcile.SourceStorage)
+		hasIssue := !r.GamedataPresent || !r.DBPresent || storageMissing || len(r.Mismatch) > 0 || r.Flapping
+		if !hasIssue {
+			continue
+		}
+
+		// Initialize empty mismatch array to prevent null in JSON
+		mismatchList := r.Mismatch
+		if mismatchList == nil {
+			mismatchList = []string{}
+		}
+
+		issues = append(issues, models.FurnitureIssue{
+			ID:              r.ID,
+			Name:            r.Name,
+			GamedataMissing: !r.GamedataPresent,
+			StorageMissing:  storageMissing,
+			DBMissing:       !r.DBPresent,
+			Mismatch:        mismatchList,
+			Warnings:        r.Warnings,
+			Flapping:        r.Flapping,
+			Transitions:     r.Transitions,
+			Triage:          r.Triage,
+		})
+	}
+	return issues
+}
+
+// assetFilename returns the storage filename reported for a result.
+func assetFilename(r reconcile.ReconcileResult) string {
+	if r.Name == "" {
+		return r.ID + ".nitro"
+	}
+	return r.Name + ".nitro"
+}
@@ -0,0 +1,108 @@
+package convert
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"asset-manager/core/reconcile"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+// fixtureResults covers every presence combination plus a field mismatch.
+func fixtureResults() []reconcile.ReconcileResult {
+	return []reconcile.ReconcileResult{
+		{ID: "1", Name: "chair", DBPresent: true, StoragePresent: true, GamedataPresent: true, Mismatch: []string{}, Metadata: map[string]string{"classname": "chair"}},
+		{ID: "2", Name: "table", DBPresent: true, StoragePresent: false, GamedataPresent: true, Mismatch: []string{}, Metadata: map[string]string{"classname": "table"}},
+		{ID: "3", Name: "lamp", DBPresent: false, StoragePresent: true, GamedataPresent: true, Mismatch: []string{}, Metadata: map[string]string{"classname": "lamp"}},
+		{ID: "4", Name: "", DBPresent: false, StoragePresent: true, GamedataPresent: false, Mismatch: []string{}},
+		{ID: "5", Name: "sofa", DBPresent: true, StoragePresent: true, GamedataPresent: true, Mismatch: []string{"width: gd=2 db=1"}, Metadata: map[string]string{"classname": "sofa"}},
+		{ID: "6", Name: "ghost", DBPresent: true, StoragePresent: false, GamedataPresent: false, Mismatch: nil, Metadata: map[string]string{"classname": "ghost"}},
+		{ID: "7", Name: "rug", DBPresent: true, StoragePresent: true, GamedataPresent: true, Mismatch: []string{}, Metadata: map[string]string{"classname": "rug"}, Transitions: 4, Flapping: true},
+	}
+}
+
+// assertGolden compares v against testdata/<name>.golden.json, rewriting it with -update.
+func assertGolden(t *testing.T, name string, v any) {
+	t.Helper()
+	got, err := json.MarshalIndent(v, "", "  ")
+	assert.NoError(t, err)
+	got = append(got, '\n')
+
+	path := filepath.Join("testdata", name+".golden.json")
+	if *update {
+		assert.NoError(t, os.WriteFile(path, got, 0644))
+	}
+
+	want, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, string(want), string(got))
+}
+
+// TestToReport_Golden tests the aggregate report against the golden file.
+func TestToReport_Golden(t *testing.T) {
+	assertGolden(t, "report", ToReport(fixtureResults()))
+}
+
+// TestToIssues_Golden tests the CLI issue list against the golden file.
+func TestToIssues_Golden(t *testing.T) {
+	assertGolden(t, "issues", ToIssues(fixtureResults()))
+}
+
+// TestToDetailReport_Golden tests detail reports for each fixture item against the golden file.
+func TestToDetailReport_Golden(t *testing.T) {
+	results := fixtureResults()
+	details := make([]any, 0, len(results))
+	for i := range results {
+		details = append(details, ToDetailReport(&results[i]))
+	}
+	assertGolden(t, "details", details)
+}
+
+// TestSurfacesAgree tests that the report summary and the issue list agree on counts.
+func TestSurfacesAgree(t *testing.T) {
+	results := fixtureResults()
+	report := ToReport(results)
+	issues := ToIssues(results)
+
+	var dbMissing, storageMissing, gamedataMissing, mismatches int
+	for _, issue := range issues {
+		if issue.DBMissing {
+			dbMissing++
+		}
+		if issue.StorageMissing {
+			storageMissing++
+		}
+		if issue.GamedataMissing {
+			gamedataMissing++
+		}
+		if len(issue.Mismatch) > 0 {
+			mismatches++
+		}
+	}
+
+	assert.Equal(t, report.Summary.MissingDB, dbMissing)
+	assert.Equal(t, report.Summary.MissingStorage, storageMissing)
+	assert.Equal(t, report.Summary.MissingGamedata, gamedataMissing)
+	assert.Equal(t, report.Summary.Mismatches, mismatches)
+	assert.Equal(t, len(report.MissingAssets), 1)
+}
+
+// TestToIgnored tests that ignored results keep their reason and problems.
+func TestToIgnored(t *testing.T) {
+	assert.Nil(t, ToIgnored(nil))
+
+	items := ToIgnored([]reconcile.IgnoredResult{{
+		ReconcileResult: reconcile.ReconcileResult{ID: "4", Name: "Lamp", GamedataPresent: true, DBPresent: true},
+		Ignore:          reconcile.IgnoreEntry{Key: "4", Reason: "custom item"},
+	}})
+	if assert.Len(t, items, 1) {
+		assert.Equal(t, "custom item", items[0].Reason)
+		assert.Equal(t, []string{"storage"}, items[0].Missing)
+	}
+}
@@ -0,0 +1,14 @@
+// Package convert is the single conversion layer between reconcile engine results
+// and the furniture report shapes exposed to users.
+//
+// Every surface (HTTP handlers, CLI output, JSON exports) converts through this
+// package so that the same underlying state always yields the same counts.
+//
+// # Conversions
+//
+//   - ToReport: Full scan results to the aggregate models.Report.
+//   - ToDetailReport: A single result to the models.FurnitureDetailReport.
+//   - ToIssues: Full scan results to the per-item issue list written by the CLI.
+//
+// Aggregate counts always come from reconcile.Summarize.
+package convert
@@ -20,5 +20,14 @@
 //
 // # HTTP Endpoints
 //
+//   - GET /furniture/search?q=thr : Find items by a partial or misspelled ID, classname or name,
+//     best match first, with their reconcile status. Runs over the cached indices.
+//   - GET /furniture/names/encoding : List public names that are the gamedata name garbled
+//     by HTML entities or double UTF-8 encoding. Repairs run from the CLI.
 //   - GET /furniture/:identifier : Get detailed status for a specific item (e.g. 'f_couch').
+//     The response includes suggested_actions (insert_db, fetch_storage, sync_db, move_storage) for repair.
+//   - POST /reconcile/furniture : Start a full reconciliation as a background job (see GET /jobs/:id).
+//   - GET /reconcile/furniture/stream : Run a full reconciliation, streaming progress as Server-Sent Events.
+//   - POST /reconcile/furniture/ignore : Exclude an item from reconcile plans.
+//   - POST /reconcile/furniture/triage : Attach triage state (acknowledged, assignee, note) to an item.
 package furniture
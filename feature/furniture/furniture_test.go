@@ -1,6 +1,7 @@
 package furniture
 
 import (
+	"asset-manager/core/storage"
 	"asset-manager/core/storage/mocks"
 	"testing"
 
@@ -35,7 +36,7 @@ func setupMockDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
 func TestLoader(t *testing.T) {
 	mockClient := new(mocks.Client)
 	logger := zap.NewNop()
-	feature := NewFeature(mockClient, "test-bucket", logger, nil, "")
+	feature := NewFeature(mockClient, storage.SingleBucket("test-bucket"), logger, nil, "")
 
 	assert.Equal(t, "furniture", feature.Name())
 	assert.True(t, feature.IsEnabled())
@@ -56,7 +57,7 @@ func TestService_GetFurnitureDetail(t *testing.T) {
 	// So we must expect the underlying calls or accept that it might fail/error,
 	// but we want to assert that the Service method delegates correctly.
 
-	svc := NewService(mockClient, "test-bucket", logger, db, "arcturus")
+	svc := NewService(mockClient, storage.SingleBucket("test-bucket"), logger, db, "arcturus")
 
 	// If we don't setup mocks, it will error, which is fine for coverage of the wiring.
 	// But let's try to make it return "Not Found" cleanly.
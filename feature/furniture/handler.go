@@ -1,7 +1,16 @@
 package furniture
 
 import (
+	"bufio"
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"asset-manager/core/jobs"
 	"asset-manager/core/logger"
+	"asset-manager/core/reconcile"
+	"asset-manager/feature/furniture/models"
 
 	"github.com/gofiber/fiber/v2"
 	"go.uber.org/zap"
@@ -20,12 +29,19 @@ func NewHandler(service *Service) *Handler {
 // RegisterRoutes registers the furniture routes.
 func (h *Handler) RegisterRoutes(app fiber.Router) {
 	group := app.Group("/furniture")
+	group.Get("/search", h.HandleSearchFurniture)
+	group.Get("/names/encoding", h.HandleNameEncoding)
 	group.Get("/:identifier", h.HandleGetFurnitureDetail)
+
+	app.Post("/reconcile/furniture", h.HandleStartReconcile)
+	app.Get("/reconcile/furniture/stream", h.HandleReconcileStream)
+	app.Post("/reconcile/furniture/ignore", h.HandleIgnoreFurniture)
+	app.Post("/reconcile/furniture/triage", h.HandleTriageFurniture)
 }
 
 // HandleGetFurnitureDetail returns a detailed report for a single furniture item.
 // @Summary Get Furniture Detail
-// @Description Get detailed integrity report for a specific furniture item.
+// @Description Get detailed integrity report for a specific furniture item, including suggested_actions for one-click repair.
 // @Tags furniture
 // @Accept json
 // @Produce json
@@ -47,3 +63,258 @@ func (h *Handler) HandleGetFurnitureDetail(c *fiber.Ctx) error {
 
 	return c.JSON(report)
 }
+
+// HandleNameEncoding reports furniture names garbled by HTML entities or double encoding.
+// @Summary Check Name Encoding
+// @Description Finds furniture whose public_name is its gamedata name garbled by HTML entities (&amp;) or a latin1/UTF-8 double encoding (CafÃ© for Café), using gamedata as the reference. Report only; repairs run from the CLI (furniture names --repair).
+// @Tags furniture
+// @Produce json
+// @Success 200 {object} models.NameEncodingReport "Name Encoding Report"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /furniture/names/encoding [get]
+func (h *Handler) HandleNameEncoding(c *fiber.Ctx) error {
+	l := logger.WithRayID(h.service.logger, c)
+
+	report, err := h.service.RepairNames(c.Context(), true)
+	if err != nil {
+		l.Error("Name encoding check failed", zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	if len(report.Repairs) > 0 {
+		l.Warn("Garbled furniture names detected", zap.Int("names", len(report.Repairs)))
+	}
+
+	return c.JSON(report)
+}
+
+const (
+	// defaultSearchLimit is the number of search matches returned without a limit.
+	defaultSearchLimit = 20
+
+	// maxSearchLimit caps the limit a search request may ask for.
+	maxSearchLimit = 100
+)
+
+// HandleSearchFurniture finds furniture by a partial or misspelled ID, classname or name.
+// @Summary Search Furniture
+// @Description Search furniture by ID, classname or name over the cached reconcile indices. Matching ignores case and accepts exact, prefix, substring and near-miss (typo) matches; each match carries its score, the term it matched and its reconcile status. Use the matched ID with GET /furniture/{identifier} for the full report.
+// @Tags furniture
+// @Produce json
+// @Param q query string true "Search query (e.g. 'thr')"
+// @Param limit query int false "Maximum number of matches (default 20, max 100)"
+// @Success 200 {array} reconcile.SearchMatch "Matches, best first"
+// @Failure 400 {object} map[string]string "Missing query or invalid limit"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /furniture/search [get]
+func (h *Handler) HandleSearchFurniture(c *fiber.Ctx) error {
+	l := logger.WithRayID(h.service.logger, c)
+
+	query := c.Query("q")
+	if query == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "q is required",
+		})
+	}
+
+	limit := defaultSearchLimit
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "limit must be a positive integer",
+			})
+		}
+		limit = min(parsed, maxSearchLimit)
+	}
+
+	matches, err := h.service.SearchFurniture(c.Context(), query, limit)
+	if err != nil {
+		l.Error("Furniture search failed", zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(matches)
+}
+
+// HandleStartReconcile starts a full furniture reconciliation as a background job.
+// @Summary Start Furniture Reconciliation
+// @Description Start a full furniture reconciliation in the background and return its job at once, so large hotels do not hit proxy timeouts. Poll GET /jobs/{id} for its progress; once it succeeded, GET /jobs/{id}/result returns the furniture report of GET /integrity/furniture. The run is read-only.
+// @Tags furniture
+// @Produce json
+// @Success 202 {object} jobs.Job "Job started"
+// @Failure 503 {object} map[string]string "Background jobs unavailable"
+// @Router /reconcile/furniture [post]
+func (h *Handler) HandleStartReconcile(c *fiber.Ctx) error {
+	l := logger.WithRayID(h.service.logger, c)
+
+	manager := jobs.Current()
+	if manager == nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+			"error": jobs.ErrNoManager.Error(),
+		})
+	}
+
+	job := h.service.StartReconcile(manager)
+	l.Info("Furniture reconciliation job started", zap.String("job", job.ID))
+	c.Set(fiber.HeaderLocation, "/jobs/"+job.ID)
+	return c.Status(fiber.StatusAccepted).JSON(job)
+}
+
+// HandleReconcileStream runs a full furniture reconciliation and streams its progress.
+// @Summary Stream Furniture Reconciliation
+// @Description Run a full furniture reconciliation and stream its progress as Server-Sent Events, for a live progress bar. Events: indexes (loading the DB, gamedata and storage indices), union (total keys), results (every 1000 items processed and the last one) and summary (the counts of the run). A failed run ends with an error event. Browsers' EventSource cannot send X-API-Key, so read the stream with fetch.
+// @Tags furniture
+// @Produce text/event-stream
+// @Success 200 {object} reconcile.ProgressEvent "Event stream"
+// @Router /reconcile/furniture/stream [get]
+func (h *Handler) HandleReconcileStream(c *fiber.Ctx) error {
+	// The run outlives the handler, which returns before the body is streamed
+	ctx, cancel := context.WithCancel(context.Background())
+	ctx, runID := reconcile.StartRun(ctx)
+	c.Set(reconcile.RunIDHeader, runID)
+	l := logger.WithRayID(h.service.logger, c).With(zap.String("run_id", runID))
+	l.Info("Starting furniture reconciliation stream")
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	// Reverse proxies such as nginx would otherwise hold events back
+	c.Set("X-Accel-Buffering", "no")
+
+	events := make(chan sseEvent, 16)
+	send := func(event sseEvent) {
+		select {
+		case events <- event:
+		case <-ctx.Done():
+		}
+	}
+
+	go func() {
+		defer close(events)
+		start := time.Now()
+		_, err := h.service.ReconcileWithProgress(ctx, func(e reconcile.ProgressEvent) {
+			send(sseEvent{name: string(e.Stage), data: e})
+		})
+		if err != nil {
+			l.Error("Furniture reconciliation stream failed", zap.Error(err))
+			send(sseEvent{name: "error", data: fiber.Map{"error": err.Error()}})
+			return
+		}
+		l.Info("Furniture reconciliation stream finished", zap.Duration("duration", time.Since(start)))
+	}()
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer cancel()
+		for event := range events {
+			if err := writeEvent(w, event); err != nil {
+				// The client went away: stop the run and let it finish
+				l.Warn("Furniture reconciliation stream closed", zap.Error(err))
+				cancel()
+				for range events {
+				}
+				return
+			}
+		}
+	})
+	return nil
+}
+
+// HandleIgnoreFurniture adds a furniture item to the persistent ignore list.
+// @Summary Ignore Furniture Item
+// @Description Persist an ignore for one furniture item, with a reason and optional expiry. Ignored items are left out of reconcile plans and listed under ignored_items in the furniture integrity report. Ignoring a key again replaces its ignore.
+// @Tags furniture
+// @Accept json
+// @Produce json
+// @Param request body models.IgnoreRequest true "Item to ignore"
+// @Success 201 {object} reconcile.IgnoreEntry "Stored ignore"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 503 {object} map[string]string "State store disabled"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /reconcile/furniture/ignore [post]
+func (h *Handler) HandleIgnoreFurniture(c *fiber.Ctx) error {
+	l := logger.WithRayID(h.service.logger, c)
+
+	var req models.IgnoreRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid request body: " + err.Error(),
+		})
+	}
+	if req.Key == "" || req.Reason == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "key and reason are required",
+		})
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "expires_at must be in the future",
+		})
+	}
+
+	entry, err := h.service.IgnoreItem(c.Context(), req)
+	if errors.Is(err, reconcile.ErrNoIgnoreStore) {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	if err != nil {
+		l.Error("Failed to ignore furniture item", zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	l.Info("Furniture item ignored", zap.String("key", entry.Key), zap.String("reason", entry.Reason))
+	return c.Status(fiber.StatusCreated).JSON(entry)
+}
+
+// HandleTriageFurniture records the triage state of a furniture item's issues.
+// @Summary Triage Furniture Issue
+// @Description Attach triage state (acknowledged, assigned_to, note) to a furniture item. It is merged into the furniture integrity report, the detail report and the CLI JSON export. A request with no field set besides key clears the triage.
+// @Tags furniture
+// @Accept json
+// @Produce json
+// @Param request body models.TriageRequest true "Triage state"
+// @Success 200 {object} reconcile.Triage "Stored triage"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 503 {object} map[string]string "State store disabled"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /reconcile/furniture/triage [post]
+func (h *Handler) HandleTriageFurniture(c *fiber.Ctx) error {
+	l := logger.WithRayID(h.service.logger, c)
+
+	var req models.TriageRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid request body: " + err.Error(),
+		})
+	}
+	if req.Key == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "key is required",
+		})
+	}
+
+	triage, err := h.service.TriageItem(c.Context(), req)
+	if errors.Is(err, reconcile.ErrNoTriageStore) {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	if err != nil {
+		l.Error("Failed to triage furniture item", zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	l.Info("Furniture item triaged",
+		zap.String("key", req.Key),
+		zap.Bool("acknowledged", triage.Acknowledged),
+		zap.String("assigned_to", triage.AssignedTo))
+	return c.JSON(triage)
+}
@@ -1,13 +1,26 @@
 package furniture
 
 import (
+	"asset-manager/core/jobs"
+	"asset-manager/core/json"
+	"asset-manager/core/reconcile"
+	"asset-manager/core/state"
+	"asset-manager/core/storage"
 	"asset-manager/core/storage/mocks"
+	"context"
+	"io"
 	"net/http/httptest"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/gofiber/fiber/v2"
+	"github.com/minio/minio-go/v7"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 )
 
@@ -21,7 +34,7 @@ func TestHandler_HandleGetFurnitureDetail(t *testing.T) {
 	mockClient := new(mocks.Client)
 	logger := zap.NewNop()
 	db, _ := setupMockDB(t)
-	svc := NewService(mockClient, "test-bucket", logger, db, "arcturus")
+	svc := NewService(mockClient, storage.SingleBucket("test-bucket"), logger, db, "arcturus")
 	handler := NewHandler(svc)
 
 	// Mock BucketExists which is called early in integrity check
@@ -36,3 +49,208 @@ func TestHandler_HandleGetFurnitureDetail(t *testing.T) {
 	// It should fail because BucketExists fails, returning 500
 	assert.Equal(t, 500, resp.StatusCode)
 }
+
+// TestHandler_HandleSearchFurniture tests query validation and that /furniture/search
+// is not taken for an identifier.
+func TestHandler_HandleSearchFurniture(t *testing.T) {
+	mockClient := new(mocks.Client)
+	db, _ := setupMockDB(t)
+	svc := NewService(mockClient, storage.SingleBucket("test-bucket"), zap.NewNop(), db, "arcturus")
+	app, _, _ := setupTestApp(NewHandler(svc))
+
+	get := func(target string) int {
+		resp, err := app.Test(httptest.NewRequest("GET", target, nil))
+		require.NoError(t, err)
+		return resp.StatusCode
+	}
+
+	assert.Equal(t, 400, get("/furniture/search"))
+	assert.Equal(t, 400, get("/furniture/search?q=thr&limit=0"))
+	assert.Equal(t, 400, get("/furniture/search?q=thr&limit=x"))
+
+	// Building the indices fails without the bucket
+	mockClient.On("BucketExists", mock.Anything, "test-bucket").Return(false, assert.AnError)
+	assert.Equal(t, 500, get("/furniture/search?q=thr"))
+}
+
+// TestHandler_HandleNameEncoding tests that /furniture/names/encoding is not taken for
+// an identifier and reports failures to load the indices.
+func TestHandler_HandleNameEncoding(t *testing.T) {
+	mockClient := new(mocks.Client)
+	db, _ := setupMockDB(t)
+	svc := NewService(mockClient, storage.SingleBucket("test-bucket"), zap.NewNop(), db, "arcturus")
+	app, _, _ := setupTestApp(NewHandler(svc))
+
+	mockClient.On("BucketExists", mock.Anything, "test-bucket").Return(false, assert.AnError)
+	resp, err := app.Test(httptest.NewRequest("GET", "/furniture/names/encoding", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 500, resp.StatusCode)
+
+	var body map[string]string
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Contains(t, body["error"], "failed to load furniture")
+}
+
+func TestHandler_HandleIgnoreFurniture(t *testing.T) {
+	svc := NewService(new(mocks.Client), storage.SingleBucket("test-bucket"), zap.NewNop(), nil, "arcturus")
+	app, _, _ := setupTestApp(NewHandler(svc))
+
+	post := func(body string) int {
+		req := httptest.NewRequest("POST", "/reconcile/furniture/ignore", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		return resp.StatusCode
+	}
+
+	// Without a state store ignores cannot be persisted
+	reconcile.SetIgnoreStore(nil)
+	assert.Equal(t, 503, post(`{"key": "1", "reason": "custom item"}`))
+
+	db, err := state.Open(state.Config{Path: filepath.Join(t.TempDir(), "state.db")})
+	require.NoError(t, err)
+	store, err := state.NewIgnoreStore(db)
+	require.NoError(t, err)
+	reconcile.SetIgnoreStore(store)
+	defer reconcile.SetIgnoreStore(nil)
+
+	assert.Equal(t, 400, post(`{"key": "1"}`))
+	assert.Equal(t, 400, post(`{"key": "1", "reason": "x", "expires_at": "2000-01-01T00:00:00Z"}`))
+	assert.Equal(t, 201, post(`{"key": "1", "reason": "custom item", "expires_at": "`+time.Now().Add(time.Hour).Format(time.RFC3339)+`"}`))
+
+	entries, err := store.LoadIgnores(context.Background(), "furniture", time.Now())
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "custom item", entries[0].Reason)
+	assert.NotNil(t, entries[0].ExpiresAt)
+}
+
+func TestHandler_HandleTriageFurniture(t *testing.T) {
+	svc := NewService(new(mocks.Client), storage.SingleBucket("test-bucket"), zap.NewNop(), nil, "arcturus")
+	app, _, _ := setupTestApp(NewHandler(svc))
+
+	post := func(body string) int {
+		req := httptest.NewRequest("POST", "/reconcile/furniture/triage", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		return resp.StatusCode
+	}
+
+	reconcile.SetTriageStore(nil)
+	assert.Equal(t, 503, post(`{"key": "1", "acknowledged": true}`))
+
+	db, err := state.Open(state.Config{Path: filepath.Join(t.TempDir(), "state.db")})
+	require.NoError(t, err)
+	store, err := state.NewTriageStore(db)
+	require.NoError(t, err)
+	reconcile.SetTriageStore(store)
+	defer reconcile.SetTriageStore(nil)
+
+	assert.Equal(t, 400, post(`{"acknowledged": true}`))
+	assert.Equal(t, 200, post(`{"key": "1", "acknowledged": true, "assigned_to": "alice", "note": "asked the designer"}`))
+
+	triage, err := store.LoadTriage(context.Background(), "furniture")
+	require.NoError(t, err)
+	assert.Equal(t, "alice", triage["1"].AssignedTo)
+
+	// An empty triage clears it
+	assert.Equal(t, 200, post(`{"key": "1"}`))
+	triage, err = store.LoadTriage(context.Background(), "furniture")
+	require.NoError(t, err)
+	assert.Empty(t, triage)
+}
+
+func TestHandler_HandleReconcileStream(t *testing.T) {
+	furniData := `{"roomitemtypes": {"furnitype": [{"id": 100, "classname": "chair", "name": "Chair"}]}, "wallitemtypes": {"furnitype": []}}`
+
+	t.Run("Success", func(t *testing.T) {
+		mockClient := new(mocks.Client)
+		db, sqlMock := setupMockDB(t)
+		mockClient.On("BucketExists", mock.Anything, "test-bucket").Return(true, nil)
+		mockClient.On("GetObject", mock.Anything, "test-bucket", "gamedata/FurnitureData.json", mock.Anything).
+			Return(io.NopCloser(strings.NewReader(furniData)), nil)
+		objCh := make(chan minio.ObjectInfo, 1)
+		objCh <- minio.ObjectInfo{Key: "bundled/furniture/chair.nitro"}
+		close(objCh)
+		mockClient.On("ListObjects", mock.Anything, "test-bucket", mock.Anything).Return((<-chan minio.ObjectInfo)(objCh))
+		rows := sqlmock.NewRows([]string{"id", "sprite_id", "item_name", "public_name", "width", "length", "allow_sit", "type"}).
+			AddRow(1, 100, "chair", "Chair", 1, 1, 1, "s")
+		sqlMock.ExpectQuery("SELECT \\* FROM items_base").WillReturnRows(rows)
+
+		svc := NewService(mockClient, storage.SingleBucket("test-bucket"), zap.NewNop(), db, "arcturus")
+		app, _, _ := setupTestApp(NewHandler(svc))
+
+		resp, err := app.Test(httptest.NewRequest("GET", "/reconcile/furniture/stream", nil), -1)
+		require.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+		assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(string(body), `event: indexes
+data: {"stage":"indexes"}
+`), string(body))
+		// Indices finish loading in any order
+		assert.Contains(t, string(body), `event: indexed
+data: {"stage":"indexed","source":"db","processed":1}
+`)
+		assert.Contains(t, string(body), `event: union
+data: {"stage":"union","total":1}
+
+event: results
+data: {"stage":"results","processed":1,"total":1}
+
+`)
+		assert.Contains(t, string(body), "event: summary\ndata: {\"stage\":\"summary\",\"summary\":{\"total_items\":1,")
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		mockClient := new(mocks.Client)
+		mockClient.On("BucketExists", mock.Anything, "test-bucket").Return(false, assert.AnError)
+		svc := NewService(mockClient, storage.SingleBucket("test-bucket"), zap.NewNop(), nil, "arcturus")
+		app, _, _ := setupTestApp(NewHandler(svc))
+
+		resp, err := app.Test(httptest.NewRequest("GET", "/reconcile/furniture/stream", nil), -1)
+		require.NoError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(string(body), "event: error\ndata: {\"error\":\"failed to check bucket existence: "), string(body))
+	})
+}
+
+func TestHandler_HandleStartReconcile(t *testing.T) {
+	mockClient := new(mocks.Client)
+	mockClient.On("BucketExists", mock.Anything, "test-bucket").Return(false, assert.AnError)
+	svc := NewService(mockClient, storage.SingleBucket("test-bucket"), zap.NewNop(), nil, "arcturus")
+	app, _, _ := setupTestApp(NewHandler(svc))
+
+	// Without a job manager the run cannot be started in the background
+	jobs.SetManager(nil)
+	resp, err := app.Test(httptest.NewRequest("POST", "/reconcile/furniture", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 503, resp.StatusCode)
+
+	manager := jobs.NewManager(jobs.Config{Retention: time.Hour, MaxRunning: 1})
+	jobs.SetManager(manager)
+	defer func() {
+		jobs.SetManager(nil)
+		manager.Stop()
+	}()
+
+	resp, err = app.Test(httptest.NewRequest("POST", "/reconcile/furniture", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 202, resp.StatusCode)
+
+	var job jobs.Job
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&job))
+	assert.Equal(t, ReconcileJobKind, job.Kind)
+	assert.Equal(t, "/jobs/"+job.ID, resp.Header.Get("Location"))
+
+	require.Eventually(t, func() bool {
+		got, _ := manager.Get(job.ID)
+		return got.Status == jobs.StatusFailed
+	}, time.Second, time.Millisecond)
+	got, _ := manager.Get(job.ID)
+	assert.Contains(t, got.Error, "failed to check bucket existence")
+}
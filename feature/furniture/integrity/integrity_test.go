@@ -6,8 +6,8 @@ import (
 	"strings"
 	"testing"
 
+	"asset-manager/core/storage"
 	"asset-manager/core/storage/mocks"
-	"asset-manager/feature/furniture/models"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/minio/minio-go/v7"
@@ -73,7 +73,7 @@ func TestCheckIntegrity(t *testing.T) {
 		rows.AddRow(1, 100, "chair", "Chair", 1, 1, 1, "s")
 		sqlMock.ExpectQuery("SELECT \\* FROM items_base").WillReturnRows(rows)
 
-		report, err := CheckIntegrity(context.Background(), mockClient, "test-bucket", db, "arcturus")
+		report, err := CheckIntegrity(context.Background(), mockClient, storage.SingleBucket("test-bucket"), db, "arcturus")
 		assert.NoError(t, err)
 		assert.NotNil(t, report)
 		assert.Equal(t, 1, report.TotalExpected)
@@ -100,7 +100,7 @@ func TestCheckIntegrity(t *testing.T) {
 		mockClient.On("ListObjects", mock.Anything, "test-bucket", mock.Anything).
 			Return((<-chan minio.ObjectInfo)(emptyCh)).Maybe()
 
-		report, err := CheckIntegrity(context.Background(), mockClient, "test-bucket", db, "arcturus")
+		report, err := CheckIntegrity(context.Background(), mockClient, storage.SingleBucket("test-bucket"), db, "arcturus")
 		assert.Error(t, err)
 		assert.Nil(t, report)
 		assert.Contains(t, err.Error(), "bucket test-bucket not found")
@@ -151,7 +151,7 @@ func TestCheckFurnitureItem(t *testing.T) {
 			WithArgs("chair", 1).
 			WillReturnRows(rows)
 
-		report, err := CheckFurnitureItem(context.Background(), mockClient, "test-bucket", db, "arcturus", "chair")
+		report, err := CheckFurnitureItem(context.Background(), mockClient, storage.SingleBucket("test-bucket"), db, "arcturus", "chair")
 		assert.NoError(t, err)
 		assert.NotNil(t, report)
 		assert.Equal(t, "PASS", report.IntegrityStatus)
@@ -160,55 +160,3 @@ func TestCheckFurnitureItem(t *testing.T) {
 		assert.True(t, report.FileExists)
 	})
 }
-
-func TestCheckIntegrityWithDB(t *testing.T) {
-	db, sqlMock := setupMockDB(t)
-
-	furniData := &models.FurnitureData{}
-	furniData.RoomItemTypes.FurniType = []models.FurnitureItem{
-		{ID: 100, ClassName: "chair", Name: "Chair", XDim: 1, YDim: 1},
-	}
-
-	t.Run("Match", func(t *testing.T) {
-		rows := sqlmock.NewRows([]string{"id", "sprite_id", "item_name", "public_name", "width", "length", "allow_sit", "type"})
-		rows.AddRow(1, 100, "chair", "Chair", 1, 1, 0, "s") // allow_sit=0
-		sqlMock.ExpectQuery("SELECT \\* FROM items_base").WillReturnRows(rows)
-
-		mismatches, err := CheckIntegrityWithDB(context.Background(), furniData, db, "arcturus")
-		assert.NoError(t, err)
-		assert.Empty(t, mismatches)
-	})
-}
-
-func TestGetDBFurnitureItem(t *testing.T) {
-	db, sqlMock := setupMockDB(t)
-
-	t.Run("Found", func(t *testing.T) {
-		rows := sqlmock.NewRows([]string{"id", "sprite_id", "item_name", "public_name", "width", "length", "allow_sit", "type"})
-		rows.AddRow(1, 100, "chair", "Chair", 1, 1, 1, "s")
-
-		sqlMock.ExpectQuery("SELECT \\* FROM [`]?items_base[`]? WHERE item_name = \\?.*").
-			WithArgs("chair", 1).
-			WillReturnRows(rows)
-
-		item, err := GetDBFurnitureItem(db, "arcturus", "chair")
-		assert.NoError(t, err)
-		assert.NotNil(t, item)
-		// Note: GetDBFurnitureItem maps ID to SpriteID if adapter parses it that way?
-		// Actually adapter.DBItem has ID and SpriteID.
-		// GetDBFurnitureItem returns models.DBFurnitureItem.
-		// Let's check mapping in integrity.go:
-		// models.DBFurnitureItem.ID = item.ID (which is DB ID, not SpriteID?)
-		// Wait, adapter loads SpriteID into Key, but DBItem struct has both.
-		// GetDBFurnitureItem mapping: ID: item.ID.
-		// So it returns the Database ID (1), NOT the SpriteID (100).
-
-		// Wait, looking at code:
-		// item.ID = toInt(id) -> DB ID.
-		// item.SpriteID = toInt(sprite_id).
-		// integrity.go:284: ID: item.ID
-		// So assert should match DB ID.
-		assert.Equal(t, 1, item.ID) // Matches DB ID
-		assert.Equal(t, "chair", item.ItemName)
-	})
-}
@@ -0,0 +1,111 @@
+package integrity
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+
+	"asset-manager/core/reconcile"
+	"asset-manager/core/storage"
+	"asset-manager/feature/furniture/models"
+	furnitureAdp "asset-manager/feature/furniture/reconcile"
+
+	"gorm.io/gorm"
+)
+
+// RepairNameEncoding finds furniture whose public_name is its gamedata name garbled by
+// HTML entities or a latin1/UTF-8 double encoding (see furnitureAdp.RepairName), using
+// gamedata as the reference. Unless dryRun, it writes the gamedata names back in one
+// transaction under the run lock; a row whose public_name changed since it was read is
+// left alone. It returns a *reconcile.LockedError when another reconcile holds the lock.
+func RepairNameEncoding(ctx context.Context, client storage.Client, buckets storage.Buckets, db *gorm.DB, emulator string, dryRun bool) (report *models.NameEncodingReport, err error) {
+	if db == nil {
+		return nil, fmt.Errorf("name repair requires a database connection")
+	}
+
+	if !dryRun {
+		lock, err := reconcile.AcquireRunLock(ctx, client, buckets.Assets, reconcile.DefaultLockTTL)
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			if releaseErr := lock.Release(); releaseErr != nil && err == nil {
+				err = releaseErr
+			}
+		}()
+	}
+
+	spec := furnitureAdp.NewSpec(furnitureAdp.NewAdapter(), emulator, buckets.Gamedata, 0)
+	cache, err := reconcile.BuildCache(ctx, spec, db, client, buckets.Assets)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load furniture: %w", err)
+	}
+
+	report = findNameRepairs(cache)
+	if dryRun || len(report.Repairs) == 0 {
+		return report, nil
+	}
+
+	profile := furnitureAdp.GetProfileByName(emulator)
+	idCol, nameCol := profile.Columns[furnitureAdp.ColID], profile.Columns[furnitureAdp.ColPublicName]
+	err = db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, repair := range report.Repairs {
+			err := tx.Table(profile.TableName).
+				Where(idCol+" = ? AND "+nameCol+" = ?", repair.ID, repair.Stored).
+				Update(nameCol, repair.Repaired).Error
+			if err != nil {
+				return fmt.Errorf("failed to repair name of %s: %w", repair.Key, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return report, err
+	}
+	report.Applied = true
+
+	// Cached DB items still hold the garbled names
+	for _, registered := range reconcile.RegisteredSpecs() {
+		if _, ok := registered.Adapter.(*furnitureAdp.FurnitureAdapter); ok {
+			reconcile.InvalidateCache(registered)
+		}
+	}
+	return report, nil
+}
+
+// findNameRepairs lists the garbled public names of the items in both the DB and
+// gamedata indices of cache.
+func findNameRepairs(cache *reconcile.ReconcileCache) *models.NameEncodingReport {
+	report := &models.NameEncodingReport{Repairs: []models.NameRepair{}}
+	for key, dbItem := range cache.DBIndex {
+		gdItem, ok := cache.GDIndex[key]
+		if !ok {
+			continue
+		}
+		dbFurni, gdFurni := dbItem.(furnitureAdp.DBItem), gdItem.(furnitureAdp.GDItem)
+		if slices.Contains(dbFurni.NotCompared, "name") {
+			report.Skipped = "the furniture table has no public_name column"
+			return report
+		}
+		report.Checked++
+
+		issues, ok := furnitureAdp.RepairName(dbFurni.PublicName, gdFurni.Name)
+		if !ok {
+			continue
+		}
+		repair := models.NameRepair{
+			Key:       key,
+			ID:        dbFurni.ID,
+			ClassName: gdFurni.ClassName,
+			Stored:    dbFurni.PublicName,
+			Repaired:  gdFurni.Name,
+		}
+		for _, issue := range issues {
+			repair.Issues = append(repair.Issues, string(issue))
+		}
+		report.Repairs = append(report.Repairs, repair)
+	}
+	sort.Slice(report.Repairs, func(i, j int) bool { return report.Repairs[i].Key < report.Repairs[j].Key })
+	return report
+}
@@ -0,0 +1,81 @@
+package integrity
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"asset-manager/core/storage"
+	"asset-manager/core/storage/mocks"
+	"asset-manager/feature/furniture/models"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+const namesGamedataJSON = `{
+	"roomitemtypes": {"furnitype": [
+		{"id": 1, "classname": "cafe_table", "name": "Café Table"},
+		{"id": 2, "classname": "rock_chair", "name": "Rock & Roll Chair"},
+		{"id": 3, "classname": "sofa", "name": "New Sofa"}
+	]},
+	"wallitemtypes": {"furnitype": []}
+}`
+
+// setupNamesDB creates an Arcturus items_base table with garbled public names.
+func setupNamesDB(t *testing.T, name string) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open("file:"+name+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+
+	require.NoError(t, db.Exec(`CREATE TABLE items_base (id INTEGER PRIMARY KEY, sprite_id INTEGER, item_name VARCHAR(70), public_name VARCHAR(56))`).Error)
+	require.NoError(t, db.Exec(`INSERT INTO items_base (id, sprite_id, item_name, public_name) VALUES
+		(11, 1, 'cafe_table', 'CafÃ© Table'),
+		(12, 2, 'rock_chair', 'Rock &amp; Roll Chair'),
+		(13, 3, 'sofa', 'Old Sofa')`).Error)
+	return db
+}
+
+// mockNamesStorage serves the gamedata and an empty asset listing.
+func mockNamesStorage() *mocks.Client {
+	mockClient := new(mocks.Client)
+	mockClient.On("GetObject", mock.Anything, "test-bucket", "gamedata/FurnitureData.json", mock.Anything).
+		Return(io.NopCloser(strings.NewReader(namesGamedataJSON)), nil)
+	mockClient.On("BucketExists", mock.Anything, "test-bucket").Return(true, nil)
+	ch := make(chan minio.ObjectInfo)
+	close(ch)
+	mockClient.On("ListObjects", mock.Anything, "test-bucket", mock.Anything).Return((<-chan minio.ObjectInfo)(ch))
+	return mockClient
+}
+
+// TestRepairNameEncoding tests that garbled names are found and restored from gamedata.
+func TestRepairNameEncoding(t *testing.T) {
+	db := setupNamesDB(t, "names_repair")
+	mockClient := mockNamesStorage()
+
+	want := []models.NameRepair{
+		{Key: "1", ID: 11, ClassName: "cafe_table", Stored: "CafÃ© Table", Repaired: "Café Table", Issues: []string{"double_encoded"}},
+		{Key: "2", ID: 12, ClassName: "rock_chair", Stored: "Rock &amp; Roll Chair", Repaired: "Rock & Roll Chair", Issues: []string{"html_entities"}},
+	}
+
+	report, err := RepairNameEncoding(context.Background(), mockClient, storage.SingleBucket("test-bucket"), db, "arcturus", true)
+	require.NoError(t, err)
+	assert.Equal(t, 3, report.Checked)
+	assert.Equal(t, want, report.Repairs)
+	assert.False(t, report.Applied)
+	assert.Equal(t, []string{"CafÃ© Table", "Rock &amp; Roll Chair", "Old Sofa"}, itemNames(t, db, "items_base", "public_name"))
+
+	// The gamedata reader was consumed by the dry run
+	mockClient = mockNamesStorage()
+	mockLock(mockClient)
+	report, err = RepairNameEncoding(context.Background(), mockClient, storage.SingleBucket("test-bucket"), db, "arcturus", false)
+	require.NoError(t, err)
+	assert.Equal(t, want, report.Repairs)
+	assert.True(t, report.Applied)
+	// A name that differs for other reasons is left to a sync
+	assert.Equal(t, []string{"Café Table", "Rock & Roll Chair", "Old Sofa"}, itemNames(t, db, "items_base", "public_name"))
+}
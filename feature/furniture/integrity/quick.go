@@ -0,0 +1,164 @@
+package integrity
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"asset-manager/core/json"
+	"asset-manager/core/reconcile"
+	"asset-manager/core/storage"
+	"asset-manager/feature/furniture/models"
+	furnitureAdp "asset-manager/feature/furniture/reconcile"
+
+	"github.com/minio/minio-go/v7"
+	"gorm.io/gorm"
+)
+
+// DefaultDriftThreshold is the count drift, in percent, above which a quick check alerts.
+const DefaultDriftThreshold = 1.0
+
+// gamedataCount decodes FurnitureData.json without keeping any item fields.
+type gamedataCount struct {
+	RoomItemTypes struct {
+		FurniType []struct{} `json:"furnitype"`
+	} `json:"roomitemtypes"`
+	WallItemTypes struct {
+		FurniType []struct{} `json:"furnitype"`
+	} `json:"wallitemtypes"`
+}
+
+// QuickCheck compares furniture counts across the database, gamedata and storage
+// without building indices or matching items, so it is cheap enough for minute-level
+// monitoring. Drift is the spread between the largest and smallest count as a
+// percentage of the largest; the report alerts when it exceeds threshold.
+// The database is skipped when db is nil.
+func QuickCheck(ctx context.Context, client storage.Client, buckets storage.Buckets, db *gorm.DB, emulator string, threshold float64) (*models.QuickReport, error) {
+	startTime := time.Now()
+
+	var (
+		dbCount, gdCount, storageCount int
+		dbErr, gdErr, storageErr       error
+		wg                             sync.WaitGroup
+	)
+
+	if db != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			dbCount, dbErr = countDB(ctx, reconcile.ReadDB(db), emulator)
+		}()
+	}
+
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		gdCount, gdErr = countGamedata(ctx, client, buckets.Gamedata)
+	}()
+	go func() {
+		defer wg.Done()
+		storageCount, storageErr = countStorage(ctx, client, buckets.Assets)
+	}()
+
+	wg.Wait()
+
+	if dbErr != nil {
+		return nil, dbErr
+	}
+	if gdErr != nil {
+		return nil, gdErr
+	}
+	if storageErr != nil {
+		return nil, storageErr
+	}
+
+	counts := map[string]int{
+		reconcile.SourceGamedata: gdCount,
+		reconcile.SourceStorage:  storageCount,
+	}
+	if db != nil {
+		counts[reconcile.SourceDB] = dbCount
+	}
+
+	drift := countDrift(counts)
+	return &models.QuickReport{
+		Counts:        counts,
+		DriftPercent:  drift,
+		Threshold:     threshold,
+		Alert:         drift > threshold,
+		GeneratedAt:   time.Now().Format(time.RFC3339),
+		ExecutionTime: time.Since(startTime).String(),
+	}, nil
+}
+
+// countDrift returns the spread between the largest and smallest count as a
+// percentage of the largest. All-empty sources have no drift.
+func countDrift(counts map[string]int) float64 {
+	first := true
+	var lowest, highest int
+	for _, count := range counts {
+		if first || count < lowest {
+			lowest = count
+		}
+		if first || count > highest {
+			highest = count
+		}
+		first = false
+	}
+	if highest == 0 {
+		return 0
+	}
+	return float64(highest-lowest) / float64(highest) * 100
+}
+
+// countDB counts the rows of the profile's furniture table.
+func countDB(ctx context.Context, db *gorm.DB, emulator string) (int, error) {
+	var count int64
+	table := furnitureAdp.GetProfileByName(emulator).TableName
+	if err := db.WithContext(ctx).Table(table).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count %s rows: %w", table, err)
+	}
+	return int(count), nil
+}
+
+// countGamedata counts the room and wall items in FurnitureData.json.
+func countGamedata(ctx context.Context, client storage.Client, bucket string) (int, error) {
+	reader, err := client.GetObject(ctx, bucket, furnitureAdp.GamedataObject, minio.GetObjectOptions{})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get gamedata object: %w", err)
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read gamedata: %w", err)
+	}
+
+	var furniData gamedataCount
+	if err := json.Unmarshal(data, &furniData); err != nil {
+		return 0, fmt.Errorf("failed to parse gamedata JSON: %w", err)
+	}
+	return len(furniData.RoomItemTypes.FurniType) + len(furniData.WallItemTypes.FurniType), nil
+}
+
+// countStorage counts the furniture assets in storage, nested ones included.
+func countStorage(ctx context.Context, client storage.Client, bucket string) (int, error) {
+	opts := minio.ListObjectsOptions{
+		Prefix:    furnitureAdp.StoragePrefix + "/",
+		Recursive: true,
+	}
+
+	count := 0
+	for obj := range client.ListObjects(ctx, bucket, opts) {
+		if obj.Err != nil {
+			return 0, fmt.Errorf("failed to list objects: %w", obj.Err)
+		}
+		if strings.HasSuffix(obj.Key, furnitureAdp.StorageExtension) {
+			count++
+		}
+	}
+	return count, nil
+}
@@ -0,0 +1,94 @@
+package integrity
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"asset-manager/core/reconcile"
+	"asset-manager/core/storage"
+	"asset-manager/core/storage/mocks"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuickCheck(t *testing.T) {
+	furniDataJSON := `{
+		"roomitemtypes": {"furnitype": [{"id": 1, "classname": "chair"}, {"id": 2, "classname": "table"}]},
+		"wallitemtypes": {"furnitype": [{"id": 3, "classname": "poster"}]}
+	}`
+
+	setup := func() *mocks.Client {
+		mockClient := new(mocks.Client)
+		mockClient.On("GetObject", mock.Anything, "test-bucket", "gamedata/FurnitureData.json", mock.Anything).
+			Return(io.NopCloser(strings.NewReader(furniDataJSON)), nil)
+
+		objCh := make(chan minio.ObjectInfo, 4)
+		objCh <- minio.ObjectInfo{Key: "bundled/furniture/chair.nitro"}
+		objCh <- minio.ObjectInfo{Key: "bundled/furniture/table.nitro"}
+		objCh <- minio.ObjectInfo{Key: "bundled/furniture/old/poster.nitro"}
+		objCh <- minio.ObjectInfo{Key: "bundled/furniture/readme.txt"}
+		close(objCh)
+		mockClient.On("ListObjects", mock.Anything, "test-bucket", mock.MatchedBy(func(opts minio.ListObjectsOptions) bool {
+			return opts.Prefix == "bundled/furniture/" && opts.Recursive
+		})).Return((<-chan minio.ObjectInfo)(objCh))
+		return mockClient
+	}
+
+	t.Run("InSync", func(t *testing.T) {
+		mockClient := setup()
+		db, sqlMock := setupMockDB(t)
+		sqlMock.ExpectQuery("SELECT count\\(\\*\\) FROM `items_base`").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+		report, err := QuickCheck(context.Background(), mockClient, storage.SingleBucket("test-bucket"), db, "arcturus", DefaultDriftThreshold)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{reconcile.SourceDB: 3, reconcile.SourceGamedata: 3, reconcile.SourceStorage: 3}, report.Counts)
+		assert.Zero(t, report.DriftPercent)
+		assert.False(t, report.Alert)
+		assert.NoError(t, sqlMock.ExpectationsWereMet())
+	})
+
+	t.Run("Drift", func(t *testing.T) {
+		mockClient := setup()
+		db, sqlMock := setupMockDB(t)
+		sqlMock.ExpectQuery("SELECT count\\(\\*\\) FROM `items_base`").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(4))
+
+		report, err := QuickCheck(context.Background(), mockClient, storage.SingleBucket("test-bucket"), db, "arcturus", 10)
+		require.NoError(t, err)
+		assert.InDelta(t, 25.0, report.DriftPercent, 0.001)
+		assert.Equal(t, 10.0, report.Threshold)
+		assert.True(t, report.Alert)
+	})
+
+	t.Run("WithoutDB", func(t *testing.T) {
+		mockClient := setup()
+
+		report, err := QuickCheck(context.Background(), mockClient, storage.SingleBucket("test-bucket"), nil, "arcturus", DefaultDriftThreshold)
+		require.NoError(t, err)
+		assert.NotContains(t, report.Counts, reconcile.SourceDB)
+		assert.False(t, report.Alert)
+	})
+
+	t.Run("DBError", func(t *testing.T) {
+		mockClient := setup()
+		db, sqlMock := setupMockDB(t)
+		sqlMock.ExpectQuery("SELECT count").WillReturnError(assert.AnError)
+
+		_, err := QuickCheck(context.Background(), mockClient, storage.SingleBucket("test-bucket"), db, "arcturus", DefaultDriftThreshold)
+		assert.ErrorContains(t, err, "failed to count items_base rows")
+	})
+}
+
+func TestCountDrift(t *testing.T) {
+	assert.Zero(t, countDrift(map[string]int{"db": 0, "gamedata": 0}))
+	assert.Zero(t, countDrift(map[string]int{"db": 7}))
+	assert.InDelta(t, 50.0, countDrift(map[string]int{"db": 10, "gamedata": 5, "storage": 8}), 0.001)
+	assert.InDelta(t, 100.0, countDrift(map[string]int{"db": 10, "storage": 0}), 0.001)
+}
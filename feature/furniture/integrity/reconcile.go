@@ -2,6 +2,7 @@ package integrity
 
 import (
 	"context"
+	"fmt"
 
 	"asset-manager/core/reconcile"
 	"asset-manager/core/storage"
@@ -12,36 +13,16 @@ import (
 
 // ReconcileFurniture performs furniture reconciliation and returns raw results.
 // This is exported for CLI use to get detailed reconcile results.
-func ReconcileFurniture(ctx context.Context, client storage.Client, bucket string, db *gorm.DB, emulator string) ([]reconcile.ReconcileResult, error) {
-	// Create furniture adapter and spec
-	adapter := furnitureAdp.NewAdapter()
-	spec := &reconcile.Spec{
-		Adapter:            adapter,
-		CacheTTL:           0, // No caching for full scan
-		StoragePrefix:      "bundled/furniture",
-		StorageExtension:   ".nitro",
-		GamedataPaths:      []string{"roomitemtypes.furnitype", "wallitemtypes.furnitype"},
-		GamedataObjectName: "gamedata/FurnitureData.json",
-		ServerProfile:      emulator,
-	}
+func ReconcileFurniture(ctx context.Context, client storage.Client, buckets storage.Buckets, db *gorm.DB, emulator string) ([]reconcile.ReconcileResult, error) {
+	spec := furnitureAdp.NewSpec(furnitureAdp.NewAdapter(), emulator, buckets.Gamedata, 0)
 
 	// Run reconciliation and return raw results
-	return reconcile.ReconcileAll(ctx, spec, db, client, bucket)
+	return reconcile.ReconcileAll(ctx, spec, db, client, buckets.Assets)
 }
 
 // ReconcileFurnitureWithPlan performs reconciliation and returns a plan with summary for accurate counting.
-func ReconcileFurnitureWithPlan(ctx context.Context, client storage.Client, bucket string, db *gorm.DB, emulator string) (*reconcile.ReconcilePlan, error) {
-	// Create adapter and spec
-	adapter := furnitureAdp.NewAdapter()
-	spec := &reconcile.Spec{
-		Adapter:            adapter,
-		CacheTTL:           0, // No caching for full scan
-		StoragePrefix:      "bundled/furniture",
-		StorageExtension:   ".nitro",
-		GamedataPaths:      []string{"roomitemtypes.furnitype", "wallitemtypes.furnitype"},
-		GamedataObjectName: "gamedata/FurnitureData.json",
-		ServerProfile:      emulator,
-	}
+func ReconcileFurnitureWithPlan(ctx context.Context, client storage.Client, buckets storage.Buckets, db *gorm.DB, emulator string) (*reconcile.ReconcilePlan, error) {
+	spec := furnitureAdp.NewSpec(furnitureAdp.NewAdapter(), emulator, buckets.Gamedata, 0)
 
 	// Build plan with proper counting
 	opts := reconcile.ReconcileOptions{
@@ -50,5 +31,35 @@ func ReconcileFurnitureWithPlan(ctx context.Context, client storage.Client, buck
 		DryRun:  true,
 	}
 
-	return reconcile.ReconcileWithPlan(ctx, spec, db, client, bucket, opts)
+	return reconcile.ReconcileWithPlan(ctx, spec, db, client, buckets.Assets, opts)
+}
+
+// SafeFixFurniture applies the whitelisted furniture syncs allowed by policy without
+// confirmation. It is used by the scheduler and by `reconcile furniture --safe-fix`.
+// It returns a *reconcile.LockedError when another reconcile holds the run lock.
+func SafeFixFurniture(ctx context.Context, client storage.Client, buckets storage.Buckets, db *gorm.DB, emulator string, policy reconcile.SafeFixPolicy) (result *reconcile.SafeFixResult, err error) {
+	lock, err := reconcile.AcquireRunLock(ctx, client, buckets.Assets, reconcile.DefaultLockTTL)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if releaseErr := lock.Release(); releaseErr != nil && err == nil {
+			err = releaseErr
+		}
+	}()
+
+	adapter := furnitureAdp.NewAdapter()
+	adapter.SetMutationContext(db, client, buckets, furnitureAdp.AssetPrefix(), emulator, furnitureAdp.GamedataKey())
+
+	spec := furnitureAdp.NewSpec(adapter, emulator, buckets.Gamedata, 0)
+	if _, err := reconcile.CheckPermissions(ctx, spec, db, client, buckets.Assets, reconcile.ReconcileOptions{DoSync: true}); err != nil {
+		return nil, err
+	}
+
+	// Widen columns first so synced values are never truncated by the schema
+	if err := adapter.Prepare(ctx, db); err != nil {
+		return nil, fmt.Errorf("failed to prepare schema: %w", err)
+	}
+
+	return reconcile.ApplySafeFixes(ctx, spec, db, client, buckets.Assets, policy)
 }
@@ -0,0 +1,471 @@
+package integrity
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"asset-manager/core/json"
+	"asset-manager/core/reconcile"
+	"asset-manager/core/storage"
+	"asset-manager/feature/furniture/models"
+	furnitureAdp "asset-manager/feature/furniture/reconcile"
+
+	"github.com/minio/minio-go/v7"
+	"gorm.io/gorm"
+)
+
+const (
+	// CatalogItemsTable is the emulator table holding catalog offers.
+	CatalogItemsTable = "catalog_items"
+
+	// catalogNameColumn is the catalog_items column that usually repeats the classname.
+	catalogNameColumn = "catalog_name"
+)
+
+// classnamePattern matches classnames (and prefixes) that are safe as storage file names.
+var classnamePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// renameRule maps classnames to their new names: one classname with its color
+// variants, or every classname starting with a prefix.
+type renameRule struct {
+	old, new string
+	prefix   bool
+}
+
+// rename returns the new name of a classname, or false when the rule does not apply.
+func (r renameRule) rename(name string) (string, bool) {
+	if r.prefix {
+		if !strings.HasPrefix(name, r.old) {
+			return "", false
+		}
+		return r.new + name[len(r.old):], true
+	}
+	return renamedClassname(name, r.old, r.new)
+}
+
+// conflicts returns the names in existing that would collide with a renamed classname.
+// Names the rule renames away themselves do not collide.
+func (r renameRule) conflicts(existing, renamed []string) []string {
+	targets := make(map[string]bool, len(renamed))
+	for _, name := range renamed {
+		if updated, ok := r.rename(name); ok {
+			targets[furnitureAdp.BaseClassname(updated)] = true
+		}
+	}
+
+	var conflicts []string
+	for _, name := range existing {
+		if _, ok := r.rename(name); ok {
+			continue
+		}
+		if targets[furnitureAdp.BaseClassname(name)] {
+			conflicts = append(conflicts, name)
+		}
+	}
+	return conflicts
+}
+
+// renameRow is a DB row whose classname column is renamed.
+type renameRow struct {
+	ID   int
+	Name string
+}
+
+// renameState holds everything read while planning a rename, for applying it.
+type renameState struct {
+	rule        renameRule
+	plan        *models.RenamePlan
+	gamedata    []byte
+	renamedDoc  []byte
+	dbRows      []renameRow
+	catalogRows []renameRow
+}
+
+// RenameClassname renames a furniture classname in the database, FurnitureData.json,
+// catalog_items and storage together. Color variants (old*N) follow the base name.
+// With dryRun the plan is returned without changing anything.
+//
+// The database updates run in one transaction that only commits once the new gamedata
+// is written; any failure rolls back the transaction, restores the gamedata and removes
+// the copied assets. The old assets are removed last, and failures there are warnings.
+// It returns a *reconcile.LockedError when another reconcile holds the run lock.
+func RenameClassname(ctx context.Context, client storage.Client, buckets storage.Buckets, db *gorm.DB, emulator, oldName, newName string, dryRun bool) (*models.RenamePlan, error) {
+	return runRename(ctx, client, buckets, db, emulator, renameRule{old: oldName, new: newName}, dryRun)
+}
+
+// RenamePrefix renames every classname starting with oldPrefix to start with newPrefix
+// (e.g. xmas14_tree to xmas_2014_tree) in one planned operation, with the same sources
+// and rollback guarantees as RenameClassname.
+func RenamePrefix(ctx context.Context, client storage.Client, buckets storage.Buckets, db *gorm.DB, emulator, oldPrefix, newPrefix string, dryRun bool) (*models.RenamePlan, error) {
+	return runRename(ctx, client, buckets, db, emulator, renameRule{old: oldPrefix, new: newPrefix, prefix: true}, dryRun)
+}
+
+// runRename plans a rename and, unless dryRun, applies it under the run lock.
+func runRename(ctx context.Context, client storage.Client, buckets storage.Buckets, db *gorm.DB, emulator string, rule renameRule, dryRun bool) (plan *models.RenamePlan, err error) {
+	if db == nil {
+		return nil, fmt.Errorf("rename requires a database connection")
+	}
+	if err := validateRename(rule); err != nil {
+		return nil, err
+	}
+
+	if !dryRun {
+		lock, err := reconcile.AcquireRunLock(ctx, client, buckets.Assets, reconcile.DefaultLockTTL)
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			if releaseErr := lock.Release(); releaseErr != nil && err == nil {
+				err = releaseErr
+			}
+		}()
+	}
+
+	state, err := planRename(ctx, client, buckets, db, emulator, rule)
+	if err != nil {
+		return nil, err
+	}
+	if dryRun {
+		return state.plan, nil
+	}
+
+	if err := applyRename(ctx, client, buckets, db, emulator, state); err != nil {
+		return state.plan, err
+	}
+
+	// Indices keyed by the old classnames are stale now
+	for _, spec := range reconcile.RegisteredSpecs() {
+		if _, ok := spec.Adapter.(*furnitureAdp.FurnitureAdapter); ok {
+			reconcile.InvalidateCache(spec)
+		}
+	}
+	return state.plan, nil
+}
+
+// validateRename rejects classnames that cannot be used as asset file names.
+func validateRename(rule renameRule) error {
+	for _, name := range []string{rule.old, rule.new} {
+		if !classnamePattern.MatchString(name) {
+			return fmt.Errorf("invalid classname %q: use letters, digits, '_', '-' and '.'", name)
+		}
+	}
+	if rule.old == rule.new {
+		return fmt.Errorf("old and new classname are both %q", rule.old)
+	}
+	return nil
+}
+
+// matchesClassname reports whether name is classname or one of its color variants.
+func matchesClassname(name, classname string) bool {
+	return name == classname || strings.HasPrefix(name, classname+"*")
+}
+
+// renamedClassname returns the new name of a classname, or false when it is neither
+// the old classname nor one of its color variants.
+func renamedClassname(name, oldName, newName string) (string, bool) {
+	if !matchesClassname(name, oldName) {
+		return "", false
+	}
+	return newName + name[len(oldName):], true
+}
+
+// planRename reads every source and fails on conflicts before anything is written.
+func planRename(ctx context.Context, client storage.Client, buckets storage.Buckets, db *gorm.DB, emulator string, rule renameRule) (*renameState, error) {
+	state := &renameState{
+		rule: rule,
+		plan: &models.RenamePlan{
+			Old:        rule.old,
+			New:        rule.new,
+			Prefix:     rule.prefix,
+			Classnames: map[string]string{},
+			Storage:    []models.RenameMove{},
+		},
+	}
+	plan := state.plan
+	record := func(name string) {
+		if updated, ok := rule.rename(name); ok {
+			plan.Classnames[name] = updated
+		}
+	}
+
+	// Gamedata
+	reader, err := client.GetObject(ctx, buckets.Gamedata, furnitureAdp.GamedataObject, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get gamedata: %w", err)
+	}
+	state.gamedata, err = io.ReadAll(reader)
+	reader.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read gamedata: %w", err)
+	}
+
+	var doc map[string]any
+	if err := json.Unmarshal(state.gamedata, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse gamedata: %w", err)
+	}
+	existing := gamedataClassnames(doc)
+	renamed := renameGamedata(doc, rule)
+	if conflicts := rule.conflicts(existing, renamed); len(conflicts) > 0 {
+		return nil, fmt.Errorf("new classnames already exist in gamedata: %s", strings.Join(conflicts, ", "))
+	}
+	for _, name := range renamed {
+		record(name)
+	}
+	plan.GamedataEntries = len(renamed)
+	if len(renamed) == 0 {
+		plan.Warnings = append(plan.Warnings, "no matching classnames in gamedata")
+	} else if state.renamedDoc, err = json.MarshalIndent(doc, "", "  "); err != nil {
+		return nil, fmt.Errorf("failed to marshal gamedata: %w", err)
+	}
+
+	// Database
+	profile := furnitureAdp.GetProfileByName(emulator)
+	idCol, nameCol := profile.Columns[furnitureAdp.ColID], profile.Columns[furnitureAdp.ColItemName]
+	if state.dbRows, err = findRenameRows(ctx, db, profile.TableName, idCol, nameCol, rule); err != nil {
+		return nil, err
+	}
+	taken, err := findRows(ctx, db, profile.TableName, idCol, nameCol, rule.new)
+	if err != nil {
+		return nil, err
+	}
+	if conflicts := rule.conflicts(rowNames(taken), rowNames(state.dbRows)); len(conflicts) > 0 {
+		return nil, fmt.Errorf("new classnames already exist in %s: %s", profile.TableName, strings.Join(conflicts, ", "))
+	}
+	for _, row := range state.dbRows {
+		record(row.Name)
+	}
+	plan.DBRows = len(state.dbRows)
+	if plan.DBRows == 0 {
+		plan.Warnings = append(plan.Warnings, "no matching classnames in "+profile.TableName)
+	}
+
+	// Catalog references
+	if db.Migrator().HasTable(CatalogItemsTable) {
+		if state.catalogRows, err = findRenameRows(ctx, db, CatalogItemsTable, "id", catalogNameColumn, rule); err != nil {
+			return nil, err
+		}
+		plan.CatalogRows = len(state.catalogRows)
+	} else {
+		plan.Warnings = append(plan.Warnings, CatalogItemsTable+" table not found, catalog references skipped")
+	}
+
+	// Storage
+	sources, err := listAssetClassnames(ctx, client, buckets.Assets, rule.old)
+	if err != nil {
+		return nil, err
+	}
+	targets, err := listAssetClassnames(ctx, client, buckets.Assets, rule.new)
+	if err != nil {
+		return nil, err
+	}
+	var moved []string
+	for _, name := range sources {
+		if updated, ok := rule.rename(name); ok {
+			moved = append(moved, name)
+			record(name)
+			plan.Storage = append(plan.Storage, models.RenameMove{From: furnitureAdp.AssetKey(name), To: furnitureAdp.AssetKey(updated)})
+		}
+	}
+	if conflicts := rule.conflicts(targets, moved); len(conflicts) > 0 {
+		return nil, fmt.Errorf("new storage objects already exist: %s", strings.Join(conflicts, ", "))
+	}
+	if len(plan.Storage) == 0 {
+		plan.Warnings = append(plan.Warnings, "no matching assets in storage")
+	}
+
+	if len(plan.Classnames) == 0 && plan.CatalogRows == 0 {
+		return nil, fmt.Errorf("no classnames match %s in any source", rule.old)
+	}
+	return state, nil
+}
+
+// applyRename writes a planned rename, undoing earlier steps when a later one fails.
+func applyRename(ctx context.Context, client storage.Client, buckets storage.Buckets, db *gorm.DB, emulator string, state *renameState) error {
+	plan := state.plan
+
+	// 1. Copy the assets; the old keys stay until everything else succeeded
+	var copied []string
+	undoCopies := func() {
+		for _, failure := range storage.RemoveObjectsWithRetry(ctx, client, buckets.Assets, copied, storage.DefaultRemoveRetry) {
+			plan.Warnings = append(plan.Warnings, fmt.Sprintf("failed to remove copied object %s: %v", failure.Object, failure.Err))
+		}
+	}
+	for _, move := range plan.Storage {
+		if err := copyObject(ctx, client, buckets.Assets, move.From, move.To); err != nil {
+			undoCopies()
+			return err
+		}
+		copied = append(copied, move.To)
+	}
+
+	// 2. Update the database inside a transaction that stays open until gamedata is written
+	profile := furnitureAdp.GetProfileByName(emulator)
+	tx := db.WithContext(ctx).Begin()
+	if tx.Error != nil {
+		undoCopies()
+		return fmt.Errorf("failed to begin transaction: %w", tx.Error)
+	}
+	err := updateRenameRows(tx, profile.TableName, profile.Columns[furnitureAdp.ColID], profile.Columns[furnitureAdp.ColItemName], state.dbRows, state.rule)
+	if err == nil {
+		err = updateRenameRows(tx, CatalogItemsTable, "id", catalogNameColumn, state.catalogRows, state.rule)
+	}
+	if err != nil {
+		tx.Rollback()
+		undoCopies()
+		return err
+	}
+
+	// 3. Write the renamed gamedata
+	if state.renamedDoc != nil {
+		if err := putJSON(ctx, client, buckets.Gamedata, state.renamedDoc); err != nil {
+			tx.Rollback()
+			undoCopies()
+			return fmt.Errorf("failed to write gamedata: %w", err)
+		}
+	}
+
+	// 4. Commit, restoring the original gamedata if that fails
+	if err := tx.Commit().Error; err != nil {
+		if state.renamedDoc != nil {
+			if restoreErr := putJSON(ctx, client, buckets.Gamedata, state.gamedata); restoreErr != nil {
+				plan.Warnings = append(plan.Warnings, fmt.Sprintf("failed to restore gamedata: %v", restoreErr))
+			}
+		}
+		undoCopies()
+		return fmt.Errorf("failed to commit rename: %w", err)
+	}
+	plan.Applied = true
+
+	// 5. Drop the old assets; leftover copies are harmless and show up as unregistered
+	if len(plan.Storage) > 0 {
+		old := make([]string, 0, len(plan.Storage))
+		for _, move := range plan.Storage {
+			old = append(old, move.From)
+		}
+		for _, failure := range storage.RemoveObjectsWithRetry(ctx, client, buckets.Assets, old, storage.DefaultRemoveRetry) {
+			plan.Warnings = append(plan.Warnings, fmt.Sprintf("failed to remove old object %s: %v", failure.Object, failure.Err))
+		}
+	}
+	return nil
+}
+
+// gamedataClassnames returns every classname in the furniture sections of doc.
+func gamedataClassnames(doc map[string]any) []string {
+	var names []string
+	furnitureAdp.EachGamedataItem(doc, func(_ string, item map[string]any) {
+		if name, ok := item["classname"].(string); ok {
+			names = append(names, name)
+		}
+	})
+	return names
+}
+
+// renameGamedata renames matching classnames in every furniture section of doc and
+// returns the old names.
+func renameGamedata(doc map[string]any, rule renameRule) []string {
+	renamed := []string{}
+	furnitureAdp.EachGamedataItem(doc, func(_ string, item map[string]any) {
+		name, _ := item["classname"].(string)
+		if updated, ok := rule.rename(name); ok {
+			item["classname"] = updated
+			renamed = append(renamed, name)
+		}
+	})
+	return renamed
+}
+
+// findRows returns the rows of table whose nameCol starts with prefix.
+func findRows(ctx context.Context, db *gorm.DB, table, idCol, nameCol, prefix string) ([]renameRow, error) {
+	var rows []renameRow
+	err := db.WithContext(ctx).
+		Table(table).
+		Select(idCol+" AS id, "+nameCol+" AS name").
+		Where("SUBSTR("+nameCol+", 1, ?) = ?", len(prefix), prefix).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", table, err)
+	}
+	return rows, nil
+}
+
+// findRenameRows returns the rows of table whose nameCol the rule renames.
+func findRenameRows(ctx context.Context, db *gorm.DB, table, idCol, nameCol string, rule renameRule) ([]renameRow, error) {
+	candidates, err := findRows(ctx, db, table, idCol, nameCol, rule.old)
+	if err != nil {
+		return nil, err
+	}
+	rows := candidates[:0]
+	for _, row := range candidates {
+		if _, ok := rule.rename(row.Name); ok {
+			rows = append(rows, row)
+		}
+	}
+	return rows, nil
+}
+
+// rowNames returns the names of rows.
+func rowNames(rows []renameRow) []string {
+	names := make([]string, 0, len(rows))
+	for _, row := range rows {
+		names = append(names, row.Name)
+	}
+	return names
+}
+
+// updateRenameRows renames the given rows one by one, keeping variant suffixes.
+func updateRenameRows(tx *gorm.DB, table, idCol, nameCol string, rows []renameRow, rule renameRule) error {
+	for _, row := range rows {
+		updated, _ := rule.rename(row.Name)
+		if err := tx.Table(table).Where(idCol+" = ?", row.ID).Update(nameCol, updated).Error; err != nil {
+			return fmt.Errorf("failed to rename %s row %d: %w", table, row.ID, err)
+		}
+	}
+	return nil
+}
+
+// listAssetClassnames returns the classnames of the top-level furniture assets whose
+// name starts with prefix.
+func listAssetClassnames(ctx context.Context, client storage.Client, bucket, prefix string) ([]string, error) {
+	opts := minio.ListObjectsOptions{Prefix: furnitureAdp.StoragePrefix + "/" + prefix}
+
+	var names []string
+	for obj := range client.ListObjects(ctx, bucket, opts) {
+		if obj.Err != nil {
+			return nil, fmt.Errorf("failed to list objects: %w", obj.Err)
+		}
+		name := strings.TrimPrefix(obj.Key, furnitureAdp.StoragePrefix+"/")
+		if strings.Contains(name, "/") || !strings.HasSuffix(name, furnitureAdp.StorageExtension) {
+			continue
+		}
+		names = append(names, strings.TrimSuffix(name, furnitureAdp.StorageExtension))
+	}
+	return names, nil
+}
+
+// copyObject copies an object within bucket by downloading and uploading it.
+func copyObject(ctx context.Context, client storage.Client, bucket, from, to string) error {
+	reader, err := client.GetObject(ctx, bucket, from, minio.GetObjectOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get %s: %w", from, err)
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", from, err)
+	}
+	if _, err := client.PutObject(ctx, bucket, to, bytes.NewReader(data), int64(len(data)), minio.PutObjectOptions{}); err != nil {
+		return fmt.Errorf("failed to write %s: %w", to, err)
+	}
+	return nil
+}
+
+// putJSON writes the furniture gamedata object.
+func putJSON(ctx context.Context, client storage.Client, bucket string, data []byte) error {
+	_, err := client.PutObject(ctx, bucket, furnitureAdp.GamedataObject, bytes.NewReader(data), int64(len(data)), minio.PutObjectOptions{ContentType: "application/json"})
+	return err
+}
@@ -0,0 +1,264 @@
+package integrity
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"asset-manager/core/json"
+	"asset-manager/core/reconcile"
+	"asset-manager/core/storage"
+	"asset-manager/core/storage/mocks"
+	"asset-manager/feature/furniture/models"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+const renameGamedataJSON = `{
+	"roomitemtypes": {"furnitype": [
+		{"id": 1, "classname": "old_chair", "name": "Chair", "revision": 5},
+		{"id": 2, "classname": "old_chair*2", "name": "Chair Red"},
+		{"id": 3, "classname": "old_chairs", "name": "Chairs"}
+	]},
+	"wallitemtypes": {"furnitype": []}
+}`
+
+// setupRenameDB creates an Arcturus items_base and catalog_items table in SQLite.
+func setupRenameDB(t *testing.T, name string) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", name)), &gorm.Config{})
+	require.NoError(t, err)
+
+	require.NoError(t, db.Exec(`CREATE TABLE items_base (id INTEGER PRIMARY KEY, sprite_id INTEGER, item_name VARCHAR(70))`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE catalog_items (id INTEGER PRIMARY KEY, catalog_name VARCHAR(100))`).Error)
+	require.NoError(t, db.Exec(`INSERT INTO items_base (id, sprite_id, item_name) VALUES (1, 1, 'old_chair'), (2, 2, 'old_chair*2'), (3, 3, 'old_chairs')`).Error)
+	require.NoError(t, db.Exec(`INSERT INTO catalog_items (id, catalog_name) VALUES (10, 'old_chair'), (11, 'sofa')`).Error)
+	return db
+}
+
+// mockRenameStorage sets up gamedata and the asset listing for a rename of old_chair.
+func mockRenameStorage(withAsset bool) *mocks.Client {
+	mockClient := new(mocks.Client)
+	mockClient.On("GetObject", mock.Anything, "test-bucket", "gamedata/FurnitureData.json", mock.Anything).
+		Return(io.NopCloser(strings.NewReader(renameGamedataJSON)), nil)
+
+	listing := func(prefix string, keys ...string) {
+		ch := make(chan minio.ObjectInfo, len(keys))
+		for _, key := range keys {
+			ch <- minio.ObjectInfo{Key: key}
+		}
+		close(ch)
+		mockClient.On("ListObjects", mock.Anything, "test-bucket", mock.MatchedBy(func(opts minio.ListObjectsOptions) bool {
+			return opts.Prefix == prefix
+		})).Return((<-chan minio.ObjectInfo)(ch))
+	}
+	listing("bundled/furniture/new_chair")
+	if withAsset {
+		listing("bundled/furniture/old_chair", "bundled/furniture/old_chair.nitro", "bundled/furniture/old_chairs.nitro")
+	} else {
+		listing("bundled/furniture/old_chair")
+	}
+	return mockClient
+}
+
+// mockLock lets the run lock be taken and released.
+func mockLock(mockClient *mocks.Client) {
+	mockClient.On("GetObject", mock.Anything, "test-bucket", reconcile.LockObject, mock.Anything).
+		Return(io.ReadCloser(nil), minio.ErrorResponse{Code: "NoSuchKey"})
+	mockClient.On("PutObject", mock.Anything, "test-bucket", reconcile.LockObject, mock.Anything, mock.Anything, mock.Anything).
+		Return(minio.UploadInfo{}, nil)
+	mockClient.On("RemoveObject", mock.Anything, "test-bucket", reconcile.LockObject, mock.Anything).Return(nil)
+}
+
+// recordRemovals records the keys passed to batch removals.
+func recordRemovals(mockClient *mocks.Client) *[]string {
+	removed := &[]string{}
+	mockClient.On("RemoveObjects", mock.Anything, "test-bucket", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			for obj := range args.Get(2).(<-chan minio.ObjectInfo) {
+				*removed = append(*removed, obj.Key)
+			}
+		}).
+		Return(nil)
+	return removed
+}
+
+func itemNames(t *testing.T, db *gorm.DB, table, column string) []string {
+	var names []string
+	require.NoError(t, db.Table(table).Order("id").Pluck(column, &names).Error)
+	return names
+}
+
+func TestRenameClassname_DryRun(t *testing.T) {
+	db := setupRenameDB(t, "rename_dry")
+	mockClient := mockRenameStorage(true)
+
+	plan, err := RenameClassname(context.Background(), mockClient, storage.SingleBucket("test-bucket"), db, "arcturus", "old_chair", "new_chair", true)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"old_chair": "new_chair", "old_chair*2": "new_chair*2"}, plan.Classnames)
+	assert.Equal(t, 2, plan.GamedataEntries)
+	assert.Equal(t, 2, plan.DBRows)
+	assert.Equal(t, 1, plan.CatalogRows)
+	assert.Equal(t, []models.RenameMove{{From: "bundled/furniture/old_chair.nitro", To: "bundled/furniture/new_chair.nitro"}}, plan.Storage)
+	assert.False(t, plan.Applied)
+
+	// Nothing was written
+	mockClient.AssertNotCalled(t, "PutObject", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	assert.Equal(t, []string{"old_chair", "old_chair*2", "old_chairs"}, itemNames(t, db, "items_base", "item_name"))
+}
+
+func TestRenameClassname_Apply(t *testing.T) {
+	db := setupRenameDB(t, "rename_apply")
+	mockClient := mockRenameStorage(true)
+	mockLock(mockClient)
+	removed := recordRemovals(mockClient)
+
+	// Asset copy
+	mockClient.On("GetObject", mock.Anything, "test-bucket", "bundled/furniture/old_chair.nitro", mock.Anything).
+		Return(io.NopCloser(strings.NewReader("nitro")), nil)
+	mockClient.On("PutObject", mock.Anything, "test-bucket", "bundled/furniture/new_chair.nitro", mock.Anything, int64(5), mock.Anything).
+		Return(minio.UploadInfo{}, nil)
+
+	// Gamedata rewrite
+	var written []byte
+	mockClient.On("PutObject", mock.Anything, "test-bucket", "gamedata/FurnitureData.json", mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			written, _ = io.ReadAll(args.Get(3).(io.Reader))
+		}).
+		Return(minio.UploadInfo{}, nil)
+
+	plan, err := RenameClassname(context.Background(), mockClient, storage.SingleBucket("test-bucket"), db, "arcturus", "old_chair", "new_chair", false)
+	require.NoError(t, err)
+	assert.True(t, plan.Applied)
+	assert.Empty(t, plan.Warnings)
+
+	assert.Equal(t, []string{"new_chair", "new_chair*2", "old_chairs"}, itemNames(t, db, "items_base", "item_name"))
+	assert.Equal(t, []string{"new_chair", "sofa"}, itemNames(t, db, "catalog_items", "catalog_name"))
+
+	var doc struct {
+		RoomItemTypes struct {
+			FurniType []map[string]any `json:"furnitype"`
+		} `json:"roomitemtypes"`
+	}
+	require.NoError(t, json.Unmarshal(written, &doc))
+	require.Len(t, doc.RoomItemTypes.FurniType, 3)
+	assert.Equal(t, "new_chair", doc.RoomItemTypes.FurniType[0]["classname"])
+	assert.Equal(t, float64(5), doc.RoomItemTypes.FurniType[0]["revision"], "unrelated fields are kept")
+	assert.Equal(t, "new_chair*2", doc.RoomItemTypes.FurniType[1]["classname"])
+	assert.Equal(t, "old_chairs", doc.RoomItemTypes.FurniType[2]["classname"])
+	assert.Equal(t, []string{"bundled/furniture/old_chair.nitro"}, *removed)
+}
+
+func TestRenameClassname_GamedataWriteFailsRollsBack(t *testing.T) {
+	db := setupRenameDB(t, "rename_rollback")
+	mockClient := mockRenameStorage(true)
+	mockLock(mockClient)
+	removed := recordRemovals(mockClient)
+
+	mockClient.On("GetObject", mock.Anything, "test-bucket", "bundled/furniture/old_chair.nitro", mock.Anything).
+		Return(io.NopCloser(strings.NewReader("nitro")), nil)
+	mockClient.On("PutObject", mock.Anything, "test-bucket", "bundled/furniture/new_chair.nitro", mock.Anything, mock.Anything, mock.Anything).
+		Return(minio.UploadInfo{}, nil)
+	mockClient.On("PutObject", mock.Anything, "test-bucket", "gamedata/FurnitureData.json", mock.Anything, mock.Anything, mock.Anything).
+		Return(minio.UploadInfo{}, assert.AnError)
+
+	plan, err := RenameClassname(context.Background(), mockClient, storage.SingleBucket("test-bucket"), db, "arcturus", "old_chair", "new_chair", false)
+	assert.ErrorContains(t, err, "failed to write gamedata")
+	assert.False(t, plan.Applied)
+
+	// Database rolled back, copied asset removed, old asset kept
+	assert.Equal(t, []string{"old_chair", "old_chair*2", "old_chairs"}, itemNames(t, db, "items_base", "item_name"))
+	assert.Equal(t, []string{"old_chair", "sofa"}, itemNames(t, db, "catalog_items", "catalog_name"))
+	assert.Equal(t, []string{"bundled/furniture/new_chair.nitro"}, *removed)
+}
+
+func TestRenameClassname_Conflicts(t *testing.T) {
+	t.Run("NewNameInGamedata", func(t *testing.T) {
+		db := setupRenameDB(t, "rename_conflict_gd")
+		_, err := RenameClassname(context.Background(), mockRenameStorage(true), storage.SingleBucket("test-bucket"), db, "arcturus", "old_chair", "old_chairs", true)
+		assert.ErrorContains(t, err, "already exist in gamedata: old_chairs")
+	})
+
+	t.Run("InvalidName", func(t *testing.T) {
+		db := setupRenameDB(t, "rename_conflict_name")
+		_, err := RenameClassname(context.Background(), new(mocks.Client), storage.SingleBucket("test-bucket"), db, "arcturus", "old_chair", "../evil", true)
+		assert.ErrorContains(t, err, "invalid classname")
+	})
+
+	t.Run("SameName", func(t *testing.T) {
+		db := setupRenameDB(t, "rename_conflict_same")
+		_, err := RenameClassname(context.Background(), new(mocks.Client), storage.SingleBucket("test-bucket"), db, "arcturus", "old_chair", "old_chair", true)
+		assert.Error(t, err)
+	})
+
+	t.Run("NoDatabase", func(t *testing.T) {
+		_, err := RenameClassname(context.Background(), new(mocks.Client), storage.SingleBucket("test-bucket"), nil, "arcturus", "old_chair", "new_chair", true)
+		assert.ErrorContains(t, err, "requires a database")
+	})
+}
+
+func TestRenamedClassname(t *testing.T) {
+	name, ok := renamedClassname("chair*12", "chair", "seat")
+	assert.True(t, ok)
+	assert.Equal(t, "seat*12", name)
+
+	_, ok = renamedClassname("chairs", "chair", "seat")
+	assert.False(t, ok)
+}
+
+func TestRenamePrefix(t *testing.T) {
+	db := setupRenameDB(t, "rename_prefix")
+	require.NoError(t, db.Exec(`INSERT INTO items_base (id, sprite_id, item_name) VALUES (4, 4, 'xmas14_tree'), (5, 5, 'xmas14_tree*3'), (6, 6, 'xmas15_tree')`).Error)
+
+	mockClient := new(mocks.Client)
+	mockClient.On("GetObject", mock.Anything, "test-bucket", "gamedata/FurnitureData.json", mock.Anything).
+		Return(io.NopCloser(strings.NewReader(`{"roomitemtypes": {"furnitype": [
+			{"id": 4, "classname": "xmas14_tree"},
+			{"id": 5, "classname": "xmas14_tree*3"},
+			{"id": 6, "classname": "xmas15_tree"}
+		]}, "wallitemtypes": {"furnitype": [{"id": 7, "classname": "xmas14_wreath"}]}}`)), nil)
+
+	oldCh := make(chan minio.ObjectInfo, 3)
+	oldCh <- minio.ObjectInfo{Key: "bundled/furniture/xmas14_tree.nitro"}
+	oldCh <- minio.ObjectInfo{Key: "bundled/furniture/xmas14_wreath.nitro"}
+	oldCh <- minio.ObjectInfo{Key: "bundled/furniture/xmas14_old/"}
+	close(oldCh)
+	mockClient.On("ListObjects", mock.Anything, "test-bucket", mock.MatchedBy(func(opts minio.ListObjectsOptions) bool {
+		return opts.Prefix == "bundled/furniture/xmas14_"
+	})).Return((<-chan minio.ObjectInfo)(oldCh))
+	newCh := make(chan minio.ObjectInfo)
+	close(newCh)
+	mockClient.On("ListObjects", mock.Anything, "test-bucket", mock.MatchedBy(func(opts minio.ListObjectsOptions) bool {
+		return opts.Prefix == "bundled/furniture/xmas_2014_"
+	})).Return((<-chan minio.ObjectInfo)(newCh))
+
+	plan, err := RenamePrefix(context.Background(), mockClient, storage.SingleBucket("test-bucket"), db, "arcturus", "xmas14_", "xmas_2014_", true)
+	require.NoError(t, err)
+	assert.True(t, plan.Prefix)
+	assert.Equal(t, map[string]string{
+		"xmas14_tree":   "xmas_2014_tree",
+		"xmas14_tree*3": "xmas_2014_tree*3",
+		"xmas14_wreath": "xmas_2014_wreath",
+	}, plan.Classnames)
+	assert.Equal(t, 3, plan.GamedataEntries)
+	assert.Equal(t, 2, plan.DBRows)
+	assert.Equal(t, []models.RenameMove{
+		{From: "bundled/furniture/xmas14_tree.nitro", To: "bundled/furniture/xmas_2014_tree.nitro"},
+		{From: "bundled/furniture/xmas14_wreath.nitro", To: "bundled/furniture/xmas_2014_wreath.nitro"},
+	}, plan.Storage)
+}
+
+func TestRenameRuleConflicts(t *testing.T) {
+	rule := renameRule{old: "xmas_", new: "xmas_2014_", prefix: true}
+	// xmas_2014_tree is renamed away itself, so it does not block xmas_tree
+	assert.Empty(t, rule.conflicts([]string{"xmas_2014_tree", "xmas_tree"}, []string{"xmas_2014_tree", "xmas_tree"}))
+
+	rule = renameRule{old: "a_", new: "b_", prefix: true}
+	assert.Equal(t, []string{"b_tree*2"}, rule.conflicts([]string{"a_tree", "b_tree*2"}, []string{"a_tree"}))
+}
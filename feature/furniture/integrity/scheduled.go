@@ -0,0 +1,144 @@
+package integrity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"asset-manager/core/reconcile"
+	"asset-manager/core/storage"
+	"asset-manager/feature/furniture/models"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// ReportKind is the kind furniture integrity reports are stored under.
+	ReportKind = "integrity/furniture"
+
+	// NewIssuesEvent names the webhook event of a scheduled check that found new issues.
+	NewIssuesEvent = "integrity.new_issues"
+)
+
+// ErrNoReportStore is returned when stored reports are read without a registered store.
+var ErrNoReportStore = errors.New("stored integrity reports require the state store (STATE_PATH)")
+
+// ReportStore persists the reports of scheduled integrity checks.
+type ReportStore interface {
+	// SaveReport stores report as the latest of kind.
+	SaveReport(ctx context.Context, kind string, at time.Time, report any) error
+
+	// LatestReport decodes the latest report of kind into report, returning false
+	// when there is none.
+	LatestReport(ctx context.Context, kind string, report any) (bool, error)
+}
+
+// reportRegistry holds the process-wide report store.
+type reportRegistry struct {
+	mu    sync.RWMutex
+	store ReportStore
+}
+
+// globalReports is the singleton report registry.
+var globalReports = &reportRegistry{}
+
+// SetReportStore registers the store scheduled check reports are kept in. A nil store
+// stops storing them.
+func SetReportStore(store ReportStore) {
+	globalReports.mu.Lock()
+	defer globalReports.mu.Unlock()
+	globalReports.store = store
+}
+
+// currentReportStore returns the registered report store, or nil.
+func currentReportStore() ReportStore {
+	globalReports.mu.RLock()
+	defer globalReports.mu.RUnlock()
+	return globalReports.store
+}
+
+// LatestReport returns the latest stored furniture integrity report, or nil when none
+// was stored yet. It returns ErrNoReportStore without a registered store.
+func LatestReport(ctx context.Context) (*models.Report, error) {
+	store := currentReportStore()
+	if store == nil {
+		return nil, ErrNoReportStore
+	}
+
+	var report models.Report
+	found, err := store.LatestReport(ctx, ReportKind, &report)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// ScheduledRun is the outcome of a scheduled furniture integrity check.
+type ScheduledRun struct {
+	// Report is the report of the run.
+	Report *models.Report
+
+	// Stored is true when the report was saved to the registered report store.
+	Stored bool
+
+	// Baseline is true when no earlier report was stored to compare with.
+	Baseline bool
+
+	// NewIssues lists the issues of Report the previous stored report did not have.
+	NewIssues []string
+}
+
+// IssuesEvent is the webhook payload announcing new issues.
+type IssuesEvent struct {
+	Event       string                `json:"event"`
+	RunID       string                `json:"run_id"`
+	GeneratedAt string                `json:"generated_at"`
+	NewIssues   []string              `json:"new_issues"`
+	Summary     reconcile.PlanSummary `json:"summary"`
+}
+
+// Event returns the webhook payload announcing the new issues of the run.
+func (r *ScheduledRun) Event() IssuesEvent {
+	return IssuesEvent{
+		Event:       NewIssuesEvent,
+		RunID:       r.Report.RunID,
+		GeneratedAt: r.Report.GeneratedAt,
+		NewIssues:   r.NewIssues,
+		Summary:     r.Report.Summary,
+	}
+}
+
+// RunScheduledCheck runs a full furniture integrity check and, when a report store is
+// registered, stores its report and lists the issues the previous report did not have.
+// The first stored report is the baseline and has no new issues.
+func RunScheduledCheck(ctx context.Context, client storage.Client, buckets storage.Buckets, db *gorm.DB, emulator string) (*ScheduledRun, error) {
+	report, err := CheckIntegrity(ctx, client, buckets, db, emulator)
+	if err != nil {
+		return nil, err
+	}
+	run := &ScheduledRun{Report: report}
+
+	store := currentReportStore()
+	if store == nil {
+		return run, nil
+	}
+
+	var previous models.Report
+	found, err := store.LatestReport(ctx, ReportKind, &previous)
+	if err != nil {
+		return run, err
+	}
+	if err := store.SaveReport(ctx, ReportKind, time.Now(), report); err != nil {
+		return run, fmt.Errorf("failed to store integrity report: %w", err)
+	}
+	run.Stored = true
+
+	if !found {
+		run.Baseline = true
+		return run, nil
+	}
+	run.NewIssues = report.NewIssues(&previous)
+	return run, nil
+}
@@ -0,0 +1,94 @@
+package integrity
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"asset-manager/core/json"
+	"asset-manager/core/storage"
+	"asset-manager/core/storage/mocks"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryReportStore keeps the latest report of each kind in memory.
+type memoryReportStore struct {
+	reports map[string][]byte
+}
+
+func (s *memoryReportStore) SaveReport(ctx context.Context, kind string, at time.Time, report any) error {
+	data, err := json.Marshal(report)
+	s.reports[kind] = data
+	return err
+}
+
+func (s *memoryReportStore) LatestReport(ctx context.Context, kind string, report any) (bool, error) {
+	data, ok := s.reports[kind]
+	if !ok {
+		return false, nil
+	}
+	return true, json.Unmarshal(data, report)
+}
+
+// scheduledClient returns a storage mock holding one gamedata item and the given objects.
+func scheduledClient(objects ...string) *mocks.Client {
+	gamedata := `{"roomitemtypes":{"furnitype":[{"id":100,"classname":"chair","name":"Chair"}]},"wallitemtypes":{"furnitype":[]}}`
+	client := new(mocks.Client)
+	client.On("BucketExists", mock.Anything, "test-bucket").Return(true, nil)
+	client.On("GetObject", mock.Anything, "test-bucket", "gamedata/FurnitureData.json", mock.Anything).
+		Return(io.NopCloser(strings.NewReader(gamedata)), nil)
+
+	objCh := make(chan minio.ObjectInfo, len(objects))
+	for _, key := range objects {
+		objCh <- minio.ObjectInfo{Key: key}
+	}
+	close(objCh)
+	client.On("ListObjects", mock.Anything, "test-bucket", mock.Anything).Return((<-chan minio.ObjectInfo)(objCh))
+	return client
+}
+
+// TestRunScheduledCheck tests the baseline run, new issues and the stored latest report.
+func TestRunScheduledCheck(t *testing.T) {
+	ctx := context.Background()
+	buckets := storage.SingleBucket("test-bucket")
+
+	// Without a store the report is returned but not kept
+	run, err := RunScheduledCheck(ctx, scheduledClient("bundled/furniture/chair.nitro"), buckets, nil, "arcturus")
+	require.NoError(t, err)
+	assert.False(t, run.Stored)
+	_, err = LatestReport(ctx)
+	assert.ErrorIs(t, err, ErrNoReportStore)
+
+	store := &memoryReportStore{reports: map[string][]byte{}}
+	SetReportStore(store)
+	defer SetReportStore(nil)
+
+	latest, err := LatestReport(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, latest)
+
+	run, err = RunScheduledCheck(ctx, scheduledClient("bundled/furniture/chair.nitro"), buckets, nil, "arcturus")
+	require.NoError(t, err)
+	assert.True(t, run.Stored)
+	assert.True(t, run.Baseline)
+	assert.Empty(t, run.NewIssues)
+
+	run, err = RunScheduledCheck(ctx, scheduledClient("bundled/furniture/chair.nitro", "bundled/furniture/lamp.nitro"), buckets, nil, "arcturus")
+	require.NoError(t, err)
+	assert.False(t, run.Baseline)
+	assert.Equal(t, []string{"unregistered_asset: lamp.nitro"}, run.NewIssues)
+
+	event := run.Event()
+	assert.Equal(t, NewIssuesEvent, event.Event)
+	assert.Equal(t, run.NewIssues, event.NewIssues)
+
+	latest, err = LatestReport(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"lamp.nitro"}, latest.UnregisteredAssets)
+}
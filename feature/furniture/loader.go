@@ -2,6 +2,7 @@ package furniture
 
 import (
 	"asset-manager/core/storage"
+	furnitureReconcile "asset-manager/feature/furniture/reconcile"
 
 	"github.com/gofiber/fiber/v2"
 	"go.uber.org/zap"
@@ -15,8 +16,8 @@ type Feature struct {
 }
 
 // NewFeature creates a new Furniture feature.
-func NewFeature(client storage.Client, bucket string, logger *zap.Logger, db *gorm.DB, emulator string) *Feature {
-	svc := NewService(client, bucket, logger, db, emulator)
+func NewFeature(client storage.Client, buckets storage.Buckets, logger *zap.Logger, db *gorm.DB, emulator string) *Feature {
+	svc := NewService(client, buckets, logger, db, emulator)
 	h := NewHandler(svc)
 	return &Feature{service: svc, handler: h}
 }
@@ -31,8 +32,9 @@ func (f *Feature) IsEnabled() bool {
 	return true
 }
 
-// Load registers the feature's routes.
+// Load registers the feature's routes and its reconcile spec.
 func (f *Feature) Load(app fiber.Router) error {
+	furnitureReconcile.Register(f.service.emulator, f.service.buckets.Gamedata, furnitureReconcile.DefaultCacheTTL)
 	f.handler.RegisterRoutes(app)
 	return nil
 }
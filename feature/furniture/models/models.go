@@ -2,18 +2,208 @@ package models
 
 import (
 	"strings"
+	"time"
+
+	"asset-manager/core/reconcile"
 )
 
 // Report contains the results of a furniture integrity check.
 type Report struct {
+	// RunID identifies the reconcile run the report was built from.
+	RunID               string   `json:"run_id,omitempty"`
 	TotalExpected       int      `json:"total_expected"`
 	TotalFound          int      `json:"total_found"`
 	MissingAssets       []string `json:"missing_assets"`
 	UnregisteredAssets  []string `json:"unregistered_assets"`
 	MalformedAssets     []string `json:"malformed_assets"`
 	ParameterMismatches []string `json:"parameter_mismatches,omitempty"`
-	GeneratedAt         string   `json:"generated_at"`
-	ExecutionTime       string   `json:"execution_time"`
+	// FieldWarnings lists differences of warning-severity fields such as the
+	// description; they are reported but not counted as issues.
+	FieldWarnings []string `json:"field_warnings,omitempty"`
+	GeneratedAt   string   `json:"generated_at"`
+	ExecutionTime string   `json:"execution_time"`
+	// FlappingItems lists items that keep oscillating between fixed and broken.
+	FlappingItems []string `json:"flapping_items,omitempty"`
+	// IgnoredItems lists items on the ignore list; they are left out of every other field.
+	IgnoredItems []IgnoredItem `json:"ignored_items,omitempty"`
+	// Triage maps item IDs to the staff workflow state recorded for their issues.
+	Triage map[string]reconcile.Triage `json:"triage,omitempty"`
+	// Summary holds the engine's aggregate counts, identical to the CLI and plan output.
+	Summary reconcile.PlanSummary `json:"summary"`
+	// Diagnostics lists problems of the sources themselves, such as several rows
+	// sharing a sprite_id; nil when there were none.
+	Diagnostics *reconcile.Diagnostics `json:"diagnostics,omitempty"`
+}
+
+// Issues returns every issue of the report, each prefixed by its category:
+// "missing_asset: ", "unregistered_asset: ", "malformed_asset: " or "parameter_mismatch: ".
+func (r *Report) Issues() []string {
+	issues := make([]string, 0, len(r.MissingAssets)+len(r.UnregisteredAssets)+len(r.MalformedAssets)+len(r.ParameterMismatches))
+	for _, category := range []struct {
+		name  string
+		items []string
+	}{
+		{"missing_asset", r.MissingAssets},
+		{"unregistered_asset", r.UnregisteredAssets},
+		{"malformed_asset", r.MalformedAssets},
+		{"parameter_mismatch", r.ParameterMismatches},
+	} {
+		for _, item := range category.items {
+			issues = append(issues, category.name+": "+item)
+		}
+	}
+	return issues
+}
+
+// NewIssues returns the issues of the report that previous did not have, in report order.
+func (r *Report) NewIssues(previous *Report) []string {
+	known := make(map[string]struct{})
+	for _, issue := range previous.Issues() {
+		known[issue] = struct{}{}
+	}
+
+	fresh := make([]string, 0)
+	for _, issue := range r.Issues() {
+		if _, ok := known[issue]; !ok {
+			fresh = append(fresh, issue)
+		}
+	}
+	return fresh
+}
+
+// QuickReport compares furniture counts across sources without a full reconcile.
+type QuickReport struct {
+	// Counts holds the item count per source (db, gamedata, storage). The database
+	// is absent when it is not configured.
+	Counts map[string]int `json:"counts"`
+	// DriftPercent is the spread between the largest and smallest count as a
+	// percentage of the largest.
+	DriftPercent float64 `json:"drift_percent"`
+	// Threshold is the drift percentage above which Alert is set.
+	Threshold float64 `json:"threshold"`
+	// Alert is true when DriftPercent exceeds Threshold.
+	Alert         bool   `json:"alert"`
+	GeneratedAt   string `json:"generated_at"`
+	ExecutionTime string `json:"execution_time"`
+}
+
+// IssueSummary is written next to a JSON issue dump, so scripts can read the counts
+// of a run without parsing its potentially huge issue array.
+type IssueSummary struct {
+	// IssueFile is the issue dump this summary belongs to.
+	IssueFile string `json:"issue_file"`
+	// ItemsWithIssues is the length of the issue array in IssueFile.
+	ItemsWithIssues int    `json:"items_with_issues"`
+	PlanID          string `json:"plan_id"`
+	RunID           string `json:"run_id"`
+	Emulator        string `json:"emulator"`
+	GeneratedAt     string `json:"generated_at"`
+	ExecutionTime   string `json:"execution_time"`
+	// Summary holds the counts of the run, as logged by the command.
+	Summary reconcile.PlanSummary `json:"summary"`
+}
+
+// RenamePlan describes a furniture classname rename across every source.
+type RenamePlan struct {
+	// Old and New are the renamed classname, or the prefixes when Prefix is set.
+	Old    string `json:"old"`
+	New    string `json:"new"`
+	Prefix bool   `json:"prefix,omitempty"`
+	// Classnames maps every classname found in any source, color variants included,
+	// to its new name.
+	Classnames map[string]string `json:"classnames"`
+	// GamedataEntries is the number of FurnitureData.json entries to rename.
+	GamedataEntries int `json:"gamedata_entries"`
+	// DBRows is the number of furniture table rows to rename.
+	DBRows int `json:"db_rows"`
+	// CatalogRows is the number of catalog_items rows whose catalog_name is renamed.
+	CatalogRows int `json:"catalog_rows"`
+	// Storage lists the asset files to move.
+	Storage []RenameMove `json:"storage"`
+	// Warnings lists sources that had nothing to rename or cleanup that failed.
+	Warnings []string `json:"warnings,omitempty"`
+	// Applied is true once every source was renamed.
+	Applied bool `json:"applied"`
+}
+
+// RenameMove is an asset file moved by a rename.
+type RenameMove struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// NameEncodingReport lists furniture whose public_name is its gamedata name garbled
+// by HTML entities or double UTF-8 encoding.
+type NameEncodingReport struct {
+	// Checked is the number of items present in both the database and gamedata.
+	Checked int `json:"checked"`
+	// Repairs lists the garbled names, sorted by key.
+	Repairs []NameRepair `json:"repairs"`
+	// Skipped explains why names were not checked, e.g. a missing public_name column.
+	Skipped string `json:"skipped,omitempty"`
+	// Applied is true once the repaired names were written.
+	Applied bool `json:"applied"`
+}
+
+// NameRepair is a garbled public_name and the gamedata name it is restored to.
+type NameRepair struct {
+	Key       string `json:"key"`
+	ID        int    `json:"id"`
+	ClassName string `json:"classname"`
+	Stored    string `json:"stored"`
+	Repaired  string `json:"repaired"`
+	// Issues lists the garblings undone, in the order they are undone
+	// (html_entities, double_encoded).
+	Issues []string `json:"issues"`
+}
+
+// FurnitureIssue describes a single furniture item with at least one integrity problem.
+// It is the shape written by the CLI JSON export.
+type FurnitureIssue struct {
+	ID              string   `json:"id"`
+	Name            string   `json:"name"`
+	GamedataMissing bool     `json:"gamedata_missing"`
+	StorageMissing  bool     `json:"storage_missing"`
+	DBMissing       bool     `json:"db_missing"`
+	Mismatch        []string `json:"mismatch"`
+	// Warnings lists differences of warning-severity fields.
+	Warnings    []string `json:"warnings,omitempty"`
+	Flapping    bool     `json:"flapping,omitempty"`
+	Transitions int      `json:"transitions,omitempty"`
+	// Triage is the staff workflow state recorded for the item, if any.
+	Triage *reconcile.Triage `json:"triage,omitempty"`
+}
+
+// IgnoredItem is a furniture item left out of reports by the ignore list, with the
+// problems it would otherwise have reported.
+type IgnoredItem struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	Reason    string     `json:"reason"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	// Missing lists the sources the item is missing from.
+	Missing  []string `json:"missing,omitempty"`
+	Mismatch []string `json:"mismatch,omitempty"`
+}
+
+// TriageRequest is the body of a triage request. Sending it without any field set
+// clears the item's triage.
+type TriageRequest struct {
+	// Key is the reconcile key of the item (its gamedata ID, or the storage path of unregistered files).
+	Key          string `json:"key"`
+	Acknowledged bool   `json:"acknowledged"`
+	AssignedTo   string `json:"assigned_to,omitempty"`
+	Note         string `json:"note,omitempty"`
+}
+
+// IgnoreRequest is the body of an ignore-list request.
+type IgnoreRequest struct {
+	// Key is the reconcile key of the item (its gamedata ID, or the storage path of unregistered files).
+	Key string `json:"key"`
+	// Reason explains why the item is ignored.
+	Reason string `json:"reason"`
+	// ExpiresAt ends the ignore; omit it to ignore the item indefinitely.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
 }
 
 // FurnitureDetailReport contains the detailed integrity check for a single item.
@@ -27,6 +217,17 @@ type FurnitureDetailReport struct {
 	InDB            bool     `json:"in_db"`
 	IntegrityStatus string   `json:"integrity_status"` // "PASS", "FAIL", "WARNING"
 	Mismatches      []string `json:"mismatches,omitempty"`
+	// Warnings lists differences of warning-severity fields such as the description.
+	Warnings []string `json:"warnings,omitempty"`
+	// NotComparable lists the fields not compared because the DB lacks their column.
+	NotComparable []string `json:"not_comparable,omitempty"`
+	// ExpectedAbsent lists the sources the emulator reserves the item without, e.g.
+	// storage for room ads. Missing there is not reported as a mismatch.
+	ExpectedAbsent []string `json:"expected_absent,omitempty"`
+	// SuggestedActions lists the repairs the plan builder would apply to this item.
+	SuggestedActions []reconcile.Action `json:"suggested_actions"`
+	// Triage is the staff workflow state recorded for the item, if any.
+	Triage *reconcile.Triage `json:"triage,omitempty"`
 }
 
 // FurnitureData represents the structure of FurniData.json
@@ -107,3 +107,25 @@ func TestFurnitureItem_Validate(t *testing.T) {
 		})
 	}
 }
+
+// TestReport_NewIssues tests that only issues absent from the previous report are new.
+func TestReport_NewIssues(t *testing.T) {
+	previous := &Report{
+		MissingAssets:       []string{"chair.nitro"},
+		ParameterMismatches: []string{"ID 5: width: gd=2 db=1"},
+	}
+	current := &Report{
+		MissingAssets:       []string{"chair.nitro", "table.nitro"},
+		UnregisteredAssets:  []string{"chair.nitro"},
+		ParameterMismatches: []string{"ID 5: width: gd=2 db=1"},
+	}
+
+	assert.Equal(t, []string{
+		"missing_asset: chair.nitro",
+		"missing_asset: table.nitro",
+		"unregistered_asset: chair.nitro",
+		"parameter_mismatch: ID 5: width: gd=2 db=1",
+	}, current.Issues())
+	assert.Equal(t, []string{"missing_asset: table.nitro", "unregistered_asset: chair.nitro"}, current.NewIssues(previous))
+	assert.Empty(t, previous.NewIssues(current))
+}
@@ -2,14 +2,17 @@ package reconcile
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"io"
+	"maps"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"asset-manager/core/database"
+	"asset-manager/core/json"
 	"asset-manager/core/reconcile"
 	"asset-manager/core/storage"
 	"asset-manager/core/utils"
@@ -27,34 +30,70 @@ type FurnitureAdapter struct {
 	mu            sync.RWMutex
 	// mappingReady signals when the classnameToID map is fully populated
 	mappingReady chan struct{}
+	// dbClassnameToID and dbIDToClassname map the item names of DB rows to their keys
+	// and back, for the storage objects of rows gamedata does not know
+	dbClassnameToID map[string]string
+	dbIDToClassname map[string]string
 
 	// Mutation context (stored for purge/sync operations)
 	db            *gorm.DB
 	client        storage.Client
-	bucket        string
+	buckets       storage.Buckets
 	storagePrefix string
 	serverProfile string
 	gamedataObj   string
 
 	// batchConcurrency allows overriding worker count (default 50)
 	batchConcurrency int
+
+	// storageExtension is the extension of the storage objects mutations write and delete
+	storageExtension string
+
+	// ignoredFields lists the fields the configured settings exclude from comparison
+	ignoredFields []string
+
+	// reserved holds the reserved items of the profile last loaded or queried (see
+	// ServerProfile.Reserved), guarded by mu
+	reserved []string
+
+	// removeRetry controls retries of failed storage deletions
+	removeRetry storage.RemoveRetry
+
+	// missingColumns holds the mapped columns the table lacked when the DB index was
+	// last loaded; syncs leave them out of their updates
+	missingColumns map[string]struct{}
+	columnsMu      sync.RWMutex
+
+	// conversions holds the conversion log by classname when SetConversionLog is set,
+	// loaded with the storage set
+	conversions   map[string]Conversion
+	conversionsMu sync.RWMutex
 }
 
-// NewAdapter creates a new furniture adapter.
+// NewAdapter creates a new furniture adapter, tuned by the configured settings (see
+// Settings).
 func NewAdapter() *FurnitureAdapter {
+	settings := Settings()
 	return &FurnitureAdapter{
-		classnameToID: make(map[string]string),
-		idToClassname: make(map[string]string),
-		mappingReady:  make(chan struct{}),
+		classnameToID:    make(map[string]string),
+		idToClassname:    make(map[string]string),
+		mappingReady:     make(chan struct{}),
+		dbClassnameToID:  make(map[string]string),
+		dbIDToClassname:  make(map[string]string),
+		removeRetry:      storage.DefaultRemoveRetry,
+		batchConcurrency: settings.Concurrency,
+		storageExtension: AssetExtension(),
+		ignoredFields:    settings.IgnoreFields,
 	}
 }
 
 // SetMutationContext stores database, storage client, and configuration for mutation operations.
 // This must be called before using DeleteDB, DeleteStorage, DeleteGamedata, or SyncDBFromGamedata.
-func (a *FurnitureAdapter) SetMutationContext(db *gorm.DB, client storage.Client, bucket, prefix, serverProfile, gamedataObj string) {
+// Gamedata writes go to buckets.Gamedata and storage deletions to buckets.Assets.
+func (a *FurnitureAdapter) SetMutationContext(db *gorm.DB, client storage.Client, buckets storage.Buckets, prefix, serverProfile, gamedataObj string) {
 	a.db = db
 	a.client = client
-	a.bucket = bucket
+	a.buckets = buckets
 	a.storagePrefix = prefix
 	a.serverProfile = serverProfile
 	a.gamedataObj = gamedataObj
@@ -65,42 +104,77 @@ func (a *FurnitureAdapter) SetMutationContext(db *gorm.DB, client storage.Client
 	}
 }
 
+// ignoresField reports whether the adapter settings exclude field from comparison.
+// "dimensions" stands for width and length, as in the field policy.
+func (a *FurnitureAdapter) ignoresField(field string) bool {
+	if slices.Contains(a.ignoredFields, field) {
+		return true
+	}
+	return (field == "width" || field == "length") && slices.Contains(a.ignoredFields, "dimensions")
+}
+
+// objectKey returns the storage key of the file of classname under the mutation prefix.
+func (a *FurnitureAdapter) objectKey(classname string) string {
+	return fmt.Sprintf("%s/%s%s", a.storagePrefix, classname, a.storageExtension)
+}
+
 // SetBatchConcurrency sets the number of concurrent workers for batch operations.
 // Set to 1 for sequential execution (useful for SQLite tests).
 func (a *FurnitureAdapter) SetBatchConcurrency(n int) {
 	a.batchConcurrency = n
 }
 
+// SetRemoveRetry sets how retriable storage deletion failures are retried.
+func (a *FurnitureAdapter) SetRemoveRetry(retry storage.RemoveRetry) {
+	a.removeRetry = retry
+}
+
 // Name returns the unique name of this adapter.
 func (a *FurnitureAdapter) Name() string {
-	return "furniture"
+	return AdapterName
+}
+
+// TableName returns the furniture table of the server profile (reconcile.TableNamer).
+func (a *FurnitureAdapter) TableName(serverProfile string) string {
+	return GetProfileByName(serverProfile).TableName
 }
 
 // DBItem represents a normalized database furniture item.
 type DBItem struct {
-	ID         int
-	SpriteID   int
-	ItemName   string
-	PublicName string
-	Width      int
-	Length     int
-	CanSit     bool
-	CanWalk    bool
-	CanLay     bool
-	Type       string
+	ID          int
+	SpriteID    int
+	ItemName    string
+	PublicName  string
+	Width       int
+	Length      int
+	StackHeight float64
+	CanSit      bool
+	CanWalk     bool
+	CanLay      bool
+	Type        string
+	Description string
+
+	// NotCompared lists the compared fields (e.g. "width") whose column the table lacks
+	// or the profile does not map. Their values above are zero and never compared.
+	NotCompared []string
 }
 
 // GDItem represents a gamedata furniture item.
 type GDItem struct {
-	ID         int    `json:"id"`
-	ClassName  string `json:"classname"`
-	Name       string `json:"name"`
-	XDim       int    `json:"xdim"`
-	YDim       int    `json:"ydim"`
-	CanSitOn   bool   `json:"cansiton"`
-	CanStandOn bool   `json:"canstandon"`
-	CanLayOn   bool   `json:"canlayon"`
-	Type       string `json:"-"` // "s" for room items, "i" for wall items
+	ID          int    `json:"id"`
+	ClassName   string `json:"classname"`
+	Name        string `json:"name"`
+	XDim        int    `json:"xdim"`
+	YDim        int    `json:"ydim"`
+	CanSitOn    bool   `json:"cansiton"`
+	CanStandOn  bool   `json:"canstandon"`
+	CanLayOn    bool   `json:"canlayon"`
+	Description string `json:"description"`
+	OfferID     int    `json:"offerid"`
+	Buyout      bool   `json:"buyout"`
+	RentOfferID int    `json:"rentofferid"`
+	RentBuyout  bool   `json:"rentbuyout"`
+	Type        string `json:"-"` // "s" for room items, "i" for wall items
 }
 
 // FurnitureData represents the structure of FurnitureData.json.
@@ -123,6 +197,7 @@ func (a *FurnitureAdapter) LoadDBIndex(ctx context.Context, db *gorm.DB, serverP
 	}
 
 	profile := GetProfileByName(serverProfile)
+	a.setReserved(profile.Reserved)
 
 	// Build query based on server profile
 	tableName := profile.TableName
@@ -141,7 +216,17 @@ func (a *FurnitureAdapter) LoadDBIndex(ctx context.Context, db *gorm.DB, serverP
 		return nil, fmt.Errorf("failed to get columns: %w", err)
 	}
 
+	// Forks may lack optional columns: report them once and skip their comparisons
+	missing, notCompared := profile.columnGaps(columns)
+	if spriteCol := profile.Columns[ColSpriteID]; slices.Contains(missing, spriteCol) {
+		// Without the key column every row would collide on key 0
+		return nil, fmt.Errorf("%s lacks the key column %s", tableName, spriteCol)
+	}
+	reconcile.RecordMissingColumns(ctx, reconcile.SourceDB, tableName, missing...)
+	a.setMissingColumns(missing)
+
 	// Parse rows into DBItem
+	classnames := make(map[string]string)
 	for dbRows.Next() {
 		// Create a map to scan into
 		values := make([]any, len(columns))
@@ -159,60 +244,38 @@ func (a *FurnitureAdapter) LoadDBIndex(ctx context.Context, db *gorm.DB, serverP
 		for i, col := range columns {
 			row[col] = values[i]
 		}
-		item := DBItem{}
-
-		// Extract values using profile column mappings
-		if id, ok := row[profile.Columns[ColID]]; ok {
-			item.ID = utils.ToInt(id)
-		}
-		if spriteID, ok := row[profile.Columns[ColSpriteID]]; ok {
-			item.SpriteID = utils.ToInt(spriteID)
-		}
-		if itemName, ok := row[profile.Columns[ColItemName]]; ok {
-			item.ItemName = utils.ToString(itemName)
-		}
-		if publicName, ok := row[profile.Columns[ColPublicName]]; ok {
-			item.PublicName = utils.ToString(publicName)
-		}
-		if width, ok := row[profile.Columns[ColWidth]]; ok {
-			item.Width = utils.ToInt(width)
-		}
-		if length, ok := row[profile.Columns[ColLength]]; ok {
-			item.Length = utils.ToInt(length)
-		}
-
-		// Boolean fields (handle different types)
-		if canSitCol, ok := profile.Columns[ColCanSit]; ok {
-			if val, exists := row[canSitCol]; exists {
-				item.CanSit = utils.ToBool(val)
-			}
-		}
-		if canWalkCol, ok := profile.Columns[ColCanWalk]; ok {
-			if val, exists := row[canWalkCol]; exists {
-				item.CanWalk = utils.ToBool(val)
-			}
-		}
-		if canLayCol, ok := profile.Columns[ColCanLay]; ok {
-			if val, exists := row[canLayCol]; exists {
-				item.CanLay = utils.ToBool(val)
-			}
-		}
-
-		// Load Type
-		if typeCol, ok := profile.Columns[ColType]; ok {
-			if val, exists := row[typeCol]; exists {
-				item.Type = utils.ToString(val)
-			}
-		}
+		item := a.parseDBRow(row, profile)
+		item.NotCompared = notCompared
 
 		// Use sprite_id as key (sprite_id matches gamedata id, not database id)
 		key := strconv.Itoa(item.SpriteID)
+		if prev, taken := index[key]; taken {
+			reconcile.RecordConflict(ctx, reconcile.SourceDB, key, describeDBItem(prev.(DBItem)), describeDBItem(item))
+		}
 		index[key] = item
+		classnames[key] = item.ItemName
 	}
+	a.setDBClassnames(classnames)
 
 	return index, nil
 }
 
+// setDBClassnames replaces the item names of DB rows by key. Numeric names are left
+// out, as they could not be told apart from keys.
+func (a *FurnitureAdapter) setDBClassnames(classnames map[string]string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.dbClassnameToID = make(map[string]string, len(classnames))
+	a.dbIDToClassname = make(map[string]string, len(classnames))
+	for key, classname := range classnames {
+		if _, err := strconv.Atoi(classname); err == nil || classname == "" {
+			continue
+		}
+		a.dbClassnameToID[classname] = key
+		a.dbIDToClassname[key] = classname
+	}
+}
+
 // LoadGamedataIndex loads furniture items from gamedata JSON.
 func (a *FurnitureAdapter) LoadGamedataIndex(ctx context.Context, client storage.Client, bucket, objectName string, paths []string) (map[string]reconcile.GDItem, error) {
 	// Download gamedata JSON
@@ -240,15 +303,30 @@ func (a *FurnitureAdapter) LoadGamedataIndex(ctx context.Context, client storage
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
+	// owners holds the first key of each classname, to report classnames shared by
+	// several IDs; the last one wins the mapping
+	owners := make(map[string]string)
+	mapClassname := func(key, classname string) {
+		if owner, taken := owners[classname]; taken && owner != key {
+			reconcile.RecordDuplicateName(ctx, reconcile.SourceGamedata, classname, owner, key)
+		} else if !taken {
+			owners[classname] = key
+		}
+		// Map classname directly to ID (classname in gamedata matches filename)
+		a.classnameToID[classname] = key
+		a.idToClassname[key] = classname
+	}
+
 	// Process room items
 	for _, item := range furniData.RoomItemTypes.FurniType {
 		if item.ID > 0 && item.ClassName != "" {
 			item.Type = "s" // Floor item
 			key := strconv.Itoa(item.ID)
+			if prev, taken := index[key]; taken {
+				reconcile.RecordConflict(ctx, reconcile.SourceGamedata, key, describeGDItem(prev.(GDItem)), describeGDItem(item))
+			}
 			index[key] = item
-			// Map classname directly to ID (classname in gamedata matches filename)
-			a.classnameToID[item.ClassName] = key
-			a.idToClassname[key] = item.ClassName
+			mapClassname(key, item.ClassName)
 		}
 	}
 
@@ -257,10 +335,11 @@ func (a *FurnitureAdapter) LoadGamedataIndex(ctx context.Context, client storage
 		if item.ID > 0 && item.ClassName != "" {
 			item.Type = "i" // Wall item
 			key := strconv.Itoa(item.ID)
+			if prev, taken := index[key]; taken {
+				reconcile.RecordConflict(ctx, reconcile.SourceGamedata, key, describeGDItem(prev.(GDItem)), describeGDItem(item))
+			}
 			index[key] = item
-			// Map classname directly to ID (classname in gamedata matches filename)
-			a.classnameToID[item.ClassName] = key
-			a.idToClassname[key] = item.ClassName
+			mapClassname(key, item.ClassName)
 		}
 	}
 
@@ -292,6 +371,12 @@ func (a *FurnitureAdapter) LoadStorageSet(ctx context.Context, client storage.Cl
 
 	set := make(map[string]struct{})
 	var mu sync.Mutex
+	// objects holds the object each key was first seen on, to report collisions
+	objects := make(map[string]string)
+	// nested holds the first nested object of each key, canonical the keys seen at
+	// their canonical path; nested-only keys are reported as misplaced
+	nested := make(map[string]string)
+	canonical := make(map[string]bool)
 
 	// List all objects under prefix
 	opts := minio.ListObjectsOptions{
@@ -299,22 +384,63 @@ func (a *FurnitureAdapter) LoadStorageSet(ctx context.Context, client storage.Cl
 		Recursive: true,
 	}
 
+	listed := 0
 	for obj := range client.ListObjects(ctx, bucket, opts) {
 		if obj.Err != nil {
 			return nil, fmt.Errorf("failed to list objects: %w", obj.Err)
 		}
+		listed++
+		reconcile.ReportListed(ctx, listed)
 
 		// Extract key from object
 		if key, ok := a.ExtractStorageKey(obj.Key, prefix, extension); ok {
 			mu.Lock()
+			if first, taken := objects[key]; taken {
+				reconcile.RecordConflict(ctx, reconcile.SourceStorage, key, first, obj.Key)
+			} else {
+				objects[key] = obj.Key
+			}
+			if isNested(obj.Key, prefix) {
+				if _, taken := nested[key]; !taken {
+					nested[key] = obj.Key
+				}
+			} else {
+				canonical[key] = true
+			}
 			set[key] = struct{}{}
 			mu.Unlock()
+			reconcile.RecordModified(ctx, key, obj.LastModified)
 		}
 	}
 
+	for key, object := range nested {
+		// Nested files without a known classname keep their path as key and cannot move
+		if _, known := a.idToClassnameOf(key); known && !canonical[key] {
+			reconcile.RecordMisplaced(ctx, key, object)
+		}
+	}
+
+	if err := a.loadConversions(ctx, client, bucket); err != nil {
+		return nil, err
+	}
+
 	return set, nil
 }
 
+// describeDBItem identifies a furniture row in a key conflict.
+func describeDBItem(item DBItem) string {
+	return fmt.Sprintf("id=%d item_name=%s", item.ID, item.ItemName)
+}
+
+// describeGDItem identifies a gamedata entry in a key conflict.
+func describeGDItem(item GDItem) string {
+	kind := "room"
+	if item.Type == "i" {
+		kind = "wall"
+	}
+	return fmt.Sprintf("%s classname=%s", kind, item.ClassName)
+}
+
 // ExtractDBKey returns the entity key from a DB item.
 func (a *FurnitureAdapter) ExtractDBKey(item reconcile.DBItem) string {
 	dbItem := item.(DBItem)
@@ -378,6 +504,42 @@ func (a *FurnitureAdapter) ExtractStorageKey(objectKey, prefix, extension string
 	return relPathNoExt, true
 }
 
+// isNested reports whether an object lies in a subfolder of the storage prefix rather
+// than directly under it.
+func isNested(objectKey, prefix string) bool {
+	return strings.Contains(strings.TrimPrefix(strings.TrimPrefix(objectKey, prefix), "/"), "/")
+}
+
+// idToClassnameOf returns the canonical classname of a furniture ID: its gamedata
+// classname, or for items gamedata does not know, the item_name of its DB row.
+func (a *FurnitureAdapter) idToClassnameOf(key string) (string, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if classname, ok := a.idToClassname[key]; ok {
+		return classname, true
+	}
+	classname, ok := a.dbIDToClassname[key]
+	return classname, ok
+}
+
+// NormalizeKey maps the storage key of an object named after the item_name of a DB row
+// that gamedata does not know to the row's key (reconcile.KeyNormalizer), so such
+// items meet their storage object. Storage objects of gamedata items are keyed by ID
+// when listed; every other key is returned unchanged.
+func (a *FurnitureAdapter) NormalizeKey(key string) string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if _, known := a.classnameToID[key]; known {
+		return key
+	}
+	if id, ok := a.dbClassnameToID[key]; ok {
+		if _, mapped := a.idToClassname[id]; !mapped {
+			return id
+		}
+	}
+	return key
+}
+
 // ResolveName returns the display name for an entity.
 func (a *FurnitureAdapter) ResolveName(dbItem reconcile.DBItem, gdItem reconcile.GDItem) string {
 	if dbItem != nil {
@@ -389,7 +551,8 @@ func (a *FurnitureAdapter) ResolveName(dbItem reconcile.DBItem, gdItem reconcile
 	return ""
 }
 
-// GetMetadata returns classname for furniture.
+// GetMetadata returns classname for furniture, and the conversion status of its legacy
+// .swf file when the conversion log records one.
 func (a *FurnitureAdapter) GetMetadata(dbItem reconcile.DBItem, gdItem reconcile.GDItem) map[string]string {
 	meta := make(map[string]string)
 
@@ -403,10 +566,28 @@ func (a *FurnitureAdapter) GetMetadata(dbItem reconcile.DBItem, gdItem reconcile
 
 	if val != "" {
 		meta["classname"] = val
+		if status, ok := a.conversionStatus(val); ok {
+			meta["swf_conversion"] = status
+		}
 	}
 	return meta
 }
 
+// SearchTerms returns the classnames and display names of both sides (reconcile.Searchable),
+// so an item is found by its gamedata name even when the DB row was renamed.
+func (a *FurnitureAdapter) SearchTerms(dbItem reconcile.DBItem, gdItem reconcile.GDItem) []string {
+	var terms []string
+	if gdItem != nil {
+		gd := gdItem.(GDItem)
+		terms = append(terms, gd.ClassName, gd.Name)
+	}
+	if dbItem != nil {
+		db := dbItem.(DBItem)
+		terms = append(terms, db.ItemName, db.PublicName)
+	}
+	return terms
+}
+
 // CompareFields compares DB and gamedata items and returns mismatch descriptions.
 func (a *FurnitureAdapter) CompareFields(dbItem reconcile.DBItem, gdItem reconcile.GDItem) []string {
 	db := dbItem.(DBItem)
@@ -417,38 +598,69 @@ func (a *FurnitureAdapter) CompareFields(dbItem reconcile.DBItem, gdItem reconci
 	// Compare name
 	// Relaxed check: Accept if DB PublicName matches GD Name OR GD ClassName
 	// (Common in emulators to use classname as public_name default)
-	if db.PublicName != gd.Name && db.PublicName != gd.ClassName {
-		mismatches = append(mismatches, fmt.Sprintf("name: gd='%s' db='%s'", gd.Name, db.PublicName))
+	// after the configured normalization (see reconcile.SetNameNormalization)
+	// Fields whose column is missing hold zero values and are skipped (see NotComparable)
+	// Fields the field policy or the adapter settings ignore are skipped as well (see
+	// reconcile.FieldPolicy and Settings)
+	compared := func(field string) bool {
+		return !slices.Contains(db.NotCompared, field) && !reconcile.IgnoresField(field) && !a.ignoresField(field)
+	}
+
+	names := reconcile.Names()
+	if compared("name") && !names.Equal(db.PublicName, gd.Name) && !names.Equal(db.PublicName, gd.ClassName) {
+		mismatch := fmt.Sprintf("name: gd='%s' db='%s'", gd.Name, db.PublicName)
+		// Flag names that are the gamedata name garbled, which RepairName can undo
+		if issues, ok := RepairName(db.PublicName, gd.Name); ok {
+			mismatch += fmt.Sprintf(" (encoding: %s)", FormatIssues(issues))
+		}
+		mismatches = append(mismatches, mismatch)
 	}
 
 	// Compare classname
-	if db.ItemName != gd.ClassName {
+	if compared("classname") && db.ItemName != gd.ClassName {
 		mismatches = append(mismatches, fmt.Sprintf("classname: gd='%s' db='%s'", gd.ClassName, db.ItemName))
 	}
 
 	// Compare dimensions
-	if db.Width != gd.XDim {
+	if compared("width") && db.Width != gd.XDim {
 		mismatches = append(mismatches, fmt.Sprintf("width: gd=%d db=%d", gd.XDim, db.Width))
 	}
-	if db.Length != gd.YDim {
+	if compared("length") && db.Length != gd.YDim {
 		mismatches = append(mismatches, fmt.Sprintf("length: gd=%d db=%d", gd.YDim, db.Length))
 	}
 
 	// Compare boolean flags
-	if db.CanSit != gd.CanSitOn {
+	if compared("can_sit") && db.CanSit != gd.CanSitOn {
 		mismatches = append(mismatches, fmt.Sprintf("can_sit: gd=%v db=%v", gd.CanSitOn, db.CanSit))
 	}
-	if db.CanWalk != gd.CanStandOn {
+	if compared("can_walk") && db.CanWalk != gd.CanStandOn {
 		mismatches = append(mismatches, fmt.Sprintf("can_walk: gd=%v db=%v", gd.CanStandOn, db.CanWalk))
 	}
-	if db.CanLay != gd.CanLayOn {
+	if compared("can_lay") && db.CanLay != gd.CanLayOn {
 		mismatches = append(mismatches, fmt.Sprintf("can_lay: gd=%v db=%v", gd.CanLayOn, db.CanLay))
 	}
 
+	// Descriptions are compared where the profile maps a column, at warning severity
+	// (see FieldSeverity)
+	if compared("description") && db.Description != gd.Description {
+		mismatches = append(mismatches, fmt.Sprintf("description: gd='%s' db='%s'", gd.Description, db.Description))
+	}
+
+	// Wall items with a footprint or floor flags are reported at warning severity (see
+	// FieldSeverity) and normalized by syncs when enabled
+	if compared(WallPlacementField) {
+		if issues := wallPlacementIssues(db); len(issues) > 0 {
+			mismatches = append(mismatches, fmt.Sprintf("%s: db='i' (wall) with %s", WallPlacementField, strings.Join(issues, " ")))
+		}
+	}
+
 	// Compare Type (Wall vs Floor)
 	// If DB type is "i", it MUST be a wall item in gamedata (Type="i").
 	// If DB type is NOT "i", it is generally a room item.
 	// Note: DB might use other letters for floor items (s, e, r, etc.), but 'i' is exclusively wall.
+	if !compared("type") {
+		return mismatches
+	}
 	if db.Type == "i" {
 		if gd.Type != "i" {
 			mismatches = append(mismatches, "type: gd='room' (WallItemTypes=false) db='i' (wall)")
@@ -463,9 +675,101 @@ func (a *FurnitureAdapter) CompareFields(dbItem reconcile.DBItem, gdItem reconci
 	return mismatches
 }
 
+// NotComparable returns the fields CompareFields skipped because the DB lacks their
+// column (reconcile.PartialComparer). Optional fields are left out: most profiles
+// do not map them.
+func (a *FurnitureAdapter) NotComparable(dbItem reconcile.DBItem, gdItem reconcile.GDItem) []string {
+	var fields []string
+	for _, field := range dbItem.(DBItem).NotCompared {
+		if !optionalField(field) {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// FieldSeverity rates description mismatches as warnings: the client shows the
+// gamedata text, so a stale DB copy is cosmetic. Wall placement issues are warnings
+// too: emulators place wall items by their wall position, not their footprint
+// (reconcile.SeverityRater).
+func (a *FurnitureAdapter) FieldSeverity(field string) reconcile.Severity {
+	if field == "description" || field == WallPlacementField {
+		return reconcile.SeverityWarning
+	}
+	return reconcile.SeverityError
+}
+
+// comparedColumns maps each field CompareFields compares to the logical column it
+// reads from the DB. Optional fields are only compared where the profile maps them and
+// are not reported as gaps.
+var comparedColumns = []struct {
+	field, column string
+	optional      bool
+}{
+	{"name", ColPublicName, false},
+	{"classname", ColItemName, false},
+	{"width", ColWidth, false},
+	{"length", ColLength, false},
+	{"can_sit", ColCanSit, false},
+	{"can_walk", ColCanWalk, false},
+	{"can_lay", ColCanLay, false},
+	{"type", ColType, false},
+	{"description", ColDescription, true},
+}
+
+// columnGaps checks the profile against the columns present in its table. It returns
+// the mapped columns the table lacks, sorted, and the compared fields whose column is
+// missing or not mapped at all.
+func (p ServerProfile) columnGaps(present []string) (missing, notCompared []string) {
+	for _, col := range p.Columns {
+		if !slices.Contains(present, col) && !slices.Contains(missing, col) {
+			missing = append(missing, col)
+		}
+	}
+	slices.Sort(missing)
+
+	for _, c := range comparedColumns {
+		col, mapped := p.Columns[c.column]
+		if !mapped || slices.Contains(missing, col) {
+			notCompared = append(notCompared, c.field)
+		}
+	}
+	return missing, notCompared
+}
+
+// optionalField reports whether field is an optional compared field.
+func optionalField(field string) bool {
+	for _, c := range comparedColumns {
+		if c.field == field {
+			return c.optional
+		}
+	}
+	return false
+}
+
+// setMissingColumns records the mapped columns the table lacks, for later syncs.
+func (a *FurnitureAdapter) setMissingColumns(columns []string) {
+	missing := make(map[string]struct{}, len(columns))
+	for _, col := range columns {
+		missing[col] = struct{}{}
+	}
+	a.columnsMu.Lock()
+	a.missingColumns = missing
+	a.columnsMu.Unlock()
+}
+
+// columnMissing reports whether the table lacked col when the DB index was last loaded.
+func (a *FurnitureAdapter) columnMissing(col string) bool {
+	a.columnsMu.RLock()
+	defer a.columnsMu.RUnlock()
+	_, missing := a.missingColumns[col]
+	return missing
+}
+
 // QueryDB performs a targeted database lookup.
 func (a *FurnitureAdapter) QueryDB(ctx context.Context, db *gorm.DB, serverProfile string, query reconcile.Query) (reconcile.DBItem, error) {
 	profile := GetProfileByName(serverProfile)
+	a.setReserved(profile.Reserved)
 
 	var row map[string]any
 	tableName := profile.TableName
@@ -483,7 +787,7 @@ func (a *FurnitureAdapter) QueryDB(ctx context.Context, db *gorm.DB, serverProfi
 					return nil, result.Error
 				}
 			} else if result.RowsAffected > 0 {
-				return a.parseDBRow(row, profile), nil
+				return a.parseQueriedRow(row, profile), nil
 			}
 		}
 	}
@@ -493,7 +797,7 @@ func (a *FurnitureAdapter) QueryDB(ctx context.Context, db *gorm.DB, serverProfi
 		row = make(map[string]any)
 		result := db.WithContext(ctx).Table(tableName).Where(profile.Columns[ColItemName]+" = ?", query.Classname).Take(&row)
 		if result.Error == nil && result.RowsAffected > 0 {
-			return a.parseDBRow(row, profile), nil
+			return a.parseQueriedRow(row, profile), nil
 		} else if result.Error != nil && result.Error != gorm.ErrRecordNotFound {
 			return nil, result.Error
 		}
@@ -504,7 +808,7 @@ func (a *FurnitureAdapter) QueryDB(ctx context.Context, db *gorm.DB, serverProfi
 		row = make(map[string]any)
 		result := db.WithContext(ctx).Table(tableName).Where(profile.Columns[ColPublicName]+" = ?", query.Name).Take(&row)
 		if result.Error == nil && result.RowsAffected > 0 {
-			return a.parseDBRow(row, profile), nil
+			return a.parseQueriedRow(row, profile), nil
 		} else if result.Error != nil && result.Error != gorm.ErrRecordNotFound {
 			return nil, result.Error
 		}
@@ -546,10 +850,7 @@ func (a *FurnitureAdapter) QueryGamedata(ctx context.Context, client storage.Cli
 func (a *FurnitureAdapter) CheckStorage(ctx context.Context, client storage.Client, bucket, prefix, extension string, key string) (bool, error) {
 	// For furniture, the key is the ID, but storage uses classname
 	// We check the idToClassname mapping.
-
-	a.mu.RLock()
-	classname, ok := a.idToClassname[key]
-	a.mu.RUnlock()
+	classname, ok := a.idToClassnameOf(key)
 
 	filename := key
 	if ok {
@@ -576,6 +877,14 @@ func (a *FurnitureAdapter) CheckStorage(ctx context.Context, client storage.Clie
 	return false, nil
 }
 
+// parseQueriedRow converts a row of a targeted lookup to a DBItem, skipping the
+// comparison of fields whose column the row lacks.
+func (a *FurnitureAdapter) parseQueriedRow(row map[string]any, profile ServerProfile) DBItem {
+	item := a.parseDBRow(row, profile)
+	_, item.NotCompared = profile.columnGaps(slices.Collect(maps.Keys(row)))
+	return item
+}
+
 // parseDBRow converts a raw DB row to a DBItem.
 func (a *FurnitureAdapter) parseDBRow(row map[string]any, profile ServerProfile) DBItem {
 	item := DBItem{}
@@ -599,19 +908,35 @@ func (a *FurnitureAdapter) parseDBRow(row map[string]any, profile ServerProfile)
 		item.Length = utils.ToInt(length)
 	}
 
+	if stackHeight, ok := row[profile.Columns[ColStackHeight]]; ok {
+		item.StackHeight = utils.ToFloat(stackHeight)
+	}
+
+	// Boolean fields, decoded by the profile's codec
 	if canSitCol, ok := profile.Columns[ColCanSit]; ok {
 		if val, exists := row[canSitCol]; exists {
-			item.CanSit = utils.ToBool(val)
+			item.CanSit = profile.Bools.Decode(val)
 		}
 	}
 	if canWalkCol, ok := profile.Columns[ColCanWalk]; ok {
 		if val, exists := row[canWalkCol]; exists {
-			item.CanWalk = utils.ToBool(val)
+			item.CanWalk = profile.Bools.Decode(val)
 		}
 	}
 	if canLayCol, ok := profile.Columns[ColCanLay]; ok {
 		if val, exists := row[canLayCol]; exists {
-			item.CanLay = utils.ToBool(val)
+			item.CanLay = profile.Bools.Decode(val)
+		}
+	}
+
+	if typeCol, ok := profile.Columns[ColType]; ok {
+		if val, exists := row[typeCol]; exists {
+			item.Type = utils.ToString(val)
+		}
+	}
+	if descCol, ok := profile.Columns[ColDescription]; ok {
+		if val, exists := row[descCol]; exists {
+			item.Description = utils.ToString(val)
 		}
 	}
 
@@ -619,11 +944,22 @@ func (a *FurnitureAdapter) parseDBRow(row map[string]any, profile ServerProfile)
 }
 
 // Prepare validates and updates the database schema for compatibility.
-// It auto-expands name columns to VARCHAR(120) to prevent truncation errors.
+// It auto-expands varchar and char name columns shorter than 120 characters to
+// VARCHAR(120) to prevent truncation errors. Columns that are already wide enough
+// (e.g. Comet's varchar(255)) and other types such as TEXT are left untouched.
 func (a *FurnitureAdapter) Prepare(ctx context.Context, db *gorm.DB) error {
 	profile := GetProfileByName(a.serverProfile)
 	tableName := profile.TableName
 
+	columns, err := database.GetTableColumns(db.WithContext(ctx), tableName)
+	if err != nil {
+		return fmt.Errorf("failed to inspect schema for %s: %w", tableName, err)
+	}
+	columnTypes := make(map[string]string, len(columns))
+	for _, col := range columns {
+		columnTypes[col.Field] = col.Type
+	}
+
 	// Columns to ensure size for (public_name and item_name)
 	targetCols := []string{ColItemName, ColPublicName}
 
@@ -633,9 +969,14 @@ func (a *FurnitureAdapter) Prepare(ctx context.Context, db *gorm.DB) error {
 			continue
 		}
 
+		size, ok := charColumnSize(columnTypes[colName])
+		if !ok || size >= minNameColumnSize {
+			continue
+		}
+
 		// Execute ALTER to resize column to 120 chars
 		// This is safe to run multiple times (idempotent for size increase)
-		query := fmt.Sprintf("ALTER TABLE %s MODIFY COLUMN %s VARCHAR(120)", tableName, colName)
+		query := fmt.Sprintf("ALTER TABLE %s MODIFY COLUMN %s VARCHAR(%d)", tableName, colName, minNameColumnSize)
 
 		if err := db.Exec(query).Error; err != nil {
 			return fmt.Errorf("failed to prepare schema for %s.%s: %w", tableName, colName, err)
@@ -643,3 +984,28 @@ func (a *FurnitureAdapter) Prepare(ctx context.Context, db *gorm.DB) error {
 	}
 	return nil
 }
+
+// minNameColumnSize is the minimum VARCHAR length required for name columns.
+const minNameColumnSize = 120
+
+// charColumnSize returns the declared length of a varchar or char column type, and
+// false for any other type (e.g. "varchar(70)" -> 70, "CHAR(32)" -> 32, "text" -> false).
+func charColumnSize(columnType string) (int, bool) {
+	columnType = strings.ToLower(strings.TrimSpace(columnType))
+	for _, prefix := range []string{"varchar(", "char("} {
+		rest, found := strings.CutPrefix(columnType, prefix)
+		if !found {
+			continue
+		}
+		end := strings.IndexByte(rest, ')')
+		if end < 0 {
+			return 0, false
+		}
+		size, err := strconv.Atoi(rest[:end])
+		if err != nil {
+			return 0, false
+		}
+		return size, true
+	}
+	return 0, false
+}
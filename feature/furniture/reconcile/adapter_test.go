@@ -5,14 +5,17 @@ import (
 	"io"
 	"strings"
 	"testing"
+	"time"
 
 	"asset-manager/core/reconcile"
+	"asset-manager/core/storage"
 	"asset-manager/core/storage/mocks"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/minio/minio-go/v7"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 )
@@ -188,6 +191,108 @@ func TestFurnitureAdapter_LoadDBIndex_Profiles(t *testing.T) {
 	}
 }
 
+// TestFurnitureAdapter_LoadDBIndex_MissingColumns tests that columns a forked schema
+// lacks are reported and their fields left out of the comparison.
+func TestFurnitureAdapter_LoadDBIndex_MissingColumns(t *testing.T) {
+	adapter := NewAdapter()
+	db, sqlMock := setupMockDB(t)
+	rows := sqlmock.NewRows([]string{"id", "sprite_id", "item_name", "public_name", "length", "allow_sit", "allow_walk", "type"}).
+		AddRow(1, 100, "chair", "Chair", 1, 1, 0, "s")
+	sqlMock.ExpectQuery("SELECT \\* FROM items_base").WillReturnRows(rows)
+
+	ctx, recorder := reconcile.WithDiagnostics(context.Background())
+	index, err := adapter.LoadDBIndex(ctx, db, "arcturus")
+	require.NoError(t, err)
+
+	assert.Equal(t, []reconcile.ColumnGap{
+		{Source: reconcile.SourceDB, Table: "items_base", Columns: []string{"allow_lay", "allow_stack", "interaction_type", "stack_height", "width"}},
+	}, recorder.Diagnostics().MissingColumns)
+	assert.True(t, adapter.columnMissing("width"))
+
+	// A wider gamedata item would otherwise mismatch on the zero width and can_lay
+	item := index["100"]
+	gd := GDItem{ID: 100, ClassName: "chair", Name: "Chair", XDim: 2, YDim: 1, CanSitOn: true, CanLayOn: true, Type: "s"}
+	assert.Empty(t, adapter.CompareFields(item, gd))
+	assert.Equal(t, []string{"width", "can_lay"}, adapter.NotComparable(item, gd))
+
+	gd.YDim = 2
+	assert.Equal(t, []string{"length: gd=2 db=1"}, adapter.CompareFields(item, gd))
+
+	// Without the key column every row would share one key
+	sqlMock.ExpectQuery("SELECT \\* FROM items_base").WillReturnRows(sqlmock.NewRows([]string{"id", "item_name"}))
+	_, err = adapter.LoadDBIndex(context.Background(), db, "arcturus")
+	assert.ErrorContains(t, err, "sprite_id")
+}
+
+// TestFurnitureAdapter_LoadIndices_KeyConflicts tests that entities sharing a key are reported.
+func TestFurnitureAdapter_LoadIndices_KeyConflicts(t *testing.T) {
+	adapter := NewAdapter()
+	db, sqlMock := setupMockDB(t)
+	rows := sqlmock.NewRows([]string{"id", "sprite_id", "item_name"}).
+		AddRow(1, 100, "chair").
+		AddRow(2, 100, "chair_copy").
+		AddRow(3, 200, "table")
+	sqlMock.ExpectQuery("SELECT \\* FROM items_base").WillReturnRows(rows)
+
+	adapter.mu.Lock()
+	adapter.classnameToID["chair"] = "100"
+	adapter.idToClassname["100"] = "chair"
+	adapter.mu.Unlock()
+	close(adapter.mappingReady)
+
+	objCh := make(chan minio.ObjectInfo, 2)
+	objCh <- minio.ObjectInfo{Key: "bundled/furniture/chair.nitro"}
+	objCh <- minio.ObjectInfo{Key: "bundled/furniture/old/chair.nitro"}
+	close(objCh)
+	mockClient := new(mocks.Client)
+	mockClient.On("ListObjects", mock.Anything, "bucket", mock.Anything).
+		Return((<-chan minio.ObjectInfo)(objCh))
+
+	ctx, recorder := reconcile.WithDiagnostics(context.Background())
+	index, err := adapter.LoadDBIndex(ctx, db, "arcturus")
+	assert.NoError(t, err)
+	assert.Len(t, index, 2)
+	_, err = adapter.LoadStorageSet(ctx, mockClient, "bucket", "bundled/furniture", ".nitro")
+	assert.NoError(t, err)
+
+	// A wall item reusing the ID of a room item
+	gamedata := `{"roomitemtypes":{"furnitype":[{"id":100,"classname":"chair"}]},"wallitemtypes":{"furnitype":[{"id":100,"classname":"poster"}]}}`
+	mockClient.On("GetObject", mock.Anything, "bucket", "gamedata.json", mock.Anything).
+		Return(io.NopCloser(strings.NewReader(gamedata)), nil)
+	_, err = NewAdapter().LoadGamedataIndex(ctx, mockClient, "bucket", "gamedata.json", nil)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []reconcile.KeyConflict{
+		{Source: reconcile.SourceDB, Key: "100", Entities: []string{"id=1 item_name=chair", "id=2 item_name=chair_copy"}},
+		{Source: reconcile.SourceGamedata, Key: "100", Entities: []string{"room classname=chair", "wall classname=poster"}},
+		{Source: reconcile.SourceStorage, Key: "100", Entities: []string{"bundled/furniture/chair.nitro", "bundled/furniture/old/chair.nitro"}},
+	}, recorder.Diagnostics().KeyConflicts)
+}
+
+// TestFurnitureAdapter_LoadGamedataIndex_DuplicateClassnames tests that classnames shared by
+// several IDs are reported, across room and wall items.
+func TestFurnitureAdapter_LoadGamedataIndex_DuplicateClassnames(t *testing.T) {
+	gamedata := `{"roomitemtypes":{"furnitype":[{"id":100,"classname":"chair"},{"id":101,"classname":"chair"},{"id":102,"classname":"table"}]},` +
+		`"wallitemtypes":{"furnitype":[{"id":200,"classname":"chair"}]}}`
+	mockClient := new(mocks.Client)
+	mockClient.On("GetObject", mock.Anything, "bucket", "gamedata.json", mock.Anything).
+		Return(io.NopCloser(strings.NewReader(gamedata)), nil)
+
+	adapter := NewAdapter()
+	ctx, recorder := reconcile.WithDiagnostics(context.Background())
+	index, err := adapter.LoadGamedataIndex(ctx, mockClient, "bucket", "gamedata.json", nil)
+	require.NoError(t, err)
+	assert.Len(t, index, 4)
+
+	assert.Equal(t, []reconcile.DuplicateName{
+		{Source: reconcile.SourceGamedata, Name: "chair", Keys: []string{"100", "101", "200"}},
+	}, recorder.Diagnostics().DuplicateNames)
+	assert.Empty(t, recorder.Diagnostics().KeyConflicts)
+
+	// The last ID wins the classname mapping
+	assert.Equal(t, "200", adapter.classnameToID["chair"])
+}
+
 func TestFurnitureAdapter_CompareFields(t *testing.T) {
 	adapter := NewAdapter()
 
@@ -206,6 +311,36 @@ func TestFurnitureAdapter_CompareFields(t *testing.T) {
 		assert.Empty(t, mismatches)
 	})
 
+	t.Run("Name Normalization", func(t *testing.T) {
+		db := DBItem{PublicName: "  rock &amp;  ROLL chair ", ItemName: "chair", Type: "s"}
+		gd := GDItem{Name: "Rock & Roll Chair", ClassName: "chair", Type: "s"}
+		assert.Len(t, adapter.CompareFields(db, gd), 1)
+
+		reconcile.SetNameNormalization(reconcile.NameNormalization{Trim: true, CollapseSpaces: true, CaseInsensitive: true, StripEntities: true})
+		defer reconcile.SetNameNormalization(reconcile.NameNormalization{})
+		assert.Empty(t, adapter.CompareFields(db, gd))
+	})
+
+	t.Run("Name Encoding", func(t *testing.T) {
+		db := DBItem{PublicName: "CafÃ© &amp; Bar", ItemName: "chair", Type: "s"}
+		gd := GDItem{Name: "Café & Bar", ClassName: "chair", Type: "s"}
+		mismatches := adapter.CompareFields(db, gd)
+		assert.Equal(t, []string{"name: gd='Café & Bar' db='CafÃ© &amp; Bar' (encoding: html_entities+double_encoded)"}, mismatches)
+	})
+
+	t.Run("Description", func(t *testing.T) {
+		db := DBItem{PublicName: "N", ItemName: "C", Type: "s", Description: "Old text"}
+		gd := GDItem{Name: "N", ClassName: "C", Type: "s", Description: "New text"}
+		assert.Equal(t, []string{"description: gd='New text' db='Old text'"}, adapter.CompareFields(db, gd))
+		assert.Equal(t, reconcile.SeverityWarning, adapter.FieldSeverity("description"))
+		assert.Equal(t, reconcile.SeverityError, adapter.FieldSeverity("name"))
+
+		// Profiles without a description column skip it without reporting a gap
+		db.NotCompared = []string{"description", "width"}
+		assert.Empty(t, adapter.CompareFields(db, gd))
+		assert.Equal(t, []string{"width"}, adapter.NotComparable(db, gd))
+	})
+
 	t.Run("Type Mismatch Wall vs Room", func(t *testing.T) {
 		// DB says Wall (i), GD says Room (s)
 		db := DBItem{Type: "i", PublicName: "N", ItemName: "C"}
@@ -225,6 +360,17 @@ func TestFurnitureAdapter_CompareFields(t *testing.T) {
 	})
 }
 
+// TestFurnitureAdapter_SearchTerms tests that both sides' classnames and names are searchable.
+func TestFurnitureAdapter_SearchTerms(t *testing.T) {
+	adapter := NewAdapter()
+	db := DBItem{ItemName: "throne", PublicName: "Old Throne"}
+	gd := GDItem{ClassName: "throne", Name: "Royal Throne"}
+
+	assert.Equal(t, []string{"throne", "Royal Throne", "throne", "Old Throne"}, adapter.SearchTerms(db, gd))
+	assert.Equal(t, []string{"throne", "Old Throne"}, adapter.SearchTerms(db, nil))
+	assert.Empty(t, adapter.SearchTerms(nil, nil))
+}
+
 func TestFurnitureAdapter_LoadStorageSet(t *testing.T) {
 	adapter := NewAdapter()
 	mockClient := new(mocks.Client)
@@ -254,6 +400,56 @@ func TestFurnitureAdapter_LoadStorageSet(t *testing.T) {
 	}
 }
 
+// TestFurnitureAdapter_LoadStorageSet_Misplaced tests that furniture found only in a
+// subfolder is reported as misplaced, unlike furniture also at its canonical path, and
+// that known modification times are recorded.
+func TestFurnitureAdapter_LoadStorageSet_Misplaced(t *testing.T) {
+	adapter := NewAdapter()
+	adapter.mu.Lock()
+	adapter.classnameToID["chair"], adapter.idToClassname["100"] = "100", "chair"
+	adapter.classnameToID["table"], adapter.idToClassname["200"] = "200", "table"
+	adapter.mu.Unlock()
+	close(adapter.mappingReady)
+
+	objCh := make(chan minio.ObjectInfo, 4)
+	objCh <- minio.ObjectInfo{Key: "bundled/furniture/old/chair.nitro"}
+	objCh <- minio.ObjectInfo{Key: "bundled/furniture/table.nitro"}
+	objCh <- minio.ObjectInfo{Key: "bundled/furniture/old/table.nitro"}
+	objCh <- minio.ObjectInfo{Key: "bundled/furniture/old/lamp.nitro", LastModified: time.Unix(1700000000, 0)}
+	close(objCh)
+	mockClient := new(mocks.Client)
+	mockClient.On("ListObjects", mock.Anything, "bucket", mock.Anything).
+		Return((<-chan minio.ObjectInfo)(objCh))
+
+	ctx, recorder := reconcile.WithDiagnostics(context.Background())
+	set, err := adapter.LoadStorageSet(ctx, mockClient, "bucket", "bundled/furniture", ".nitro")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]struct{}{"100": {}, "200": {}, "old/lamp": {}}, set)
+	assert.Equal(t, map[string]string{"100": "bundled/furniture/old/chair.nitro"}, recorder.Misplaced())
+	assert.Equal(t, map[string]time.Time{"old/lamp": time.Unix(1700000000, 0)}, recorder.Modified())
+}
+
+// TestFurnitureAdapter_ConversionMetadata tests that the conversion log registered with
+// SetConversionLog is loaded with the storage set and reported as metadata.
+func TestFurnitureAdapter_ConversionMetadata(t *testing.T) {
+	SetConversionLog("swf/")
+	defer SetConversionLog("")
+
+	adapter := NewAdapter()
+	close(adapter.mappingReady)
+	mockClient := new(mocks.Client)
+	mockClient.On("ListObjects", mock.Anything, "bucket", mock.Anything).Return(nil)
+	mockClient.On("GetObject", mock.Anything, "bucket", "swf/conversions.json", mock.Anything).
+		Return(io.NopCloser(strings.NewReader(`{"chair": {"classname": "chair", "source": "swf/chair.swf", "status": "failed", "error": "boom"}}`)), nil)
+
+	_, err := adapter.LoadStorageSet(context.Background(), mockClient, "bucket", "bundled/furniture", ".nitro")
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"classname": "chair", "swf_conversion": ConversionFailed},
+		adapter.GetMetadata(nil, GDItem{ClassName: "chair"}))
+	assert.Equal(t, map[string]string{"classname": "table"}, adapter.GetMetadata(nil, GDItem{ClassName: "table"}))
+}
+
 func TestFurnitureAdapter_QueryDB(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -359,3 +555,65 @@ func TestFurnitureAdapter_QueryDB_Classname(t *testing.T) {
 	assert.Equal(t, "Portable TV", dbItem.PublicName)
 	assert.Equal(t, 1, dbItem.Width)
 }
+
+// TestFurnitureAdapter_NormalizeKey tests that storage objects named after DB rows
+// gamedata does not know meet those rows, and that gamedata classnames win.
+func TestFurnitureAdapter_NormalizeKey(t *testing.T) {
+	adapter := NewAdapter()
+	db, sqlMock := setupMockDB(t)
+	rows := sqlmock.NewRows([]string{"id", "sprite_id", "item_name"}).
+		AddRow(1, 100, "chair").
+		AddRow(2, 500, "custom_chair").
+		AddRow(3, 600, "1234")
+	sqlMock.ExpectQuery("SELECT \\* FROM items_base").WillReturnRows(rows)
+	adapter.classnameToID["chair"] = "100"
+	adapter.idToClassname["100"] = "chair"
+
+	_, err := adapter.LoadDBIndex(context.Background(), db, "arcturus")
+	require.NoError(t, err)
+
+	assert.Equal(t, "500", adapter.NormalizeKey("custom_chair"))
+	assert.Equal(t, "500", adapter.NormalizeKey("500"))
+	assert.Equal(t, "chair", adapter.NormalizeKey("chair"), "gamedata classnames are resolved when listed")
+	assert.Equal(t, "1234", adapter.NormalizeKey("1234"), "numeric names are not mapped")
+	assert.Equal(t, "old/lamp", adapter.NormalizeKey("old/lamp"))
+
+	classname, ok := adapter.idToClassnameOf("500")
+	assert.True(t, ok)
+	assert.Equal(t, "custom_chair", classname)
+}
+
+// TestNewSpec_Settings tests that the configured furniture settings tune specs and
+// adapters built afterwards.
+func TestNewSpec_Settings(t *testing.T) {
+	reconcile.SetAdapterSettings(reconcile.AdapterConfig{AdapterName: {
+		CacheTTL:         time.Minute,
+		Concurrency:      8,
+		StoragePrefix:    "assets/furni",
+		StorageExtension: ".glb",
+		GamedataObject:   "data/furni.json",
+		IgnoreFields:     []string{"description", "dimensions"},
+	}})
+	defer reconcile.SetAdapterSettings(nil)
+
+	adapter := NewAdapter()
+	spec := NewSpec(adapter, "arcturus", "", DefaultCacheTTL)
+	assert.Equal(t, time.Minute, spec.CacheTTL)
+	assert.Equal(t, "assets/furni", spec.StoragePrefix)
+	assert.Equal(t, ".glb", spec.StorageExtension)
+	assert.Equal(t, "data/furni.json", spec.GamedataObjectName)
+	assert.Zero(t, NewSpec(adapter, "arcturus", "", 0).CacheTTL, "full scans never cache")
+	assert.Equal(t, 8, adapter.batchConcurrency)
+
+	adapter.SetMutationContext(nil, nil, storage.Buckets{}, AssetPrefix(), "arcturus", GamedataKey())
+	assert.Equal(t, "assets/furni/chair.glb", adapter.objectKey("chair"))
+
+	db := DBItem{SpriteID: 100, ItemName: "chair", PublicName: "Chair", Width: 1, Length: 1, Description: "old"}
+	gd := GDItem{ID: 100, ClassName: "chair", Name: "Chair", XDim: 2, YDim: 3, Description: "new", Type: "s"}
+	assert.Empty(t, adapter.CompareFields(db, gd))
+
+	// A negative TTL disables caching
+	reconcile.SetAdapterSettings(reconcile.AdapterConfig{AdapterName: {CacheTTL: -1}})
+	assert.Zero(t, NewSpec(NewAdapter(), "arcturus", "", DefaultCacheTTL).CacheTTL)
+	assert.Equal(t, StoragePrefix, AssetPrefix())
+}
@@ -0,0 +1,250 @@
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"asset-manager/core/reconcile"
+
+	"gorm.io/gorm"
+)
+
+// gamedataSections are the FurnitureData.json sections holding furnitype entries.
+var gamedataSections = []string{"roomitemtypes", "wallitemtypes"}
+
+// storageObject returns the object a storage action on key targets: the object of its
+// classname, or for storage orphans the object named after the key itself.
+func (a *FurnitureAdapter) storageObject(key string) string {
+	if classname, ok := a.idToClassnameOf(key); ok {
+		return a.objectKey(classname)
+	}
+	return a.objectKey(key)
+}
+
+// BackupItems saves the rows and gamedata entries of the sprite IDs of backup.Keys and
+// lists the .nitro files storage actions touch, including the misplaced files of moves
+// (reconcile.Backuper). Keys that are no sprite ID, such as storage orphans, only
+// have files.
+func (a *FurnitureAdapter) BackupItems(ctx context.Context, actions []reconcile.Action, backup *reconcile.Backup) error {
+	if a.db == nil || a.client == nil {
+		return fmt.Errorf("mutation context not set, call SetMutationContext first")
+	}
+	ids := spriteIDsOf(backup.Keys)
+
+	if backup.HasSource(reconcile.SourceDB) && len(ids) > 0 {
+		rows, err := a.backupRows(ctx, ids)
+		if err != nil {
+			return err
+		}
+		backup.Rows = rows
+	}
+
+	if backup.HasSource(reconcile.SourceGamedata) && len(ids) > 0 {
+		doc, err := a.readGamedataDoc(ctx)
+		if err != nil {
+			return err
+		}
+		backup.Gamedata = make(map[string][]reconcile.BackupEntry)
+		for _, section := range gamedataSections {
+			for _, raw := range gamedataEntries(doc, section) {
+				entry, ok := raw.(map[string]any)
+				if !ok {
+					continue
+				}
+				key := entryKey(entry)
+				if _, affected := ids[key]; affected {
+					backup.Gamedata[key] = append(backup.Gamedata[key], reconcile.BackupEntry{Section: section, Entry: entry})
+				}
+			}
+		}
+	}
+
+	seen := make(map[string]bool)
+	add := func(key, object string) {
+		if object == "" || seen[object] {
+			return
+		}
+		seen[object] = true
+		backup.Objects = append(backup.Objects, reconcile.BackupObject{Key: key, Bucket: a.buckets.Assets, Object: object})
+	}
+	for _, action := range actions {
+		switch action.Type {
+		case reconcile.ActionDeleteStorage, reconcile.ActionDownloadStorage:
+			add(action.Key, a.storageObject(action.Key))
+		case reconcile.ActionMoveStorage:
+			add(action.Key, a.storageObject(action.Key))
+			add(action.Key, action.From)
+		}
+	}
+	return nil
+}
+
+// backupRows reads the rows of ids by key, with byte values as strings so they
+// survive the JSON manifest.
+func (a *FurnitureAdapter) backupRows(ctx context.Context, ids map[string]int) (map[string][]map[string]any, error) {
+	profile := GetProfileByName(a.serverProfile)
+	spriteCol := profile.Columns[ColSpriteID]
+
+	query := fmt.Sprintf("SELECT * FROM %s WHERE %s IN ?", profile.TableName, spriteCol)
+	dbRows, err := a.db.WithContext(ctx).Raw(query, spriteIDValues(ids)).Rows()
+	if err != nil {
+		return nil, fmt.Errorf("failed to back up %s rows: %w", profile.TableName, err)
+	}
+	defer dbRows.Close()
+
+	columns, err := dbRows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get columns: %w", err)
+	}
+
+	rows := make(map[string][]map[string]any)
+	for dbRows.Next() {
+		values := make([]any, len(columns))
+		valuePtrs := make([]any, len(columns))
+		for i := range values {
+			valuePtrs[i] = &values[i]
+		}
+		if err := dbRows.Scan(valuePtrs...); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+
+		row := make(map[string]any, len(columns))
+		for i, col := range columns {
+			if b, ok := values[i].([]byte); ok {
+				row[col] = string(b)
+			} else {
+				row[col] = values[i]
+			}
+		}
+		key := fmt.Sprint(row[spriteCol])
+		rows[key] = append(rows[key], row)
+	}
+	return rows, dbRows.Err()
+}
+
+// RestoreItems puts back the rows and gamedata entries of backup.Keys as they were
+// backed up (reconcile.Backuper). Current rows of the keys are replaced in one
+// transaction; current gamedata entries are replaced in place, and entries the plan
+// deleted are appended to their section.
+func (a *FurnitureAdapter) RestoreItems(ctx context.Context, backup *reconcile.Backup) error {
+	if a.db == nil || a.client == nil {
+		return fmt.Errorf("mutation context not set, call SetMutationContext first")
+	}
+	ids := spriteIDsOf(backup.Keys)
+	if len(ids) == 0 {
+		return nil
+	}
+
+	if backup.HasSource(reconcile.SourceDB) {
+		profile := GetProfileByName(a.serverProfile)
+		err := a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := tx.Table(profile.TableName).Where(profile.Columns[ColSpriteID]+" IN ?", spriteIDValues(ids)).Delete(nil).Error; err != nil {
+				return fmt.Errorf("failed to clear rows: %w", err)
+			}
+			for _, key := range backup.Keys {
+				for _, row := range backup.Rows[key] {
+					if err := tx.Table(profile.TableName).Create(row).Error; err != nil {
+						return fmt.Errorf("failed to restore row of %s: %w", key, err)
+					}
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if backup.HasSource(reconcile.SourceGamedata) {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+
+		doc, err := a.readGamedataDoc(ctx)
+		if err != nil {
+			return err
+		}
+		restoreGamedataEntries(doc, backup.Keys, ids, backup.Gamedata)
+		if err := a.writeGamedataDoc(ctx, doc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// restoreGamedataEntries replaces the entries of keys, whose sprite IDs are ids, in doc
+// with their saved entries. The first entry of a key in a section takes the place of
+// its saved entries there; saved entries of keys doc no longer holds are appended to
+// their section in key order.
+func restoreGamedataEntries(doc map[string]any, keys []string, ids map[string]int, saved map[string][]reconcile.BackupEntry) {
+	placed := make(map[string]bool)
+	for _, section := range gamedataSections {
+		entries := make([]any, 0)
+		for _, raw := range gamedataEntries(doc, section) {
+			entry, ok := raw.(map[string]any)
+			if !ok {
+				entries = append(entries, raw)
+				continue
+			}
+			key := entryKey(entry)
+			if _, affected := ids[key]; !affected {
+				entries = append(entries, raw)
+				continue
+			}
+			if placed[section+"/"+key] {
+				continue
+			}
+			placed[section+"/"+key] = true
+			entries = append(entries, savedEntries(saved[key], section)...)
+		}
+		setGamedataEntries(doc, section, entries)
+	}
+
+	for _, section := range gamedataSections {
+		entries := gamedataEntries(doc, section)
+		for _, key := range keys {
+			if _, affected := ids[key]; affected && !placed[section+"/"+key] {
+				placed[section+"/"+key] = true
+				entries = append(entries, savedEntries(saved[key], section)...)
+			}
+		}
+		setGamedataEntries(doc, section, entries)
+	}
+}
+
+// savedEntries returns the saved entries of section.
+func savedEntries(saved []reconcile.BackupEntry, section string) []any {
+	var entries []any
+	for _, e := range saved {
+		if e.Section == section {
+			entries = append(entries, e.Entry)
+		}
+	}
+	return entries
+}
+
+// entryKey returns the key of a gamedata entry: its id.
+func entryKey(entry map[string]any) string {
+	id, _ := entry["id"].(float64)
+	return strconv.Itoa(int(id))
+}
+
+// spriteIDsOf maps the keys that are sprite IDs to their value.
+func spriteIDsOf(keys []string) map[string]int {
+	ids := make(map[string]int, len(keys))
+	for _, key := range keys {
+		if id, err := strconv.Atoi(key); err == nil {
+			ids[strconv.Itoa(id)] = id
+		}
+	}
+	return ids
+}
+
+// spriteIDValues returns the values of ids for an IN clause.
+func spriteIDValues(ids map[string]int) []int {
+	values := make([]int, 0, len(ids))
+	for _, id := range ids {
+		values = append(values, id)
+	}
+	return values
+}
@@ -0,0 +1,90 @@
+package reconcile
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"asset-manager/core/json"
+	"asset-manager/core/reconcile"
+	"asset-manager/core/storage"
+	"asset-manager/core/storage/mocks"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBackupItems_RestoreItems tests that rows and gamedata entries survive a backup
+// round trip through its JSON manifest: changed ones are put back in place, deleted
+// ones are recreated.
+func TestBackupItems_RestoreItems(t *testing.T) {
+	db := setupTestDB(t, "db_backup")
+	require.NoError(t, db.Exec(`INSERT INTO items_base (id, sprite_id, item_name, public_name, width, length, type) VALUES (1, 200, 'chair', 'Chair', 1, 1, 's'), (2, 300, 'table', 'Table', 2, 2, 's')`).Error)
+
+	before := `{"roomitemtypes": {"furnitype": [
+		{"id": 200, "classname": "chair", "name": "Chair"},
+		{"id": 300, "classname": "table", "name": "Table"},
+		{"id": 400, "classname": "lamp", "name": "Lamp"}
+	]}, "wallitemtypes": {"furnitype": []}}`
+	after := `{"roomitemtypes": {"furnitype": [
+		{"id": 300, "classname": "table", "name": "Desk"},
+		{"id": 400, "classname": "lamp", "name": "Lamp"}
+	]}, "wallitemtypes": {"furnitype": []}}`
+
+	var written []byte
+	client := new(mocks.Client)
+	client.On("GetObject", mock.Anything, "assets", GamedataObject, mock.Anything).
+		Return(io.NopCloser(strings.NewReader(before)), nil).Once()
+	client.On("GetObject", mock.Anything, "assets", GamedataObject, mock.Anything).
+		Return(io.NopCloser(strings.NewReader(after)), nil).Once()
+	client.On("PutObject", mock.Anything, "assets", GamedataObject, mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { written, _ = io.ReadAll(args.Get(3).(io.Reader)) }).
+		Return(minio.UploadInfo{}, nil)
+
+	adapter := NewAdapter()
+	adapter.idToClassname["200"] = "chair"
+	adapter.SetMutationContext(db, client, storage.SingleBucket("assets"), StoragePrefix, "arcturus", GamedataObject)
+
+	actions := []reconcile.Action{
+		{Type: reconcile.ActionDeleteDB, Key: "200"},
+		{Type: reconcile.ActionDeleteGamedata, Key: "200"},
+		{Type: reconcile.ActionDeleteStorage, Key: "200"},
+		{Type: reconcile.ActionSyncDB, Key: "300", Fields: []string{"name"}},
+	}
+	backup := &reconcile.Backup{
+		Keys:    []string{"200", "300"},
+		Sources: []string{reconcile.SourceDB, reconcile.SourceGamedata, reconcile.SourceStorage},
+	}
+	require.NoError(t, adapter.BackupItems(context.Background(), actions, backup))
+	require.Len(t, backup.Rows["200"], 1)
+	assert.Equal(t, "chair", backup.Rows["200"][0]["item_name"])
+	require.Len(t, backup.Gamedata["300"], 1)
+	assert.Equal(t, "roomitemtypes", backup.Gamedata["300"][0].Section)
+	assert.Equal(t, []reconcile.BackupObject{{Key: "200", Bucket: "assets", Object: StoragePrefix + "/chair.nitro"}}, backup.Objects)
+
+	// The plan deletes chair and renames table
+	require.NoError(t, db.Exec(`DELETE FROM items_base WHERE sprite_id = 200`).Error)
+	require.NoError(t, db.Exec(`UPDATE items_base SET public_name = 'Desk' WHERE sprite_id = 300`).Error)
+
+	data, err := json.Marshal(backup)
+	require.NoError(t, err)
+	var loaded reconcile.Backup
+	require.NoError(t, json.Unmarshal(data, &loaded))
+	require.NoError(t, adapter.RestoreItems(context.Background(), &loaded))
+
+	var names []string
+	require.NoError(t, db.Table("items_base").Order("sprite_id").Pluck("public_name", &names).Error)
+	assert.Equal(t, []string{"Chair", "Table"}, names)
+
+	var doc map[string]any
+	require.NoError(t, json.Unmarshal(written, &doc))
+	room := doc["roomitemtypes"].(map[string]any)["furnitype"].([]any)
+	require.Len(t, room, 3)
+	assert.Equal(t, "Table", room[0].(map[string]any)["name"], "changed entries keep their place")
+	assert.Equal(t, "lamp", room[1].(map[string]any)["classname"])
+	assert.Equal(t, "chair", room[2].(map[string]any)["classname"], "deleted entries are appended")
+	assert.Empty(t, doc["wallitemtypes"].(map[string]any)["furnitype"])
+}
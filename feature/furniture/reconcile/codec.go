@@ -0,0 +1,87 @@
+package reconcile
+
+import (
+	"fmt"
+	"strings"
+
+	"asset-manager/core/utils"
+)
+
+// BoolCodec converts boolean flags between Go values and a profile's column type.
+// Reads (LoadDBIndex, QueryDB) and writes (SyncDBFromGamedata) go through the same
+// codec so a value always round-trips.
+type BoolCodec interface {
+	// Encode returns the value to write for v.
+	Encode(v bool) any
+
+	// Decode interprets a value read from the column.
+	Decode(val any) bool
+}
+
+// TinyIntBools stores booleans as tinyint(1), as Arcturus and Plus do.
+// Any non-zero number reads as true.
+type TinyIntBools struct{}
+
+// Encode implements BoolCodec.
+func (TinyIntBools) Encode(v bool) any {
+	if v {
+		return 1
+	}
+	return 0
+}
+
+// Decode implements BoolCodec.
+func (TinyIntBools) Decode(val any) bool {
+	switch v := val.(type) {
+	case bool:
+		return v
+	case string, []byte:
+		s := strings.TrimSpace(utils.ToString(v))
+		if strings.EqualFold(s, "true") {
+			return true
+		}
+		return utils.ToInt(s) != 0
+	case nil:
+		return false
+	default:
+		return utils.ToInt(v) != 0
+	}
+}
+
+// EnumBools stores booleans as enum('0','1'), as Comet does.
+// MySQL resolves numeric values against enum columns by index, so writes must send
+// the string literal to avoid storing the wrong member.
+type EnumBools struct{}
+
+// Encode implements BoolCodec.
+func (EnumBools) Encode(v bool) any {
+	if v {
+		return "1"
+	}
+	return "0"
+}
+
+// Decode implements BoolCodec.
+func (EnumBools) Decode(val any) bool {
+	switch v := val.(type) {
+	case bool:
+		return v
+	case nil:
+		return false
+	default:
+		return strings.TrimSpace(utils.ToString(v)) == "1"
+	}
+}
+
+// BoolCodecByName returns the codec for a boolean encoding named in configuration:
+// "tinyint" for TinyIntBools or "enum" for EnumBools.
+func BoolCodecByName(name string) (BoolCodec, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "tinyint":
+		return TinyIntBools{}, nil
+	case "enum":
+		return EnumBools{}, nil
+	default:
+		return nil, fmt.Errorf("unknown boolean encoding %q (want tinyint or enum)", name)
+	}
+}
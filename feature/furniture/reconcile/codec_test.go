@@ -0,0 +1,57 @@
+package reconcile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBoolCodecs(t *testing.T) {
+	tests := []struct {
+		name  string
+		codec BoolCodec
+		val   any
+		want  bool
+	}{
+		{"tinyint int64", TinyIntBools{}, int64(1), true},
+		{"tinyint zero", TinyIntBools{}, int64(0), false},
+		{"tinyint non-one", TinyIntBools{}, int64(2), true},
+		{"tinyint bytes", TinyIntBools{}, []byte("1"), true},
+		{"tinyint true string", TinyIntBools{}, "true", true},
+		{"tinyint nil", TinyIntBools{}, nil, false},
+		{"enum one", EnumBools{}, []byte("1"), true},
+		{"enum zero", EnumBools{}, "0", false},
+		{"enum padded", EnumBools{}, " 1 ", true},
+		{"enum bool", EnumBools{}, true, true},
+		{"enum nil", EnumBools{}, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.codec.Decode(tt.val))
+		})
+	}
+}
+
+func TestBoolCodecs_RoundTrip(t *testing.T) {
+	for _, codec := range []BoolCodec{TinyIntBools{}, EnumBools{}} {
+		for _, v := range []bool{true, false} {
+			assert.Equal(t, v, codec.Decode(codec.Encode(v)))
+		}
+	}
+	assert.Equal(t, "1", EnumBools{}.Encode(true))
+	assert.Equal(t, 0, TinyIntBools{}.Encode(false))
+}
+
+func TestBoolCodecByName(t *testing.T) {
+	codec, err := BoolCodecByName("tinyint")
+	assert.NoError(t, err)
+	assert.Equal(t, TinyIntBools{}, codec)
+
+	codec, err = BoolCodecByName(" Enum ")
+	assert.NoError(t, err)
+	assert.Equal(t, EnumBools{}, codec)
+
+	_, err = BoolCodecByName("bit")
+	assert.Error(t, err)
+}
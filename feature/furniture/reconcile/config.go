@@ -1,5 +1,16 @@
 package reconcile
 
+import (
+	"fmt"
+	"maps"
+	"path"
+	"slices"
+	"sort"
+	"sync"
+
+	"asset-manager/core/config"
+)
+
 // ServerProfile defines emulator-specific database schema mappings.
 type ServerProfile struct {
 	// TableName is the name of the furniture table in the database.
@@ -7,6 +18,28 @@ type ServerProfile struct {
 
 	// Columns maps logical field names to actual database column names.
 	Columns map[string]string
+
+	// Bools reads and writes the boolean flag columns.
+	Bools BoolCodec
+
+	// DecimalStrings indicates decimal columns (stack_height) are stored as varchar,
+	// which may hold comma separators that must be normalized before comparing or writing.
+	DecimalStrings bool
+
+	// UsersTable and OnlineColumn locate the emulator's online flag per user, used to
+	// count online users. Either empty disables the count.
+	UsersTable   string
+	OnlineColumn string
+
+	// OfferColumns maps the commerce fields of gamedata (OfferFieldID, OfferFieldBuyout,
+	// OfferFieldRentID, OfferFieldRentBuyout) to catalog_items columns, for the catalog
+	// offer sync. Unmapped fields are not synced.
+	OfferColumns map[string]string
+
+	// Reserved lists the items the emulator reserves without a storage bundle, such as
+	// room ad backgrounds, by sprite ID or by classname pattern ("present_wrap*", see
+	// path.Match). They are reported as expected absent from storage instead of missing.
+	Reserved []string
 }
 
 // Column name constants for logical field references.
@@ -25,9 +58,26 @@ const (
 	ColType        = "type"
 	ColInteraction = "interaction_type"
 	ColIsRare      = "is_rare"
+	ColDescription = "description"
 )
 
-// ArcturusProfile returns the server profile for Arcturus Morningstar emulator.
+// Commerce field constants for OfferColumns, named after their gamedata fields.
+const (
+	OfferFieldID         = "offer_id"
+	OfferFieldBuyout     = "buyout"
+	OfferFieldRentID     = "rent_offer_id"
+	OfferFieldRentBuyout = "rent_buyout"
+)
+
+// reservedFurniture lists the items every built-in emulator handles without a storage
+// bundle: the room ad background, whose image each room sets, and the gift wrapping
+// entries presents are wrapped in, which the client draws from the present bundle.
+func reservedFurniture() []string {
+	return []string{"ads_background", "present_wrap*"}
+}
+
+// ArcturusProfile returns the server profile for Arcturus Morningstar emulator, whose
+// items_base stores the boolean flags as tinyint(1).
 func ArcturusProfile() ServerProfile {
 	return ServerProfile{
 		TableName: "items_base",
@@ -46,9 +96,25 @@ func ArcturusProfile() ServerProfile {
 			ColType:        "type",
 			ColInteraction: "interaction_type",
 		},
+		Bools:        TinyIntBools{},
+		UsersTable:   "users",
+		OnlineColumn: "online",
+		OfferColumns: map[string]string{
+			OfferFieldID:     "offer_id",
+			OfferFieldBuyout: "have_offer",
+		},
+		Reserved: reservedFurniture(),
 	}
 }
 
+// ArcturusMSProfile returns the server profile for Arcturus Morningstar 4.x, whose
+// items_base stores the boolean flags as enum('0','1') instead of tinyint(1).
+func ArcturusMSProfile() ServerProfile {
+	p := ArcturusProfile()
+	p.Bools = EnumBools{}
+	return p
+}
+
 // CometProfile returns the server profile for Comet emulator.
 func CometProfile() ServerProfile {
 	return ServerProfile{
@@ -68,6 +134,14 @@ func CometProfile() ServerProfile {
 			ColType:        "type",
 			ColInteraction: "interaction_type",
 		},
+		Bools:          EnumBools{},
+		DecimalStrings: true,
+		UsersTable:     "players",
+		OnlineColumn:   "online",
+		OfferColumns: map[string]string{
+			OfferFieldBuyout: "offer_active",
+		},
+		Reserved: reservedFurniture(),
 	}
 }
 
@@ -90,20 +164,206 @@ func PlusProfile() ServerProfile {
 			ColInteraction: "interaction_type",
 			ColIsRare:      "is_rare",
 		},
+		Bools:        TinyIntBools{},
+		UsersTable:   "users",
+		OnlineColumn: "online",
+		OfferColumns: map[string]string{
+			OfferFieldID:     "offer_id",
+			OfferFieldBuyout: "offer_active",
+		},
+		Reserved: reservedFurniture(),
+	}
+}
+
+// requiredColumns are the logical columns every profile must map; the adapter
+// builds its queries from them.
+var requiredColumns = []string{ColID, ColSpriteID, ColItemName, ColPublicName}
+
+// Validate checks that the profile names a table, maps the required columns, has a
+// codec for the boolean flag columns and only valid reserved patterns.
+func (p ServerProfile) Validate() error {
+	if p.TableName == "" {
+		return fmt.Errorf("profile has no table name")
+	}
+	for _, col := range requiredColumns {
+		if p.Columns[col] == "" {
+			return fmt.Errorf("profile does not map required column %q", col)
+		}
+	}
+	if p.Bools == nil {
+		return fmt.Errorf("profile has no boolean codec")
+	}
+	for _, pattern := range p.Reserved {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid reserved pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// profiles holds the server profiles known by emulator name.
+var profiles = struct {
+	sync.RWMutex
+	byName map[string]ServerProfile
+}{byName: map[string]ServerProfile{
+	"arcturus":    ArcturusProfile(),
+	"arcturus-ms": ArcturusMSProfile(),
+	"comet":       CometProfile(),
+	"plus":        PlusProfile(),
+	"plusemu":     PlusProfile(),
+}}
+
+// RegisterProfile makes a server profile available under an emulator name, so forks
+// and other emulators can be supported without changing this package. Registering an
+// existing name replaces that profile, including the built-in ones.
+func RegisterProfile(name string, p ServerProfile) error {
+	if name == "" {
+		return fmt.Errorf("profile name is empty")
+	}
+	if err := p.Validate(); err != nil {
+		return fmt.Errorf("invalid profile %q: %w", name, err)
+	}
+
+	profiles.Lock()
+	defer profiles.Unlock()
+	profiles.byName[name] = copyProfile(p)
+	return nil
+}
+
+// LookupProfile returns the profile registered under name and whether one exists.
+func LookupProfile(name string) (ServerProfile, bool) {
+	profiles.RLock()
+	defer profiles.RUnlock()
+	p, ok := profiles.byName[name]
+	if !ok {
+		return ServerProfile{}, false
+	}
+	return copyProfile(p), true
+}
+
+// ProfileNames returns the registered emulator names in sorted order.
+func ProfileNames() []string {
+	profiles.RLock()
+	defer profiles.RUnlock()
+	names := make([]string, 0, len(profiles.byName))
+	for name := range profiles.byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RegisterConfigProfiles registers the profiles defined in the profiles section of
+// the configuration. A profile extending another starts from that profile, which may
+// be built in or itself configured, and overrides the table, columns and encodings it
+// sets. Nothing is registered when any definition is invalid.
+func RegisterConfigProfiles(defs map[string]config.ProfileConfig) error {
+	resolved := make(map[string]ServerProfile, len(defs))
+	names := make([]string, 0, len(defs))
+	for name := range defs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if _, err := resolveConfigProfile(name, defs, resolved, map[string]bool{}); err != nil {
+			return err
+		}
+	}
+	for _, name := range names {
+		if err := RegisterProfile(name, resolved[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolveConfigProfile builds the profile configured under name, resolving the
+// profiles it extends first. visiting detects extends cycles.
+func resolveConfigProfile(name string, defs map[string]config.ProfileConfig, resolved map[string]ServerProfile, visiting map[string]bool) (ServerProfile, error) {
+	if p, ok := resolved[name]; ok {
+		return p, nil
+	}
+	if visiting[name] {
+		return ServerProfile{}, fmt.Errorf("profile %q extends itself", name)
+	}
+	visiting[name] = true
+	def := defs[name]
+
+	var p ServerProfile
+	if def.Extends != "" {
+		if _, ok := defs[def.Extends]; ok && def.Extends != name {
+			base, err := resolveConfigProfile(def.Extends, defs, resolved, visiting)
+			if err != nil {
+				return ServerProfile{}, err
+			}
+			p = copyProfile(base)
+		} else if base, ok := LookupProfile(def.Extends); ok {
+			p = base
+		} else {
+			return ServerProfile{}, fmt.Errorf("profile %q extends unknown profile %q", name, def.Extends)
+		}
+	}
+	if p.Columns == nil {
+		p.Columns = make(map[string]string, len(def.Columns))
+	}
+
+	if def.Table != "" {
+		p.TableName = def.Table
+	}
+	for field, column := range def.Columns {
+		p.Columns[field] = column
+	}
+	if def.Bools != "" {
+		codec, err := BoolCodecByName(def.Bools)
+		if err != nil {
+			return ServerProfile{}, fmt.Errorf("profile %q: %w", name, err)
+		}
+		p.Bools = codec
+	}
+	if def.DecimalStrings != nil {
+		p.DecimalStrings = *def.DecimalStrings
+	}
+	if def.UsersTable != "" {
+		p.UsersTable = def.UsersTable
+	}
+	if def.OnlineColumn != "" {
+		p.OnlineColumn = def.OnlineColumn
+	}
+	if len(def.OfferColumns) > 0 && p.OfferColumns == nil {
+		p.OfferColumns = make(map[string]string, len(def.OfferColumns))
+	}
+	for field, column := range def.OfferColumns {
+		p.OfferColumns[field] = column
+	}
+	p.Reserved = append(p.Reserved, def.Reserved...)
+
+	if err := p.Validate(); err != nil {
+		return ServerProfile{}, fmt.Errorf("invalid profile %q: %w", name, err)
+	}
+	resolved[name] = p
+	return p, nil
+}
+
+// copyProfile returns p with its own Columns and OfferColumns maps and Reserved list,
+// so callers cannot change a registered profile through a shared one.
+func copyProfile(p ServerProfile) ServerProfile {
+	p.Columns = maps.Clone(p.Columns)
+	p.OfferColumns = maps.Clone(p.OfferColumns)
+	p.Reserved = slices.Clone(p.Reserved)
+	if p.Columns == nil {
+		p.Columns = make(map[string]string)
 	}
+	return p
 }
 
-// GetProfileByName returns the appropriate server profile for a given emulator name.
+// GetProfileByName returns the server profile registered for a given emulator name.
+// Both "plus" and the configured server value "plusemu" resolve to the Plus profile.
+// Unknown names fall back to the Arcturus profile.
 func GetProfileByName(emulator string) ServerProfile {
-	switch emulator {
-	case "arcturus":
-		return ArcturusProfile()
-	case "comet":
-		return CometProfile()
-	case "plus":
-		return PlusProfile()
-	default:
-		// Default to Arcturus
-		return ArcturusProfile()
+	if p, ok := LookupProfile(emulator); ok {
+		return p
 	}
+	// Default to Arcturus
+	return ArcturusProfile()
 }
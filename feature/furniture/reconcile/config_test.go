@@ -0,0 +1,141 @@
+package reconcile
+
+import (
+	"context"
+	"testing"
+
+	"asset-manager/core/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// keplerProfile returns a profile for a schema with renamed columns.
+func keplerProfile() ServerProfile {
+	return ServerProfile{
+		TableName: "items_definitions",
+		Columns: map[string]string{
+			ColID:         "id",
+			ColSpriteID:   "sprite",
+			ColItemName:   "sprite_name",
+			ColPublicName: "name",
+			ColCanSit:     "is_sittable",
+			ColType:       "kind",
+		},
+		Bools: EnumBools{},
+	}
+}
+
+func TestGetProfileByName_BuiltIns(t *testing.T) {
+	assert.Equal(t, "items_base", GetProfileByName("arcturus").TableName)
+	assert.Equal(t, "furniture", GetProfileByName("comet").TableName)
+	assert.Equal(t, "is_rare", GetProfileByName("plusemu").Columns[ColIsRare])
+	assert.Equal(t, "items_base", GetProfileByName("unknown").TableName)
+	assert.Equal(t, EnumBools{}, GetProfileByName("arcturus-ms").Bools)
+	assert.Equal(t, "allow_stack", GetProfileByName("arcturus-ms").Columns[ColCanStack])
+	assert.Subset(t, ProfileNames(), []string{"arcturus", "arcturus-ms", "comet", "plus", "plusemu"})
+}
+
+func TestRegisterProfile(t *testing.T) {
+	require.NoError(t, RegisterProfile("kepler", keplerProfile()))
+
+	p, ok := LookupProfile("kepler")
+	require.True(t, ok)
+	assert.Equal(t, "items_definitions", p.TableName)
+	assert.Contains(t, ProfileNames(), "kepler")
+
+	// Lookups return copies, so callers cannot change the registered profile
+	p.Columns[ColSpriteID] = "changed"
+	assert.Equal(t, "sprite", GetProfileByName("kepler").Columns[ColSpriteID])
+
+	adapter := NewAdapter()
+	db, mock := setupMockDB(t)
+	rows := mock.NewRows([]string{"id", "sprite", "sprite_name", "name", "is_sittable", "kind"})
+	rows.AddRow(1, 100, "chair", "Public Chair", "1", "s")
+	mock.ExpectQuery("SELECT \\* FROM items_definitions").WillReturnRows(rows)
+
+	index, err := adapter.LoadDBIndex(context.Background(), db, "kepler")
+	require.NoError(t, err)
+	item := index["100"].(DBItem)
+	assert.Equal(t, "chair", item.ItemName)
+	assert.Equal(t, "Public Chair", item.PublicName)
+	assert.Equal(t, "s", item.Type)
+	assert.True(t, item.CanSit)
+}
+
+func TestRegisterProfile_Invalid(t *testing.T) {
+	noTable := keplerProfile()
+	noTable.TableName = ""
+	missingColumn := keplerProfile()
+	delete(missingColumn.Columns, ColItemName)
+	noCodec := keplerProfile()
+	noCodec.Bools = nil
+
+	assert.Error(t, RegisterProfile("", keplerProfile()))
+	assert.ErrorContains(t, RegisterProfile("broken", noTable), "table name")
+	assert.ErrorContains(t, RegisterProfile("broken", missingColumn), ColItemName)
+	assert.ErrorContains(t, RegisterProfile("broken", noCodec), "boolean codec")
+
+	_, ok := LookupProfile("broken")
+	assert.False(t, ok)
+}
+
+func TestRegisterConfigProfiles(t *testing.T) {
+	decimal := true
+	defs := map[string]config.ProfileConfig{
+		"fork": {
+			Extends:      "arcturus",
+			Columns:      map[string]string{ColSpriteID: "spriteid"},
+			Bools:        "enum",
+			OfferColumns: map[string]string{OfferFieldRentID: "rent_offer_id"},
+		},
+		"forkfork": {
+			Extends:        "fork",
+			Table:          "furni",
+			DecimalStrings: &decimal,
+			Reserved:       []string{"1999", "sys_*"},
+		},
+	}
+	require.NoError(t, RegisterConfigProfiles(defs))
+
+	fork := GetProfileByName("fork")
+	assert.Equal(t, "items_base", fork.TableName)
+	assert.Equal(t, "spriteid", fork.Columns[ColSpriteID])
+	assert.Equal(t, "allow_sit", fork.Columns[ColCanSit])
+	assert.Equal(t, EnumBools{}, fork.Bools)
+	assert.False(t, fork.DecimalStrings)
+	assert.Equal(t, map[string]string{OfferFieldID: "offer_id", OfferFieldBuyout: "have_offer", OfferFieldRentID: "rent_offer_id"}, fork.OfferColumns)
+
+	forkfork := GetProfileByName("forkfork")
+	assert.Equal(t, "furni", forkfork.TableName)
+	assert.Equal(t, "spriteid", forkfork.Columns[ColSpriteID])
+	assert.True(t, forkfork.DecimalStrings)
+	assert.Equal(t, []string{"ads_background", "present_wrap*", "1999", "sys_*"}, forkfork.Reserved)
+
+	// The built-in profile is not changed by profiles extending it
+	assert.Equal(t, "sprite_id", GetProfileByName("arcturus").Columns[ColSpriteID])
+	assert.NotContains(t, GetProfileByName("arcturus").OfferColumns, OfferFieldRentID)
+	assert.NotContains(t, GetProfileByName("arcturus").Reserved, "sys_*")
+}
+
+func TestRegisterConfigProfiles_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		defs map[string]config.ProfileConfig
+		want string
+	}{
+		{"unknown base", map[string]config.ProfileConfig{"a": {Extends: "nope"}}, "unknown profile"},
+		{"cycle", map[string]config.ProfileConfig{"a": {Extends: "b"}, "b": {Extends: "a"}}, "extends itself"},
+		{"bad codec", map[string]config.ProfileConfig{"a": {Extends: "comet", Bools: "bit"}}, "boolean encoding"},
+		{"incomplete", map[string]config.ProfileConfig{"a": {Table: "furni", Bools: "tinyint"}}, "required column"},
+		{"bad reserved pattern", map[string]config.ProfileConfig{"a": {Extends: "arcturus", Reserved: []string{"ads_["}}}, "reserved pattern"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorContains(t, RegisterConfigProfiles(tt.defs), tt.want)
+			_, ok := LookupProfile("a")
+			assert.False(t, ok)
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package reconcile
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"sync"
+	"time"
+
+	"asset-manager/core/json"
+	"asset-manager/core/storage"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// Conversion statuses recorded in the conversion log.
+const (
+	// ConversionConverted marks a .swf file converted to a bundled .nitro file.
+	ConversionConverted = "converted"
+	// ConversionFailed marks a .swf file the converter could not convert.
+	ConversionFailed = "failed"
+)
+
+// ConversionLogFile is the name of the conversion log under the .swf source prefix.
+const ConversionLogFile = "conversions.json"
+
+// Conversion records the last conversion of a legacy .swf furniture file.
+type Conversion struct {
+	// Classname is the furniture the file holds, from its file name.
+	Classname string `json:"classname"`
+	// Source is the .swf object or uploaded file name.
+	Source string `json:"source"`
+	// Object is the bundled .nitro object written; empty when the conversion failed.
+	Object string `json:"object,omitempty"`
+	// Status is ConversionConverted or ConversionFailed.
+	Status string `json:"status"`
+	// Error describes why the conversion failed.
+	Error string `json:"error,omitempty"`
+	// ConvertedAt is when the conversion ran.
+	ConvertedAt time.Time `json:"converted_at"`
+}
+
+var (
+	// conversionLogMu guards conversionLog.
+	conversionLogMu sync.RWMutex
+	// conversionLog is the assets bucket object holding the conversion log, if any.
+	conversionLog string
+)
+
+// SetConversionLog makes adapters report the conversion status of each classname from
+// the conversion log under sourcePrefix. Empty disables it, which is the default.
+func SetConversionLog(sourcePrefix string) {
+	conversionLogMu.Lock()
+	defer conversionLogMu.Unlock()
+	conversionLog = ""
+	if sourcePrefix != "" {
+		conversionLog = ConversionLogObject(sourcePrefix)
+	}
+}
+
+// registeredConversionLog returns the object set by SetConversionLog.
+func registeredConversionLog() string {
+	conversionLogMu.RLock()
+	defer conversionLogMu.RUnlock()
+	return conversionLog
+}
+
+// ConversionLogObject returns the conversion log object of a .swf source prefix.
+func ConversionLogObject(sourcePrefix string) string {
+	return path.Join(sourcePrefix, ConversionLogFile)
+}
+
+// LoadConversions reads the conversion log, keyed by classname. A missing log is empty.
+func LoadConversions(ctx context.Context, client storage.Client, bucket, object string) (map[string]Conversion, error) {
+	conversions := make(map[string]Conversion)
+	reader, err := client.GetObject(ctx, bucket, object, minio.GetObjectOptions{})
+	if err != nil {
+		if storage.IsNoSuchKey(err) {
+			return conversions, nil
+		}
+		return nil, fmt.Errorf("failed to read conversion log: %w", err)
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		if storage.IsNoSuchKey(err) {
+			return conversions, nil
+		}
+		return nil, fmt.Errorf("failed to read conversion log: %w", err)
+	}
+	if err := json.Unmarshal(data, &conversions); err != nil {
+		return nil, fmt.Errorf("failed to parse conversion log: %w", err)
+	}
+	return conversions, nil
+}
+
+// RecordConversions merges records into the conversion log, replacing earlier records
+// of the same classnames.
+func RecordConversions(ctx context.Context, client storage.Client, bucket, object string, records []Conversion) error {
+	if len(records) == 0 {
+		return nil
+	}
+	conversions, err := LoadConversions(ctx, client, bucket, object)
+	if err != nil {
+		return err
+	}
+	for _, record := range records {
+		conversions[record.Classname] = record
+	}
+
+	data, err := json.MarshalIndent(conversions, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode conversion log: %w", err)
+	}
+	if _, err := client.PutObject(ctx, bucket, object, bytes.NewReader(data), int64(len(data)), minio.PutObjectOptions{ContentType: "application/json"}); err != nil {
+		return fmt.Errorf("failed to write conversion log: %w", err)
+	}
+	return nil
+}
+
+// loadConversions refreshes the conversion statuses reported by GetMetadata from the
+// registered conversion log, if any.
+func (a *FurnitureAdapter) loadConversions(ctx context.Context, client storage.Client, bucket string) error {
+	object := registeredConversionLog()
+	if object == "" {
+		return nil
+	}
+	conversions, err := LoadConversions(ctx, client, bucket, object)
+	if err != nil {
+		return err
+	}
+	a.conversionsMu.Lock()
+	a.conversions = conversions
+	a.conversionsMu.Unlock()
+	return nil
+}
+
+// conversionStatus returns the recorded conversion status of classname, if any.
+func (a *FurnitureAdapter) conversionStatus(classname string) (string, bool) {
+	a.conversionsMu.RLock()
+	defer a.conversionsMu.RUnlock()
+	record, ok := a.conversions[classname]
+	return record.Status, ok
+}
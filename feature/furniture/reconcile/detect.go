@@ -0,0 +1,29 @@
+package reconcile
+
+import (
+	"asset-manager/core/database"
+	"asset-manager/core/server"
+
+	"gorm.io/gorm"
+)
+
+// DetectEmulator returns the emulator name matching the schema of db (see
+// database.DetectEmulator). "auto" resolves to whichever emulator the database
+// belongs to. Arcturus ships two items_base layouts, so the configured "arcturus"
+// resolves to "arcturus-ms" on Morningstar 4.x databases, whose flags are enums, and
+// stays "arcturus" otherwise. Other names, including an explicit "arcturus-ms", are
+// returned unchanged without querying the database.
+func DetectEmulator(db *gorm.DB, emulator string) (string, error) {
+	if emulator != server.EmulatorAuto && emulator != server.EmulatorArcturus {
+		return emulator, nil
+	}
+
+	detected, err := database.DetectEmulator(db)
+	if err != nil {
+		return emulator, err
+	}
+	if emulator == server.EmulatorArcturus && detected != server.EmulatorArcturusMS {
+		return emulator, nil
+	}
+	return detected, nil
+}
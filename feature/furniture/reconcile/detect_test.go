@@ -0,0 +1,60 @@
+package reconcile
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+// TestDetectEmulator tests that "auto" takes the detected emulator, and "arcturus"
+// only the detected Arcturus variant.
+func TestDetectEmulator(t *testing.T) {
+	conn, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	db, err := gorm.Open(mysql.New(mysql.Config{Conn: conn, SkipInitializeWithVersion: true}), &gorm.Config{})
+	require.NoError(t, err)
+
+	expectSchema := func(table, flag, flagType string) {
+		mock.ExpectQuery("SHOW TABLES").WillReturnRows(sqlmock.NewRows([]string{"Tables_in_emulator"}).AddRow(table))
+		mock.ExpectQuery("SHOW COLUMNS FROM `" + table + "`").WillReturnRows(
+			sqlmock.NewRows([]string{"Field", "Type", "Null", "Key", "Default", "Extra"}).
+				AddRow("id", "int(11)", "NO", "PRI", nil, "auto_increment").
+				AddRow(flag, flagType, "NO", "", "0", ""))
+	}
+
+	expectSchema("items_base", "allow_sit", "enum('0','1')")
+	emulator, err := DetectEmulator(db, "arcturus")
+	require.NoError(t, err)
+	assert.Equal(t, "arcturus-ms", emulator)
+
+	expectSchema("items_base", "allow_sit", "tinyint(1)")
+	emulator, err = DetectEmulator(db, "arcturus")
+	require.NoError(t, err)
+	assert.Equal(t, "arcturus", emulator)
+
+	// An Arcturus setting never switches to another emulator
+	expectSchema("furniture", "can_sit", "enum('0','1')")
+	emulator, err = DetectEmulator(db, "arcturus")
+	require.NoError(t, err)
+	assert.Equal(t, "arcturus", emulator)
+
+	expectSchema("furniture", "can_sit", "enum('0','1')")
+	emulator, err = DetectEmulator(db, "auto")
+	require.NoError(t, err)
+	assert.Equal(t, "comet", emulator)
+
+	mock.ExpectQuery("SHOW TABLES").WillReturnError(assert.AnError)
+	emulator, err = DetectEmulator(db, "auto")
+	assert.Error(t, err)
+	assert.Equal(t, "auto", emulator)
+
+	// Other emulators are never inspected
+	emulator, err = DetectEmulator(db, "comet")
+	require.NoError(t, err)
+	assert.Equal(t, "comet", emulator)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,130 @@
+package reconcile
+
+import (
+	"html"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// EncodingIssue names a way a stored name was garbled on its way into the database.
+type EncodingIssue string
+
+const (
+	// EncodingEntities is a name holding HTML entities (&amp;, &#233;, ...) instead of
+	// the characters they stand for.
+	EncodingEntities EncodingIssue = "html_entities"
+
+	// EncodingDoubleUTF8 is a UTF-8 name read as latin1 and encoded to UTF-8 again, so
+	// "Café" is stored as "CafÃ©".
+	EncodingDoubleUTF8 EncodingIssue = "double_encoded"
+)
+
+// maxRepairSteps bounds how many decodings RepairName chains, e.g. entities inside a
+// name that was then double encoded twice.
+const maxRepairSteps = 3
+
+// nameDecoder undoes one kind of garbling, reporting false when it does not apply.
+type nameDecoder struct {
+	issue  EncodingIssue
+	decode func(string) (string, bool)
+}
+
+// nameDecoders lists the decodings RepairName tries at every step.
+var nameDecoders = []nameDecoder{
+	{EncodingEntities, decodeEntities},
+	{EncodingDoubleUTF8, decodeDoubleUTF8},
+}
+
+// RepairName reports whether stored is reference garbled by HTML entities or double
+// UTF-8 encoding, and returns the decodings that turn it back into reference in the
+// order they apply. Names that only differ from reference in other ways are left to
+// the regular name comparison.
+func RepairName(stored, reference string) ([]EncodingIssue, bool) {
+	if stored == reference || reference == "" {
+		return nil, false
+	}
+
+	type candidate struct {
+		name   string
+		issues []EncodingIssue
+	}
+	queue := []candidate{{name: stored}}
+	for step := 0; step < maxRepairSteps && len(queue) > 0; step++ {
+		var next []candidate
+		for _, c := range queue {
+			for _, decoder := range nameDecoders {
+				decoded, ok := decoder.decode(c.name)
+				if !ok {
+					continue
+				}
+				issues := append(slices.Clone(c.issues), decoder.issue)
+				if decoded == reference {
+					return issues, true
+				}
+				next = append(next, candidate{name: decoded, issues: issues})
+			}
+		}
+		queue = next
+	}
+	return nil, false
+}
+
+// FormatIssues joins issues with "+", e.g. "html_entities+double_encoded".
+func FormatIssues(issues []EncodingIssue) string {
+	names := make([]string, len(issues))
+	for i, issue := range issues {
+		names[i] = string(issue)
+	}
+	return strings.Join(names, "+")
+}
+
+// decodeEntities replaces the HTML entities of name.
+func decodeEntities(name string) (string, bool) {
+	decoded := html.UnescapeString(name)
+	return decoded, decoded != name
+}
+
+// decodeDoubleUTF8 reads the characters of name as the windows-1252 bytes MySQL's
+// latin1 maps them to and returns those bytes as UTF-8. It fails when a character
+// has no such byte or the bytes are not valid UTF-8, as for correctly stored names.
+func decodeDoubleUTF8(name string) (string, bool) {
+	raw := make([]byte, 0, len(name))
+	multibyte := false
+	for _, r := range name {
+		b, ok := latin1Byte(r)
+		if !ok {
+			return "", false
+		}
+		multibyte = multibyte || b >= 0x80
+		raw = append(raw, b)
+	}
+	if !multibyte || !utf8.Valid(raw) {
+		return "", false
+	}
+	return string(raw), true
+}
+
+// cp1252Bytes maps the characters windows-1252 places in 0x80-0x9F to their byte.
+var cp1252Bytes = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87,
+	'ˆ': 0x88, '‰': 0x89, 'Š': 0x8A, '‹': 0x8B, 'Œ': 0x8C, 'Ž': 0x8E,
+	'‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97,
+	'˜': 0x98, '™': 0x99, 'š': 0x9A, '›': 0x9B, 'œ': 0x9C, 'ž': 0x9E, 'Ÿ': 0x9F,
+}
+
+// latin1Byte returns the windows-1252 byte of r. The five bytes windows-1252 leaves
+// undefined pass through as the matching C1 control characters, as MySQL does.
+func latin1Byte(r rune) (byte, bool) {
+	if b, ok := cp1252Bytes[r]; ok {
+		return b, true
+	}
+	if r < 0x80 || (r >= 0xA0 && r <= 0xFF) {
+		return byte(r), true
+	}
+	switch r {
+	case 0x81, 0x8D, 0x8F, 0x90, 0x9D:
+		return byte(r), true
+	}
+	return 0, false
+}
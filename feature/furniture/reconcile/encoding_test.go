@@ -0,0 +1,60 @@
+package reconcile
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// doubleEncode reads the UTF-8 bytes of name as windows-1252, as a latin1 connection does.
+func doubleEncode(name string) string {
+	runes := make(map[byte]rune, len(cp1252Bytes))
+	for r, b := range cp1252Bytes {
+		runes[b] = r
+	}
+	var sb strings.Builder
+	for _, b := range []byte(name) {
+		if r, ok := runes[b]; ok {
+			sb.WriteRune(r)
+		} else {
+			sb.WriteRune(rune(b))
+		}
+	}
+	return sb.String()
+}
+
+// TestRepairName tests which garbled names are traced back to the gamedata name.
+func TestRepairName(t *testing.T) {
+	tests := []struct {
+		name      string
+		stored    string
+		reference string
+		want      []EncodingIssue
+	}{
+		{"entities", "Tom &amp; Jerry&#39;s Sofa", "Tom & Jerry's Sofa", []EncodingIssue{EncodingEntities}},
+		{"double encoded", "CafÃ© Table", "Café Table", []EncodingIssue{EncodingDoubleUTF8}},
+		{"double encoded cp1252", doubleEncode("Sofa – “Red”"), "Sofa – “Red”", []EncodingIssue{EncodingDoubleUTF8}},
+		{"encoded twice", doubleEncode(doubleEncode("Crème brûlée")), "Crème brûlée", []EncodingIssue{EncodingDoubleUTF8, EncodingDoubleUTF8}},
+		{"entities then encoded", doubleEncode("Café &amp; Bar"), "Café & Bar", []EncodingIssue{EncodingEntities, EncodingDoubleUTF8}},
+		{"entity of a multibyte character", "Caf&#233;", "Café", []EncodingIssue{EncodingEntities}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues, ok := RepairName(tt.stored, tt.reference)
+			assert.True(t, ok)
+			assert.Equal(t, tt.want, issues)
+		})
+	}
+
+	for _, tt := range []struct{ stored, reference string }{
+		{"Café Table", "Café Table"},
+		{"Old Sofa", "New Sofa"},
+		{"Caf&eacute;", "Cafe"},
+		{"CafÃ© Table", ""},
+		{"Plain ASCII", "Plain ASCII "},
+	} {
+		_, ok := RepairName(tt.stored, tt.reference)
+		assert.False(t, ok, tt.stored)
+	}
+}
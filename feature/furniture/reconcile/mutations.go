@@ -5,12 +5,17 @@ package reconcile
 import (
 	"bytes"
 	"context"
-	"encoding/json"
+	"database/sql"
+	"errors"
 	"fmt"
 	"io"
+	"slices"
 	"strconv"
 
+	"asset-manager/core/json"
 	"asset-manager/core/reconcile"
+	"asset-manager/core/upload"
+	"asset-manager/core/utils"
 
 	"github.com/minio/minio-go/v7"
 )
@@ -54,7 +59,7 @@ func (a *FurnitureAdapter) DeleteGamedata(ctx context.Context, key string) error
 	defer a.mu.Unlock()
 
 	// Read the entire FurnitureData.json
-	reader, err := a.client.GetObject(ctx, a.bucket, a.gamedataObj, minio.GetObjectOptions{})
+	reader, err := a.client.GetObject(ctx, a.buckets.Gamedata, a.gamedataObj, minio.GetObjectOptions{})
 	if err != nil {
 		return fmt.Errorf("failed to get gamedata: %w", err)
 	}
@@ -104,7 +109,7 @@ func (a *FurnitureAdapter) DeleteGamedata(ctx context.Context, key string) error
 	// Write back to storage
 	_, err = a.client.PutObject(
 		ctx,
-		a.bucket,
+		a.buckets.Gamedata,
 		a.gamedataObj,
 		io.NopCloser(bytes.NewReader(newData)),
 		int64(len(newData)),
@@ -124,9 +129,7 @@ func (a *FurnitureAdapter) DeleteStorage(ctx context.Context, key string) error
 	}
 
 	// Get classname from key
-	a.mu.RLock()
-	classname, ok := a.idToClassname[key]
-	a.mu.RUnlock()
+	classname, ok := a.idToClassnameOf(key)
 
 	if !ok {
 		// Classname not found - best effort: log and skip
@@ -135,10 +138,10 @@ func (a *FurnitureAdapter) DeleteStorage(ctx context.Context, key string) error
 	}
 
 	// Build object key
-	objectKey := fmt.Sprintf("%s/%s.nitro", a.storagePrefix, classname)
+	objectKey := a.objectKey(classname)
 
 	// Delete object
-	err := a.client.RemoveObject(ctx, a.bucket, objectKey, minio.RemoveObjectOptions{})
+	err := a.client.RemoveObject(ctx, a.buckets.Assets, objectKey, minio.RemoveObjectOptions{})
 	if err != nil {
 		return fmt.Errorf("failed to delete storage object %s: %w", objectKey, err)
 	}
@@ -146,8 +149,88 @@ func (a *FurnitureAdapter) DeleteStorage(ctx context.Context, key string) error
 	return nil
 }
 
+// MoveStorage moves a misplaced .nitro file to its canonical path under the storage
+// prefix. The object is copied before the misplaced one is removed, so a failed move
+// leaves the file where it was.
+func (a *FurnitureAdapter) MoveStorage(ctx context.Context, key, from string) error {
+	if a.client == nil {
+		return fmt.Errorf("mutation context not set, call SetMutationContext first")
+	}
+
+	classname, ok := a.idToClassnameOf(key)
+	if !ok {
+		return fmt.Errorf("classname not found for key %s", key)
+	}
+	objectKey := a.objectKey(classname)
+
+	reader, err := a.client.GetObject(ctx, a.buckets.Assets, from, minio.GetObjectOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get storage object %s: %w", from, err)
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return fmt.Errorf("failed to read storage object %s: %w", from, err)
+	}
+	if _, err := a.client.PutObject(ctx, a.buckets.Assets, objectKey, bytes.NewReader(data), int64(len(data)), minio.PutObjectOptions{}); err != nil {
+		return fmt.Errorf("failed to write storage object %s: %w", objectKey, err)
+	}
+	if err := a.client.RemoveObject(ctx, a.buckets.Assets, from, minio.RemoveObjectOptions{}); err != nil {
+		return fmt.Errorf("failed to delete storage object %s: %w", from, err)
+	}
+
+	return nil
+}
+
+// DownloadStorage fetches the .nitro file of a furniture item missing in storage from
+// upstream and writes it to its canonical path. Files that are no Nitro bundle or that
+// the scanner rejects are reported as reconcile.ErrUpstreamUnavailable.
+func (a *FurnitureAdapter) DownloadStorage(ctx context.Context, key string, upstream reconcile.Upstream) error {
+	if a.client == nil {
+		return fmt.Errorf("mutation context not set, call SetMutationContext first")
+	}
+
+	classname, ok := a.idToClassnameOf(key)
+	if !ok {
+		return fmt.Errorf("classname not found for key %s", key)
+	}
+	objectKey := a.objectKey(classname)
+
+	data, err := upstream.Fetch(ctx, a.client, objectKey)
+	if err != nil {
+		return err
+	}
+	if err := upload.SniffNitro(data); err != nil {
+		return fmt.Errorf("upstream %s: %v: %w", objectKey, err, reconcile.ErrUpstreamUnavailable)
+	}
+	if err := upload.ScanObject(ctx, a.client, a.buckets.Assets, objectKey, data); err != nil {
+		var infected *upload.InfectedError
+		if errors.As(err, &infected) {
+			return fmt.Errorf("upstream %s: %v: %w", objectKey, err, reconcile.ErrUpstreamUnavailable)
+		}
+		return err
+	}
+	if _, err := a.client.PutObject(ctx, a.buckets.Assets, objectKey, bytes.NewReader(data), int64(len(data)), minio.PutObjectOptions{}); err != nil {
+		return fmt.Errorf("failed to write storage object %s: %w", objectKey, err)
+	}
+
+	return nil
+}
+
+// maxNameLen truncates synced names to fit the DB schema limit (varchar(120)).
+// We use 110 as a safe buffer.
+const maxNameLen = 110
+
 // SyncDBFromGamedata updates DB fields to match gamedata using server-aware mapping.
+// Fields the field policy lets the DB win are kept (see reconcile.FieldPolicy).
 func (a *FurnitureAdapter) SyncDBFromGamedata(ctx context.Context, key string, gdItem reconcile.GDItem) error {
+	return a.syncDB(ctx, key, gdItem, nil)
+}
+
+// syncDB updates the DB fields of key to match gamedata, limited to fields when any
+// are given.
+func (a *FurnitureAdapter) syncDB(ctx context.Context, key string, gdItem reconcile.GDItem, fields []string) error {
 	if a.db == nil {
 		return fmt.Errorf("mutation context not set, call SetMutationContext first")
 	}
@@ -155,37 +238,71 @@ func (a *FurnitureAdapter) SyncDBFromGamedata(ctx context.Context, key string, g
 	profile := GetProfileByName(a.serverProfile)
 	gd := gdItem.(GDItem)
 
-	// Build update map based on field mappings
-	// Truncate strings to match updated DB schema limits (varchar(120))
-	// We use 110 as a safe buffer.
-	const maxNameLen = 110
+	// Convert key to sprite_id
+	spriteID, err := strconv.Atoi(key)
+	if err != nil {
+		return fmt.Errorf("invalid key %s: %w", key, err)
+	}
+
+	// Build update map based on field mappings and the field policy
+	syncs := func(field string) bool { return reconcile.SyncsField(field, reconcile.SourceGamedata, fields) }
+	updates := map[string]any{}
+	if syncs("classname") {
+		updates[profile.Columns[ColItemName]] = truncateStr(gd.ClassName, maxNameLen)
+	}
+	if syncs("name") {
+		updates[profile.Columns[ColPublicName]] = truncateStr(gd.Name, maxNameLen)
+	}
+	if col, ok := profile.Columns[ColWidth]; ok && syncs("width") {
+		updates[col] = gd.XDim
+	}
+	if col, ok := profile.Columns[ColLength]; ok && syncs("length") {
+		updates[col] = gd.YDim
+	}
 
-	updates := map[string]any{
-		profile.Columns[ColItemName]:    truncateStr(gd.ClassName, maxNameLen),
-		profile.Columns[ColPublicName]:  truncateStr(gd.Name, maxNameLen),
-		profile.Columns[ColWidth]:       gd.XDim,
-		profile.Columns[ColLength]:      gd.YDim,
-		profile.Columns[ColStackHeight]: 1, // Default, gamedata doesn't always have this
+	// Gamedata has no stack height: varchar values (Comet) are kept and only
+	// normalized, numeric columns are reset to the default of 1
+	if col, ok := profile.Columns[ColStackHeight]; ok {
+		switch {
+		case !profile.DecimalStrings:
+			updates[col] = 1
+		case !a.columnMissing(col):
+			if err := a.normalizeStackHeight(ctx, profile, spriteID, updates); err != nil {
+				return err
+			}
+		}
 	}
 
-	// Add boolean fields if mapped
-	if col, ok := profile.Columns[ColCanSit]; ok {
-		updates[col] = gd.CanSitOn
+	// Add boolean fields if mapped, encoded by the profile's codec
+	if col, ok := profile.Columns[ColCanSit]; ok && syncs("can_sit") {
+		updates[col] = profile.Bools.Encode(gd.CanSitOn)
 	}
-	if col, ok := profile.Columns[ColCanWalk]; ok {
-		updates[col] = gd.CanStandOn
+	if col, ok := profile.Columns[ColCanWalk]; ok && syncs("can_walk") {
+		updates[col] = profile.Bools.Encode(gd.CanStandOn)
 	}
-	if col, ok := profile.Columns[ColCanLay]; ok {
-		updates[col] = gd.CanLayOn
+	if col, ok := profile.Columns[ColCanLay]; ok && syncs("can_lay") {
+		updates[col] = profile.Bools.Encode(gd.CanLayOn)
 	}
-	if col, ok := profile.Columns[ColType]; ok {
+	if col, ok := profile.Columns[ColType]; ok && syncs("type") {
 		updates[col] = gd.Type
 	}
+	// Descriptions only differ at warning severity and are synced when enabled
+	if col, ok := profile.Columns[ColDescription]; ok && reconcile.SyncsWarning("description") && syncs("description") {
+		updates[col] = gd.Description
+	}
+	// Wall items lose their footprint and floor flags when the normalization is enabled
+	if normalizesWallPlacement(gd, fields) {
+		normalizeWallPlacement(profile, updates)
+	}
 
-	// Convert key to sprite_id
-	spriteID, err := strconv.Atoi(key)
-	if err != nil {
-		return fmt.Errorf("invalid key %s: %w", key, err)
+	// Columns the table lacks cannot be written
+	for col := range updates {
+		if a.columnMissing(col) {
+			delete(updates, col)
+		}
+	}
+	if len(updates) == 0 {
+		return nil
 	}
 
 	// Execute update
@@ -205,6 +322,197 @@ func (a *FurnitureAdapter) SyncDBFromGamedata(ctx context.Context, key string, g
 	return nil
 }
 
+// SyncGamedataFromDB updates the gamedata entry of key to match its DB row
+// (reconcile.GamedataSyncer). Fields the field policy lets gamedata win are kept.
+func (a *FurnitureAdapter) SyncGamedataFromDB(ctx context.Context, key string, dbItem reconcile.DBItem, gdItem reconcile.GDItem) error {
+	return a.SyncGamedataBatch(ctx, []reconcile.Action{{Type: reconcile.ActionSyncGamedata, Key: key, DBItem: dbItem, GDItem: gdItem}})
+}
+
+// InsertGamedata adds the gamedata entry of key, built from its DB row
+// (reconcile.GamedataInserter).
+func (a *FurnitureAdapter) InsertGamedata(ctx context.Context, key string, dbItem reconcile.DBItem) error {
+	return a.InsertGamedataBatch(ctx, []reconcile.Action{{Type: reconcile.ActionInsertGamedata, Key: key, DBItem: dbItem}})
+}
+
+// normalizeStackHeight adds a canonical stack_height to updates when the stored varchar
+// value uses a comma separator or padding. Unparseable values are left for a human.
+func (a *FurnitureAdapter) normalizeStackHeight(ctx context.Context, profile ServerProfile, spriteID int, updates map[string]any) error {
+	col := profile.Columns[ColStackHeight]
+
+	var raw *string
+	err := a.db.WithContext(ctx).
+		Table(profile.TableName).
+		Select(col).
+		Where(profile.Columns[ColSpriteID]+" = ?", spriteID).
+		Limit(1).
+		Row().
+		Scan(&raw)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return fmt.Errorf("failed to read stack height: %w", err)
+	}
+	if raw == nil {
+		return nil
+	}
+
+	if normalized, ok := utils.NormalizeDecimal(*raw); ok && normalized != *raw {
+		updates[col] = normalized
+	}
+	return nil
+}
+
+// UpdateCache applies executed actions to cached indices (reconcile.CacheUpdater).
+// Syncs rewrite the cached DB item the same way SyncDBFromGamedata rewrites the row,
+// reverse syncs the cached gamedata item the way SyncGamedataBatch rewrites the entry,
+// and inserts add the item InsertGamedataBatch writes.
+func (a *FurnitureAdapter) UpdateCache(cache *reconcile.ReconcileCache, actions []reconcile.Action) error {
+	for _, action := range actions {
+		switch action.Type {
+		case reconcile.ActionSyncDB:
+			dbItem, ok := cache.DBIndex[action.Key]
+			if !ok {
+				return fmt.Errorf("synced key %s is not in the DB index", action.Key)
+			}
+			cache.DBIndex[action.Key] = a.syncedDBItem(dbItem.(DBItem), action.GDItem.(GDItem), action.Fields)
+		case reconcile.ActionSyncGamedata:
+			gdItem, ok := cache.GDIndex[action.Key]
+			if !ok {
+				return fmt.Errorf("synced key %s is not in the gamedata index", action.Key)
+			}
+			cache.GDIndex[action.Key] = syncedGDItem(action.DBItem.(DBItem), gdItem.(GDItem), action.Fields)
+		case reconcile.ActionInsertGamedata:
+			cache.GDIndex[action.Key] = insertedGDItem(action.DBItem.(DBItem))
+		}
+	}
+	cache.ApplyDeletions(actions)
+	return nil
+}
+
+// syncedDBItem returns the DB item as a sync of fields (all when empty) leaves it.
+func (a *FurnitureAdapter) syncedDBItem(db DBItem, gd GDItem, fields []string) DBItem {
+	profile := GetProfileByName(a.serverProfile)
+	syncs := func(field string) bool { return reconcile.SyncsField(field, reconcile.SourceGamedata, fields) }
+
+	if syncs("classname") {
+		db.ItemName = truncateStr(gd.ClassName, maxNameLen)
+	}
+	if syncs("name") {
+		db.PublicName = truncateStr(gd.Name, maxNameLen)
+	}
+	if syncs("width") {
+		db.Width = gd.XDim
+	}
+	if syncs("length") {
+		db.Length = gd.YDim
+	}
+
+	if _, ok := profile.Columns[ColCanSit]; ok && syncs("can_sit") {
+		db.CanSit = gd.CanSitOn
+	}
+	if _, ok := profile.Columns[ColCanWalk]; ok && syncs("can_walk") {
+		db.CanWalk = gd.CanStandOn
+	}
+	if _, ok := profile.Columns[ColCanLay]; ok && syncs("can_lay") {
+		db.CanLay = gd.CanLayOn
+	}
+	if _, ok := profile.Columns[ColType]; ok && syncs("type") {
+		db.Type = gd.Type
+	}
+	if _, ok := profile.Columns[ColDescription]; ok && reconcile.SyncsWarning("description") && syncs("description") {
+		db.Description = gd.Description
+	}
+	if _, ok := profile.Columns[ColStackHeight]; ok && !profile.DecimalStrings {
+		db.StackHeight = 1
+	}
+	if normalizesWallPlacement(gd, fields) {
+		db = normalizedWallItem(db)
+	}
+
+	return db
+}
+
+// syncedGDItem returns the gamedata item as a reverse sync of fields (all when empty)
+// leaves it. Only the fields CompareFields finds mismatched change, so a DB name that
+// is the classname keeps the gamedata name.
+func syncedGDItem(db DBItem, gd GDItem, fields []string) GDItem {
+	compared := func(field string) bool {
+		return !slices.Contains(db.NotCompared, field) && reconcile.SyncsField(field, reconcile.SourceDB, fields)
+	}
+
+	if compared("classname") {
+		gd.ClassName = db.ItemName
+	}
+	// Names are checked against the synced classname, as the next compare will
+	names := reconcile.Names()
+	if compared("name") && !names.Equal(db.PublicName, gd.Name) && !names.Equal(db.PublicName, gd.ClassName) {
+		gd.Name = db.PublicName
+	}
+	if compared("width") {
+		gd.XDim = db.Width
+	}
+	if compared("length") {
+		gd.YDim = db.Length
+	}
+	if compared("can_sit") {
+		gd.CanSitOn = db.CanSit
+	}
+	if compared("can_walk") {
+		gd.CanStandOn = db.CanWalk
+	}
+	if compared("can_lay") {
+		gd.CanLayOn = db.CanLay
+	}
+	if compared("description") && reconcile.SyncsWarning("description") {
+		gd.Description = db.Description
+	}
+	// Only wall items ("i") belong in wallitemtypes
+	if compared("type") {
+		if db.Type == "i" {
+			gd.Type = "i"
+		} else if gd.Type == "i" {
+			gd.Type = "s"
+		}
+	}
+
+	return gd
+}
+
+// insertedGDItem returns the gamedata item an insert builds from a DB row, so the next
+// compare finds them equal. Dimensions the table does not hold default to 1, and the
+// item gets no catalog offer (-1).
+func insertedGDItem(db DBItem) GDItem {
+	gd := GDItem{
+		ID:          db.SpriteID,
+		ClassName:   db.ItemName,
+		Name:        db.PublicName,
+		XDim:        db.Width,
+		YDim:        db.Length,
+		CanSitOn:    db.CanSit,
+		CanStandOn:  db.CanWalk,
+		CanLayOn:    db.CanLay,
+		Description: db.Description,
+		OfferID:     -1,
+		RentOfferID: -1,
+		Type:        "s",
+	}
+	if gd.Name == "" {
+		gd.Name = db.ItemName
+	}
+	if slices.Contains(db.NotCompared, "width") {
+		gd.XDim = 1
+	}
+	if slices.Contains(db.NotCompared, "length") {
+		gd.YDim = 1
+	}
+	// Only wall items ("i") belong in wallitemtypes
+	if db.Type == "i" {
+		gd.Type = "i"
+	}
+	return gd
+}
+
 // truncateStr truncates a string to the specified length.
 func truncateStr(s string, maxLen int) string {
 	if len(s) <= maxLen {
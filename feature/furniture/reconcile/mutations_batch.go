@@ -3,15 +3,17 @@ package reconcile
 // Batch mutation operations for performance optimization.
 
 import (
-	"asset-manager/core/reconcile"
 	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
 	"io"
 	"strconv"
 	"sync"
 
+	"asset-manager/core/json"
+	"asset-manager/core/reconcile"
+	"asset-manager/core/storage"
+
 	"github.com/minio/minio-go/v7"
 )
 
@@ -25,54 +27,35 @@ func (a *FurnitureAdapter) DeleteStorageBatch(ctx context.Context, keys []string
 		return nil
 	}
 
-	// Build object info channel for batch deletion
-	objectsCh := make(chan minio.ObjectInfo, len(keys))
+	// Resolve object keys, remembering which plan key each belongs to
+	objects := make([]string, 0, len(keys))
+	keyByObject := make(map[string]string, len(keys))
 
 	for _, key := range keys {
-		var classname string
-
-		// Check if key is numeric (ID) or already a classname/relPath
-		if _, err := strconv.Atoi(key); err == nil {
-			// Key is an ID, likely a mapped item.
-			a.mu.RLock()
-			cn, ok := a.idToClassname[key]
-			a.mu.RUnlock()
-
-			if ok {
-				// It's a mapped item, use the classname
-				classname = cn
-			} else {
-				// Numeric key but not mapped?
-				// This happens if ExtractStorageKey returns a numeric filename (e.g. 00011.nitro)
-				// which is an orphan. We treat it as the relative path.
-				classname = key
-			}
-		} else {
-			// Key is already a classname/relPath (storage orphan)
-			classname = key
-		}
-
-		objectKey := fmt.Sprintf("%s/%s.nitro", a.storagePrefix, classname)
-		objectsCh <- minio.ObjectInfo{Key: objectKey}
+		objectKey := a.storageObject(key)
+		objects = append(objects, objectKey)
+		keyByObject[objectKey] = key
 	}
-	close(objectsCh)
 
-	// Execute batch deletion
-	errorCh := a.client.RemoveObjects(ctx, a.bucket, objectsCh, minio.RemoveObjectsOptions{})
-
-	// Collect any errors
-	var errors []string
-	for err := range errorCh {
-		if err.Err != nil {
-			errors = append(errors, fmt.Sprintf("%s: %v", err.ObjectName, err.Err))
-		}
+	// Execute batch deletion, retrying transient failures
+	failures := storage.RemoveObjectsWithRetry(ctx, a.client, a.buckets.Assets, objects, a.removeRetry)
+	if len(failures) == 0 {
+		return nil
 	}
 
-	if len(errors) > 0 {
-		return fmt.Errorf("batch delete had %d errors: %v", len(errors), errors)
+	batchErr := &reconcile.BatchDeleteError{Failures: make([]reconcile.DeleteFailure, 0, len(failures))}
+	for _, f := range failures {
+		batchErr.Failures = append(batchErr.Failures, reconcile.DeleteFailure{
+			Key:       keyByObject[f.Object],
+			Action:    reconcile.ActionDeleteStorage,
+			Object:    f.Object,
+			Code:      f.Code,
+			Message:   f.Err.Error(),
+			Retriable: f.Retriable,
+			Attempts:  f.Attempts,
+		})
 	}
-
-	return nil
+	return batchErr
 }
 
 // DeleteDBBatch deletes multiple DB rows efficiently using IN clause.
@@ -155,9 +138,11 @@ func (a *FurnitureAdapter) SyncDBBatch(ctx context.Context, actions []reconcile.
 			for action := range actionsCh {
 				// Reuse existing single-item Sync logic
 				// It is self-contained and safe for concurrent use (uses local scope vars)
-				if err := a.SyncDBFromGamedata(ctx, action.Key, action.GDItem); err != nil {
+				if err := a.syncDB(ctx, action.Key, action.GDItem, action.Fields); err != nil {
 					errorCh <- fmt.Errorf("sync failed for %s: %w", action.Key, err)
+					continue
 				}
+				reconcile.ReportApplied(ctx)
 			}
 		}()
 	}
@@ -193,7 +178,7 @@ func (a *FurnitureAdapter) DeleteGamedataBatch(ctx context.Context, keys []strin
 	defer a.mu.Unlock()
 
 	// Read the entire FurnitureData.json
-	reader, err := a.client.GetObject(ctx, a.bucket, a.gamedataObj, minio.GetObjectOptions{})
+	reader, err := a.client.GetObject(ctx, a.buckets.Gamedata, a.gamedataObj, minio.GetObjectOptions{})
 	if err != nil {
 		return fmt.Errorf("failed to get gamedata: %w", err)
 	}
@@ -247,7 +232,7 @@ func (a *FurnitureAdapter) DeleteGamedataBatch(ctx context.Context, keys []strin
 	// Write back to storage
 	_, err = a.client.PutObject(
 		ctx,
-		a.bucket,
+		a.buckets.Gamedata,
 		a.gamedataObj,
 		io.NopCloser(bytes.NewReader(newData)),
 		int64(len(newData)),
@@ -259,3 +244,252 @@ func (a *FurnitureAdapter) DeleteGamedataBatch(ctx context.Context, keys []strin
 
 	return nil
 }
+
+// SyncGamedataBatch rewrites the gamedata entries of multiple items from their DB rows
+// in one write. Entries are edited in place, so fields the adapter does not model
+// (e.g. partcolors) are kept, and items whose type changed move between
+// roomitemtypes and wallitemtypes.
+func (a *FurnitureAdapter) SyncGamedataBatch(ctx context.Context, actions []reconcile.Action) error {
+	if a.client == nil {
+		return fmt.Errorf("mutation context not set, call SetMutationContext first")
+	}
+
+	if len(actions) == 0 {
+		return nil
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	doc, err := a.readGamedataDoc(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Index the synced items by gamedata ID
+	synced := make(map[int]GDItem, len(actions))
+	previous := make(map[int]GDItem, len(actions))
+	for _, action := range actions {
+		gd := action.GDItem.(GDItem)
+		previous[gd.ID] = gd
+		synced[gd.ID] = syncedGDItem(action.DBItem.(DBItem), gd, action.Fields)
+	}
+
+	// Rewrite the entries, sorting them into their section by type
+	sections := map[string][]any{"roomitemtypes": {}, "wallitemtypes": {}}
+	for _, section := range []string{"roomitemtypes", "wallitemtypes"} {
+		for _, raw := range gamedataEntries(doc, section) {
+			target := section
+			if entry, ok := raw.(map[string]any); ok {
+				id, _ := entry["id"].(float64)
+				if item, ok := synced[int(id)]; ok {
+					applyGamedataItem(entry, previous[int(id)], item)
+					delete(synced, int(id))
+					target = "roomitemtypes"
+					if item.Type == "i" {
+						target = "wallitemtypes"
+					}
+				}
+			}
+			sections[target] = append(sections[target], raw)
+		}
+	}
+	if len(synced) > 0 {
+		missing := make([]int, 0, len(synced))
+		for id := range synced {
+			missing = append(missing, id)
+		}
+		return fmt.Errorf("no gamedata entries for ids %v", missing)
+	}
+	for section, entries := range sections {
+		setGamedataEntries(doc, section, entries)
+	}
+
+	if err := a.writeGamedataDoc(ctx, doc); err != nil {
+		return err
+	}
+
+	// Storage keys follow the new classnames
+	for _, action := range actions {
+		gd := action.GDItem.(GDItem)
+		classname := syncedGDItem(action.DBItem.(DBItem), gd, action.Fields).ClassName
+		if classname != gd.ClassName {
+			delete(a.classnameToID, gd.ClassName)
+			a.classnameToID[classname] = action.Key
+			a.idToClassname[action.Key] = classname
+		}
+	}
+
+	return nil
+}
+
+// InsertGamedataBatch adds the gamedata entries of multiple items from their DB rows in
+// one write. Wall items go to wallitemtypes, everything else to roomitemtypes; items
+// gamedata already holds are refused before anything is written.
+func (a *FurnitureAdapter) InsertGamedataBatch(ctx context.Context, actions []reconcile.Action) error {
+	if a.client == nil {
+		return fmt.Errorf("mutation context not set, call SetMutationContext first")
+	}
+
+	if len(actions) == 0 {
+		return nil
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	doc, err := a.readGamedataDoc(ctx)
+	if err != nil {
+		return err
+	}
+
+	inserted := make([]GDItem, 0, len(actions))
+	ids := make(map[int]struct{}, len(actions))
+	for _, action := range actions {
+		item := insertedGDItem(action.DBItem.(DBItem))
+		inserted = append(inserted, item)
+		ids[item.ID] = struct{}{}
+	}
+
+	var existing []int
+	for _, section := range []string{"roomitemtypes", "wallitemtypes"} {
+		for _, raw := range gamedataEntries(doc, section) {
+			if entry, ok := raw.(map[string]any); ok {
+				id, _ := entry["id"].(float64)
+				if _, taken := ids[int(id)]; taken {
+					existing = append(existing, int(id))
+				}
+			}
+		}
+	}
+	if len(existing) > 0 {
+		return fmt.Errorf("gamedata already has entries for ids %v", existing)
+	}
+
+	for _, item := range inserted {
+		section := "roomitemtypes"
+		if item.Type == "i" {
+			section = "wallitemtypes"
+		}
+		setGamedataEntries(doc, section, append(gamedataEntries(doc, section), newGamedataEntry(item)))
+	}
+
+	if err := a.writeGamedataDoc(ctx, doc); err != nil {
+		return err
+	}
+
+	// Storage keys of the inserted items now resolve through gamedata
+	for _, item := range inserted {
+		key := strconv.Itoa(item.ID)
+		a.classnameToID[item.ClassName] = key
+		a.idToClassname[key] = item.ClassName
+	}
+
+	return nil
+}
+
+// readGamedataDoc reads FurnitureData.json as a generic document, so rewriting it keeps
+// the fields the adapter does not model.
+func (a *FurnitureAdapter) readGamedataDoc(ctx context.Context) (map[string]any, error) {
+	reader, err := a.client.GetObject(ctx, a.buckets.Gamedata, a.gamedataObj, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get gamedata: %w", err)
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read gamedata: %w", err)
+	}
+
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse gamedata: %w", err)
+	}
+	return doc, nil
+}
+
+// writeGamedataDoc writes doc back as FurnitureData.json.
+func (a *FurnitureAdapter) writeGamedataDoc(ctx context.Context, doc map[string]any) error {
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal gamedata: %w", err)
+	}
+
+	_, err = a.client.PutObject(
+		ctx,
+		a.buckets.Gamedata,
+		a.gamedataObj,
+		io.NopCloser(bytes.NewReader(data)),
+		int64(len(data)),
+		minio.PutObjectOptions{ContentType: "application/json"},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to write gamedata: %w", err)
+	}
+	return nil
+}
+
+// gamedataEntries returns the furnitype entries of a FurnitureData.json section.
+func gamedataEntries(doc map[string]any, section string) []any {
+	parent, _ := doc[section].(map[string]any)
+	entries, _ := parent["furnitype"].([]any)
+	return entries
+}
+
+// setGamedataEntries replaces the furnitype entries of a FurnitureData.json section.
+func setGamedataEntries(doc map[string]any, section string, entries []any) {
+	parent, ok := doc[section].(map[string]any)
+	if !ok {
+		parent = map[string]any{}
+		doc[section] = parent
+	}
+	parent["furnitype"] = entries
+}
+
+// applyGamedataItem writes the fields that differ between from and to into entry.
+func applyGamedataItem(entry map[string]any, from, to GDItem) {
+	if to.Name != from.Name {
+		entry["name"] = to.Name
+	}
+	if to.ClassName != from.ClassName {
+		entry["classname"] = to.ClassName
+	}
+	if to.XDim != from.XDim {
+		entry["xdim"] = to.XDim
+	}
+	if to.YDim != from.YDim {
+		entry["ydim"] = to.YDim
+	}
+	if to.CanSitOn != from.CanSitOn {
+		entry["cansiton"] = to.CanSitOn
+	}
+	if to.CanStandOn != from.CanStandOn {
+		entry["canstandon"] = to.CanStandOn
+	}
+	if to.CanLayOn != from.CanLayOn {
+		entry["canlayon"] = to.CanLayOn
+	}
+	if to.Description != from.Description {
+		entry["description"] = to.Description
+	}
+}
+
+// newGamedataEntry returns the FurnitureData.json entry of an inserted item.
+func newGamedataEntry(item GDItem) map[string]any {
+	return map[string]any{
+		"id":          item.ID,
+		"classname":   item.ClassName,
+		"name":        item.Name,
+		"description": item.Description,
+		"xdim":        item.XDim,
+		"ydim":        item.YDim,
+		"cansiton":    item.CanSitOn,
+		"canstandon":  item.CanStandOn,
+		"canlayon":    item.CanLayOn,
+		"offerid":     item.OfferID,
+		"buyout":      item.Buyout,
+		"rentofferid": item.RentOfferID,
+		"rentbuyout":  item.RentBuyout,
+	}
+}
@@ -1,14 +1,28 @@
 package reconcile
 
 import (
+	"bytes"
+	"compress/zlib"
 	"context"
+	"encoding/binary"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
+	"asset-manager/core/json"
 	"asset-manager/core/reconcile"
+	"asset-manager/core/storage"
+	"asset-manager/core/storage/mocks"
 
+	"github.com/minio/minio-go/v7"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
@@ -47,7 +61,7 @@ func setupTestDB(t *testing.T, dbName string) *gorm.DB {
 func TestSyncDBFromGamedata_Truncation(t *testing.T) {
 	db := setupTestDB(t, "db_truncation")
 	adapter := NewAdapter()
-	adapter.SetMutationContext(db, nil, "", "", "arcturus", "")
+	adapter.SetMutationContext(db, nil, storage.Buckets{}, "", "arcturus", "")
 
 	// Insert initial row
 	initialRow := `INSERT INTO items_base (id, sprite_id, item_name, public_name) VALUES (1, 100, 'old_name', 'Old Name')`
@@ -77,10 +91,120 @@ func TestSyncDBFromGamedata_Truncation(t *testing.T) {
 	assert.Equal(t, expectedName, result["item_name"], "Item Name should be truncated")
 }
 
+// TestSyncDBFromGamedata_NumericStackHeight tests that a sync resets a numeric
+// stack_height to 1, since gamedata carries none.
+func TestSyncDBFromGamedata_NumericStackHeight(t *testing.T) {
+	db := setupTestDB(t, "db_numeric_stack")
+	adapter := NewAdapter()
+	adapter.SetMutationContext(db, nil, storage.Buckets{}, "", "arcturus", "")
+	require.NoError(t, db.Exec(`INSERT INTO items_base (id, sprite_id, item_name, public_name, stack_height) VALUES (1, 100, 'chair', 'Chair', 3)`).Error)
+
+	gdItem := GDItem{ID: 100, ClassName: "chair", Name: "Chair", XDim: 1, YDim: 1}
+	require.NoError(t, adapter.SyncDBFromGamedata(context.Background(), "100", gdItem))
+
+	var stackHeight int
+	require.NoError(t, db.Table("items_base").Where("sprite_id = ?", 100).Pluck("stack_height", &stackHeight).Error)
+	assert.Equal(t, 1, stackHeight)
+	assert.Equal(t, 1.0, adapter.syncedDBItem(DBItem{StackHeight: 3}, gdItem, nil).StackHeight)
+}
+
+// TestSyncDBFromGamedata_MissingColumns tests that a sync leaves out the columns the
+// table lacked when the DB index was loaded.
+func TestSyncDBFromGamedata_MissingColumns(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:db_missing_columns?mode=memory&cache=shared"), &gorm.Config{})
+	assert.NoError(t, err)
+	assert.NoError(t, db.Exec(`CREATE TABLE items_base (id INTEGER PRIMARY KEY, sprite_id INTEGER, item_name VARCHAR(60), public_name VARCHAR(60), length INTEGER, type VARCHAR(1))`).Error)
+	assert.NoError(t, db.Exec(`INSERT INTO items_base (id, sprite_id, item_name, public_name, length, type) VALUES (1, 100, 'chair', 'Chair', 1, 's')`).Error)
+
+	adapter := NewAdapter()
+	adapter.SetMutationContext(db, nil, storage.Buckets{}, "", "arcturus", "")
+	index, err := adapter.LoadDBIndex(context.Background(), db, "arcturus")
+	assert.NoError(t, err)
+	assert.Len(t, index, 1)
+
+	gdItem := GDItem{ID: 100, ClassName: "chair", Name: "Chair", XDim: 2, YDim: 2, CanSitOn: true, Type: "s"}
+	assert.NoError(t, adapter.SyncDBFromGamedata(context.Background(), "100", gdItem))
+
+	var length int
+	assert.NoError(t, db.Table("items_base").Where("sprite_id = ?", 100).Pluck("length", &length).Error)
+	assert.Equal(t, 2, length)
+}
+
+// TestSyncDBFromGamedata_Description tests that descriptions are read from a mapped
+// column and only synced when their warnings are enabled.
+func TestSyncDBFromGamedata_Description(t *testing.T) {
+	profile := ArcturusProfile()
+	profile.Columns[ColDescription] = "description"
+	assert.NoError(t, RegisterProfile("described", profile))
+
+	db, err := gorm.Open(sqlite.Open("file:db_description?mode=memory&cache=shared"), &gorm.Config{})
+	assert.NoError(t, err)
+	assert.NoError(t, db.Exec(`CREATE TABLE items_base (id INTEGER PRIMARY KEY, sprite_id INTEGER, item_name VARCHAR(60), public_name VARCHAR(60), description TEXT)`).Error)
+	assert.NoError(t, db.Exec(`INSERT INTO items_base (id, sprite_id, item_name, public_name, description) VALUES (1, 100, 'chair', 'Chair', 'Old text')`).Error)
+
+	adapter := NewAdapter()
+	adapter.SetMutationContext(db, nil, storage.Buckets{}, "", "described", "")
+	index, err := adapter.LoadDBIndex(context.Background(), db, "described")
+	assert.NoError(t, err)
+	assert.Equal(t, "Old text", index["100"].(DBItem).Description)
+	assert.NotContains(t, index["100"].(DBItem).NotCompared, "description")
+
+	description := func() string {
+		var value string
+		assert.NoError(t, db.Table("items_base").Where("sprite_id = ?", 100).Pluck("description", &value).Error)
+		return value
+	}
+	gdItem := GDItem{ID: 100, ClassName: "chair", Name: "Chair", Description: "New text", Type: "s"}
+	assert.NoError(t, adapter.SyncDBFromGamedata(context.Background(), "100", gdItem))
+	assert.Equal(t, "Old text", description())
+
+	reconcile.SetSyncedWarnings([]string{"description"})
+	defer reconcile.SetSyncedWarnings(nil)
+	assert.NoError(t, adapter.SyncDBFromGamedata(context.Background(), "100", gdItem))
+	assert.Equal(t, "New text", description())
+}
+
+// TestSyncDBFromGamedata_FieldPolicy tests that syncs keep the fields the DB wins and
+// write only the fields of their action, and that ignored fields are not compared.
+func TestSyncDBFromGamedata_FieldPolicy(t *testing.T) {
+	db := setupTestDB(t, "db_field_policy")
+	assert.NoError(t, db.Exec(`INSERT INTO items_base (id, sprite_id, item_name, public_name, width, length) VALUES (1, 100, 'chair', 'Stoel', 1, 1)`).Error)
+	adapter := NewAdapter()
+	adapter.SetMutationContext(db, nil, storage.Buckets{}, "", "arcturus", "")
+
+	reconcile.SetFieldPolicy(reconcile.FieldPolicy{"name": reconcile.SourceDB, "length": reconcile.FieldIgnore})
+	defer reconcile.SetFieldPolicy(nil)
+
+	gdItem := GDItem{ID: 100, ClassName: "chair", Name: "Chair", XDim: 2, YDim: 3, Type: "s"}
+	dbItem := DBItem{SpriteID: 100, ItemName: "chair", PublicName: "Stoel", Width: 1, Length: 1, Type: "s"}
+	assert.Equal(t, []string{"name: gd='Chair' db='Stoel'", "width: gd=2 db=1"}, adapter.CompareFields(dbItem, gdItem))
+
+	row := func() (name string, width, length int) {
+		var r struct {
+			PublicName string
+			Width      int
+			Length     int
+		}
+		assert.NoError(t, db.Table("items_base").Where("sprite_id = ?", 100).Take(&r).Error)
+		return r.PublicName, r.Width, r.Length
+	}
+
+	assert.NoError(t, adapter.SyncDBBatch(context.Background(), []reconcile.Action{{Type: reconcile.ActionSyncDB, Key: "100", GDItem: gdItem, Fields: []string{"width"}}}))
+	name, width, length := row()
+	assert.Equal(t, "Stoel", name)
+	assert.Equal(t, 2, width)
+	assert.Equal(t, 1, length)
+
+	assert.NoError(t, adapter.SyncDBFromGamedata(context.Background(), "100", gdItem))
+	name, _, length = row()
+	assert.Equal(t, "Stoel", name, "the DB wins names")
+	assert.Equal(t, 1, length, "ignored fields are not synced")
+}
+
 func TestSyncDBBatch_Concurrency(t *testing.T) {
 	db := setupTestDB(t, "db_concurrency")
 	adapter := NewAdapter()
-	adapter.SetMutationContext(db, nil, "", "", "arcturus", "")
+	adapter.SetMutationContext(db, nil, storage.Buckets{}, "", "arcturus", "")
 
 	count := 100
 	actions := make([]reconcile.Action, count)
@@ -120,3 +244,380 @@ func TestSyncDBBatch_Concurrency(t *testing.T) {
 		assert.Equal(t, expected, res.PublicName, "Row %d should be updated", i)
 	}
 }
+
+func TestSyncDBFromGamedata_CometEnumBooleans(t *testing.T) {
+	dsn := "file:db_comet_enum?mode=memory&cache=shared"
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to connect database: %v", err)
+	}
+	err = db.Exec(`CREATE TABLE furniture (
+		id INTEGER PRIMARY KEY,
+		sprite_id INTEGER,
+		item_name VARCHAR(255),
+		public_name VARCHAR(255),
+		width INTEGER,
+		length INTEGER,
+		stack_height VARCHAR(255),
+		can_sit TEXT,
+		can_lay TEXT,
+		is_walkable TEXT,
+		type VARCHAR(1)
+	)`).Error
+	if err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+	db.Exec(`INSERT INTO furniture (id, sprite_id, item_name, public_name, can_sit, can_lay, is_walkable) VALUES (1, 200, 'chair', 'Chair', '0', '1', '0')`)
+
+	adapter := NewAdapter()
+	adapter.SetMutationContext(db, nil, storage.Buckets{}, "", "comet", "")
+
+	gdItem := GDItem{ID: 200, ClassName: "chair", Name: "Chair", XDim: 1, YDim: 1, CanSitOn: true, CanStandOn: true, Type: "s"}
+	err = adapter.SyncDBFromGamedata(context.Background(), "200", gdItem)
+	assert.NoError(t, err)
+
+	var result map[string]interface{}
+	db.Table("furniture").Where("sprite_id = ?", 200).Take(&result)
+
+	assert.Equal(t, "1", result["can_sit"])
+	assert.Equal(t, "0", result["can_lay"])
+	assert.Equal(t, "1", result["is_walkable"])
+}
+
+func TestSyncDBFromGamedata_CometStackHeight(t *testing.T) {
+	dsn := "file:db_comet_stack?mode=memory&cache=shared"
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to connect database: %v", err)
+	}
+	err = db.Exec(`CREATE TABLE furniture (
+		id INTEGER PRIMARY KEY,
+		sprite_id INTEGER,
+		item_name VARCHAR(255),
+		public_name VARCHAR(255),
+		width INTEGER,
+		length INTEGER,
+		stack_height VARCHAR(255),
+		can_sit TEXT,
+		can_lay TEXT,
+		is_walkable TEXT,
+		type VARCHAR(1)
+	)`).Error
+	if err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+	db.Exec(`INSERT INTO furniture (id, sprite_id, item_name, stack_height) VALUES
+		(1, 300, 'a', '1,5'), (2, 301, 'b', '0.25'), (3, 302, 'c', 'n/a'), (4, 303, 'd', NULL)`)
+
+	adapter := NewAdapter()
+	adapter.SetMutationContext(db, nil, storage.Buckets{}, "", "comet", "")
+
+	want := map[int]any{300: "1.5", 301: "0.25", 302: "n/a", 303: nil}
+	for spriteID, expected := range want {
+		key := strconv.Itoa(spriteID)
+		gdItem := GDItem{ID: spriteID, ClassName: "x", Name: "X", XDim: 1, YDim: 1, Type: "s"}
+		assert.NoError(t, adapter.SyncDBFromGamedata(context.Background(), key, gdItem))
+
+		var result map[string]interface{}
+		db.Table("furniture").Where("sprite_id = ?", spriteID).Take(&result)
+		assert.Equal(t, expected, result["stack_height"], "sprite %d", spriteID)
+	}
+
+	// Loading parses the comma form the same as the normalized one
+	index, err := adapter.LoadDBIndex(context.Background(), db, "comet")
+	assert.NoError(t, err)
+	assert.Equal(t, 1.5, index["300"].(DBItem).StackHeight)
+}
+
+func TestGetProfileByName(t *testing.T) {
+	tests := []struct {
+		name      string
+		emulator  string
+		wantTable string
+		wantEnum  bool
+		wantLay   bool
+	}{
+		{"arcturus", "arcturus", "items_base", false, true},
+		{"comet", "comet", "furniture", true, true},
+		{"plus", "plus", "furniture", false, false},
+		{"plusemu", "plusemu", "furniture", false, false},
+		{"unknown defaults to arcturus", "other", "items_base", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile := GetProfileByName(tt.emulator)
+			assert.Equal(t, tt.wantTable, profile.TableName)
+			_, isEnum := profile.Bools.(EnumBools)
+			assert.Equal(t, tt.wantEnum, isEnum)
+			_, hasLay := profile.Columns[ColCanLay]
+			assert.Equal(t, tt.wantLay, hasLay)
+		})
+	}
+}
+
+func TestUpdateCache(t *testing.T) {
+	gd := GDItem{ID: 10, ClassName: "chair", Name: "Chair", XDim: 2, YDim: 3, CanSitOn: true, CanLayOn: true, Type: "s"}
+	newCache := func() *reconcile.ReconcileCache {
+		return &reconcile.ReconcileCache{
+			DBIndex:    map[string]reconcile.DBItem{"10": DBItem{ID: 1, SpriteID: 10, ItemName: "old", PublicName: "Old", Width: 1, Length: 1}, "11": DBItem{SpriteID: 11}},
+			GDIndex:    map[string]reconcile.GDItem{"10": gd},
+			StorageSet: map[string]struct{}{"10": {}},
+		}
+	}
+	actions := []reconcile.Action{
+		{Type: reconcile.ActionSyncDB, Key: "10", GDItem: gd},
+		{Type: reconcile.ActionDeleteDB, Key: "11"},
+	}
+
+	adapter := NewAdapter()
+	adapter.serverProfile = "arcturus"
+	cache := newCache()
+	assert.NoError(t, adapter.UpdateCache(cache, actions))
+	assert.NotContains(t, cache.DBIndex, "11")
+	synced := cache.DBIndex["10"].(DBItem)
+	assert.Equal(t, 1, synced.ID)
+	assert.Equal(t, 10, synced.SpriteID)
+	assert.Empty(t, adapter.CompareFields(synced, gd))
+
+	// Plus has no can_lay column, so the cached value must stay as loaded
+	adapter.serverProfile = "plus"
+	cache = newCache()
+	assert.NoError(t, adapter.UpdateCache(cache, actions))
+	synced = cache.DBIndex["10"].(DBItem)
+	assert.False(t, synced.CanLay)
+	assert.True(t, synced.CanSit)
+
+	// A sync for a key the index never held cannot be patched
+	assert.Error(t, adapter.UpdateCache(newCache(), []reconcile.Action{{Type: reconcile.ActionSyncDB, Key: "99", GDItem: gd}}))
+}
+
+func TestMoveStorage(t *testing.T) {
+	adapter := NewAdapter()
+	adapter.idToClassname["200"] = "chair"
+
+	client := new(mocks.Client)
+	client.On("GetObject", mock.Anything, "assets", "bundled/furniture/old/chair.nitro", mock.Anything).
+		Return(io.NopCloser(strings.NewReader("bundle")), nil)
+	client.On("PutObject", mock.Anything, "assets", "bundled/furniture/chair.nitro", mock.Anything, int64(6), mock.Anything).
+		Return(minio.UploadInfo{}, nil)
+	client.On("RemoveObject", mock.Anything, "assets", "bundled/furniture/old/chair.nitro", mock.Anything).Return(nil)
+	adapter.SetMutationContext(nil, client, storage.SingleBucket("assets"), StoragePrefix, "arcturus", GamedataObject)
+
+	assert.NoError(t, adapter.MoveStorage(context.Background(), "200", "bundled/furniture/old/chair.nitro"))
+	client.AssertExpectations(t)
+	assert.ErrorContains(t, adapter.MoveStorage(context.Background(), "300", "bundled/furniture/old/lamp.nitro"), "classname not found")
+
+	// The misplaced object stays when the copy fails
+	failing := new(mocks.Client)
+	failing.On("GetObject", mock.Anything, "assets", "bundled/furniture/old/chair.nitro", mock.Anything).
+		Return(io.NopCloser(strings.NewReader("bundle")), nil)
+	failing.On("PutObject", mock.Anything, "assets", "bundled/furniture/chair.nitro", mock.Anything, mock.Anything, mock.Anything).
+		Return(minio.UploadInfo{}, errors.New("denied"))
+	adapter.SetMutationContext(nil, failing, storage.SingleBucket("assets"), StoragePrefix, "arcturus", GamedataObject)
+	assert.ErrorContains(t, adapter.MoveStorage(context.Background(), "200", "bundled/furniture/old/chair.nitro"), "denied")
+	failing.AssertNotCalled(t, "RemoveObject", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// nitroBundle builds a Nitro bundle holding one file.
+func nitroBundle(t *testing.T, name string) []byte {
+	var compressed bytes.Buffer
+	zw := zlib.NewWriter(&compressed)
+	_, err := zw.Write([]byte(`{"name":"` + name + `"}`))
+	assert.NoError(t, err)
+	assert.NoError(t, zw.Close())
+
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.BigEndian, uint16(1))
+	binary.Write(&buf, binary.BigEndian, uint16(len(name)))
+	buf.WriteString(name)
+	binary.Write(&buf, binary.BigEndian, uint32(compressed.Len()))
+	buf.Write(compressed.Bytes())
+	return buf.Bytes()
+}
+
+func TestDownloadStorage(t *testing.T) {
+	adapter := NewAdapter()
+	adapter.idToClassname["200"] = "chair"
+	adapter.idToClassname["300"] = "lamp"
+	bundle := nitroBundle(t, "chair")
+
+	client := new(mocks.Client)
+	client.On("GetObject", mock.Anything, "mirror", "bundled/furniture/chair.nitro", mock.Anything).
+		Return(io.NopCloser(bytes.NewReader(bundle)), nil)
+	client.On("GetObject", mock.Anything, "mirror", "bundled/furniture/lamp.nitro", mock.Anything).
+		Return(io.NopCloser(strings.NewReader("<html>not found</html>")), nil)
+	client.On("PutObject", mock.Anything, "assets", "bundled/furniture/chair.nitro", mock.Anything, int64(len(bundle)), mock.Anything).
+		Return(minio.UploadInfo{}, nil)
+	adapter.SetMutationContext(nil, client, storage.SingleBucket("assets"), StoragePrefix, "arcturus", GamedataObject)
+	upstream := reconcile.BucketUpstream{Bucket: "mirror"}
+
+	assert.NoError(t, adapter.DownloadStorage(context.Background(), "200", upstream))
+
+	// Files that are no Nitro bundle are skipped, not written
+	err := adapter.DownloadStorage(context.Background(), "300", upstream)
+	assert.ErrorIs(t, err, reconcile.ErrUpstreamUnavailable)
+	client.AssertExpectations(t)
+	client.AssertNotCalled(t, "PutObject", mock.Anything, "assets", "bundled/furniture/lamp.nitro", mock.Anything, mock.Anything, mock.Anything)
+
+	assert.ErrorContains(t, adapter.DownloadStorage(context.Background(), "400", upstream), "classname not found")
+}
+
+func TestDeleteStorageBatch_ReportsFailures(t *testing.T) {
+	adapter := NewAdapter()
+	adapter.idToClassname["200"] = "chair"
+	adapter.SetRemoveRetry(storage.RemoveRetry{Attempts: 1})
+
+	denied := minio.ErrorResponse{Code: "AccessDenied", StatusCode: http.StatusForbidden}
+	results := make(chan minio.RemoveObjectError, 1)
+	results <- minio.RemoveObjectError{ObjectName: "bundled/furniture/chair.nitro", Err: denied}
+	close(results)
+
+	client := new(mocks.Client)
+	client.On("RemoveObjects", mock.Anything, "assets", mock.Anything, mock.Anything).
+		Return((<-chan minio.RemoveObjectError)(results))
+	adapter.SetMutationContext(nil, client, storage.SingleBucket("assets"), StoragePrefix, "arcturus", GamedataObject)
+
+	err := adapter.DeleteStorageBatch(context.Background(), []string{"200", "orphan"})
+
+	var batchErr *reconcile.BatchDeleteError
+	if assert.True(t, errors.As(err, &batchErr)) {
+		assert.Equal(t, []reconcile.DeleteFailure{{
+			Key:      "200",
+			Action:   reconcile.ActionDeleteStorage,
+			Object:   "bundled/furniture/chair.nitro",
+			Code:     "AccessDenied",
+			Message:  denied.Error(),
+			Attempts: 1,
+		}}, batchErr.Failures)
+	}
+}
+
+func TestSyncGamedataBatch(t *testing.T) {
+	gamedata := `{
+		"roomitemtypes": {"furnitype": [
+			{"id": 200, "classname": "chair", "name": "Chair", "xdim": 1, "ydim": 1, "partcolors": {"color": ["#ffffff"]}},
+			{"id": 300, "classname": "poster", "name": "Poster", "xdim": 1, "ydim": 1}
+		]},
+		"wallitemtypes": {"furnitype": []},
+		"revision": 42
+	}`
+	var written []byte
+	client := new(mocks.Client)
+	client.On("GetObject", mock.Anything, "assets", GamedataObject, mock.Anything).
+		Return(io.NopCloser(strings.NewReader(gamedata)), nil)
+	client.On("PutObject", mock.Anything, "assets", GamedataObject, mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { written, _ = io.ReadAll(args.Get(3).(io.Reader)) }).
+		Return(minio.UploadInfo{}, nil)
+
+	adapter := NewAdapter()
+	adapter.idToClassname["200"] = "chair"
+	adapter.classnameToID["chair"] = "200"
+	adapter.SetMutationContext(nil, client, storage.SingleBucket("assets"), StoragePrefix, "arcturus", GamedataObject)
+
+	chair := GDItem{ID: 200, ClassName: "chair", Name: "Chair", XDim: 1, YDim: 1, Type: "s"}
+	poster := GDItem{ID: 300, ClassName: "poster", Name: "Poster", XDim: 1, YDim: 1, Type: "s"}
+	actions := []reconcile.Action{
+		// A DB name equal to the classname keeps the gamedata name
+		{Type: reconcile.ActionSyncGamedata, Key: "200", GDItem: chair,
+			DBItem: DBItem{SpriteID: 200, ItemName: "chair_red", PublicName: "chair_red", Width: 2, Length: 1, CanSit: true, Type: "s"}},
+		{Type: reconcile.ActionSyncGamedata, Key: "300", GDItem: poster,
+			DBItem: DBItem{SpriteID: 300, ItemName: "poster", PublicName: "Poster", Width: 1, Length: 1, Type: "i"}},
+	}
+	assert.NoError(t, adapter.SyncGamedataBatch(context.Background(), actions))
+
+	var doc map[string]any
+	assert.NoError(t, json.Unmarshal(written, &doc))
+	assert.Equal(t, float64(42), doc["revision"])
+	room := doc["roomitemtypes"].(map[string]any)["furnitype"].([]any)
+	wall := doc["wallitemtypes"].(map[string]any)["furnitype"].([]any)
+	assert.Len(t, room, 1)
+	assert.Len(t, wall, 1, "the wall item moves out of roomitemtypes")
+
+	entry := room[0].(map[string]any)
+	assert.Equal(t, "chair_red", entry["classname"])
+	assert.Equal(t, "Chair", entry["name"])
+	assert.Equal(t, float64(2), entry["xdim"])
+	assert.Equal(t, true, entry["cansiton"])
+	assert.Contains(t, entry, "partcolors")
+	assert.Equal(t, float64(300), wall[0].(map[string]any)["id"])
+
+	// Storage keys follow the new classname
+	classname, _ := adapter.idToClassnameOf("200")
+	assert.Equal(t, "chair_red", classname)
+
+	// The cached gamedata item matches the DB afterwards
+	cache := &reconcile.ReconcileCache{GDIndex: map[string]reconcile.GDItem{"200": chair}, DBIndex: map[string]reconcile.DBItem{}}
+	assert.NoError(t, adapter.UpdateCache(cache, actions[:1]))
+	assert.Empty(t, adapter.CompareFields(actions[0].DBItem, cache.GDIndex["200"]))
+
+	// Entries missing from gamedata cannot be synced
+	client.On("GetObject", mock.Anything, "assets", GamedataObject, mock.Anything).Unset()
+	client.On("GetObject", mock.Anything, "assets", GamedataObject, mock.Anything).
+		Return(io.NopCloser(strings.NewReader(gamedata)), nil)
+	missing := []reconcile.Action{{Type: reconcile.ActionSyncGamedata, Key: "400", GDItem: GDItem{ID: 400}, DBItem: DBItem{SpriteID: 400}}}
+	assert.ErrorContains(t, adapter.SyncGamedataBatch(context.Background(), missing), "no gamedata entries for ids [400]")
+}
+
+// TestInsertGamedataBatch tests that entries are added from DB rows in their section,
+// that unmodeled fields of the file survive, and that existing IDs are refused.
+func TestInsertGamedataBatch(t *testing.T) {
+	gamedata := `{
+		"roomitemtypes": {"furnitype": [
+			{"id": 200, "classname": "chair", "name": "Chair", "xdim": 1, "ydim": 1, "partcolors": {"color": ["#ffffff"]}}
+		]},
+		"wallitemtypes": {"furnitype": []}
+	}`
+	var written []byte
+	client := new(mocks.Client)
+	client.On("GetObject", mock.Anything, "assets", GamedataObject, mock.Anything).
+		Return(io.NopCloser(strings.NewReader(gamedata)), nil)
+	client.On("PutObject", mock.Anything, "assets", GamedataObject, mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { written, _ = io.ReadAll(args.Get(3).(io.Reader)) }).
+		Return(minio.UploadInfo{}, nil)
+
+	adapter := NewAdapter()
+	adapter.SetMutationContext(nil, client, storage.SingleBucket("assets"), StoragePrefix, "arcturus", GamedataObject)
+
+	sofa := DBItem{SpriteID: 500, ItemName: "custom_sofa", PublicName: "Custom Sofa", Width: 2, Length: 1, CanSit: true, Type: "s"}
+	poster := DBItem{SpriteID: 600, ItemName: "custom_poster", Type: "i"}
+	actions := []reconcile.Action{
+		{Type: reconcile.ActionInsertGamedata, Key: "500", DBItem: sofa},
+		{Type: reconcile.ActionInsertGamedata, Key: "600", DBItem: poster},
+	}
+	require.NoError(t, adapter.InsertGamedataBatch(context.Background(), actions))
+
+	var doc map[string]any
+	require.NoError(t, json.Unmarshal(written, &doc))
+	room := doc["roomitemtypes"].(map[string]any)["furnitype"].([]any)
+	wall := doc["wallitemtypes"].(map[string]any)["furnitype"].([]any)
+	require.Len(t, room, 2)
+	require.Len(t, wall, 1)
+	assert.Contains(t, room[0].(map[string]any), "partcolors")
+
+	entry := room[1].(map[string]any)
+	assert.Equal(t, float64(500), entry["id"])
+	assert.Equal(t, "custom_sofa", entry["classname"])
+	assert.Equal(t, "Custom Sofa", entry["name"])
+	assert.Equal(t, float64(2), entry["xdim"])
+	assert.Equal(t, true, entry["cansiton"])
+	assert.Equal(t, float64(-1), entry["offerid"])
+	assert.Equal(t, "custom_poster", wall[0].(map[string]any)["name"], "an empty name falls back to the classname")
+
+	// Storage keys of the inserted items resolve through gamedata
+	classname, _ := adapter.idToClassnameOf("500")
+	assert.Equal(t, "custom_sofa", classname)
+
+	// The cached gamedata item matches the DB afterwards
+	cache := &reconcile.ReconcileCache{GDIndex: map[string]reconcile.GDItem{}}
+	require.NoError(t, adapter.UpdateCache(cache, actions))
+	assert.Empty(t, adapter.CompareFields(sofa, cache.GDIndex["500"]))
+	assert.Equal(t, "i", cache.GDIndex["600"].(GDItem).Type)
+
+	// Items gamedata already holds are refused
+	existing := []reconcile.Action{{Type: reconcile.ActionInsertGamedata, Key: "200", DBItem: DBItem{SpriteID: 200, ItemName: "chair"}}}
+	client.On("GetObject", mock.Anything, "assets", GamedataObject, mock.Anything).Unset()
+	client.On("GetObject", mock.Anything, "assets", GamedataObject, mock.Anything).
+		Return(io.NopCloser(strings.NewReader(gamedata)), nil)
+	assert.ErrorContains(t, adapter.InsertGamedataBatch(context.Background(), existing), "gamedata already has entries for ids [200]")
+}
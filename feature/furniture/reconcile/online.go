@@ -0,0 +1,34 @@
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// ErrNoOnlineSource is returned when the server profile does not locate the online flag.
+var ErrNoOnlineSource = errors.New("server profile has no online users table")
+
+// CountOnlineUsers returns how many users the emulator marks as online. Every
+// supported emulator stores the flag as '1' in a tinyint or enum column.
+func CountOnlineUsers(ctx context.Context, db *gorm.DB, serverProfile string) (int64, error) {
+	if db == nil {
+		return 0, fmt.Errorf("database connection is nil")
+	}
+	profile := GetProfileByName(serverProfile)
+	if profile.UsersTable == "" || profile.OnlineColumn == "" {
+		return 0, ErrNoOnlineSource
+	}
+
+	var count int64
+	err := db.WithContext(ctx).
+		Table(profile.UsersTable).
+		Where(profile.OnlineColumn+" = ?", "1").
+		Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to count online users in %s: %w", profile.UsersTable, err)
+	}
+	return count, nil
+}
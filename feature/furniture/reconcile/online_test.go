@@ -0,0 +1,26 @@
+package reconcile
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCountOnlineUsers(t *testing.T) {
+	db, mock := setupMockDB(t)
+	mock.ExpectQuery("SELECT count\\(\\*\\) FROM `players` WHERE online = \\?").
+		WithArgs("1").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(42))
+
+	count, err := CountOnlineUsers(context.Background(), db, "comet")
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), count)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	require.NoError(t, RegisterProfile("offline", keplerProfile()))
+	_, err = CountOnlineUsers(context.Background(), db, "offline")
+	assert.ErrorIs(t, err, ErrNoOnlineSource)
+}
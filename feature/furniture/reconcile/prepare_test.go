@@ -30,6 +30,11 @@ func TestPrepare_SchemaExpansion(t *testing.T) {
 	adapter := NewAdapter()
 	adapter.serverProfile = "arcturus" // Profile uses 'items_base' table and maps public_name/item_name
 
+	mock.ExpectQuery("SHOW COLUMNS FROM `items_base`").
+		WillReturnRows(sqlmock.NewRows([]string{"Field", "Type", "Null", "Key", "Default", "Extra"}).
+			AddRow("item_name", "varchar(70)", "NO", "", nil, "").
+			AddRow("public_name", "varchar(56)", "NO", "", nil, ""))
+
 	// Expect ALTER TABLE statements
 	// Note: GORM might wrap queries or we might execute raw SQL.
 	// The implementation performs: db.Exec("ALTER TABLE items_base MODIFY COLUMN ...")
@@ -47,3 +52,71 @@ func TestPrepare_SchemaExpansion(t *testing.T) {
 	// Verify all expectations were met
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestPrepare_SkipsWideColumns(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	gormDB, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      db,
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{})
+	assert.NoError(t, err)
+
+	adapter := NewAdapter()
+	adapter.serverProfile = "comet"
+
+	// Comet already uses varchar(255); shrinking it would truncate data
+	mock.ExpectQuery("SHOW COLUMNS FROM `furniture`").
+		WillReturnRows(sqlmock.NewRows([]string{"Field", "Type", "Null", "Key", "Default", "Extra"}).
+			AddRow("item_name", "varchar(255)", "NO", "", nil, "").
+			AddRow("public_name", "VARCHAR(255)", "NO", "", nil, ""))
+
+	err = adapter.Prepare(context.Background(), gormDB)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPrepare_OnlyWidensCharColumns(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	gormDB, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      db,
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{})
+	assert.NoError(t, err)
+
+	adapter := NewAdapter()
+	adapter.serverProfile = "arcturus"
+
+	// Altering TEXT down to VARCHAR(120) would truncate it; a short CHAR is widened
+	mock.ExpectQuery("SHOW COLUMNS FROM `items_base`").
+		WillReturnRows(sqlmock.NewRows([]string{"Field", "Type", "Null", "Key", "Default", "Extra"}).
+			AddRow("item_name", "text", "NO", "", nil, "").
+			AddRow("public_name", "char(40)", "NO", "", nil, ""))
+	mock.ExpectExec("ALTER TABLE items_base MODIFY COLUMN public_name VARCHAR\\(120\\)").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = adapter.Prepare(context.Background(), gormDB)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCharColumnSize(t *testing.T) {
+	for columnType, want := range map[string]int{"varchar(70)": 70, "VARCHAR(255)": 255, "char(32)": 32} {
+		size, ok := charColumnSize(columnType)
+		assert.True(t, ok, columnType)
+		assert.Equal(t, want, size, columnType)
+	}
+	for _, columnType := range []string{"text", "mediumtext", "int(11)", ""} {
+		_, ok := charColumnSize(columnType)
+		assert.False(t, ok, columnType)
+	}
+}
@@ -0,0 +1,50 @@
+package reconcile
+
+import (
+	"path"
+	"strconv"
+
+	"asset-manager/core/reconcile"
+)
+
+// setReserved records the reserved items of the profile being reconciled.
+func (a *FurnitureAdapter) setReserved(reserved []string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.reserved = reserved
+}
+
+// ExpectedAbsent reports storage for items the emulator reserves without a bundle
+// (reconcile.AbsenceExpecter), matched by sprite ID or classname against the profile's
+// Reserved list.
+func (a *FurnitureAdapter) ExpectedAbsent(key string, dbItem reconcile.DBItem, gdItem reconcile.GDItem) []string {
+	a.mu.RLock()
+	reserved := a.reserved
+	a.mu.RUnlock()
+	if len(reserved) == 0 {
+		return nil
+	}
+
+	var classnames []string
+	if db, ok := dbItem.(DBItem); ok {
+		classnames = append(classnames, db.ItemName)
+	}
+	if gd, ok := gdItem.(GDItem); ok {
+		classnames = append(classnames, gd.ClassName)
+	}
+
+	for _, entry := range reserved {
+		if _, err := strconv.Atoi(entry); err == nil {
+			if entry == key {
+				return []string{reconcile.SourceStorage}
+			}
+			continue
+		}
+		for _, classname := range classnames {
+			if matched, _ := path.Match(entry, classname); matched {
+				return []string{reconcile.SourceStorage}
+			}
+		}
+	}
+	return nil
+}
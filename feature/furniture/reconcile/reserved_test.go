@@ -0,0 +1,23 @@
+package reconcile
+
+import (
+	"testing"
+
+	"asset-manager/core/reconcile"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestFurnitureAdapter_ExpectedAbsent tests that reserved items match by sprite ID or
+// classname pattern, from either source.
+func TestFurnitureAdapter_ExpectedAbsent(t *testing.T) {
+	adapter := NewAdapter()
+	assert.Nil(t, adapter.ExpectedAbsent("1", DBItem{ItemName: "ads_background"}, nil), "no profile loaded yet")
+
+	adapter.setReserved(append(ArcturusProfile().Reserved, "4242"))
+	storage := []string{reconcile.SourceStorage}
+	assert.Equal(t, storage, adapter.ExpectedAbsent("1", DBItem{ItemName: "ads_background"}, nil))
+	assert.Equal(t, storage, adapter.ExpectedAbsent("2", nil, GDItem{ClassName: "present_wrap_red"}))
+	assert.Equal(t, storage, adapter.ExpectedAbsent("4242", DBItem{ItemName: "custom"}, nil))
+	assert.Nil(t, adapter.ExpectedAbsent("3", DBItem{ItemName: "chair"}, GDItem{ClassName: "chair"}))
+}
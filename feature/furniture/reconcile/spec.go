@@ -0,0 +1,141 @@
+package reconcile
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"asset-manager/core/reconcile"
+)
+
+const (
+	// AdapterName is the name of the furniture adapter in results, history and ignores.
+	AdapterName = "furniture"
+
+	// StoragePrefix is the storage folder holding bundled furniture assets.
+	StoragePrefix = "bundled/furniture"
+
+	// StorageExtension is the file extension of bundled furniture assets.
+	StorageExtension = ".nitro"
+
+	// GamedataObject is the storage key of the furniture gamedata file.
+	GamedataObject = "gamedata/FurnitureData.json"
+
+	// DefaultCacheTTL is how long the registered spec keeps its indices warm.
+	DefaultCacheTTL = 5 * time.Minute
+)
+
+// GamedataPaths lists the JSON paths holding furniture entries in FurnitureData.json.
+var GamedataPaths = []string{"roomitemtypes.furnitype", "wallitemtypes.furnitype"}
+
+// EachGamedataItem calls fn for every item in the GamedataPaths sections of a decoded
+// FurnitureData.json, with the section ("roomitemtypes" or "wallitemtypes") holding it.
+func EachGamedataItem(doc map[string]any, fn func(section string, item map[string]any)) {
+	for _, path := range GamedataPaths {
+		section, field, _ := strings.Cut(path, ".")
+		parent, _ := doc[section].(map[string]any)
+		items, _ := parent[field].([]any)
+		for _, raw := range items {
+			if item, ok := raw.(map[string]any); ok {
+				fn(section, item)
+			}
+		}
+	}
+}
+
+// BaseClassname strips the color variant suffix ("*2") from a classname.
+func BaseClassname(name string) string {
+	base, _, _ := strings.Cut(name, "*")
+	return base
+}
+
+// AssetKey returns the storage key of the bundled asset of classname under
+// StoragePrefix.
+func AssetKey(classname string) string {
+	return StoragePrefix + "/" + classname + StorageExtension
+}
+
+var (
+	// gamedataURLMu guards gamedataURL.
+	gamedataURLMu sync.RWMutex
+	// gamedataURL is the http(s) URL furniture gamedata is read from, if any.
+	gamedataURL string
+)
+
+// SetGamedataURL makes specs built afterwards read furniture gamedata from an http(s)
+// URL, e.g. a CDN, instead of GamedataObject. Empty restores the bucket object.
+func SetGamedataURL(url string) {
+	gamedataURLMu.Lock()
+	defer gamedataURLMu.Unlock()
+	gamedataURL = url
+}
+
+// GamedataSource returns where specs read furniture gamedata from: the URL set by
+// SetGamedataURL, or GamedataKey.
+func GamedataSource() string {
+	gamedataURLMu.RLock()
+	defer gamedataURLMu.RUnlock()
+	if gamedataURL != "" {
+		return gamedataURL
+	}
+	return GamedataKey()
+}
+
+// Settings returns the furniture entry of the reconcile.adapters section of config.yaml
+// (see reconcile.SetAdapterSettings).
+func Settings() reconcile.AdapterSettings {
+	return reconcile.SettingsFor(AdapterName)
+}
+
+// AssetPrefix returns the storage folder furniture is reconciled in: the configured
+// storage_prefix, or StoragePrefix.
+func AssetPrefix() string {
+	if prefix := Settings().StoragePrefix; prefix != "" {
+		return prefix
+	}
+	return StoragePrefix
+}
+
+// AssetExtension returns the extension of reconciled furniture files: the configured
+// storage_extension, or StorageExtension.
+func AssetExtension() string {
+	if extension := Settings().StorageExtension; extension != "" {
+		return extension
+	}
+	return StorageExtension
+}
+
+// GamedataKey returns the storage key of the furniture gamedata file reconcile reads
+// and writes: the configured gamedata_object, or GamedataObject.
+func GamedataKey() string {
+	if key := Settings().GamedataObject; key != "" {
+		return key
+	}
+	return GamedataObject
+}
+
+// NewSpec builds the reconcile spec used by every furniture integrity surface.
+// An empty gamedataBucket reads gamedata from the bucket passed to each operation.
+// A cacheTTL of zero disables caching, which is what full scans and mutations want;
+// other TTLs give way to the configured cache_ttl.
+func NewSpec(adapter *FurnitureAdapter, emulator, gamedataBucket string, cacheTTL time.Duration) *reconcile.Spec {
+	if cacheTTL > 0 {
+		cacheTTL = Settings().CacheTTLOr(cacheTTL)
+	}
+	return &reconcile.Spec{
+		Adapter:            adapter,
+		CacheTTL:           cacheTTL,
+		StoragePrefix:      AssetPrefix(),
+		StorageExtension:   AssetExtension(),
+		GamedataPaths:      GamedataPaths,
+		GamedataObjectName: GamedataSource(),
+		GamedataBucket:     gamedataBucket,
+		ServerProfile:      emulator,
+	}
+}
+
+// Register makes the furniture spec available to cross-adapter operations such as
+// the combined health score. A cacheTTL of zero makes every such call a fresh scan.
+func Register(emulator, gamedataBucket string, cacheTTL time.Duration) {
+	reconcile.RegisterSpec(NewSpec(NewAdapter(), emulator, gamedataBucket, cacheTTL))
+}
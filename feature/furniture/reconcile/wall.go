@@ -0,0 +1,72 @@
+package reconcile
+
+import (
+	"fmt"
+	"slices"
+
+	"asset-manager/core/reconcile"
+)
+
+// WallPlacementField labels the wall placement check in mismatches. It is rated at
+// warning severity and normalized by syncs once listed in reconcile.sync_warnings.
+const WallPlacementField = "wall_placement"
+
+// wallPlacementIssues returns the values of a DB wall item ("i") that only floor items
+// hold: a footprint, or sit, walk or lay flags. Fields whose column is missing are
+// skipped.
+func wallPlacementIssues(db DBItem) []string {
+	if db.Type != "i" {
+		return nil
+	}
+	held := func(field string) bool { return !slices.Contains(db.NotCompared, field) }
+
+	var issues []string
+	if held("width") && db.Width != 0 {
+		issues = append(issues, fmt.Sprintf("width=%d", db.Width))
+	}
+	if held("length") && db.Length != 0 {
+		issues = append(issues, fmt.Sprintf("length=%d", db.Length))
+	}
+	if held("can_sit") && db.CanSit {
+		issues = append(issues, "can_sit=true")
+	}
+	if held("can_walk") && db.CanWalk {
+		issues = append(issues, "can_walk=true")
+	}
+	if held("can_lay") && db.CanLay {
+		issues = append(issues, "can_lay=true")
+	}
+	return issues
+}
+
+// normalizeWallPlacement adds the updates that clear the footprint and floor flags of a
+// wall item to updates, for the columns profile maps.
+func normalizeWallPlacement(profile ServerProfile, updates map[string]any) {
+	if col, ok := profile.Columns[ColWidth]; ok {
+		updates[col] = 0
+	}
+	if col, ok := profile.Columns[ColLength]; ok {
+		updates[col] = 0
+	}
+	for _, c := range []string{ColCanSit, ColCanWalk, ColCanLay} {
+		if col, ok := profile.Columns[c]; ok {
+			updates[col] = profile.Bools.Encode(false)
+		}
+	}
+}
+
+// normalizedWallItem returns db as normalizeWallPlacement leaves its row.
+func normalizedWallItem(db DBItem) DBItem {
+	db.Width, db.Length = 0, 0
+	db.CanSit, db.CanWalk, db.CanLay = false, false, false
+	return db
+}
+
+// normalizesWallPlacement reports whether a sync of fields (all when empty) from gd
+// normalizes the wall placement of the row: the row must end up a wall item and the
+// check must be listed in reconcile.sync_warnings.
+func normalizesWallPlacement(gd GDItem, fields []string) bool {
+	return gd.Type == "i" &&
+		reconcile.SyncsWarning(WallPlacementField) &&
+		reconcile.SyncsField(WallPlacementField, reconcile.SourceGamedata, fields)
+}
@@ -0,0 +1,67 @@
+package reconcile
+
+import (
+	"context"
+	"testing"
+
+	"asset-manager/core/reconcile"
+	"asset-manager/core/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCompareFields_WallPlacement tests that wall items with a footprint or floor flags
+// are reported at warning severity, and that floor items and missing columns are not.
+func TestCompareFields_WallPlacement(t *testing.T) {
+	adapter := NewAdapter()
+	gd := GDItem{ID: 200, ClassName: "poster", Name: "Poster", Type: "i"}
+
+	wall := DBItem{SpriteID: 200, ItemName: "poster", PublicName: "Poster", Width: 1, Length: 2, CanWalk: true, Type: "i"}
+	mismatches := adapter.CompareFields(wall, gd)
+	assert.Contains(t, mismatches, "wall_placement: db='i' (wall) with width=1 length=2 can_walk=true")
+	assert.Equal(t, reconcile.SeverityWarning, adapter.FieldSeverity(WallPlacementField))
+
+	clean := DBItem{SpriteID: 200, ItemName: "poster", PublicName: "Poster", Type: "i"}
+	assert.Empty(t, adapter.CompareFields(clean, gd))
+
+	floor := DBItem{SpriteID: 100, ItemName: "chair", PublicName: "Chair", Width: 1, Length: 1, CanSit: true, Type: "s"}
+	assert.Empty(t, wallPlacementIssues(floor))
+
+	unmapped := DBItem{Width: 0, Length: 0, CanSit: true, Type: "i", NotCompared: []string{"can_sit"}}
+	assert.Empty(t, wallPlacementIssues(unmapped), "fields without a column are not checked")
+}
+
+// TestSyncDBFromGamedata_WallPlacement tests that syncs clear the footprint and floor
+// flags of wall items only once the normalization is enabled.
+func TestSyncDBFromGamedata_WallPlacement(t *testing.T) {
+	db := setupTestDB(t, "db_wall_placement")
+	require.NoError(t, db.Exec(`INSERT INTO items_base (id, sprite_id, item_name, public_name, width, length, allow_sit, allow_walk, allow_lay, type) VALUES (1, 200, 'poster', 'Poster', 1, 1, 0, 1, 0, 'i')`).Error)
+	adapter := NewAdapter()
+	adapter.SetMutationContext(db, nil, storage.Buckets{}, "", "arcturus", "")
+
+	row := func() (width, length, walk int) {
+		var r struct {
+			Width     int
+			Length    int
+			AllowWalk int
+		}
+		require.NoError(t, db.Table("items_base").Where("sprite_id = ?", 200).Take(&r).Error)
+		return r.Width, r.Length, r.AllowWalk
+	}
+	gdItem := GDItem{ID: 200, ClassName: "poster", Name: "Poster", XDim: 1, YDim: 1, CanStandOn: true, Type: "i"}
+	action := reconcile.Action{Type: reconcile.ActionSyncDB, Key: "200", GDItem: gdItem, Fields: []string{WallPlacementField}}
+
+	require.NoError(t, adapter.SyncDBBatch(context.Background(), []reconcile.Action{action}))
+	width, length, walk := row()
+	assert.Equal(t, []int{1, 1, 1}, []int{width, length, walk}, "reported only by default")
+
+	reconcile.SetSyncedWarnings([]string{WallPlacementField})
+	defer reconcile.SetSyncedWarnings(nil)
+	require.NoError(t, adapter.SyncDBBatch(context.Background(), []reconcile.Action{action}))
+	width, length, walk = row()
+	assert.Equal(t, []int{0, 0, 0}, []int{width, length, walk})
+
+	dbItem := DBItem{SpriteID: 200, ItemName: "poster", PublicName: "Poster", Width: 1, Length: 1, CanWalk: true, Type: "i"}
+	assert.Empty(t, wallPlacementIssues(adapter.syncedDBItem(dbItem, gdItem, action.Fields)), "cached rows are normalized the same way")
+}
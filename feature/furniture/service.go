@@ -2,10 +2,14 @@ package furniture
 
 import (
 	"context"
+	"time"
 
+	"asset-manager/core/jobs"
+	"asset-manager/core/reconcile"
 	"asset-manager/core/storage"
 	"asset-manager/feature/furniture/integrity"
 	"asset-manager/feature/furniture/models"
+	furnitureReconcile "asset-manager/feature/furniture/reconcile"
 
 	"go.uber.org/zap"
 	"gorm.io/gorm"
@@ -14,17 +18,17 @@ import (
 // Service handles furniture operations.
 type Service struct {
 	client   storage.Client
-	bucket   string
+	buckets  storage.Buckets
 	logger   *zap.Logger
 	db       *gorm.DB
 	emulator string
 }
 
 // NewService creates a new furniture service.
-func NewService(client storage.Client, bucket string, logger *zap.Logger, db *gorm.DB, emulator string) *Service {
+func NewService(client storage.Client, buckets storage.Buckets, logger *zap.Logger, db *gorm.DB, emulator string) *Service {
 	return &Service{
 		client:   client,
-		bucket:   bucket,
+		buckets:  buckets,
 		logger:   logger,
 		db:       db,
 		emulator: emulator,
@@ -33,5 +37,100 @@ func NewService(client storage.Client, bucket string, logger *zap.Logger, db *go
 
 // GetFurnitureDetail returns detailed integrity info for a single furniture item.
 func (s *Service) GetFurnitureDetail(ctx context.Context, identifier string) (*models.FurnitureDetailReport, error) {
-	return integrity.CheckFurnitureItem(ctx, s.client, s.bucket, s.db, s.emulator, identifier)
+	return integrity.CheckFurnitureItem(ctx, s.client, s.buckets, s.db, s.emulator, identifier)
+}
+
+// ReconcileWithProgress runs a full furniture reconciliation, reporting each stage of
+// it to progress.
+func (s *Service) ReconcileWithProgress(ctx context.Context, progress reconcile.ProgressFunc) (*models.Report, error) {
+	return integrity.CheckIntegrity(reconcile.WithProgress(ctx, progress), s.client, s.buckets, s.db, s.emulator)
+}
+
+// ReconcileJobKind is the kind of furniture reconciliation jobs.
+const ReconcileJobKind = "reconcile/furniture"
+
+// StartReconcile submits a full furniture reconciliation to manager and returns the
+// job at once. The job reports the share of results built as its progress, and its
+// result is the furniture report.
+func (s *Service) StartReconcile(manager *jobs.Manager) jobs.Job {
+	return manager.Submit(ReconcileJobKind, func(ctx context.Context, progress jobs.ProgressFunc) (any, error) {
+		ctx, runID := reconcile.StartRun(ctx)
+		l := s.logger.With(zap.String("run_id", runID))
+		start := time.Now()
+		report, err := s.ReconcileWithProgress(ctx, func(e reconcile.ProgressEvent) {
+			percent := 0.0
+			switch {
+			case e.Stage == reconcile.StageSummary:
+				percent = 100
+			case e.Total > 0:
+				percent = float64(e.Processed) * 100 / float64(e.Total)
+			}
+			progress(string(e.Stage), percent)
+		})
+		if err != nil {
+			l.Error("Furniture reconciliation job failed", zap.Error(err))
+			return nil, err
+		}
+		l.Info("Furniture reconciliation job finished", zap.Duration("duration", time.Since(start)))
+		return report, nil
+	})
+}
+
+// RenameClassname renames a classname across the database, gamedata, catalog and storage.
+// With dryRun only the plan is returned.
+func (s *Service) RenameClassname(ctx context.Context, oldName, newName string, dryRun bool) (*models.RenamePlan, error) {
+	return integrity.RenameClassname(ctx, s.client, s.buckets, s.db, s.emulator, oldName, newName, dryRun)
+}
+
+// RenamePrefix renames every classname starting with oldPrefix to start with newPrefix.
+// With dryRun only the plan is returned.
+func (s *Service) RenamePrefix(ctx context.Context, oldPrefix, newPrefix string, dryRun bool) (*models.RenamePlan, error) {
+	return integrity.RenamePrefix(ctx, s.client, s.buckets, s.db, s.emulator, oldPrefix, newPrefix, dryRun)
+}
+
+// RepairNames finds furniture whose public_name is its gamedata name garbled by HTML
+// entities or double UTF-8 encoding and, unless dryRun, restores the gamedata names.
+func (s *Service) RepairNames(ctx context.Context, dryRun bool) (*models.NameEncodingReport, error) {
+	return integrity.RepairNameEncoding(ctx, s.client, s.buckets, s.db, s.emulator, dryRun)
+}
+
+// IgnoreItem adds a furniture item to the persistent ignore list, replacing any
+// earlier ignore of the same key. It returns reconcile.ErrNoIgnoreStore when the
+// state store is disabled.
+func (s *Service) IgnoreItem(ctx context.Context, req models.IgnoreRequest) (*reconcile.IgnoreEntry, error) {
+	entry := reconcile.IgnoreEntry{
+		Adapter:   furnitureReconcile.AdapterName,
+		Key:       req.Key,
+		Reason:    req.Reason,
+		CreatedAt: time.Now().UTC(),
+		ExpiresAt: req.ExpiresAt,
+	}
+	if err := reconcile.AddIgnore(ctx, entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// TriageItem records the staff workflow state of a furniture item's issues; a request
+// without any field set clears it. It returns reconcile.ErrNoTriageStore when the
+// state store is disabled.
+func (s *Service) TriageItem(ctx context.Context, req models.TriageRequest) (*reconcile.Triage, error) {
+	triage := reconcile.Triage{
+		Acknowledged: req.Acknowledged,
+		AssignedTo:   req.AssignedTo,
+		Note:         req.Note,
+		UpdatedAt:    time.Now().UTC(),
+	}
+	if err := reconcile.SetTriage(ctx, furnitureReconcile.AdapterName, req.Key, triage); err != nil {
+		return nil, err
+	}
+	return &triage, nil
+}
+
+// SearchFurniture returns at most limit furniture items whose ID, classname or name
+// matches query, best first. It runs over the cached reconcile indices, so lookups
+// between two scans do not touch the database or storage.
+func (s *Service) SearchFurniture(ctx context.Context, query string, limit int) ([]reconcile.SearchMatch, error) {
+	spec := furnitureReconcile.NewSpec(furnitureReconcile.NewAdapter(), s.emulator, s.buckets.Gamedata, furnitureReconcile.DefaultCacheTTL)
+	return reconcile.Search(ctx, spec, s.db, s.client, s.buckets.Assets, query, limit)
 }
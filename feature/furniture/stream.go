@@ -0,0 +1,27 @@
+package furniture
+
+import (
+	"bufio"
+	"fmt"
+
+	"asset-manager/core/json"
+)
+
+// sseEvent is one Server-Sent Event: a name and a JSON payload.
+type sseEvent struct {
+	name string
+	data any
+}
+
+// writeEvent writes event to w and flushes it, so the client sees it right away. An
+// error means the client went away.
+func writeEvent(w *bufio.Writer, event sseEvent) error {
+	payload, err := json.Marshal(event.data)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s event: %w", event.name, err)
+	}
+	if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.name, payload); err != nil {
+		return err
+	}
+	return w.Flush()
+}
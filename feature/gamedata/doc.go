@@ -0,0 +1,21 @@
+// Package gamedata serves the history of the furniture gamedata kept by the storage
+// client (storage.WithHistory).
+//
+// Every write or removal of FurnitureData.json through the manager (reconcile syncs
+// and purges, renames, pack installs and imports) first copies the current file to
+// gamedata/history/FurnitureData.<timestamp>.json. The timestamp, in
+// storage.HistoryTimeFormat, is the version of the snapshot. STORAGE_HISTORY_KEEP and
+// STORAGE_HISTORY_MAX_AGE bound how many snapshots are kept.
+//
+// # Restore
+//
+// Restore writes a snapshot back as FurnitureData.json under the run lock, so no
+// reconcile mutates the hotel meanwhile, and drops the cached indices of furniture
+// specs. The replaced file is snapshotted like any other change, so a restore can be
+// undone by restoring that snapshot. Database rows and storage assets are left alone.
+//
+// # HTTP Endpoints
+//
+//   - GET /gamedata/versions : List the snapshots, newest first.
+//   - POST /gamedata/restore/:version : Restore a snapshot.
+package gamedata
@@ -0,0 +1,112 @@
+package gamedata
+
+import (
+	"errors"
+
+	"asset-manager/core/confirm"
+	"asset-manager/core/logger"
+	"asset-manager/core/reconcile"
+	"asset-manager/core/storage"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// Handler handles HTTP requests for gamedata snapshots.
+type Handler struct {
+	service *Service
+}
+
+// NewHandler creates a new HTTP handler.
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// RegisterRoutes registers the gamedata routes.
+func (h *Handler) RegisterRoutes(app fiber.Router) {
+	app.Get("/gamedata/versions", h.HandleVersions)
+	app.Post("/gamedata/restore/:version", h.HandleRestore)
+}
+
+// HandleVersions lists the snapshots of the furniture gamedata.
+// @Summary List Gamedata Versions
+// @Description Lists the snapshots of FurnitureData.json taken before each change the manager made to it, newest first. Each version is the UTC timestamp of its snapshot (e.g. 20260301T120000.000Z), stored as gamedata/history/FurnitureData.<version>.json.
+// @Tags gamedata
+// @Produce json
+// @Success 200 {object} gamedata.VersionList "Gamedata Versions"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /gamedata/versions [get]
+func (h *Handler) HandleVersions(c *fiber.Ctx) error {
+	l := logger.WithRayID(h.service.logger, c)
+
+	list, err := h.service.Versions(c.Context())
+	if err != nil {
+		l.Error("Failed to list gamedata versions", zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	return c.JSON(list)
+}
+
+// HandleRestore restores a snapshot of the furniture gamedata.
+// @Summary Restore Gamedata Version
+// @Description Writes a snapshot back as FurnitureData.json under the run lock. The replaced file is snapshotted first, so the restore can be undone by restoring that version. Database rows and storage assets are left alone; run a reconcile afterwards. With SERVER_MUTATIONS_REQUIRE_CONFIRMATION set, the first request answers with the snapshot it would restore and a confirmation token; repeat it with confirm=<token> to restore.
+// @Tags gamedata
+// @Produce json
+// @Param version path string true "Snapshot version, as listed by GET /gamedata/versions"
+// @Param confirm query string false "Confirmation token of a reviewed restore (when SERVER_MUTATIONS_REQUIRE_CONFIRMATION is set)"
+// @Success 200 {object} storage.HistoryVersion "Restored Version"
+// @Failure 400 {object} map[string]string "Invalid version"
+// @Failure 403 {object} map[string]string "Unknown or already used confirmation token"
+// @Failure 404 {object} map[string]string "Unknown version"
+// @Failure 409 {object} map[string]string "Another reconcile holds the run lock, or the snapshot changed since the token was issued"
+// @Failure 410 {object} map[string]string "Confirmation token expired"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /gamedata/restore/{version} [post]
+func (h *Handler) HandleRestore(c *fiber.Ctx) error {
+	l := logger.WithRayID(h.service.logger, c)
+	version := c.Params("version")
+
+	// Review the snapshot before it replaces the gamedata when confirmation is required
+	if store := confirm.Required(); store != nil {
+		target, err := h.service.Version(c.Context(), version)
+		if err != nil {
+			return restoreError(c, l, version, err)
+		}
+		if confirmed, err := confirm.Await(c, l, store, "gamedata/restore", target, fiber.Map{"would_restore": target}); !confirmed {
+			return err
+		}
+	}
+
+	restored, err := h.service.Restore(c.Context(), version)
+	if err != nil {
+		return restoreError(c, l, version, err)
+	}
+
+	l.Info("Gamedata version restored", zap.String("version", restored.Version), zap.String("key", restored.Key))
+	return c.JSON(restored)
+}
+
+// restoreError answers a failed restore of version with its HTTP status.
+func restoreError(c *fiber.Ctx, l *zap.Logger, version string, err error) error {
+	var locked *reconcile.LockedError
+	switch {
+	case errors.Is(err, storage.ErrInvalidHistoryVersion):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, storage.ErrNoHistoryVersion):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.As(err, &locked):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	l.Error("Gamedata restore failed", zap.String("version", version), zap.Error(err))
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": err.Error(),
+	})
+}
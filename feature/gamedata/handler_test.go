@@ -0,0 +1,158 @@
+package gamedata
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"asset-manager/core/confirm"
+	"asset-manager/core/json"
+	"asset-manager/core/storage"
+	"asset-manager/core/storage/mocks"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// memoryClient keeps the objects of every bucket in memory, by bucket and key.
+type memoryClient struct {
+	mocks.Client
+	objects map[string][]byte
+}
+
+func (m *memoryClient) GetObject(ctx context.Context, bucketName, objectName string, opts minio.GetObjectOptions) (io.ReadCloser, error) {
+	data, ok := m.objects[bucketName+"/"+objectName]
+	if !ok {
+		return nil, minio.ErrorResponse{Code: "NoSuchKey"}
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (m *memoryClient) PutObject(ctx context.Context, bucketName, objectName string, reader io.Reader, objectSize int64, opts minio.PutObjectOptions) (minio.UploadInfo, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return minio.UploadInfo{}, err
+	}
+	m.objects[bucketName+"/"+objectName] = data
+	return minio.UploadInfo{}, nil
+}
+
+func (m *memoryClient) RemoveObject(ctx context.Context, bucketName, objectName string, opts minio.RemoveObjectOptions) error {
+	delete(m.objects, bucketName+"/"+objectName)
+	return nil
+}
+
+func (m *memoryClient) ListObjects(ctx context.Context, bucketName string, opts minio.ListObjectsOptions) <-chan minio.ObjectInfo {
+	var keys []string
+	for id := range m.objects {
+		if key, ok := strings.CutPrefix(id, bucketName+"/"); ok && strings.HasPrefix(key, opts.Prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	ch := make(chan minio.ObjectInfo, len(keys))
+	for _, key := range keys {
+		ch <- minio.ObjectInfo{Key: key, Size: int64(len(m.objects[bucketName+"/"+key]))}
+	}
+	close(ch)
+	return ch
+}
+
+// testHistory snapshots the furniture gamedata without pruning.
+var testHistory = storage.History{Enabled: true, Object: "gamedata/FurnitureData.json", Prefix: "gamedata/history/"}
+
+// newTestApp serves the gamedata routes over a memory client holding the current
+// gamedata and one snapshot of it.
+func newTestApp() (*fiber.App, *memoryClient) {
+	inner := &memoryClient{objects: map[string][]byte{
+		"gamedata/gamedata/FurnitureData.json":                                  []byte(`{"v":2}`),
+		"gamedata/gamedata/history/FurnitureData.20260301T120000.000Z.json":     []byte(`{"v":1}`),
+		"gamedata/gamedata/history/FurnitureData.20260301T120000.000Z.json.tmp": []byte(`ignored`),
+	}}
+	buckets := storage.Buckets{Assets: "assets", Gamedata: "gamedata"}
+	app := fiber.New()
+	NewHandler(NewService(storage.WithHistory(inner, testHistory), buckets, testHistory, zap.NewNop())).RegisterRoutes(app)
+	return app, inner
+}
+
+func TestHandler_HandleVersions(t *testing.T) {
+	app, _ := newTestApp()
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/gamedata/versions", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var list VersionList
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&list))
+	assert.Equal(t, "gamedata/FurnitureData.json", list.Object)
+	require.Len(t, list.Versions, 1)
+	assert.Equal(t, "20260301T120000.000Z", list.Versions[0].Version)
+	assert.Equal(t, int64(7), list.Versions[0].Size)
+}
+
+func TestHandler_HandleRestore(t *testing.T) {
+	app, inner := newTestApp()
+
+	resp, err := app.Test(httptest.NewRequest("POST", "/gamedata/restore/20260301T120000.000Z", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Equal(t, `{"v":1}`, string(inner.objects["gamedata/gamedata/FurnitureData.json"]))
+
+	// The replaced file was snapshotted and the run lock released
+	versions, err := storage.ListHistory(context.Background(), inner, "gamedata", testHistory)
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+	assert.Equal(t, `{"v":2}`, string(inner.objects["gamedata/"+versions[0].Key]))
+	assert.Len(t, inner.objects, 4)
+
+	resp, err = app.Test(httptest.NewRequest("POST", "/gamedata/restore/20200101T000000.000Z", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 404, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest("POST", "/gamedata/restore/latest", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 400, resp.StatusCode)
+}
+
+// TestHandler_HandleRestore_Confirmation tests that a restore waits for its token when
+// mutations require confirmation.
+func TestHandler_HandleRestore_Confirmation(t *testing.T) {
+	confirm.SetStore(confirm.NewStore(time.Minute))
+	defer confirm.SetStore(nil)
+	app, inner := newTestApp()
+
+	resp, err := app.Test(httptest.NewRequest("POST", "/gamedata/restore/20260301T120000.000Z", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	var body struct {
+		Status       string                 `json:"status"`
+		Token        string                 `json:"confirmation_token"`
+		WouldRestore storage.HistoryVersion `json:"would_restore"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "confirmation_required", body.Status)
+	assert.Equal(t, "20260301T120000.000Z", body.WouldRestore.Version)
+	assert.Equal(t, `{"v":2}`, string(inner.objects["gamedata/gamedata/FurnitureData.json"]), "nothing restored before confirmation")
+
+	resp, err = app.Test(httptest.NewRequest("POST", "/gamedata/restore/20260301T120000.000Z?confirm=bogus", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 403, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest("POST", "/gamedata/restore/20260301T120000.000Z?confirm="+body.Token, nil))
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Equal(t, `{"v":1}`, string(inner.objects["gamedata/gamedata/FurnitureData.json"]))
+
+	// Unknown versions are refused before a token is issued
+	resp, err = app.Test(httptest.NewRequest("POST", "/gamedata/restore/20200101T000000.000Z", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 404, resp.StatusCode)
+}
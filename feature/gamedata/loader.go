@@ -0,0 +1,37 @@
+package gamedata
+
+import (
+	"asset-manager/core/storage"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// Feature implements the loader.Feature interface.
+type Feature struct {
+	service *Service
+	handler *Handler
+}
+
+// NewFeature creates a new Gamedata feature.
+func NewFeature(client storage.Client, buckets storage.Buckets, history storage.History, logger *zap.Logger) *Feature {
+	svc := NewService(client, buckets, history, logger)
+	h := NewHandler(svc)
+	return &Feature{service: svc, handler: h}
+}
+
+// Name returns the name of the feature.
+func (f *Feature) Name() string {
+	return "gamedata"
+}
+
+// IsEnabled reports whether gamedata snapshots are taken.
+func (f *Feature) IsEnabled() bool {
+	return f.service.history.Enabled
+}
+
+// Load registers the feature's routes.
+func (f *Feature) Load(app fiber.Router) error {
+	f.handler.RegisterRoutes(app)
+	return nil
+}
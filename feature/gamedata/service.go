@@ -0,0 +1,89 @@
+package gamedata
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"asset-manager/core/reconcile"
+	"asset-manager/core/storage"
+	furnitureAdp "asset-manager/feature/furniture/reconcile"
+
+	"go.uber.org/zap"
+)
+
+// VersionList lists the snapshots of the gamedata file.
+type VersionList struct {
+	// Object is the snapshotted gamedata file.
+	Object string `json:"object"`
+	// Versions are its snapshots, newest first.
+	Versions []storage.HistoryVersion `json:"versions"`
+}
+
+// Service lists and restores gamedata snapshots.
+type Service struct {
+	client  storage.Client
+	buckets storage.Buckets
+	history storage.History
+	logger  *zap.Logger
+}
+
+// NewService creates a new gamedata service. The client must be the one wrapped by
+// storage.WithHistory, so restores snapshot the file they replace.
+func NewService(client storage.Client, buckets storage.Buckets, history storage.History, logger *zap.Logger) *Service {
+	return &Service{client: client, buckets: buckets, history: history, logger: logger}
+}
+
+// Versions lists the snapshots of the gamedata file, newest first.
+func (s *Service) Versions(ctx context.Context) (*VersionList, error) {
+	versions, err := storage.ListHistory(ctx, s.client, s.buckets.Gamedata, s.history)
+	if err != nil {
+		return nil, err
+	}
+	return &VersionList{Object: s.history.Object, Versions: versions}, nil
+}
+
+// Version returns the snapshot version of the gamedata file. It returns
+// storage.ErrInvalidHistoryVersion for malformed versions and
+// storage.ErrNoHistoryVersion when the history does not hold it.
+func (s *Service) Version(ctx context.Context, version string) (*storage.HistoryVersion, error) {
+	if _, err := time.Parse(storage.HistoryTimeFormat, version); err != nil {
+		return nil, fmt.Errorf("%w %q: want a timestamp like %s", storage.ErrInvalidHistoryVersion, version, storage.HistoryTimeFormat)
+	}
+	versions, err := storage.ListHistory(ctx, s.client, s.buckets.Gamedata, s.history)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range versions {
+		if v.Version == version {
+			return &v, nil
+		}
+	}
+	return nil, fmt.Errorf("%w %s", storage.ErrNoHistoryVersion, version)
+}
+
+// Restore writes the snapshot version back as the gamedata file under the run lock.
+func (s *Service) Restore(ctx context.Context, version string) (restored *storage.HistoryVersion, err error) {
+	lock, err := reconcile.AcquireRunLock(ctx, s.client, s.buckets.Assets, reconcile.DefaultLockTTL)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if releaseErr := lock.Release(); releaseErr != nil && err == nil {
+			err = releaseErr
+		}
+	}()
+
+	restored, err = storage.RestoreHistory(ctx, s.client, s.buckets.Gamedata, s.history, version)
+	if err != nil {
+		return nil, err
+	}
+
+	// Indices built before the restore hold the replaced entries
+	for _, spec := range reconcile.RegisteredSpecs() {
+		if _, ok := spec.Adapter.(*furnitureAdp.FurnitureAdapter); ok {
+			reconcile.InvalidateCache(spec)
+		}
+	}
+	return restored, nil
+}
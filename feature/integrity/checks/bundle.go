@@ -8,7 +8,7 @@ import (
 	"go.uber.org/zap"
 )
 
-// RequiredBundledFolders lists the bundled asset folders that must exist.
+// RequiredBundledFolders lists the bundled asset folders that must exist when no layout is configured.
 var RequiredBundledFolders = []string{
 	"bundled/effect",
 	"bundled/figure",
@@ -17,12 +17,17 @@ var RequiredBundledFolders = []string{
 	"bundled/pet",
 }
 
-// CheckBundled returns a list of missing bundled folders.
-func CheckBundled(ctx context.Context, client storage.Client, bucket string) ([]string, error) {
-	return CheckFolders(ctx, client, bucket, RequiredBundledFolders)
+// CheckBundled returns the bundled folders that are missing.
+func CheckBundled(ctx context.Context, client storage.Client, bucket string, folders []string) ([]string, error) {
+	return CheckFolders(ctx, client, bucket, folders)
 }
 
 // FixBundled creates the missing bundled folders.
-func FixBundled(ctx context.Context, client storage.Client, bucket string, logger *zap.Logger, missing []string) error {
-	return FixFolders(ctx, client, bucket, logger, missing)
+func FixBundled(ctx context.Context, client storage.Client, bucket string, logger *zap.Logger, missing []string, keep bool) error {
+	return FixFolders(ctx, client, bucket, logger, missing, keep)
+}
+
+// PlanBundled returns the placeholders FixBundled would create for the missing folders.
+func PlanBundled(bucket string, missing []string, keep bool) []Placeholder {
+	return PlanFolders(bucket, missing, keep)
 }
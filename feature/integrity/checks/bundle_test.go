@@ -20,7 +20,7 @@ func TestCheckBundled(t *testing.T) {
 		close(ch)
 		mockClient.On("ListObjects", mock.Anything, "assets", mock.Anything).Return((<-chan minio.ObjectInfo)(ch))
 
-		missing, err := CheckBundled(context.Background(), mockClient, "assets")
+		missing, err := CheckBundled(context.Background(), mockClient, "assets", RequiredBundledFolders)
 		assert.NoError(t, err)
 		assert.Len(t, missing, len(RequiredBundledFolders))
 	})
@@ -38,7 +38,7 @@ func TestCheckBundled(t *testing.T) {
 			})).Return((<-chan minio.ObjectInfo)(ch))
 		}
 
-		missing, err := CheckBundled(context.Background(), mockClient, "assets")
+		missing, err := CheckBundled(context.Background(), mockClient, "assets", RequiredBundledFolders)
 		assert.NoError(t, err)
 		assert.Len(t, missing, 0)
 	})
@@ -50,7 +50,15 @@ func TestFixBundled(t *testing.T) {
 
 	mockClient.On("PutObject", mock.Anything, "assets", mock.Anything, mock.Anything, int64(0), mock.Anything).Return(minio.UploadInfo{}, nil)
 
-	err := FixBundled(context.Background(), mockClient, "assets", logger, []string{"bundled/effect"})
+	err := FixBundled(context.Background(), mockClient, "assets", logger, []string{"bundled/effect"}, false)
 	assert.NoError(t, err)
 	mockClient.AssertNumberOfCalls(t, "PutObject", 1)
 }
+
+func TestResolveFolders(t *testing.T) {
+	defaults := []string{"bundled/pet"}
+
+	assert.Equal(t, defaults, ResolveFolders(nil, defaults))
+	assert.Equal(t, defaults, ResolveFolders([]string{" ", ""}, defaults))
+	assert.Equal(t, []string{"bundled/games", "bundled/quests"}, ResolveFolders([]string{"bundled/games/", " bundled/quests"}, defaults))
+}
@@ -0,0 +1,143 @@
+package checks
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path"
+	"strings"
+
+	"asset-manager/core/storage"
+
+	"github.com/minio/minio-go/v7"
+	"gorm.io/gorm"
+)
+
+// CatalogPagesTable is the emulator table holding catalog (shop) pages. Arcturus, Plus
+// and Comet share its name and the icon and image columns read here.
+const CatalogPagesTable = "catalog_pages"
+
+// catalogImageColumns lists the catalog_pages columns naming page images.
+var catalogImageColumns = []string{"page_headline", "page_teaser", "page_special"}
+
+// catalogPageRow is one catalog_pages row as read by the catalog check.
+type catalogPageRow struct {
+	ID           int
+	Caption      sql.NullString
+	IconImage    sql.NullString
+	PageHeadline sql.NullString
+	PageTeaser   sql.NullString
+	PageSpecial  sql.NullString
+}
+
+// BrokenCatalogPage is a catalog page referencing images that do not exist in storage.
+type BrokenCatalogPage struct {
+	// ID is the catalog_pages id.
+	ID int `json:"id"`
+	// Caption is the page title shown in the shop.
+	Caption string `json:"caption"`
+	// Missing lists the storage keys of the missing icon and images.
+	Missing []string `json:"missing"`
+}
+
+// CatalogReport contains the results of the catalog page image check.
+type CatalogReport struct {
+	// Pages is the number of catalog pages inspected.
+	Pages int `json:"pages"`
+	// Referenced is the number of distinct icon and image keys referenced by pages.
+	Referenced int `json:"referenced"`
+	// Prefix is the storage prefix the images were looked up in.
+	Prefix string `json:"prefix"`
+	// Broken lists the pages with at least one missing icon or image.
+	Broken []BrokenCatalogPage `json:"broken"`
+}
+
+// CheckCatalog verifies that the icon and headline, teaser and special images of every
+// catalog page exist under prefix. Icons are looked up as icon_<icon_image>.png and
+// images as <name>.gif unless the name already has an extension, matching the paths
+// the Nitro client requests. External URLs are skipped.
+func CheckCatalog(ctx context.Context, client storage.Client, bucket string, db *gorm.DB, prefix string) (*CatalogReport, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database connection is nil")
+	}
+	prefix = strings.Trim(prefix, "/")
+
+	var rows []catalogPageRow
+	err := db.WithContext(ctx).
+		Table(CatalogPagesTable).
+		Select(append([]string{"id", "caption", "icon_image"}, catalogImageColumns...)).
+		Order("id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", CatalogPagesTable, err)
+	}
+
+	existing, err := listObjectSet(ctx, client, bucket, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &CatalogReport{
+		Pages:  len(rows),
+		Prefix: prefix,
+		Broken: []BrokenCatalogPage{},
+	}
+	referenced := make(map[string]struct{})
+
+	for _, row := range rows {
+		var missing []string
+		for _, key := range catalogPageKeys(row, prefix) {
+			referenced[key] = struct{}{}
+			if _, ok := existing[key]; !ok {
+				missing = append(missing, key)
+			}
+		}
+		if len(missing) > 0 {
+			report.Broken = append(report.Broken, BrokenCatalogPage{
+				ID:      row.ID,
+				Caption: row.Caption.String,
+				Missing: missing,
+			})
+		}
+	}
+	report.Referenced = len(referenced)
+
+	return report, nil
+}
+
+// catalogPageKeys returns the storage keys of the icon and images a page references.
+func catalogPageKeys(row catalogPageRow, prefix string) []string {
+	var keys []string
+	if icon := strings.TrimSpace(row.IconImage.String); icon != "" {
+		keys = append(keys, path.Join(prefix, "icon_"+icon+".png"))
+	}
+	for _, image := range []sql.NullString{row.PageHeadline, row.PageTeaser, row.PageSpecial} {
+		name := strings.TrimSpace(image.String)
+		if name == "" || strings.Contains(name, "://") {
+			continue
+		}
+		name = path.Base(name)
+		if path.Ext(name) == "" {
+			name += ".gif"
+		}
+		keys = append(keys, path.Join(prefix, name))
+	}
+	return keys
+}
+
+// listObjectSet lists every object under prefix into a set of keys.
+func listObjectSet(ctx context.Context, client storage.Client, bucket, prefix string) (map[string]struct{}, error) {
+	opts := minio.ListObjectsOptions{
+		Prefix:    prefix + "/",
+		Recursive: true,
+	}
+
+	set := make(map[string]struct{})
+	for obj := range client.ListObjects(ctx, bucket, opts) {
+		if obj.Err != nil {
+			return nil, fmt.Errorf("failed to list objects: %w", obj.Err)
+		}
+		set[obj.Key] = struct{}{}
+	}
+	return set, nil
+}
@@ -0,0 +1,70 @@
+package checks
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"asset-manager/core/storage/mocks"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckCatalog(t *testing.T) {
+	t.Run("Reports Broken Pages", func(t *testing.T) {
+		db, sqlMock := setupMockDB(t)
+		rows := sqlmock.NewRows([]string{"id", "caption", "icon_image", "page_headline", "page_teaser", "page_special"}).
+			AddRow(1, "Front Page", "1", "catalog_frontpage_headline", "", nil).
+			AddRow(2, "Rares", "5", "rares_headline", "rares_teaser.png", "https://cdn.example.com/special.gif").
+			AddRow(3, "Hidden", nil, nil, nil, nil)
+		sqlMock.ExpectQuery("SELECT id,caption,icon_image,page_headline,page_teaser,page_special FROM `catalog_pages`").
+			WillReturnRows(rows)
+
+		mockClient := new(mocks.Client)
+		ch := make(chan minio.ObjectInfo, 3)
+		ch <- minio.ObjectInfo{Key: "c_images/catalogue/icon_1.png"}
+		ch <- minio.ObjectInfo{Key: "c_images/catalogue/catalog_frontpage_headline.gif"}
+		ch <- minio.ObjectInfo{Key: "c_images/catalogue/rares_headline.gif"}
+		close(ch)
+		mockClient.On("ListObjects", mock.Anything, "assets", mock.MatchedBy(func(opts minio.ListObjectsOptions) bool {
+			return opts.Prefix == "c_images/catalogue/" && opts.Recursive
+		})).Return((<-chan minio.ObjectInfo)(ch))
+
+		report, err := CheckCatalog(context.Background(), mockClient, "assets", db, "/c_images/catalogue/")
+		require.NoError(t, err)
+		assert.Equal(t, 3, report.Pages)
+		assert.Equal(t, 5, report.Referenced)
+		assert.Equal(t, "c_images/catalogue", report.Prefix)
+		require.Len(t, report.Broken, 1)
+		assert.Equal(t, 2, report.Broken[0].ID)
+		assert.Equal(t, "Rares", report.Broken[0].Caption)
+		assert.Equal(t, []string{"c_images/catalogue/icon_5.png", "c_images/catalogue/rares_teaser.png"}, report.Broken[0].Missing)
+		assert.NoError(t, sqlMock.ExpectationsWereMet())
+	})
+
+	t.Run("Query Error", func(t *testing.T) {
+		db, sqlMock := setupMockDB(t)
+		sqlMock.ExpectQuery("SELECT").WillReturnError(assert.AnError)
+
+		_, err := CheckCatalog(context.Background(), new(mocks.Client), "assets", db, "c_images/catalogue")
+		assert.ErrorContains(t, err, "failed to read catalog_pages")
+	})
+
+	t.Run("No Database", func(t *testing.T) {
+		_, err := CheckCatalog(context.Background(), new(mocks.Client), "assets", nil, "c_images/catalogue")
+		assert.Error(t, err)
+	})
+}
+
+func TestCatalogPageKeys(t *testing.T) {
+	row := catalogPageRow{
+		IconImage:    sql.NullString{String: " 12 ", Valid: true},
+		PageHeadline: sql.NullString{String: "catalogue/headline.gif", Valid: true},
+		PageTeaser:   sql.NullString{String: "http://example.com/teaser.gif", Valid: true},
+	}
+	assert.Equal(t, []string{"c_images/catalogue/icon_12.png", "c_images/catalogue/headline.gif"}, catalogPageKeys(row, "c_images/catalogue"))
+}
@@ -0,0 +1,258 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"strings"
+
+	"asset-manager/core/json"
+	"asset-manager/core/storage"
+
+	"github.com/minio/minio-go/v7"
+)
+
+const (
+	// FigureDataObject is the storage key of the clothing gamedata file.
+	FigureDataObject = "gamedata/FigureData.json"
+	// FigureMapObject is the storage key of the file mapping clothing parts to libraries.
+	FigureMapObject = "gamedata/FigureMap.json"
+	// FigureBundlePrefix is where the clothing library bundles live in the assets bucket.
+	FigureBundlePrefix = "bundled/figure/"
+)
+
+// Figure problem kinds, one per report counter.
+const (
+	// FigureBrokenPalette marks a set type whose palette is not defined.
+	FigureBrokenPalette = "broken_palette"
+	// FigureDuplicateSet marks a set ID defined more than once in its set type.
+	FigureDuplicateSet = "duplicate_set"
+	// FigureMissingAsset marks a set with a part no clothing bundle provides.
+	FigureMissingAsset = "missing_asset"
+)
+
+// figureData is the part of FigureData.json the check reads.
+type figureData struct {
+	Palettes []struct {
+		ID int `json:"id"`
+	} `json:"palettes"`
+	SetTypes []struct {
+		Type      string `json:"type"`
+		PaletteID int    `json:"paletteId"`
+		Sets      []struct {
+			ID    int `json:"id"`
+			Parts []struct {
+				ID   int    `json:"id"`
+				Type string `json:"type"`
+			} `json:"parts"`
+		} `json:"sets"`
+	} `json:"setTypes"`
+}
+
+// figureMap is the part of FigureMap.json the check reads.
+type figureMap struct {
+	Libraries []struct {
+		ID    string `json:"id"`
+		Parts []struct {
+			ID   int    `json:"id"`
+			Type string `json:"type"`
+		} `json:"parts"`
+	} `json:"libraries"`
+}
+
+// FigureIssue describes a single problem found in FigureData.json.
+type FigureIssue struct {
+	// Kind is FigureBrokenPalette, FigureDuplicateSet or FigureMissingAsset.
+	Kind string `json:"kind"`
+	// SetType is the set type holding the problem, e.g. "hr".
+	SetType string `json:"settype"`
+	// SetID is the offending set. Zero for problems of the whole set type.
+	SetID int `json:"set_id,omitempty"`
+	// Problem describes what is wrong.
+	Problem string `json:"problem"`
+}
+
+// FigureDataReport contains the results of the structural FigureData.json validation.
+type FigureDataReport struct {
+	// Palettes is the number of palettes defined.
+	Palettes int `json:"palettes"`
+	// SetTypes is the number of set types inspected.
+	SetTypes int `json:"settypes"`
+	// Sets is the number of sets inspected.
+	Sets int `json:"sets"`
+	// BrokenPalettes counts set types referencing an undefined palette.
+	BrokenPalettes int `json:"broken_palettes"`
+	// DuplicateSets counts set IDs defined more than once in their set type.
+	DuplicateSets int `json:"duplicate_sets"`
+	// MissingAssets counts sets with at least one part no clothing bundle provides.
+	MissingAssets int `json:"missing_assets"`
+	// AssetsChecked is false when parts could not be resolved to clothing bundles,
+	// because no figure map was available.
+	AssetsChecked bool `json:"assets_checked"`
+	// Issues lists every problem found.
+	Issues []FigureIssue `json:"issues"`
+}
+
+// FigureAssets resolves clothing parts to the bundles providing them.
+type FigureAssets struct {
+	// Libraries maps every part, as "type:id", to the library holding it.
+	Libraries map[string]string
+	// Bundles holds the libraries with a bundle in storage.
+	Bundles map[string]bool
+}
+
+// LoadFigureData downloads FigureData.json from storage.
+func LoadFigureData(ctx context.Context, client storage.Client, bucket string) ([]byte, error) {
+	reader, err := client.GetObject(ctx, bucket, FigureDataObject, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get figure data object: %w", err)
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read figure data: %w", err)
+	}
+	return data, nil
+}
+
+// LoadFigureAssets reads FigureMap.json from the gamedata bucket and lists the clothing
+// bundles of the assets bucket. It returns nil when there is no figure map, so the
+// asset check is skipped instead of flagging every set.
+func LoadFigureAssets(ctx context.Context, client storage.Client, gamedataBucket, assetsBucket string) (*FigureAssets, error) {
+	data, err := LoadFigureMap(ctx, client, gamedataBucket)
+	if err != nil {
+		if storage.IsNoSuchKey(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	assets, err := ParseFigureMap(data)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := minio.ListObjectsOptions{Prefix: FigureBundlePrefix, Recursive: true}
+	for obj := range client.ListObjects(ctx, assetsBucket, opts) {
+		if obj.Err != nil {
+			return nil, fmt.Errorf("failed to list clothing bundles: %w", obj.Err)
+		}
+		if name, ok := strings.CutSuffix(path.Base(obj.Key), ".nitro"); ok {
+			assets.Bundles[name] = true
+		}
+	}
+	return assets, nil
+}
+
+// LoadFigureMap downloads FigureMap.json from storage.
+func LoadFigureMap(ctx context.Context, client storage.Client, bucket string) ([]byte, error) {
+	reader, err := client.GetObject(ctx, bucket, FigureMapObject, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get figure map object: %w", err)
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read figure map: %w", err)
+	}
+	return data, nil
+}
+
+// ParseFigureMap parses FigureMap.json content into FigureAssets without any bundles.
+func ParseFigureMap(data []byte) (*FigureAssets, error) {
+	var fm figureMap
+	if err := json.Unmarshal(data, &fm); err != nil {
+		return nil, fmt.Errorf("failed to parse figure map JSON: %w", err)
+	}
+	assets := &FigureAssets{Libraries: make(map[string]string), Bundles: make(map[string]bool)}
+	for _, library := range fm.Libraries {
+		for _, part := range library.Parts {
+			assets.Libraries[figurePartKey(part.Type, part.ID)] = library.ID
+		}
+	}
+	return assets, nil
+}
+
+// ValidateFigureData checks FigureData.json content structurally: every set type must
+// reference a defined palette and set IDs must be unique within their set type. With
+// assets, every part of every set must also resolve to a library whose bundle exists;
+// nil assets skip that check, so local files can be validated without storage access.
+func ValidateFigureData(data []byte, assets *FigureAssets) (*FigureDataReport, error) {
+	var fd figureData
+	if err := json.Unmarshal(data, &fd); err != nil {
+		return nil, fmt.Errorf("failed to parse figure data JSON: %w", err)
+	}
+
+	report := &FigureDataReport{
+		Palettes:      len(fd.Palettes),
+		SetTypes:      len(fd.SetTypes),
+		AssetsChecked: assets != nil,
+		Issues:        make([]FigureIssue, 0),
+	}
+	palettes := make(map[int]bool, len(fd.Palettes))
+	for _, palette := range fd.Palettes {
+		palettes[palette.ID] = true
+	}
+
+	for _, setType := range fd.SetTypes {
+		if !palettes[setType.PaletteID] {
+			report.BrokenPalettes++
+			report.Issues = append(report.Issues, FigureIssue{
+				Kind:    FigureBrokenPalette,
+				SetType: setType.Type,
+				Problem: fmt.Sprintf("palette %d is not defined", setType.PaletteID),
+			})
+		}
+
+		seen := make(map[int]bool, len(setType.Sets))
+		for _, set := range setType.Sets {
+			report.Sets++
+			if seen[set.ID] {
+				report.DuplicateSets++
+				report.Issues = append(report.Issues, FigureIssue{
+					Kind:    FigureDuplicateSet,
+					SetType: setType.Type,
+					SetID:   set.ID,
+					Problem: "duplicate set id",
+				})
+			}
+			seen[set.ID] = true
+
+			if assets == nil {
+				continue
+			}
+			var missing []string
+			for _, part := range set.Parts {
+				key := figurePartKey(part.Type, part.ID)
+				library, mapped := assets.Libraries[key]
+				switch {
+				case !mapped:
+					missing = append(missing, fmt.Sprintf("%s (in no library)", key))
+				case !assets.Bundles[library]:
+					missing = append(missing, fmt.Sprintf("%s (%s%s.nitro)", key, FigureBundlePrefix, library))
+				}
+			}
+			if len(missing) > 0 {
+				slices.Sort(missing)
+				missing = slices.Compact(missing)
+				report.MissingAssets++
+				report.Issues = append(report.Issues, FigureIssue{
+					Kind:    FigureMissingAsset,
+					SetType: setType.Type,
+					SetID:   set.ID,
+					Problem: "missing clothing assets for parts " + strings.Join(missing, ", "),
+				})
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// figurePartKey identifies a clothing part, e.g. "hr:5".
+func figurePartKey(partType string, id int) string {
+	return fmt.Sprintf("%s:%d", partType, id)
+}
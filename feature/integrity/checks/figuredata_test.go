@@ -0,0 +1,92 @@
+package checks
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"asset-manager/core/storage/mocks"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const testFigureData = `{
+	"palettes": [{"id": 1, "colors": [{"id": 1, "hexCode": "FFFFFF"}]}],
+	"setTypes": [
+		{"type": "hr", "paletteId": 1, "sets": [
+			{"id": 1, "parts": [{"id": 1, "type": "hr"}, {"id": 1, "type": "hrb"}]},
+			{"id": 2, "parts": [{"id": 2, "type": "hr"}]},
+			{"id": 2, "parts": [{"id": 2, "type": "hr"}]}
+		]},
+		{"type": "ch", "paletteId": 3, "sets": [
+			{"id": 10, "parts": [{"id": 10, "type": "ch"}, {"id": 10, "type": "ls"}, {"id": 10, "type": "ls"}]}
+		]}
+	]
+}`
+
+const testFigureMap = `{"libraries": [
+	{"id": "hh_human_hair", "parts": [{"id": 1, "type": "hr"}, {"id": 1, "type": "hrb"}]},
+	{"id": "shirt_U_polo", "parts": [{"id": 2, "type": "hr"}, {"id": 10, "type": "ch"}]}
+]}`
+
+func TestValidateFigureData(t *testing.T) {
+	assets, err := ParseFigureMap([]byte(testFigureMap))
+	require.NoError(t, err)
+	assets.Bundles["hh_human_hair"] = true
+
+	report, err := ValidateFigureData([]byte(testFigureData), assets)
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Palettes)
+	assert.Equal(t, 2, report.SetTypes)
+	assert.Equal(t, 4, report.Sets)
+	assert.True(t, report.AssetsChecked)
+	assert.Equal(t, 1, report.BrokenPalettes)
+	assert.Equal(t, 1, report.DuplicateSets)
+	assert.Equal(t, 3, report.MissingAssets)
+
+	require.Len(t, report.Issues, 5)
+	assert.Equal(t, FigureIssue{Kind: FigureDuplicateSet, SetType: "hr", SetID: 2, Problem: "duplicate set id"}, report.Issues[1])
+	assert.Equal(t, FigureIssue{Kind: FigureBrokenPalette, SetType: "ch", Problem: "palette 3 is not defined"}, report.Issues[3])
+	// Each part is named once, whatever the number of layers using it
+	assert.Equal(t, "missing clothing assets for parts ch:10 (bundled/figure/shirt_U_polo.nitro), ls:10 (in no library)", report.Issues[4].Problem)
+}
+
+func TestValidateFigureData_WithoutAssets(t *testing.T) {
+	report, err := ValidateFigureData([]byte(testFigureData), nil)
+	require.NoError(t, err)
+	assert.False(t, report.AssetsChecked)
+	assert.Zero(t, report.MissingAssets)
+	assert.Len(t, report.Issues, 2)
+
+	_, err = ValidateFigureData([]byte("{"), nil)
+	assert.Error(t, err)
+}
+
+func TestLoadFigureAssets(t *testing.T) {
+	mockClient := new(mocks.Client)
+	mockClient.On("GetObject", mock.Anything, "gamedata", FigureMapObject, mock.Anything).
+		Return(io.NopCloser(strings.NewReader(testFigureMap)), nil).Once()
+	ch := make(chan minio.ObjectInfo, 2)
+	ch <- minio.ObjectInfo{Key: "bundled/figure/hh_human_hair.nitro"}
+	ch <- minio.ObjectInfo{Key: "bundled/figure/readme.txt"}
+	close(ch)
+	mockClient.On("ListObjects", mock.Anything, "assets", mock.MatchedBy(func(opts minio.ListObjectsOptions) bool {
+		return opts.Prefix == FigureBundlePrefix
+	})).Return((<-chan minio.ObjectInfo)(ch))
+
+	assets, err := LoadFigureAssets(context.Background(), mockClient, "gamedata", "assets")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"hh_human_hair": true}, assets.Bundles)
+	assert.Equal(t, "shirt_U_polo", assets.Libraries["ch:10"])
+
+	// Without a figure map the asset check is skipped
+	mockClient.On("GetObject", mock.Anything, "gamedata", FigureMapObject, mock.Anything).
+		Return(nil, minio.ErrorResponse{Code: "NoSuchKey"})
+	assets, err = LoadFigureAssets(context.Background(), mockClient, "gamedata", "assets")
+	require.NoError(t, err)
+	assert.Nil(t, assets)
+}
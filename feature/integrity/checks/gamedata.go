@@ -3,6 +3,7 @@ package checks
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"asset-manager/core/storage"
 
@@ -20,10 +21,41 @@ var RequiredGameDataFiles = []string{
 	"FurnitureData.json",
 }
 
-// CheckGameData returns a list of missing files in the gamedata folder.
-func CheckGameData(ctx context.Context, client storage.Client, bucket string) ([]string, error) {
-	var missing []string
+// GameDataFile describes one required gamedata file.
+type GameDataFile struct {
+	// Name is the file name inside the gamedata folder.
+	Name string `json:"name"`
+	// Present is true when the file exists.
+	Present bool `json:"present"`
+	// Size is the object size in bytes.
+	Size int64 `json:"size"`
+	// LastModified is when the object was last written. Nil when missing.
+	LastModified *time.Time `json:"last_modified,omitempty"`
+	// Warnings lists freshness problems, such as a stale or suspiciously small file.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// GameDataReport is the result of a gamedata check.
+type GameDataReport struct {
+	// Files holds one entry per required file, in RequiredGameDataFiles order.
+	Files []GameDataFile `json:"files"`
+	// Missing lists the required files that do not exist.
+	Missing []string `json:"missing"`
+	// Warnings is the number of present files with at least one warning.
+	Warnings int `json:"warnings"`
+}
 
+// FreshnessPolicy sets when a present gamedata file is flagged.
+type FreshnessPolicy struct {
+	// MaxAge flags files that have not changed for longer. Zero disables the check.
+	MaxAge time.Duration
+	// MinSize flags files smaller than this many bytes, which usually means a
+	// truncated upload. Zero disables the check.
+	MinSize int64
+}
+
+// CheckGameData reports the presence, size and age of every required gamedata file.
+func CheckGameData(ctx context.Context, client storage.Client, bucket string, policy FreshnessPolicy) (*GameDataReport, error) {
 	exists, err := client.BucketExists(ctx, bucket)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check bucket existence: %w", err)
@@ -32,6 +64,12 @@ func CheckGameData(ctx context.Context, client storage.Client, bucket string) ([
 		return nil, fmt.Errorf("bucket %s does not exist", bucket)
 	}
 
+	report := &GameDataReport{
+		Files:   make([]GameDataFile, 0, len(RequiredGameDataFiles)),
+		Missing: []string{},
+	}
+	now := time.Now()
+
 	for _, filename := range RequiredGameDataFiles {
 		filePath := "gamedata/" + filename
 		opts := minio.ListObjectsOptions{
@@ -40,18 +78,38 @@ func CheckGameData(ctx context.Context, client storage.Client, bucket string) ([
 			MaxKeys:   1,
 		}
 
-		found := false
+		file := GameDataFile{Name: filename}
 		for obj := range client.ListObjects(ctx, bucket, opts) {
 			if obj.Err == nil && obj.Key == filePath {
-				found = true
+				file.Present = true
+				file.Size = obj.Size
+				modified := obj.LastModified
+				file.LastModified = &modified
 			}
 			break
 		}
 
-		if !found {
-			missing = append(missing, filename)
+		if !file.Present {
+			report.Missing = append(report.Missing, filename)
+		} else if file.Warnings = freshnessWarnings(file, policy, now); len(file.Warnings) > 0 {
+			report.Warnings++
 		}
+		report.Files = append(report.Files, file)
 	}
 
-	return missing, nil
+	return report, nil
+}
+
+// freshnessWarnings returns the policy violations of a present file.
+func freshnessWarnings(file GameDataFile, policy FreshnessPolicy, now time.Time) []string {
+	var warnings []string
+	if policy.MinSize > 0 && file.Size < policy.MinSize {
+		warnings = append(warnings, fmt.Sprintf("only %d bytes (minimum %d), possibly a truncated upload", file.Size, policy.MinSize))
+	}
+	if policy.MaxAge > 0 && file.LastModified != nil {
+		if age := now.Sub(*file.LastModified); age > policy.MaxAge {
+			warnings = append(warnings, fmt.Sprintf("unchanged for %s (maximum %s)", age.Truncate(time.Hour), policy.MaxAge))
+		}
+	}
+	return warnings
 }
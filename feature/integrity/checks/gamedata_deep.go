@@ -0,0 +1,153 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"asset-manager/core/json"
+	"asset-manager/core/storage"
+	"asset-manager/feature/furniture/models"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// FurnitureDataObject is the storage key of the furniture gamedata file.
+const FurnitureDataObject = "gamedata/FurnitureData.json"
+
+// hexColorPattern matches part colors such as "#FFFFFF" or "FFFFFF".
+var hexColorPattern = regexp.MustCompile(`^#?[0-9A-Fa-f]{6}$`)
+
+// GamedataIssue describes a single internal problem found in FurnitureData.json.
+type GamedataIssue struct {
+	// Section is the JSON section holding the item ("roomitemtypes" or "wallitemtypes").
+	Section string `json:"section"`
+	// ID is the furniture ID of the offending item.
+	ID int `json:"id"`
+	// ClassName is the classname of the offending item.
+	ClassName string `json:"classname"`
+	// Problem describes what is wrong.
+	Problem string `json:"problem"`
+}
+
+// GamedataDeepReport contains the results of the offline gamedata validation.
+type GamedataDeepReport struct {
+	// TotalItems is the number of furniture entries inspected.
+	TotalItems int `json:"total_items"`
+	// DuplicateIDs counts IDs defined more than once.
+	DuplicateIDs int `json:"duplicate_ids"`
+	// DuplicateClassNames counts classnames defined more than once.
+	DuplicateClassNames int `json:"duplicate_classnames"`
+	// InvalidColors counts items with malformed color variants or part colors.
+	InvalidColors int `json:"invalid_colors"`
+	// MissingFields counts items missing required fields.
+	MissingFields int `json:"missing_fields"`
+	// Issues lists every problem found.
+	Issues []GamedataIssue `json:"issues"`
+}
+
+// LoadFurnitureData downloads FurnitureData.json from storage.
+func LoadFurnitureData(ctx context.Context, client storage.Client, bucket string) ([]byte, error) {
+	reader, err := client.GetObject(ctx, bucket, FurnitureDataObject, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get gamedata object: %w", err)
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read gamedata: %w", err)
+	}
+	return data, nil
+}
+
+// ValidateFurnitureData runs gamedata-internal validations on FurnitureData.json content.
+// It needs neither storage nor database access, so it also works on local files.
+func ValidateFurnitureData(data []byte) (*GamedataDeepReport, error) {
+	var furniData models.FurnitureData
+	if err := json.Unmarshal(data, &furniData); err != nil {
+		return nil, fmt.Errorf("failed to parse gamedata JSON: %w", err)
+	}
+
+	report := &GamedataDeepReport{Issues: make([]GamedataIssue, 0)}
+	seenIDs := make(map[int]string)
+	seenClassNames := make(map[string]int)
+
+	sections := []struct {
+		name  string
+		items []models.FurnitureItem
+	}{
+		{"roomitemtypes", furniData.RoomItemTypes.FurniType},
+		{"wallitemtypes", furniData.WallItemTypes.FurniType},
+	}
+
+	for _, section := range sections {
+		for _, item := range section.items {
+			report.TotalItems++
+			addIssue := func(problem string) {
+				report.Issues = append(report.Issues, GamedataIssue{
+					Section:   section.name,
+					ID:        item.ID,
+					ClassName: item.ClassName,
+					Problem:   problem,
+				})
+			}
+
+			// Required fields and classname format
+			if problem := item.Validate(); problem != "" {
+				if strings.HasPrefix(problem, "invalid classname") {
+					report.InvalidColors++
+				} else {
+					report.MissingFields++
+				}
+				addIssue(problem)
+			}
+
+			// Duplicate IDs (across both sections)
+			if item.ID != 0 {
+				if firstSection, dup := seenIDs[item.ID]; dup {
+					report.DuplicateIDs++
+					addIssue(fmt.Sprintf("duplicate id (first defined in %s)", firstSection))
+				} else {
+					seenIDs[item.ID] = section.name
+				}
+			}
+
+			// Duplicate classnames
+			if item.ClassName != "" {
+				if firstID, dup := seenClassNames[item.ClassName]; dup {
+					report.DuplicateClassNames++
+					addIssue(fmt.Sprintf("duplicate classname (first defined by id %d)", firstID))
+				} else {
+					seenClassNames[item.ClassName] = item.ID
+				}
+			}
+
+			// Color variants
+			if problem := validateColors(item); problem != "" {
+				report.InvalidColors++
+				addIssue(problem)
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// validateColors checks the classname color index and the part color values.
+func validateColors(item models.FurnitureItem) string {
+	if _, colorIndex, found := strings.Cut(item.ClassName, "*"); found && colorIndex != "" {
+		if _, err := strconv.Atoi(colorIndex); err != nil && !strings.Contains(colorIndex, "*") {
+			return fmt.Sprintf("invalid color index %q: must be numeric", colorIndex)
+		}
+	}
+	for _, color := range item.PartColors.Color {
+		if !hexColorPattern.MatchString(color) {
+			return fmt.Sprintf("invalid part color %q: must be a hex color like #FFFFFF", color)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,52 @@
+package checks
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"asset-manager/core/storage/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestValidateFurnitureData(t *testing.T) {
+	data := `{
+		"roomitemtypes": {"furnitype": [
+			{"id": 1, "classname": "chair", "name": "Chair", "category": "chair"},
+			{"id": 2, "classname": "chair", "name": "Chair Copy", "category": "chair"},
+			{"id": 3, "classname": "sofa*x", "name": "Sofa", "category": "sofa"},
+			{"id": 4, "classname": "lamp", "name": "Lamp", "category": "lighting", "partcolors": {"color": ["#FFFFFF", "red"]}},
+			{"id": 5, "classname": "table", "category": "table"}
+		]},
+		"wallitemtypes": {"furnitype": [
+			{"id": 1, "classname": "poster", "name": "Poster", "category": "poster"}
+		]}
+	}`
+
+	report, err := ValidateFurnitureData([]byte(data))
+	assert.NoError(t, err)
+	assert.Equal(t, 6, report.TotalItems)
+	assert.Equal(t, 1, report.DuplicateIDs)
+	assert.Equal(t, 1, report.DuplicateClassNames)
+	assert.Equal(t, 2, report.InvalidColors)
+	assert.Equal(t, 1, report.MissingFields)
+	assert.Len(t, report.Issues, 5)
+}
+
+func TestValidateFurnitureData_InvalidJSON(t *testing.T) {
+	_, err := ValidateFurnitureData([]byte("{"))
+	assert.Error(t, err)
+}
+
+func TestLoadFurnitureData(t *testing.T) {
+	mockClient := new(mocks.Client)
+	mockClient.On("GetObject", mock.Anything, "assets", FurnitureDataObject, mock.Anything).
+		Return(io.NopCloser(strings.NewReader(`{}`)), nil)
+
+	data, err := LoadFurnitureData(context.Background(), mockClient, "assets")
+	assert.NoError(t, err)
+	assert.Equal(t, "{}", string(data))
+}
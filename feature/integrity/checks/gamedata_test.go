@@ -3,6 +3,7 @@ package checks
 import (
 	"context"
 	"testing"
+	"time"
 
 	"asset-manager/core/storage/mocks"
 
@@ -21,9 +22,12 @@ func TestCheckGameData(t *testing.T) {
 		// For any ListObjects call, return empty channel
 		mockClient.On("ListObjects", mock.Anything, "assets", mock.Anything).Return((<-chan minio.ObjectInfo)(ch))
 
-		missing, err := CheckGameData(context.Background(), mockClient, "assets")
+		report, err := CheckGameData(context.Background(), mockClient, "assets", FreshnessPolicy{})
 		assert.NoError(t, err)
-		assert.Len(t, missing, len(RequiredGameDataFiles))
+		assert.Len(t, report.Missing, len(RequiredGameDataFiles))
+		assert.Len(t, report.Files, len(RequiredGameDataFiles))
+		assert.False(t, report.Files[0].Present)
+		assert.Nil(t, report.Files[0].LastModified)
 	})
 
 	t.Run("GameData All Present", func(t *testing.T) {
@@ -41,16 +45,17 @@ func TestCheckGameData(t *testing.T) {
 			})).Return((<-chan minio.ObjectInfo)(ch))
 		}
 
-		missing, err := CheckGameData(context.Background(), mockClient, "assets")
+		report, err := CheckGameData(context.Background(), mockClient, "assets", FreshnessPolicy{})
 		assert.NoError(t, err)
-		assert.Len(t, missing, 0)
+		assert.Len(t, report.Missing, 0)
+		assert.Zero(t, report.Warnings)
 	})
 
 	t.Run("Bucket Missing", func(t *testing.T) {
 		mockClient := new(mocks.Client)
 		mockClient.On("BucketExists", mock.Anything, "assets").Return(false, nil)
 
-		_, err := CheckGameData(context.Background(), mockClient, "assets")
+		_, err := CheckGameData(context.Background(), mockClient, "assets", FreshnessPolicy{})
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "does not exist")
 	})
@@ -59,8 +64,58 @@ func TestCheckGameData(t *testing.T) {
 		mockClient := new(mocks.Client)
 		mockClient.On("BucketExists", mock.Anything, "assets").Return(false, assert.AnError)
 
-		_, err := CheckGameData(context.Background(), mockClient, "assets")
+		_, err := CheckGameData(context.Background(), mockClient, "assets", FreshnessPolicy{})
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "failed to check bucket existence")
 	})
 }
+
+func TestCheckGameData_Freshness(t *testing.T) {
+	mockClient := new(mocks.Client)
+	mockClient.On("BucketExists", mock.Anything, "assets").Return(true, nil)
+
+	fresh := time.Now().Add(-time.Hour)
+	stale := time.Now().Add(-60 * 24 * time.Hour)
+	objects := map[string]minio.ObjectInfo{
+		"FurnitureData.json": {Size: 10, LastModified: fresh},
+		"ProductData.json":   {Size: 4096, LastModified: stale},
+		"FigureData.json":    {Size: 10, LastModified: stale},
+	}
+	for _, filename := range RequiredGameDataFiles {
+		info, ok := objects[filename]
+		if !ok {
+			info = minio.ObjectInfo{Size: 4096, LastModified: fresh}
+		}
+		info.Key = "gamedata/" + filename
+
+		ch := make(chan minio.ObjectInfo, 1)
+		ch <- info
+		close(ch)
+
+		targetPrefix := info.Key
+		mockClient.On("ListObjects", mock.Anything, "assets", mock.MatchedBy(func(opts minio.ListObjectsOptions) bool {
+			return opts.Prefix == targetPrefix
+		})).Return((<-chan minio.ObjectInfo)(ch))
+	}
+
+	report, err := CheckGameData(context.Background(), mockClient, "assets", FreshnessPolicy{
+		MaxAge:  30 * 24 * time.Hour,
+		MinSize: 64,
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, report.Missing)
+	assert.Equal(t, 3, report.Warnings)
+
+	byName := map[string]GameDataFile{}
+	for _, file := range report.Files {
+		byName[file.Name] = file
+	}
+	assert.Len(t, byName["FurnitureData.json"].Warnings, 1)
+	assert.Contains(t, byName["FurnitureData.json"].Warnings[0], "truncated")
+	assert.Len(t, byName["ProductData.json"].Warnings, 1)
+	assert.Contains(t, byName["ProductData.json"].Warnings[0], "unchanged")
+	assert.Len(t, byName["FigureData.json"].Warnings, 2)
+	assert.Empty(t, byName["UITexts.json"].Warnings)
+	assert.Equal(t, int64(4096), byName["UITexts.json"].Size)
+	assert.NotNil(t, byName["UITexts.json"].LastModified)
+}
@@ -36,7 +36,9 @@ func CheckServerIntegrity(db *gorm.DB, emulator string) (*ServerReport, error) {
 	switch emulator {
 	case "arcturus":
 		model = models.ArcturusItemsBase{}
-	case "plus":
+	case "arcturus-ms":
+		model = models.ArcturusMSItemsBase{}
+	case "plus", "plusemu":
 		model = models.PlusFurniture{}
 	case "comet":
 		model = models.CometFurniture{}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 )
@@ -38,6 +39,17 @@ func TestCheckServerIntegrity_UnknownEmulator(t *testing.T) {
 	assert.Equal(t, "unknown emulator model: unknown", err.Error())
 }
 
+func TestCheckServerIntegrity_PlusEmu(t *testing.T) {
+	db, mock := setupMockDB(t)
+	header := []string{"Field", "Type", "Null", "Key", "Default", "Extra"}
+	mock.ExpectQuery("SHOW COLUMNS FROM `furniture`").WillReturnRows(sqlmock.NewRows(header))
+
+	report, err := CheckServerIntegrity(db, "plusemu")
+	require.NoError(t, err)
+	assert.Equal(t, "plusemu", report.Emulator)
+	assert.Contains(t, report.Tables, "furniture")
+}
+
 func TestCheckServerIntegrity_NilDB(t *testing.T) {
 	report, err := CheckServerIntegrity(nil, "arcturus")
 	assert.Error(t, err)
@@ -147,3 +159,48 @@ func TestCheckServerIntegrity_Arcturus_RareEnum(t *testing.T) {
 	}
 	assert.False(t, foundMismatch, "Should NOT detect mismatch for slightly different rare enum. Got: %v", tbl.TypeMismatches)
 }
+
+// arcturusMSColumns adds the items_base columns of an Arcturus Morningstar 4.x database.
+func arcturusMSColumns(rows *sqlmock.Rows) *sqlmock.Rows {
+	rows.AddRow("id", "int(11)", "NO", "PRI", nil, "auto_increment")
+	rows.AddRow("sprite_id", "int(11)", "NO", "", "0", "")
+	rows.AddRow("item_name", "varchar(70)", "NO", "", "0", "")
+	rows.AddRow("public_name", "varchar(56)", "NO", "", "0", "")
+	rows.AddRow("width", "int(11)", "NO", "", "1", "")
+	rows.AddRow("length", "int(11)", "NO", "", "1", "")
+	rows.AddRow("stack_height", "double(4,2)", "NO", "", "0.00", "")
+	for _, flag := range []string{"allow_stack", "allow_sit", "allow_lay", "allow_walk", "allow_gift", "allow_trade", "allow_recycle", "allow_marketplace_sell", "allow_inventory_stack"} {
+		rows.AddRow(flag, "enum('0','1')", "NO", "", "0", "")
+	}
+	rows.AddRow("type", "varchar(3)", "NO", "", "s", "")
+	rows.AddRow("interaction_type", "varchar(1000)", "NO", "", "default", "")
+	rows.AddRow("interaction_modes_count", "int(11)", "NO", "", "1", "")
+	rows.AddRow("vending_ids", "varchar(255)", "NO", "", "0", "")
+	rows.AddRow("multiheight", "varchar(50)", "NO", "", "0", "")
+	rows.AddRow("customparams", "mediumtext", "YES", "", nil, "")
+	rows.AddRow("effect_id_male", "int(11)", "NO", "", "0", "")
+	rows.AddRow("effect_id_female", "int(11)", "NO", "", "0", "")
+	rows.AddRow("clothing_on_walk", "varchar(255)", "YES", "", nil, "")
+	rows.AddRow("page_id", "varchar(250)", "YES", "", nil, "")
+	rows.AddRow("rare", "enum('0','1','2','3','4')", "NO", "", "0", "")
+	return rows
+}
+
+// TestCheckServerIntegrity_ArcturusMS tests that a Morningstar 4.x schema matches the
+// arcturus-ms model and reports its flags as mismatches against the legacy one.
+func TestCheckServerIntegrity_ArcturusMS(t *testing.T) {
+	db, mock := setupMockDB(t)
+	header := []string{"Field", "Type", "Null", "Key", "Default", "Extra"}
+
+	mock.ExpectQuery("SHOW COLUMNS FROM `items_base`").WillReturnRows(arcturusMSColumns(sqlmock.NewRows(header)))
+	report, err := CheckServerIntegrity(db, "arcturus-ms")
+	require.NoError(t, err)
+	assert.True(t, report.Matched, "unexpected report: %+v", report.Tables)
+
+	mock.ExpectQuery("SHOW COLUMNS FROM `items_base`").WillReturnRows(arcturusMSColumns(sqlmock.NewRows(header)))
+	report, err = CheckServerIntegrity(db, "arcturus")
+	require.NoError(t, err)
+	assert.False(t, report.Matched)
+	assert.Contains(t, report.Tables["items_base"].TypeMismatches, "allow_stack: expected tinyint(1), got enum('0','1')")
+	assert.Contains(t, report.Tables["items_base"].TypeMismatches, "customparams: expected varchar(25600), got mediumtext")
+}
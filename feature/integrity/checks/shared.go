@@ -3,6 +3,7 @@ package checks
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 
@@ -12,7 +13,15 @@ import (
 	"go.uber.org/zap"
 )
 
+// KeepObject is the object written under a folder when a .keep marker is requested.
+const KeepObject = ".keep"
+
+// ErrKeepRequired is returned when a fix is requested in prefix folder mode without
+// explicitly asking for .keep objects.
+var ErrKeepRequired = errors.New("folders are plain prefixes (folder mode prefix); request .keep objects to create them")
+
 // CheckFolders is a generic function to check for the existence of a list of folders.
+// A folder exists when any object lives under its prefix, placeholder or not.
 func CheckFolders(ctx context.Context, client storage.Client, bucket string, folders []string) ([]string, error) {
 	var missing []string
 
@@ -50,13 +59,58 @@ func CheckFolders(ctx context.Context, client storage.Client, bucket string, fol
 	return missing, nil
 }
 
-// FixFolders is a generic function to create missing folders.
-func FixFolders(ctx context.Context, client storage.Client, bucket string, logger *zap.Logger, missing []string) error {
-	for _, folder := range missing {
-		folderPath := folder
-		if !strings.HasSuffix(folderPath, "/") {
-			folderPath += "/"
+// ResolveFolders cleans a configured folder list, dropping blanks and trailing slashes.
+// The defaults are used when nothing usable is configured.
+func ResolveFolders(configured, defaults []string) []string {
+	folders := make([]string, 0, len(configured))
+	for _, folder := range configured {
+		folder = strings.TrimSuffix(strings.TrimSpace(folder), "/")
+		if folder != "" {
+			folders = append(folders, folder)
 		}
+	}
+	if len(folders) == 0 {
+		return defaults
+	}
+	return folders
+}
+
+// Placeholder is an empty object that a folder fix creates to make a folder exist:
+// either the "folder/" object itself or a "folder/.keep" marker.
+type Placeholder struct {
+	// Folder is the missing folder.
+	Folder string `json:"folder"`
+	// Bucket is the bucket the placeholder is written to.
+	Bucket string `json:"bucket"`
+	// Key is the object key of the placeholder.
+	Key string `json:"key"`
+}
+
+// PlanFolders returns the placeholders FixFolders would create for the missing folders.
+func PlanFolders(bucket string, missing []string, keep bool) []Placeholder {
+	placeholders := make([]Placeholder, 0, len(missing))
+	for _, folder := range missing {
+		placeholders = append(placeholders, Placeholder{Folder: folder, Bucket: bucket, Key: placeholderKey(folder, keep)})
+	}
+	return placeholders
+}
+
+// placeholderKey returns the object key of a folder placeholder, or of its .keep marker.
+func placeholderKey(folder string, keep bool) string {
+	if !strings.HasSuffix(folder, "/") {
+		folder += "/"
+	}
+	if keep {
+		return folder + KeepObject
+	}
+	return folder
+}
+
+// FixFolders is a generic function to create missing folders. With keep, a .keep
+// marker is written under each folder instead of a zero-byte folder object.
+func FixFolders(ctx context.Context, client storage.Client, bucket string, logger *zap.Logger, missing []string, keep bool) error {
+	for _, folder := range missing {
+		folderPath := placeholderKey(folder, keep)
 
 		_, err := client.PutObject(ctx, bucket, folderPath, bytes.NewReader([]byte{}), 0, minio.PutObjectOptions{})
 		if err != nil {
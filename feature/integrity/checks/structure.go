@@ -8,17 +8,63 @@ import (
 	"go.uber.org/zap"
 )
 
-// RequiredFolders lists the folders that must exist in the bucket.
+// RequiredFolders lists the folders that must exist in the bucket when no layout is configured.
 var RequiredFolders = []string{
 	"bundled", "c_images", "dcr", "gamedata", "images", "logos", "sounds",
 }
 
-// CheckStructure returns a list of missing folders.
-func CheckStructure(ctx context.Context, client storage.Client, bucket string) ([]string, error) {
-	return CheckFolders(ctx, client, bucket, RequiredFolders)
+// CheckStructure returns the folders that are missing, looking up each folder in the
+// bucket that holds it.
+func CheckStructure(ctx context.Context, client storage.Client, buckets storage.Buckets, folders []string) ([]string, error) {
+	var missing []string
+	for _, group := range groupFolders(buckets, folders) {
+		groupMissing, err := CheckFolders(ctx, client, group.bucket, group.folders)
+		if err != nil {
+			return nil, err
+		}
+		missing = append(missing, groupMissing...)
+	}
+	return missing, nil
 }
 
-// FixStructure creates the missing folders.
-func FixStructure(ctx context.Context, client storage.Client, bucket string, logger *zap.Logger, missing []string) error {
-	return FixFolders(ctx, client, bucket, logger, missing)
+// FixStructure creates the missing folders in the bucket that holds each of them.
+func FixStructure(ctx context.Context, client storage.Client, buckets storage.Buckets, logger *zap.Logger, missing []string, keep bool) error {
+	for _, group := range groupFolders(buckets, missing) {
+		if err := FixFolders(ctx, client, group.bucket, logger, group.folders, keep); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PlanStructure returns the placeholders FixStructure would create for the missing folders.
+func PlanStructure(buckets storage.Buckets, missing []string, keep bool) []Placeholder {
+	var placeholders []Placeholder
+	for _, group := range groupFolders(buckets, missing) {
+		placeholders = append(placeholders, PlanFolders(group.bucket, group.folders, keep)...)
+	}
+	return placeholders
+}
+
+// folderGroup is a set of folders living in the same bucket.
+type folderGroup struct {
+	bucket  string
+	folders []string
+}
+
+// groupFolders splits folders by bucket, keeping the order in which buckets first appear.
+func groupFolders(buckets storage.Buckets, folders []string) []folderGroup {
+	var groups []folderGroup
+	index := make(map[string]int)
+	for _, folder := range folders {
+		bucket := buckets.ForFolder(folder)
+		i, ok := index[bucket]
+		if !ok {
+			i = len(groups)
+			index[bucket] = i
+			groups = append(groups, folderGroup{bucket: bucket})
+		}
+		groups[i].folders = append(groups[i].folders, folder)
+	}
+	return groups
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"testing"
 
+	"asset-manager/core/storage"
 	"asset-manager/core/storage/mocks"
 
 	"github.com/minio/minio-go/v7"
@@ -17,7 +18,7 @@ func TestCheckStructure(t *testing.T) {
 		mockClient := new(mocks.Client)
 		mockClient.On("BucketExists", mock.Anything, "assets").Return(false, nil)
 
-		_, err := CheckStructure(context.Background(), mockClient, "assets")
+		_, err := CheckStructure(context.Background(), mockClient, storage.SingleBucket("assets"), RequiredFolders)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "does not exist")
 	})
@@ -29,7 +30,7 @@ func TestCheckStructure(t *testing.T) {
 		close(ch)
 		mockClient.On("ListObjects", mock.Anything, "assets", mock.Anything).Return((<-chan minio.ObjectInfo)(ch))
 
-		missing, err := CheckStructure(context.Background(), mockClient, "assets")
+		missing, err := CheckStructure(context.Background(), mockClient, storage.SingleBucket("assets"), RequiredFolders)
 		assert.NoError(t, err)
 		assert.Len(t, missing, len(RequiredFolders))
 	})
@@ -47,7 +48,7 @@ func TestCheckStructure(t *testing.T) {
 			})).Return((<-chan minio.ObjectInfo)(ch))
 		}
 
-		missing, err := CheckStructure(context.Background(), mockClient, "assets")
+		missing, err := CheckStructure(context.Background(), mockClient, storage.SingleBucket("assets"), RequiredFolders)
 		assert.NoError(t, err)
 		assert.Len(t, missing, 0)
 	})
@@ -55,10 +56,31 @@ func TestCheckStructure(t *testing.T) {
 		mockClient := new(mocks.Client)
 		mockClient.On("BucketExists", mock.Anything, "assets").Return(false, assert.AnError)
 
-		_, err := CheckStructure(context.Background(), mockClient, "assets")
+		_, err := CheckStructure(context.Background(), mockClient, storage.SingleBucket("assets"), RequiredFolders)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "failed to check bucket existence")
 	})
+
+	t.Run("Split Buckets", func(t *testing.T) {
+		mockClient := new(mocks.Client)
+		mockClient.On("BucketExists", mock.Anything, "assets").Return(true, nil)
+		mockClient.On("BucketExists", mock.Anything, "gamedata-bucket").Return(true, nil)
+		empty := make(chan minio.ObjectInfo)
+		close(empty)
+		mockClient.On("ListObjects", mock.Anything, "assets", mock.Anything).Return((<-chan minio.ObjectInfo)(empty))
+		mockClient.On("ListObjects", mock.Anything, "gamedata-bucket", mock.Anything).Return((<-chan minio.ObjectInfo)(empty))
+
+		buckets := storage.Buckets{Assets: "assets", Gamedata: "gamedata-bucket"}
+		missing, err := CheckStructure(context.Background(), mockClient, buckets, RequiredFolders)
+		assert.NoError(t, err)
+		assert.Len(t, missing, len(RequiredFolders))
+		mockClient.AssertCalled(t, "ListObjects", mock.Anything, "gamedata-bucket", mock.MatchedBy(func(opts minio.ListObjectsOptions) bool {
+			return opts.Prefix == "gamedata/"
+		}))
+		mockClient.AssertNotCalled(t, "ListObjects", mock.Anything, "assets", mock.MatchedBy(func(opts minio.ListObjectsOptions) bool {
+			return opts.Prefix == "gamedata/"
+		}))
+	})
 }
 
 func TestFixStructure(t *testing.T) {
@@ -68,7 +90,7 @@ func TestFixStructure(t *testing.T) {
 		mockClient := new(mocks.Client)
 		mockClient.On("PutObject", mock.Anything, "assets", mock.Anything, mock.Anything, int64(0), mock.Anything).Return(minio.UploadInfo{}, nil)
 
-		err := FixStructure(context.Background(), mockClient, "assets", logger, []string{"bundled"})
+		err := FixStructure(context.Background(), mockClient, storage.SingleBucket("assets"), logger, []string{"bundled"}, false)
 		assert.NoError(t, err)
 		mockClient.AssertNumberOfCalls(t, "PutObject", 1)
 	})
@@ -77,8 +99,42 @@ func TestFixStructure(t *testing.T) {
 		mockClient := new(mocks.Client)
 		mockClient.On("PutObject", mock.Anything, "assets", mock.Anything, mock.Anything, int64(0), mock.Anything).Return(minio.UploadInfo{}, assert.AnError)
 
-		err := FixStructure(context.Background(), mockClient, "assets", logger, []string{"bundled"})
+		err := FixStructure(context.Background(), mockClient, storage.SingleBucket("assets"), logger, []string{"bundled"}, false)
 		assert.Error(t, err)
 		assert.Equal(t, assert.AnError, err)
 	})
+
+	t.Run("Split Buckets", func(t *testing.T) {
+		mockClient := new(mocks.Client)
+		mockClient.On("PutObject", mock.Anything, "assets", "bundled/", mock.Anything, int64(0), mock.Anything).Return(minio.UploadInfo{}, nil)
+		mockClient.On("PutObject", mock.Anything, "gamedata-bucket", "gamedata/", mock.Anything, int64(0), mock.Anything).Return(minio.UploadInfo{}, nil)
+
+		buckets := storage.Buckets{Assets: "assets", Gamedata: "gamedata-bucket"}
+		err := FixStructure(context.Background(), mockClient, buckets, logger, []string{"bundled", "gamedata"}, false)
+		assert.NoError(t, err)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("Keep Markers", func(t *testing.T) {
+		mockClient := new(mocks.Client)
+		mockClient.On("PutObject", mock.Anything, "assets", "bundled/.keep", mock.Anything, int64(0), mock.Anything).Return(minio.UploadInfo{}, nil)
+
+		err := FixStructure(context.Background(), mockClient, storage.SingleBucket("assets"), logger, []string{"bundled"}, true)
+		assert.NoError(t, err)
+		mockClient.AssertExpectations(t)
+	})
+}
+
+func TestPlanStructure(t *testing.T) {
+	buckets := storage.Buckets{Assets: "assets", Gamedata: "gamedata-bucket"}
+
+	placeholders := PlanStructure(buckets, []string{"bundled", "gamedata", "sounds/"}, false)
+	assert.Equal(t, []Placeholder{
+		{Folder: "bundled", Bucket: "assets", Key: "bundled/"},
+		{Folder: "sounds/", Bucket: "assets", Key: "sounds/"},
+		{Folder: "gamedata", Bucket: "gamedata-bucket", Key: "gamedata/"},
+	}, placeholders)
+
+	keeps := PlanStructure(buckets, []string{"sounds/"}, true)
+	assert.Equal(t, []Placeholder{{Folder: "sounds/", Bucket: "assets", Key: "sounds/.keep"}}, keeps)
 }
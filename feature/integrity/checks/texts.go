@@ -0,0 +1,196 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"asset-manager/core/json"
+	"asset-manager/core/storage"
+	"asset-manager/feature/furniture/models"
+
+	"github.com/minio/minio-go/v7"
+)
+
+const (
+	// ProductDataObject is the storage key of the product gamedata file.
+	ProductDataObject = "gamedata/ProductData.json"
+	// ExternalTextsObject is the storage key of the external texts file.
+	ExternalTextsObject = "gamedata/ExternalTexts.json"
+)
+
+// Text problem kinds, one per report counter.
+const (
+	// TextMissingProduct marks an item without a ProductData.json entry.
+	TextMissingProduct = "missing_product"
+	// TextMissingName marks an item without a name in ExternalTexts.json.
+	TextMissingName = "missing_name"
+	// TextMissingDescription marks an item without a description in ExternalTexts.json.
+	TextMissingDescription = "missing_description"
+)
+
+// productData is the part of ProductData.json the check reads.
+type productData struct {
+	ProductData struct {
+		Product []struct {
+			Code string `json:"code"`
+		} `json:"product"`
+	} `json:"productdata"`
+}
+
+// TextIssue describes a furniture item missing a product entry or text.
+type TextIssue struct {
+	// Kind is TextMissingProduct, TextMissingName or TextMissingDescription.
+	Kind string `json:"kind"`
+	// Section is the JSON section holding the item ("roomitemtypes" or "wallitemtypes").
+	Section string `json:"section"`
+	// ID is the furniture ID of the item.
+	ID int `json:"id"`
+	// ClassName is the classname of the item.
+	ClassName string `json:"classname"`
+	// Problem describes what is missing.
+	Problem string `json:"problem"`
+}
+
+// TextsReport contains the results of the FurnitureData.json cross-check against
+// ProductData.json and ExternalTexts.json.
+type TextsReport struct {
+	// TotalItems is the number of furniture entries inspected.
+	TotalItems int `json:"total_items"`
+	// MissingProducts counts items without a ProductData.json entry.
+	MissingProducts int `json:"missing_products"`
+	// MissingNames counts items without a name text.
+	MissingNames int `json:"missing_names"`
+	// MissingDescriptions counts items without a description text.
+	MissingDescriptions int `json:"missing_descriptions"`
+	// BlankTooltips lists the classnames of the items missing a name or description,
+	// whose tooltips show blank to players.
+	BlankTooltips []string `json:"blank_tooltips"`
+	// Issues lists every problem found.
+	Issues []TextIssue `json:"issues"`
+}
+
+// LoadProductData downloads ProductData.json from storage.
+func LoadProductData(ctx context.Context, client storage.Client, bucket string) ([]byte, error) {
+	reader, err := client.GetObject(ctx, bucket, ProductDataObject, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product data object: %w", err)
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read product data: %w", err)
+	}
+	return data, nil
+}
+
+// LoadExternalTexts downloads ExternalTexts.json from storage.
+func LoadExternalTexts(ctx context.Context, client storage.Client, bucket string) ([]byte, error) {
+	reader, err := client.GetObject(ctx, bucket, ExternalTextsObject, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get external texts object: %w", err)
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read external texts: %w", err)
+	}
+	return data, nil
+}
+
+// CrossCheckTexts checks that every FurnitureData.json classname has a ProductData.json
+// entry and name and description texts in ExternalTexts.json. Floor items use the
+// furni_<classname>_name and furni_<classname>_desc keys, wall items the wallitem_
+// prefix or, failing that, the furni_ one. Color variants replace "*" by "_", so
+// "chair*3" reads furni_chair_3_name. Empty texts count as missing.
+func CrossCheckTexts(furniture, products, texts []byte) (*TextsReport, error) {
+	var furniData models.FurnitureData
+	if err := json.Unmarshal(furniture, &furniData); err != nil {
+		return nil, fmt.Errorf("failed to parse gamedata JSON: %w", err)
+	}
+	var pd productData
+	if err := json.Unmarshal(products, &pd); err != nil {
+		return nil, fmt.Errorf("failed to parse product data JSON: %w", err)
+	}
+	var textMap map[string]any
+	if err := json.Unmarshal(texts, &textMap); err != nil {
+		return nil, fmt.Errorf("failed to parse external texts JSON: %w", err)
+	}
+
+	codes := make(map[string]bool, len(pd.ProductData.Product))
+	for _, product := range pd.ProductData.Product {
+		codes[product.Code] = true
+	}
+	hasText := func(keys []string) bool {
+		for _, key := range keys {
+			if text, ok := textMap[key].(string); ok && strings.TrimSpace(text) != "" {
+				return true
+			}
+		}
+		return false
+	}
+
+	report := &TextsReport{BlankTooltips: make([]string, 0), Issues: make([]TextIssue, 0)}
+	sections := []struct {
+		name     string
+		items    []models.FurnitureItem
+		prefixes []string
+	}{
+		{"roomitemtypes", furniData.RoomItemTypes.FurniType, []string{"furni_"}},
+		{"wallitemtypes", furniData.WallItemTypes.FurniType, []string{"wallitem_", "furni_"}},
+	}
+
+	for _, section := range sections {
+		for _, item := range section.items {
+			report.TotalItems++
+			if item.ClassName == "" {
+				// Reported by the deep gamedata check
+				continue
+			}
+			addIssue := func(kind, problem string) {
+				report.Issues = append(report.Issues, TextIssue{
+					Kind:      kind,
+					Section:   section.name,
+					ID:        item.ID,
+					ClassName: item.ClassName,
+					Problem:   problem,
+				})
+			}
+
+			if !codes[item.ClassName] {
+				report.MissingProducts++
+				addIssue(TextMissingProduct, "no product data entry")
+			}
+			blank := false
+			if keys := textKeys(section.prefixes, item.ClassName, "_name"); !hasText(keys) {
+				report.MissingNames++
+				blank = true
+				addIssue(TextMissingName, "no name text "+strings.Join(keys, " or "))
+			}
+			if keys := textKeys(section.prefixes, item.ClassName, "_desc"); !hasText(keys) {
+				report.MissingDescriptions++
+				blank = true
+				addIssue(TextMissingDescription, "no description text "+strings.Join(keys, " or "))
+			}
+			if blank {
+				report.BlankTooltips = append(report.BlankTooltips, item.ClassName)
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// textKeys returns the ExternalTexts.json keys that may hold a text of className,
+// e.g. "furni_chair_3_name" for "chair*3".
+func textKeys(prefixes []string, className, suffix string) []string {
+	name := strings.ReplaceAll(className, "*", "_")
+	keys := make([]string, len(prefixes))
+	for i, prefix := range prefixes {
+		keys[i] = prefix + name + suffix
+	}
+	return keys
+}
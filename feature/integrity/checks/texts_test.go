@@ -0,0 +1,53 @@
+package checks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCrossCheckTexts(t *testing.T) {
+	furniture := `{
+		"roomitemtypes": {"furnitype": [
+			{"id": 1, "classname": "chair"},
+			{"id": 2, "classname": "chair*3"},
+			{"id": 3, "classname": "table"}
+		]},
+		"wallitemtypes": {"furnitype": [
+			{"id": 4, "classname": "poster"},
+			{"id": 5, "classname": "window"}
+		]}
+	}`
+	products := `{"productdata": {"product": [
+		{"code": "chair"}, {"code": "chair*3"}, {"code": "poster"}, {"code": "window"}
+	]}}`
+	texts := `{
+		"furni_chair_name": "Chair", "furni_chair_desc": "A chair",
+		"furni_chair_3_name": "Red Chair", "furni_chair_3_desc": " ",
+		"wallitem_poster_name": "Poster", "wallitem_poster_desc": "A poster",
+		"furni_window_name": "Window", "furni_window_desc": "A window"
+	}`
+
+	report, err := CrossCheckTexts([]byte(furniture), []byte(products), []byte(texts))
+	require.NoError(t, err)
+	assert.Equal(t, 5, report.TotalItems)
+	assert.Equal(t, 1, report.MissingProducts)
+	assert.Equal(t, 1, report.MissingNames)
+	assert.Equal(t, 2, report.MissingDescriptions)
+	assert.Equal(t, []string{"chair*3", "table"}, report.BlankTooltips)
+
+	require.Len(t, report.Issues, 4)
+	assert.Equal(t, TextIssue{
+		Kind:      TextMissingDescription,
+		Section:   "roomitemtypes",
+		ID:        2,
+		ClassName: "chair*3",
+		Problem:   "no description text furni_chair_3_desc",
+	}, report.Issues[0])
+	assert.Equal(t, TextMissingProduct, report.Issues[1].Kind)
+	assert.Equal(t, 3, report.Issues[1].ID)
+
+	_, err = CrossCheckTexts([]byte(furniture), []byte(products), []byte(`[]`))
+	assert.Error(t, err)
+}
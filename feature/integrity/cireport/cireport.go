@@ -0,0 +1,221 @@
+package cireport
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+
+	"asset-manager/core/json"
+)
+
+const (
+	// FormatText keeps the regular log output.
+	FormatText = "text"
+	// FormatJUnit writes JUnit XML.
+	FormatJUnit = "junit"
+	// FormatSARIF writes SARIF 2.1.0 JSON.
+	FormatSARIF = "sarif"
+)
+
+// ToolName is the tool reported in SARIF output.
+const ToolName = "asset-manager"
+
+// ParseFormat validates an output format name.
+func ParseFormat(format string) (string, error) {
+	switch format {
+	case "", FormatText:
+		return FormatText, nil
+	case FormatJUnit, FormatSARIF:
+		return format, nil
+	}
+	return "", fmt.Errorf("unknown format %q: must be %s, %s or %s", format, FormatText, FormatJUnit, FormatSARIF)
+}
+
+// Rule is one category of finding, e.g. "storage_missing".
+type Rule struct {
+	ID          string
+	Description string
+}
+
+// Finding is one problem of one item.
+type Finding struct {
+	// Rule is the ID of the rule the finding belongs to.
+	Rule string
+	// Target identifies the affected item, e.g. "4021 (chair)".
+	Target string
+	// Message describes the problem.
+	Message string
+}
+
+// Report is the outcome of one integrity check.
+type Report struct {
+	// Name names the check, e.g. "integrity furniture".
+	Name string
+	// Rules lists every rule the check evaluates, including rules without findings.
+	Rules    []Rule
+	Findings []Finding
+}
+
+// Write renders r in format to w. FormatText writes nothing.
+func Write(w io.Writer, format string, r Report) error {
+	switch format {
+	case FormatJUnit:
+		return WriteJUnit(w, r)
+	case FormatSARIF:
+		return WriteSARIF(w, r)
+	case FormatText, "":
+		return nil
+	}
+	return fmt.Errorf("unknown format %q", format)
+}
+
+// byRule groups findings by rule ID.
+func (r Report) byRule() map[string][]Finding {
+	grouped := make(map[string][]Finding, len(r.Rules))
+	for _, f := range r.Findings {
+		grouped[f.Rule] = append(grouped[f.Rule], f)
+	}
+	return grouped
+}
+
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// WriteJUnit renders r as JUnit XML: one test suite per rule and one failed test
+// case per finding. Rules without findings report a single passing test case.
+func WriteJUnit(w io.Writer, r Report) error {
+	grouped := r.byRule()
+	doc := junitSuites{Name: r.Name}
+	for _, rule := range r.Rules {
+		suite := junitSuite{Name: rule.ID}
+		findings := grouped[rule.ID]
+		if len(findings) == 0 {
+			suite.Cases = []junitCase{{Name: rule.ID, ClassName: rule.ID}}
+		}
+		for _, f := range findings {
+			suite.Cases = append(suite.Cases, junitCase{
+				Name:      f.Target,
+				ClassName: rule.ID,
+				Failure:   &junitFailure{Message: f.Message, Type: rule.ID, Text: rule.Description},
+			})
+		}
+		suite.Tests = len(suite.Cases)
+		suite.Failures = len(findings)
+		doc.Tests += suite.Tests
+		doc.Failures += suite.Failures
+		doc.Suites = append(doc.Suites, suite)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("failed to encode JUnit report: %w", err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name  string      `json:"name"`
+	Rules []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+}
+
+// WriteSARIF renders r as a SARIF 2.1.0 log with one error result per finding.
+func WriteSARIF(w io.Writer, r Report) error {
+	run := sarifRun{
+		Tool:    sarifTool{Driver: sarifDriver{Name: ToolName, Rules: make([]sarifRule, 0, len(r.Rules))}},
+		Results: make([]sarifResult, 0, len(r.Findings)),
+	}
+	index := make(map[string]int, len(r.Rules))
+	for i, rule := range r.Rules {
+		index[rule.ID] = i
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{ID: rule.ID, ShortDescription: sarifMessage{Text: rule.Description}})
+	}
+	for _, f := range r.Findings {
+		run.Results = append(run.Results, sarifResult{
+			RuleID:    f.Rule,
+			RuleIndex: index[f.Rule],
+			Level:     "error",
+			Message:   sarifMessage{Text: f.Target + ": " + f.Message},
+			Locations: []sarifLocation{{LogicalLocations: []sarifLogicalLocation{{FullyQualifiedName: f.Target}}}},
+		})
+	}
+
+	data, err := json.MarshalIndent(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode SARIF report: %w", err)
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
@@ -0,0 +1,116 @@
+package cireport
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+
+	"asset-manager/core/json"
+	"asset-manager/feature/furniture/models"
+	"asset-manager/feature/integrity/checks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testReport() Report {
+	return Furniture([]models.FurnitureIssue{
+		{ID: "1", Name: "chair", StorageMissing: true, DBMissing: true},
+		{ID: "2", Mismatch: []string{"name mismatch", "width mismatch"}},
+	})
+}
+
+func TestParseFormat(t *testing.T) {
+	for in, want := range map[string]string{"": "text", "text": "text", "junit": "junit", "sarif": "sarif"} {
+		got, err := ParseFormat(in)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+	_, err := ParseFormat("xml")
+	assert.ErrorContains(t, err, `unknown format "xml"`)
+}
+
+func TestWriteJUnit(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, FormatJUnit, testReport()))
+
+	var doc junitSuites
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+	assert.Equal(t, "integrity furniture", doc.Name)
+	assert.Equal(t, 4, doc.Tests)
+	assert.Equal(t, 3, doc.Failures)
+	require.Len(t, doc.Suites, 4)
+
+	// A rule without findings is one passing case
+	assert.Equal(t, "gamedata_missing", doc.Suites[0].Name)
+	require.Len(t, doc.Suites[0].Cases, 1)
+	assert.Nil(t, doc.Suites[0].Cases[0].Failure)
+
+	assert.Equal(t, "1 (chair)", doc.Suites[1].Cases[0].Name)
+	require.NotNil(t, doc.Suites[3].Cases[0].Failure)
+	assert.Equal(t, "name mismatch; width mismatch", doc.Suites[3].Cases[0].Failure.Message)
+}
+
+func TestWriteSARIF(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, FormatSARIF, testReport()))
+
+	var doc sarifLog
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &doc))
+	assert.Equal(t, "2.1.0", doc.Version)
+	require.Len(t, doc.Runs, 1)
+	assert.Len(t, doc.Runs[0].Tool.Driver.Rules, 4)
+	require.Len(t, doc.Runs[0].Results, 3)
+
+	result := doc.Runs[0].Results[1]
+	assert.Equal(t, "db_missing", result.RuleID)
+	assert.Equal(t, 2, result.RuleIndex)
+	assert.Equal(t, "1 (chair)", result.Locations[0].LogicalLocations[0].FullyQualifiedName)
+}
+
+func TestWrite_Text(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, FormatText, testReport()))
+	assert.Empty(t, buf.String())
+}
+
+func TestGamedata(t *testing.T) {
+	r := Gamedata(&checks.GamedataDeepReport{Issues: []checks.GamedataIssue{
+		{Section: "roomitemtypes", ID: 1, ClassName: "chair", Problem: "duplicate id (first defined in roomitemtypes)"},
+		{Section: "roomitemtypes", ID: 2, ClassName: "sofa", Problem: "duplicate classname (first defined by id 1)"},
+		{Section: "wallitemtypes", ID: 3, ClassName: "a*x", Problem: `invalid color index "x": must be numeric`},
+		{Section: "wallitemtypes", ID: 4, Problem: "missing classname"},
+	}})
+	require.Len(t, r.Findings, 4)
+	assert.Equal(t, "duplicate_id", r.Findings[0].Rule)
+	assert.Equal(t, "duplicate_classname", r.Findings[1].Rule)
+	assert.Equal(t, "invalid_color", r.Findings[2].Rule)
+	assert.Equal(t, "missing_field", r.Findings[3].Rule)
+	assert.Equal(t, "wallitemtypes/4", r.Findings[3].Target)
+}
+
+func TestFigure(t *testing.T) {
+	r := Figure(&checks.FigureDataReport{Issues: []checks.FigureIssue{
+		{Kind: checks.FigureBrokenPalette, SetType: "hr", Problem: "palette 9 is not defined"},
+		{Kind: checks.FigureMissingAsset, SetType: "ch", SetID: 210, Problem: "missing clothing assets for parts ch:210 (in no library)"},
+	}})
+	require.Len(t, r.Rules, 3)
+	require.Len(t, r.Findings, 2)
+	assert.Equal(t, Finding{Rule: "broken_palette", Target: "hr", Message: "palette 9 is not defined"}, r.Findings[0])
+	assert.Equal(t, "ch/210", r.Findings[1].Target)
+}
+
+func TestTexts(t *testing.T) {
+	r := Texts(&checks.TextsReport{Issues: []checks.TextIssue{
+		{Kind: checks.TextMissingName, Section: "roomitemtypes", ID: 2, ClassName: "chair*3", Problem: "no name text furni_chair_3_name"},
+	}})
+	require.Len(t, r.Rules, 3)
+	require.Len(t, r.Findings, 1)
+	assert.Equal(t, Finding{Rule: "missing_name", Target: "roomitemtypes/2 (chair*3)", Message: "no name text furni_chair_3_name"}, r.Findings[0])
+}
+
+func TestCatalog(t *testing.T) {
+	r := Catalog(&checks.CatalogReport{Broken: []checks.BrokenCatalogPage{{ID: 5, Caption: "Shop", Missing: []string{"icon_1.png"}}}})
+	require.Len(t, r.Findings, 1)
+	assert.Equal(t, Finding{Rule: "catalog_image_missing", Target: "page 5 (Shop)", Message: "missing icon_1.png"}, r.Findings[0])
+}
@@ -0,0 +1,13 @@
+// Package cireport renders integrity check findings in formats CI systems display
+// natively, so integrity failures show up next to regular test results.
+//
+// # Formats
+//
+//   - junit: JUnit XML. Every rule is a test suite and every finding a failed test case;
+//     a rule without findings becomes one passing test case.
+//   - sarif: SARIF 2.1.0, as read by GitHub code scanning and most CI dashboards. Every
+//     finding is a result whose logical location is the affected item.
+//
+// The check commands build a Report from their own results (see Furniture, Gamedata
+// and Catalog) and write it to stdout with Write; logs stay on stderr.
+package cireport
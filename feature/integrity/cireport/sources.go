@@ -0,0 +1,168 @@
+package cireport
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"asset-manager/feature/badges"
+	"asset-manager/feature/furniture/models"
+	"asset-manager/feature/integrity/checks"
+)
+
+// Furniture builds the report of "integrity furniture" from its issue list.
+// An item missing from several sources fails once per source.
+func Furniture(issues []models.FurnitureIssue) Report {
+	r := Report{
+		Name: "integrity furniture",
+		Rules: []Rule{
+			{ID: "gamedata_missing", Description: "Item is missing from FurnitureData.json"},
+			{ID: "storage_missing", Description: "Item has no .nitro file in storage"},
+			{ID: "db_missing", Description: "Item is missing from the furniture table"},
+			{ID: "mismatch", Description: "Item fields differ between gamedata and the database"},
+		},
+	}
+	for _, issue := range issues {
+		target := issue.ID
+		if issue.Name != "" {
+			target = fmt.Sprintf("%s (%s)", issue.ID, issue.Name)
+		}
+		add := func(rule, message string) {
+			r.Findings = append(r.Findings, Finding{Rule: rule, Target: target, Message: message})
+		}
+		if issue.GamedataMissing {
+			add("gamedata_missing", "missing from gamedata")
+		}
+		if issue.StorageMissing {
+			add("storage_missing", "missing from storage")
+		}
+		if issue.DBMissing {
+			add("db_missing", "missing from database")
+		}
+		if len(issue.Mismatch) > 0 {
+			add("mismatch", strings.Join(issue.Mismatch, "; "))
+		}
+	}
+	return r
+}
+
+// Gamedata builds the report of "integrity gamedata --deep", using the same
+// categories as the report counters.
+func Gamedata(report *checks.GamedataDeepReport) Report {
+	r := Report{
+		Name: "integrity gamedata",
+		Rules: []Rule{
+			{ID: "duplicate_id", Description: "Furniture ID is defined more than once"},
+			{ID: "duplicate_classname", Description: "Classname is defined more than once"},
+			{ID: "invalid_color", Description: "Classname color index or part color is invalid"},
+			{ID: "missing_field", Description: "Required field is missing"},
+		},
+	}
+	for _, issue := range report.Issues {
+		target := strconv.Itoa(issue.ID)
+		if issue.ClassName != "" {
+			target = fmt.Sprintf("%d (%s)", issue.ID, issue.ClassName)
+		}
+		r.Findings = append(r.Findings, Finding{
+			Rule:    gamedataRule(issue.Problem),
+			Target:  issue.Section + "/" + target,
+			Message: issue.Problem,
+		})
+	}
+	return r
+}
+
+// gamedataRule maps a gamedata problem to its rule.
+func gamedataRule(problem string) string {
+	switch {
+	case strings.HasPrefix(problem, "duplicate id"):
+		return "duplicate_id"
+	case strings.HasPrefix(problem, "duplicate classname"):
+		return "duplicate_classname"
+	case strings.HasPrefix(problem, "invalid"):
+		return "invalid_color"
+	}
+	return "missing_field"
+}
+
+// Figure builds the report of "integrity gamedata --figure" with one finding per issue.
+func Figure(report *checks.FigureDataReport) Report {
+	r := Report{
+		Name: "integrity figuredata",
+		Rules: []Rule{
+			{ID: checks.FigureBrokenPalette, Description: "Set type references an undefined palette"},
+			{ID: checks.FigureDuplicateSet, Description: "Set ID is defined more than once in its set type"},
+			{ID: checks.FigureMissingAsset, Description: "Set part has no clothing bundle in storage"},
+		},
+	}
+	for _, issue := range report.Issues {
+		target := issue.SetType
+		if issue.SetID != 0 {
+			target = fmt.Sprintf("%s/%d", issue.SetType, issue.SetID)
+		}
+		r.Findings = append(r.Findings, Finding{Rule: issue.Kind, Target: target, Message: issue.Problem})
+	}
+	return r
+}
+
+// Texts builds the report of "integrity gamedata --texts" with one finding per issue.
+func Texts(report *checks.TextsReport) Report {
+	r := Report{
+		Name: "integrity texts",
+		Rules: []Rule{
+			{ID: checks.TextMissingProduct, Description: "Item has no ProductData.json entry"},
+			{ID: checks.TextMissingName, Description: "Item has no name in ExternalTexts.json"},
+			{ID: checks.TextMissingDescription, Description: "Item has no description in ExternalTexts.json"},
+		},
+	}
+	for _, issue := range report.Issues {
+		r.Findings = append(r.Findings, Finding{
+			Rule:    issue.Kind,
+			Target:  fmt.Sprintf("%s/%d (%s)", issue.Section, issue.ID, issue.ClassName),
+			Message: issue.Problem,
+		})
+	}
+	return r
+}
+
+// Catalog builds the report of "integrity catalog" with one finding per broken page.
+func Catalog(report *checks.CatalogReport) Report {
+	r := Report{
+		Name:  "integrity catalog",
+		Rules: []Rule{{ID: "catalog_image_missing", Description: "Catalog page references an image missing from storage"}},
+	}
+	for _, page := range report.Broken {
+		r.Findings = append(r.Findings, Finding{
+			Rule:    "catalog_image_missing",
+			Target:  fmt.Sprintf("page %d (%s)", page.ID, page.Caption),
+			Message: "missing " + strings.Join(page.Missing, ", "),
+		})
+	}
+	return r
+}
+
+// Badges builds the report of "integrity badges".
+func Badges(report *badges.Report) Report {
+	r := Report{
+		Name: "integrity badges",
+		Rules: []Rule{
+			{ID: "badge_image_missing", Description: "Defined badge has no image in storage"},
+			{ID: "badge_image_orphaned", Description: "Badge image belongs to no defined badge"},
+		},
+	}
+	for _, badge := range report.Missing {
+		r.Findings = append(r.Findings, Finding{
+			Rule:    "badge_image_missing",
+			Target:  badge.Code,
+			Message: "no image, defined in " + strings.Join(badge.Sources, ", "),
+		})
+	}
+	for _, badge := range report.Orphaned {
+		r.Findings = append(r.Findings, Finding{
+			Rule:    "badge_image_orphaned",
+			Target:  badge.Key,
+			Message: "badge " + badge.Code + " is not defined",
+		})
+	}
+	return r
+}
@@ -7,6 +7,10 @@
 //
 //   - Structure: Checks if the required directory structure exists in the storage bucket (e.g., /gamedata, /bundled).
 //   - GameData: Verifies the presence of key configuration files like FurnitureData.json and FigureData.json.
+//   - FigureData: Parses FigureData.json and reports broken palette references, duplicate sets and
+//     sets whose parts resolve through FigureMap.json to no clothing bundle.
+//   - Texts: Cross-checks FurnitureData.json against ProductData.json and ExternalTexts.json and
+//     reports the items that show blank tooltips to players.
 //   - Bundled: Checks for the existence of bundled asset directories (e.g., /bundled/furniture, /bundled/clothing).
 //   - Server: Validates that the connected database schema matches the expected emulator definition (columns, types).
 //   - Furniture: Triggers the furniture reconciliation process (delegates to furniture package/reconcile engine).
@@ -16,6 +20,8 @@
 //   - GET /integrity : Runs all checks.
 //   - GET /integrity/structure : Runs structure check (supports ?fix=true).
 //   - GET /integrity/gamedata : Runs gamedata check.
+//   - GET /integrity/gamedata/figure : Runs the structural FigureData.json check.
+//   - GET /integrity/gamedata/texts : Runs the product data and texts cross-check.
 //   - GET /integrity/bundled : Runs bundle check (supports ?fix=true).
 //   - GET /integrity/server : Runs server schema check.
 package integrity
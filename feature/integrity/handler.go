@@ -1,7 +1,15 @@
 package integrity
 
 import (
+	"errors"
+	"strconv"
+
+	"asset-manager/core/confirm"
 	"asset-manager/core/logger"
+	"asset-manager/core/reconcile"
+	furnitureIntegrity "asset-manager/feature/furniture/integrity"
+	"asset-manager/feature/furniture/models"
+	furnitureReconcile "asset-manager/feature/furniture/reconcile"
 	"asset-manager/feature/integrity/checks"
 
 	"github.com/gofiber/fiber/v2"
@@ -17,6 +25,8 @@ type Handler struct {
 func NewHandler(service *Service) *Handler {
 	// Force import for Swagger
 	var _ = checks.ServerReport{}
+	var _ = reconcile.HealthReport{}
+	var _ = models.QuickReport{}
 	return &Handler{service: service}
 }
 
@@ -27,13 +37,23 @@ func (h *Handler) RegisterRoutes(app fiber.Router) {
 	group.Get("/structure", h.HandleStructureCheck)
 	group.Get("/bundled", h.HandleBundleCheck)
 	group.Get("/gamedata", h.HandleGameDataCheck)
+	group.Get("/gamedata/figure", h.HandleFigureDataCheck)
+	group.Get("/gamedata/texts", h.HandleTextsCheck)
 	group.Get("/furniture", h.HandleFurnitureCheck)
+	group.Get("/furniture/quick", h.HandleFurnitureQuickCheck)
+	group.Get("/furniture/latest", h.HandleLatestFurnitureReport)
 	group.Get("/server", h.HandleServerCheck)
+	group.Get("/catalog", h.HandleCatalogCheck)
+	group.Get("/health", h.HandleHealthCheck)
+
+	app.Get("/reconcile/history", h.HandleRunHistory)
+	app.Get("/reconcile/diff", h.HandleRunDiff)
+	app.Get("/reconcile/plans/:id/status", h.HandlePlanStatus)
 }
 
 // HandleIntegrityCheck triggers all integrity checks.
 // @Summary Run All Integrity Checks
-// @Description Performs all available integrity checks (Structure, Bundled, GameData, Furniture, Server). This operation may take a long time.
+// @Description Performs all available integrity checks (Structure, Bundled, GameData, FigureData, Texts, Furniture, Server, and Catalog when a database is configured). This operation may take a long time.
 // @Tags integrity
 // @Accept json
 // @Produce json
@@ -41,10 +61,10 @@ func (h *Handler) RegisterRoutes(app fiber.Router) {
 // @Failure 500 {object} map[string]string "Internal Server Error"
 // @Router /integrity [get]
 func (h *Handler) HandleIntegrityCheck(c *fiber.Ctx) error {
-	l := logger.WithRayID(h.service.logger, c)
+	ctx, runID := reconcile.StartRun(c.Context())
+	c.Set(reconcile.RunIDHeader, runID)
+	l := logger.WithRayID(h.service.logger, c).With(zap.String("run_id", runID))
 	l.Info("Triggering all integrity checks")
-
-	ctx := c.Context()
 	report := make(map[string]any)
 
 	// Structure
@@ -62,10 +82,24 @@ func (h *Handler) HandleIntegrityCheck(c *fiber.Ctx) error {
 	}
 
 	// GameData
-	if missing, err := h.service.CheckGameData(ctx); err != nil {
+	if gamedata, err := h.service.CheckGameData(ctx); err != nil {
 		report["gamedata"] = map[string]any{"status": "error", "error": err.Error()}
 	} else {
-		report["gamedata"] = map[string]any{"status": "ok", "missing": missing}
+		report["gamedata"] = map[string]any{"status": "ok", "missing": gamedata.Missing, "files": gamedata.Files}
+	}
+
+	// FigureData structure and clothing assets
+	if figure, err := h.service.CheckFigureData(ctx); err != nil {
+		report["figuredata"] = map[string]any{"status": "error", "error": err.Error()}
+	} else {
+		report["figuredata"] = figure
+	}
+
+	// Furniture names and descriptions
+	if texts, err := h.service.CheckTexts(ctx); err != nil {
+		report["texts"] = map[string]any{"status": "error", "error": err.Error()}
+	} else {
+		report["texts"] = texts
 	}
 
 	// Server
@@ -75,6 +109,15 @@ func (h *Handler) HandleIntegrityCheck(c *fiber.Ctx) error {
 		report["server"] = srvReport
 	}
 
+	// Catalog (needs the database)
+	if h.service.db != nil {
+		if catReport, err := h.service.CheckCatalog(ctx); err != nil {
+			report["catalog"] = map[string]any{"status": "error", "error": err.Error()}
+		} else {
+			report["catalog"] = catReport
+		}
+	}
+
 	// Furniture (Slow)
 	if furnReport, err := h.service.CheckFurniture(ctx, false); err != nil {
 		report["furniture"] = map[string]any{"status": "error", "error": err.Error()}
@@ -87,17 +130,26 @@ func (h *Handler) HandleIntegrityCheck(c *fiber.Ctx) error {
 
 // HandleStructureCheck checks and optionally fixes structure.
 // @Summary Check Structure
-// @Description Checks if the required folder structure exists in the storage bucket. Optionally fixes missing folders.
+// @Description Checks if the required folder structure exists in the storage bucket. Optionally fixes missing folders, or with dry_run=true lists the placeholder objects a fix would create.
 // @Tags integrity
 // @Accept json
 // @Produce json
 // @Param fix query boolean false "Fix missing folders"
+// @Param dry_run query boolean false "Report the placeholder objects a fix would create without writing them"
+// @Param keep query boolean false "Create folder/.keep objects instead of zero-byte folder objects (required in prefix folder mode)"
+// @Param confirm query string false "Confirmation token of a reviewed fix (when SERVER_MUTATIONS_REQUIRE_CONFIRMATION is set)"
 // @Success 200 {object} map[string]any "Structure Report"
+// @Failure 400 {object} map[string]string "Fix requires keep in prefix folder mode"
+// @Failure 403 {object} map[string]string "Unknown or already used confirmation token"
+// @Failure 409 {object} map[string]string "Another reconcile holds the run lock, or the plan changed since the token was issued"
+// @Failure 410 {object} map[string]string "Confirmation token expired"
 // @Failure 500 {object} map[string]string "Internal Server Error"
 // @Router /integrity/structure [get]
 func (h *Handler) HandleStructureCheck(c *fiber.Ctx) error {
 	l := logger.WithRayID(h.service.logger, c)
 	fix := c.Query("fix") == "true"
+	dryRun := c.QueryBool("dry_run")
+	keep := c.QueryBool("keep")
 
 	missing, err := h.service.CheckStructure(c.Context())
 	if err != nil {
@@ -108,14 +160,32 @@ func (h *Handler) HandleStructureCheck(c *fiber.Ctx) error {
 	if len(missing) > 0 {
 		l.Warn("Missing folders detected", zap.Strings("missing", missing))
 
+		if dryRun {
+			placeholders, err := h.service.PlanStructureFix(missing, keep)
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error(), "missing": missing})
+			}
+			return c.JSON(fiber.Map{
+				"status":       "dry_run",
+				"missing":      missing,
+				"would_create": placeholders,
+			})
+		}
+
 		if fix {
+			if store := confirm.Required(); store != nil {
+				placeholders, err := h.service.PlanStructureFix(missing, keep)
+				if err != nil {
+					return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error(), "missing": missing})
+				}
+				if confirmed, err := awaitConfirmation(c, l, store, "integrity/structure", missing, placeholders); !confirmed {
+					return err
+				}
+			}
+
 			l.Info("Attempting to fix missing folders")
-			if err := h.service.FixStructure(c.Context(), missing); err != nil {
-				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-					"error":   "Failed to fix structure",
-					"details": err.Error(),
-					"missing": missing,
-				})
+			if err := h.service.FixStructure(c.Context(), missing, keep); err != nil {
+				return folderFixError(c, "Failed to fix structure", missing, err)
 			}
 			return c.JSON(fiber.Map{
 				"status": "fixed",
@@ -132,17 +202,26 @@ func (h *Handler) HandleStructureCheck(c *fiber.Ctx) error {
 
 // HandleBundleCheck checks and optionally fixes bundled folders.
 // @Summary Check Bundled Folders
-// @Description Checks if the required bundled asset folders exist. Optionally fixes missing folders.
+// @Description Checks if the required bundled asset folders exist. Optionally fixes missing folders, or with dry_run=true lists the placeholder objects a fix would create.
 // @Tags integrity
 // @Accept json
 // @Produce json
 // @Param fix query boolean false "Fix missing folders"
+// @Param dry_run query boolean false "Report the placeholder objects a fix would create without writing them"
+// @Param keep query boolean false "Create folder/.keep objects instead of zero-byte folder objects (required in prefix folder mode)"
+// @Param confirm query string false "Confirmation token of a reviewed fix (when SERVER_MUTATIONS_REQUIRE_CONFIRMATION is set)"
 // @Success 200 {object} map[string]any "Bundle Report"
+// @Failure 400 {object} map[string]string "Fix requires keep in prefix folder mode"
+// @Failure 403 {object} map[string]string "Unknown or already used confirmation token"
+// @Failure 409 {object} map[string]string "Another reconcile holds the run lock, or the plan changed since the token was issued"
+// @Failure 410 {object} map[string]string "Confirmation token expired"
 // @Failure 500 {object} map[string]string "Internal Server Error"
 // @Router /integrity/bundled [get]
 func (h *Handler) HandleBundleCheck(c *fiber.Ctx) error {
 	l := logger.WithRayID(h.service.logger, c)
 	fix := c.Query("fix") == "true"
+	dryRun := c.QueryBool("dry_run")
+	keep := c.QueryBool("keep")
 
 	missing, err := h.service.CheckBundled(c.Context())
 	if err != nil {
@@ -153,14 +232,32 @@ func (h *Handler) HandleBundleCheck(c *fiber.Ctx) error {
 	if len(missing) > 0 {
 		l.Warn("Missing bundled folders detected", zap.Strings("missing", missing))
 
+		if dryRun {
+			placeholders, err := h.service.PlanBundledFix(missing, keep)
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error(), "missing": missing})
+			}
+			return c.JSON(fiber.Map{
+				"status":       "dry_run",
+				"missing":      missing,
+				"would_create": placeholders,
+			})
+		}
+
 		if fix {
+			if store := confirm.Required(); store != nil {
+				placeholders, err := h.service.PlanBundledFix(missing, keep)
+				if err != nil {
+					return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error(), "missing": missing})
+				}
+				if confirmed, err := awaitConfirmation(c, l, store, "integrity/bundled", missing, placeholders); !confirmed {
+					return err
+				}
+			}
+
 			l.Info("Attempting to fix missing bundled folders")
-			if err := h.service.FixBundled(c.Context(), missing); err != nil {
-				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-					"error":   "Failed to fix bundled folders",
-					"details": err.Error(),
-					"missing": missing,
-				})
+			if err := h.service.FixBundled(c.Context(), missing, keep); err != nil {
+				return folderFixError(c, "Failed to fix bundled folders", missing, err)
 			}
 			return c.JSON(fiber.Map{
 				"status": "fixed",
@@ -175,30 +272,118 @@ func (h *Handler) HandleBundleCheck(c *fiber.Ctx) error {
 	})
 }
 
+// awaitConfirmation gates a folder fix behind a confirmation token (see confirm.Await),
+// reporting the missing folders and the placeholders the fix would create.
+func awaitConfirmation(c *fiber.Ctx, l *zap.Logger, store *confirm.Store, scope string, missing []string, plan []checks.Placeholder) (bool, error) {
+	return confirm.Await(c, l, store, scope, plan, fiber.Map{"missing": missing, "would_create": plan})
+}
+
+// folderFixError answers a failed folder fix with its HTTP status. A fix refused by
+// the run lock answers 409 with the lock holder.
+func folderFixError(c *fiber.Ctx, message string, missing []string, err error) error {
+	var locked *reconcile.LockedError
+	if errors.As(err, &locked) {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error":   err.Error(),
+			"missing": missing,
+		})
+	}
+	status := fiber.StatusInternalServerError
+	if errors.Is(err, checks.ErrKeepRequired) {
+		status = fiber.StatusBadRequest
+	}
+	return c.Status(status).JSON(fiber.Map{
+		"error":   message,
+		"details": err.Error(),
+		"missing": missing,
+	})
+}
+
 // HandleGameDataCheck checks gamedata files.
 // @Summary Check GameData
-// @Description Verify that all required GameData JSON files are present.
+// @Description Verify that all required GameData JSON files are present and report each file's size and last-modified time, flagging files that are stale or suspiciously small. With deep=true, validates FurnitureData.json internally instead (duplicate IDs, duplicate classnames, invalid color variants, missing required fields).
 // @Tags integrity
 // @Accept json
 // @Produce json
+// @Param deep query boolean false "Run gamedata-internal validations on FurnitureData.json"
 // @Success 200 {object} map[string]any "GameData Report"
 // @Failure 500 {object} map[string]string "Internal Server Error"
 // @Router /integrity/gamedata [get]
 func (h *Handler) HandleGameDataCheck(c *fiber.Ctx) error {
 	l := logger.WithRayID(h.service.logger, c)
 
-	missing, err := h.service.CheckGameData(c.Context())
+	if c.QueryBool("deep") {
+		report, err := h.service.CheckGameDataDeep(c.Context())
+		if err != nil {
+			l.Error("Deep GameData check failed", zap.Error(err))
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.JSON(report)
+	}
+
+	report, err := h.service.CheckGameData(c.Context())
 	if err != nil {
 		l.Error("GameData check failed", zap.Error(err))
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
+	if report.Warnings > 0 {
+		l.Warn("Stale or truncated gamedata files detected", zap.Int("files", report.Warnings))
+	}
 
 	return c.JSON(fiber.Map{
-		"status":  "checked",
-		"missing": missing,
+		"status":   "checked",
+		"missing":  report.Missing,
+		"files":    report.Files,
+		"warnings": report.Warnings,
 	})
 }
 
+// HandleFigureDataCheck validates FigureData.json structurally.
+// @Summary Check FigureData
+// @Description Parse FigureData.json and report set types referencing undefined palettes, duplicate set IDs, and sets whose parts resolve through FigureMap.json to no clothing bundle under bundled/figure. The asset check is skipped (assets_checked=false) when there is no FigureMap.json.
+// @Tags integrity
+// @Accept json
+// @Produce json
+// @Success 200 {object} checks.FigureDataReport
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /integrity/gamedata/figure [get]
+func (h *Handler) HandleFigureDataCheck(c *fiber.Ctx) error {
+	l := logger.WithRayID(h.service.logger, c)
+
+	report, err := h.service.CheckFigureData(c.Context())
+	if err != nil {
+		l.Error("FigureData check failed", zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	if len(report.Issues) > 0 {
+		l.Warn("FigureData issues detected", zap.Int("issues", len(report.Issues)))
+	}
+	return c.JSON(report)
+}
+
+// HandleTextsCheck cross-checks FurnitureData.json against ProductData.json and ExternalTexts.json.
+// @Summary Check Furniture Texts
+// @Description Report FurnitureData.json classnames without a ProductData.json entry or without furni_<classname>_name and _desc keys (wallitem_ for wall items) in ExternalTexts.json. blank_tooltips lists the items that show blank tooltips to players.
+// @Tags integrity
+// @Accept json
+// @Produce json
+// @Success 200 {object} checks.TextsReport
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /integrity/gamedata/texts [get]
+func (h *Handler) HandleTextsCheck(c *fiber.Ctx) error {
+	l := logger.WithRayID(h.service.logger, c)
+
+	report, err := h.service.CheckTexts(c.Context())
+	if err != nil {
+		l.Error("Texts check failed", zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	if len(report.BlankTooltips) > 0 {
+		l.Warn("Furniture with blank tooltips detected", zap.Int("items", len(report.BlankTooltips)))
+	}
+	return c.JSON(report)
+}
+
 // HandleFurnitureCheck checks integrity of bundled furniture assets.
 // @Summary Check Furniture Assets
 // @Description Perform deep integrity check on furniture assets in storage.
@@ -210,11 +395,13 @@ func (h *Handler) HandleGameDataCheck(c *fiber.Ctx) error {
 // @Failure 500 {object} map[string]string "Internal Server Error"
 // @Router /integrity/furniture [get]
 func (h *Handler) HandleFurnitureCheck(c *fiber.Ctx) error {
-	l := logger.WithRayID(h.service.logger, c)
+	ctx, runID := reconcile.StartRun(c.Context())
+	c.Set(reconcile.RunIDHeader, runID)
+	l := logger.WithRayID(h.service.logger, c).With(zap.String("run_id", runID))
 	l.Info("Starting furniture integrity check")
 
 	checkDB := c.Query("db") == "true"
-	report, err := h.service.CheckFurniture(c.Context(), checkDB)
+	report, err := h.service.CheckFurniture(ctx, checkDB)
 	if err != nil {
 		l.Error("Furniture check failed", zap.Error(err))
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -229,6 +416,84 @@ func (h *Handler) HandleFurnitureCheck(c *fiber.Ctx) error {
 	return c.JSON(report)
 }
 
+// HandleLatestFurnitureReport returns the report of the latest scheduled furniture check.
+// @Summary Latest Scheduled Furniture Report
+// @Description Returns the stored report of the latest scheduled furniture integrity check (SCHEDULER_INTEGRITY_SCHEDULE) without running a new one. Reports are kept in the local state store.
+// @Tags integrity
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.Report "Furniture Report"
+// @Failure 404 {object} map[string]string "No scheduled check has stored a report yet"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Failure 503 {object} map[string]string "State store disabled"
+// @Router /integrity/furniture/latest [get]
+func (h *Handler) HandleLatestFurnitureReport(c *fiber.Ctx) error {
+	l := logger.WithRayID(h.service.logger, c)
+
+	report, err := h.service.LatestFurnitureReport(c.Context())
+	if errors.Is(err, furnitureIntegrity.ErrNoReportStore) {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	if err != nil {
+		l.Error("Failed to load the latest furniture report", zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	if report == nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "no scheduled furniture check has run yet",
+		})
+	}
+
+	return c.JSON(report)
+}
+
+// HandleFurnitureQuickCheck compares furniture counts across sources.
+// @Summary Quick Furniture Drift Check
+// @Description Compares furniture counts in the database, FurnitureData.json and storage without a full reconcile, and sets alert when the drift between the largest and smallest count exceeds threshold percent. Cheap enough for 1-minute monitoring intervals.
+// @Tags integrity
+// @Accept json
+// @Produce json
+// @Param threshold query number false "Drift threshold in percent (default 1)"
+// @Success 200 {object} models.QuickReport "Quick Report"
+// @Failure 400 {object} map[string]string "Invalid threshold"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /integrity/furniture/quick [get]
+func (h *Handler) HandleFurnitureQuickCheck(c *fiber.Ctx) error {
+	l := logger.WithRayID(h.service.logger, c)
+
+	threshold := furnitureIntegrity.DefaultDriftThreshold
+	if raw := c.Query("threshold"); raw != "" {
+		parsed, err := strconv.ParseFloat(raw, 64)
+		if err != nil || parsed < 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "threshold must be a non-negative number",
+			})
+		}
+		threshold = parsed
+	}
+
+	report, err := h.service.QuickCheckFurniture(c.Context(), threshold)
+	if err != nil {
+		l.Error("Furniture quick check failed", zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	if report.Alert {
+		l.Warn("Furniture count drift above threshold",
+			zap.Any("counts", report.Counts),
+			zap.Float64("drift_percent", report.DriftPercent),
+			zap.Float64("threshold", report.Threshold))
+	}
+
+	return c.JSON(report)
+}
+
 // HandleServerCheck checks server schema integrity.
 // @Summary Check Server Schema
 // @Description Checks if the emulator database schema matches the expected models.
@@ -252,3 +517,191 @@ func (h *Handler) HandleServerCheck(c *fiber.Ctx) error {
 
 	return c.JSON(report)
 }
+
+// HandleCatalogCheck checks that catalog page icons and images exist.
+// @Summary Check Catalog Page Images
+// @Description Reads the emulator catalog_pages table and reports shop pages whose icon (icon_<icon_image>.png) or headline, teaser and special images are missing under the configured catalog images prefix.
+// @Tags integrity
+// @Accept json
+// @Produce json
+// @Success 200 {object} checks.CatalogReport "Catalog Report"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /integrity/catalog [get]
+func (h *Handler) HandleCatalogCheck(c *fiber.Ctx) error {
+	l := logger.WithRayID(h.service.logger, c)
+	l.Info("Starting catalog page image check")
+
+	report, err := h.service.CheckCatalog(c.Context())
+	if err != nil {
+		l.Error("Catalog check failed", zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	if len(report.Broken) > 0 {
+		l.Warn("Broken catalog pages detected", zap.Int("pages", len(report.Broken)))
+	}
+
+	return c.JSON(report)
+}
+
+// HandleHealthCheck returns the combined health score across all reconcile domains.
+// @Summary Combined Health Score
+// @Description Reconciles every registered domain (or reads its cached indices) and returns one hotel health score (0-100) with per-domain contributions. Domains are weighted by entity count; an entity is healthy when present in every source without mismatches.
+// @Tags integrity
+// @Accept json
+// @Produce json
+// @Success 200 {object} reconcile.HealthReport "Health Report"
+// @Router /integrity/health [get]
+func (h *Handler) HandleHealthCheck(c *fiber.Ctx) error {
+	l := logger.WithRayID(h.service.logger, c)
+
+	report := h.service.CheckHealth(c.Context())
+	for _, domain := range report.Domains {
+		if domain.Error != "" {
+			l.Warn("Health check failed for domain", zap.String("adapter", domain.Adapter), zap.String("error", domain.Error))
+		}
+	}
+
+	return c.JSON(report)
+}
+
+// defaultHistoryLimit is the number of runs GET /reconcile/history returns by default.
+const defaultHistoryLimit = 30
+
+// HandleRunHistory lists the stored reconcile runs of an adapter.
+// @Summary Reconcile Run History
+// @Description Lists the stored full reconcile runs of an adapter, newest first, with their summaries. Every full reconcile is stored in the local state store, keeping STATE_RUNS_KEEP runs per adapter.
+// @Tags reconcile
+// @Accept json
+// @Produce json
+// @Param adapter query string false "Adapter name (default furniture)"
+// @Param limit query int false "Maximum runs to return (default 30)"
+// @Success 200 {array} reconcile.Run "Runs"
+// @Failure 400 {object} map[string]string "Invalid limit"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Failure 503 {object} map[string]string "State store disabled"
+// @Router /reconcile/history [get]
+func (h *Handler) HandleRunHistory(c *fiber.Ctx) error {
+	l := logger.WithRayID(h.service.logger, c)
+	adapter := c.Query("adapter", furnitureReconcile.AdapterName)
+
+	limit := defaultHistoryLimit
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "limit must be a positive integer",
+			})
+		}
+		limit = parsed
+	}
+
+	runs, err := h.service.RunHistory(c.Context(), adapter, limit)
+	if errors.Is(err, reconcile.ErrNoRunStore) {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	if err != nil {
+		l.Error("Failed to list reconcile runs", zap.String("adapter", adapter), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	if runs == nil {
+		runs = []reconcile.Run{}
+	}
+
+	return c.JSON(runs)
+}
+
+// HandleRunDiff compares two stored reconcile runs.
+// @Summary Diff Reconcile Runs
+// @Description Compares two stored reconcile runs of an adapter and lists the entities that broke, were fixed or whose issues changed in between. from and to take a run ID, an RFC 3339 time or a YYYY-MM-DD date (UTC); times and dates select the latest run finished by then.
+// @Tags reconcile
+// @Accept json
+// @Produce json
+// @Param adapter query string false "Adapter name (default furniture)"
+// @Param from query string true "Earlier run: ID, RFC 3339 time or YYYY-MM-DD"
+// @Param to query string true "Later run: ID, RFC 3339 time or YYYY-MM-DD"
+// @Success 200 {object} reconcile.RunDiff "Run Diff"
+// @Failure 400 {object} map[string]string "Missing or invalid run reference"
+// @Failure 404 {object} map[string]string "No run matches a reference"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Failure 503 {object} map[string]string "State store disabled"
+// @Router /reconcile/diff [get]
+func (h *Handler) HandleRunDiff(c *fiber.Ctx) error {
+	l := logger.WithRayID(h.service.logger, c)
+	adapter := c.Query("adapter", furnitureReconcile.AdapterName)
+	from, to := c.Query("from"), c.Query("to")
+	if from == "" || to == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "from and to are required",
+		})
+	}
+
+	diff, err := h.service.DiffRuns(c.Context(), adapter, from, to)
+	if err != nil {
+		status := runDiffStatus(err)
+		if status == fiber.StatusInternalServerError {
+			l.Error("Failed to diff reconcile runs", zap.String("adapter", adapter), zap.Error(err))
+		}
+		return c.Status(status).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(diff)
+}
+
+// HandlePlanStatus reports where an applied plan stopped.
+// @Summary Applied Plan Status
+// @Description Returns the stored outcome of an applied plan: its state (applied, partial or failed), the actions that ran, the actions that failed with their errors and the actions never reached. Statuses are kept in the local state store, STATE_RUNS_KEEP per adapter.
+// @Tags reconcile
+// @Accept json
+// @Produce json
+// @Param id path string true "Plan ID"
+// @Success 200 {object} reconcile.PlanStatus "Plan Status"
+// @Failure 404 {object} map[string]string "No applied plan has the ID"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Failure 503 {object} map[string]string "State store disabled"
+// @Router /reconcile/plans/{id}/status [get]
+func (h *Handler) HandlePlanStatus(c *fiber.Ctx) error {
+	l := logger.WithRayID(h.service.logger, c)
+	id := c.Params("id")
+
+	status, err := h.service.PlanStatus(c.Context(), id)
+	switch {
+	case errors.Is(err, reconcile.ErrNoPlanStatusStore):
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, reconcile.ErrPlanStatusNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case err != nil:
+		l.Error("Failed to load plan status", zap.String("plan_id", id), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(status)
+}
+
+// runDiffStatus maps a run diff error to its HTTP status.
+func runDiffStatus(err error) int {
+	switch {
+	case errors.Is(err, reconcile.ErrNoRunStore):
+		return fiber.StatusServiceUnavailable
+	case errors.Is(err, reconcile.ErrInvalidRunRef):
+		return fiber.StatusBadRequest
+	case errors.Is(err, reconcile.ErrRunNotFound):
+		return fiber.StatusNotFound
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
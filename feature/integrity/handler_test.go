@@ -1,11 +1,22 @@
 package integrity
 
 import (
+	"context"
 	"encoding/json"
+	"io"
+	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
+	"asset-manager/core/confirm"
+	"asset-manager/core/reconcile"
+	"asset-manager/core/storage"
 	"asset-manager/core/storage/mocks"
+	furnitureIntegrity "asset-manager/feature/furniture/integrity"
+	"asset-manager/feature/furniture/models"
+	"asset-manager/feature/integrity/checks"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/gofiber/fiber/v2"
@@ -21,7 +32,7 @@ func setupTestApp(t *testing.T) (*fiber.App, *mocks.Client, sqlmock.Sqlmock) {
 	mockClient := new(mocks.Client)
 	db, sqlMock := setupMockDB(t)
 	logger := zap.NewNop()
-	svc := NewService(mockClient, "test-bucket", logger, db, "arcturus")
+	svc := NewService(mockClient, storage.SingleBucket("test-bucket"), storage.Layout{}, logger, db, "arcturus")
 	handler := NewHandler(svc)
 	handler.RegisterRoutes(app)
 	return app, mockClient, sqlMock
@@ -47,6 +58,99 @@ func TestHandleStructureCheck(t *testing.T) {
 	assert.NotEmpty(t, body["missing"])
 }
 
+func TestHandleStructureCheck_DryRun(t *testing.T) {
+	app, mockClient, _ := setupTestApp(t)
+
+	mockClient.On("BucketExists", mock.Anything, "test-bucket").Return(true, nil)
+	ch := make(chan minio.ObjectInfo)
+	close(ch)
+	mockClient.On("ListObjects", mock.Anything, "test-bucket", mock.Anything).Return((<-chan minio.ObjectInfo)(ch))
+
+	req := httptest.NewRequest("GET", "/integrity/structure?fix=true&dry_run=true", nil)
+	resp, err := app.Test(req)
+
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	mockClient.AssertNotCalled(t, "PutObject", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	var body struct {
+		Status      string               `json:"status"`
+		WouldCreate []checks.Placeholder `json:"would_create"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "dry_run", body.Status)
+	require.Len(t, body.WouldCreate, len(checks.RequiredFolders))
+	assert.Equal(t, checks.Placeholder{Folder: "bundled", Bucket: "test-bucket", Key: "bundled/"}, body.WouldCreate[0])
+}
+
+func TestHandleStructureCheck_Confirmation(t *testing.T) {
+	confirm.SetStore(confirm.NewStore(time.Minute))
+	defer confirm.SetStore(nil)
+
+	app, mockClient, _ := setupTestApp(t)
+	mockClient.On("BucketExists", mock.Anything, "test-bucket").Return(true, nil)
+	ch := make(chan minio.ObjectInfo)
+	close(ch)
+	mockClient.On("ListObjects", mock.Anything, "test-bucket", mock.Anything).Return((<-chan minio.ObjectInfo)(ch))
+	mockLock(mockClient)
+	mockClient.On("PutObject", mock.Anything, "test-bucket", mock.Anything, mock.Anything, int64(0), mock.Anything).
+		Return(minio.UploadInfo{}, nil)
+
+	// The first request only returns the plan and a token
+	resp, err := app.Test(httptest.NewRequest("GET", "/integrity/structure?fix=true", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	mockClient.AssertNotCalled(t, "PutObject", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	var body struct {
+		Status      string               `json:"status"`
+		WouldCreate []checks.Placeholder `json:"would_create"`
+		Token       string               `json:"confirmation_token"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "confirmation_required", body.Status)
+	assert.Len(t, body.WouldCreate, len(checks.RequiredFolders))
+	require.NotEmpty(t, body.Token)
+
+	// A token issued for another fix is refused
+	resp, err = app.Test(httptest.NewRequest("GET", "/integrity/structure?fix=true&confirm=bogus", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 403, resp.StatusCode)
+
+	// Echoing the token applies the fix, once, under the run lock
+	resp, err = app.Test(httptest.NewRequest("GET", "/integrity/structure?fix=true&confirm="+body.Token, nil))
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	mockClient.AssertNumberOfCalls(t, "PutObject", len(checks.RequiredFolders)+1)
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/integrity/structure?fix=true&confirm="+body.Token, nil))
+	require.NoError(t, err)
+	assert.Equal(t, 403, resp.StatusCode)
+}
+
+// TestHandleFolderFix_Locked tests that structure and bundled fixes answer 409 with
+// the lock holder while another process holds the run lock.
+func TestHandleFolderFix_Locked(t *testing.T) {
+	app, mockClient, _ := setupTestApp(t)
+	mockClient.On("BucketExists", mock.Anything, "test-bucket").Return(true, nil)
+	ch := make(chan minio.ObjectInfo)
+	close(ch)
+	mockClient.On("ListObjects", mock.Anything, "test-bucket", mock.Anything).Return((<-chan minio.ObjectInfo)(ch))
+	mockHeldLock(mockClient, 2)
+
+	for _, path := range []string{"/integrity/structure?fix=true", "/integrity/bundled?fix=true"} {
+		resp, err := app.Test(httptest.NewRequest("GET", path, nil))
+		require.NoError(t, err)
+		assert.Equal(t, 409, resp.StatusCode, path)
+
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		assert.Contains(t, body["error"], "cli@other-host:42", path)
+		assert.NotEmpty(t, body["missing"], path)
+	}
+	mockClient.AssertNotCalled(t, "PutObject", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestHandleBundleCheck(t *testing.T) {
 	app, mockClient, _ := setupTestApp(t)
 
@@ -77,6 +181,86 @@ func TestHandleGameDataCheck(t *testing.T) {
 	assert.Equal(t, 200, resp.StatusCode)
 }
 
+func TestHandleGameDataCheck_Deep(t *testing.T) {
+	app, mockClient, _ := setupTestApp(t)
+
+	gamedata := `{"roomitemtypes":{"furnitype":[{"id":1,"classname":"chair","name":"Chair","category":"chair"},{"id":1,"classname":"chair","name":"Chair","category":"chair"}]}}`
+	mockClient.On("GetObject", mock.Anything, "test-bucket", "gamedata/FurnitureData.json", mock.Anything).
+		Return(io.NopCloser(strings.NewReader(gamedata)), nil)
+
+	req := httptest.NewRequest("GET", "/integrity/gamedata?deep=true", nil)
+	resp, err := app.Test(req)
+
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var body map[string]any
+	json.NewDecoder(resp.Body).Decode(&body)
+	assert.Equal(t, float64(2), body["total_items"])
+	assert.Equal(t, float64(1), body["duplicate_ids"])
+	assert.Equal(t, float64(1), body["duplicate_classnames"])
+}
+
+func TestHandleFigureDataCheck(t *testing.T) {
+	app, mockClient, _ := setupTestApp(t)
+
+	figure := `{"palettes":[{"id":1}],"setTypes":[{"type":"hr","paletteId":2,"sets":[{"id":1,"parts":[{"id":1,"type":"hr"}]}]}]}`
+	mockClient.On("GetObject", mock.Anything, "test-bucket", "gamedata/FigureData.json", mock.Anything).
+		Return(io.NopCloser(strings.NewReader(figure)), nil)
+	mockClient.On("GetObject", mock.Anything, "test-bucket", "gamedata/FigureMap.json", mock.Anything).
+		Return(io.NopCloser(strings.NewReader(`{"libraries":[{"id":"hh_human_hair","parts":[{"id":1,"type":"hr"}]}]}`)), nil)
+	ch := make(chan minio.ObjectInfo)
+	close(ch)
+	mockClient.On("ListObjects", mock.Anything, "test-bucket", mock.Anything).Return((<-chan minio.ObjectInfo)(ch))
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/integrity/gamedata/figure", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var report checks.FigureDataReport
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&report))
+	assert.True(t, report.AssetsChecked)
+	assert.Equal(t, 1, report.BrokenPalettes)
+	assert.Equal(t, 1, report.MissingAssets)
+}
+
+func TestHandleTextsCheck(t *testing.T) {
+	app, mockClient, _ := setupTestApp(t)
+
+	furniture := `{"roomitemtypes":{"furnitype":[{"id":1,"classname":"chair"},{"id":2,"classname":"table"}]}}`
+	mockClient.On("GetObject", mock.Anything, "test-bucket", "gamedata/FurnitureData.json", mock.Anything).
+		Return(io.NopCloser(strings.NewReader(furniture)), nil)
+	mockClient.On("GetObject", mock.Anything, "test-bucket", "gamedata/ProductData.json", mock.Anything).
+		Return(io.NopCloser(strings.NewReader(`{"productdata":{"product":[{"code":"chair"},{"code":"table"}]}}`)), nil)
+	mockClient.On("GetObject", mock.Anything, "test-bucket", "gamedata/ExternalTexts.json", mock.Anything).
+		Return(io.NopCloser(strings.NewReader(`{"furni_chair_name":"Chair","furni_chair_desc":"A chair"}`)), nil)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/integrity/gamedata/texts", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var report checks.TextsReport
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&report))
+	assert.Equal(t, 2, report.TotalItems)
+	assert.Zero(t, report.MissingProducts)
+	assert.Equal(t, []string{"table"}, report.BlankTooltips)
+}
+
+func TestHandleHealthCheck(t *testing.T) {
+	app, _, _ := setupTestApp(t)
+
+	req := httptest.NewRequest("GET", "/integrity/health", nil)
+	resp, err := app.Test(req)
+
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var body map[string]any
+	json.NewDecoder(resp.Body).Decode(&body)
+	assert.Equal(t, float64(100), body["score"])
+	assert.NotNil(t, body["domains"])
+}
+
 func TestHandleServerCheck(t *testing.T) {
 	app, _, sqlMock := setupTestApp(t)
 
@@ -112,6 +296,8 @@ func TestHandleIntegrityCheck(t *testing.T) {
 	// Furniture check - fail fast
 	mockClient.On("GetObject", mock.Anything, "test-bucket", "gamedata/FurnitureData.json", mock.Anything).
 		Return(nil, assert.AnError)
+	mockClient.On("GetObject", mock.Anything, "test-bucket", "gamedata/FigureData.json", mock.Anything).
+		Return(nil, assert.AnError)
 
 	req := httptest.NewRequest("GET", "/integrity", nil)
 	// Give it more time? Fiber's app.Test timeout defaults to 1s.
@@ -133,3 +319,223 @@ func TestHandleFurnitureCheck(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, 500, resp.StatusCode)
 }
+
+func TestHandleFurnitureQuickCheck(t *testing.T) {
+	t.Run("Drift", func(t *testing.T) {
+		app, mockClient, sqlMock := setupTestApp(t)
+
+		mockClient.On("GetObject", mock.Anything, "test-bucket", "gamedata/FurnitureData.json", mock.Anything).
+			Return(io.NopCloser(strings.NewReader(`{"roomitemtypes":{"furnitype":[{"id":1}]},"wallitemtypes":{"furnitype":[]}}`)), nil)
+		objCh := make(chan minio.ObjectInfo, 1)
+		objCh <- minio.ObjectInfo{Key: "bundled/furniture/chair.nitro"}
+		close(objCh)
+		mockClient.On("ListObjects", mock.Anything, "test-bucket", mock.Anything).Return((<-chan minio.ObjectInfo)(objCh))
+		sqlMock.ExpectQuery("SELECT count").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+
+		req := httptest.NewRequest("GET", "/integrity/furniture/quick?threshold=5", nil)
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		assert.Equal(t, true, body["alert"])
+		assert.Equal(t, 5.0, body["threshold"])
+		assert.Equal(t, 50.0, body["drift_percent"])
+	})
+
+	t.Run("InvalidThreshold", func(t *testing.T) {
+		app, _, _ := setupTestApp(t)
+
+		req := httptest.NewRequest("GET", "/integrity/furniture/quick?threshold=-1", nil)
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, 400, resp.StatusCode)
+	})
+}
+
+// latestReportStore serves one stored furniture report.
+type latestReportStore struct {
+	report *models.Report
+}
+
+func (s *latestReportStore) SaveReport(ctx context.Context, kind string, at time.Time, report any) error {
+	return nil
+}
+
+func (s *latestReportStore) LatestReport(ctx context.Context, kind string, report any) (bool, error) {
+	if s.report == nil {
+		return false, nil
+	}
+	*report.(*models.Report) = *s.report
+	return true, nil
+}
+
+// TestHandleLatestFurnitureReport tests the stored report endpoint with and without a store.
+func TestHandleLatestFurnitureReport(t *testing.T) {
+	app, _, _ := setupTestApp(t)
+	get := func() *http.Response {
+		resp, err := app.Test(httptest.NewRequest("GET", "/integrity/furniture/latest", nil))
+		require.NoError(t, err)
+		return resp
+	}
+
+	// Without the state store
+	assert.Equal(t, 503, get().StatusCode)
+
+	store := &latestReportStore{}
+	furnitureIntegrity.SetReportStore(store)
+	defer furnitureIntegrity.SetReportStore(nil)
+	assert.Equal(t, 404, get().StatusCode)
+
+	store.report = &models.Report{TotalExpected: 3, MissingAssets: []string{"chair.nitro"}}
+	resp := get()
+	assert.Equal(t, 200, resp.StatusCode)
+	var body models.Report
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, 3, body.TotalExpected)
+	assert.Equal(t, []string{"chair.nitro"}, body.MissingAssets)
+}
+
+// memoryRunStore holds reconcile runs in memory.
+type memoryRunStore struct {
+	runs []reconcile.Run
+}
+
+func (s *memoryRunStore) SaveRun(ctx context.Context, run *reconcile.Run) error {
+	run.ID = uint64(len(s.runs) + 1)
+	s.runs = append(s.runs, *run)
+	return nil
+}
+
+func (s *memoryRunStore) ListRuns(ctx context.Context, adapter string, limit int) ([]reconcile.Run, error) {
+	var runs []reconcile.Run
+	for i := len(s.runs) - 1; i >= 0 && len(runs) < limit; i-- {
+		if s.runs[i].Adapter == adapter {
+			runs = append(runs, s.runs[i])
+		}
+	}
+	return runs, nil
+}
+
+func (s *memoryRunStore) LoadRun(ctx context.Context, adapter string, id uint64) (*reconcile.Run, error) {
+	for _, run := range s.runs {
+		if run.Adapter == adapter && run.ID == id {
+			return &run, nil
+		}
+	}
+	return nil, reconcile.ErrRunNotFound
+}
+
+func (s *memoryRunStore) LoadRunAt(ctx context.Context, adapter string, t time.Time) (*reconcile.Run, error) {
+	for i := len(s.runs) - 1; i >= 0; i-- {
+		if s.runs[i].Adapter == adapter && !s.runs[i].Time.After(t) {
+			return &s.runs[i], nil
+		}
+	}
+	return nil, reconcile.ErrRunNotFound
+}
+
+// TestHandleRunHistoryAndDiff tests listing and diffing stored reconcile runs.
+func TestHandleRunHistoryAndDiff(t *testing.T) {
+	app, _, _ := setupTestApp(t)
+	get := func(target string) *http.Response {
+		resp, err := app.Test(httptest.NewRequest("GET", target, nil))
+		require.NoError(t, err)
+		return resp
+	}
+
+	// Without the state store
+	assert.Equal(t, 503, get("/reconcile/history").StatusCode)
+	assert.Equal(t, 503, get("/reconcile/diff?from=1&to=2").StatusCode)
+
+	store := &memoryRunStore{}
+	reconcile.SetRunStore(store)
+	defer reconcile.SetRunStore(nil)
+
+	yesterday := time.Date(2026, 3, 1, 3, 0, 0, 0, time.UTC)
+	require.NoError(t, store.SaveRun(context.Background(), &reconcile.Run{
+		Adapter: "furniture",
+		Time:    yesterday,
+		Items:   []reconcile.RunItem{{Key: "10", Issues: []string{"missing_storage"}}},
+	}))
+	require.NoError(t, store.SaveRun(context.Background(), &reconcile.Run{
+		Adapter: "furniture",
+		Time:    yesterday.AddDate(0, 0, 1),
+		Items:   []reconcile.RunItem{{Key: "20", Name: "chair", Issues: []string{"mismatch: width: gd=2 db=1"}}},
+	}))
+
+	resp := get("/reconcile/history?limit=1")
+	assert.Equal(t, 200, resp.StatusCode)
+	var runs []reconcile.Run
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&runs))
+	require.Len(t, runs, 1)
+	assert.Equal(t, uint64(2), runs[0].ID)
+
+	resp = get("/reconcile/diff?from=2026-03-01&to=2")
+	assert.Equal(t, 200, resp.StatusCode)
+	var diff reconcile.RunDiff
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&diff))
+	require.Len(t, diff.Broken, 1)
+	assert.Equal(t, "20", diff.Broken[0].Key)
+	require.Len(t, diff.Fixed, 1)
+	assert.Equal(t, "10", diff.Fixed[0].Key)
+	assert.Empty(t, diff.Changed)
+
+	assert.Equal(t, 400, get("/reconcile/history?limit=zero").StatusCode)
+	assert.Equal(t, 400, get("/reconcile/diff?from=1").StatusCode)
+	assert.Equal(t, 400, get("/reconcile/diff?from=yesterday&to=2").StatusCode)
+	assert.Equal(t, 404, get("/reconcile/diff?from=1&to=9").StatusCode)
+}
+
+// memoryPlanStatusStore holds plan statuses in memory.
+type memoryPlanStatusStore map[string]reconcile.PlanStatus
+
+func (s memoryPlanStatusStore) SavePlanStatus(ctx context.Context, status *reconcile.PlanStatus) error {
+	s[status.PlanID] = *status
+	return nil
+}
+
+func (s memoryPlanStatusStore) LoadPlanStatus(ctx context.Context, planID string) (*reconcile.PlanStatus, error) {
+	status, ok := s[planID]
+	if !ok {
+		return nil, reconcile.ErrPlanStatusNotFound
+	}
+	return &status, nil
+}
+
+// TestHandlePlanStatus tests reporting where an applied plan stopped.
+func TestHandlePlanStatus(t *testing.T) {
+	app, _, _ := setupTestApp(t)
+	get := func(target string) *http.Response {
+		resp, err := app.Test(httptest.NewRequest("GET", target, nil))
+		require.NoError(t, err)
+		return resp
+	}
+
+	// Without the state store
+	assert.Equal(t, 503, get("/reconcile/plans/plan-1/status").StatusCode)
+
+	store := memoryPlanStatusStore{}
+	reconcile.SetPlanStatusStore(store)
+	defer reconcile.SetPlanStatusStore(nil)
+	require.NoError(t, store.SavePlanStatus(context.Background(), &reconcile.PlanStatus{
+		PlanID:    "plan-1",
+		Adapter:   "furniture",
+		State:     reconcile.PlanPartial,
+		Total:     3,
+		Executed:  []reconcile.Action{{Type: reconcile.ActionDeleteDB, Key: "1"}},
+		Failed:    []reconcile.FailedAction{{Action: reconcile.Action{Type: reconcile.ActionDeleteStorage, Key: "2"}, Error: "access denied"}},
+		Remaining: []reconcile.Action{{Type: reconcile.ActionSyncDB, Key: "3"}},
+	}))
+
+	resp := get("/reconcile/plans/plan-1/status")
+	assert.Equal(t, 200, resp.StatusCode)
+	var body map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "partial", body["state"])
+	assert.Equal(t, []any{map[string]any{"type": "delete_storage", "key": "2", "reason": "", "error": "access denied"}}, body["failed"])
+	assert.Len(t, body["remaining"], 1)
+
+	assert.Equal(t, 404, get("/reconcile/plans/plan-9/status").StatusCode)
+}
@@ -15,8 +15,8 @@ type Feature struct {
 }
 
 // NewFeature creates a new Integrity feature.
-func NewFeature(client storage.Client, bucket string, logger *zap.Logger, db *gorm.DB, emulator string) *Feature {
-	svc := NewService(client, bucket, logger, db, emulator)
+func NewFeature(client storage.Client, buckets storage.Buckets, layout storage.Layout, logger *zap.Logger, db *gorm.DB, emulator string) *Feature {
+	svc := NewService(client, buckets, layout, logger, db, emulator)
 	h := NewHandler(svc)
 	return &Feature{service: svc, handler: h}
 }
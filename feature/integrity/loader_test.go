@@ -1,6 +1,7 @@
 package integrity
 
 import (
+	"asset-manager/core/storage"
 	"asset-manager/core/storage/mocks"
 	"testing"
 
@@ -13,7 +14,7 @@ func TestLoader(t *testing.T) {
 	mockClient := new(mocks.Client)
 	logger := zap.NewNop()
 	// Pass nil db for this test as we don't access it unless we use the service
-	feature := NewFeature(mockClient, "test-bucket", logger, nil, "")
+	feature := NewFeature(mockClient, storage.SingleBucket("test-bucket"), storage.Layout{}, logger, nil, "")
 
 	assert.Equal(t, "integrity", feature.Name())
 	assert.True(t, feature.IsEnabled())
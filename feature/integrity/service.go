@@ -2,7 +2,9 @@ package integrity
 
 import (
 	"context"
+	"fmt"
 
+	"asset-manager/core/reconcile"
 	"asset-manager/core/storage"
 	furnitureIntegrity "asset-manager/feature/furniture/integrity"
 	"asset-manager/feature/furniture/models"
@@ -15,17 +17,20 @@ import (
 // Service handles integrity checks.
 type Service struct {
 	client   storage.Client
-	bucket   string
+	buckets  storage.Buckets
+	layout   storage.Layout
 	logger   *zap.Logger
 	db       *gorm.DB
 	emulator string
 }
 
 // NewService creates a new integrity service.
-func NewService(client storage.Client, bucket string, logger *zap.Logger, db *gorm.DB, emulator string) *Service {
+// An empty layout checks the default folder lists.
+func NewService(client storage.Client, buckets storage.Buckets, layout storage.Layout, logger *zap.Logger, db *gorm.DB, emulator string) *Service {
 	return &Service{
 		client:   client,
-		bucket:   bucket,
+		buckets:  buckets,
+		layout:   layout,
 		logger:   logger,
 		db:       db,
 		emulator: emulator,
@@ -34,27 +39,130 @@ func NewService(client storage.Client, bucket string, logger *zap.Logger, db *go
 
 // CheckStructure returns a list of missing folders.
 func (s *Service) CheckStructure(ctx context.Context) ([]string, error) {
-	return checks.CheckStructure(ctx, s.client, s.bucket)
+	return checks.CheckStructure(ctx, s.client, s.buckets, checks.ResolveFolders(s.layout.Folders, checks.RequiredFolders))
 }
 
-// FixStructure creates the missing folders.
-func (s *Service) FixStructure(ctx context.Context, missing []string) error {
-	return checks.FixStructure(ctx, s.client, s.bucket, s.logger, missing)
+// FixStructure creates the missing folders under the run lock. With keep, .keep
+// markers are written instead of folder objects; prefix folder mode requires keep. It
+// returns a *reconcile.LockedError when another reconcile holds the run lock.
+func (s *Service) FixStructure(ctx context.Context, missing []string, keep bool) error {
+	if err := s.checkFolderFix(keep); err != nil {
+		return err
+	}
+	return s.withRunLock(ctx, func() error {
+		return checks.FixStructure(ctx, s.client, s.buckets, s.logger, missing, keep)
+	})
+}
+
+// PlanStructureFix returns the placeholder objects FixStructure would create, without writing.
+func (s *Service) PlanStructureFix(missing []string, keep bool) ([]checks.Placeholder, error) {
+	if err := s.checkFolderFix(keep); err != nil {
+		return nil, err
+	}
+	return checks.PlanStructure(s.buckets, missing, keep), nil
+}
+
+// CheckGameData reports missing gamedata files and flags stale or truncated ones.
+func (s *Service) CheckGameData(ctx context.Context) (*checks.GameDataReport, error) {
+	return checks.CheckGameData(ctx, s.client, s.buckets.Gamedata, checks.FreshnessPolicy{
+		MaxAge:  s.layout.GamedataMaxAge,
+		MinSize: s.layout.GamedataMinSize,
+	})
 }
 
-// CheckGameData returns a list of missing files in the gamedata folder.
-func (s *Service) CheckGameData(ctx context.Context) ([]string, error) {
-	return checks.CheckGameData(ctx, s.client, s.bucket)
+// CheckGameDataDeep validates FurnitureData.json internally (duplicate IDs and classnames,
+// invalid color variants, missing required fields) without touching the database.
+func (s *Service) CheckGameDataDeep(ctx context.Context) (*checks.GamedataDeepReport, error) {
+	data, err := checks.LoadFurnitureData(ctx, s.client, s.buckets.Gamedata)
+	if err != nil {
+		return nil, err
+	}
+	return checks.ValidateFurnitureData(data)
+}
+
+// CheckFigureData validates FigureData.json structurally (palette references, duplicate
+// sets) and resolves every part through FigureMap.json to a clothing bundle in storage.
+// The asset check is skipped when there is no figure map.
+func (s *Service) CheckFigureData(ctx context.Context) (*checks.FigureDataReport, error) {
+	data, err := checks.LoadFigureData(ctx, s.client, s.buckets.Gamedata)
+	if err != nil {
+		return nil, err
+	}
+	assets, err := checks.LoadFigureAssets(ctx, s.client, s.buckets.Gamedata, s.buckets.Assets)
+	if err != nil {
+		return nil, err
+	}
+	return checks.ValidateFigureData(data, assets)
+}
+
+// CheckTexts cross-checks FurnitureData.json against ProductData.json and
+// ExternalTexts.json, reporting the items whose tooltips show blank to players.
+func (s *Service) CheckTexts(ctx context.Context) (*checks.TextsReport, error) {
+	furniture, err := checks.LoadFurnitureData(ctx, s.client, s.buckets.Gamedata)
+	if err != nil {
+		return nil, err
+	}
+	products, err := checks.LoadProductData(ctx, s.client, s.buckets.Gamedata)
+	if err != nil {
+		return nil, err
+	}
+	texts, err := checks.LoadExternalTexts(ctx, s.client, s.buckets.Gamedata)
+	if err != nil {
+		return nil, err
+	}
+	return checks.CrossCheckTexts(furniture, products, texts)
 }
 
 // CheckBundled returns a list of missing bundled folders.
 func (s *Service) CheckBundled(ctx context.Context) ([]string, error) {
-	return checks.CheckBundled(ctx, s.client, s.bucket)
+	return checks.CheckBundled(ctx, s.client, s.buckets.Assets, checks.ResolveFolders(s.layout.BundledFolders, checks.RequiredBundledFolders))
+}
+
+// FixBundled creates the missing bundled folders under the run lock. See FixStructure
+// for keep and the lock.
+func (s *Service) FixBundled(ctx context.Context, missing []string, keep bool) error {
+	if err := s.checkFolderFix(keep); err != nil {
+		return err
+	}
+	return s.withRunLock(ctx, func() error {
+		return checks.FixBundled(ctx, s.client, s.buckets.Assets, s.logger, missing, keep)
+	})
+}
+
+// PlanBundledFix returns the placeholder objects FixBundled would create, without writing.
+func (s *Service) PlanBundledFix(missing []string, keep bool) ([]checks.Placeholder, error) {
+	if err := s.checkFolderFix(keep); err != nil {
+		return nil, err
+	}
+	return checks.PlanBundled(s.buckets.Assets, missing, keep), nil
+}
+
+// withRunLock runs fix under the run lock, so folder fixes never write while a
+// reconcile or import is changing the bucket.
+func (s *Service) withRunLock(ctx context.Context, fix func() error) (err error) {
+	lock, err := reconcile.AcquireRunLock(ctx, s.client, s.buckets.Assets, reconcile.DefaultLockTTL)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if releaseErr := lock.Release(); releaseErr != nil && err == nil {
+			err = releaseErr
+		}
+	}()
+	return fix()
 }
 
-// FixBundled creates the missing bundled folders.
-func (s *Service) FixBundled(ctx context.Context, missing []string) error {
-	return checks.FixBundled(ctx, s.client, s.bucket, s.logger, missing)
+// checkFolderFix refuses folder fixes that would write placeholders in prefix folder mode.
+func (s *Service) checkFolderFix(keep bool) error {
+	if s.layout.PrefixFolders() && !keep {
+		return checks.ErrKeepRequired
+	}
+	return nil
+}
+
+// CheckCatalog reports catalog pages whose icon or images are missing from storage.
+func (s *Service) CheckCatalog(ctx context.Context) (*checks.CatalogReport, error) {
+	return checks.CheckCatalog(ctx, s.client, s.buckets.Assets, reconcile.ReadDB(s.db), s.layout.CatalogImagesPrefix)
 }
 
 // CheckFurniture performs an integrity check on furniture assets.
@@ -63,7 +171,47 @@ func (s *Service) CheckFurniture(ctx context.Context, checkDB bool) (*models.Rep
 	if checkDB {
 		db = s.db
 	}
-	return furnitureIntegrity.CheckIntegrity(ctx, s.client, s.bucket, db, s.emulator)
+	return furnitureIntegrity.CheckIntegrity(ctx, s.client, s.buckets, db, s.emulator)
+}
+
+// LatestFurnitureReport returns the stored report of the latest scheduled furniture
+// check, or nil when none was stored yet.
+func (s *Service) LatestFurnitureReport(ctx context.Context) (*models.Report, error) {
+	return furnitureIntegrity.LatestReport(ctx)
+}
+
+// RunHistory returns at most limit stored reconcile runs of the adapter, newest first.
+func (s *Service) RunHistory(ctx context.Context, adapter string, limit int) ([]reconcile.Run, error) {
+	return reconcile.RunHistory(ctx, adapter, limit)
+}
+
+// DiffRuns compares the stored reconcile runs of the adapter that from and to name
+// (run IDs, RFC 3339 times or YYYY-MM-DD dates).
+func (s *Service) DiffRuns(ctx context.Context, adapter, from, to string) (*reconcile.RunDiff, error) {
+	fromRun, err := reconcile.FindRun(ctx, adapter, from)
+	if err != nil {
+		return nil, fmt.Errorf("from: %w", err)
+	}
+	toRun, err := reconcile.FindRun(ctx, adapter, to)
+	if err != nil {
+		return nil, fmt.Errorf("to: %w", err)
+	}
+	return reconcile.DiffRuns(fromRun, toRun), nil
+}
+
+// PlanStatus returns the stored outcome of the applied plan with the given ID.
+func (s *Service) PlanStatus(ctx context.Context, id string) (*reconcile.PlanStatus, error) {
+	return reconcile.FindPlanStatus(ctx, id)
+}
+
+// QuickCheckFurniture compares furniture counts across sources and flags drift above threshold percent.
+func (s *Service) QuickCheckFurniture(ctx context.Context, threshold float64) (*models.QuickReport, error) {
+	return furnitureIntegrity.QuickCheck(ctx, s.client, s.buckets, s.db, s.emulator, threshold)
+}
+
+// CheckHealth computes the combined health score across every registered reconcile domain.
+func (s *Service) CheckHealth(ctx context.Context) *reconcile.HealthReport {
+	return reconcile.ComputeHealth(ctx, reconcile.RegisteredSpecs(), s.db, s.client, s.buckets.Assets)
 }
 
 // CheckServer performs an integrity check on the emulator database schema.
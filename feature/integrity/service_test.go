@@ -4,14 +4,20 @@ import (
 	"bytes"
 	"context"
 	"io"
+	"strings"
 	"testing"
+	"time"
 
+	"asset-manager/core/reconcile"
+	"asset-manager/core/storage"
 	"asset-manager/core/storage/mocks"
+	"asset-manager/feature/integrity/checks"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/minio/minio-go/v7"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
@@ -37,10 +43,29 @@ func setupMockDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
 	return gormDB, mock
 }
 
+// mockLock lets the run lock be acquired and released on test-bucket.
+func mockLock(mockClient *mocks.Client) {
+	mockClient.On("GetObject", mock.Anything, "test-bucket", reconcile.LockObject, mock.Anything).
+		Return(io.ReadCloser(nil), minio.ErrorResponse{Code: "NoSuchKey"})
+	mockClient.On("PutObject", mock.Anything, "test-bucket", reconcile.LockObject, mock.Anything, mock.Anything, mock.Anything).
+		Return(minio.UploadInfo{}, nil)
+	mockClient.On("RemoveObject", mock.Anything, "test-bucket", reconcile.LockObject, mock.Anything).Return(nil).Maybe()
+}
+
+// mockHeldLock makes the run lock of test-bucket held by another process for the
+// next reads of it.
+func mockHeldLock(mockClient *mocks.Client, reads int) {
+	held := `{"owner":"cli@other-host:42","started_at":"2026-01-01T00:00:00Z","expires_at":"` + time.Now().Add(time.Hour).UTC().Format(time.RFC3339) + `"}`
+	for range reads {
+		mockClient.On("GetObject", mock.Anything, "test-bucket", reconcile.LockObject, mock.Anything).
+			Return(io.NopCloser(strings.NewReader(held)), nil).Once()
+	}
+}
+
 func TestService_Structure(t *testing.T) {
 	mockClient := new(mocks.Client)
 	logger := zap.NewNop()
-	svc := NewService(mockClient, "test-bucket", logger, nil, "")
+	svc := NewService(mockClient, storage.SingleBucket("test-bucket"), storage.Layout{}, logger, nil, "")
 
 	t.Run("CheckStructure", func(t *testing.T) {
 		mockClient.On("BucketExists", mock.Anything, "test-bucket").Return(true, nil)
@@ -56,8 +81,9 @@ func TestService_Structure(t *testing.T) {
 	})
 
 	t.Run("FixStructure", func(t *testing.T) {
+		mockLock(mockClient)
 		mockClient.On("PutObject", mock.Anything, "test-bucket", mock.Anything, mock.Anything, int64(0), mock.Anything).Return(minio.UploadInfo{}, nil)
-		err := svc.FixStructure(context.Background(), []string{"bundled"})
+		err := svc.FixStructure(context.Background(), []string{"bundled"}, false)
 		assert.NoError(t, err)
 	})
 }
@@ -65,22 +91,22 @@ func TestService_Structure(t *testing.T) {
 func TestService_GameData(t *testing.T) {
 	mockClient := new(mocks.Client)
 	logger := zap.NewNop()
-	svc := NewService(mockClient, "test-bucket", logger, nil, "")
+	svc := NewService(mockClient, storage.SingleBucket("test-bucket"), storage.Layout{}, logger, nil, "")
 
 	mockClient.On("BucketExists", mock.Anything, "test-bucket").Return(true, nil)
 	ch := make(chan minio.ObjectInfo)
 	close(ch)
 	mockClient.On("ListObjects", mock.Anything, "test-bucket", mock.Anything).Return((<-chan minio.ObjectInfo)(ch))
 
-	missing, err := svc.CheckGameData(context.Background())
+	report, err := svc.CheckGameData(context.Background())
 	assert.NoError(t, err)
-	assert.NotEmpty(t, missing)
+	assert.NotEmpty(t, report.Missing)
 }
 
 func TestService_Bundled(t *testing.T) {
 	mockClient := new(mocks.Client)
 	logger := zap.NewNop()
-	svc := NewService(mockClient, "test-bucket", logger, nil, "")
+	svc := NewService(mockClient, storage.SingleBucket("test-bucket"), storage.Layout{}, logger, nil, "")
 
 	t.Run("CheckBundled", func(t *testing.T) {
 		mockClient.On("BucketExists", mock.Anything, "test-bucket").Return(true, nil)
@@ -94,12 +120,61 @@ func TestService_Bundled(t *testing.T) {
 	})
 
 	t.Run("FixBundled", func(t *testing.T) {
+		mockLock(mockClient)
 		mockClient.On("PutObject", mock.Anything, "test-bucket", mock.Anything, mock.Anything, int64(0), mock.Anything).Return(minio.UploadInfo{}, nil)
-		err := svc.FixBundled(context.Background(), []string{"bundled/furni"})
+		err := svc.FixBundled(context.Background(), []string{"bundled/furni"}, false)
 		assert.NoError(t, err)
+		mockClient.AssertCalled(t, "PutObject", mock.Anything, "test-bucket", reconcile.LockObject, mock.Anything, mock.Anything, mock.Anything)
 	})
 }
 
+// TestService_FixLocked tests that folder fixes write nothing while another process
+// holds the run lock.
+func TestService_FixLocked(t *testing.T) {
+	mockClient := new(mocks.Client)
+	mockHeldLock(mockClient, 2)
+	svc := NewService(mockClient, storage.SingleBucket("test-bucket"), storage.Layout{}, zap.NewNop(), nil, "")
+
+	var locked *reconcile.LockedError
+	err := svc.FixStructure(context.Background(), []string{"sounds"}, false)
+	require.ErrorAs(t, err, &locked)
+	assert.Equal(t, "cli@other-host:42", locked.Holder.Owner)
+	assert.ErrorAs(t, svc.FixBundled(context.Background(), []string{"bundled/pet"}, false), &locked)
+	mockClient.AssertNotCalled(t, "PutObject", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestService_CustomLayout(t *testing.T) {
+	mockClient := new(mocks.Client)
+	layout := storage.Layout{BundledFolders: []string{"bundled/furniture", " bundled/games/ ", ""}}
+	svc := NewService(mockClient, storage.SingleBucket("test-bucket"), layout, zap.NewNop(), nil, "")
+
+	mockClient.On("BucketExists", mock.Anything, "test-bucket").Return(true, nil)
+	ch := make(chan minio.ObjectInfo)
+	close(ch)
+	mockClient.On("ListObjects", mock.Anything, "test-bucket", mock.Anything).Return((<-chan minio.ObjectInfo)(ch))
+
+	missing, err := svc.CheckBundled(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"bundled/furniture", "bundled/games"}, missing)
+}
+
+func TestService_PrefixFolderMode(t *testing.T) {
+	mockClient := new(mocks.Client)
+	layout := storage.Layout{FolderMode: storage.FolderModePrefix}
+	svc := NewService(mockClient, storage.SingleBucket("test-bucket"), layout, zap.NewNop(), nil, "")
+
+	err := svc.FixStructure(context.Background(), []string{"sounds"}, false)
+	assert.ErrorIs(t, err, checks.ErrKeepRequired)
+	_, err = svc.PlanBundledFix([]string{"bundled/pet"}, false)
+	assert.ErrorIs(t, err, checks.ErrKeepRequired)
+	mockClient.AssertNotCalled(t, "PutObject", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	mockLock(mockClient)
+	mockClient.On("PutObject", mock.Anything, "test-bucket", "sounds/.keep", mock.Anything, int64(0), mock.Anything).Return(minio.UploadInfo{}, nil)
+	assert.NoError(t, svc.FixStructure(context.Background(), []string{"sounds"}, true))
+	mockClient.AssertExpectations(t)
+}
+
 func TestService_Furniture(t *testing.T) {
 	// Mock valid JSON
 	mockJSON := `{"roomitemtypes":{"furnitype":[]},"wallitemtypes":{"furnitype":[]}}`
@@ -107,7 +182,7 @@ func TestService_Furniture(t *testing.T) {
 	t.Run("Failure", func(t *testing.T) {
 		mockClient := new(mocks.Client)
 		logger := zap.NewNop()
-		svc := NewService(mockClient, "test-bucket", logger, nil, "")
+		svc := NewService(mockClient, storage.SingleBucket("test-bucket"), storage.Layout{}, logger, nil, "")
 
 		mockClient.On("BucketExists", mock.Anything, "test-bucket").Return(false, nil).Once()
 		report, err := svc.CheckFurniture(context.Background(), false)
@@ -118,7 +193,7 @@ func TestService_Furniture(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		mockClient := new(mocks.Client)
 		logger := zap.NewNop()
-		svc := NewService(mockClient, "test-bucket", logger, nil, "")
+		svc := NewService(mockClient, storage.SingleBucket("test-bucket"), storage.Layout{}, logger, nil, "")
 
 		// Mock BucketExists
 		mockClient.On("BucketExists", mock.Anything, "test-bucket").Return(true, nil)
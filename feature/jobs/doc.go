@@ -0,0 +1,11 @@
+// Package jobs exposes the status and results of background jobs over HTTP.
+//
+// Long operations such as POST /reconcile/furniture answer 202 Accepted with a job
+// right away; the caller then polls GET /jobs/:id until its status is succeeded or
+// failed, and fetches the result from the location in its result field.
+//
+// # HTTP Endpoints
+//
+//   - GET /jobs/:id : Status, stage and progress percent of a job.
+//   - GET /jobs/:id/result : Result of a succeeded job.
+package jobs
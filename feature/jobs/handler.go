@@ -0,0 +1,69 @@
+package jobs
+
+import (
+	"asset-manager/core/jobs"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Handler handles HTTP requests for background jobs.
+type Handler struct {
+	service *Service
+}
+
+// NewHandler creates a new HTTP handler.
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// RegisterRoutes registers the job routes.
+func (h *Handler) RegisterRoutes(app fiber.Router) {
+	group := app.Group("/jobs")
+	group.Get("/:id", h.HandleGetJob)
+	group.Get("/:id/result", h.HandleGetJobResult)
+}
+
+// HandleGetJob returns the status of a background job.
+// @Summary Get Job
+// @Description Get the status (queued, running, succeeded, failed), stage and progress percent of a background job. Once it succeeded, result holds the path of its result. Finished jobs are kept for JOBS_RETENTION.
+// @Tags jobs
+// @Produce json
+// @Param id path string true "Job ID"
+// @Success 200 {object} jobs.Job "Job"
+// @Failure 404 {object} map[string]string "Job not found"
+// @Router /jobs/{id} [get]
+func (h *Handler) HandleGetJob(c *fiber.Ctx) error {
+	job, ok := h.service.Get(c.Params("id"))
+	if !ok {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "job not found",
+		})
+	}
+	return c.JSON(job)
+}
+
+// HandleGetJobResult returns the result of a succeeded background job.
+// @Summary Get Job Result
+// @Description Get the result of a succeeded background job, e.g. the furniture report of POST /reconcile/furniture.
+// @Tags jobs
+// @Produce json
+// @Param id path string true "Job ID"
+// @Success 200 {object} map[string]any "Job result"
+// @Failure 404 {object} map[string]string "Job not found"
+// @Failure 409 {object} map[string]string "Job has not succeeded"
+// @Router /jobs/{id}/result [get]
+func (h *Handler) HandleGetJobResult(c *fiber.Ctx) error {
+	job, result, ok := h.service.Result(c.Params("id"))
+	if !ok {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "job not found",
+		})
+	}
+	if job.Status != jobs.StatusSucceeded {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error":  "job has not succeeded",
+			"status": job.Status,
+		})
+	}
+	return c.JSON(result)
+}
@@ -0,0 +1,82 @@
+package jobs
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"asset-manager/core/jobs"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// newTestApp registers a fresh job manager and returns an app serving the job routes.
+func newTestApp(t *testing.T) (*fiber.App, *jobs.Manager) {
+	manager := jobs.NewManager(jobs.Config{Retention: time.Hour, MaxRunning: 1})
+	jobs.SetManager(manager)
+	t.Cleanup(func() {
+		jobs.SetManager(nil)
+		manager.Stop()
+	})
+
+	feature := NewFeature(zap.NewNop())
+	require.True(t, feature.IsEnabled())
+	app := fiber.New()
+	require.NoError(t, feature.Load(app))
+	return app, manager
+}
+
+func get(t *testing.T, app *fiber.App, path string) (int, string) {
+	resp, err := app.Test(httptest.NewRequest("GET", path, nil))
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, string(body)
+}
+
+func TestHandler_HandleGetJob(t *testing.T) {
+	app, manager := newTestApp(t)
+
+	status, _ := get(t, app, "/jobs/unknown")
+	assert.Equal(t, 404, status)
+
+	release := make(chan struct{})
+	job := manager.Submit("test", func(ctx context.Context, progress jobs.ProgressFunc) (any, error) {
+		progress("results", 50)
+		<-release
+		return map[string]int{"total": 3}, nil
+	})
+
+	require.Eventually(t, func() bool {
+		_, body := get(t, app, "/jobs/"+job.ID)
+		return strings.Contains(body, `"progress":50`)
+	}, time.Second, time.Millisecond)
+	status, body := get(t, app, "/jobs/"+job.ID)
+	assert.Equal(t, 200, status)
+	assert.Contains(t, body, `"status":"running"`)
+	assert.NotContains(t, body, `"result"`)
+
+	status, body = get(t, app, "/jobs/"+job.ID+"/result")
+	assert.Equal(t, 409, status)
+	assert.Contains(t, body, `"status":"running"`)
+
+	close(release)
+	require.Eventually(t, func() bool {
+		got, _ := manager.Get(job.ID)
+		return got.Status == jobs.StatusSucceeded
+	}, time.Second, time.Millisecond)
+
+	status, body = get(t, app, "/jobs/"+job.ID)
+	assert.Equal(t, 200, status)
+	assert.Contains(t, body, `"result":"/jobs/`+job.ID+`/result"`)
+
+	status, body = get(t, app, "/jobs/"+job.ID+"/result")
+	assert.Equal(t, 200, status)
+	assert.JSONEq(t, `{"total": 3}`, body)
+}
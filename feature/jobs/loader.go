@@ -0,0 +1,37 @@
+package jobs
+
+import (
+	"asset-manager/core/jobs"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// Feature implements the loader.Feature interface.
+type Feature struct {
+	service *Service
+	handler *Handler
+}
+
+// NewFeature creates a new Jobs feature.
+func NewFeature(logger *zap.Logger) *Feature {
+	svc := NewService(logger)
+	h := NewHandler(svc)
+	return &Feature{service: svc, handler: h}
+}
+
+// Name returns the name of the feature.
+func (f *Feature) Name() string {
+	return "jobs"
+}
+
+// IsEnabled reports whether a job manager is registered.
+func (f *Feature) IsEnabled() bool {
+	return jobs.Current() != nil
+}
+
+// Load registers the feature's routes.
+func (f *Feature) Load(app fiber.Router) error {
+	f.handler.RegisterRoutes(app)
+	return nil
+}
@@ -0,0 +1,50 @@
+package jobs
+
+import (
+	"asset-manager/core/jobs"
+
+	"go.uber.org/zap"
+)
+
+// Service reads background jobs from the registered manager.
+type Service struct {
+	logger *zap.Logger
+}
+
+// NewService creates a new jobs service.
+func NewService(logger *zap.Logger) *Service {
+	return &Service{logger: logger}
+}
+
+// Get returns the job with id, with its result location once it succeeded.
+func (s *Service) Get(id string) (jobs.Job, bool) {
+	manager := jobs.Current()
+	if manager == nil {
+		return jobs.Job{}, false
+	}
+	job, ok := manager.Get(id)
+	return withLocation(job), ok
+}
+
+// Result returns the job with id and its result, which is nil until it succeeded.
+func (s *Service) Result(id string) (jobs.Job, any, bool) {
+	manager := jobs.Current()
+	if manager == nil {
+		return jobs.Job{}, nil, false
+	}
+	job, result, ok := manager.Result(id)
+	return withLocation(job), result, ok
+}
+
+// ResultPath returns the route serving the result of the job with id.
+func ResultPath(id string) string {
+	return "/jobs/" + id + "/result"
+}
+
+// withLocation sets the result location of a succeeded job.
+func withLocation(job jobs.Job) jobs.Job {
+	if job.Status == jobs.StatusSucceeded {
+		job.Result = ResultPath(job.ID)
+	}
+	return job
+}
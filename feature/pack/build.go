@@ -0,0 +1,167 @@
+package pack
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"asset-manager/core/json"
+	"asset-manager/core/reconcile"
+	"asset-manager/core/storage"
+	"asset-manager/core/utils"
+	furnitureAdp "asset-manager/feature/furniture/reconcile"
+
+	"gorm.io/gorm"
+)
+
+// Build exports the furniture items with the given IDs into a pack written to w.
+// Items are selected by gamedata ID and database sprite ID; IDs found in neither are
+// reported as warnings, as are assets missing from storage. Build fails when none of
+// the IDs match.
+func Build(ctx context.Context, client storage.Client, buckets storage.Buckets, db *gorm.DB, emulator string, ids []int, w io.Writer) (*Manifest, error) {
+	if db == nil {
+		return nil, fmt.Errorf("pack build requires a database connection")
+	}
+
+	profile := furnitureAdp.GetProfileByName(emulator)
+	p := &Pack{
+		Manifest: Manifest{
+			Version:   FormatVersion,
+			CreatedAt: time.Now().UTC(),
+			Emulator:  canonicalEmulator(emulator),
+			Table:     profile.TableName,
+			Items:     []Item{},
+			Assets:    []string{},
+		},
+		Gamedata: map[string][]map[string]any{},
+		Rows:     []map[string]any{},
+		Assets:   map[string][]byte{},
+	}
+	selected := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		selected[id] = true
+	}
+	found := make(map[int]bool, len(ids))
+
+	// Gamedata fragments
+	doc, _, err := readGamedata(ctx, client, buckets.Gamedata)
+	if err != nil {
+		return nil, err
+	}
+	classnames := make(map[string]bool)
+	furnitureAdp.EachGamedataItem(doc, func(section string, item map[string]any) {
+		id, ok := intField(item, "id")
+		if !ok || !selected[id] {
+			return
+		}
+		classname, _ := item["classname"].(string)
+		p.Gamedata[section] = append(p.Gamedata[section], item)
+		p.Manifest.Items = append(p.Manifest.Items, Item{ID: id, Classname: classname, Section: section})
+		found[id] = true
+		if classname != "" {
+			classnames[furnitureAdp.BaseClassname(classname)] = true
+		}
+	})
+
+	// Database rows, without the primary key so the target assigns its own
+	idCol, spriteCol, nameCol := profile.Columns[furnitureAdp.ColID], profile.Columns[furnitureAdp.ColSpriteID], profile.Columns[furnitureAdp.ColItemName]
+	var rows []map[string]any
+	err = reconcile.ReadDB(db).WithContext(ctx).
+		Table(profile.TableName).
+		Where(spriteCol+" IN ?", ids).
+		Order(spriteCol).
+		Find(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", profile.TableName, err)
+	}
+	for _, row := range rows {
+		delete(row, idCol)
+		for column, value := range row {
+			// MySQL returns text columns as bytes, which JSON would encode as base64
+			if b, ok := value.([]byte); ok {
+				row[column] = string(b)
+			}
+		}
+		if id, ok := intField(row, spriteCol); ok {
+			found[id] = true
+		}
+		if name, ok := row[nameCol].(string); ok && name != "" {
+			classnames[furnitureAdp.BaseClassname(name)] = true
+		}
+		p.Rows = append(p.Rows, row)
+	}
+
+	for _, id := range ids {
+		if !found[id] {
+			p.Manifest.Warnings = append(p.Manifest.Warnings, fmt.Sprintf("id %d not found in gamedata or %s", id, profile.TableName))
+		}
+	}
+	if len(found) == 0 {
+		return nil, fmt.Errorf("none of the %d ids exist in gamedata or %s", len(ids), profile.TableName)
+	}
+
+	// Assets, one per base classname shared by all color variants
+	names := make([]string, 0, len(classnames))
+	for name := range classnames {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		data, found, err := storage.ReadObject(ctx, client, buckets.Assets, furnitureAdp.AssetKey(name))
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			p.Manifest.Warnings = append(p.Manifest.Warnings, "asset not found: "+furnitureAdp.AssetKey(name))
+			continue
+		}
+		p.Assets[name] = data
+		p.Manifest.Assets = append(p.Manifest.Assets, name)
+	}
+
+	if err := p.Write(w); err != nil {
+		return nil, err
+	}
+	return &p.Manifest, nil
+}
+
+// canonicalEmulator maps an emulator name to the profile it selects, so packs built
+// with "plusemu" install into "plus" hotels.
+func canonicalEmulator(emulator string) string {
+	switch emulator {
+	case "comet":
+		return "comet"
+	case "plus", "plusemu":
+		return "plus"
+	default:
+		return "arcturus"
+	}
+}
+
+// readGamedata reads and parses the furniture gamedata, returning the raw file too.
+func readGamedata(ctx context.Context, client storage.Client, bucket string) (map[string]any, []byte, error) {
+	data, found, err := storage.ReadObject(ctx, client, bucket, furnitureAdp.GamedataObject)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !found {
+		return nil, nil, fmt.Errorf("%s not found", furnitureAdp.GamedataObject)
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse gamedata: %w", err)
+	}
+	return doc, data, nil
+}
+
+// intField returns the numeric value of key in a gamedata item or database row, or
+// false when it is unset.
+func intField(fields map[string]any, key string) (int, bool) {
+	v, ok := fields[key]
+	if !ok || v == nil {
+		return 0, false
+	}
+	return utils.ToInt(v), true
+}
@@ -0,0 +1,163 @@
+package pack
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"asset-manager/core/reconcile"
+	"asset-manager/core/storage"
+	"asset-manager/core/upload"
+	"asset-manager/feature/assets"
+	furnitureAdp "asset-manager/feature/furniture/reconcile"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// ConvertOptions configures ConvertSWF.
+type ConvertOptions struct {
+	// Upload checks the converted bundles and holds the .swf source prefix.
+	Upload upload.Config
+	// DryRun lists the files that would be converted without converting anything.
+	DryRun bool
+}
+
+// ConvertFile is a legacy .swf file ConvertSWF converts, or would convert.
+type ConvertFile struct {
+	// Source is the .swf object.
+	Source    string `json:"source"`
+	Classname string `json:"classname"`
+	// Object is the bundled .nitro object it is written to.
+	Object string `json:"object"`
+}
+
+// ConvertReport lists the .swf files a conversion run found and what became of them.
+type ConvertReport struct {
+	Files []ConvertFile `json:"files"`
+	// Conversions holds the outcome of every file; empty in a dry run.
+	Conversions []furnitureAdp.Conversion `json:"conversions"`
+	Skipped     []SkippedFile             `json:"skipped"`
+	Warnings    []string                  `json:"warnings,omitempty"`
+	Applied     bool                      `json:"applied"`
+}
+
+// ConvertSWF converts the legacy .swf files under the source prefix of the assets
+// bucket into bundled .nitro files with the registered converter. Files whose
+// classname already has a bundled file are skipped. A failed file does not stop the
+// others; every outcome is recorded in the conversion log under the source prefix,
+// which reconciles report as the "swf_conversion" metadata of each item.
+//
+// Gamedata and database rows are left alone. It returns upload.ErrNoConverter when
+// no converter is registered, and a *reconcile.LockedError when another reconcile
+// holds the run lock.
+func ConvertSWF(ctx context.Context, client storage.Client, buckets storage.Buckets, opts ConvertOptions) (report *ConvertReport, err error) {
+	prefix := opts.Upload.Convert.SourcePrefix
+	report = &ConvertReport{Files: []ConvertFile{}, Conversions: []furnitureAdp.Conversion{}, Skipped: []SkippedFile{}}
+
+	existing := make(map[string]bool)
+	for obj := range client.ListObjects(ctx, buckets.Assets, minio.ListObjectsOptions{Prefix: furnitureAdp.StoragePrefix + "/", Recursive: true}) {
+		if obj.Err != nil {
+			return nil, fmt.Errorf("failed to list objects: %w", obj.Err)
+		}
+		existing[obj.Key] = true
+	}
+
+	seen := make(map[string]string)
+	for obj := range client.ListObjects(ctx, buckets.Assets, minio.ListObjectsOptions{Prefix: prefix, Recursive: true}) {
+		if obj.Err != nil {
+			return nil, fmt.Errorf("failed to list objects: %w", obj.Err)
+		}
+		if !upload.IsSWF(obj.Key) {
+			continue
+		}
+		classname := strings.TrimSuffix(path.Base(obj.Key), path.Ext(obj.Key))
+		key, err := assets.FurnitureKey(classname)
+		switch {
+		case err != nil:
+			report.Skipped = append(report.Skipped, SkippedFile{File: obj.Key, Reason: err.Error()})
+		case seen[classname] != "":
+			report.Skipped = append(report.Skipped, SkippedFile{File: obj.Key, Classname: classname, Reason: "same classname as " + seen[classname]})
+		case existing[key]:
+			report.Skipped = append(report.Skipped, SkippedFile{File: obj.Key, Classname: classname, Reason: "already bundled as " + key})
+		default:
+			seen[classname] = obj.Key
+			report.Files = append(report.Files, ConvertFile{Source: obj.Key, Classname: classname, Object: key})
+		}
+	}
+
+	if opts.DryRun || len(report.Files) == 0 {
+		return report, nil
+	}
+	if !upload.CanConvert() {
+		return nil, upload.ErrNoConverter
+	}
+
+	lock, err := reconcile.AcquireRunLock(ctx, client, buckets.Assets, reconcile.DefaultLockTTL)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if releaseErr := lock.Release(); releaseErr != nil && err == nil {
+			err = releaseErr
+		}
+	}()
+
+	converted := 0
+	for _, file := range report.Files {
+		record := furnitureAdp.Conversion{Classname: file.Classname, Source: file.Source, Status: furnitureAdp.ConversionConverted, ConvertedAt: time.Now()}
+		if err := convertFile(ctx, client, buckets.Assets, file, opts.Upload); err != nil {
+			if ctx.Err() != nil {
+				return report, ctx.Err()
+			}
+			record.Status, record.Error = furnitureAdp.ConversionFailed, err.Error()
+		} else {
+			record.Object = file.Object
+			converted++
+		}
+		report.Conversions = append(report.Conversions, record)
+	}
+	report.Applied = true
+
+	if err := furnitureAdp.RecordConversions(ctx, client, buckets.Assets, furnitureAdp.ConversionLogObject(prefix), report.Conversions); err != nil {
+		report.Warnings = append(report.Warnings, err.Error())
+	}
+
+	// Indices built before the run miss the new bundles
+	if converted > 0 {
+		for _, spec := range reconcile.RegisteredSpecs() {
+			if _, ok := spec.Adapter.(*furnitureAdp.FurnitureAdapter); ok {
+				reconcile.InvalidateCache(spec)
+			}
+		}
+	}
+	return report, nil
+}
+
+// convertFile converts one .swf object and writes the bundle once it passes the
+// upload checks and the malware scan.
+func convertFile(ctx context.Context, client storage.Client, bucket string, file ConvertFile, uploads upload.Config) error {
+	data, found, err := storage.ReadObject(ctx, client, bucket, file.Source)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("%s not found", file.Source)
+	}
+	bundle, err := upload.ConvertSWF(ctx, file.Source, data)
+	if err != nil {
+		return err
+	}
+	if err := uploads.Check(path.Base(file.Object), bundle); err != nil {
+		return err
+	}
+	if err := upload.ScanObject(ctx, client, bucket, file.Object, bundle); err != nil {
+		return err
+	}
+	if _, err := client.PutObject(ctx, bucket, file.Object, bytes.NewReader(bundle), int64(len(bundle)), minio.PutObjectOptions{}); err != nil {
+		return fmt.Errorf("failed to write %s: %w", file.Object, err)
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package pack
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"asset-manager/core/json"
+	"asset-manager/core/storage/mocks"
+	"asset-manager/core/upload"
+	furnitureAdp "asset-manager/feature/furniture/reconcile"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// testConverter converts chair files and fails on everything else.
+type testConverter struct {
+	t *testing.T
+}
+
+func (c testConverter) Convert(ctx context.Context, name string, data []byte) ([]byte, error) {
+	if strings.HasSuffix(name, "chair.swf") {
+		return nitroBundle(c.t, "chair.json"), nil
+	}
+	return nil, errors.New("unsupported swf version")
+}
+
+// objectListing returns a listing of keys.
+func objectListing(keys ...string) <-chan minio.ObjectInfo {
+	ch := make(chan minio.ObjectInfo, len(keys))
+	for _, key := range keys {
+		ch <- minio.ObjectInfo{Key: key}
+	}
+	close(ch)
+	return ch
+}
+
+// mockSWFStorage lists a chair and a lamp to convert, a table already bundled, a
+// second chair and the conversion log under swf/.
+func mockSWFStorage() *mocks.Client {
+	mockClient := new(mocks.Client)
+	mockClient.On("ListObjects", mock.Anything, "test-bucket", mock.MatchedBy(func(opts minio.ListObjectsOptions) bool {
+		return opts.Prefix == "bundled/furniture/"
+	})).Return(objectListing("bundled/furniture/table.nitro"))
+	mockClient.On("ListObjects", mock.Anything, "test-bucket", mock.MatchedBy(func(opts minio.ListObjectsOptions) bool {
+		return opts.Prefix == "swf/"
+	})).Return(objectListing("swf/chair.swf", "swf/conversions.json", "swf/lamp.swf", "swf/old/chair.swf", "swf/table.swf"))
+	return mockClient
+}
+
+// TestConvertSWF tests that .swf files are converted to bundles, that failures do
+// not stop the run and that every outcome is recorded in the conversion log.
+func TestConvertSWF(t *testing.T) {
+	upload.SetConverter(testConverter{t: t})
+	defer upload.SetConverter(nil)
+
+	mockClient := mockSWFStorage()
+	mockLock(mockClient)
+	mockClient.On("GetObject", mock.Anything, "test-bucket", "swf/chair.swf", mock.Anything).
+		Return(io.NopCloser(strings.NewReader("FWS")), nil)
+	mockClient.On("GetObject", mock.Anything, "test-bucket", "swf/lamp.swf", mock.Anything).
+		Return(io.NopCloser(strings.NewReader("FWS")), nil)
+	mockClient.On("PutObject", mock.Anything, "test-bucket", "bundled/furniture/chair.nitro", mock.Anything, mock.Anything, mock.Anything).
+		Return(minio.UploadInfo{}, nil)
+	mockClient.On("GetObject", mock.Anything, "test-bucket", "swf/conversions.json", mock.Anything).
+		Return(io.ReadCloser(nil), minio.ErrorResponse{Code: "NoSuchKey"})
+	var written []byte
+	mockClient.On("PutObject", mock.Anything, "test-bucket", "swf/conversions.json", mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			written, _ = io.ReadAll(args.Get(3).(io.Reader))
+		}).
+		Return(minio.UploadInfo{}, nil)
+
+	opts := ConvertOptions{Upload: testUploads}
+	opts.Upload.Convert.SourcePrefix = "swf/"
+	report, err := ConvertSWF(context.Background(), mockClient, testBuckets, opts)
+	require.NoError(t, err)
+	assert.True(t, report.Applied)
+	assert.Equal(t, []ConvertFile{
+		{Source: "swf/chair.swf", Classname: "chair", Object: "bundled/furniture/chair.nitro"},
+		{Source: "swf/lamp.swf", Classname: "lamp", Object: "bundled/furniture/lamp.nitro"},
+	}, report.Files)
+	assert.Equal(t, []SkippedFile{
+		{File: "swf/old/chair.swf", Classname: "chair", Reason: "same classname as swf/chair.swf"},
+		{File: "swf/table.swf", Classname: "table", Reason: "already bundled as bundled/furniture/table.nitro"},
+	}, report.Skipped)
+
+	require.Len(t, report.Conversions, 2)
+	assert.Equal(t, furnitureAdp.ConversionConverted, report.Conversions[0].Status)
+	assert.Equal(t, "bundled/furniture/chair.nitro", report.Conversions[0].Object)
+	assert.Equal(t, furnitureAdp.ConversionFailed, report.Conversions[1].Status)
+	assert.Contains(t, report.Conversions[1].Error, "unsupported swf version")
+
+	var log map[string]furnitureAdp.Conversion
+	require.NoError(t, json.Unmarshal(written, &log))
+	assert.Equal(t, furnitureAdp.ConversionConverted, log["chair"].Status)
+	assert.Equal(t, furnitureAdp.ConversionFailed, log["lamp"].Status)
+	mockClient.AssertNotCalled(t, "PutObject", mock.Anything, mock.Anything, "bundled/furniture/lamp.nitro", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestConvertSWF_DryRun tests that a dry run lists the files without a converter, and
+// that applying without one is refused.
+func TestConvertSWF_DryRun(t *testing.T) {
+	upload.SetConverter(nil)
+	opts := ConvertOptions{Upload: testUploads, DryRun: true}
+	opts.Upload.Convert.SourcePrefix = "swf/"
+
+	mockClient := mockSWFStorage()
+	report, err := ConvertSWF(context.Background(), mockClient, testBuckets, opts)
+	require.NoError(t, err)
+	assert.False(t, report.Applied)
+	assert.Len(t, report.Files, 2)
+	assert.Empty(t, report.Conversions)
+	mockClient.AssertNotCalled(t, "GetObject", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	opts.DryRun = false
+	_, err = ConvertSWF(context.Background(), mockSWFStorage(), testBuckets, opts)
+	assert.ErrorIs(t, err, upload.ErrNoConverter)
+}
+
+// TestImportZip_SWF tests that archived .swf files are converted and imported when a
+// converter is registered, and skipped otherwise.
+func TestImportZip_SWF(t *testing.T) {
+	archive := buildTestZip(t, map[string][]byte{"chair.swf": []byte("FWS"), "lamp.swf": []byte("FWS")})
+
+	report, err := ImportZip(context.Background(), mockImportStorage(), testBuckets, archive, ImportOptions{Upload: testUploads, DryRun: true})
+	require.NoError(t, err)
+	assert.Empty(t, report.Created)
+	assert.Len(t, report.Skipped, 2)
+
+	upload.SetConverter(testConverter{t: t})
+	defer upload.SetConverter(nil)
+	report, err = ImportZip(context.Background(), mockImportStorage(), testBuckets, archive, ImportOptions{Upload: testUploads, DryRun: true})
+	require.NoError(t, err)
+	require.Len(t, report.Created, 1)
+	assert.Equal(t, "chair", report.Created[0].Classname)
+	assert.Equal(t, "chair.swf", report.Created[0].ConvertedFrom)
+	require.Len(t, report.Skipped, 1)
+	assert.Equal(t, "lamp", report.Skipped[0].Classname)
+	assert.True(t, strings.HasPrefix(report.Skipped[0].Reason, upload.ErrConversion.Error()))
+
+	require.Len(t, report.Conversions, 2)
+	statuses := map[string]string{}
+	for _, conversion := range report.Conversions {
+		statuses[conversion.Classname] = conversion.Status
+	}
+	assert.Equal(t, map[string]string{"chair": furnitureAdp.ConversionConverted, "lamp": furnitureAdp.ConversionFailed}, statuses)
+}
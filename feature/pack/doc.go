@@ -0,0 +1,42 @@
+// Package pack exports furniture into portable content packs, installs them into
+// another hotel, imports ZIP archives of .nitro files and converts legacy .swf furniture.
+//
+// A pack is a gzipped tar archive holding everything one hotel needs to serve a set
+// of furniture items from another:
+//
+//   - manifest.json: format version, source emulator and table, and the packed items.
+//   - gamedata.json: the FurnitureData.json entries of the items, every field kept.
+//   - rows.json: the furniture table rows of the items, used by Install.
+//   - items.sql: the same rows as INSERT statements, for manual imports.
+//   - nitro/<classname>.nitro: the bundled asset of every packed classname.
+//
+// # Install
+//
+// Install refuses packs built for another emulator and reports every conflict (IDs
+// or classnames already present in gamedata, the database or storage) before writing
+// anything. Rows are inserted without their primary key so the target database
+// assigns new ones. With a malware scanner configured (core/upload), every asset is
+// scanned first and a rejected file stops the install. Like rename, the database transaction only commits once the
+// merged gamedata is written, and a failure removes the uploaded assets again.
+//
+// # Import
+//
+// ImportZip imports a ZIP archive of bare .nitro files, e.g. from a furniture pack
+// found online, as new furniture. Each file passes the upload checks and gets a
+// gamedata entry built from a template and its manifest item; files that fail, or
+// whose classname or ID already exists, are skipped and reported instead of stopping
+// the import. No database rows are written.
+//
+// # SWF Conversion
+//
+// With a converter configured (core/upload), archived .swf files are converted and
+// imported like .nitro files, and ConvertSWF converts the .swf files found under the
+// source prefix (default swf/) into bundled/furniture. Every outcome is recorded in
+// the conversion log next to them, which furniture reconciles report as the
+// swf_conversion metadata of each item.
+//
+// # HTTP Endpoints
+//
+//   - POST /furniture/import : Import a ZIP archive sent as multipart form data.
+//   - POST /furniture/convert : Convert the .swf files under the source prefix.
+package pack
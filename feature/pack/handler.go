@@ -0,0 +1,234 @@
+package pack
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"asset-manager/core/confirm"
+	"asset-manager/core/json"
+	"asset-manager/core/logger"
+	"asset-manager/core/reconcile"
+	"asset-manager/core/upload"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// Handler handles HTTP requests for furniture imports and .swf conversions.
+type Handler struct {
+	service *Service
+}
+
+// NewHandler creates a new HTTP handler.
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// RegisterRoutes registers the import and conversion routes.
+func (h *Handler) RegisterRoutes(app fiber.Router) {
+	app.Post("/furniture/import", upload.Limit(h.service.uploads.MaxImportSize), h.HandleImport)
+	app.Post("/furniture/convert", h.HandleConvert)
+}
+
+// HandleImport imports a ZIP archive of .nitro files as new furniture.
+// @Summary Import Furniture Archive
+// @Description Extracts a ZIP archive, uploads every .nitro file to storage and adds a FurnitureData.json entry per file, built from the template (default: a 1x1 room item named after its classname) with the manifest item of its classname on top. The manifest is the manifest form part or, without one, manifest.json at the archive root. Files failing the upload checks and classnames or IDs already in gamedata or storage are skipped; the report lists created and skipped items. Database rows are left to a reconcile sync. With SERVER_MUTATIONS_REQUIRE_CONFIRMATION set, the first request answers with the dry-run report and a confirmation token; send the same archive again with confirm=<token> to import it.
+// @Tags furniture
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "ZIP archive of .nitro files"
+// @Param manifest formData string false "Manifest JSON: {\"template\": {...}, \"items\": [{\"classname\": \"...\", ...}]}"
+// @Param template formData string false "Template JSON object, replacing the manifest template"
+// @Param dry_run query boolean false "Report what would be imported without writing anything"
+// @Param confirm query string false "Confirmation token of a reviewed import (when SERVER_MUTATIONS_REQUIRE_CONFIRMATION is set)"
+// @Success 200 {object} pack.ImportReport "Import Report"
+// @Failure 400 {object} map[string]string "Missing or invalid archive, manifest or template"
+// @Failure 403 {object} map[string]string "Unknown or already used confirmation token"
+// @Failure 409 {object} map[string]string "Another reconcile holds the run lock, or the import changed since the token was issued"
+// @Failure 410 {object} map[string]string "Confirmation token expired"
+// @Failure 413 {object} map[string]string "Request body above UPLOAD_MAX_IMPORT_SIZE"
+// @Failure 422 {object} map[string]string "A file was rejected by the malware scan"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /furniture/import [post]
+func (h *Handler) HandleImport(c *fiber.Ctx) error {
+	l := logger.WithRayID(h.service.logger, c)
+
+	archive, err := formPart(c, "file")
+	if err == nil && archive == nil {
+		err = errors.New("missing archive in the file form part")
+	}
+	var manifest *ImportManifest
+	if err == nil {
+		err = decodeFormPart(c, "manifest", &manifest)
+	}
+	var template map[string]any
+	if err == nil {
+		err = decodeFormPart(c, "template", &template)
+	}
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	dryRun := c.QueryBool("dry_run")
+	l.Info("Starting furniture import", zap.Int("bytes", len(archive)), zap.Bool("dry_run", dryRun))
+
+	// Review the dry-run report before anything is written when confirmation is required
+	if store := confirm.Required(); store != nil && !dryRun {
+		review, err := h.service.Import(c.Context(), archive, manifest, template, true)
+		if err != nil {
+			return importError(c, l, err)
+		}
+		if confirmed, err := confirm.Await(c, l, store, "furniture/import", importPlan(review), fiber.Map{"would_import": review}); !confirmed {
+			return err
+		}
+	}
+
+	report, err := h.service.Import(c.Context(), archive, manifest, template, dryRun)
+	if err != nil {
+		return importError(c, l, err)
+	}
+
+	l.Info("Furniture import finished",
+		zap.Int("created", len(report.Created)),
+		zap.Int("skipped", len(report.Skipped)),
+		zap.Bool("applied", report.Applied))
+	return c.JSON(report)
+}
+
+// HandleConvert converts the legacy .swf files under the source prefix to bundled furniture.
+// @Summary Convert Legacy SWF Furniture
+// @Description Converts every .swf file under UPLOAD_CONVERT_SOURCE_PREFIX (default swf/) of the assets bucket whose classname has no bundled .nitro file yet, using the configured converter command or service, and writes the bundles to bundled/furniture. Each outcome is recorded in the conversion log, which reconciles report as the swf_conversion metadata of each item. Gamedata and database rows are left alone. With SERVER_MUTATIONS_REQUIRE_CONFIRMATION set, the first request answers with the dry-run report and a confirmation token; repeat it with confirm=<token> to convert.
+// @Tags furniture
+// @Produce json
+// @Param dry_run query boolean false "List the files that would be converted without converting them"
+// @Param confirm query string false "Confirmation token of a reviewed conversion (when SERVER_MUTATIONS_REQUIRE_CONFIRMATION is set)"
+// @Success 200 {object} pack.ConvertReport "Conversion Report"
+// @Failure 403 {object} map[string]string "Unknown or already used confirmation token"
+// @Failure 409 {object} map[string]string "Another reconcile holds the run lock, or the files to convert changed since the token was issued"
+// @Failure 410 {object} map[string]string "Confirmation token expired"
+// @Failure 503 {object} map[string]string "No converter configured"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /furniture/convert [post]
+func (h *Handler) HandleConvert(c *fiber.Ctx) error {
+	l := logger.WithRayID(h.service.logger, c)
+
+	dryRun := c.QueryBool("dry_run")
+	l.Info("Starting swf conversion", zap.Bool("dry_run", dryRun))
+
+	// Review the dry-run report before anything is converted when confirmation is required
+	if store := confirm.Required(); store != nil && !dryRun {
+		if !upload.CanConvert() {
+			return convertError(c, l, upload.ErrNoConverter)
+		}
+		review, err := h.service.Convert(c.Context(), true)
+		if err != nil {
+			return convertError(c, l, err)
+		}
+		if confirmed, err := confirm.Await(c, l, store, "furniture/convert", convertPlan(review), fiber.Map{"would_convert": review}); !confirmed {
+			return err
+		}
+	}
+
+	report, err := h.service.Convert(c.Context(), dryRun)
+	if err != nil {
+		return convertError(c, l, err)
+	}
+
+	l.Info("Swf conversion finished",
+		zap.Int("files", len(report.Files)),
+		zap.Int("skipped", len(report.Skipped)),
+		zap.Bool("applied", report.Applied))
+	return c.JSON(report)
+}
+
+// importError answers a failed import with its HTTP status.
+func importError(c *fiber.Ctx, l *zap.Logger, err error) error {
+	var locked *reconcile.LockedError
+	switch {
+	case errors.Is(err, ErrInvalidArchive):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.As(err, &locked):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, upload.ErrInfected):
+		l.Warn("Import rejected by malware scan", zap.Error(err))
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	l.Error("Furniture import failed", zap.Error(err))
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": err.Error(),
+	})
+}
+
+// convertError answers a failed conversion with its HTTP status.
+func convertError(c *fiber.Ctx, l *zap.Logger, err error) error {
+	var locked *reconcile.LockedError
+	switch {
+	case errors.Is(err, upload.ErrNoConverter):
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.As(err, &locked):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	l.Error("Swf conversion failed", zap.Error(err))
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": err.Error(),
+	})
+}
+
+// importPlan is the part of an import dry run a confirmation token covers. Conversion
+// times are left out, so the same archive reviews the same.
+func importPlan(report *ImportReport) fiber.Map {
+	return fiber.Map{"created": report.Created, "skipped": report.Skipped}
+}
+
+// convertPlan is the part of a conversion dry run a confirmation token covers.
+func convertPlan(report *ConvertReport) fiber.Map {
+	return fiber.Map{"files": report.Files, "skipped": report.Skipped}
+}
+
+// formPart returns a multipart form part sent as a file or as a value, or nil when
+// the request has none.
+func formPart(c *fiber.Ctx, name string) ([]byte, error) {
+	header, err := c.FormFile(name)
+	if err != nil {
+		if value := c.FormValue(name); value != "" {
+			return []byte(value), nil
+		}
+		return nil, nil
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", name, err)
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	return data, nil
+}
+
+// decodeFormPart decodes the JSON form part name into v, leaving v alone without one.
+func decodeFormPart(c *fiber.Ctx, name string, v any) error {
+	data, err := formPart(c, name)
+	if err != nil || data == nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("invalid %s: %w", name, err)
+	}
+	return nil
+}
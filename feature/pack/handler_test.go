@@ -0,0 +1,209 @@
+package pack
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"asset-manager/core/confirm"
+	"asset-manager/core/json"
+	"asset-manager/core/storage/mocks"
+	"asset-manager/core/upload"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// TestHandler_HandleImport tests imports of multipart archives and the refusal of
+// incomplete or invalid requests.
+func TestHandler_HandleImport(t *testing.T) {
+	newApp := func(client *mocks.Client) *fiber.App {
+		app := fiber.New()
+		NewHandler(NewService(client, testBuckets, zap.NewNop(), testUploads)).RegisterRoutes(app)
+		return app
+	}
+	form := func(archive []byte, fields map[string]string) (*bytes.Buffer, string) {
+		var body bytes.Buffer
+		w := multipart.NewWriter(&body)
+		if archive != nil {
+			part, err := w.CreateFormFile("file", "pack.zip")
+			require.NoError(t, err)
+			part.Write(archive)
+		}
+		for name, value := range fields {
+			require.NoError(t, w.WriteField(name, value))
+		}
+		require.NoError(t, w.Close())
+		return &body, w.FormDataContentType()
+	}
+
+	t.Run("DryRun", func(t *testing.T) {
+		archive := buildTestZip(t, map[string][]byte{"chair.nitro": nitroBundle(t, "chair.json")})
+		body, contentType := form(archive, map[string]string{
+			"manifest": `{"items": [{"classname": "chair", "id": 7000}]}`,
+		})
+		req := httptest.NewRequest("POST", "/furniture/import?dry_run=true", body)
+		req.Header.Set("Content-Type", contentType)
+
+		resp, err := newApp(mockImportStorage()).Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+
+		var report ImportReport
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&report))
+		assert.False(t, report.Applied)
+		assert.Equal(t, []ImportedItem{{ID: 7000, Classname: "chair", Section: "roomitemtypes", Object: "bundled/furniture/chair.nitro", Source: "manifest"}}, report.Created)
+	})
+
+	t.Run("Confirmation", func(t *testing.T) {
+		confirm.SetStore(confirm.NewStore(time.Minute))
+		defer confirm.SetStore(nil)
+		archive := buildTestZip(t, map[string][]byte{"chair.nitro": nitroBundle(t, "chair.json")})
+		send := func(client *mocks.Client, query string) *http.Response {
+			body, contentType := form(archive, map[string]string{
+				"manifest": `{"items": [{"classname": "chair", "id": 7000}]}`,
+			})
+			req := httptest.NewRequest("POST", "/furniture/import"+query, body)
+			req.Header.Set("Content-Type", contentType)
+			resp, err := newApp(client).Test(req)
+			require.NoError(t, err)
+			return resp
+		}
+
+		// Nothing is written before confirmation; the mock has no PutObject
+		resp := send(mockImportStorage(), "")
+		assert.Equal(t, 200, resp.StatusCode)
+		var review struct {
+			Status      string       `json:"status"`
+			Token       string       `json:"confirmation_token"`
+			WouldImport ImportReport `json:"would_import"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&review))
+		assert.Equal(t, "confirmation_required", review.Status)
+		assert.Equal(t, "chair", review.WouldImport.Created[0].Classname)
+
+		assert.Equal(t, 403, send(mockImportStorage(), "?confirm=bogus").StatusCode)
+
+		// The confirming request reads the gamedata twice: to review it again and to import
+		client := new(mocks.Client)
+		for range 2 {
+			client.On("GetObject", mock.Anything, "test-bucket", "gamedata/FurnitureData.json", mock.Anything).
+				Return(io.NopCloser(strings.NewReader(targetGamedataJSON)), nil).Once()
+		}
+		client.On("ListObjects", mock.Anything, "test-bucket", mock.Anything).Return(nil)
+		mockLock(client)
+		client.On("PutObject", mock.Anything, "test-bucket", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(minio.UploadInfo{}, nil)
+		resp = send(client, "?confirm="+review.Token)
+		assert.Equal(t, 200, resp.StatusCode)
+		var report ImportReport
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&report))
+		assert.True(t, report.Applied)
+		client.AssertCalled(t, "PutObject", mock.Anything, "test-bucket", "bundled/furniture/chair.nitro", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("BadRequest", func(t *testing.T) {
+		for name, fields := range map[string]map[string]string{
+			"MissingFile":     nil,
+			"InvalidTemplate": {"template": "["},
+		} {
+			var archive []byte
+			if name != "MissingFile" {
+				archive = buildTestZip(t, nil)
+			}
+			body, contentType := form(archive, fields)
+			req := httptest.NewRequest("POST", "/furniture/import", body)
+			req.Header.Set("Content-Type", contentType)
+
+			resp, err := newApp(new(mocks.Client)).Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, 400, resp.StatusCode, name)
+		}
+
+		body, contentType := form([]byte("not a zip"), nil)
+		req := httptest.NewRequest("POST", "/furniture/import", body)
+		req.Header.Set("Content-Type", contentType)
+		resp, err := newApp(new(mocks.Client)).Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, 400, resp.StatusCode)
+	})
+
+	t.Run("TooLarge", func(t *testing.T) {
+		uploads := testUploads
+		uploads.MaxImportSize = 64
+		app := fiber.New()
+		NewHandler(NewService(new(mocks.Client), testBuckets, zap.NewNop(), uploads)).RegisterRoutes(app)
+
+		archive := buildTestZip(t, map[string][]byte{"chair.nitro": nitroBundle(t, "chair.json")})
+		body, contentType := form(archive, nil)
+		req := httptest.NewRequest("POST", "/furniture/import", body)
+		req.Header.Set("Content-Type", contentType)
+
+		// The mock has no expectations: nothing is read or written
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, 413, resp.StatusCode)
+	})
+}
+
+// TestHandler_HandleConvert_Confirmation tests that a conversion waits for its
+// confirmation token when confirmation is required.
+func TestHandler_HandleConvert_Confirmation(t *testing.T) {
+	confirm.SetStore(confirm.NewStore(time.Minute))
+	defer confirm.SetStore(nil)
+	uploads := testUploads
+	uploads.Convert.SourcePrefix = "swf/"
+	send := func(client *mocks.Client, query string) *http.Response {
+		app := fiber.New()
+		NewHandler(NewService(client, testBuckets, zap.NewNop(), uploads)).RegisterRoutes(app)
+		resp, err := app.Test(httptest.NewRequest("POST", "/furniture/convert"+query, nil))
+		require.NoError(t, err)
+		return resp
+	}
+
+	upload.SetConverter(nil)
+	assert.Equal(t, 503, send(mockSWFStorage(), "").StatusCode, "no token without a converter")
+
+	upload.SetConverter(testConverter{t: t})
+	defer upload.SetConverter(nil)
+	resp := send(mockSWFStorage(), "")
+	assert.Equal(t, 200, resp.StatusCode)
+	var review struct {
+		Status       string        `json:"status"`
+		Token        string        `json:"confirmation_token"`
+		WouldConvert ConvertReport `json:"would_convert"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&review))
+	assert.Equal(t, "confirmation_required", review.Status)
+	assert.Len(t, review.WouldConvert.Files, 2)
+
+	// The confirming request lists the files twice: to review them again and to convert
+	client := new(mocks.Client)
+	for range 2 {
+		client.On("ListObjects", mock.Anything, "test-bucket", mock.MatchedBy(func(opts minio.ListObjectsOptions) bool {
+			return opts.Prefix == "bundled/furniture/"
+		})).Return(objectListing("bundled/furniture/table.nitro")).Once()
+		client.On("ListObjects", mock.Anything, "test-bucket", mock.MatchedBy(func(opts minio.ListObjectsOptions) bool {
+			return opts.Prefix == "swf/"
+		})).Return(objectListing("swf/chair.swf", "swf/conversions.json", "swf/lamp.swf", "swf/old/chair.swf", "swf/table.swf")).Once()
+	}
+	mockLock(client)
+	client.On("GetObject", mock.Anything, "test-bucket", mock.Anything, mock.Anything).
+		Return(io.NopCloser(strings.NewReader("FWS")), nil)
+	client.On("PutObject", mock.Anything, "test-bucket", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(minio.UploadInfo{}, nil)
+	resp = send(client, "?confirm="+review.Token)
+	assert.Equal(t, 200, resp.StatusCode)
+	var report ConvertReport
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&report))
+	assert.True(t, report.Applied)
+}
@@ -0,0 +1,470 @@
+package pack
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"asset-manager/core/json"
+	"asset-manager/core/reconcile"
+	"asset-manager/core/storage"
+	"asset-manager/core/upload"
+	"asset-manager/feature/assets"
+	furnitureAdp "asset-manager/feature/furniture/reconcile"
+
+	"github.com/minio/minio-go/v7"
+)
+
+const (
+	// ImportManifestFile is read from the root of an import archive when no manifest
+	// is given.
+	ImportManifestFile = "manifest.json"
+
+	// classnamePlaceholder is replaced by the classname in template string values.
+	classnamePlaceholder = "{classname}"
+
+	roomSection = "roomitemtypes"
+	wallSection = "wallitemtypes"
+)
+
+// ErrInvalidArchive is returned by ImportZip for an archive that is not a ZIP file or
+// holds an invalid manifest.
+var ErrInvalidArchive = errors.New("invalid import archive")
+
+// DefaultTemplate holds the gamedata fields of imported items the manifest does not
+// describe: a 1x1 room item named after its classname.
+var DefaultTemplate = map[string]any{
+	"name":            classnamePlaceholder,
+	"description":     "",
+	"revision":        0,
+	"category":        "",
+	"xdim":            1,
+	"ydim":            1,
+	"partcolors":      map[string]any{"color": []any{}},
+	"offerid":         -1,
+	"buyout":          false,
+	"rentofferid":     -1,
+	"rentbuyout":      false,
+	"bc":              false,
+	"excludeddynamic": false,
+	"customparams":    "",
+	"specialtype":     1,
+	"canstandon":      false,
+	"cansiton":        false,
+	"canlayon":        false,
+	"furniline":       "",
+	"environment":     "",
+	"rare":            false,
+}
+
+// ImportManifest describes the items of an import archive.
+type ImportManifest struct {
+	// Template holds the gamedata fields every generated entry starts from.
+	// "{classname}" in string values is replaced by the item's classname.
+	Template map[string]any `json:"template,omitempty"`
+	// Items holds gamedata fields per item, matched to the archived files by their
+	// "classname". An optional "section" ("roomitemtypes" or "wallitemtypes") picks
+	// the gamedata section; entries without an "id" get the next free one.
+	Items []map[string]any `json:"items,omitempty"`
+}
+
+// ImportOptions configures ImportZip.
+type ImportOptions struct {
+	// Manifest describes the archived items. Nil reads manifest.json from the
+	// archive root, if there is one.
+	Manifest *ImportManifest
+	// Template replaces the template of the manifest; with neither, DefaultTemplate
+	// is used.
+	Template map[string]any
+	// Upload checks the size, extension and content of every archived file.
+	Upload upload.Config
+	// DryRun reports what would be imported without writing anything.
+	DryRun bool
+}
+
+// ImportedItem is a furniture item created, or to be created, by ImportZip.
+type ImportedItem struct {
+	ID        int    `json:"id"`
+	Classname string `json:"classname"`
+	Section   string `json:"section"`
+	// Object is the storage key the .nitro file is written to.
+	Object string `json:"object"`
+	// Source is "manifest" when the manifest describes the item, else "template".
+	Source string `json:"source"`
+	// ConvertedFrom is the archived .swf file the .nitro file was converted from.
+	ConvertedFrom string `json:"converted_from,omitempty"`
+}
+
+// SkippedFile is an archived file, or manifest item, ImportZip left out.
+type SkippedFile struct {
+	File      string `json:"file"`
+	Classname string `json:"classname,omitempty"`
+	Reason    string `json:"reason"`
+}
+
+// ImportReport lists the items an import created and the files it skipped.
+type ImportReport struct {
+	Created  []ImportedItem `json:"created"`
+	Skipped  []SkippedFile  `json:"skipped"`
+	Warnings []string       `json:"warnings,omitempty"`
+	Applied  bool           `json:"applied"`
+	// Conversions holds the outcome of every archived .swf file; an applied import
+	// records them in the conversion log.
+	Conversions []furnitureAdp.Conversion `json:"conversions,omitempty"`
+}
+
+// importFile is one .nitro file of an import archive, or the bundle of a .swf file.
+type importFile struct {
+	name  string
+	data  []byte
+	item  ImportedItem
+	entry map[string]any
+}
+
+// ImportZip imports a ZIP archive of .nitro files as new furniture: every file is
+// uploaded to storage and gets a gamedata entry built from the template and its
+// manifest item. Files that fail the upload checks, and classnames or IDs that
+// already exist in gamedata or storage, are skipped and reported; the rest is
+// imported. Database rows are left to a reconcile sync.
+//
+// With a converter registered (upload.SetConverter), legacy .swf files are converted
+// and imported like .nitro files; an applied import records each conversion in the
+// conversion log under opts.Upload.Convert.SourcePrefix.
+//
+// Like Install, the files are scanned before the first is written, and a failure to
+// write the gamedata removes the uploaded files again. It returns a
+// *reconcile.LockedError when another reconcile holds the run lock.
+func ImportZip(ctx context.Context, client storage.Client, buckets storage.Buckets, archive []byte, opts ImportOptions) (report *ImportReport, err error) {
+	zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+	}
+
+	report = &ImportReport{Created: []ImportedItem{}, Skipped: []SkippedFile{}}
+	files, manifest, err := readImportArchive(ctx, zr, opts, report)
+	if err != nil {
+		return nil, err
+	}
+
+	if !opts.DryRun {
+		lock, err := reconcile.AcquireRunLock(ctx, client, buckets.Assets, reconcile.DefaultLockTTL)
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			if releaseErr := lock.Release(); releaseErr != nil && err == nil {
+				err = releaseErr
+			}
+		}()
+	}
+	// Write the change log once for the whole pack
+	ctx, changes := storage.WithChangeBuffer(ctx)
+	defer changes.Flush()
+
+	doc, _, err := readGamedata(ctx, client, buckets.Gamedata)
+	if err != nil {
+		return nil, err
+	}
+
+	template := opts.Template
+	if template == nil {
+		template = manifest.Template
+	}
+	if template == nil {
+		template = DefaultTemplate
+	}
+	files, entries, err := planImport(ctx, client, buckets.Assets, doc, files, manifest, template, report)
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		report.Created = append(report.Created, file.item)
+		if file.item.ConvertedFrom != "" {
+			report.Conversions = append(report.Conversions, furnitureAdp.Conversion{
+				Classname:   file.item.Classname,
+				Source:      file.item.ConvertedFrom,
+				Object:      file.item.Object,
+				Status:      furnitureAdp.ConversionConverted,
+				ConvertedAt: time.Now(),
+			})
+		}
+	}
+	if opts.DryRun {
+		return report, nil
+	}
+
+	if len(files) > 0 {
+		if err := applyImport(ctx, client, buckets, doc, files, entries, report); err != nil {
+			return report, err
+		}
+
+		// Indices built before the import miss the new classnames
+		for _, spec := range reconcile.RegisteredSpecs() {
+			if _, ok := spec.Adapter.(*furnitureAdp.FurnitureAdapter); ok {
+				reconcile.InvalidateCache(spec)
+			}
+		}
+	}
+
+	if prefix := opts.Upload.Convert.SourcePrefix; prefix != "" {
+		if err := furnitureAdp.RecordConversions(ctx, client, buckets.Assets, furnitureAdp.ConversionLogObject(prefix), report.Conversions); err != nil {
+			report.Warnings = append(report.Warnings, err.Error())
+		}
+	}
+	return report, nil
+}
+
+// readImportArchive reads the .nitro files of zr that pass the upload checks, converting
+// .swf files when a converter is registered, and the manifest of opts or, without one,
+// of the archive. Other files are skipped.
+func readImportArchive(ctx context.Context, zr *zip.Reader, opts ImportOptions, report *ImportReport) ([]importFile, ImportManifest, error) {
+	var manifest ImportManifest
+	if opts.Manifest != nil {
+		manifest = *opts.Manifest
+	}
+
+	var files []importFile
+	seen := make(map[string]string)
+	for _, entry := range zr.File {
+		name := entry.Name
+		if entry.FileInfo().IsDir() || strings.HasPrefix(name, "__MACOSX/") {
+			continue
+		}
+		if name == ImportManifestFile {
+			if opts.Manifest == nil {
+				data, err := readZipEntry(entry, opts.Upload.MaxFileSize)
+				if err == nil {
+					err = json.Unmarshal(data, &manifest)
+				}
+				if err != nil {
+					return nil, manifest, fmt.Errorf("%w: %s: %v", ErrInvalidArchive, ImportManifestFile, err)
+				}
+			}
+			continue
+		}
+		swf := upload.IsSWF(name) && upload.CanConvert()
+		if !swf && !strings.EqualFold(path.Ext(name), furnitureAdp.StorageExtension) {
+			report.Skipped = append(report.Skipped, SkippedFile{File: name, Reason: "not a .nitro file"})
+			continue
+		}
+
+		classname := strings.TrimSuffix(path.Base(name), path.Ext(name))
+		key, err := assets.FurnitureKey(classname)
+		if err != nil {
+			report.Skipped = append(report.Skipped, SkippedFile{File: name, Reason: err.Error()})
+			continue
+		}
+		if first, ok := seen[classname]; ok {
+			report.Skipped = append(report.Skipped, SkippedFile{File: name, Classname: classname, Reason: "same classname as " + first})
+			continue
+		}
+
+		data, err := readZipEntry(entry, opts.Upload.MaxFileSize)
+		if err == nil && swf {
+			if data, err = upload.ConvertSWF(ctx, name, data); err != nil {
+				report.Conversions = append(report.Conversions, furnitureAdp.Conversion{
+					Classname:   classname,
+					Source:      name,
+					Status:      furnitureAdp.ConversionFailed,
+					Error:       err.Error(),
+					ConvertedAt: time.Now(),
+				})
+			}
+		}
+		if err == nil {
+			err = opts.Upload.Check(path.Base(key), data)
+		}
+		if err != nil {
+			report.Skipped = append(report.Skipped, SkippedFile{File: name, Classname: classname, Reason: err.Error()})
+			continue
+		}
+		seen[classname] = name
+		item := ImportedItem{Classname: classname, Section: roomSection, Object: key, Source: "template"}
+		if swf {
+			item.ConvertedFrom = name
+		}
+		files = append(files, importFile{name: name, data: data, item: item})
+	}
+	return files, manifest, nil
+}
+
+// readZipEntry reads an archived file, refusing more than max bytes once
+// decompressed so a crafted archive cannot exhaust memory. Zero means no limit.
+func readZipEntry(entry *zip.File, max int64) ([]byte, error) {
+	rc, err := entry.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", entry.Name, err)
+	}
+	defer rc.Close()
+
+	var r io.Reader = rc
+	if max > 0 {
+		r = io.LimitReader(rc, max+1)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", entry.Name, err)
+	}
+	if max > 0 && int64(len(data)) > max {
+		return nil, fmt.Errorf("%w: %s exceeds %d bytes", upload.ErrTooLarge, entry.Name, max)
+	}
+	return data, nil
+}
+
+// planImport builds the gamedata entries of files, skipping those whose classname,
+// ID or storage object already exists, and manifest items without a file. It returns
+// the files to import with their entries per gamedata section.
+func planImport(ctx context.Context, client storage.Client, bucket string, doc map[string]any, files []importFile, manifest ImportManifest, template map[string]any, report *ImportReport) ([]importFile, map[string][]map[string]any, error) {
+	ids := make(map[int]bool)
+	names := make(map[string]bool)
+	nextID := 1
+	furnitureAdp.EachGamedataItem(doc, func(_ string, item map[string]any) {
+		if id, ok := intField(item, "id"); ok {
+			ids[id] = true
+			nextID = max(nextID, id+1)
+		}
+		if name, ok := item["classname"].(string); ok {
+			names[name] = true
+		}
+	})
+
+	described := make(map[string]map[string]any, len(manifest.Items))
+	for _, item := range manifest.Items {
+		if classname, ok := item["classname"].(string); ok {
+			described[classname] = item
+		}
+	}
+	archived := make(map[string]bool, len(files))
+	for _, file := range files {
+		archived[file.item.Classname] = true
+	}
+	for _, item := range manifest.Items {
+		classname, _ := item["classname"].(string)
+		if !archived[classname] {
+			report.Skipped = append(report.Skipped, SkippedFile{File: ImportManifestFile, Classname: classname, Reason: "no .nitro file in the archive"})
+		}
+	}
+
+	var planned []importFile
+	var needID []int
+	for _, file := range files {
+		classname := file.item.Classname
+		if names[classname] {
+			report.Skipped = append(report.Skipped, SkippedFile{File: file.name, Classname: classname, Reason: "classname already exists in gamedata"})
+			continue
+		}
+		exists, err := storage.ObjectExists(ctx, client, bucket, file.item.Object)
+		if err != nil {
+			return nil, nil, err
+		}
+		if exists {
+			report.Skipped = append(report.Skipped, SkippedFile{File: file.name, Classname: classname, Reason: "object already exists in storage"})
+			continue
+		}
+
+		entry := importEntry(template, described[classname], classname)
+		if section, ok := entry["section"].(string); ok {
+			delete(entry, "section")
+			if section != roomSection && section != wallSection {
+				report.Skipped = append(report.Skipped, SkippedFile{File: file.name, Classname: classname, Reason: fmt.Sprintf("unknown section %q", section)})
+				continue
+			}
+			file.item.Section = section
+		}
+		if _, ok := described[classname]; ok {
+			file.item.Source = "manifest"
+		}
+		if id, ok := intField(entry, "id"); ok {
+			if ids[id] {
+				report.Skipped = append(report.Skipped, SkippedFile{File: file.name, Classname: classname, Reason: fmt.Sprintf("id %d already used in gamedata", id)})
+				continue
+			}
+			ids[id] = true
+			nextID = max(nextID, id+1)
+			file.item.ID = id
+		} else {
+			needID = append(needID, len(planned))
+		}
+
+		names[classname] = true
+		file.entry = entry
+		planned = append(planned, file)
+	}
+
+	// IDs are assigned once every manifest ID is known, so none is handed out twice
+	for _, i := range needID {
+		planned[i].item.ID = nextID
+		planned[i].entry["id"] = nextID
+		nextID++
+	}
+	sort.Slice(planned, func(i, j int) bool { return planned[i].item.ID < planned[j].item.ID })
+
+	entries := make(map[string][]map[string]any)
+	for _, file := range planned {
+		entries[file.item.Section] = append(entries[file.item.Section], file.entry)
+	}
+	return planned, entries, nil
+}
+
+// importEntry returns the gamedata entry of classname: the template with the manifest
+// item's fields on top.
+func importEntry(template, item map[string]any, classname string) map[string]any {
+	entry := make(map[string]any, len(template)+len(item)+1)
+	for field, value := range template {
+		if s, ok := value.(string); ok {
+			value = strings.ReplaceAll(s, classnamePlaceholder, classname)
+		}
+		entry[field] = value
+	}
+	maps.Copy(entry, item)
+	entry["classname"] = classname
+	return entry
+}
+
+// applyImport scans and uploads files, then writes the gamedata with entries merged,
+// removing the uploads again when that fails.
+func applyImport(ctx context.Context, client storage.Client, buckets storage.Buckets, doc map[string]any, files []importFile, entries map[string][]map[string]any, report *ImportReport) error {
+	// 1. Scan every file before the first one is written; rejected files are quarantined
+	for _, file := range files {
+		if err := upload.ScanObject(ctx, client, buckets.Assets, file.item.Object, file.data); err != nil {
+			return err
+		}
+	}
+
+	// 2. Upload the files
+	var uploaded []string
+	undoUploads := func() {
+		for _, failure := range storage.RemoveObjectsWithRetry(ctx, client, buckets.Assets, uploaded, storage.DefaultRemoveRetry) {
+			report.Warnings = append(report.Warnings, fmt.Sprintf("failed to remove uploaded object %s: %v", failure.Object, failure.Err))
+		}
+	}
+	for _, file := range files {
+		key := file.item.Object
+		if _, err := client.PutObject(ctx, buckets.Assets, key, bytes.NewReader(file.data), int64(len(file.data)), minio.PutObjectOptions{}); err != nil {
+			undoUploads()
+			return fmt.Errorf("failed to write %s: %w", key, err)
+		}
+		uploaded = append(uploaded, key)
+	}
+
+	// 3. Write the merged gamedata
+	mergeGamedata(doc, &Pack{Gamedata: entries})
+	merged, err := json.MarshalIndent(doc, "", "  ")
+	if err == nil {
+		err = putGamedata(ctx, client, buckets.Gamedata, merged)
+	}
+	if err != nil {
+		undoUploads()
+		return fmt.Errorf("failed to write gamedata: %w", err)
+	}
+	report.Applied = true
+	return nil
+}
@@ -0,0 +1,160 @@
+package pack
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/zlib"
+	"context"
+	"encoding/binary"
+	"io"
+	"strings"
+	"testing"
+
+	"asset-manager/core/json"
+	"asset-manager/core/storage/mocks"
+	"asset-manager/core/upload"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var testUploads = upload.Config{MaxFileSize: 1024, Extensions: []string{".nitro"}}
+
+// nitroBundle builds a Nitro bundle holding one file.
+func nitroBundle(t *testing.T, name string) []byte {
+	var compressed bytes.Buffer
+	zw := zlib.NewWriter(&compressed)
+	_, err := zw.Write([]byte(`{"name":"` + name + `"}`))
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.BigEndian, uint16(1))
+	binary.Write(&buf, binary.BigEndian, uint16(len(name)))
+	buf.WriteString(name)
+	binary.Write(&buf, binary.BigEndian, uint32(compressed.Len()))
+	buf.Write(compressed.Bytes())
+	return buf.Bytes()
+}
+
+// buildTestZip returns a ZIP archive of files.
+func buildTestZip(t *testing.T, files map[string][]byte) []byte {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, data := range files {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write(data)
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+// importTestZip holds a new chair, a lamp described by the manifest, the table
+// gamedata already has, a text file, a renamed image and a manifest item without file.
+func importTestZip(t *testing.T) []byte {
+	return buildTestZip(t, map[string][]byte{
+		"chair.nitro":      nitroBundle(t, "chair.json"),
+		"walls/lamp.nitro": nitroBundle(t, "lamp.json"),
+		"table.nitro":      nitroBundle(t, "table.json"),
+		"readme.txt":       []byte("hello"),
+		"image.nitro":      []byte("\x89PNG\r\n"),
+		ImportManifestFile: []byte(`{"items": [
+			{"classname": "lamp", "id": 5000, "name": "Lamp", "section": "wallitemtypes"},
+			{"classname": "ghost", "name": "Ghost"}
+		]}`),
+	})
+}
+
+// mockImportStorage serves the target gamedata and an empty asset listing.
+func mockImportStorage() *mocks.Client {
+	mockClient := new(mocks.Client)
+	mockClient.On("GetObject", mock.Anything, "test-bucket", "gamedata/FurnitureData.json", mock.Anything).
+		Return(io.NopCloser(strings.NewReader(targetGamedataJSON)), nil)
+	mockClient.On("ListObjects", mock.Anything, "test-bucket", mock.Anything).Return(nil)
+	return mockClient
+}
+
+// TestImportZip tests that archived files are uploaded with generated gamedata
+// entries, and that invalid or existing files are skipped.
+func TestImportZip(t *testing.T) {
+	mockClient := mockImportStorage()
+	mockLock(mockClient)
+	mockClient.On("PutObject", mock.Anything, "test-bucket", "bundled/furniture/chair.nitro", mock.Anything, mock.Anything, mock.Anything).
+		Return(minio.UploadInfo{}, nil)
+	mockClient.On("PutObject", mock.Anything, "test-bucket", "bundled/furniture/lamp.nitro", mock.Anything, mock.Anything, mock.Anything).
+		Return(minio.UploadInfo{}, nil)
+	var written []byte
+	mockClient.On("PutObject", mock.Anything, "test-bucket", "gamedata/FurnitureData.json", mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			written, _ = io.ReadAll(args.Get(3).(io.Reader))
+		}).
+		Return(minio.UploadInfo{}, nil)
+
+	report, err := ImportZip(context.Background(), mockClient, testBuckets, importTestZip(t), ImportOptions{Upload: testUploads})
+	require.NoError(t, err)
+	assert.True(t, report.Applied)
+	// The lamp keeps its manifest ID; the chair gets the next free one
+	assert.Equal(t, []ImportedItem{
+		{ID: 5000, Classname: "lamp", Section: "wallitemtypes", Object: "bundled/furniture/lamp.nitro", Source: "manifest"},
+		{ID: 5001, Classname: "chair", Section: "roomitemtypes", Object: "bundled/furniture/chair.nitro", Source: "template"},
+	}, report.Created)
+
+	reasons := make(map[string]string)
+	for _, skipped := range report.Skipped {
+		reasons[skipped.File+"|"+skipped.Classname] = skipped.Reason
+	}
+	require.Len(t, reasons, 4)
+	assert.Equal(t, "classname already exists in gamedata", reasons["table.nitro|table"])
+	assert.Equal(t, "not a .nitro file", reasons["readme.txt|"])
+	assert.Contains(t, reasons["image.nitro|image"], upload.ErrContent.Error())
+	assert.Equal(t, "no .nitro file in the archive", reasons[ImportManifestFile+"|ghost"])
+
+	var doc map[string]map[string][]map[string]any
+	require.NoError(t, json.Unmarshal(written, &doc))
+	room, wall := doc["roomitemtypes"]["furnitype"], doc["wallitemtypes"]["furnitype"]
+	require.Len(t, room, 2)
+	assert.Equal(t, "chair", room[1]["classname"])
+	assert.Equal(t, "chair", room[1]["name"])
+	assert.Equal(t, float64(5001), room[1]["id"])
+	assert.Equal(t, float64(1), room[1]["xdim"])
+	require.Len(t, wall, 1)
+	assert.Equal(t, "Lamp", wall[0]["name"])
+	assert.NotContains(t, wall[0], "section")
+}
+
+// TestImportZip_DryRun tests that a dry run reports the import without writing, and
+// that a given template replaces the default one.
+func TestImportZip_DryRun(t *testing.T) {
+	mockClient := mockImportStorage()
+	archive := buildTestZip(t, map[string][]byte{"chair.nitro": nitroBundle(t, "chair.json")})
+
+	report, err := ImportZip(context.Background(), mockClient, testBuckets, archive, ImportOptions{
+		Template: map[string]any{"name": "New {classname}"},
+		Upload:   testUploads,
+		DryRun:   true,
+	})
+	require.NoError(t, err)
+	assert.False(t, report.Applied)
+	require.Len(t, report.Created, 1)
+	assert.Equal(t, 2, report.Created[0].ID)
+	assert.Empty(t, report.Skipped)
+	mockClient.AssertNotCalled(t, "PutObject", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	assert.Equal(t, map[string]any{"name": "New chair", "classname": "chair"},
+		importEntry(map[string]any{"name": "New {classname}"}, nil, "chair"))
+}
+
+// TestImportZip_Invalid tests that archives that are not ZIP files or hold an invalid
+// manifest are refused.
+func TestImportZip_Invalid(t *testing.T) {
+	_, err := ImportZip(context.Background(), new(mocks.Client), testBuckets, []byte("not a zip"), ImportOptions{Upload: testUploads})
+	assert.ErrorIs(t, err, ErrInvalidArchive)
+
+	archive := buildTestZip(t, map[string][]byte{ImportManifestFile: []byte("{")})
+	_, err = ImportZip(context.Background(), new(mocks.Client), testBuckets, archive, ImportOptions{Upload: testUploads})
+	assert.ErrorIs(t, err, ErrInvalidArchive)
+}
@@ -0,0 +1,311 @@
+package pack
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"asset-manager/core/json"
+	"asset-manager/core/reconcile"
+	"asset-manager/core/storage"
+	"asset-manager/core/upload"
+	furnitureAdp "asset-manager/feature/furniture/reconcile"
+
+	"github.com/minio/minio-go/v7"
+	"gorm.io/gorm"
+)
+
+// ErrConflicts is returned by Install when the pack collides with existing content.
+var ErrConflicts = errors.New("pack conflicts with existing content")
+
+// Conflict is a packed item that already exists in the target hotel.
+type Conflict struct {
+	// Source is where the item exists: "gamedata", the furniture table or "storage".
+	Source string `json:"source"`
+	// Item is the conflicting ID, classname or storage key.
+	Item string `json:"item"`
+	// Detail explains the collision.
+	Detail string `json:"detail"`
+}
+
+// InstallReport summarizes what Install wrote, or would write.
+type InstallReport struct {
+	GamedataEntries int        `json:"gamedata_entries"`
+	Rows            int        `json:"rows"`
+	Assets          int        `json:"assets"`
+	Conflicts       []Conflict `json:"conflicts"`
+	Warnings        []string   `json:"warnings,omitempty"`
+	Applied         bool       `json:"applied"`
+}
+
+// Install imports a pack into the hotel. Conflicts are detected before anything is
+// written; with conflicts Install returns the report and ErrConflicts. With dryRun
+// the report is returned without changing anything, conflicts included.
+//
+// The rows are inserted in one transaction that only commits once the merged gamedata
+// is written; any failure rolls back the transaction, restores the gamedata and removes
+// the uploaded assets. It returns a *reconcile.LockedError when another reconcile holds
+// the run lock.
+func Install(ctx context.Context, client storage.Client, buckets storage.Buckets, db *gorm.DB, emulator string, p *Pack, dryRun bool) (report *InstallReport, err error) {
+	if db == nil {
+		return nil, fmt.Errorf("pack install requires a database connection")
+	}
+	if target := canonicalEmulator(emulator); p.Manifest.Emulator != target {
+		return nil, fmt.Errorf("pack was built for %s but the hotel runs %s", p.Manifest.Emulator, target)
+	}
+
+	if !dryRun {
+		lock, err := reconcile.AcquireRunLock(ctx, client, buckets.Assets, reconcile.DefaultLockTTL)
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			if releaseErr := lock.Release(); releaseErr != nil && err == nil {
+				err = releaseErr
+			}
+		}()
+	}
+	// Write the change log once for the whole pack
+	ctx, changes := storage.WithChangeBuffer(ctx)
+	defer changes.Flush()
+
+	report = &InstallReport{
+		Rows:      len(p.Rows),
+		Assets:    len(p.Manifest.Assets),
+		Conflicts: []Conflict{},
+		Warnings:  append([]string(nil), p.Manifest.Warnings...),
+	}
+	for _, items := range p.Gamedata {
+		report.GamedataEntries += len(items)
+	}
+
+	doc, original, err := readGamedata(ctx, client, buckets.Gamedata)
+	if err != nil {
+		return nil, err
+	}
+
+	profile := furnitureAdp.GetProfileByName(emulator)
+	report.Conflicts = append(report.Conflicts, gamedataConflicts(doc, p)...)
+	dbConflicts, err := tableConflicts(ctx, db, profile, p)
+	if err != nil {
+		return nil, err
+	}
+	report.Conflicts = append(report.Conflicts, dbConflicts...)
+	storageConflicts, err := assetConflicts(ctx, client, buckets.Assets, p)
+	if err != nil {
+		return nil, err
+	}
+	report.Conflicts = append(report.Conflicts, storageConflicts...)
+
+	if dryRun {
+		return report, nil
+	}
+	if len(report.Conflicts) > 0 {
+		return report, ErrConflicts
+	}
+
+	if err := applyInstall(ctx, client, buckets, db, profile.TableName, p, doc, original, report); err != nil {
+		return report, err
+	}
+
+	// Indices built before the install miss the new classnames
+	for _, spec := range reconcile.RegisteredSpecs() {
+		if _, ok := spec.Adapter.(*furnitureAdp.FurnitureAdapter); ok {
+			reconcile.InvalidateCache(spec)
+		}
+	}
+	return report, nil
+}
+
+// applyInstall writes a conflict-free pack, undoing earlier steps when a later one fails.
+func applyInstall(ctx context.Context, client storage.Client, buckets storage.Buckets, db *gorm.DB, table string, p *Pack, doc map[string]any, original []byte, report *InstallReport) error {
+	// 1. Scan every asset before the first one is written; rejected files are quarantined
+	for _, classname := range p.Manifest.Assets {
+		if err := upload.ScanObject(ctx, client, buckets.Assets, furnitureAdp.AssetKey(classname), p.Assets[classname]); err != nil {
+			return err
+		}
+	}
+
+	// 2. Upload the assets
+	var uploaded []string
+	undoUploads := func() {
+		for _, failure := range storage.RemoveObjectsWithRetry(ctx, client, buckets.Assets, uploaded, storage.DefaultRemoveRetry) {
+			report.Warnings = append(report.Warnings, fmt.Sprintf("failed to remove uploaded object %s: %v", failure.Object, failure.Err))
+		}
+	}
+	for _, classname := range p.Manifest.Assets {
+		data, key := p.Assets[classname], furnitureAdp.AssetKey(classname)
+		if _, err := client.PutObject(ctx, buckets.Assets, key, bytes.NewReader(data), int64(len(data)), minio.PutObjectOptions{}); err != nil {
+			undoUploads()
+			return fmt.Errorf("failed to write %s: %w", key, err)
+		}
+		uploaded = append(uploaded, key)
+	}
+
+	// 3. Insert the rows inside a transaction that stays open until gamedata is written
+	tx := db.WithContext(ctx).Begin()
+	if tx.Error != nil {
+		undoUploads()
+		return fmt.Errorf("failed to begin transaction: %w", tx.Error)
+	}
+	for i, row := range p.Rows {
+		if err := tx.Table(table).Create(row).Error; err != nil {
+			tx.Rollback()
+			undoUploads()
+			return fmt.Errorf("failed to insert %s row %d: %w", table, i+1, err)
+		}
+	}
+
+	// 4. Write the merged gamedata
+	mergeGamedata(doc, p)
+	merged, err := json.MarshalIndent(doc, "", "  ")
+	if err == nil {
+		err = putGamedata(ctx, client, buckets.Gamedata, merged)
+	}
+	if err != nil {
+		tx.Rollback()
+		undoUploads()
+		return fmt.Errorf("failed to write gamedata: %w", err)
+	}
+
+	// 5. Commit, restoring the original gamedata if that fails
+	if err := tx.Commit().Error; err != nil {
+		if restoreErr := putGamedata(ctx, client, buckets.Gamedata, original); restoreErr != nil {
+			report.Warnings = append(report.Warnings, fmt.Sprintf("failed to restore gamedata: %v", restoreErr))
+		}
+		undoUploads()
+		return fmt.Errorf("failed to commit install: %w", err)
+	}
+	report.Applied = true
+	return nil
+}
+
+// gamedataConflicts returns the packed gamedata entries whose ID or classname exists in doc.
+func gamedataConflicts(doc map[string]any, p *Pack) []Conflict {
+	ids := make(map[int]bool)
+	names := make(map[string]bool)
+	furnitureAdp.EachGamedataItem(doc, func(_ string, item map[string]any) {
+		if id, ok := intField(item, "id"); ok {
+			ids[id] = true
+		}
+		if name, ok := item["classname"].(string); ok {
+			names[name] = true
+		}
+	})
+
+	var conflicts []Conflict
+	for _, item := range p.Manifest.Items {
+		if ids[item.ID] {
+			conflicts = append(conflicts, Conflict{Source: "gamedata", Item: fmt.Sprint(item.ID), Detail: "id already used"})
+		}
+		if names[item.Classname] {
+			conflicts = append(conflicts, Conflict{Source: "gamedata", Item: item.Classname, Detail: "classname already exists"})
+		}
+	}
+	return conflicts
+}
+
+// tableConflicts returns the packed rows whose sprite ID or item name exists in the
+// furniture table.
+func tableConflicts(ctx context.Context, db *gorm.DB, profile furnitureAdp.ServerProfile, p *Pack) ([]Conflict, error) {
+	spriteCol, nameCol := profile.Columns[furnitureAdp.ColSpriteID], profile.Columns[furnitureAdp.ColItemName]
+
+	var sprites []int
+	var names []string
+	for _, row := range p.Rows {
+		if id, ok := intField(row, spriteCol); ok {
+			sprites = append(sprites, id)
+		}
+		if name, ok := row[nameCol].(string); ok {
+			names = append(names, name)
+		}
+	}
+	if len(sprites) == 0 && len(names) == 0 {
+		return nil, nil
+	}
+
+	var existing []struct {
+		Sprite int
+		Name   string
+	}
+	query := db.WithContext(ctx).
+		Table(profile.TableName).
+		Select(spriteCol + " AS sprite, " + nameCol + " AS name")
+	if len(sprites) > 0 {
+		query = query.Or(spriteCol+" IN ?", sprites)
+	}
+	if len(names) > 0 {
+		query = query.Or(nameCol+" IN ?", names)
+	}
+	err := query.Scan(&existing).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", profile.TableName, err)
+	}
+
+	takenSprites := make(map[int]bool, len(existing))
+	takenNames := make(map[string]bool, len(existing))
+	for _, row := range existing {
+		takenSprites[row.Sprite] = true
+		takenNames[row.Name] = true
+	}
+
+	var conflicts []Conflict
+	for _, id := range sprites {
+		if id != 0 && takenSprites[id] {
+			conflicts = append(conflicts, Conflict{Source: profile.TableName, Item: fmt.Sprint(id), Detail: "sprite_id already used"})
+		}
+	}
+	for _, name := range names {
+		if name != "" && takenNames[name] {
+			conflicts = append(conflicts, Conflict{Source: profile.TableName, Item: name, Detail: "item_name already exists"})
+		}
+	}
+	return conflicts, nil
+}
+
+// assetConflicts returns the packed assets that already exist in storage.
+func assetConflicts(ctx context.Context, client storage.Client, bucket string, p *Pack) ([]Conflict, error) {
+	var conflicts []Conflict
+	for _, classname := range p.Manifest.Assets {
+		key := furnitureAdp.AssetKey(classname)
+		for obj := range client.ListObjects(ctx, bucket, minio.ListObjectsOptions{Prefix: key}) {
+			if obj.Err != nil {
+				return nil, fmt.Errorf("failed to list objects: %w", obj.Err)
+			}
+			if obj.Key == key {
+				conflicts = append(conflicts, Conflict{Source: "storage", Item: key, Detail: "object already exists"})
+			}
+		}
+	}
+	return conflicts, nil
+}
+
+// mergeGamedata appends the packed entries to their sections of doc.
+func mergeGamedata(doc map[string]any, p *Pack) {
+	for _, path := range furnitureAdp.GamedataPaths {
+		section, field, _ := strings.Cut(path, ".")
+		entries := p.Gamedata[section]
+		if len(entries) == 0 {
+			continue
+		}
+
+		parent, ok := doc[section].(map[string]any)
+		if !ok {
+			parent = map[string]any{}
+			doc[section] = parent
+		}
+		items, _ := parent[field].([]any)
+		for _, entry := range entries {
+			items = append(items, entry)
+		}
+		parent[field] = items
+	}
+}
+
+// putGamedata writes the furniture gamedata object.
+func putGamedata(ctx context.Context, client storage.Client, bucket string, data []byte) error {
+	_, err := client.PutObject(ctx, bucket, furnitureAdp.GamedataObject, bytes.NewReader(data), int64(len(data)), minio.PutObjectOptions{ContentType: "application/json"})
+	return err
+}
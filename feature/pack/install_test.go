@@ -0,0 +1,246 @@
+package pack
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"asset-manager/core/json"
+	"asset-manager/core/reconcile"
+	"asset-manager/core/storage"
+	"asset-manager/core/storage/mocks"
+	"asset-manager/core/upload"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+const sourceGamedataJSON = `{
+	"roomitemtypes": {"furnitype": [
+		{"id": 1000, "classname": "chair", "name": "Chair", "revision": 5},
+		{"id": 1001, "classname": "chair*2", "name": "Chair Red"},
+		{"id": 2000, "classname": "sofa", "name": "Sofa"}
+	]},
+	"wallitemtypes": {"furnitype": [
+		{"id": 1002, "classname": "poster", "name": "Poster"}
+	]}
+}`
+
+const targetGamedataJSON = `{
+	"roomitemtypes": {"furnitype": [{"id": 1, "classname": "table"}]},
+	"wallitemtypes": {"furnitype": []}
+}`
+
+var testBuckets = storage.Buckets{Assets: "test-bucket", Gamedata: "test-bucket"}
+
+// setupPackDB creates an Arcturus items_base table in SQLite holding rows.
+func setupPackDB(t *testing.T, name, rows string) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", name)), &gorm.Config{})
+	require.NoError(t, err)
+
+	require.NoError(t, db.Exec(`CREATE TABLE items_base (id INTEGER PRIMARY KEY, sprite_id INTEGER, item_name VARCHAR(70), public_name VARCHAR(56))`).Error)
+	if rows != "" {
+		require.NoError(t, db.Exec(`INSERT INTO items_base (id, sprite_id, item_name, public_name) VALUES `+rows).Error)
+	}
+	return db
+}
+
+// mockLock lets the run lock be taken and released.
+func mockLock(mockClient *mocks.Client) {
+	mockClient.On("GetObject", mock.Anything, "test-bucket", reconcile.LockObject, mock.Anything).
+		Return(io.ReadCloser(nil), minio.ErrorResponse{Code: "NoSuchKey"})
+	mockClient.On("PutObject", mock.Anything, "test-bucket", reconcile.LockObject, mock.Anything, mock.Anything, mock.Anything).
+		Return(minio.UploadInfo{}, nil)
+	mockClient.On("RemoveObject", mock.Anything, "test-bucket", reconcile.LockObject, mock.Anything).Return(nil)
+}
+
+// buildTestPack builds a pack of the chair and poster items from a source hotel.
+func buildTestPack(t *testing.T) *Pack {
+	db := setupPackDB(t, "pack_source_"+t.Name(), `(1, 1000, 'chair', 'Chair'), (2, 1001, 'chair*2', 'Chair Red'), (3, 1002, 'poster', 'Poster'), (4, 2000, 'sofa', 'Sofa')`)
+
+	mockClient := new(mocks.Client)
+	mockClient.On("GetObject", mock.Anything, "test-bucket", "gamedata/FurnitureData.json", mock.Anything).
+		Return(io.NopCloser(strings.NewReader(sourceGamedataJSON)), nil)
+	mockClient.On("GetObject", mock.Anything, "test-bucket", "bundled/furniture/chair.nitro", mock.Anything).
+		Return(io.NopCloser(strings.NewReader("chair-nitro")), nil)
+	mockClient.On("GetObject", mock.Anything, "test-bucket", "bundled/furniture/poster.nitro", mock.Anything).
+		Return(io.ReadCloser(nil), minio.ErrorResponse{Code: "NoSuchKey"})
+
+	var buf bytes.Buffer
+	manifest, err := Build(context.Background(), mockClient, testBuckets, db, "arcturus", []int{1000, 1001, 1002, 1003}, &buf)
+	require.NoError(t, err)
+	assert.Len(t, manifest.Items, 3)
+	assert.Equal(t, []string{"chair"}, manifest.Assets)
+	assert.Contains(t, manifest.Warnings, "id 1003 not found in gamedata or items_base")
+	assert.Contains(t, manifest.Warnings, "asset not found: bundled/furniture/poster.nitro")
+
+	p, err := Read(&buf)
+	require.NoError(t, err)
+	return p
+}
+
+func TestBuild(t *testing.T) {
+	p := buildTestPack(t)
+
+	assert.Equal(t, "arcturus", p.Manifest.Emulator)
+	assert.Len(t, p.Gamedata["roomitemtypes"], 2)
+	assert.Len(t, p.Gamedata["wallitemtypes"], 1)
+	assert.Equal(t, float64(5), p.Gamedata["roomitemtypes"][0]["revision"])
+	require.Len(t, p.Rows, 3)
+	assert.NotContains(t, p.Rows[0], "id")
+	assert.Equal(t, "chair", p.Rows[0]["item_name"])
+	assert.Equal(t, []byte("chair-nitro"), p.Assets["chair"])
+}
+
+func TestBuild_NoMatches(t *testing.T) {
+	db := setupPackDB(t, "pack_nomatch", "")
+
+	mockClient := new(mocks.Client)
+	mockClient.On("GetObject", mock.Anything, "test-bucket", "gamedata/FurnitureData.json", mock.Anything).
+		Return(io.NopCloser(strings.NewReader(sourceGamedataJSON)), nil)
+
+	_, err := Build(context.Background(), mockClient, testBuckets, db, "arcturus", []int{5}, io.Discard)
+	assert.ErrorContains(t, err, "none of the 1 ids exist")
+}
+
+func TestInstall(t *testing.T) {
+	p := buildTestPack(t)
+	db := setupPackDB(t, "pack_install", `(1, 1, 'table', 'Table')`)
+
+	mockClient := new(mocks.Client)
+	mockLock(mockClient)
+	mockClient.On("GetObject", mock.Anything, "test-bucket", "gamedata/FurnitureData.json", mock.Anything).
+		Return(io.NopCloser(strings.NewReader(targetGamedataJSON)), nil)
+	mockClient.On("ListObjects", mock.Anything, "test-bucket", mock.Anything).Return(nil)
+	mockClient.On("PutObject", mock.Anything, "test-bucket", "bundled/furniture/chair.nitro", mock.Anything, int64(len("chair-nitro")), mock.Anything).
+		Return(minio.UploadInfo{}, nil)
+	var written []byte
+	mockClient.On("PutObject", mock.Anything, "test-bucket", "gamedata/FurnitureData.json", mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			written, _ = io.ReadAll(args.Get(3).(io.Reader))
+		}).
+		Return(minio.UploadInfo{}, nil)
+
+	report, err := Install(context.Background(), mockClient, testBuckets, db, "arcturus", p, false)
+	require.NoError(t, err)
+	assert.True(t, report.Applied)
+	assert.Empty(t, report.Conflicts)
+	assert.Equal(t, 3, report.GamedataEntries)
+	assert.Equal(t, 3, report.Rows)
+
+	var names []string
+	require.NoError(t, db.Table("items_base").Order("sprite_id").Pluck("item_name", &names).Error)
+	assert.Equal(t, []string{"table", "chair", "chair*2", "poster"}, names)
+
+	var doc struct {
+		RoomItemTypes struct {
+			FurniType []struct {
+				ID        int    `json:"id"`
+				Classname string `json:"classname"`
+			} `json:"furnitype"`
+		} `json:"roomitemtypes"`
+		WallItemTypes struct {
+			FurniType []struct {
+				Classname string `json:"classname"`
+			} `json:"furnitype"`
+		} `json:"wallitemtypes"`
+	}
+	require.NoError(t, json.Unmarshal(written, &doc))
+	assert.Len(t, doc.RoomItemTypes.FurniType, 3)
+	assert.Equal(t, 1001, doc.RoomItemTypes.FurniType[2].ID)
+	require.Len(t, doc.WallItemTypes.FurniType, 1)
+	assert.Equal(t, "poster", doc.WallItemTypes.FurniType[0].Classname)
+}
+
+func TestInstall_Conflicts(t *testing.T) {
+	p := buildTestPack(t)
+	db := setupPackDB(t, "pack_conflicts", `(1, 1000, 'other', 'Other'), (2, 5, 'poster', 'Poster')`)
+
+	mockClient := new(mocks.Client)
+	mockLock(mockClient)
+	mockClient.On("GetObject", mock.Anything, "test-bucket", "gamedata/FurnitureData.json", mock.Anything).
+		Return(io.NopCloser(strings.NewReader(`{"roomitemtypes": {"furnitype": [{"id": 1001, "classname": "x"}]}}`)), nil)
+	existing := make(chan minio.ObjectInfo, 1)
+	existing <- minio.ObjectInfo{Key: "bundled/furniture/chair.nitro"}
+	close(existing)
+	mockClient.On("ListObjects", mock.Anything, "test-bucket", mock.Anything).Return((<-chan minio.ObjectInfo)(existing))
+
+	report, err := Install(context.Background(), mockClient, testBuckets, db, "arcturus", p, false)
+	assert.ErrorIs(t, err, ErrConflicts)
+	require.NotNil(t, report)
+	assert.False(t, report.Applied)
+	assert.ElementsMatch(t, []Conflict{
+		{Source: "gamedata", Item: "1001", Detail: "id already used"},
+		{Source: "items_base", Item: "1000", Detail: "sprite_id already used"},
+		{Source: "items_base", Item: "poster", Detail: "item_name already exists"},
+		{Source: "storage", Item: "bundled/furniture/chair.nitro", Detail: "object already exists"},
+	}, report.Conflicts)
+
+	mockClient.AssertNotCalled(t, "PutObject", mock.Anything, "test-bucket", "bundled/furniture/chair.nitro", mock.Anything, mock.Anything, mock.Anything)
+	var count int64
+	require.NoError(t, db.Table("items_base").Count(&count).Error)
+	assert.Equal(t, int64(2), count)
+}
+
+func TestInstall_DryRun(t *testing.T) {
+	p := buildTestPack(t)
+	db := setupPackDB(t, "pack_dryrun", `(1, 1000, 'other', 'Other')`)
+
+	mockClient := new(mocks.Client)
+	mockClient.On("GetObject", mock.Anything, "test-bucket", "gamedata/FurnitureData.json", mock.Anything).
+		Return(io.NopCloser(strings.NewReader(targetGamedataJSON)), nil)
+	mockClient.On("ListObjects", mock.Anything, "test-bucket", mock.Anything).Return(nil)
+
+	report, err := Install(context.Background(), mockClient, testBuckets, db, "arcturus", p, true)
+	require.NoError(t, err)
+	assert.False(t, report.Applied)
+	assert.Len(t, report.Conflicts, 1)
+	mockClient.AssertNotCalled(t, "PutObject", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestInstall_EmulatorMismatch(t *testing.T) {
+	p := &Pack{Manifest: Manifest{Version: FormatVersion, Emulator: "comet"}}
+	db := setupPackDB(t, "pack_mismatch", "")
+
+	_, err := Install(context.Background(), new(mocks.Client), testBuckets, db, "arcturus", p, true)
+	assert.ErrorContains(t, err, "built for comet but the hotel runs arcturus")
+}
+
+// rejectAll is a scanner that finds a threat in every file.
+type rejectAll struct{}
+
+func (rejectAll) Scan(ctx context.Context, name string, r io.Reader) (upload.Verdict, error) {
+	return upload.Verdict{Signature: "Test-Signature"}, nil
+}
+
+func TestInstall_ScanRejected(t *testing.T) {
+	p := buildTestPack(t)
+	db := setupPackDB(t, "pack_scan", "")
+
+	upload.SetScanner(rejectAll{}, "quarantine/")
+	defer upload.SetScanner(nil, "")
+
+	mockClient := new(mocks.Client)
+	mockLock(mockClient)
+	mockClient.On("GetObject", mock.Anything, "test-bucket", "gamedata/FurnitureData.json", mock.Anything).
+		Return(io.NopCloser(strings.NewReader(targetGamedataJSON)), nil)
+	mockClient.On("ListObjects", mock.Anything, "test-bucket", mock.Anything).Return(nil)
+	mockClient.On("PutObject", mock.Anything, "test-bucket", "quarantine/bundled/furniture/chair.nitro", mock.Anything, mock.Anything, mock.Anything).
+		Return(minio.UploadInfo{}, nil)
+
+	report, err := Install(context.Background(), mockClient, testBuckets, db, "arcturus", p, false)
+	assert.ErrorIs(t, err, upload.ErrInfected)
+	assert.False(t, report.Applied)
+	mockClient.AssertNotCalled(t, "PutObject", mock.Anything, "test-bucket", "bundled/furniture/chair.nitro", mock.Anything, mock.Anything, mock.Anything)
+
+	var count int64
+	require.NoError(t, db.Table("items_base").Count(&count).Error)
+	assert.Zero(t, count)
+}
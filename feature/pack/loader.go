@@ -0,0 +1,38 @@
+package pack
+
+import (
+	"asset-manager/core/storage"
+	"asset-manager/core/upload"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// Feature implements the loader.Feature interface.
+type Feature struct {
+	service *Service
+	handler *Handler
+}
+
+// NewFeature creates a new Pack feature serving furniture imports.
+func NewFeature(client storage.Client, buckets storage.Buckets, logger *zap.Logger, uploads upload.Config) *Feature {
+	svc := NewService(client, buckets, logger, uploads)
+	h := NewHandler(svc)
+	return &Feature{service: svc, handler: h}
+}
+
+// Name returns the name of the feature.
+func (f *Feature) Name() string {
+	return "pack"
+}
+
+// IsEnabled checks if the feature is enabled.
+func (f *Feature) IsEnabled() bool {
+	return true
+}
+
+// Load registers the feature's routes.
+func (f *Feature) Load(app fiber.Router) error {
+	f.handler.RegisterRoutes(app)
+	return nil
+}
@@ -0,0 +1,276 @@
+package pack
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"asset-manager/core/json"
+)
+
+const (
+	// FormatVersion is the pack format written by Build and accepted by Read.
+	FormatVersion = 1
+
+	// MaxIDs caps how many IDs one --ids expression may select.
+	MaxIDs = 10000
+
+	manifestFile = "manifest.json"
+	gamedataFile = "gamedata.json"
+	rowsFile     = "rows.json"
+	sqlFile      = "items.sql"
+	nitroDir     = "nitro/"
+)
+
+// Item is a furniture item recorded in a pack manifest.
+type Item struct {
+	// ID is the gamedata ID, which is the sprite ID in the database.
+	ID int `json:"id"`
+	// Classname is the gamedata classname, color variant included.
+	Classname string `json:"classname"`
+	// Section is the gamedata section holding the item ("roomitemtypes" or "wallitemtypes").
+	Section string `json:"section"`
+}
+
+// Manifest describes the contents of a pack.
+type Manifest struct {
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	// Emulator and Table identify the schema of the packed rows.
+	Emulator string `json:"emulator"`
+	Table    string `json:"table"`
+	// Items lists the packed gamedata entries.
+	Items []Item `json:"items"`
+	// Assets lists the packed .nitro classnames.
+	Assets []string `json:"assets"`
+	// Warnings lists what was selected but could not be packed.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// Pack is a content pack held in memory.
+type Pack struct {
+	Manifest Manifest
+	// Gamedata holds the packed entries per gamedata section, as raw JSON objects.
+	Gamedata map[string][]map[string]any
+	// Rows holds the packed furniture table rows.
+	Rows []map[string]any
+	// Assets maps classnames to their .nitro file contents.
+	Assets map[string][]byte
+}
+
+// ParseIDs parses an ID selection such as "1000-1050,1200" into sorted, unique IDs.
+func ParseIDs(expr string) ([]int, error) {
+	seen := make(map[int]struct{})
+	for _, part := range strings.Split(expr, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		from, to, isRange := strings.Cut(part, "-")
+		first, err := strconv.Atoi(strings.TrimSpace(from))
+		if err != nil || first <= 0 {
+			return nil, fmt.Errorf("invalid id %q", part)
+		}
+		last := first
+		if isRange {
+			if last, err = strconv.Atoi(strings.TrimSpace(to)); err != nil || last < first {
+				return nil, fmt.Errorf("invalid id range %q", part)
+			}
+		}
+		if last-first+1 > MaxIDs || len(seen)+last-first+1 > MaxIDs {
+			return nil, fmt.Errorf("id selection exceeds %d ids", MaxIDs)
+		}
+
+		for id := first; id <= last; id++ {
+			seen[id] = struct{}{}
+		}
+	}
+	if len(seen) == 0 {
+		return nil, fmt.Errorf("no ids selected")
+	}
+
+	ids := make([]int, 0, len(seen))
+	for id := range seen {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids, nil
+}
+
+// Write encodes p as a gzipped tar archive.
+func (p *Pack) Write(w io.Writer) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	files := []struct {
+		name string
+		data any
+	}{
+		{manifestFile, p.Manifest},
+		{gamedataFile, p.Gamedata},
+		{rowsFile, p.Rows},
+	}
+	for _, file := range files {
+		data, err := json.MarshalIndent(file.data, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode %s: %w", file.name, err)
+		}
+		if err := writeEntry(tw, file.name, data, p.Manifest.CreatedAt); err != nil {
+			return err
+		}
+	}
+
+	if err := writeEntry(tw, sqlFile, []byte(insertSQL(p.Manifest.Table, p.Rows)), p.Manifest.CreatedAt); err != nil {
+		return err
+	}
+
+	for _, classname := range p.Manifest.Assets {
+		if err := writeEntry(tw, nitroDir+classname+".nitro", p.Assets[classname], p.Manifest.CreatedAt); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to finish archive: %w", err)
+	}
+	return gz.Close()
+}
+
+// writeEntry adds one regular file to the archive.
+func writeEntry(tw *tar.Writer, name string, data []byte, modTime time.Time) error {
+	header := &tar.Header{
+		Name:    name,
+		Mode:    0o644,
+		Size:    int64(len(data)),
+		ModTime: modTime,
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+// Read decodes a pack written by Write.
+func Read(r io.Reader) (*Pack, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open pack: %w", err)
+	}
+	defer gz.Close()
+
+	p := &Pack{Assets: make(map[string][]byte)}
+	seen := make(map[string]bool)
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read pack: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", header.Name, err)
+		}
+		seen[header.Name] = true
+
+		switch name := header.Name; {
+		case name == manifestFile:
+			err = json.Unmarshal(data, &p.Manifest)
+		case name == gamedataFile:
+			err = json.Unmarshal(data, &p.Gamedata)
+		case name == rowsFile:
+			err = json.Unmarshal(data, &p.Rows)
+		case strings.HasPrefix(name, nitroDir) && path.Ext(name) == ".nitro":
+			classname := strings.TrimSuffix(strings.TrimPrefix(name, nitroDir), ".nitro")
+			if strings.Contains(classname, "/") {
+				return nil, fmt.Errorf("invalid asset path %s", name)
+			}
+			p.Assets[classname] = data
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", header.Name, err)
+		}
+	}
+
+	for _, name := range []string{manifestFile, gamedataFile, rowsFile} {
+		if !seen[name] {
+			return nil, fmt.Errorf("pack is missing %s", name)
+		}
+	}
+	if p.Manifest.Version != FormatVersion {
+		return nil, fmt.Errorf("unsupported pack version %d (expected %d)", p.Manifest.Version, FormatVersion)
+	}
+	for _, classname := range p.Manifest.Assets {
+		if _, ok := p.Assets[classname]; !ok {
+			return nil, fmt.Errorf("pack is missing asset %s", classname)
+		}
+	}
+	return p, nil
+}
+
+// insertSQL renders rows as INSERT statements. Columns are sorted so packs of the
+// same items are byte-identical.
+func insertSQL(table string, rows []map[string]any) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "-- %d rows for %s\n", len(rows), table)
+	for _, row := range rows {
+		columns := make([]string, 0, len(row))
+		for column := range row {
+			columns = append(columns, column)
+		}
+		sort.Strings(columns)
+
+		values := make([]string, 0, len(columns))
+		for _, column := range columns {
+			values = append(values, sqlValue(row[column]))
+		}
+		fmt.Fprintf(&b, "INSERT INTO `%s` (`%s`) VALUES (%s);\n", table, strings.Join(columns, "`, `"), strings.Join(values, ", "))
+	}
+	return b.String()
+}
+
+// sqlValue renders a value as a MySQL literal.
+func sqlValue(v any) string {
+	switch value := v.(type) {
+	case nil:
+		return "NULL"
+	case bool:
+		if value {
+			return "1"
+		}
+		return "0"
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case float32, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprint(value)
+	case []byte:
+		return quoteSQL(string(value))
+	case time.Time:
+		return quoteSQL(value.Format("2006-01-02 15:04:05"))
+	default:
+		return quoteSQL(fmt.Sprint(value))
+	}
+}
+
+// quoteSQL quotes s as a MySQL string literal.
+func quoteSQL(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, "'", "''")
+	return "'" + s + "'"
+}
@@ -0,0 +1,64 @@
+package pack
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseIDs(t *testing.T) {
+	ids, err := ParseIDs("1003-1005, 1000,1004")
+	require.NoError(t, err)
+	assert.Equal(t, []int{1000, 1003, 1004, 1005}, ids)
+
+	for _, expr := range []string{"", "abc", "10-5", "0", "1-20000", "5-"} {
+		_, err := ParseIDs(expr)
+		assert.Error(t, err, expr)
+	}
+}
+
+func TestPackRoundTrip(t *testing.T) {
+	p := &Pack{
+		Manifest: Manifest{
+			Version:   FormatVersion,
+			CreatedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+			Emulator:  "arcturus",
+			Table:     "items_base",
+			Items:     []Item{{ID: 1000, Classname: "chair", Section: "roomitemtypes"}},
+			Assets:    []string{"chair"},
+		},
+		Gamedata: map[string][]map[string]any{"roomitemtypes": {{"id": float64(1000), "classname": "chair"}}},
+		Rows:     []map[string]any{{"sprite_id": float64(1000), "item_name": "chair"}},
+		Assets:   map[string][]byte{"chair": []byte("nitro")},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, p.Write(&buf))
+
+	read, err := Read(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, p.Manifest.Items, read.Manifest.Items)
+	assert.Equal(t, p.Gamedata, read.Gamedata)
+	assert.Equal(t, p.Rows, read.Rows)
+	assert.Equal(t, []byte("nitro"), read.Assets["chair"])
+}
+
+func TestRead_UnsupportedVersion(t *testing.T) {
+	p := &Pack{Manifest: Manifest{Version: FormatVersion + 1}}
+
+	var buf bytes.Buffer
+	require.NoError(t, p.Write(&buf))
+
+	_, err := Read(&buf)
+	assert.ErrorContains(t, err, "unsupported pack version")
+}
+
+func TestInsertSQL(t *testing.T) {
+	sql := insertSQL("items_base", []map[string]any{
+		{"sprite_id": float64(1000), "item_name": `it's\`, "public_name": nil},
+	})
+	assert.Contains(t, sql, "INSERT INTO `items_base` (`item_name`, `public_name`, `sprite_id`) VALUES ('it''s\\\\', NULL, 1000);")
+}
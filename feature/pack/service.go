@@ -0,0 +1,43 @@
+package pack
+
+import (
+	"context"
+
+	"asset-manager/core/storage"
+	"asset-manager/core/upload"
+
+	"go.uber.org/zap"
+)
+
+// Service runs furniture imports and .swf conversions for the HTTP routes.
+type Service struct {
+	client  storage.Client
+	buckets storage.Buckets
+	logger  *zap.Logger
+	uploads upload.Config
+}
+
+// NewService creates a new import service checking archived files against uploads.
+func NewService(client storage.Client, buckets storage.Buckets, logger *zap.Logger, uploads upload.Config) *Service {
+	return &Service{
+		client:  client,
+		buckets: buckets,
+		logger:  logger,
+		uploads: uploads,
+	}
+}
+
+// Import imports a ZIP archive of .nitro files (see ImportZip).
+func (s *Service) Import(ctx context.Context, archive []byte, manifest *ImportManifest, template map[string]any, dryRun bool) (*ImportReport, error) {
+	return ImportZip(ctx, s.client, s.buckets, archive, ImportOptions{
+		Manifest: manifest,
+		Template: template,
+		Upload:   s.uploads,
+		DryRun:   dryRun,
+	})
+}
+
+// Convert converts the legacy .swf files under the configured source prefix (see ConvertSWF).
+func (s *Service) Convert(ctx context.Context, dryRun bool) (*ConvertReport, error) {
+	return ConvertSWF(ctx, s.client, s.buckets, ConvertOptions{Upload: s.uploads, DryRun: dryRun})
+}